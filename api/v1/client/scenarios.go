@@ -0,0 +1,33 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	v1 "go.k6.io/k6/api/v1"
+)
+
+// Scenarios returns the current state of all scenarios.
+func (c *Client) Scenarios(ctx context.Context) (ret []v1.Scenario, err error) {
+	var resp v1.ScenariosJSONAPI
+
+	if err = c.CallAPI(ctx, http.MethodGet, &url.URL{Path: "/v1/scenarios"}, nil, &resp); err != nil {
+		return ret, err
+	}
+
+	return resp.Scenarios(), nil
+}
+
+// UpdateScenario tries to change the scenario with the given name while it's
+// running and returns its new state if it was successful.
+func (c *Client) UpdateScenario(ctx context.Context, name string, patch v1.ScenarioPatch) (ret v1.Scenario, err error) {
+	var resp v1.ScenarioJSONAPI
+
+	apiURL := &url.URL{Path: "/v1/scenarios/" + name}
+	if err = c.CallAPI(ctx, http.MethodPatch, apiURL, v1.NewScenarioPatchJSONAPI(name, patch), &resp); err != nil {
+		return ret, err
+	}
+
+	return resp.Scenario(), nil
+}
@@ -72,11 +72,18 @@ type Metric struct {
 // NewMetric constructs a new v1.Metric struct that is used for
 // a metric representation in a k6 REST API
 func NewMetric(m *metrics.Metric, t time.Duration) Metric {
+	sample := m.Sink.Format(t)
+	// The values of the time-windowed thresholds, e.g. "p(95) over 1m",
+	// are only known by the thresholds, so we include them separately.
+	for key, value := range m.Thresholds.WindowValues() {
+		sample[key] = value
+	}
+
 	return Metric{
 		Name:     m.Name,
 		Type:     NullMetricType{m.Type, true},
 		Contains: NullValueType{m.Contains, true},
 		Tainted:  m.Tainted,
-		Sample:   m.Sink.Format(t),
+		Sample:   sample,
 	}
 }
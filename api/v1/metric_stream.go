@@ -0,0 +1,54 @@
+package v1
+
+import (
+	"go.k6.io/k6/lib/types"
+)
+
+// The names of the events sent by the metrics stream.
+const (
+	MetricsStreamSnapshotEvent       = "metrics"
+	MetricsStreamThresholdEvent      = "threshold"
+	MetricsStreamTestStartEvent      = "test-start"
+	MetricsStreamTestEndEvent        = "test-end"
+	MetricsStreamIterationErrorEvent = "iteration-error"
+)
+
+// MetricsSnapshot is the data of the periodic "metrics" events of the metrics
+// stream. It's the same JSON API envelop that GET /v1/metrics returns, with
+// the test run duration at the time of the snapshot.
+type MetricsSnapshot struct {
+	MetricsJSONAPI
+	Meta MetricsSnapshotMeta `json:"meta"`
+}
+
+// MetricsSnapshotMeta is the metadata of a metrics snapshot.
+type MetricsSnapshotMeta struct {
+	Time types.Duration `json:"time"`
+}
+
+// ThresholdStatus is the data of the "threshold" events of the metrics
+// stream, which are sent when a threshold is evaluated for the first time and
+// every time after that when it starts or stops failing.
+type ThresholdStatus struct {
+	Time        types.Duration `json:"time"`
+	Metric      string         `json:"metric"`
+	Threshold   string         `json:"threshold"`
+	Ok          bool           `json:"ok"`
+	AbortOnFail bool           `json:"abortOnFail"`
+}
+
+// TestLifecycleEvent is the data of the "test-start" and "test-end" events
+// of the metrics stream.
+type TestLifecycleEvent struct {
+	Time types.Duration `json:"time"`
+}
+
+// IterationError is the data of the "iteration-error" events of the metrics
+// stream.
+type IterationError struct {
+	Time      types.Duration `json:"time"`
+	Scenario  string         `json:"scenario"`
+	VUID      uint64         `json:"vuId"`
+	Iteration int64          `json:"iteration"`
+	Error     string         `json:"error"`
+}
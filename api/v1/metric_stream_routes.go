@@ -0,0 +1,231 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"go.k6.io/k6/internal/event"
+	"go.k6.io/k6/lib/types"
+)
+
+const (
+	defaultMetricsStreamInterval = time.Second
+	minMetricsStreamInterval     = 100 * time.Millisecond
+	metricsStreamEventsBuffer    = 100
+)
+
+type metricsStreamMessage struct {
+	event string
+	data  any
+}
+
+type thresholdKey struct {
+	metric, source string
+}
+
+// metricsStream sends the Server-Sent Events of a single client of the
+// metrics stream.
+type metricsStream struct {
+	cs *ControlSurface
+	rw http.ResponseWriter
+	rc *http.ResponseController
+
+	// The last known status of the evaluated thresholds
+	thresholds map[thresholdKey]bool
+}
+
+func handleGetMetricsStream(cs *ControlSurface, rw http.ResponseWriter, r *http.Request) {
+	interval := defaultMetricsStreamInterval
+	if value := r.URL.Query().Get("interval"); value != "" {
+		var err error
+		interval, err = types.ParseExtendedDuration(value)
+		if err != nil || interval < minMetricsStreamInterval {
+			rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+			apiError(rw, "Invalid interval", fmt.Sprintf(
+				"the interval must be a duration of at least %s, got '%s'", minMetricsStreamInterval, value,
+			), http.StatusBadRequest)
+			return
+		}
+	}
+
+	rw.Header().Set("Content-Type", "text/event-stream")
+	rw.Header().Set("Cache-Control", "no-cache")
+	rw.Header().Set("Connection", "keep-alive")
+	rw.WriteHeader(http.StatusOK)
+
+	stream := &metricsStream{
+		cs:         cs,
+		rw:         rw,
+		rc:         http.NewResponseController(rw),
+		thresholds: make(map[thresholdKey]bool),
+	}
+	messages, unsubscribe := stream.subscribe()
+	defer unsubscribe()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	err := stream.writeSnapshot()
+	for err == nil {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			err = stream.writeSnapshot()
+		case msg, ok := <-messages:
+			if !ok {
+				messages = nil
+				continue
+			}
+			err = stream.writeEvent(msg)
+		}
+	}
+	cs.RunState.Logger.WithError(err).Debug("Closing the metrics stream")
+}
+
+// subscribe forwards the test lifecycle events to the returned channel. The
+// events are processed right away, so the test isn't held back by the client,
+// and the iteration errors are dropped if the client can't keep up with them.
+func (ms *metricsStream) subscribe() (messages <-chan metricsStreamMessage, unsubscribe func()) {
+	events := ms.cs.RunState.Events
+	if events == nil {
+		return nil, func() {}
+	}
+
+	subID, eventsCh := events.Subscribe(event.TestStart, event.TestEnd, event.IterError)
+	msgs := make(chan metricsStreamMessage, metricsStreamEventsBuffer)
+	done := make(chan struct{})
+	go func() {
+		defer close(msgs)
+		for evt := range eventsCh {
+			msg := ms.newEventMessage(evt)
+			evt.Done()
+			if evt.Type == event.IterError {
+				select {
+				case msgs <- msg:
+				default:
+				}
+				continue
+			}
+			select {
+			case msgs <- msg:
+			case <-done:
+			}
+		}
+	}()
+
+	return msgs, func() {
+		close(done)
+		events.Unsubscribe(subID)
+	}
+}
+
+func (ms *metricsStream) newEventMessage(evt *event.Event) metricsStreamMessage {
+	t := types.Duration(ms.getCurrentTestRunDuration())
+	switch evt.Type {
+	case event.TestStart:
+		return metricsStreamMessage{event: MetricsStreamTestStartEvent, data: TestLifecycleEvent{Time: t}}
+	case event.TestEnd:
+		return metricsStreamMessage{event: MetricsStreamTestEndEvent, data: TestLifecycleEvent{Time: t}}
+	default:
+		iterData, _ := evt.Data.(event.IterData)
+		iterErr := IterationError{
+			Time:      t,
+			Scenario:  iterData.ScenarioName,
+			VUID:      iterData.VUID,
+			Iteration: iterData.Iteration,
+		}
+		if iterData.Error != nil {
+			iterErr.Error = iterData.Error.Error()
+		}
+		return metricsStreamMessage{event: MetricsStreamIterationErrorEvent, data: iterErr}
+	}
+}
+
+func (ms *metricsStream) getCurrentTestRunDuration() time.Duration {
+	if ms.cs.Scheduler == nil {
+		return 0
+	}
+	return ms.cs.Scheduler.GetState().GetCurrentTestRunDuration()
+}
+
+// writeSnapshot sends the current values of the metrics, followed by the
+// thresholds whose status changed since the last snapshot.
+func (ms *metricsStream) writeSnapshot() error {
+	t := ms.getCurrentTestRunDuration()
+
+	ms.cs.MetricsEngine.MetricsLock.Lock()
+	snapshot := MetricsSnapshot{
+		MetricsJSONAPI: newMetricsJSONAPI(ms.cs.MetricsEngine.ObservedMetrics, t),
+		Meta:           MetricsSnapshotMeta{Time: types.Duration(t)},
+	}
+	thresholds := ms.getThresholdChanges(t)
+	ms.cs.MetricsEngine.MetricsLock.Unlock()
+
+	if err := ms.write(metricsStreamMessage{event: MetricsStreamSnapshotEvent, data: snapshot}); err != nil {
+		return err
+	}
+	for _, status := range thresholds {
+		if err := ms.write(metricsStreamMessage{event: MetricsStreamThresholdEvent, data: status}); err != nil {
+			return err
+		}
+	}
+	return ms.rc.Flush()
+}
+
+// getThresholdChanges has to be called with the metrics lock held.
+func (ms *metricsStream) getThresholdChanges(t time.Duration) []ThresholdStatus {
+	names := make([]string, 0, len(ms.cs.MetricsEngine.ObservedMetrics))
+	for name := range ms.cs.MetricsEngine.ObservedMetrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var changes []ThresholdStatus
+	for _, name := range names {
+		m := ms.cs.MetricsEngine.ObservedMetrics[name]
+		if !m.Tainted.Valid {
+			continue // the thresholds of the metric weren't evaluated yet
+		}
+		for _, threshold := range m.Thresholds.Thresholds {
+			key := thresholdKey{metric: name, source: threshold.Source}
+			ok := !threshold.LastFailed
+			if lastOk, seen := ms.thresholds[key]; seen && lastOk == ok {
+				continue
+			}
+			ms.thresholds[key] = ok
+			changes = append(changes, ThresholdStatus{
+				Time:        types.Duration(t),
+				Metric:      name,
+				Threshold:   threshold.Source,
+				Ok:          ok,
+				AbortOnFail: threshold.AbortOnFail,
+			})
+		}
+	}
+	return changes
+}
+
+// writeEvent sends a test lifecycle event. The final values of the metrics are
+// sent right after the end of the test.
+func (ms *metricsStream) writeEvent(msg metricsStreamMessage) error {
+	if err := ms.write(msg); err != nil {
+		return err
+	}
+	if msg.event == MetricsStreamTestEndEvent {
+		return ms.writeSnapshot()
+	}
+	return ms.rc.Flush()
+}
+
+func (ms *metricsStream) write(msg metricsStreamMessage) error {
+	data, err := json.Marshal(msg.data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(ms.rw, "event: %s\ndata: %s\n\n", msg.event, data)
+	return err
+}
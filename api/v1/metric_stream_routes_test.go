@@ -0,0 +1,163 @@
+package v1
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/internal/event"
+	"go.k6.io/k6/internal/lib/testutils/minirunner"
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/metrics"
+)
+
+type testStreamEvent struct {
+	name, data string
+}
+
+// readStreamEvents parses the Server-Sent Events of the response body.
+func readStreamEvents(t *testing.T, res *http.Response) <-chan testStreamEvent {
+	events := make(chan testStreamEvent, 100)
+	go func() {
+		defer close(events)
+		var evt testStreamEvent
+		scanner := bufio.NewScanner(res.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case strings.HasPrefix(line, "event: "):
+				evt.name = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				evt.data = strings.TrimPrefix(line, "data: ")
+			case line == "":
+				events <- evt
+				evt = testStreamEvent{}
+			default:
+				t.Errorf("unexpected line %q", line)
+			}
+		}
+	}()
+	return events
+}
+
+// waitForStreamEvent skips the events until the one with the given name and
+// decodes its data.
+func waitForStreamEvent(t *testing.T, events <-chan testStreamEvent, name string, data any) {
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case evt, ok := <-events:
+			require.True(t, ok, "the stream was closed before a %s event", name)
+			if evt.name != name {
+				continue
+			}
+			require.NoError(t, json.Unmarshal([]byte(evt.data), data))
+			return
+		case <-timeout:
+			require.FailNow(t, "timed out waiting for a "+name+" event")
+		}
+	}
+}
+
+func TestGetMetricsStream(t *testing.T) {
+	t.Parallel()
+
+	testState := getTestRunState(t, lib.Options{}, &minirunner.MiniRunner{})
+	testState.Events = event.NewEventSystem(100, testState.Logger)
+	testMetric, err := testState.Registry.NewMetric("my_metric", metrics.Counter)
+	require.NoError(t, err)
+	testMetric.Thresholds = metrics.NewThresholds([]string{"count>5"})
+	cs := getControlSurface(t, testState)
+	cs.MetricsEngine.ObservedMetrics = map[string]*metrics.Metric{
+		"my_metric": testMetric,
+	}
+
+	srv := httptest.NewServer(NewHandler(cs))
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/v1/metrics/stream?interval=100ms", nil)
+	require.NoError(t, err)
+	res, err := srv.Client().Do(req) //nolint:bodyclose // closed in the cleanup
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, res.Body.Close())
+	})
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+	events := readStreamEvents(t, res)
+
+	var snapshot MetricsSnapshot
+	waitForStreamEvent(t, events, MetricsStreamSnapshotEvent, &snapshot)
+	resMetrics := snapshot.Metrics()
+	require.Len(t, resMetrics, 1)
+	assert.Equal(t, "my_metric", resMetrics[0].Name)
+
+	testState.Events.Emit(&event.Event{Type: event.TestStart})
+	var lifecycleEvent TestLifecycleEvent
+	waitForStreamEvent(t, events, MetricsStreamTestStartEvent, &lifecycleEvent)
+
+	testState.Events.Emit(&event.Event{Type: event.IterError, Data: event.IterData{
+		Iteration:    3,
+		VUID:         2,
+		ScenarioName: "default",
+		Error:        errors.New("something went wrong"),
+	}})
+	var iterErr IterationError
+	waitForStreamEvent(t, events, MetricsStreamIterationErrorEvent, &iterErr)
+	assert.Equal(t, IterationError{
+		Scenario:  "default",
+		VUID:      2,
+		Iteration: 3,
+		Error:     "something went wrong",
+	}, iterErr)
+
+	setThresholdFailed := func(failed bool) {
+		cs.MetricsEngine.MetricsLock.Lock()
+		defer cs.MetricsEngine.MetricsLock.Unlock()
+		testMetric.Tainted = null.BoolFrom(failed)
+		testMetric.Thresholds.Thresholds[0].LastFailed = failed
+	}
+	setThresholdFailed(true)
+	var status ThresholdStatus
+	waitForStreamEvent(t, events, MetricsStreamThresholdEvent, &status)
+	assert.Equal(t, ThresholdStatus{Metric: "my_metric", Threshold: "count>5", Ok: false}, status)
+
+	setThresholdFailed(false)
+	waitForStreamEvent(t, events, MetricsStreamThresholdEvent, &status)
+	assert.Equal(t, ThresholdStatus{Metric: "my_metric", Threshold: "count>5", Ok: true}, status)
+
+	testState.Events.Emit(&event.Event{Type: event.TestEnd})
+	waitForStreamEvent(t, events, MetricsStreamTestEndEvent, &lifecycleEvent)
+	select {
+	case evt := <-events:
+		assert.Equal(t, MetricsStreamSnapshotEvent, evt.name)
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "timed out waiting for the final metrics snapshot")
+	}
+}
+
+func TestGetMetricsStreamInvalidInterval(t *testing.T) {
+	t.Parallel()
+
+	cs := getControlSurface(t, getTestRunState(t, lib.Options{}, &minirunner.MiniRunner{}))
+	rw := httptest.NewRecorder()
+	NewHandler(cs).ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/v1/metrics/stream?interval=10ms", nil))
+	res := rw.Result()
+	t.Cleanup(func() {
+		assert.NoError(t, res.Body.Close())
+	})
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	assert.Contains(t, rw.Body.String(), "the interval must be a duration of at least 100ms")
+}
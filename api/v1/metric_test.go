@@ -3,6 +3,7 @@ package v1
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -101,3 +102,24 @@ func TestNewMetric(t *testing.T) {
 	assert.Equal(t, metrics.Time, m.Contains.Type)
 	assert.NotEmpty(t, m.Sample)
 }
+
+func TestNewMetricWithWindowedThresholds(t *testing.T) {
+	t.Parallel()
+
+	m, err := metrics.NewRegistry().NewMetric("test_metric", metrics.Counter)
+	require.NoError(t, err)
+	m.Thresholds = metrics.NewThresholds([]string{"count over 30s<10"})
+	require.NoError(t, m.Thresholds.Parse())
+	m.Sink.Add(metrics.Sample{Time: time.Now(), Value: 5})
+
+	window := metrics.NewSink(metrics.Counter)
+	window.Add(metrics.Sample{Time: time.Now(), Value: 2})
+	_, err = m.Thresholds.RunWithWindows(m.Sink, map[time.Duration]metrics.Sink{30 * time.Second: window}, time.Minute)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]float64{
+		"count":          5,
+		"rate":           5.0 / 60,
+		"count over 30s": 2,
+	}, NewMetric(m, time.Minute).Sample)
+}
@@ -0,0 +1,60 @@
+package v1
+
+import (
+	"encoding/json"
+
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/executor"
+	"go.k6.io/k6/lib/types"
+)
+
+// Scenario represents the current state of a scenario of the test run. Its
+// config and description reflect the live updates of the scenario.
+type Scenario struct {
+	Name        string             `json:"-" yaml:"name"`
+	Executor    string             `json:"executor" yaml:"executor"`
+	Description string             `json:"description" yaml:"description"`
+	Config      json.RawMessage    `json:"config" yaml:"config"`
+	Progress    []string           `json:"progress" yaml:"progress"`
+	Stopped     bool               `json:"stopped" yaml:"stopped"`
+	StoppedAt   types.NullDuration `json:"stoppedAt" yaml:"stoppedAt"`
+}
+
+// NewScenario constructs the v1.Scenario of the given executor.
+func NewScenario(exec lib.Executor, es *lib.ExecutionState) (Scenario, error) {
+	config := exec.GetConfig()
+	rawConfig, err := json.Marshal(config)
+	if err != nil {
+		return Scenario{}, err
+	}
+	scenario := Scenario{
+		Name:        config.GetName(),
+		Executor:    config.GetType(),
+		Description: config.GetDescription(es.ExecutionTuple),
+		Config:      rawConfig,
+		Progress:    exec.GetProgress().Render(0, 0).Right,
+	}
+	if stoppedAt, ok := es.GetScenarioStopTime(scenario.Name); ok {
+		scenario.Stopped = true
+		scenario.StoppedAt = types.NullDurationFrom(stoppedAt)
+	}
+	return scenario, nil
+}
+
+// ScenarioPatch is the change of a running scenario that is sent with a PATCH
+// request. Which changes are supported depends on the executor of the
+// scenario, except for stopping it, which all but the externally-controlled
+// one support.
+type ScenarioPatch struct {
+	Stopped    bool             `json:"stopped" yaml:"stopped"`
+	Rate       null.Int         `json:"rate" yaml:"rate"`
+	SkipStages null.Int         `json:"skipStages" yaml:"skipStages"`
+	AddStages  []executor.Stage `json:"addStages" yaml:"addStages"`
+}
+
+func (sp ScenarioPatch) getUpdate() (executor.ScenarioUpdate, bool) {
+	update := executor.ScenarioUpdate{Rate: sp.Rate, SkipStages: sp.SkipStages, AddStages: sp.AddStages}
+	return update, sp.Rate.Valid || sp.SkipStages.Valid || len(sp.AddStages) > 0
+}
@@ -0,0 +1,70 @@
+package v1
+
+// ScenarioJSONAPI is JSON API envelop for a scenario
+type ScenarioJSONAPI struct {
+	Data scenarioData `json:"data"`
+}
+
+// ScenariosJSONAPI is JSON API envelop for scenarios
+type ScenariosJSONAPI struct {
+	Data []scenarioData `json:"data"`
+}
+
+type scenarioData struct {
+	Type       string   `json:"type"`
+	ID         string   `json:"id"`
+	Attributes Scenario `json:"attributes"`
+}
+
+func newScenarioData(s Scenario) scenarioData {
+	return scenarioData{
+		Type:       "scenarios",
+		ID:         s.Name,
+		Attributes: s,
+	}
+}
+
+// Scenario extracts the v1.Scenario from the JSON API envelop
+func (s ScenarioJSONAPI) Scenario() Scenario {
+	scenario := s.Data.Attributes
+	scenario.Name = s.Data.ID
+	return scenario
+}
+
+// Scenarios extracts the v1.Scenario list from the JSON API envelop
+func (s ScenariosJSONAPI) Scenarios() []Scenario {
+	scenarios := make([]Scenario, len(s.Data))
+	for i, data := range s.Data {
+		scenarios[i] = data.Attributes
+		scenarios[i].Name = data.ID
+	}
+	return scenarios
+}
+
+// ScenarioPatchJSONAPI is JSON API envelop for the change of a scenario
+type ScenarioPatchJSONAPI struct {
+	Data scenarioPatchData `json:"data"`
+}
+
+type scenarioPatchData struct {
+	Type       string        `json:"type"`
+	ID         string        `json:"id"`
+	Attributes ScenarioPatch `json:"attributes"`
+}
+
+// NewScenarioPatchJSONAPI creates the JSON API envelop for the change of the
+// scenario with the given name
+func NewScenarioPatchJSONAPI(name string, patch ScenarioPatch) ScenarioPatchJSONAPI {
+	return ScenarioPatchJSONAPI{
+		Data: scenarioPatchData{
+			Type:       "scenarios",
+			ID:         name,
+			Attributes: patch,
+		},
+	}
+}
+
+// Patch extracts the v1.ScenarioPatch from the JSON API envelop
+func (s ScenarioPatchJSONAPI) Patch() ScenarioPatch {
+	return s.Data.Attributes
+}
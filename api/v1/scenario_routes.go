@@ -0,0 +1,126 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/executor"
+)
+
+func getScenarioExecutor(cs *ControlSurface, name string) lib.Executor {
+	for _, exec := range cs.Scheduler.GetExecutors() {
+		if exec.GetConfig().GetName() == name {
+			return exec
+		}
+	}
+	return nil
+}
+
+func handleGetScenarios(cs *ControlSurface, rw http.ResponseWriter, _ *http.Request) {
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	executors := cs.Scheduler.GetExecutors()
+	envelop := ScenariosJSONAPI{Data: make([]scenarioData, 0, len(executors))}
+	for _, exec := range executors {
+		scenario, err := NewScenario(exec, cs.Scheduler.GetState())
+		if err != nil {
+			apiError(rw, "Encoding error", err.Error(), http.StatusInternalServerError)
+			return
+		}
+		envelop.Data = append(envelop.Data, newScenarioData(scenario))
+	}
+
+	data, err := json.Marshal(envelop)
+	if err != nil {
+		apiError(rw, "Encoding error", err.Error(), http.StatusInternalServerError)
+		return
+	}
+	_, _ = rw.Write(data)
+}
+
+func handleGetScenario(cs *ControlSurface, rw http.ResponseWriter, _ *http.Request, name string) {
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	exec := getScenarioExecutor(cs, name)
+	if exec == nil {
+		apiError(rw, "Not Found", "No scenario with that name was found", http.StatusNotFound)
+		return
+	}
+	writeScenario(cs, rw, exec)
+}
+
+func handlePatchScenario(cs *ControlSurface, rw http.ResponseWriter, r *http.Request, name string) {
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	exec := getScenarioExecutor(cs, name)
+	if exec == nil {
+		apiError(rw, "Not Found", "No scenario with that name was found", http.StatusNotFound)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		apiError(rw, "Couldn't read request", err.Error(), http.StatusBadRequest)
+		return
+	}
+	var patchEnvelop ScenarioPatchJSONAPI
+	if err = json.Unmarshal(body, &patchEnvelop); err != nil {
+		apiError(rw, "Invalid data", err.Error(), http.StatusBadRequest)
+		return
+	}
+	patch := patchEnvelop.Patch()
+
+	if _, ok := exec.(*executor.ExternallyControlled); ok {
+		apiError(rw, "Scenario update error", fmt.Sprintf(
+			"the externally-controlled scenario %s can only be changed with PATCH /v1/status", name,
+		), http.StatusBadRequest)
+		return
+	}
+
+	if update, ok := patch.getUpdate(); ok {
+		updatable, isUpdatable := exec.(lib.LiveUpdatableExecutor)
+		if !isUpdatable {
+			apiError(rw, "Scenario update error", fmt.Sprintf(
+				"the %s executor of scenario %s doesn't support live updates", exec.GetConfig().GetType(), name,
+			), http.StatusBadRequest)
+			return
+		}
+		if err = updatable.UpdateConfig(r.Context(), update); err != nil {
+			apiError(rw, "Scenario update error", err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	if patch.Stopped {
+		stoppable, isStoppable := exec.(lib.StoppableExecutor)
+		if !isStoppable {
+			apiError(rw, "Scenario stop error", fmt.Sprintf(
+				"the %s executor of scenario %s can't be stopped", exec.GetConfig().GetType(), name,
+			), http.StatusBadRequest)
+			return
+		}
+		if err = stoppable.Stop(); err != nil {
+			apiError(rw, "Scenario stop error", err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	writeScenario(cs, rw, exec)
+}
+
+func writeScenario(cs *ControlSurface, rw http.ResponseWriter, exec lib.Executor) {
+	scenario, err := NewScenario(exec, cs.Scheduler.GetState())
+	if err != nil {
+		apiError(rw, "Encoding error", err.Error(), http.StatusInternalServerError)
+		return
+	}
+	data, err := json.Marshal(ScenarioJSONAPI{Data: newScenarioData(scenario)})
+	if err != nil {
+		apiError(rw, "Encoding error", err.Error(), http.StatusInternalServerError)
+		return
+	}
+	_, _ = rw.Write(data)
+}
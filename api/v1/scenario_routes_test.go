@@ -0,0 +1,230 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/internal/execution"
+	"go.k6.io/k6/internal/execution/local"
+	"go.k6.io/k6/internal/lib/testutils/minirunner"
+	"go.k6.io/k6/internal/metrics/engine"
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/executor"
+	"go.k6.io/k6/lib/types"
+	"go.k6.io/k6/metrics"
+	"go.k6.io/k6/output"
+)
+
+const testScenarios = `{
+	"car": {"executor": "constant-arrival-rate", "rate": 10, "duration": "1m", "preAllocatedVUs": 2, "maxVUs": 2},
+	"rvu": {"executor": "ramping-vus", "startVUs": 0, "stages": [{"duration": "1m", "target": 2}]},
+	"ext": {"executor": "externally-controlled", "vus": 0, "maxVUs": 2, "duration": "0"}
+}`
+
+func getScenarioTestRunState(t *testing.T) *lib.TestRunState {
+	scenarios := lib.ScenarioConfigs{}
+	require.NoError(t, json.Unmarshal([]byte(testScenarios), &scenarios))
+	return getTestRunState(t, lib.Options{Scenarios: scenarios}, &minirunner.MiniRunner{})
+}
+
+func TestGetScenarios(t *testing.T) {
+	t.Parallel()
+
+	cs := getControlSurface(t, getScenarioTestRunState(t))
+
+	t.Run("list", func(t *testing.T) {
+		t.Parallel()
+
+		rw := httptest.NewRecorder()
+		NewHandler(cs).ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/v1/scenarios", nil))
+		res := rw.Result()
+		t.Cleanup(func() {
+			assert.NoError(t, res.Body.Close())
+		})
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		var envelop ScenariosJSONAPI
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &envelop))
+		executors := make(map[string]string)
+		for _, scenario := range envelop.Scenarios() {
+			executors[scenario.Name] = scenario.Executor
+		}
+		assert.Equal(t, map[string]string{
+			"car": "constant-arrival-rate",
+			"rvu": "ramping-vus",
+			"ext": "externally-controlled",
+		}, executors)
+	})
+
+	t.Run("scenario", func(t *testing.T) {
+		t.Parallel()
+
+		rw := httptest.NewRecorder()
+		NewHandler(cs).ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/v1/scenarios/car", nil))
+		res := rw.Result()
+		t.Cleanup(func() {
+			assert.NoError(t, res.Body.Close())
+		})
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		var envelop ScenarioJSONAPI
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &envelop))
+		scenario := envelop.Scenario()
+		assert.Equal(t, "car", scenario.Name)
+		assert.Equal(t, "10.00 iterations/s for 1m0s (maxVUs: 2, gracefulStop: 30s)", scenario.Description)
+		assert.False(t, scenario.Stopped)
+		assert.False(t, scenario.StoppedAt.Valid)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		rw := httptest.NewRecorder()
+		NewHandler(cs).ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/v1/scenarios/missing", nil))
+		res := rw.Result()
+		t.Cleanup(func() {
+			assert.NoError(t, res.Body.Close())
+		})
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+}
+
+func TestPatchScenario(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		scenario     string
+		patch        ScenarioPatch
+		expectedCode int
+		check        func(t *testing.T, scenario Scenario)
+	}{
+		"rate": {
+			scenario:     "car",
+			patch:        ScenarioPatch{Rate: null.IntFrom(20)},
+			expectedCode: http.StatusOK,
+			check: func(t *testing.T, scenario Scenario) {
+				assert.Contains(t, scenario.Description, "20.00 iterations/s")
+				assert.Contains(t, string(scenario.Config), `"rate":20`)
+			},
+		},
+		"stages of a constant-arrival-rate scenario": {
+			scenario:     "car",
+			patch:        ScenarioPatch{SkipStages: null.IntFrom(1)},
+			expectedCode: http.StatusBadRequest,
+		},
+		"stop": {
+			scenario:     "car",
+			patch:        ScenarioPatch{Stopped: true},
+			expectedCode: http.StatusOK,
+			check: func(t *testing.T, scenario Scenario) {
+				assert.True(t, scenario.Stopped)
+				assert.True(t, scenario.StoppedAt.Valid)
+			},
+		},
+		"skip and add stages": {
+			scenario: "rvu",
+			patch: ScenarioPatch{SkipStages: null.IntFrom(1), AddStages: []executor.Stage{
+				{Duration: types.NullDurationFrom(time.Second), Target: null.IntFrom(1)},
+			}},
+			expectedCode: http.StatusOK,
+			check: func(t *testing.T, scenario Scenario) {
+				assert.Contains(t, string(scenario.Config), `"target":1`)
+			},
+		},
+		"externally controlled": {
+			scenario:     "ext",
+			patch:        ScenarioPatch{Stopped: true},
+			expectedCode: http.StatusBadRequest,
+		},
+		"not found": {
+			scenario:     "missing",
+			patch:        ScenarioPatch{Stopped: true},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cs := runScenarioTestScheduler(t)
+			payload, err := json.Marshal(NewScenarioPatchJSONAPI(tc.scenario, tc.patch))
+			require.NoError(t, err)
+
+			rw := httptest.NewRecorder()
+			NewHandler(cs).ServeHTTP(rw, httptest.NewRequest(
+				http.MethodPatch, "/v1/scenarios/"+tc.scenario, bytes.NewReader(payload)))
+			res := rw.Result()
+			t.Cleanup(func() {
+				assert.NoError(t, res.Body.Close())
+			})
+			require.Equal(t, "application/json; charset=utf-8", rw.Header().Get("Content-Type"))
+			require.Equal(t, tc.expectedCode, res.StatusCode, rw.Body.String())
+			if tc.check == nil {
+				return
+			}
+
+			var envelop ScenarioJSONAPI
+			require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &envelop))
+			tc.check(t, envelop.Scenario())
+		})
+	}
+}
+
+// runScenarioTestScheduler runs the test scenarios until the end of the test.
+func runScenarioTestScheduler(t *testing.T) *ControlSurface {
+	testState := getScenarioTestRunState(t)
+	execScheduler, err := execution.NewScheduler(testState, local.NewController())
+	require.NoError(t, err)
+
+	metricsEngine, err := engine.NewMetricsEngine(testState.Registry, testState.Logger)
+	require.NoError(t, err)
+
+	globalCtx, globalCancel := context.WithCancel(context.Background())
+	runCtx, runAbort := execution.NewTestRunContext(globalCtx, testState.Logger)
+
+	outputManager := output.NewManager([]output.Output{metricsEngine.CreateIngester()}, testState.Logger, runAbort)
+	samples := make(chan metrics.SampleContainer, 1000)
+	waitMetricsFlushed, stopOutputs, err := outputManager.Start(samples)
+	require.NoError(t, err)
+
+	stopEmission, err := execScheduler.Init(runCtx, samples)
+	require.NoError(t, err)
+
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		assert.ErrorContains(t, execScheduler.Run(globalCtx, runCtx, samples), "custom cancel signal")
+		stopEmission()
+		close(samples)
+		wg.Done()
+	}()
+	t.Cleanup(func() {
+		runAbort(fmt.Errorf("custom cancel signal"))
+		waitMetricsFlushed()
+		wg.Wait()
+		stopOutputs(nil)
+		globalCancel()
+	})
+	// wait for the executors to start
+	time.Sleep(200 * time.Millisecond)
+
+	return &ControlSurface{
+		RunCtx:        runCtx,
+		Samples:       samples,
+		MetricsEngine: metricsEngine,
+		Scheduler:     execScheduler,
+		RunState:      testState,
+	}
+}
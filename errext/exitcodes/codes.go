@@ -54,4 +54,8 @@ const (
 
 	// MarkedAsFailed indicates that the test was marked as failed.
 	MarkedAsFailed ExitCode = 110
+
+	// PerformanceRegression indicates that `k6 compare` found one or more
+	// regressions above the configured tolerances between two test runs.
+	PerformanceRegression ExitCode = 111
 )
@@ -0,0 +1,38 @@
+import { EventSource } from 'k6/experimental/sse';
+
+export const options = {
+	thresholds: {
+		sse_time_to_first_event: ['p(95)<1000'],
+	},
+};
+
+export default function () {
+	// the connection is reestablished with a Last-Event-ID header if it's lost
+	const es = new EventSource('https://sse.dev/test', {
+		headers: { 'X-Client': `VU ${__VU}` },
+		tags: { stream: 'test' },
+	});
+
+	es.addEventListener('open', () => {
+		console.log(`VU ${__VU} connected`);
+	});
+
+	// events without an event field are dispatched as "message" events
+	es.addEventListener('message', (e) => {
+		console.log(`VU ${__VU} received: ${e.data} (last event ID ${e.lastEventId})`);
+	});
+
+	// named events can be listened to with their names
+	es.addEventListener('update', (e) => {
+		console.log(`VU ${__VU} received an update: ${e.data}`);
+	});
+
+	es.addEventListener('error', (e) => {
+		console.error(`VU ${__VU} got an error: ${e.error}`);
+	});
+
+	// the EventSource keeps the iteration running until it's closed
+	setTimeout(() => {
+		es.close();
+	}, 10000);
+}
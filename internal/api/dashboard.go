@@ -0,0 +1,46 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+)
+
+// dashboardPath is where the REST API server serves the web dashboard.
+const dashboardPath = "/dashboard/"
+
+// handleDashboard serves the web dashboard of the `web-dashboard` output from
+// the REST API server, by proxying the requests to the server of the output.
+// The dashboardURL function returns its address, or an empty string if the
+// output isn't enabled or doesn't serve the dashboard. It's called for every
+// request, since the output only knows its address after it has started.
+func handleDashboard(dashboardURL func() string) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			rw.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		target, err := url.Parse(dashboardURL())
+		if err != nil || target.Host == "" {
+			http.Error(rw, "the web dashboard isn't enabled, run the test with --out web-dashboard", http.StatusNotFound)
+			return
+		}
+		if r.URL.Path == strings.TrimSuffix(dashboardPath, "/") || r.URL.Path == dashboardPath {
+			// the page loads its events from the given endpoint, and the rest relative to itself
+			http.Redirect(rw, r, dashboardPath+"ui/?endpoint="+dashboardPath, http.StatusTemporaryRedirect)
+			return
+		}
+
+		proxy := &httputil.ReverseProxy{
+			Rewrite: func(pr *httputil.ProxyRequest) {
+				pr.SetURL(target)
+				pr.Out.URL.Path = "/" + strings.TrimPrefix(pr.In.URL.Path, dashboardPath)
+				pr.Out.URL.RawPath = ""
+			},
+			// the events of the dashboard are streamed
+			FlushInterval: -1,
+		}
+		proxy.ServeHTTP(rw, r)
+	})
+}
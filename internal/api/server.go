@@ -6,6 +6,7 @@ import (
 	"context"
 	"expvar"
 	"fmt"
+	"net"
 	"net/http"
 	_ "net/http/pprof" //nolint:gosec // Register pprof handlers
 	"time"
@@ -20,10 +21,12 @@ import (
 	"go.k6.io/k6/metrics"
 )
 
-func newHandler(cs *v1.ControlSurface, profilingEnabled bool) http.Handler {
+func newHandler(cs *v1.ControlSurface, profilingEnabled bool, dashboardURL func() string) http.Handler {
 	mux := http.NewServeMux()
 	mux.Handle("/v1/", v1.NewHandler(cs))
 	mux.Handle("/ping", handlePing(cs.RunState.Logger))
+	mux.Handle("/dashboard", handleDashboard(dashboardURL))
+	mux.Handle(dashboardPath, handleDashboard(dashboardURL))
 	mux.Handle("/", handlePing(cs.RunState.Logger))
 
 	injectProfilerHandler(mux, profilingEnabled)
@@ -48,7 +51,9 @@ func injectProfilerHandler(mux *http.ServeMux, profilingEnabled bool) {
 	mux.Handle("/metrics", promhttp.Handler())
 }
 
-// GetServer returns a http.Server instance that can serve k6's REST API.
+// GetServer returns a http.Server instance that can serve k6's REST API. It
+// also serves the web dashboard at /dashboard/, if the dashboardURL function
+// returns the address of the `web-dashboard` output.
 func GetServer(
 	runCtx context.Context,
 	addr string,
@@ -57,6 +62,7 @@ func GetServer(
 	samples chan metrics.SampleContainer,
 	me *engine.MetricsEngine,
 	es *execution.Scheduler,
+	dashboardURL func() string,
 ) *http.Server {
 	// TODO: reduce the control surface as much as possible? For example, if
 	// we refactor the Runner API, we won't need to send the Samples channel.
@@ -68,8 +74,16 @@ func GetServer(
 		RunState:      runState,
 	}
 
-	mux := withLoggingHandler(runState.Logger, newHandler(cs, profilingEnabled))
-	return &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	mux := withLoggingHandler(runState.Logger, newHandler(cs, profilingEnabled, dashboardURL))
+	srv := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	// The contexts of the requests are cancelled when the server is shut down,
+	// so the long-lived ones, like the metrics stream, end with it.
+	baseCtx, baseCancel := context.WithCancel(context.Background())
+	srv.BaseContext = func(net.Listener) context.Context { return baseCtx }
+	srv.RegisterOnShutdown(baseCancel)
+
+	return srv
 }
 
 type wrappedResponseWriter struct {
@@ -82,6 +96,12 @@ func (w *wrappedResponseWriter) WriteHeader(status int) {
 	w.ResponseWriter.WriteHeader(w.status)
 }
 
+// Unwrap allows http.ResponseController to reach the features of the original
+// http.ResponseWriter, e.g. flushing.
+func (w *wrappedResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
 // withLoggingHandler returns the middleware which logs response status for request.
 func withLoggingHandler(l logrus.FieldLogger, next http.Handler) http.HandlerFunc {
 	return func(rw http.ResponseWriter, r *http.Request) {
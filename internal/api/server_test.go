@@ -69,3 +69,42 @@ func TestPing(t *testing.T) {
 	assert.Equal(t, []byte{'o', 'k'}, rw.Body.Bytes())
 	assert.NoError(t, res.Body.Close())
 }
+
+func TestDashboard(t *testing.T) {
+	t.Parallel()
+
+	// the server of the web-dashboard output
+	dashboardSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(rw, "%s %s", r.URL.Path, r.URL.Query().Get("endpoint"))
+	}))
+	t.Cleanup(dashboardSrv.Close)
+	handler := handleDashboard(func() string { return dashboardSrv.URL })
+
+	for _, path := range []string{"/dashboard", "/dashboard/"} {
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusTemporaryRedirect, rw.Code)
+		assert.Equal(t, "/dashboard/ui/?endpoint=/dashboard/", rw.Header().Get("Location"))
+	}
+
+	for path, expected := range map[string]string{
+		"/dashboard/ui/?endpoint=/dashboard/": "/ui/ /dashboard/",
+		"/dashboard/ui/assets/index.js":       "/ui/assets/index.js ",
+		"/dashboard/events":                   "/events ",
+		"/dashboard/report":                   "/report ",
+	} {
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusOK, rw.Code, path)
+		assert.Equal(t, expected, rw.Body.String(), path)
+	}
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest(http.MethodPost, "/dashboard/events", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rw.Code)
+
+	rw = httptest.NewRecorder()
+	handleDashboard(func() string { return "" }).ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/dashboard/", nil))
+	assert.Equal(t, http.StatusNotFound, rw.Code)
+	assert.Contains(t, rw.Body.String(), "run the test with --out web-dashboard")
+}
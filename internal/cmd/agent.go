@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/cmd/state"
+	"go.k6.io/k6/errext"
+	"go.k6.io/k6/errext/exitcodes"
+	"go.k6.io/k6/internal/execution"
+	"go.k6.io/k6/internal/execution/distributed"
+	"go.k6.io/k6/internal/loader"
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/fsext"
+)
+
+// cmdAgent handles the `k6 agent` sub-command
+type cmdAgent struct {
+	gs *state.GlobalState
+
+	token string
+	tlsCA string
+}
+
+func (c *cmdAgent) run(cmd *cobra.Command, args []string) (err error) {
+	coordinatorAddress := args[0]
+	if c.token == "" {
+		c.token = c.gs.Env[distributedTokenEnvVar]
+	}
+	client, err := c.client()
+	if err != nil {
+		return errext.WithExitCodeIfNone(err, exitcodes.InvalidConfig)
+	}
+	controller, data, err := distributed.Register(c.gs.Ctx, client, coordinatorAddress, c.token, c.gs.Logger)
+	if err != nil {
+		return err
+	}
+	c.gs.Logger.Infof("Registered with the coordinator as instance %d, executing segment %s",
+		data.InstanceID, data.ExecutionSegment)
+
+	defer func() {
+		if derr := controller.Done(err); derr != nil {
+			c.gs.Logger.WithError(derr).Warn("Could not report the end of the test run to the coordinator")
+		}
+	}()
+
+	runCmd := &cmdRun{
+		gs: c.gs,
+		loadConfiguredTest: func(cmd *cobra.Command, _ []string) (*loadedAndConfiguredTest, execution.Controller, error) {
+			test, err := c.loadTest(cmd, coordinatorAddress, data)
+			return test, controller, err
+		},
+	}
+	return runCmd.run(cmd, args)
+}
+
+// loadTest loads the test archive that was received from the coordinator and
+// configures it to execute only the assigned execution segment.
+func (c *cmdAgent) loadTest(
+	cmd *cobra.Command, coordinatorAddress string, data *distributed.InstanceData,
+) (*loadedAndConfiguredTest, error) {
+	segment, err := lib.NewExecutionSegmentFromString(data.ExecutionSegment)
+	if err != nil {
+		return nil, err
+	}
+	sequence, err := lib.NewExecutionSegmentSequenceFromString(data.ExecutionSegmentSequence)
+	if err != nil {
+		return nil, err
+	}
+
+	pwd, err := c.gs.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	src := &loader.SourceData{
+		URL:  &url.URL{Scheme: "http", Host: coordinatorAddress, Path: "/archive.tar"},
+		Data: data.Archive,
+	}
+	test, err := loadTestFromSource(c.gs, cmd, coordinatorAddress, src, loader.CreateFilesystems(c.gs.FS), pwd)
+	if err != nil {
+		return nil, err
+	}
+
+	return test.consolidateDeriveAndValidateConfig(c.gs, cmd, func(flags *pflag.FlagSet) (Config, error) {
+		out, err := flags.GetStringArray("out")
+		if err != nil {
+			return Config{}, err
+		}
+		return Config{
+			Options: lib.Options{
+				ExecutionSegment:         segment,
+				ExecutionSegmentSequence: &sequence,
+			},
+			Out:             out,
+			Linger:          getNullBool(flags, "linger"),
+			NoUsageReport:   getNullBool(flags, "no-usage-report"),
+			NoArchiveUpload: null.NewBool(true, true),
+		}, nil
+	})
+}
+
+// client returns the HTTP client for the coordinator, which trusts the --tls-ca
+// certificates, if they are set, instead of the system ones.
+func (c *cmdAgent) client() (*http.Client, error) {
+	if c.tlsCA == "" {
+		return &http.Client{}, nil
+	}
+	caPEM, err := fsext.ReadFile(c.gs.FS, c.tlsCA)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the TLS CA certificates: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, errors.New("no PEM certificates were found in the --tls-ca file")
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert
+	transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	return &http.Client{Transport: transport}, nil
+}
+
+func (c *cmdAgent) flagSet() *pflag.FlagSet {
+	flags := pflag.NewFlagSet("", pflag.ContinueOnError)
+	flags.SortFlags = false
+	flags.AddFlagSet(runtimeOptionFlagSet(false))
+	flags.AddFlagSet(configFlagSet())
+	flags.StringVar(&c.token, "token", "",
+		"token of the coordinator, can also be set with the "+distributedTokenEnvVar+" environment variable")
+	flags.StringVar(&c.tlsCA, "tls-ca", "",
+		"path to the PEM certificates of the CAs that are trusted for an https:// coordinator address, "+
+			"instead of the system ones")
+	return flags
+}
+
+func getCmdAgent(gs *state.GlobalState) *cobra.Command {
+	c := &cmdAgent{gs: gs}
+
+	exampleText := getExampleText(gs, `
+  # Join the distributed test run of a coordinator running on the same machine.
+  {{.}} agent localhost:6566
+
+  # Join a remote coordinator over HTTPS and send the metrics of this instance to InfluxDB.
+  K6_DISTRIBUTED_TOKEN=s3cr3t {{.}} agent -o influxdb=http://1.2.3.4:8086/k6 https://10.0.0.1:6566`[1:])
+
+	agentCmd := &cobra.Command{
+		Use:   "agent",
+		Short: "Join a distributed test as an agent instance",
+		Long: `Join a distributed test as an agent instance.
+
+The agent connects to a running "k6 coordinator", receives the test archive and its own
+execution segment from it, and then executes that part of the test, synchronized with all
+of the other agent instances. The coordinator address starts with https:// when it's
+served over HTTPS.`,
+		Example: exampleText,
+		Args: exactArgsWithMsg(1,
+			"arg should be the address of the coordinator, e.g. localhost:6566 or https://10.0.0.1:6566"),
+		RunE: c.run,
+	}
+
+	agentCmd.Flags().SortFlags = false
+	agentCmd.Flags().AddFlagSet(c.flagSet())
+
+	return agentCmd
+}
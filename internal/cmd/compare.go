@@ -0,0 +1,704 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"go.k6.io/k6/cmd/state"
+	"go.k6.io/k6/errext"
+	"go.k6.io/k6/errext/exitcodes"
+	"go.k6.io/k6/internal/lib/summary"
+	"go.k6.io/k6/lib/fsext"
+	"go.k6.io/k6/metrics"
+)
+
+const (
+	defaultCompareTolerance         = 10.0
+	defaultCompareAbsoluteTolerance = 0.01
+)
+
+// cmdCompare handles the `k6 compare` sub-command
+type cmdCompare struct {
+	gs *state.GlobalState
+
+	tolerance         float64
+	absoluteTolerance float64
+	metricTolerances  map[string]string
+	metricDirections  map[string]string
+}
+
+func (c *cmdCompare) run(_ *cobra.Command, args []string) error {
+	tolerances, err := c.parseTolerances()
+	if err != nil {
+		return errext.WithExitCodeIfNone(err, exitcodes.InvalidConfig)
+	}
+	directions, err := c.parseDirections()
+	if err != nil {
+		return errext.WithExitCodeIfNone(err, exitcodes.InvalidConfig)
+	}
+
+	baseline, err := readSummaryData(c.gs.FS, args[0])
+	if err != nil {
+		return err
+	}
+	current, err := readSummaryData(c.gs.FS, args[1])
+	if err != nil {
+		return err
+	}
+
+	result := compareSummaries(baseline, current, tolerances, directions)
+	if len(result.unknownDirections) > 0 {
+		names := sortedKeys(result.unknownDirections)
+		return errext.WithExitCodeIfNone(fmt.Errorf(
+			"it's unknown whether the higher or the lower values of the metrics %s are worse, "+
+				"they have to be set with --metric-direction, e.g. %s=higher-is-worse, or %s=none for not comparing them",
+			strings.Join(names, ", "), names[0], names[0],
+		), exitcodes.InvalidConfig)
+	}
+
+	noColor := c.gs.Flags.NoColor || !c.gs.Stdout.IsTTY
+	if err := result.print(c.gs.Stdout, noColor); err != nil {
+		return err
+	}
+
+	if result.regressions > 0 {
+		return errext.WithExitCodeIfNone(
+			fmt.Errorf("%d regression(s) found between %q and %q", result.regressions, args[0], args[1]),
+			exitcodes.PerformanceRegression,
+		)
+	}
+
+	return nil
+}
+
+// parseTolerances validates the tolerance flags and returns the tolerance
+// resolver for the comparison.
+func (c *cmdCompare) parseTolerances() (compareTolerances, error) {
+	if c.tolerance < 0 {
+		return compareTolerances{}, fmt.Errorf("the tolerance should be a non-negative percentage, but got %g", c.tolerance)
+	}
+
+	if c.absoluteTolerance < 0 {
+		return compareTolerances{}, fmt.Errorf(
+			"the absolute tolerance should be a non-negative number, but got %g", c.absoluteTolerance)
+	}
+
+	t := compareTolerances{
+		defaultTolerance: c.tolerance,
+		absolute:         c.absoluteTolerance,
+		metrics:          make(map[string]float64, len(c.metricTolerances)),
+	}
+	for name, raw := range c.metricTolerances {
+		v, err := strconv.ParseFloat(strings.TrimSuffix(raw, "%"), 64)
+		if err != nil || v < 0 {
+			return compareTolerances{}, fmt.Errorf(
+				"the tolerance for metric %q should be a non-negative percentage, but got %q", name, raw)
+		}
+		t.metrics[name] = v
+	}
+
+	return t, nil
+}
+
+// parseDirections validates the regression directions set for the metrics.
+func (c *cmdCompare) parseDirections() (map[string]regressionDirection, error) {
+	directions := make(map[string]regressionDirection, len(c.metricDirections))
+	for name, raw := range c.metricDirections {
+		d, ok := regressionDirections[raw]
+		if !ok {
+			return nil, fmt.Errorf(
+				"the direction for metric %q should be higher-is-worse, lower-is-worse or none, but got %q", name, raw)
+		}
+		directions[name] = d
+	}
+	return directions, nil
+}
+
+func (c *cmdCompare) flagSet() *pflag.FlagSet {
+	flags := pflag.NewFlagSet("", pflag.ContinueOnError)
+	flags.SortFlags = false
+	flags.Float64Var(&c.tolerance, "tolerance", defaultCompareTolerance,
+		"the allowed change of the metric values, as a percentage of the baseline values")
+	flags.Float64Var(&c.absoluteTolerance, "absolute-tolerance", defaultCompareAbsoluteTolerance,
+		"the allowed change of the metric values whose baseline value is 0, in the unit of the metric "+
+			"(e.g. milliseconds for times)")
+	flags.StringToStringVar(&c.metricTolerances, "metric-tolerance", nil,
+		"override the tolerance for specific metrics, e.g. `http_req_duration=5,http_req_failed=0`")
+	flags.StringToStringVar(&c.metricDirections, "metric-direction", nil,
+		"set whether the higher or the lower values of metrics are worse, or none for not comparing them, "+
+			"e.g. `my_errors=higher-is-worse,my_throughput=lower-is-worse,my_counter=none`")
+	return flags
+}
+
+func getCmdCompare(gs *state.GlobalState) *cobra.Command {
+	c := &cmdCompare{gs: gs}
+
+	exampleText := getExampleText(gs, `
+  # Save the end-of-test summary data of two test runs.
+  {{.}} run --summary-mode=full --summary-json=baseline.json script.js
+  {{.}} run --summary-mode=full --summary-json=current.json script.js
+
+  # Compare them, failing if any value got worse by more than 10%.
+  {{.}} compare baseline.json current.json
+
+  # Compare the files written by "k6 run --summary-export" instead.
+  {{.}} compare baseline-export.json current-export.json
+
+  # Use a stricter tolerance for the HTTP request duration.
+  {{.}} compare --tolerance=20 --metric-tolerance=http_req_duration=5 baseline.json current.json
+
+  # Set the direction of the regressions of the custom metrics.
+  {{.}} compare --metric-direction=cart_errors=higher-is-worse,orders=lower-is-worse baseline.json current.json`[1:])
+
+	compareCmd := &cobra.Command{
+		Use:   "compare",
+		Short: "Compare the results of two test runs",
+		Long: `Compare the results of two test runs.
+
+The command reads the end-of-test summary data of two test runs, as written by
+"k6 run --summary-json", and prints the changes of the metrics' values in total,
+per group and per scenario. The groups and scenarios are only in the summary data
+of the test runs executed with "--summary-mode=full".
+
+The files written by "k6 run --summary-export" can be compared as well, but they
+only have the total values of the metrics and the checks of the groups, and not
+the types of the custom metrics, which are guessed from their values.
+
+The changes of the metrics' values are flagged as regressions when they get worse
+by more than the allowed tolerance, as well as the thresholds which passed in the
+baseline test run but fail in the current one. The changes of the values that are
+0 in the baseline test run can't be expressed as percentages, so they are compared
+with the absolute tolerance. If there are any regressions, the command exits with
+a non-zero code.
+
+Whether the higher or the lower values of a metric are worse is known for the
+built-in metrics. For the custom ones, it's derived from their thresholds, e.g.
+the higher values are worse for "p(95)<200", or it has to be set with
+--metric-direction, which overrides the others too. The command fails when it's
+unknown for any compared metric. For the Rate metrics, only the rates are compared.`,
+		Example: exampleText,
+		Args: exactArgsWithMsg(2, "args should be the paths of the baseline and the current summary data files, "+
+			"e.g. baseline.json current.json"),
+		RunE: c.run,
+	}
+
+	compareCmd.Flags().SortFlags = false
+	compareCmd.Flags().AddFlagSet(c.flagSet())
+
+	return compareCmd
+}
+
+// readSummaryData reads the end-of-test summary data written by either
+// `k6 run --summary-json` or `k6 run --summary-export`.
+func readSummaryData(fs fsext.Fs, path string) (*summary.Summary, error) {
+	data, err := fsext.ReadFile(fs, path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the summary data: %w", err)
+	}
+
+	// Only the --summary-export files have the metrics at the top level.
+	var probe struct {
+		Metrics json.RawMessage `json:"metrics"`
+	}
+	if err := json.Unmarshal(data, &probe); err == nil && probe.Metrics != nil {
+		s, err := parseSummaryExport(data)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"could not parse the summary data in %q, written by \"k6 run --summary-export\": %w", path, err)
+		}
+		return s, nil
+	}
+
+	s := &summary.Summary{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("could not parse the summary data in %q, it should be a file written by "+
+			"\"k6 run --summary-json\" or \"k6 run --summary-export\": %w", path, err)
+	}
+
+	return s, nil
+}
+
+// compareTolerances holds the allowed changes of the metrics' values, as
+// percentages of the baseline values.
+type compareTolerances struct {
+	defaultTolerance float64
+	metrics          map[string]float64
+
+	// absolute is the allowed change of the values whose baseline value is 0,
+	// in the unit of the metrics.
+	absolute float64
+}
+
+func (t compareTolerances) forMetric(name string) float64 {
+	if v, ok := t.metrics[name]; ok {
+		return v
+	}
+	return t.defaultTolerance
+}
+
+// metricDelta is the change of a single metric value between the two test runs.
+type metricDelta struct {
+	metric    string
+	stat      string
+	contains  string
+	baseline  float64
+	current   float64
+	change    float64 // as a percentage of the baseline value, unless absolute is true
+	absolute  bool    // the change is the difference of the values, since the baseline value is 0
+	regressed bool
+}
+
+// thresholdDelta is a threshold whose result differs between the two test runs.
+type thresholdDelta struct {
+	metric    string
+	source    string
+	baseline  string
+	current   string
+	regressed bool
+}
+
+// groupComparison holds the changes of the metrics of a group or a scenario.
+type groupComparison struct {
+	title  string
+	deltas []metricDelta
+	notes  []string
+}
+
+// comparison is the result of comparing two end-of-test summaries.
+type comparison struct {
+	thresholds  []thresholdDelta
+	groups      []groupComparison
+	regressions int
+
+	directions map[string]regressionDirection
+	// unknownDirections are the compared metrics whose direction is unknown
+	unknownDirections map[string]struct{}
+}
+
+func compareSummaries(
+	baseline, current *summary.Summary, tolerances compareTolerances, directions map[string]regressionDirection,
+) *comparison {
+	c := &comparison{
+		directions:        thresholdDirections(directions, baseline.Thresholds, current.Thresholds),
+		unknownDirections: make(map[string]struct{}),
+	}
+
+	c.compareThresholds(baseline.Thresholds, current.Thresholds)
+	c.compareGroup("TOTAL RESULTS", baseline.Group, current.Group, tolerances)
+	c.compareGroups("", "", baseline.Group, current.Group, tolerances)
+
+	for _, name := range sortedKeys(baseline.Scenarios, current.Scenarios) {
+		title := "SCENARIO: " + name
+		b, inBaseline := baseline.Scenarios[name]
+		cur, inCurrent := current.Scenarios[name]
+		if !inBaseline || !inCurrent {
+			c.groups = append(c.groups, groupComparison{title: title, notes: []string{onlyIn(inBaseline)}})
+			continue
+		}
+		c.compareGroup(title, b, cur, tolerances)
+		c.compareGroups(title+", ", "", b, cur, tolerances)
+	}
+
+	return c
+}
+
+func (c *comparison) compareThresholds(baseline, current summary.Thresholds) {
+	thresholdResult := func(mt summary.MetricThresholds, source string) string {
+		for _, t := range mt.Thresholds {
+			if t.Source != source {
+				continue
+			}
+			if t.Ok {
+				return "passed"
+			}
+			return "failed"
+		}
+		return "missing"
+	}
+
+	for _, name := range sortedKeys(baseline, current) {
+		var sources []string
+		seen := make(map[string]struct{})
+		for _, mt := range []summary.MetricThresholds{baseline[name], current[name]} {
+			for _, t := range mt.Thresholds {
+				if _, ok := seen[t.Source]; !ok {
+					seen[t.Source] = struct{}{}
+					sources = append(sources, t.Source)
+				}
+			}
+		}
+
+		for _, source := range sources {
+			b, cur := thresholdResult(baseline[name], source), thresholdResult(current[name], source)
+			if b == cur {
+				continue
+			}
+			d := thresholdDelta{
+				metric:    name,
+				source:    source,
+				baseline:  b,
+				current:   cur,
+				regressed: cur == "failed",
+			}
+			if d.regressed {
+				c.regressions++
+			}
+			c.thresholds = append(c.thresholds, d)
+		}
+	}
+}
+
+// compareGroups recursively compares the sub-groups of the provided groups.
+func (c *comparison) compareGroups(
+	titlePrefix, path string, baseline, current summary.Group, tolerances compareTolerances,
+) {
+	names := append([]string{}, baseline.GroupsOrder...)
+	for _, name := range current.GroupsOrder {
+		if _, ok := baseline.Groups[name]; !ok {
+			names = append(names, name)
+		}
+	}
+
+	for _, name := range names {
+		groupPath := path + "::" + name
+		title := titlePrefix + "GROUP: " + groupPath
+		b, inBaseline := baseline.Groups[name]
+		cur, inCurrent := current.Groups[name]
+		if !inBaseline || !inCurrent {
+			c.groups = append(c.groups, groupComparison{title: title, notes: []string{onlyIn(inBaseline)}})
+			continue
+		}
+		c.compareGroup(title, b, cur, tolerances)
+		c.compareGroups(titlePrefix, groupPath, b, cur, tolerances)
+	}
+}
+
+// compareGroup compares the metrics of a single group, without its sub-groups.
+func (c *comparison) compareGroup(title string, baseline, current summary.Group, tolerances compareTolerances) {
+	gc := groupComparison{title: title}
+	bMetrics, curMetrics := groupMetrics(baseline), groupMetrics(current)
+
+	for _, name := range sortedKeys(bMetrics, curMetrics) {
+		b, inBaseline := bMetrics[name]
+		cur, inCurrent := curMetrics[name]
+		if !inBaseline || !inCurrent {
+			gc.notes = append(gc.notes, name+": "+onlyIn(inBaseline))
+			continue
+		}
+
+		tolerance := tolerances.forMetric(name)
+		for _, stat := range sortedKeys(b.Values, cur.Values) {
+			bv, inBaseline := b.Values[stat]
+			cv, inCurrent := cur.Values[stat]
+			if !inBaseline || !inCurrent {
+				continue
+			}
+
+			d := metricDelta{
+				metric:   name,
+				stat:     stat,
+				contains: cur.Contains,
+				baseline: bv,
+				current:  cv,
+			}
+			d.change, d.absolute = valueChange(bv, cv)
+			allowed := tolerance
+			if d.absolute {
+				allowed = tolerances.absolute
+			}
+			direction, known := c.direction(cur.MetricInfo, stat)
+			if !known {
+				c.unknownDirections[name] = struct{}{}
+			}
+			d.regressed = direction != directionNone && d.change*float64(direction) > allowed
+			if d.regressed {
+				c.regressions++
+			}
+			gc.deltas = append(gc.deltas, d)
+		}
+	}
+
+	if len(gc.deltas) > 0 || len(gc.notes) > 0 {
+		c.groups = append(c.groups, gc)
+	}
+}
+
+// groupMetrics returns all the metrics of the group, regardless of their section.
+func groupMetrics(g summary.Group) map[string]summary.Metric {
+	result := make(map[string]summary.Metric)
+	for _, section := range []map[string]summary.Metric{
+		g.Metrics.HTTP, g.Metrics.Execution, g.Metrics.Network, g.Metrics.Browser,
+		g.Metrics.WebVitals, g.Metrics.Grpc, g.Metrics.WebSocket, g.Metrics.Custom,
+	} {
+		for name, m := range section {
+			result[name] = m
+		}
+	}
+
+	if g.Checks != nil {
+		for _, m := range []summary.Metric{g.Checks.Metrics.Total, g.Checks.Metrics.Success, g.Checks.Metrics.Fail} {
+			if len(m.Values) > 0 {
+				result[m.Name] = m
+			}
+		}
+	}
+
+	return result
+}
+
+// regressionDirection is the direction of the changes of a metric's values
+// which are regressions.
+type regressionDirection int
+
+const (
+	directionNone          regressionDirection = 0
+	directionHigherIsWorse regressionDirection = 1
+	directionLowerIsWorse  regressionDirection = -1
+)
+
+// regressionDirections are the values of the --metric-direction flag.
+var regressionDirections = map[string]regressionDirection{ //nolint:gochecknoglobals
+	"higher-is-worse": directionHigherIsWorse,
+	"lower-is-worse":  directionLowerIsWorse,
+	"none":            directionNone,
+}
+
+// builtinDirections are the regression directions of the built-in metrics. The
+// changes of the ones that depend on the load, like the counts of the requests,
+// aren't regressions.
+var builtinDirections = map[string]regressionDirection{ //nolint:gochecknoglobals
+	metrics.VUsName:               directionNone,
+	metrics.VUsMaxName:            directionNone,
+	metrics.IterationsName:        directionNone,
+	metrics.IterationDurationName: directionHigherIsWorse,
+	metrics.DroppedIterationsName: directionHigherIsWorse,
+	metrics.SustainableRateName:   directionLowerIsWorse,
+
+	metrics.ChecksName:        directionLowerIsWorse,
+	"checks_total":            directionNone,
+	"checks_succeeded":        directionLowerIsWorse,
+	"checks_failed":           directionHigherIsWorse,
+	metrics.GroupDurationName: directionHigherIsWorse,
+
+	metrics.HTTPReqsName:               directionNone,
+	metrics.HTTPReqFailedName:          directionHigherIsWorse,
+	metrics.HTTPReqDurationName:        directionHigherIsWorse,
+	metrics.HTTPReqBlockedName:         directionHigherIsWorse,
+	metrics.HTTPReqConnectingName:      directionHigherIsWorse,
+	metrics.HTTPReqTLSHandshakingName:  directionHigherIsWorse,
+	metrics.HTTPReqSendingName:         directionHigherIsWorse,
+	metrics.HTTPReqWaitingName:         directionHigherIsWorse,
+	metrics.HTTPReqReceivingName:       directionHigherIsWorse,
+	metrics.HTTPReqQUICHandshakingName: directionHigherIsWorse,
+	metrics.HTTPReqQUIC0RTTName:        directionLowerIsWorse,
+
+	metrics.WSSessionsName:         directionNone,
+	metrics.WSMessagesSentName:     directionNone,
+	metrics.WSMessagesReceivedName: directionNone,
+	metrics.WSPingName:             directionHigherIsWorse,
+	metrics.WSSessionDurationName:  directionNone,
+	metrics.WSConnectingName:       directionHigherIsWorse,
+
+	metrics.GRPCReqDurationName:  directionHigherIsWorse,
+	"grpc_streams":               directionNone,
+	"grpc_streams_msgs_sent":     directionNone,
+	"grpc_streams_msgs_received": directionNone,
+
+	"sse_time_to_first_event": directionHigherIsWorse,
+	"sse_events_received":     directionNone,
+	"sse_reconnects":          directionHigherIsWorse,
+
+	metrics.DataSentName:     directionNone,
+	metrics.DataReceivedName: directionNone,
+
+	"browser_data_sent":         directionNone,
+	"browser_data_received":     directionNone,
+	"browser_http_req_duration": directionHigherIsWorse,
+	"browser_http_req_failed":   directionHigherIsWorse,
+	"browser_web_vital_cls":     directionHigherIsWorse,
+	"browser_web_vital_fcp":     directionHigherIsWorse,
+	"browser_web_vital_fid":     directionHigherIsWorse,
+	"browser_web_vital_inp":     directionHigherIsWorse,
+	"browser_web_vital_lcp":     directionHigherIsWorse,
+	"browser_web_vital_ttfb":    directionHigherIsWorse,
+}
+
+// thresholdDirections returns the regression directions set with the flag,
+// with the ones derived from the thresholds of the other metrics: the higher
+// values are worse for the upper limits, like "p(95)<200", and the lower ones
+// for the lower limits. The metrics with both, or only with equalities, are
+// left out.
+func thresholdDirections(
+	directions map[string]regressionDirection, thresholds ...summary.Thresholds,
+) map[string]regressionDirection {
+	derived := make(map[string]map[regressionDirection]struct{})
+	for _, ts := range thresholds {
+		for name, mt := range ts {
+			for _, t := range mt.Thresholds {
+				var d regressionDirection
+				switch {
+				case strings.Contains(t.Source, "<"):
+					d = directionHigherIsWorse
+				case strings.Contains(t.Source, ">"):
+					d = directionLowerIsWorse
+				default:
+					continue
+				}
+				if derived[name] == nil {
+					derived[name] = make(map[regressionDirection]struct{})
+				}
+				derived[name][d] = struct{}{}
+			}
+		}
+	}
+
+	result := make(map[string]regressionDirection, len(directions)+len(derived))
+	for name, ds := range derived {
+		if len(ds) != 1 {
+			continue
+		}
+		for d := range ds {
+			result[name] = d
+		}
+	}
+	for name, d := range directions {
+		result[name] = d
+	}
+	return result
+}
+
+// direction returns the regression direction of the metric's stat, i.e. whether
+// its increase or its decrease is a regression, and whether it's known. The
+// submetrics have the direction of their parent metrics, unless it's set for them.
+func (c *comparison) direction(m summary.MetricInfo, stat string) (regressionDirection, bool) {
+	if m.Type == metrics.Rate.String() && stat != "rate" {
+		// the counts of the passes and the fails depend on the load
+		return directionNone, true
+	}
+
+	parent, _, _ := strings.Cut(m.Name, "{")
+	for _, directions := range []map[string]regressionDirection{c.directions, builtinDirections} {
+		for _, name := range []string{m.Name, parent} {
+			if d, ok := directions[name]; ok {
+				return d, true
+			}
+		}
+	}
+	return directionNone, false
+}
+
+// valueChange returns the change from the baseline to the current value, as a
+// percentage of the baseline value. When the baseline value is 0, it can't be
+// a percentage of it, so the difference of the values is returned instead,
+// with absolute set to true.
+func valueChange(baseline, current float64) (change float64, absolute bool) {
+	switch {
+	case baseline == current:
+		return 0, false
+	case baseline == 0:
+		return current, true
+	default:
+		return (current - baseline) / math.Abs(baseline) * 100, false
+	}
+}
+
+func onlyIn(baseline bool) string {
+	if baseline {
+		return "only in the baseline"
+	}
+	return "only in the current"
+}
+
+func sortedKeys[V any](maps ...map[string]V) []string {
+	seen := make(map[string]struct{})
+	keys := make([]string, 0)
+	for _, m := range maps {
+		for k := range m {
+			if _, ok := seen[k]; !ok {
+				seen[k] = struct{}{}
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (c *comparison) print(w io.Writer, noColor bool) error {
+	red := getColor(noColor, color.FgRed)
+	green := getColor(noColor, color.FgGreen)
+	bold := getColor(noColor, color.Bold)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	if len(c.thresholds) > 0 {
+		_, _ = fmt.Fprintf(tw, "%s\n\n", bold.Sprint("█ THRESHOLDS"))
+		for _, t := range c.thresholds {
+			line := fmt.Sprintf("  %s\t'%s'\t%s -> %s", t.metric, t.source, t.baseline, t.current)
+			if t.regressed {
+				line += "\t" + red.Sprint("regression")
+			}
+			_, _ = fmt.Fprintln(tw, line)
+		}
+		_, _ = fmt.Fprintln(tw)
+	}
+
+	for _, g := range c.groups {
+		_, _ = fmt.Fprintf(tw, "%s\n\n", bold.Sprint("█ "+g.title))
+		if len(g.deltas) > 0 {
+			_, _ = fmt.Fprintln(tw, "  METRIC\tSTAT\tBASELINE\tCURRENT\tCHANGE\t")
+		}
+		for _, d := range g.deltas {
+			line := fmt.Sprintf("  %s\t%s\t%s\t%s\t%s\t",
+				d.metric, d.stat, formatCompareValue(d.baseline, d.contains),
+				formatCompareValue(d.current, d.contains), formatChange(d))
+			if d.regressed {
+				line += red.Sprint("regression")
+			}
+			_, _ = fmt.Fprintln(tw, line)
+		}
+		for _, note := range g.notes {
+			_, _ = fmt.Fprintf(tw, "  %s\n", note)
+		}
+		_, _ = fmt.Fprintln(tw)
+	}
+
+	if c.regressions > 0 {
+		_, _ = fmt.Fprintln(tw, red.Sprintf("✗ %d regression(s) above the tolerance", c.regressions))
+	} else {
+		_, _ = fmt.Fprintln(tw, green.Sprint("✓ no regressions above the tolerance"))
+	}
+
+	return tw.Flush()
+}
+
+func formatCompareValue(v float64, contains string) string {
+	if contains == metrics.Time.String() {
+		return time.Duration(v * float64(time.Millisecond)).Round(time.Microsecond).String()
+	}
+	return strconv.FormatFloat(v, 'g', 6, 64)
+}
+
+func formatChange(d metricDelta) string {
+	switch {
+	case d.change == 0:
+		return "0%"
+	case d.absolute && d.change > 0:
+		return "+" + formatCompareValue(d.change, d.contains)
+	case d.absolute:
+		return formatCompareValue(d.change, d.contains)
+	default:
+		return fmt.Sprintf("%+.2f%%", d.change)
+	}
+}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"go.k6.io/k6/internal/lib/summary"
+	"go.k6.io/k6/metrics"
+)
+
+// exportedGroup is a group in the data written by `k6 run --summary-export`.
+type exportedGroup struct {
+	Name   string                   `json:"name"`
+	Groups map[string]exportedGroup `json:"groups"`
+	Checks map[string]exportedCheck `json:"checks"`
+}
+
+// exportedCheck is a check in the data written by `k6 run --summary-export`.
+type exportedCheck struct {
+	Name   string `json:"name"`
+	Passes int64  `json:"passes"`
+	Fails  int64  `json:"fails"`
+}
+
+// parseSummaryExport converts the data written by `k6 run --summary-export` to
+// a summary. It only has the total values of the metrics, the thresholds and
+// the checks of the groups, without any per-group or per-scenario metrics.
+func parseSummaryExport(data []byte) (*summary.Summary, error) {
+	var export struct {
+		RootGroup exportedGroup                         `json:"root_group"`
+		Metrics   map[string]map[string]json.RawMessage `json:"metrics"`
+	}
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, err
+	}
+
+	builtins := metrics.NewRegistry()
+	metrics.RegisterBuiltinMetrics(builtins)
+
+	s := summary.New()
+	for name, raw := range export.Metrics {
+		m, thresholds, err := parseExportedMetric(builtins, name, raw)
+		if err != nil {
+			return nil, err
+		}
+		s.Metrics.Custom[name] = m
+		if len(thresholds) > 0 {
+			s.Thresholds[name] = summary.MetricThresholds{Metric: m, Thresholds: thresholds}
+		}
+	}
+
+	s.Group = exportedGroupToSummary(export.RootGroup, s.Metrics)
+	// the total checks metric already covers the checks of the root group
+	s.Group.Checks = nil
+
+	return s, nil
+}
+
+// parseExportedMetric converts an exported metric to a summary metric and its thresholds.
+//
+// The export doesn't have the types of the metrics, so they are taken from the
+// built-in metrics, or guessed from the exported values for the custom ones.
+func parseExportedMetric(
+	builtins *metrics.Registry, name string, raw map[string]json.RawMessage,
+) (summary.Metric, []summary.Threshold, error) {
+	m := summary.Metric{Values: make(map[string]float64, len(raw))}
+	var thresholds []summary.Threshold
+	for key, value := range raw {
+		if key == "thresholds" {
+			// the exported value of each threshold is whether it failed
+			var failed map[string]bool
+			if err := json.Unmarshal(value, &failed); err != nil {
+				return m, nil, fmt.Errorf("invalid thresholds of the metric %q: %w", name, err)
+			}
+			for source, isFailed := range failed {
+				thresholds = append(thresholds, summary.Threshold{Source: source, Ok: !isFailed})
+			}
+			continue
+		}
+
+		var v float64
+		if err := json.Unmarshal(value, &v); err != nil {
+			return m, nil, fmt.Errorf("invalid value %q of the metric %q: %w", key, name, err)
+		}
+		m.Values[key] = v
+	}
+	sort.Slice(thresholds, func(i, j int) bool { return thresholds[i].Source < thresholds[j].Source })
+
+	m.MetricInfo = summary.MetricInfo{Name: name, Type: guessExportedMetricType(m.Values).String()}
+	m.Contains = metrics.Default.String()
+	if builtin := builtins.Get(name); builtin != nil {
+		m.Type = builtin.Type.String()
+		m.Contains = builtin.Contains.String()
+	}
+
+	// the rates are exported with their rate as "value"
+	if v, ok := m.Values["value"]; ok && m.Type == metrics.Rate.String() {
+		m.Values["rate"] = v
+		delete(m.Values, "value")
+	}
+
+	return m, thresholds, nil
+}
+
+func guessExportedMetricType(values map[string]float64) metrics.MetricType {
+	has := func(key string) bool {
+		_, ok := values[key]
+		return ok
+	}
+
+	switch {
+	case has("passes") || has("fails"):
+		return metrics.Rate
+	case has("avg") || has("med"):
+		return metrics.Trend
+	case has("count"):
+		return metrics.Counter
+	default:
+		return metrics.Gauge
+	}
+}
+
+// exportedGroupToSummary converts an exported group and its sub-groups to a
+// summary group, with only the checks for the sub-groups. The total metrics
+// are only set for the root group.
+func exportedGroupToSummary(g exportedGroup, totalMetrics summary.Metrics) summary.Group {
+	group := summary.NewGroup()
+	group.Metrics = totalMetrics
+
+	if len(g.Checks) > 0 {
+		group.Checks = summary.NewChecks()
+		var passes, fails int64
+		for _, name := range sortedKeys(g.Checks) {
+			c := g.Checks[name]
+			group.Checks.OrderedChecks = append(group.Checks.OrderedChecks,
+				&summary.Check{Name: c.Name, Passes: c.Passes, Fails: c.Fails})
+			passes += c.Passes
+			fails += c.Fails
+		}
+
+		total := float64(passes + fails)
+		group.Checks.Metrics.Total.Values["count"] = total
+		if total > 0 {
+			group.Checks.Metrics.Success.Values["rate"] = float64(passes) / total
+			group.Checks.Metrics.Fail.Values["rate"] = float64(fails) / total
+		}
+	}
+
+	for _, name := range sortedKeys(g.Groups) {
+		group.Groups[name] = exportedGroupToSummary(g.Groups[name], summary.NewMetrics())
+		group.GroupsOrder = append(group.GroupsOrder, name)
+	}
+
+	return group
+}
@@ -0,0 +1,326 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/errext/exitcodes"
+	"go.k6.io/k6/internal/cmd/tests"
+	"go.k6.io/k6/internal/lib/summary"
+	"go.k6.io/k6/lib/fsext"
+)
+
+func newCompareTestSummary(duration, failed, checks, requests float64, thresholdOk bool) *summary.Summary {
+	s := summary.New()
+
+	reqDuration := summary.Metric{
+		MetricInfo: summary.MetricInfo{Name: "http_req_duration", Type: "trend", Contains: "time"},
+		Values:     map[string]float64{"avg": duration, "p(95)": duration * 2},
+	}
+	s.Metrics.HTTP["http_req_duration"] = reqDuration
+	s.Metrics.HTTP["http_req_failed"] = summary.Metric{
+		MetricInfo: summary.MetricInfo{Name: "http_req_failed", Type: "rate", Contains: "default"},
+		Values:     map[string]float64{"rate": failed},
+	}
+	s.Metrics.HTTP["http_reqs"] = summary.Metric{
+		MetricInfo: summary.MetricInfo{Name: "http_reqs", Type: "counter", Contains: "default"},
+		Values:     map[string]float64{"count": requests},
+	}
+	s.Thresholds["http_req_duration"] = summary.MetricThresholds{
+		Metric:     reqDuration,
+		Thresholds: []summary.Threshold{{Source: "p(95)<300", Ok: thresholdOk}},
+	}
+
+	group := summary.NewGroup()
+	group.Checks = summary.NewChecks()
+	group.Checks.Metrics.Success.Values["rate"] = checks
+	group.Metrics.HTTP["http_req_duration"] = reqDuration
+	s.Groups["login"] = group
+	s.GroupsOrder = []string{"login"}
+
+	scenario := summary.NewGroup()
+	scenario.Metrics.HTTP["http_req_duration"] = reqDuration
+	s.Scenarios["browse"] = scenario
+
+	return s
+}
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		baseline *summary.Summary
+		current  *summary.Summary
+		args     []string
+		exitCode exitcodes.ExitCode
+		contains []string
+	}{
+		"no changes": {
+			current:  newCompareTestSummary(100, 0.01, 1, 100, true),
+			contains: []string{"█ TOTAL RESULTS", "█ GROUP: ::login", "█ SCENARIO: browse", "no regressions"},
+		},
+		"within the tolerance": {
+			current: newCompareTestSummary(105, 0.0105, 0.95, 100, true),
+		},
+		"counters aren't regressions": {
+			current:  newCompareTestSummary(100, 0.01, 1, 10, true),
+			contains: []string{"-90.00%"},
+		},
+		"improvements": {
+			current: newCompareTestSummary(50, 0, 1, 100, true),
+		},
+		"slower requests": {
+			current:  newCompareTestSummary(150, 0.01, 1, 100, false),
+			exitCode: exitcodes.PerformanceRegression,
+			contains: []string{
+				"+50.00%  regression",
+				"'p(95)<300'  passed -> failed  regression",
+				// 2 trend values in total, group and scenario and 1 threshold
+				"7 regression(s) above the tolerance",
+			},
+		},
+		"slower requests within a higher tolerance": {
+			current: newCompareTestSummary(150, 0.01, 1, 100, true),
+			args:    []string{"--tolerance=60"},
+		},
+		"slower requests within the metric tolerance": {
+			current: newCompareTestSummary(150, 0.01, 1, 100, true),
+			args:    []string{"--metric-tolerance=http_req_duration=60%"},
+		},
+		"more failures": {
+			current:  newCompareTestSummary(100, 0.02, 1, 100, true),
+			exitCode: exitcodes.PerformanceRegression,
+			contains: []string{"+100.00%  regression", "1 regression(s)"},
+		},
+		"less successful checks": {
+			current:  newCompareTestSummary(100, 0.01, 0.5, 100, true),
+			exitCode: exitcodes.PerformanceRegression,
+			contains: []string{"-50.00%  regression", "1 regression(s)"},
+		},
+		"failures from a zero baseline within the absolute tolerance": {
+			current:  newCompareTestSummary(100, 0.0001, 1, 100, true),
+			baseline: newCompareTestSummary(100, 0, 1, 100, true),
+			contains: []string{"http_req_failed    rate   0         0.0001   +0.0001", "no regressions"},
+		},
+		"failures from a zero baseline above the absolute tolerance": {
+			current:  newCompareTestSummary(100, 0.05, 1, 100, true),
+			baseline: newCompareTestSummary(100, 0, 1, 100, true),
+			exitCode: exitcodes.PerformanceRegression,
+			contains: []string{"+0.05", "1 regression(s)"},
+		},
+		"failures from a zero baseline with a lower absolute tolerance": {
+			current:  newCompareTestSummary(100, 0.0001, 1, 100, true),
+			baseline: newCompareTestSummary(100, 0, 1, 100, true),
+			args:     []string{"--absolute-tolerance=0"},
+			exitCode: exitcodes.PerformanceRegression,
+			contains: []string{"1 regression(s)"},
+		},
+		"invalid tolerance": {
+			current:  newCompareTestSummary(100, 0.01, 1, 100, true),
+			args:     []string{"--metric-tolerance=http_req_duration=fast"},
+			exitCode: exitcodes.InvalidConfig,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			baseline := tc.baseline
+			if baseline == nil {
+				baseline = newCompareTestSummary(100, 0.01, 1, 100, true)
+			}
+
+			ts := tests.NewGlobalTestState(t)
+			for file, s := range map[string]*summary.Summary{"baseline.json": baseline, "current.json": tc.current} {
+				data, err := json.Marshal(s)
+				require.NoError(t, err)
+				require.NoError(t, fsext.WriteFile(ts.FS, file, data, 0o644))
+			}
+
+			ts.CmdArgs = append(append([]string{"k6", "compare"}, tc.args...), "baseline.json", "current.json")
+			ts.ExpectedExitCode = int(tc.exitCode)
+			ExecuteWithGlobalState(ts.GlobalState)
+
+			stdout := ts.Stdout.String()
+			t.Log(stdout)
+			for _, s := range tc.contains {
+				assert.Contains(t, stdout, s)
+			}
+		})
+	}
+}
+
+func TestCompareInvalidSummaryData(t *testing.T) {
+	t.Parallel()
+
+	ts := tests.NewGlobalTestState(t)
+	legacyExport := `{"root_group": {"name": "", "groups": [], "checks": []}, "metrics": {}}`
+	require.NoError(t, fsext.WriteFile(ts.FS, "export.json", []byte(legacyExport), 0o644))
+
+	ts.CmdArgs = []string{"k6", "compare", "export.json", "export.json"}
+	ts.ExpectedExitCode = -1
+	ExecuteWithGlobalState(ts.GlobalState)
+
+	assert.Contains(t, ts.Stderr.String(), `could not parse the summary data in \"export.json\"`)
+}
+
+func TestValueChange(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		baseline, current, change float64
+		absolute                  bool
+	}{
+		{baseline: 0, current: 0, change: 0},
+		{baseline: 100, current: 150, change: 50},
+		{baseline: 100, current: 50, change: -50},
+		{baseline: -10, current: 0, change: 100},
+		{baseline: 0, current: 0.25, change: 0.25, absolute: true},
+		{baseline: 0, current: -2, change: -2, absolute: true},
+	} {
+		change, absolute := valueChange(tc.baseline, tc.current)
+		assert.Equal(t, tc.change, change, "%g -> %g", tc.baseline, tc.current)
+		assert.Equal(t, tc.absolute, absolute, "%g -> %g", tc.baseline, tc.current)
+	}
+}
+
+func TestCompareSummaryExport(t *testing.T) {
+	t.Parallel()
+
+	export := func(duration, failed float64, thresholdFailed bool, loginPasses int) string {
+		return fmt.Sprintf(`{
+			"root_group": {
+				"name": "", "path": "", "id": "d41d8cd98f00b204e9800998ecf8427e",
+				"groups": {
+					"login": {
+						"name": "login", "path": "::login", "id": "b4c6b3e5e8a6f7f4b7fa3a0c6b0c5a2c",
+						"groups": {},
+						"checks": {
+							"status is 200": {"name": "status is 200", "path": "::login::status is 200", "id": "1", "passes": %d, "fails": %d}
+						}
+					}
+				},
+				"checks": {}
+			},
+			"metrics": {
+				"http_req_duration": {"avg": %g, "min": 1, "med": %g, "max": 500, "p(90)": 200, "p(95)": 250,
+					"thresholds": {"p(95)<300": %t}},
+				"http_req_failed": {"passes": 1, "fails": 99, "value": %g},
+				"http_reqs": {"count": 100, "rate": 10},
+				"vus": {"value": 1, "min": 1, "max": 10},
+				"my_errors": {"passes": 0, "fails": 100, "value": 0},
+				"my_latency": {"avg": 0, "min": 0, "med": 0, "max": 0, "p(90)": 0, "p(95)": 0,
+					"thresholds": {"avg<100": false}}
+			}
+		}`, loginPasses, 10-loginPasses, duration, duration, thresholdFailed, failed)
+	}
+
+	testCases := map[string]struct {
+		current  string
+		args     []string
+		exitCode exitcodes.ExitCode
+		contains []string
+		stderr   string
+	}{
+		"no changes": {
+			current:  export(100, 0.01, false, 10),
+			args:     []string{"--metric-direction=my_errors=higher-is-worse"},
+			contains: []string{"█ TOTAL RESULTS", "http_req_duration  avg     100ms     100ms    0%", "█ GROUP: ::login", "no regressions"},
+		},
+		"slower requests and failing checks": {
+			current:  export(150, 0.01, true, 5),
+			args:     []string{"--metric-direction=my_errors=higher-is-worse"},
+			exitCode: exitcodes.PerformanceRegression,
+			contains: []string{
+				"http_req_duration  avg     100ms     150ms    +50.00%  regression",
+				"'p(95)<300'  passed -> failed  regression",
+				"checks_succeeded  rate   1         0.5      -50.00%  regression",
+				// avg and med, the threshold and both check rates of the group
+				"5 regression(s) above the tolerance",
+			},
+		},
+		"unknown direction": {
+			current:  export(100, 0.01, false, 10),
+			exitCode: exitcodes.InvalidConfig,
+			stderr:   "it's unknown whether the higher or the lower values of the metrics my_errors are worse",
+		},
+		"not compared metric": {
+			current:  export(100, 0.01, false, 10),
+			args:     []string{"--metric-direction=my_errors=none"},
+			contains: []string{"no regressions"},
+		},
+		"invalid direction": {
+			current:  export(100, 0.01, false, 10),
+			args:     []string{"--metric-direction=my_errors=up"},
+			exitCode: exitcodes.InvalidConfig,
+			stderr:   `the direction for metric \"my_errors\" should be higher-is-worse, lower-is-worse or none`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ts := tests.NewGlobalTestState(t)
+			require.NoError(t, fsext.WriteFile(ts.FS, "baseline.json", []byte(export(100, 0.01, false, 10)), 0o644))
+			require.NoError(t, fsext.WriteFile(ts.FS, "current.json", []byte(tc.current), 0o644))
+
+			ts.CmdArgs = append(append([]string{"k6", "compare"}, tc.args...), "baseline.json", "current.json")
+			ts.ExpectedExitCode = int(tc.exitCode)
+			ExecuteWithGlobalState(ts.GlobalState)
+
+			stdout := ts.Stdout.String()
+			t.Log(stdout)
+			for _, s := range tc.contains {
+				assert.Contains(t, stdout, s)
+			}
+			assert.Contains(t, ts.Stderr.String(), tc.stderr)
+		})
+	}
+}
+
+func TestCompareDirection(t *testing.T) {
+	t.Parallel()
+
+	thresholds := summary.Thresholds{
+		"my_latency":   {Thresholds: []summary.Threshold{{Source: "p(95)<200"}, {Source: "avg<=100"}}},
+		"my_successes": {Thresholds: []summary.Threshold{{Source: "rate>0.99"}}},
+		"my_range":     {Thresholds: []summary.Threshold{{Source: "value>1"}, {Source: "value<10"}}},
+		"my_exact":     {Thresholds: []summary.Threshold{{Source: "count==0"}}},
+		"http_reqs":    {Thresholds: []summary.Threshold{{Source: "count>100"}}},
+	}
+	c := &comparison{directions: thresholdDirections(
+		map[string]regressionDirection{"my_successes": directionNone, "http_req_duration{name:login}": directionNone},
+		thresholds,
+	)}
+
+	for _, tc := range []struct {
+		metric    summary.MetricInfo
+		stat      string
+		direction regressionDirection
+		known     bool
+	}{
+		{summary.MetricInfo{Name: "http_req_duration", Type: "trend"}, "avg", directionHigherIsWorse, true},
+		{summary.MetricInfo{Name: "http_req_duration{status:200}", Type: "trend"}, "avg", directionHigherIsWorse, true},
+		{summary.MetricInfo{Name: "http_req_duration{name:login}", Type: "trend"}, "avg", directionNone, true},
+		{summary.MetricInfo{Name: "http_req_failed", Type: "rate"}, "rate", directionHigherIsWorse, true},
+		{summary.MetricInfo{Name: "http_req_failed", Type: "rate"}, "passes", directionNone, true},
+		{summary.MetricInfo{Name: "checks_succeeded", Type: "rate"}, "rate", directionLowerIsWorse, true},
+		{summary.MetricInfo{Name: "http_reqs", Type: "counter"}, "count", directionLowerIsWorse, true},
+		{summary.MetricInfo{Name: "iterations", Type: "counter"}, "count", directionNone, true},
+		{summary.MetricInfo{Name: "my_latency", Type: "trend"}, "p(95)", directionHigherIsWorse, true},
+		{summary.MetricInfo{Name: "my_successes", Type: "rate"}, "rate", directionNone, true},
+		{summary.MetricInfo{Name: "my_range", Type: "gauge"}, "value", directionNone, false},
+		{summary.MetricInfo{Name: "my_exact", Type: "counter"}, "count", directionNone, false},
+		{summary.MetricInfo{Name: "my_failures", Type: "rate"}, "rate", directionNone, false},
+	} {
+		direction, known := c.direction(tc.metric, tc.stat)
+		assert.Equal(t, tc.direction, direction, tc.metric.Name)
+		assert.Equal(t, tc.known, known, tc.metric.Name)
+	}
+}
@@ -199,7 +199,10 @@ func readEnvConfig(envMap map[string]string) (Config, error) {
 		v, ok := envMap[key]
 		return v, ok
 	})
-	return conf, err
+	if err != nil {
+		return conf, err
+	}
+	return conf, nil
 }
 
 // loadConfigFile wraps the ordinary readDiskConfig operation.
@@ -257,6 +260,15 @@ func getConsolidatedConfig(gs *state.GlobalState, cliConf Config, runnerOpts lib
 		return Config{}, errext.WithExitCodeIfNone(err, exitcodes.InvalidConfig)
 	}
 
+	if cliConf.FSAllowWrite, err = absoluteDirs(gs.Getwd, cliConf.FSAllowWrite); err != nil {
+		err = fmt.Errorf("invalid --fs-allow-write directory: %w", err)
+		return Config{}, errext.WithExitCodeIfNone(err, exitcodes.InvalidConfig)
+	}
+	if envConf.FSAllowWrite, err = absoluteDirs(gs.Getwd, envConf.FSAllowWrite); err != nil {
+		err = fmt.Errorf("invalid K6_FS_ALLOW_WRITE directory: %w", err)
+		return Config{}, errext.WithExitCodeIfNone(err, exitcodes.InvalidConfig)
+	}
+
 	conf := cliConf.Apply(fileConf)
 
 	warnOnShortHandOverride(conf.Options, runnerOpts, "script", gs.Logger)
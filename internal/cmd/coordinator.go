@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"go.k6.io/k6/cmd/state"
+	"go.k6.io/k6/errext"
+	"go.k6.io/k6/errext/exitcodes"
+	"go.k6.io/k6/internal/execution/distributed"
+	"go.k6.io/k6/lib/fsext"
+)
+
+// distributedTokenEnvVar is the environment variable with the token of the
+// coordinator, used when it's not set with the --token flag.
+const distributedTokenEnvVar = "K6_DISTRIBUTED_TOKEN"
+
+// cmdCoordinator handles the `k6 coordinator` sub-command
+type cmdCoordinator struct {
+	gs *state.GlobalState
+
+	coordinatorAddress string
+	instanceCount      int
+	agentTimeout       time.Duration
+	regTimeout         time.Duration
+	token              string
+	tlsCert            string
+	tlsKey             string
+}
+
+func (c *cmdCoordinator) run(cmd *cobra.Command, args []string) (err error) {
+	if c.token == "" {
+		c.token = c.gs.Env[distributedTokenEnvVar]
+	}
+	tlsConfig, err := c.tlsConfig()
+	if err != nil {
+		return errext.WithExitCodeIfNone(err, exitcodes.InvalidConfig)
+	}
+	if c.token == "" && !isLoopbackAddress(c.coordinatorAddress) {
+		return errext.WithExitCodeIfNone(fmt.Errorf(
+			"a token is required when the coordinator listens on %s, which isn't a localhost address, "+
+				"since it hands out the test and the setup() data, set it with --token or %s",
+			c.coordinatorAddress, distributedTokenEnvVar,
+		), exitcodes.InvalidConfig)
+	}
+
+	test, err := loadAndConfigureLocalTest(c.gs, cmd, args, getPartialConfig)
+	if err != nil {
+		return err
+	}
+
+	// Similar to `k6 archive`, we only set the consolidated options back to
+	// the runner, since every agent will derive its own config with the
+	// execution segment the coordinator assigned to it.
+	testRunState, err := test.buildTestRunState(test.consolidatedConfig.Options)
+	if err != nil {
+		return err
+	}
+
+	coordinator, err := distributed.NewCoordinatorServer(
+		c.instanceCount, testRunState.Runner.MakeArchive(), c.agentTimeout, c.regTimeout, c.token, c.gs.Logger,
+	)
+	if err != nil {
+		return errext.WithExitCodeIfNone(err, exitcodes.InvalidConfig)
+	}
+
+	listener, err := net.Listen("tcp", c.coordinatorAddress)
+	if err != nil {
+		return fmt.Errorf("could not start the coordinator server on %s: %w", c.coordinatorAddress, err)
+	}
+	srv := &http.Server{Handler: coordinator, ReadHeaderTimeout: 10 * time.Second, TLSConfig: tlsConfig}
+	scheme := "http"
+	if tlsConfig != nil {
+		scheme = "https"
+		listener = tls.NewListener(listener, tlsConfig)
+	} else if !isLoopbackAddress(c.coordinatorAddress) {
+		c.gs.Logger.Warn("The coordinator isn't served over HTTPS, so the test, the setup() data and " +
+			"the token are sent unencrypted, use --tls-cert and --tls-key to serve it over HTTPS")
+	}
+	go func() {
+		if serr := srv.Serve(listener); serr != nil && !errors.Is(serr, http.ErrServerClosed) {
+			c.gs.Logger.WithError(serr).Error("Error from the coordinator server")
+		}
+	}()
+	defer func() {
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.WithoutCancel(c.gs.Ctx), time.Second)
+		defer shutdownCancel()
+		if serr := srv.Shutdown(shutdownCtx); serr != nil {
+			c.gs.Logger.WithError(serr).Debug("The coordinator server did not shut down correctly")
+		}
+	}()
+
+	c.gs.Logger.Infof("Waiting for %d agent instances to connect to %s://%s...",
+		c.instanceCount, scheme, listener.Addr().String())
+
+	if err = coordinator.Wait(c.gs.Ctx); err != nil {
+		return err
+	}
+	c.gs.Logger.Info("All agent instances finished successfully!")
+	return nil
+}
+
+// tlsConfig returns the TLS config of the coordinator server, or nil if it
+// isn't served over HTTPS.
+func (c *cmdCoordinator) tlsConfig() (*tls.Config, error) {
+	if c.tlsCert == "" && c.tlsKey == "" {
+		return nil, nil //nolint:nilnil
+	}
+	if c.tlsCert == "" || c.tlsKey == "" {
+		return nil, errors.New("both --tls-cert and --tls-key have to be set for serving the coordinator over HTTPS")
+	}
+	certPEM, err := fsext.ReadFile(c.gs.FS, c.tlsCert)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the TLS certificate: %w", err)
+	}
+	keyPEM, err := fsext.ReadFile(c.gs.FS, c.tlsKey)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the TLS key: %w", err)
+	}
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TLS certificate or key: %w", err)
+	}
+	return &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}, nil
+}
+
+// isLoopbackAddress returns whether the host of the address is localhost or a
+// loopback IP. The addresses without a host listen on all of the interfaces.
+func isLoopbackAddress(address string) bool {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func (c *cmdCoordinator) flagSet() *pflag.FlagSet {
+	flags := pflag.NewFlagSet("", pflag.ContinueOnError)
+	flags.SortFlags = false
+	flags.AddFlagSet(optionFlagSet())
+	flags.AddFlagSet(runtimeOptionFlagSet(false))
+	flags.StringVar(
+		&c.coordinatorAddress, "coordinator-address", c.coordinatorAddress,
+		"address for the coordinator server that the agent instances will connect to",
+	)
+	flags.IntVar(&c.instanceCount, "instance-count", c.instanceCount,
+		"number of agent instances that will execute the test")
+	flags.DurationVar(&c.agentTimeout, "agent-timeout", c.agentTimeout,
+		"abort the test run if an agent instance doesn't send any heartbeats for this long")
+	flags.DurationVar(&c.regTimeout, "registration-timeout", c.regTimeout,
+		"abort the test run if not all of the agent instances have registered in this long")
+	flags.StringVar(&c.token, "token", "",
+		"token that the agent instances have to send, required for non-localhost addresses, "+
+			"can also be set with the "+distributedTokenEnvVar+" environment variable")
+	flags.StringVar(&c.tlsCert, "tls-cert", "", "path to a PEM certificate for serving the coordinator over HTTPS")
+	flags.StringVar(&c.tlsKey, "tls-key", "", "path to the PEM private key of the --tls-cert certificate")
+	return flags
+}
+
+func getCmdCoordinator(gs *state.GlobalState) *cobra.Command {
+	c := &cmdCoordinator{
+		gs:                 gs,
+		coordinatorAddress: "localhost:6566",
+		instanceCount:      1,
+		agentTimeout:       distributed.DefaultAgentTimeout,
+		regTimeout:         distributed.DefaultRegistrationTimeout,
+	}
+
+	exampleText := getExampleText(gs, `
+  # Split a test between 2 agent instances that connect to the default address.
+  {{.}} coordinator --instance-count 2 script.js
+
+  # Then start each agent instance on the same machine.
+  {{.}} agent localhost:6566
+
+  # Accept agent instances from other machines, which have to send the token.
+  K6_DISTRIBUTED_TOKEN=s3cr3t {{.}} coordinator --coordinator-address 0.0.0.0:6566 \
+    --tls-cert cert.pem --tls-key key.pem --instance-count 2 script.js
+  K6_DISTRIBUTED_TOKEN=s3cr3t {{.}} agent https://10.0.0.1:6566`[1:])
+
+	coordinatorCmd := &cobra.Command{
+		Use:   "coordinator",
+		Short: "Coordinate a distributed test",
+		Long: `Coordinate a distributed test.
+
+The coordinator waits for the specified number of "k6 agent" instances to connect, sends
+them the test archive and an evenly split execution segment each, and then synchronizes
+their execution, so that setup() runs only once and all instances start and stop together.
+If an agent instance stops sending heartbeats for longer than --agent-timeout, the test
+run is aborted for all of the other instances, and so it is if not all of the instances
+have registered within --registration-timeout.
+
+Since the coordinator hands out the test and the setup() data, which can contain
+credentials, the agent instances have to send a --token when it listens on a
+non-localhost address, and it should be served over HTTPS, with --tls-cert and --tls-key.`,
+		Example: exampleText,
+		Args:    exactArgsWithMsg(1, "arg should either be \"-\", if reading script from stdin, or a path to a script file"),
+		RunE:    c.run,
+	}
+
+	coordinatorCmd.Flags().SortFlags = false
+	coordinatorCmd.Flags().AddFlagSet(c.flagSet())
+
+	return coordinatorCmd
+}
@@ -1,14 +1,18 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"go.k6.io/k6/cmd/state"
 	"go.k6.io/k6/internal/cmd/templates"
+	"go.k6.io/k6/internal/converter/har"
+	"go.k6.io/k6/internal/converter/openapi"
 	"go.k6.io/k6/lib/fsext"
 )
 
@@ -19,6 +23,13 @@ type newScriptCmd struct {
 	overwriteFiles bool
 	templateType   string
 	projectID      string
+
+	fromHAR       string
+	harOnly       []string
+	harSkip       []string
+	harSkipStatic bool
+
+	fromOpenAPI string
 }
 
 func (c *newScriptCmd) flagSet() *pflag.FlagSet {
@@ -27,10 +38,19 @@ func (c *newScriptCmd) flagSet() *pflag.FlagSet {
 	flags.BoolVarP(&c.overwriteFiles, "force", "f", false, "overwrite existing files")
 	flags.StringVar(&c.templateType, "template", "minimal", "template type (choices: minimal, protocol, browser) or relative/absolute path to a custom template file") //nolint:lll
 	flags.StringVar(&c.projectID, "project-id", "", "specify the Grafana Cloud project ID for the test")
+	flags.StringVar(&c.fromHAR, "from-har", "", "generate the script from a HAR recording file instead of a template")
+	flags.StringSliceVar(&c.harOnly, "har-only", nil,
+		"only include the requests of the HAR recording to these domains and their subdomains")
+	flags.StringSliceVar(&c.harSkip, "har-skip", nil,
+		"skip the requests of the HAR recording to these domains and their subdomains")
+	flags.BoolVar(&c.harSkipStatic, "har-skip-static", false,
+		"skip the requests of the HAR recording for static assets, like images, stylesheets, scripts and fonts")
+	flags.StringVar(&c.fromOpenAPI, "from-openapi", "",
+		"generate the script from an OpenAPI 3 document instead of a template, with a request for each operation")
 	return flags
 }
 
-func (c *newScriptCmd) run(_ *cobra.Command, args []string) (err error) {
+func (c *newScriptCmd) run(cmd *cobra.Command, args []string) (err error) {
 	target := defaultNewScriptName
 	if len(args) > 0 {
 		target = args[0]
@@ -44,15 +64,68 @@ func (c *newScriptCmd) run(_ *cobra.Command, args []string) (err error) {
 		return fmt.Errorf("%s already exists. Use the `--force` flag to overwrite it", target)
 	}
 
+	var content, source string
+	switch {
+	case c.fromHAR != "" && c.fromOpenAPI != "":
+		return errors.New("the --from-har and --from-openapi flags can't be used together")
+	case c.fromHAR != "":
+		if cmd.Flags().Changed("template") {
+			return errors.New("the --template and --from-har flags can't be used together")
+		}
+		content, err = c.convertHAR(target)
+		source = "from " + c.fromHAR
+	case c.fromOpenAPI != "":
+		if cmd.Flags().Changed("template") {
+			return errors.New("the --template and --from-openapi flags can't be used together")
+		}
+		content, err = c.convertOpenAPI(target)
+		source = "from " + c.fromOpenAPI
+	default:
+		content, err = c.renderTemplate(target)
+		source = c.templateType + " template"
+	}
+	if err != nil {
+		return err
+	}
+
+	// Only create the file after the script is generated successfully
+	fd, err := c.gs.FS.Create(target)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if cerr := fd.Close(); cerr != nil {
+			if _, werr := fmt.Fprintf(c.gs.Stderr, "error closing file: %v\n", cerr); werr != nil {
+				err = fmt.Errorf("error writing error message to stderr: %w", werr)
+			} else {
+				err = cerr
+			}
+		}
+	}()
+
+	// Write the rendered content to the file
+	if _, err := io.WriteString(fd, content); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(c.gs.Stdout, "New script created: %s (%s).\n", target, source); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *newScriptCmd) renderTemplate(target string) (string, error) {
 	// Initialize template manager and validate template before creating any files
 	tm, err := templates.NewTemplateManager(c.gs.FS)
 	if err != nil {
-		return fmt.Errorf("error initializing template manager: %w", err)
+		return "", fmt.Errorf("error initializing template manager: %w", err)
 	}
 
 	tmpl, err := tm.GetTemplate(c.templateType)
 	if err != nil {
-		return fmt.Errorf("error retrieving template: %w", err)
+		return "", fmt.Errorf("error retrieving template: %w", err)
 	}
 
 	// Prepare template arguments
@@ -64,35 +137,58 @@ func (c *newScriptCmd) run(_ *cobra.Command, args []string) (err error) {
 	// First render the template to a buffer to validate it
 	var buf strings.Builder
 	if err := templates.ExecuteTemplate(&buf, tmpl, argsStruct); err != nil {
-		return fmt.Errorf("failed to execute template %s: %w", c.templateType, err)
+		return "", fmt.Errorf("failed to execute template %s: %w", c.templateType, err)
 	}
 
-	// Only create the file after template rendering succeeds
-	fd, err := c.gs.FS.Create(target)
+	return buf.String(), nil
+}
+
+func (c *newScriptCmd) convertHAR(target string) (string, error) {
+	data, err := fsext.ReadFile(c.gs.FS, c.fromHAR)
 	if err != nil {
-		return err
+		return "", fmt.Errorf("error reading the HAR recording: %w", err)
 	}
 
-	defer func() {
-		if cerr := fd.Close(); cerr != nil {
-			if _, werr := fmt.Fprintf(c.gs.Stderr, "error closing file: %v\n", cerr); werr != nil {
-				err = fmt.Errorf("error writing error message to stderr: %w", werr)
-			} else {
-				err = cerr
-			}
-		}
-	}()
+	recording, err := har.Decode(data)
+	if err != nil {
+		return "", err
+	}
 
-	// Write the rendered content to the file
-	if _, err := io.WriteString(fd, buf.String()); err != nil {
-		return err
+	script, err := har.Convert(recording, har.Options{
+		OnlyDomains:  c.harOnly,
+		SkipDomains:  c.harSkip,
+		SkipStatic:   c.harSkipStatic,
+		MinThinkTime: har.DefaultMinThinkTime,
+		ScriptName:   filepath.Base(target),
+		ProjectID:    c.projectID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error converting the HAR recording: %w", err)
 	}
 
-	if _, err := fmt.Fprintf(c.gs.Stdout, "New script created: %s (%s template).\n", target, c.templateType); err != nil {
-		return err
+	return script, nil
+}
+
+func (c *newScriptCmd) convertOpenAPI(target string) (string, error) {
+	data, err := fsext.ReadFile(c.gs.FS, c.fromOpenAPI)
+	if err != nil {
+		return "", fmt.Errorf("error reading the OpenAPI document: %w", err)
 	}
 
-	return nil
+	doc, err := openapi.Decode(data)
+	if err != nil {
+		return "", err
+	}
+
+	script, err := openapi.Convert(doc, openapi.Options{
+		ScriptName: filepath.Base(target),
+		ProjectID:  c.projectID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error converting the OpenAPI document: %w", err)
+	}
+
+	return script, nil
 }
 
 func getCmdNewScript(gs *state.GlobalState) *cobra.Command {
@@ -112,12 +208,24 @@ func getCmdNewScript(gs *state.GlobalState) *cobra.Command {
     $ {{.}} new --template protocol
 
     # Create a cloud-ready script with a specific project ID
-    $ {{.}} new --project-id 12315`[1:])
+    $ {{.}} new --project-id 12315
+
+    # Create a script from a HAR recording of the browser, without the static assets
+    $ {{.}} new --from-har recording.har --har-skip-static recording.js
+
+    # Create a smoke test of an API, which sends a request to each operation of its OpenAPI document
+    $ {{.}} new --from-openapi openapi.yaml api.js`[1:])
 
 	initCmd := &cobra.Command{
 		Use:   "new [file]",
 		Short: "Create and initialize a new k6 script",
-		Long: `Create and initialize a new k6 script using one of the predefined templates.
+		Long: `Create and initialize a new k6 script using one of the predefined templates,
+from a HAR recording of a user journey in the browser, or from an OpenAPI 3
+document of an API.
+
+The scripts generated from OpenAPI documents read the base URL of the API from
+the BASE_URL environment variable, and the credentials from API_TOKEN, API_KEY,
+API_USERNAME and API_PASSWORD, depending on the security schemes.
 
 By default, the script will be named script.js unless a different name is specified.`,
 		Example: exampleText,
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -221,3 +222,135 @@ func TestNewScriptCmd_LocalTemplate_SyntaxError(t *testing.T) {
 	require.NoError(t, err)
 	assert.False(t, exists, "script file should not exist")
 }
+
+func TestNewScriptCmd_FromHAR(t *testing.T) {
+	t.Parallel()
+
+	ts := tests.NewGlobalTestState(t)
+	harData, err := os.ReadFile(filepath.Join("testdata", "example.har")) //nolint:forbidigo
+	require.NoError(t, err)
+	require.NoError(t, fsext.WriteFile(ts.FS, "recording.har", harData, 0o644))
+
+	ts.CmdArgs = []string{
+		"k6", "new", "--from-har", "recording.har", "--har-skip", "some-other-host.example.com",
+		"--project-id", "1234", "recording.js",
+	}
+
+	newRootCommand(ts.GlobalState).execute()
+
+	assert.Contains(t, ts.Stdout.String(), "New script created: recording.js (from recording.har)")
+
+	data, err := fsext.ReadFile(ts.FS, "recording.js")
+	require.NoError(t, err)
+
+	jsData := string(data)
+	assert.Contains(t, jsData, "projectID: 1234,")
+	assert.Contains(t, jsData, `orderId = res.json("order_id");`)
+	assert.Contains(t, jsData, `http.get("https://some-host.example.com/checkout/v3/orders/" + orderId, {`)
+	assert.NotContains(t, jsData, "some-other-host.example.com")
+}
+
+func TestNewScriptCmd_FromHARWithTemplate(t *testing.T) {
+	t.Parallel()
+
+	ts := tests.NewGlobalTestState(t)
+	ts.CmdArgs = []string{"k6", "new", "--from-har", "recording.har", "--template", "browser"}
+	ts.ExpectedExitCode = -1
+
+	newRootCommand(ts.GlobalState).execute()
+
+	assert.Contains(t, ts.Stderr.String(), "the --template and --from-har flags can't be used together")
+}
+
+func TestNewScriptCmd_FromInvalidHAR(t *testing.T) {
+	t.Parallel()
+
+	ts := tests.NewGlobalTestState(t)
+	require.NoError(t, fsext.WriteFile(ts.FS, "recording.har", []byte(`{"log": {"entries": []}}`), 0o644))
+	ts.CmdArgs = []string{"k6", "new", "--from-har", "recording.har"}
+	ts.ExpectedExitCode = -1
+
+	newRootCommand(ts.GlobalState).execute()
+
+	assert.Contains(t, ts.Stderr.String(), "the HAR recording has no requests to convert")
+
+	exists, err := fsext.Exists(ts.FS, defaultNewScriptName)
+	require.NoError(t, err)
+	assert.False(t, exists, "script file should not exist")
+}
+
+func TestNewScriptCmd_FromOpenAPI(t *testing.T) {
+	t.Parallel()
+
+	ts := tests.NewGlobalTestState(t)
+	require.NoError(t, fsext.WriteFile(ts.FS, "openapi.yaml", []byte(`
+openapi: 3.0.0
+info:
+  title: Orders
+  version: "2"
+servers:
+  - url: https://api.example.com
+paths:
+  /orders/{orderId}:
+    get:
+      parameters:
+        - name: orderId
+          in: path
+          required: true
+          schema:
+            type: integer
+            minimum: 42
+      responses:
+        '200':
+          description: The order
+`), 0o644))
+	ts.CmdArgs = []string{"k6", "new", "--from-openapi", "openapi.yaml", "--project-id", "1234", "orders.js"}
+
+	newRootCommand(ts.GlobalState).execute()
+
+	assert.Contains(t, ts.Stdout.String(), "New script created: orders.js (from openapi.yaml)")
+
+	data, err := fsext.ReadFile(ts.FS, "orders.js")
+	require.NoError(t, err)
+
+	jsData := string(data)
+	assert.Contains(t, jsData, "projectID: 1234,")
+	assert.Contains(t, jsData, `const BASE_URL = __ENV.BASE_URL || "https://api.example.com";`)
+	assert.Contains(t, jsData, `res = http.get(BASE_URL + "/orders/42", {`)
+	assert.Contains(t, jsData, `tags: { name: "/orders/{orderId}" },`)
+	assert.Contains(t, jsData, `"GET /orders/{orderId} status is 200": (r) => r.status === 200,`)
+}
+
+func TestNewScriptCmd_FromOpenAPIWithOtherSources(t *testing.T) {
+	t.Parallel()
+
+	for expectedErr, args := range map[string][]string{
+		"the --template and --from-openapi flags can't be used together": {"--template", "browser"},
+		"the --from-har and --from-openapi flags can't be used together": {"--from-har", "recording.har"},
+	} {
+		ts := tests.NewGlobalTestState(t)
+		ts.CmdArgs = append([]string{"k6", "new", "--from-openapi", "openapi.yaml"}, args...)
+		ts.ExpectedExitCode = -1
+
+		newRootCommand(ts.GlobalState).execute()
+
+		assert.Contains(t, ts.Stderr.String(), expectedErr)
+	}
+}
+
+func TestNewScriptCmd_FromInvalidOpenAPI(t *testing.T) {
+	t.Parallel()
+
+	ts := tests.NewGlobalTestState(t)
+	require.NoError(t, fsext.WriteFile(ts.FS, "swagger.json", []byte(`{"swagger": "2.0", "paths": {}}`), 0o644))
+	ts.CmdArgs = []string{"k6", "new", "--from-openapi", "swagger.json"}
+	ts.ExpectedExitCode = -1
+
+	newRootCommand(ts.GlobalState).execute()
+
+	assert.Contains(t, ts.Stderr.String(), "only OpenAPI 3 documents are supported")
+
+	exists, err := fsext.Exists(ts.FS, defaultNewScriptName)
+	require.NoError(t, err)
+	assert.False(t, exists, "script file should not exist")
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/pflag"
@@ -43,6 +44,7 @@ func optionFlagSet() *pflag.FlagSet {
 	flags.Bool("insecure-skip-tls-verify", false, "skip verification of TLS certificates")
 	flags.Bool("no-connection-reuse", false, "disable keep-alive connections")
 	flags.Bool("no-vu-connection-reuse", false, "don't reuse connections between iterations")
+	flags.Bool("http3", false, "make HTTPS requests over HTTP/3 (QUIC)")
 	flags.Duration("min-iteration-duration", 0, "minimum amount of time k6 will take executing a single iteration")
 	flags.BoolP("throw", "w", false, "throw warnings (like failed http requests) as errors")
 	flags.StringSlice("blacklist-ip", nil, "blacklist an `ip range` from being called")
@@ -67,6 +69,8 @@ func optionFlagSet() *pflag.FlagSet {
 	flags.StringArray("tag", nil, "add a `tag` to be applied to all samples, as `[name]=[value]`")
 	flags.String("console-output", "", "redirects the console logging to the provided output file")
 	flags.Bool("discard-response-bodies", false, "Read but don't process or save HTTP response bodies")
+	flags.StringSlice("fs-allow-write", nil, "allow the k6/experimental/fs module to write files inside the `directory`, "+
+		"relative to the current working directory")
 	flags.String("local-ips", "", "Client IP Ranges and/or CIDRs from which each VU will be making requests, "+
 		"e.g. '192.168.220.1,192.168.0.10-192.168.0.25', 'fd:1::0/120', etc.")
 	flags.String("dns", types.DefaultDNSConfig().String(), "DNS resolver configuration. Possible ttl values are: 'inf' "+
@@ -95,6 +99,7 @@ func getOptions(flags *pflag.FlagSet) (lib.Options, error) {
 		InsecureSkipTLSVerify:   getNullBool(flags, "insecure-skip-tls-verify"),
 		NoConnectionReuse:       getNullBool(flags, "no-connection-reuse"),
 		NoVUConnectionReuse:     getNullBool(flags, "no-vu-connection-reuse"),
+		HTTP3:                   getNullBool(flags, "http3"),
 		MinIterationDuration:    getNullDuration(flags, "min-iteration-duration"),
 		Throw:                   getNullBool(flags, "throw"),
 		DiscardResponseBodies:   getNullBool(flags, "discard-response-bodies"),
@@ -177,6 +182,14 @@ func getOptions(flags *pflag.FlagSet) (lib.Options, error) {
 		}
 	}
 
+	if flags.Changed("fs-allow-write") {
+		fsAllowWrite, errFs := flags.GetStringSlice("fs-allow-write")
+		if errFs != nil {
+			return opts, errFs
+		}
+		opts.FSAllowWrite = fsAllowWrite
+	}
+
 	localIpsString, err := flags.GetString("local-ips")
 	if err != nil {
 		return opts, err
@@ -264,3 +277,27 @@ func parseTagNameValue(nv string) (string, string, error) {
 		return nv[:idx], nv[idx+1:], nil
 	}
 }
+
+// absoluteDirs resolves the directories relative to the working directory of the
+// process, like the other paths given on the command line, instead of relative to
+// the script, which is what the relative paths used in it are resolved against.
+func absoluteDirs(getwd func() (string, error), dirs []string) ([]string, error) {
+	if dirs == nil {
+		return nil, nil
+	}
+	result := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		if dir = strings.TrimSpace(dir); dir == "" {
+			continue
+		}
+		if !filepath.IsAbs(dir) {
+			pwd, err := getwd()
+			if err != nil {
+				return nil, err
+			}
+			dir = filepath.Join(pwd, dir)
+		}
+		result = append(result, filepath.Clean(dir))
+	}
+	return result, nil
+}
@@ -1,9 +1,14 @@
 package cmd
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/internal/cmd/tests"
+	"go.k6.io/k6/lib"
 )
 
 func TestParseTagKeyValue(t *testing.T) {
@@ -62,3 +67,23 @@ func TestParseTagKeyValue(t *testing.T) {
 		})
 	}
 }
+
+func TestFSAllowWriteRelativeToWorkingDirectory(t *testing.T) {
+	t.Parallel()
+
+	ts := tests.NewGlobalTestState(t)
+	ts.Env["K6_FS_ALLOW_WRITE"] = "logs"
+	absDir := filepath.Join(t.TempDir(), "out")
+
+	flags := optionFlagSet()
+	require.NoError(t, flags.Parse([]string{"--fs-allow-write", "results, ," + absDir}))
+	opts, err := getOptions(flags)
+	require.NoError(t, err)
+	conf, err := getConsolidatedConfig(ts.GlobalState, Config{Options: opts}, lib.Options{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(ts.Cwd, "results"), absDir}, conf.FSAllowWrite)
+
+	conf, err = getConsolidatedConfig(ts.GlobalState, Config{}, lib.Options{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(ts.Cwd, "logs")}, conf.FSAllowWrite)
+}
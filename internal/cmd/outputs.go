@@ -13,6 +13,7 @@ import (
 	"go.k6.io/k6/internal/output/influxdb"
 	"go.k6.io/k6/internal/output/json"
 	"go.k6.io/k6/internal/output/opentelemetry"
+	"go.k6.io/k6/internal/output/parquet"
 	"go.k6.io/k6/internal/output/prometheusrw/remotewrite"
 	"go.k6.io/k6/lib"
 	"go.k6.io/k6/output"
@@ -38,6 +39,7 @@ const (
 	builtinOutputStatsd
 	builtinOutputExperimentalOpentelemetry
 	builtinOutputSummary
+	builtinOutputParquet
 )
 
 // TODO: move this to an output sub-module after we get rid of the old collectors?
@@ -47,6 +49,7 @@ func getAllOutputConstructors() (map[string]output.Constructor, error) {
 		builtinOutputJSON.String():     json.New,
 		builtinOutputCloud.String():    cloud.New,
 		builtinOutputCSV.String():      csv.New,
+		builtinOutputParquet.String():  parquet.New,
 		builtinOutputInfluxdb.String(): influxdb.New,
 		builtinOutputKafka.String(): func(_ output.Params) (output.Output, error) {
 			return nil, errors.New("the kafka output was deprecated in k6 v0.32.0 and removed in k6 v0.34.0, " +
@@ -88,6 +91,18 @@ func getAllOutputConstructors() (map[string]output.Constructor, error) {
 	return result, nil
 }
 
+// webDashboardURL returns the address of the server of the web-dashboard
+// output, if it's one of the given outputs and it serves the dashboard.
+func webDashboardURL(outputs []output.Output) string {
+	for _, out := range outputs {
+		// the description of the output is its name and its address, if any
+		if addr, ok := strings.CutPrefix(out.Description(), dashboard.OutputName+" "); ok {
+			return addr
+		}
+	}
+	return ""
+}
+
 func getPossibleIDList(constrs map[string]output.Constructor) string {
 	res := make([]string, 0, len(constrs))
 	for k := range constrs {
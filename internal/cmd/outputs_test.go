@@ -3,7 +3,13 @@ package cmd
 import (
 	"testing"
 
+	"github.com/grafana/xk6-dashboard/dashboard"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/internal/lib/testutils"
+	"go.k6.io/k6/lib/fsext"
+	"go.k6.io/k6/output"
 )
 
 func TestBuiltinOutputString(t *testing.T) {
@@ -11,7 +17,27 @@ func TestBuiltinOutputString(t *testing.T) {
 	exp := []string{
 		"cloud", "csv", "datadog", "experimental-prometheus-rw",
 		"influxdb", "json", "kafka", "statsd", "experimental-opentelemetry",
-		"summary",
+		"summary", "parquet",
 	}
 	assert.Equal(t, exp, builtinOutputStrings())
 }
+
+func TestWebDashboardURL(t *testing.T) {
+	t.Parallel()
+
+	newDashboard := func(arg string) output.Output {
+		out, err := dashboard.New(output.Params{
+			OutputType:     dashboard.OutputName,
+			ConfigArgument: arg,
+			Logger:         testutils.NewLogger(t),
+			FS:             fsext.NewMemMapFs(),
+		})
+		require.NoError(t, err)
+		return out
+	}
+
+	assert.Empty(t, webDashboardURL(nil))
+	assert.Equal(t, "http://127.0.0.1:5666", webDashboardURL([]output.Output{newDashboard("port=5666")}))
+	assert.Equal(t, "http://10.0.0.1:5665", webDashboardURL([]output.Output{newDashboard("host=10.0.0.1")}))
+	assert.Empty(t, webDashboardURL([]output.Output{newDashboard("port=-1")}), "the dashboard isn't served")
+}
@@ -87,7 +87,7 @@ func newRootWithLauncher(gs *state.GlobalState, l *launcher) *rootCommand {
 	subCommands := []func(*state.GlobalState) *cobra.Command{
 		getCmdArchive, getCmdCloud, getCmdNewScript, getCmdInspect,
 		getCmdLogin, getCmdPause, getCmdResume, getCmdScale, getCmdRun,
-		getCmdStats, getCmdStatus, getCmdVersion,
+		getCmdStats, getCmdStatus, getCmdVersion, getCmdCoordinator, getCmdAgent, getCmdCompare,
 	}
 
 	for _, sc := range subCommands {
@@ -186,7 +186,8 @@ func rootCmdPersistentFlagSet(gs *state.GlobalState) *pflag.FlagSet {
 
 	// TODO(@mstoykov): likely needs work - no env variables and such. No config.json.
 	flags.StringArrayVar(&gs.Flags.SecretSource, "secret-source", gs.Flags.SecretSource,
-		"setting secret sources for k6 file[=./path.fileformat],")
+		"setting secret sources for k6, possible values are: "+
+			"'file=./path.fileformat', 'env[=prefix=K6_SECRET_]', 'vault=address=https://vault:8200,path=k6'")
 
 	flags.StringVar(&gs.Flags.LogOutput, "log-output", gs.Flags.LogOutput,
 		"change the output for k6 logs, possible values are: "+
@@ -343,10 +344,8 @@ func createSecretSources(gs *state.GlobalState) (map[string]secretsource.Source,
 
 	result := make(map[string]secretsource.Source)
 	for _, line := range gs.Flags.SecretSource {
-		t, config, ok := strings.Cut(line, "=")
-		if !ok {
-			return nil, fmt.Errorf("couldn't parse secret source configuration %q", line)
-		}
+		// the configuration is optional, e.g. for all the environment variables with `env`
+		t, config, _ := strings.Cut(line, "=")
 		secretSources := ext.Get(ext.SecretSourceExtension)
 		found, ok := secretSources[t]
 		if !ok {
@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -33,6 +35,7 @@ import (
 	"go.k6.io/k6/lib/fsext"
 	"go.k6.io/k6/metrics"
 	"go.k6.io/k6/output"
+	"go.k6.io/k6/secretsource"
 )
 
 // cmdRun handles the `k6 run` sub-command
@@ -138,6 +141,23 @@ func (c *cmdRun) run(cmd *cobra.Command, args []string) (err error) {
 	if err != nil {
 		return err
 	}
+	for _, executor := range execScheduler.GetExecutors() {
+		if fre, ok := executor.(lib.FileReadingExecutor); ok {
+			fre.SetFileSystem(test.fileSystems["file"], test.pwd)
+		}
+	}
+
+	// Some controllers, like the distributed one, can find out on their own
+	// that the test run has to be aborted, e.g. because another instance was lost.
+	if notifier, ok := controller.(execution.AbortNotifier); ok {
+		go func() {
+			select {
+			case abortErr := <-notifier.Aborted():
+				runAbort(abortErr)
+			case <-globalCtx.Done():
+			}
+		}()
+	}
 
 	backgroundProcesses := &sync.WaitGroup{}
 	defer backgroundProcesses.Wait()
@@ -178,6 +198,13 @@ func (c *cmdRun) run(cmd *cobra.Command, args []string) (err error) {
 	// of these are enabled: thresholds, end-of-test summary
 	shouldProcessMetrics := !testRunState.RuntimeOptions.NoSummary.Bool ||
 		!testRunState.RuntimeOptions.NoThresholds.Bool
+	// or if any of the executors adapts the load to the metric values
+	for _, executor := range execScheduler.GetExecutors() {
+		if moe, ok := executor.(lib.MetricsObservingExecutor); ok {
+			moe.SetMetricsObserver(metricsEngine)
+			shouldProcessMetrics = true
+		}
+	}
 	var metricsIngester *engine.OutputIngester
 	if shouldProcessMetrics {
 		err = metricsEngine.InitSubMetricsAndThresholds(conf.Options, testRunState.RuntimeOptions.NoThresholds.Bool)
@@ -222,11 +249,27 @@ func (c *cmdRun) run(cmd *cobra.Command, args []string) (err error) {
 		switch sm {
 		// TODO: Remove this code block once we stop supporting the legacy summary, and just leave the default.
 		case summary.ModeLegacy:
+			if testRunState.RuntimeOptions.SummaryJSON.String != "" {
+				logger.Warn("The summary data JSON file isn't supported with the legacy summary mode, it won't be written")
+			}
+			if testRunState.RuntimeOptions.SummaryHTML.String != "" {
+				logger.Warn("The HTML report isn't supported with the legacy summary mode, it won't be written")
+			}
+			if testRunState.RuntimeOptions.SummaryJUnit.String != "" {
+				logger.Warn("The JUnit XML report isn't supported with the legacy summary mode, it won't be written")
+			}
+			if testRunState.RuntimeOptions.SummaryTimeline.Valid {
+				logger.Warn("The summary timeline isn't supported with the legacy summary mode, it won't be collected")
+			}
+
 			// At the end of the test run
 			defer func() {
 				logger.Debug("Generating the end-of-test summary...")
 
 				summaryResult, hsErr := test.initRunner.HandleSummary(globalCtx, legacySummary(), nil)
+				if hsErr == nil {
+					hsErr = redactSummaryResult(summaryResult, c.gs.SecretsManager)
+				}
 				if hsErr == nil {
 					hsErr = handleSummaryResult(c.gs.FS, c.gs.Stdout, c.gs.Stderr, summaryResult)
 				}
@@ -238,11 +281,16 @@ func (c *cmdRun) run(cmd *cobra.Command, args []string) (err error) {
 			// Instantiates the summary output
 			summaryOutput, err := summaryoutput.New(output.Params{
 				RuntimeOptions: testRunState.RuntimeOptions,
+				ScriptOptions:  conf.Options,
 				Logger:         c.gs.Logger,
+				ExecutionPlan:  execScheduler.GetExecutionPlan(),
 			})
 			if err != nil {
 				logger.WithError(err).Error("failed to initialize the end-of-test summary output")
+				break
 			}
+			summaryOutput.SetExecutionState(executionState)
+			summaryOutput.SetExecutors(execScheduler.GetExecutors())
 			outputs = append(outputs, summaryOutput)
 
 			// At the end of the test run
@@ -261,14 +309,36 @@ func (c *cmdRun) run(cmd *cobra.Command, args []string) (err error) {
 				summary.EnableColors = !summary.NoColor && c.gs.Stdout.IsTTY
 
 				summaryResult, hsErr := test.initRunner.HandleSummary(globalCtx, legacySummary(), summary)
+				if hsErr != nil {
+					logger.WithError(hsErr).Error("failed to handle the end-of-test summary")
+					// The built-in files don't depend on handleSummary(), so they're still written
+					summaryResult = make(map[string]io.Reader)
+				}
+				hsErr = addSummaryJSON(summaryResult, testRunState.RuntimeOptions.SummaryJSON.String, summary)
+				if hsErr == nil {
+					hsErr = addSummaryReports(summaryResult, testRunState.RuntimeOptions, summary)
+				}
+				if hsErr == nil {
+					hsErr = redactSummaryResult(summaryResult, c.gs.SecretsManager)
+				}
 				if hsErr == nil {
 					hsErr = handleSummaryResult(c.gs.FS, c.gs.Stdout, c.gs.Stderr, summaryResult)
 				}
 				if hsErr != nil {
-					logger.WithError(hsErr).Error("failed to handle the end-of-test summary")
+					logger.WithError(hsErr).Error("failed to write the end-of-test summary")
 				}
 			}()
 		}
+	} else {
+		for _, file := range []struct{ flag, path string }{
+			{flag: "--summary-json", path: testRunState.RuntimeOptions.SummaryJSON.String},
+			{flag: "--summary-html", path: testRunState.RuntimeOptions.SummaryHTML.String},
+			{flag: "--summary-junit", path: testRunState.RuntimeOptions.SummaryJUnit.String},
+		} {
+			if file.path != "" {
+				logger.Warnf("The end-of-test summary is disabled with --no-summary, so %s won't be written", file.flag)
+			}
+		}
 	}
 
 	waitInitDone := emitEvent(&event.Event{Type: event.Init})
@@ -284,6 +354,10 @@ func (c *cmdRun) run(cmd *cobra.Command, args []string) (err error) {
 		// TODO: attach run status and exit code?
 		runAbort(err)
 	})
+	if len(c.gs.Flags.SecretSource) > 0 {
+		// the thresholds and the submetrics have to match the real tag values
+		outputManager.SetRedactor(c.gs.SecretsManager, metricsIngester)
+	}
 	samples := make(chan metrics.SampleContainer, test.derivedConfig.MetricSamplesBufferSize.Int64)
 	// Spin up the REST API server, if not disabled.
 	if c.gs.Flags.Address != "" { //nolint:nestif
@@ -306,6 +380,7 @@ func (c *cmdRun) run(cmd *cobra.Command, args []string) (err error) {
 			samples,
 			metricsEngine,
 			execScheduler,
+			func() string { return webDashboardURL(outputs) },
 		)
 		go func() {
 			defer apiWG.Done()
@@ -573,6 +648,64 @@ a commandline interface for interacting with it.`,
 	return runCmd
 }
 
+// addSummaryJSON adds the JSON representation of the summary data to the
+// handleSummary() result, so it's written together with the rest of the files.
+func addSummaryJSON(result map[string]io.Reader, path string, s *summary.Summary) error {
+	if path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not serialize the summary data: %w", err)
+	}
+	result[path] = bytes.NewReader(data)
+
+	return nil
+}
+
+// addSummaryReports adds the enabled reports of the summary data, e.g. the HTML
+// one, to the handleSummary() result, so they're written together with the rest
+// of the files.
+func addSummaryReports(result map[string]io.Reader, opts lib.RuntimeOptions, s *summary.Summary) error {
+	reports := []struct {
+		path   string
+		render func(io.Writer, *summary.Summary) error
+	}{
+		{path: opts.SummaryHTML.String, render: summary.RenderHTML},
+		{path: opts.SummaryJUnit.String, render: summary.RenderJUnit},
+	}
+
+	for _, report := range reports {
+		if report.path == "" {
+			continue
+		}
+		buf := &bytes.Buffer{}
+		if err := report.render(buf, s); err != nil {
+			return fmt.Errorf("could not render the summary report '%s': %w", report.path, err)
+		}
+		result[report.path] = buf
+	}
+
+	return nil
+}
+
+// redactSummaryResult replaces the secrets in the handleSummary() result, as the
+// summary data can contain them, e.g. in the names of the checks and the groups.
+func redactSummaryResult(result map[string]io.Reader, secretsManager *secretsource.Manager) error {
+	if secretsManager == nil {
+		return nil
+	}
+	for path, value := range result {
+		data, err := io.ReadAll(value)
+		if err != nil {
+			return fmt.Errorf("could not read the summary for '%s': %w", path, err)
+		}
+		result[path] = strings.NewReader(secretsManager.Redact(string(data)))
+	}
+	return nil
+}
+
 func handleSummaryResult(fs fsext.Fs, stdOut, stdErr io.Writer, result map[string]io.Reader) error {
 	var errs []error
 
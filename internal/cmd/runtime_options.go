@@ -11,6 +11,8 @@ import (
 	"go.k6.io/k6/cmd/state"
 	"go.k6.io/k6/internal/lib/summary"
 	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/types"
+	"go.k6.io/k6/metrics"
 )
 
 // TODO: move this whole file out of the cmd package? maybe when fixing
@@ -39,8 +41,33 @@ extended: base + sets "global" as alias for "globalThis"
 		"",
 		"output the end-of-test summary report to JSON file",
 	)
+	flags.String(
+		"summary-json",
+		"",
+		"output the end-of-test summary data to JSON file, which can be compared with \"k6 compare\"",
+	)
+	flags.String(
+		"summary-html",
+		"",
+		"output the end-of-test summary to a self-contained HTML report file",
+	)
+	flags.String(
+		"summary-junit",
+		"",
+		"output the thresholds and the checks of the end-of-test summary to a JUnit XML file",
+	)
+	flags.Duration(
+		"summary-timeline",
+		0,
+		"also aggregate the main metrics of the end-of-test summary for each time bucket of this size, e.g. 10s",
+	)
 	flags.String("traces-output", "none",
 		"set the output for k6 traces, possible values are none,otel[=host:port]")
+	flags.String("trend-sink-mode", metrics.TrendSinkModeExact.String(),
+		`how Trend metrics store values for thresholds and the summary, "exact" or "hdr"
+exact: keeps all values, so memory usage grows during the test
+hdr: bounded memory, percentiles are within 0.4% (or 0.0005) of the exact value
+`)
 	return flags
 }
 
@@ -81,7 +108,12 @@ func runtimeOptionsFromFlags(flags *pflag.FlagSet) lib.RuntimeOptions {
 		NoSummary:            getNullBool(flags, "no-summary"),
 		SummaryMode:          getNullString(flags, "summary-mode"),
 		SummaryExport:        getNullString(flags, "summary-export"),
+		SummaryJSON:          getNullString(flags, "summary-json"),
+		SummaryHTML:          getNullString(flags, "summary-html"),
+		SummaryJUnit:         getNullString(flags, "summary-junit"),
+		SummaryTimeline:      getNullDuration(flags, "summary-timeline"),
 		TracesOutput:         getNullString(flags, "traces-output"),
+		TrendSinkMode:        getNullString(flags, "trend-sink-mode"),
 		Env:                  make(map[string]string),
 	}
 	return opts
@@ -128,6 +160,31 @@ func populateRuntimeOptionsFromEnv(opts lib.RuntimeOptions, environment map[stri
 		opts.SummaryExport = null.StringFrom(envVar)
 	}
 
+	if envVar, ok := environment["K6_SUMMARY_JSON"]; !opts.SummaryJSON.Valid && ok {
+		opts.SummaryJSON = null.StringFrom(envVar)
+	}
+
+	if envVar, ok := environment["K6_SUMMARY_HTML"]; !opts.SummaryHTML.Valid && ok {
+		opts.SummaryHTML = null.StringFrom(envVar)
+	}
+
+	if envVar, ok := environment["K6_SUMMARY_JUNIT"]; !opts.SummaryJUnit.Valid && ok {
+		opts.SummaryJUnit = null.StringFrom(envVar)
+	}
+
+	if envVar, ok := environment["K6_SUMMARY_TIMELINE"]; !opts.SummaryTimeline.Valid && ok {
+		bucketSize, err := types.ParseExtendedDuration(envVar)
+		if err != nil {
+			return opts, fmt.Errorf("env var 'K6_SUMMARY_TIMELINE' is not a valid duration value: %w", err)
+		}
+		opts.SummaryTimeline = types.NullDurationFrom(bucketSize)
+	}
+
+	if opts.SummaryTimeline.Valid && opts.SummaryTimeline.Duration <= 0 {
+		// some early validation
+		return opts, fmt.Errorf("the summary timeline bucket size must be positive, got %s", opts.SummaryTimeline.Duration)
+	}
+
 	if envVar, ok := environment["SSLKEYLOGFILE"]; !opts.KeyWriter.Valid && ok {
 		opts.KeyWriter = null.StringFrom(envVar)
 	}
@@ -136,6 +193,15 @@ func populateRuntimeOptionsFromEnv(opts lib.RuntimeOptions, environment map[stri
 		opts.TracesOutput = null.StringFrom(envVar)
 	}
 
+	if envVar, ok := environment["K6_TREND_SINK_MODE"]; !opts.TrendSinkMode.Valid && ok {
+		opts.TrendSinkMode = null.StringFrom(envVar)
+	}
+
+	if _, err := metrics.ParseTrendSinkMode(opts.TrendSinkMode.String); err != nil {
+		// some early validation
+		return opts, err
+	}
+
 	// If enabled, gather the actual system environment variables
 	if opts.IncludeSystemEnvVars.Bool {
 		opts.Env = environment
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -15,6 +16,7 @@ import (
 	"go.k6.io/k6/internal/usage"
 	"go.k6.io/k6/lib"
 	"go.k6.io/k6/lib/fsext"
+	"go.k6.io/k6/lib/types"
 	"go.k6.io/k6/metrics"
 )
 
@@ -115,6 +117,7 @@ func TestRuntimeOptions(t *testing.T) {
 		enhancedCompatMode  = null.NewString("experimental_enhanced", true)
 		defaultTracesOutput = null.NewString("none", false)
 		defaultSummaryMode  = null.NewString("compact", false)
+		defaultTrendSink    = null.NewString("exact", false)
 	)
 
 	runtimeOptionsTestCases := map[string]runtimeOptionsTestCase{
@@ -127,6 +130,7 @@ func TestRuntimeOptions(t *testing.T) {
 				Env:                  nil,
 				TracesOutput:         defaultTracesOutput,
 				SummaryMode:          defaultSummaryMode,
+				TrendSinkMode:        defaultTrendSink,
 			},
 		},
 		"disabled sys env by default": {
@@ -138,6 +142,7 @@ func TestRuntimeOptions(t *testing.T) {
 				Env:                  map[string]string{},
 				TracesOutput:         defaultTracesOutput,
 				SummaryMode:          defaultSummaryMode,
+				TrendSinkMode:        defaultTrendSink,
 			},
 		},
 		"disabled sys env by default with ext compat mode": {
@@ -149,6 +154,7 @@ func TestRuntimeOptions(t *testing.T) {
 				Env:                  map[string]string{},
 				TracesOutput:         defaultTracesOutput,
 				SummaryMode:          defaultSummaryMode,
+				TrendSinkMode:        defaultTrendSink,
 			},
 		},
 		"disabled sys env by default with experimental_enhanced compat mode": {
@@ -160,6 +166,7 @@ func TestRuntimeOptions(t *testing.T) {
 				Env:                  map[string]string{},
 				TracesOutput:         defaultTracesOutput,
 				SummaryMode:          defaultSummaryMode,
+				TrendSinkMode:        defaultTrendSink,
 			},
 		},
 		"disabled sys env by cli 1": {
@@ -172,6 +179,7 @@ func TestRuntimeOptions(t *testing.T) {
 				Env:                  map[string]string{},
 				TracesOutput:         defaultTracesOutput,
 				SummaryMode:          defaultSummaryMode,
+				TrendSinkMode:        defaultTrendSink,
 			},
 		},
 		"disabled sys env by cli 2": {
@@ -184,6 +192,7 @@ func TestRuntimeOptions(t *testing.T) {
 				Env:                  map[string]string{},
 				TracesOutput:         defaultTracesOutput,
 				SummaryMode:          defaultSummaryMode,
+				TrendSinkMode:        defaultTrendSink,
 			},
 		},
 		"disabled sys env by env": {
@@ -195,6 +204,7 @@ func TestRuntimeOptions(t *testing.T) {
 				Env:                  map[string]string{},
 				TracesOutput:         defaultTracesOutput,
 				SummaryMode:          defaultSummaryMode,
+				TrendSinkMode:        defaultTrendSink,
 			},
 		},
 		"enabled sys env by env": {
@@ -206,6 +216,7 @@ func TestRuntimeOptions(t *testing.T) {
 				Env:                  map[string]string{"K6_INCLUDE_SYSTEM_ENV_VARS": "true", "K6_COMPATIBILITY_MODE": "extended"},
 				TracesOutput:         defaultTracesOutput,
 				SummaryMode:          defaultSummaryMode,
+				TrendSinkMode:        defaultTrendSink,
 			},
 		},
 		"enabled sys env by default": {
@@ -218,6 +229,7 @@ func TestRuntimeOptions(t *testing.T) {
 				Env:                  map[string]string{"test1": "val1"},
 				TracesOutput:         defaultTracesOutput,
 				SummaryMode:          defaultSummaryMode,
+				TrendSinkMode:        defaultTrendSink,
 			},
 		},
 		"enabled sys env by cli 1": {
@@ -230,6 +242,7 @@ func TestRuntimeOptions(t *testing.T) {
 				Env:                  map[string]string{"test1": "val1"},
 				TracesOutput:         defaultTracesOutput,
 				SummaryMode:          defaultSummaryMode,
+				TrendSinkMode:        defaultTrendSink,
 			},
 		},
 		"enabled sys env by cli 2": {
@@ -242,6 +255,7 @@ func TestRuntimeOptions(t *testing.T) {
 				Env:                  map[string]string{"test1": "val1"},
 				TracesOutput:         defaultTracesOutput,
 				SummaryMode:          defaultSummaryMode,
+				TrendSinkMode:        defaultTrendSink,
 			},
 		},
 		"run only system env": {
@@ -254,6 +268,7 @@ func TestRuntimeOptions(t *testing.T) {
 				Env:                  map[string]string{"test1": "val1"},
 				TracesOutput:         defaultTracesOutput,
 				SummaryMode:          defaultSummaryMode,
+				TrendSinkMode:        defaultTrendSink,
 			},
 		},
 		"mixed system and cli env": {
@@ -266,6 +281,7 @@ func TestRuntimeOptions(t *testing.T) {
 				Env:                  map[string]string{"test1": "val1", "test2": "", "test3": "val3", "test4": "", "test5": ""},
 				TracesOutput:         defaultTracesOutput,
 				SummaryMode:          defaultSummaryMode,
+				TrendSinkMode:        defaultTrendSink,
 			},
 		},
 		"mixed system and cli env 2": {
@@ -278,6 +294,7 @@ func TestRuntimeOptions(t *testing.T) {
 				Env:                  map[string]string{"test1": "val1", "test2": "", "test3": "val3", "test4": "", "test5": ""},
 				TracesOutput:         defaultTracesOutput,
 				SummaryMode:          defaultSummaryMode,
+				TrendSinkMode:        defaultTrendSink,
 			},
 		},
 		"disabled system env with cli params": {
@@ -290,6 +307,7 @@ func TestRuntimeOptions(t *testing.T) {
 				Env:                  map[string]string{"test2": "val2"},
 				TracesOutput:         defaultTracesOutput,
 				SummaryMode:          defaultSummaryMode,
+				TrendSinkMode:        defaultTrendSink,
 			},
 		},
 		"overwriting system env with cli param": {
@@ -302,6 +320,7 @@ func TestRuntimeOptions(t *testing.T) {
 				Env:                  map[string]string{"test1": "val1cli"},
 				TracesOutput:         defaultTracesOutput,
 				SummaryMode:          defaultSummaryMode,
+				TrendSinkMode:        defaultTrendSink,
 			},
 		},
 		"error wrong compat mode env var value": {
@@ -345,6 +364,7 @@ func TestRuntimeOptions(t *testing.T) {
 				Env:                  map[string]string{"test1": "value 1", "test2": "value 2"},
 				TracesOutput:         defaultTracesOutput,
 				SummaryMode:          defaultSummaryMode,
+				TrendSinkMode:        defaultTrendSink,
 			},
 		},
 		"valid env vars with special chars": {
@@ -357,6 +377,7 @@ func TestRuntimeOptions(t *testing.T) {
 				Env:                  map[string]string{"test1": "value 1", "test2": "value,2", "test3": ` ,  ,,, value, ,, 2!'@#,"`},
 				TracesOutput:         defaultTracesOutput,
 				SummaryMode:          defaultSummaryMode,
+				TrendSinkMode:        defaultTrendSink,
 			},
 		},
 		"summary and thresholds from env": {
@@ -371,6 +392,7 @@ func TestRuntimeOptions(t *testing.T) {
 				SummaryExport:        null.NewString("foo", true),
 				TracesOutput:         defaultTracesOutput,
 				SummaryMode:          defaultSummaryMode,
+				TrendSinkMode:        defaultTrendSink,
 			},
 		},
 		"summary and thresholds from env overwritten by CLI": {
@@ -386,6 +408,7 @@ func TestRuntimeOptions(t *testing.T) {
 				SummaryExport:        null.NewString("bar", true),
 				TracesOutput:         defaultTracesOutput,
 				SummaryMode:          defaultSummaryMode,
+				TrendSinkMode:        defaultTrendSink,
 			},
 		},
 		"env var error detected even when CLI flags overwrite 1": {
@@ -408,6 +431,7 @@ func TestRuntimeOptions(t *testing.T) {
 				Env:                  map[string]string{},
 				TracesOutput:         null.NewString("none", false),
 				SummaryMode:          defaultSummaryMode,
+				TrendSinkMode:        defaultTrendSink,
 			},
 		},
 		"traces output from env": {
@@ -419,6 +443,7 @@ func TestRuntimeOptions(t *testing.T) {
 				Env:                  map[string]string{},
 				TracesOutput:         null.NewString("foo", true),
 				SummaryMode:          defaultSummaryMode,
+				TrendSinkMode:        defaultTrendSink,
 			},
 		},
 		"traces output from env overwritten by CLI": {
@@ -431,6 +456,7 @@ func TestRuntimeOptions(t *testing.T) {
 				Env:                  map[string]string{},
 				TracesOutput:         null.NewString("bar", true),
 				SummaryMode:          defaultSummaryMode,
+				TrendSinkMode:        defaultTrendSink,
 			},
 		},
 		"summary mode from env": {
@@ -442,6 +468,7 @@ func TestRuntimeOptions(t *testing.T) {
 				Env:                  map[string]string{},
 				TracesOutput:         defaultTracesOutput,
 				SummaryMode:          null.NewString("full", true),
+				TrendSinkMode:        defaultTrendSink,
 			},
 		},
 		"summary mode from env overwritten by CLI": {
@@ -454,8 +481,76 @@ func TestRuntimeOptions(t *testing.T) {
 				Env:                  map[string]string{},
 				TracesOutput:         defaultTracesOutput,
 				SummaryMode:          null.NewString("legacy", true),
+				TrendSinkMode:        defaultTrendSink,
 			},
 		},
+		"trend sink mode from env": {
+			useSysEnv: false,
+			systemEnv: map[string]string{"K6_TREND_SINK_MODE": "hdr"},
+			expRTOpts: lib.RuntimeOptions{
+				IncludeSystemEnvVars: null.NewBool(false, false),
+				CompatibilityMode:    defaultCompatMode,
+				Env:                  map[string]string{},
+				TracesOutput:         defaultTracesOutput,
+				SummaryMode:          defaultSummaryMode,
+				TrendSinkMode:        null.NewString("hdr", true),
+			},
+		},
+		"trend sink mode from env overwritten by CLI": {
+			useSysEnv: false,
+			systemEnv: map[string]string{"K6_TREND_SINK_MODE": "hdr"},
+			cliFlags:  []string{"--trend-sink-mode", "exact"},
+			expRTOpts: lib.RuntimeOptions{
+				IncludeSystemEnvVars: null.NewBool(false, false),
+				CompatibilityMode:    defaultCompatMode,
+				Env:                  map[string]string{},
+				TracesOutput:         defaultTracesOutput,
+				SummaryMode:          defaultSummaryMode,
+				TrendSinkMode:        null.NewString("exact", true),
+			},
+		},
+		"summary timeline from env": {
+			useSysEnv: false,
+			systemEnv: map[string]string{"K6_SUMMARY_TIMELINE": "10s"},
+			expRTOpts: lib.RuntimeOptions{
+				IncludeSystemEnvVars: null.NewBool(false, false),
+				CompatibilityMode:    defaultCompatMode,
+				Env:                  map[string]string{},
+				TracesOutput:         defaultTracesOutput,
+				SummaryMode:          defaultSummaryMode,
+				TrendSinkMode:        defaultTrendSink,
+				SummaryTimeline:      types.NullDurationFrom(10 * time.Second),
+			},
+		},
+		"summary timeline from env overwritten by CLI": {
+			useSysEnv: false,
+			systemEnv: map[string]string{"K6_SUMMARY_TIMELINE": "10s"},
+			cliFlags:  []string{"--summary-timeline", "1m"},
+			expRTOpts: lib.RuntimeOptions{
+				IncludeSystemEnvVars: null.NewBool(false, false),
+				CompatibilityMode:    defaultCompatMode,
+				Env:                  map[string]string{},
+				TracesOutput:         defaultTracesOutput,
+				SummaryMode:          defaultSummaryMode,
+				TrendSinkMode:        defaultTrendSink,
+				SummaryTimeline:      types.NullDurationFrom(time.Minute),
+			},
+		},
+		"invalid summary timeline": {
+			useSysEnv: false,
+			systemEnv: map[string]string{"K6_SUMMARY_TIMELINE": "soon"},
+			expErr:    true,
+		},
+		"non-positive summary timeline": {
+			useSysEnv: false,
+			cliFlags:  []string{"--summary-timeline", "0s"},
+			expErr:    true,
+		},
+		"invalid trend sink mode": {
+			useSysEnv: false,
+			systemEnv: map[string]string{"K6_TREND_SINK_MODE": "tdigest"},
+			expErr:    true,
+		},
 	}
 	for name, tc := range runtimeOptionsTestCases {
 		t.Run(fmt.Sprintf("RuntimeOptions test '%s'", name), func(t *testing.T) {
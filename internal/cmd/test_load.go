@@ -54,12 +54,22 @@ func loadLocalTest(gs *state.GlobalState, cmd *cobra.Command, args []string) (*l
 	if err != nil {
 		return nil, err
 	}
-	resolvedPath := src.URL.String()
 	gs.Logger.Debugf(
 		"'%s' resolved to '%s' and successfully loaded %d bytes!",
-		sourceRootPath, resolvedPath, len(src.Data),
+		sourceRootPath, src.URL.String(), len(src.Data),
 	)
 
+	return loadTestFromSource(gs, cmd, sourceRootPath, src, fileSystems, pwd)
+}
+
+// loadTestFromSource initializes the first runner for a test whose source was
+// already read, either from the local filesystem or from somewhere else.
+func loadTestFromSource(
+	gs *state.GlobalState, cmd *cobra.Command, sourceRootPath string,
+	src *loader.SourceData, fileSystems map[string]fsext.Fs, pwd string,
+) (*loadedTest, error) {
+	resolvedPath := src.URL.String()
+
 	gs.Logger.Debugf("Gathering k6 runtime options...")
 	runtimeOptions, err := getRuntimeOptions(cmd.Flags(), gs.Env)
 	if err != nil {
@@ -72,7 +82,13 @@ func loadLocalTest(gs *state.GlobalState, cmd *cobra.Command, args []string) (*l
 			lib.CompatibilityModeExperimentalEnhanced.String(), lib.CompatibilityModeBase.String())
 	}
 
+	trendSinkMode, err := metrics.ParseTrendSinkMode(runtimeOptions.TrendSinkMode.String)
+	if err != nil {
+		return nil, err
+	}
+
 	registry := metrics.NewRegistry()
+	registry.SetTrendSinkMode(trendSinkMode)
 	state := &lib.TestPreInitState{
 		Logger:         gs.Logger,
 		RuntimeOptions: runtimeOptions,
@@ -85,6 +101,7 @@ func loadLocalTest(gs *state.GlobalState, cmd *cobra.Command, args []string) (*l
 		},
 		Usage:          gs.Usage,
 		SecretsManager: gs.SecretsManager,
+		WritableFS:     gs.FS,
 		TestStatus:     gs.TestStatus,
 	}
 
@@ -161,6 +178,8 @@ func (lt *loadedTest) initializeFirstRunner(gs *state.GlobalState) error {
 			if err != nil {
 				return fmt.Errorf("could not load JS from test archive bundle '%s': %w", testPath, err)
 			}
+			// the other files needed by the test are read from the archive too
+			lt.fileSystems = arc.Filesystems
 			lt.initRunner = runner
 			lt.moduleResolver = runner.Bundle.ModuleResolver
 			return nil
@@ -235,6 +254,14 @@ func (lt *loadedTest) consolidateDeriveAndValidateConfig(
 		return nil, err
 	}
 
+	gs.Logger.Debug("Loading the files read by the executors...")
+	if consolidatedConfig.Scenarios, err = lt.loadExecutorFiles(consolidatedConfig.Scenarios); err != nil {
+		return nil, errext.WithExitCodeIfNone(err, exitcodes.InvalidConfig)
+	}
+	if derivedConfig.Scenarios, err = lt.loadExecutorFiles(derivedConfig.Scenarios); err != nil {
+		return nil, errext.WithExitCodeIfNone(err, exitcodes.InvalidConfig)
+	}
+
 	return &loadedAndConfiguredTest{
 		loadedTest:         lt,
 		consolidatedConfig: consolidatedConfig,
@@ -242,6 +269,28 @@ func (lt *loadedTest) consolidateDeriveAndValidateConfig(
 	}, nil
 }
 
+// loadExecutorFiles loads the files read by the executors of the scenarios, e.g. the
+// logs of the replay ones, into the file system of the test, so that they're included
+// in its archives, like the files opened by the script. It returns the scenarios with
+// the absolute paths of the files, which are resolved against the working directory.
+func (lt *loadedTest) loadExecutorFiles(scenarios lib.ScenarioConfigs) (lib.ScenarioConfigs, error) {
+	fs := lt.fileSystems["file"]
+	return scenarios.WithFilePaths(func(path string) (string, error) {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(lt.pwd, path)
+		}
+		// the files in the archives are already loaded
+		if cacher, ok := fs.(fsext.FileCacher); ok {
+			if err := cacher.CacheFile(path); err != nil {
+				return "", fmt.Errorf("couldn't load the file %q: %w", path, err)
+			}
+		} else if exists, err := fsext.Exists(fs, path); err != nil || !exists {
+			return "", fmt.Errorf("the file %q isn't in the archive of the test", path)
+		}
+		return path, nil
+	})
+}
+
 // loadedAndConfiguredTest contains the whole loadedTest, as well as the
 // consolidated test config and the full test run state.
 type loadedAndConfiguredTest struct {
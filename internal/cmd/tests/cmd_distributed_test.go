@@ -0,0 +1,232 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/cmd/state"
+	"go.k6.io/k6/errext/exitcodes"
+	"go.k6.io/k6/internal/cmd"
+	"go.k6.io/k6/lib/fsext"
+)
+
+// k6ProcessEnvVar makes the test binary behave as the k6 binary, so that the
+// tests can start real k6 processes with it.
+const k6ProcessEnvVar = "K6_TEST_RUN_AS_K6_PROCESS"
+
+//nolint:forbidigo
+func runK6Process() {
+	cmd.ExecuteWithGlobalState(state.NewGlobalState(context.Background()))
+	os.Exit(0)
+}
+
+// syncBuffer is a bytes.Buffer that can be written by a process and read by the test concurrently.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (sb *syncBuffer) Write(p []byte) (int, error) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.buf.Write(p)
+}
+
+func (sb *syncBuffer) String() string {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.buf.String()
+}
+
+type k6Process struct {
+	cmd    *exec.Cmd
+	output *syncBuffer
+	done   chan struct{}
+	err    error
+}
+
+//nolint:forbidigo
+func startK6Process(t *testing.T, args ...string) *k6Process {
+	t.Helper()
+
+	p := &k6Process{output: &syncBuffer{}, done: make(chan struct{})}
+	p.cmd = exec.Command(os.Args[0], args...) //nolint:gosec,noctx
+	p.cmd.Env = append(os.Environ(), k6ProcessEnvVar+"=1", "K6_NO_USAGE_REPORT=true", "K6_NO_COLOR=true")
+	p.cmd.Stdout = p.output
+	p.cmd.Stderr = p.output
+	require.NoError(t, p.cmd.Start())
+
+	go func() {
+		p.err = p.cmd.Wait()
+		close(p.done)
+	}()
+	t.Cleanup(func() {
+		select {
+		case <-p.done:
+		default:
+			_ = p.cmd.Process.Kill()
+			<-p.done
+		}
+		if t.Failed() {
+			t.Logf("output of k6 %s:\n%s", strings.Join(args, " "), p.output.String())
+		}
+	})
+
+	return p
+}
+
+func (p *k6Process) waitForOutput(t *testing.T, text string) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		return strings.Contains(p.output.String(), text)
+	}, 30*time.Second, 50*time.Millisecond, "%q was not found in the output", text)
+}
+
+// wait waits for the process to exit and returns its exit code.
+func (p *k6Process) wait(t *testing.T, timeout time.Duration) int {
+	t.Helper()
+	select {
+	case <-p.done:
+	case <-time.After(timeout):
+		t.Fatalf("the k6 process didn't exit in %s", timeout)
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(p.err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	require.NoError(t, p.err)
+	return 0
+}
+
+func TestDistributedProcesses(t *testing.T) {
+	t.Parallel()
+
+	t.Run("agents execute their segments of the test", func(t *testing.T) {
+		t.Parallel()
+
+		script := filepath.Join(t.TempDir(), "script.js")
+		require.NoError(t, os.WriteFile(script, []byte(`
+			export const options = {
+				scenarios: {
+					main: { executor: 'shared-iterations', vus: 2, iterations: 4 },
+				},
+			};
+
+			export function setup() {
+				return { token: 'secret' };
+			}
+
+			export default function (data) {
+				if (data.token !== 'secret') {
+					throw new Error('unexpected setup data ' + JSON.stringify(data));
+				}
+				console.log('iteration done');
+			}
+		`), 0o600))
+
+		addr := getFreeBindAddr(t)
+		coordinator := startK6Process(t, "coordinator", "--instance-count", "2", "--coordinator-address", addr, script)
+		coordinator.waitForOutput(t, "Waiting for 2 agent instances")
+
+		agents := []*k6Process{startK6Process(t, "agent", addr), startK6Process(t, "agent", addr)}
+		for _, agent := range agents {
+			assert.Equal(t, 0, agent.wait(t, time.Minute))
+			assert.Equal(t, 2, strings.Count(agent.output.String(), "iteration done"))
+		}
+		assert.Equal(t, 0, coordinator.wait(t, time.Minute))
+		assert.Contains(t, coordinator.output.String(), "All agent instances finished successfully")
+	})
+
+	t.Run("a lost agent aborts the test run", func(t *testing.T) {
+		t.Parallel()
+
+		script := filepath.Join(t.TempDir(), "script.js")
+		require.NoError(t, os.WriteFile(script, []byte(`
+			import { sleep } from 'k6';
+
+			export const options = {
+				scenarios: {
+					main: { executor: 'constant-vus', vus: 2, duration: '5m' },
+				},
+			};
+
+			export default function () {
+				console.log('iteration started');
+				sleep(0.1);
+			}
+		`), 0o600))
+
+		addr := getFreeBindAddr(t)
+		coordinator := startK6Process(t,
+			"coordinator", "--instance-count", "2", "--coordinator-address", addr, "--agent-timeout", "2s", script,
+		)
+		coordinator.waitForOutput(t, "Waiting for 2 agent instances")
+
+		alive := startK6Process(t, "agent", addr)
+		alive.waitForOutput(t, "Registered with the coordinator as instance 1")
+		lost := startK6Process(t, "agent", addr)
+		alive.waitForOutput(t, "iteration started")
+		lost.waitForOutput(t, "iteration started")
+
+		require.NoError(t, lost.cmd.Process.Kill())
+
+		// Way before the end of the test run, both the coordinator and the
+		// remaining agent should notice that the other agent was lost.
+		assert.Equal(t, int(exitcodes.ExternalAbort), coordinator.wait(t, time.Minute))
+		assert.Contains(t, coordinator.output.String(), "Instance 2 was lost")
+		assert.Equal(t, int(exitcodes.ExternalAbort), alive.wait(t, time.Minute))
+		assert.Contains(t, alive.output.String(), "instance 2 aborted: no heartbeat was received for 2s")
+	})
+	t.Run("agents without the token are rejected", func(t *testing.T) {
+		t.Parallel()
+
+		script := filepath.Join(t.TempDir(), "script.js")
+		require.NoError(t, os.WriteFile(script, []byte(`
+			export const options = {
+				scenarios: {
+					main: { executor: 'shared-iterations', vus: 1, iterations: 1 },
+				},
+			};
+
+			export default function () { console.log('iteration done'); }
+		`), 0o600))
+
+		addr := getFreeBindAddr(t)
+		coordinator := startK6Process(t,
+			"coordinator", "--instance-count", "1", "--coordinator-address", addr, "--token", "s3cr3t", script,
+		)
+		coordinator.waitForOutput(t, "Waiting for 1 agent instances")
+
+		intruder := startK6Process(t, "agent", "--token", "wrong", addr)
+		assert.NotEqual(t, 0, intruder.wait(t, time.Minute))
+		assert.Contains(t, intruder.output.String(), "the token is missing or invalid")
+
+		agent := startK6Process(t, "agent", "--token", "s3cr3t", addr)
+		assert.Equal(t, 0, agent.wait(t, time.Minute))
+		assert.Contains(t, agent.output.String(), "iteration done")
+		assert.Equal(t, 0, coordinator.wait(t, time.Minute))
+	})
+}
+
+func TestCoordinatorRequiresToken(t *testing.T) {
+	t.Parallel()
+
+	ts := NewGlobalTestState(t)
+	require.NoError(t, fsext.WriteFile(ts.FS, filepath.Join(ts.Cwd, "test.js"), []byte(`export default function() {};`), 0o644))
+	ts.CmdArgs = []string{"k6", "coordinator", "--coordinator-address", "0.0.0.0:0", "test.js"}
+	ts.ExpectedExitCode = int(exitcodes.InvalidConfig)
+	cmd.ExecuteWithGlobalState(ts.GlobalState)
+	assert.Contains(t, ts.Stderr.String(), "a token is required when the coordinator listens on 0.0.0.0:0")
+}
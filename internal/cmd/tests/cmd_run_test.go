@@ -29,10 +29,13 @@ import (
 	"go.k6.io/k6/internal/cmd"
 	"go.k6.io/k6/internal/cmd/tests/events"
 	"go.k6.io/k6/internal/event"
+	"go.k6.io/k6/internal/lib/summary"
 	"go.k6.io/k6/internal/lib/testutils"
 	"go.k6.io/k6/internal/lib/testutils/httpmultibin"
 	"go.k6.io/k6/js/modules"
+	"go.k6.io/k6/lib"
 	"go.k6.io/k6/lib/fsext"
+	"go.k6.io/k6/lib/types"
 )
 
 func TestVersion(t *testing.T) {
@@ -374,6 +377,116 @@ func TestThresholdsWithCustomPercentile(t *testing.T) {
 	assert.Regexp(t, `✓ 'p\(99.99\)<200' p\(99.99\)=\d+(\.\d+)?(µs|ms|ns|s)`, stdout)
 }
 
+func TestThresholdsWithHDRTrendSinkMode(t *testing.T) {
+	t.Parallel()
+	script := `
+		import { Trend } from 'k6/metrics';
+
+		const myTrend = new Trend('my_trend');
+
+		export const options = {
+			iterations: 1,
+			thresholds: {
+				'my_trend': ['p(50)<600', 'p(99)>900', 'max==1000'],
+				'my_trend{tag:odd}': ['p(50)>400'],
+			},
+		};
+
+		export default function () {
+			for (let i = 1; i <= 1000; i++) {
+				myTrend.add(i, { tag: i % 2 ? 'odd' : 'even' });
+			}
+		}
+	`
+	ts := getSingleFileTestState(t, script, []string{"--trend-sink-mode", "hdr"}, 0)
+	cmd.ExecuteWithGlobalState(ts.GlobalState)
+
+	stdout := ts.Stdout.String()
+	t.Log(stdout)
+
+	assert.Regexp(t, `✓ 'p\(50\)<600' p\(50\)=50\d\.\d+`, stdout)
+	assert.Regexp(t, `✓ 'p\(99\)>900' p\(99\)=9[89]\d\.\d+`, stdout)
+	assert.Contains(t, stdout, `✓ 'max==1000' max=1000`)
+	assert.Contains(t, stdout, `✓ 'p(50)>400'`)
+}
+
+func TestTimeWindowedThresholds(t *testing.T) {
+	t.Parallel()
+	script := `
+		import { Counter, Rate, Trend } from 'k6/metrics';
+
+		const myTrend = new Trend('my_trend');
+		const myCounter = new Counter('my_counter');
+		const myRate = new Rate('my_rate');
+
+		export const options = {
+			iterations: 1,
+			thresholds: {
+				'my_trend': ['p(95) over 1m < 300', 'max over 30s==250'],
+				'my_counter': ['count over 30s>5'],
+				'my_rate': ['rate over 1m<0.5'],
+			},
+		};
+
+		export default function () {
+			for (let i = 1; i <= 250; i++) {
+				myTrend.add(i);
+			}
+			myCounter.add(10);
+			myRate.add(false);
+		}
+	`
+	ts := getSingleFileTestState(t, script, nil, 0)
+	cmd.ExecuteWithGlobalState(ts.GlobalState)
+
+	stdout := ts.Stdout.String()
+	t.Log(stdout)
+
+	assert.Regexp(t, `✓ 'p\(95\) over 1m < 300' p\(95\) over 1m=23\d\.\d+`, stdout)
+	assert.Contains(t, stdout, `✓ 'max over 30s==250' max over 30s=250`)
+	assert.Contains(t, stdout, `✓ 'count over 30s>5' count over 30s=10`)
+	assert.Contains(t, stdout, `✓ 'rate over 1m<0.5' rate over 1m=0.00%`)
+}
+
+func TestAbortedByTimeWindowedThreshold(t *testing.T) {
+	t.Parallel()
+	script := `
+		import { Trend } from 'k6/metrics';
+		import { sleep } from 'k6';
+
+		const myTrend = new Trend('my_trend');
+
+		export const options = {
+			scenarios: {
+				sc1: {
+					executor: 'constant-vus',
+					vus: 1,
+					duration: '30s',
+				},
+			},
+			thresholds: {
+				'my_trend': [{
+					threshold: 'p(95) over 10s<300',
+					abortOnFail: true,
+				}],
+			},
+		};
+
+		export default function () {
+			myTrend.add(500);
+			sleep(0.1);
+		};
+	`
+	ts := getSingleFileTestState(t, script, nil, exitcodes.ThresholdsHaveFailed)
+	cmd.ExecuteWithGlobalState(ts.GlobalState)
+
+	expErr := "thresholds on metrics 'my_trend' were crossed; at least one has abortOnFail enabled, stopping test prematurely"
+	assert.True(t, testutils.LogContains(ts.LoggerHook.Drain(), logrus.ErrorLevel, expErr))
+	stdout := ts.Stdout.String()
+	t.Log(stdout)
+	assert.Contains(t, stdout, `✗ 'p(95) over 10s<300' p(95) over 10s=500`)
+}
+
 func TestSSLKEYLOGFILEAbsolute(t *testing.T) {
 	t.Parallel()
 	ts := NewGlobalTestState(t)
@@ -473,7 +586,7 @@ func TestExecutionTestOptionsDefaultValues(t *testing.T) {
 	loglines := ts.LoggerHook.Drain()
 	require.Len(t, loglines, 1)
 
-	expected := `{"paused":null,"executionSegment":null,"executionSegmentSequence":null,"noSetup":null,"setupTimeout":null,"noTeardown":null,"teardownTimeout":null,"rps":null,"dns":{"ttl":null,"select":null,"policy":null},"maxRedirects":null,"userAgent":null,"batch":null,"batchPerHost":null,"httpDebug":null,"insecureSkipTLSVerify":null,"tlsCipherSuites":null,"tlsVersion":null,"tlsAuth":null,"throw":null,"thresholds":null,"blacklistIPs":null,"blockHostnames":null,"hosts":null,"noConnectionReuse":null,"noVUConnectionReuse":null,"minIterationDuration":null,"ext":null,"summaryTrendStats":["avg", "min", "med", "max", "p(90)", "p(95)"],"summaryTimeUnit":null,"systemTags":["check","error","error_code","expected_response","group","method","name","proto","scenario","service","status","subproto","tls_version","url"],"tags":null,"metricSamplesBufferSize":null,"noCookiesReset":null,"discardResponseBodies":null,"consoleOutput":null,"scenarios":{"default":{"vus":null,"iterations":1,"executor":"shared-iterations","maxDuration":null,"startTime":null,"env":null,"tags":null,"gracefulStop":null,"exec":null}},"localIPs":null}`
+	expected := `{"paused":null,"executionSegment":null,"executionSegmentSequence":null,"noSetup":null,"setupTimeout":null,"noTeardown":null,"teardownTimeout":null,"rps":null,"dns":{"ttl":null,"select":null,"policy":null},"maxRedirects":null,"userAgent":null,"batch":null,"batchPerHost":null,"httpDebug":null,"insecureSkipTLSVerify":null,"tlsCipherSuites":null,"tlsVersion":null,"tlsAuth":null,"throw":null,"thresholds":null,"blacklistIPs":null,"blockHostnames":null,"hosts":null,"noConnectionReuse":null,"noVUConnectionReuse":null,"http3":null,"minIterationDuration":null,"ext":null,"summaryTrendStats":["avg", "min", "med", "max", "p(90)", "p(95)"],"summaryTimeUnit":null,"systemTags":["check","error","error_code","expected_response","group","method","name","proto","scenario","service","status","subproto","tls_version","url"],"tags":null,"metricSamplesBufferSize":null,"noCookiesReset":null,"discardResponseBodies":null,"consoleOutput":null,"scenarios":{"default":{"vus":null,"iterations":1,"executor":"shared-iterations","maxDuration":null,"startTime":null,"env":null,"tags":null,"gracefulStop":null,"exec":null}},"localIPs":null}`
 	assert.JSONEq(t, expected, loglines[0].Message)
 }
 
@@ -2501,6 +2614,157 @@ func TestMultipleSecretSources(t *testing.T) {
 	assert.Contains(t, stderr, `level=info msg="trigger exception on wrong key" ***SECRET_REDACTED***=console`)
 }
 
+func TestEnvAndVaultSecretSources(t *testing.T) {
+	t.Parallel()
+	mainScript := `
+		import secrets from "k6/secrets";
+
+		export default async () => {
+			console.log(await secrets.source("env").get("DB_PASSWORD"));
+			console.log(await secrets.source("vault").get("app/token"));
+			try {
+				await secrets.source("env").get("HOME");
+			} catch {
+				console.log("the variables without the prefix aren't secrets");
+			}
+		}
+	`
+
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/secret/data/k6/app" || r.Header.Get("X-Vault-Token") != "vault-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": {"data": {"token": "t0k3n-from-vault"}}}`))
+	}))
+	t.Cleanup(vault.Close)
+
+	ts := NewGlobalTestState(t)
+	require.NoError(t, fsext.WriteFile(ts.FS, filepath.Join(ts.Cwd, "secrets.js"), []byte(mainScript), 0o644))
+	ts.Env["K6_SECRET_DB_PASSWORD"] = "p4ssw0rd-from-env"
+	ts.Env["HOME"] = "/home/k6"
+	ts.Env["VAULT_TOKEN"] = "vault-token"
+
+	ts.CmdArgs = []string{
+		"k6", "run",
+		"--secret-source=env=name=env,prefix=K6_SECRET_",
+		"--secret-source=vault=name=vault,address=" + vault.URL + ",path=k6",
+		"secrets.js",
+	}
+
+	cmd.ExecuteWithGlobalState(ts.GlobalState)
+
+	stderr := ts.Stderr.String()
+	t.Log(stderr)
+
+	assert.Equal(t, 2, strings.Count(stderr, `level=info msg="***SECRET_REDACTED***" source=console`))
+	assert.Contains(t, stderr, `msg="the variables without the prefix aren't secrets" source=console`)
+	assert.NotContains(t, stderr, "p4ssw0rd-from-env")
+	assert.NotContains(t, stderr, "t0k3n-from-vault")
+}
+
+func TestSecretsRedaction(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// echo the token, so it's in the response dump too
+		_, _ = w.Write([]byte("token=" + r.URL.Query().Get("token")))
+	}))
+	t.Cleanup(srv.Close)
+
+	consoleOutput := filepath.Join(t.TempDir(), "console.log")
+	mainScript := fmt.Sprintf(`
+		import secrets from "k6/secrets";
+		import http from "k6/http";
+		import { check } from "k6";
+
+		export const options = { iterations: 1 };
+
+		export default async () => {
+			const token = await secrets.get("token");
+			const res = http.get("%s/?token=" + encodeURIComponent(token));
+			check(res, { ["body has " + token]: (r) => r.body.includes(token) });
+			console.log("the token is " + token);
+		}
+
+		export function handleSummary(data) {
+			return {
+				"summary.json": JSON.stringify(data),
+				"stdout": data.root_group.checks.map((c) => c.name).join("\\n"),
+			};
+		}
+	`, srv.URL)
+
+	ts := NewGlobalTestState(t)
+	require.NoError(t, fsext.WriteFile(ts.FS, filepath.Join(ts.Cwd, "secrets.js"), []byte(mainScript), 0o644))
+
+	ts.CmdArgs = []string{
+		"k6", "run", "--http-debug=full", "--console-output", consoleOutput, "--out", "json=results.json",
+		"--secret-source=mock=token=s3cr3t two/parts", "secrets.js",
+	}
+
+	cmd.ExecuteWithGlobalState(ts.GlobalState)
+
+	for _, secret := range []string{"s3cr3t two/parts", "s3cr3t%20two%2Fparts", "s3cr3t+two%2Fparts"} {
+		assert.NotContains(t, ts.Stderr.String(), secret)
+		assert.NotContains(t, ts.Stdout.String(), secret)
+
+		//nolint:forbidigo // the console output file is always on the OS file system
+		consoleData, err := os.ReadFile(consoleOutput)
+		require.NoError(t, err)
+		assert.NotContains(t, string(consoleData), secret)
+
+		for _, path := range []string{"results.json", "summary.json"} {
+			data, err := fsext.ReadFile(ts.FS, path)
+			require.NoError(t, err)
+			assert.NotContains(t, string(data), secret, path)
+		}
+	}
+
+	assert.Contains(t, ts.Stderr.String(), "GET /?token=***SECRET_REDACTED*** HTTP/1.1")
+	//nolint:forbidigo // the console output file is always on the OS file system
+	consoleData, err := os.ReadFile(consoleOutput)
+	require.NoError(t, err)
+	assert.Contains(t, string(consoleData), "the token is ***SECRET_REDACTED***")
+	assert.Contains(t, ts.Stdout.String(), "body has ***SECRET_REDACTED***")
+	results, err := fsext.ReadFile(ts.FS, "results.json")
+	require.NoError(t, err)
+	assert.Contains(t, string(results), `"url":"`+srv.URL+`/?token=***SECRET_REDACTED***"`)
+}
+
+func TestSecretsRedactionKeepsThresholdTags(t *testing.T) {
+	t.Parallel()
+
+	mainScript := `
+		import secrets from "k6/secrets";
+		import { Counter } from "k6/metrics";
+
+		const tokens = new Counter("tokens");
+
+		export const options = {
+			iterations: 1,
+			thresholds: { "tokens{token:s3cr3t}": ["count==1"] },
+		};
+
+		export default async () => {
+			tokens.add(1, { token: await secrets.get("token") });
+		}
+	`
+
+	ts := NewGlobalTestState(t)
+	require.NoError(t, fsext.WriteFile(ts.FS, filepath.Join(ts.Cwd, "secrets.js"), []byte(mainScript), 0o644))
+	ts.CmdArgs = []string{
+		"k6", "run", "--out", "json=results.json", "--secret-source=mock=token=s3cr3t", "secrets.js",
+	}
+
+	// the threshold only passes if it sees the real tag value
+	cmd.ExecuteWithGlobalState(ts.GlobalState)
+
+	results, err := fsext.ReadFile(ts.FS, "results.json")
+	require.NoError(t, err)
+	assert.Contains(t, string(results), `"token":"***SECRET_REDACTED***"`)
+}
+
 func TestSummaryExport(t *testing.T) {
 	t.Parallel()
 
@@ -2616,6 +2880,286 @@ func TestSummaryExport(t *testing.T) {
 	})
 }
 
+func TestSummaryJSON(t *testing.T) {
+	t.Parallel()
+
+	mainScript := `
+		import { check, group } from "k6";
+		import { Counter } from 'k6/metrics';
+
+		const customIter = new Counter("custom_iterations");
+
+		export const options = {
+			scenarios: {
+				sc: { executor: "shared-iterations", iterations: 1 },
+			},
+		};
+
+		export default function () {
+			group("my group", () => {
+				customIter.add(1);
+				check(true, { "TRUE is TRUE": (r) => r });
+			});
+		};
+	`
+
+	ts := NewGlobalTestState(t)
+	require.NoError(t, fsext.WriteFile(ts.FS, filepath.Join(ts.Cwd, "script.js"), []byte(mainScript), 0o644))
+	ts.CmdArgs = []string{
+		"k6", "run",
+		"--summary-json=data.json",
+		"--summary-mode=full",
+		"script.js",
+	}
+
+	cmd.ExecuteWithGlobalState(ts.GlobalState)
+	t.Log(ts.Stdout.String())
+
+	rawSummaryData, err := fsext.ReadFile(ts.FS, "data.json")
+	require.NoError(t, err)
+
+	// The durations are encoded as strings, like in the options, and not as nanoseconds
+	testRunDuration := gjson.GetBytes(rawSummaryData, "test_run_duration")
+	assert.Equal(t, gjson.String, testRunDuration.Type, testRunDuration.Raw)
+
+	var summaryData summary.Summary
+	require.NoError(t, json.Unmarshal(rawSummaryData, &summaryData))
+
+	assert.Positive(t, summaryData.TestRunDuration)
+	assert.Equal(t, 1.0, summaryData.Metrics.Custom["custom_iterations"].Values["count"])
+	assert.Equal(t, 1.0, summaryData.Metrics.Execution["iterations"].Values["count"])
+
+	require.Contains(t, summaryData.Scenarios, "sc")
+	scenarioData := summaryData.Scenarios["sc"]
+	assert.Equal(t, 1.0, scenarioData.Metrics.Custom["custom_iterations"].Values["count"])
+	assert.Equal(t, []string{"my group"}, scenarioData.GroupsOrder)
+	require.NotNil(t, scenarioData.Groups["my group"].Checks)
+	assert.Equal(t, 1.0, scenarioData.Groups["my group"].Checks.Metrics.Success.Values["rate"])
+
+	// The same summary data can't contain any regressions
+	ts.CmdArgs = []string{"k6", "compare", "--metric-direction=custom_iterations=none", "data.json", "data.json"}
+	ts.Stdout.Reset()
+	cmd.ExecuteWithGlobalState(ts.GlobalState)
+
+	stdout := ts.Stdout.String()
+	t.Log(stdout)
+	assert.Contains(t, stdout, "█ SCENARIO: sc, GROUP: ::my group")
+	assert.Contains(t, stdout, "no regressions above the tolerance")
+}
+
+func TestSummaryTimeline(t *testing.T) {
+	t.Parallel()
+
+	mainScript := `
+		import { sleep } from "k6";
+
+		export const options = {
+			scenarios: {
+				ramp: {
+					executor: "ramping-vus",
+					startVUs: 1,
+					stages: [
+						{ duration: "1s", target: 2 },
+						{ duration: "1s", target: 0 },
+					],
+					gracefulRampDown: "0s",
+				},
+			},
+		};
+
+		export default function () {
+			sleep(0.1);
+		};
+
+		export function handleSummary(data) {
+			const ramp = data.timeline.scenarios.ramp;
+			return {
+				"timeline.json": JSON.stringify({
+					bucketSize: data.timeline.bucket_size,
+					targets: ramp.stages.map((stage) => stage.target),
+					stageIterations: ramp.stages.map((stage) => stage.metrics.iterations.count),
+				}),
+			};
+		}
+	`
+
+	ts := NewGlobalTestState(t)
+	require.NoError(t, fsext.WriteFile(ts.FS, filepath.Join(ts.Cwd, "script.js"), []byte(mainScript), 0o644))
+	ts.CmdArgs = []string{
+		"k6", "run",
+		"--summary-timeline=1s",
+		"--summary-json=data.json",
+		"script.js",
+	}
+
+	cmd.ExecuteWithGlobalState(ts.GlobalState)
+	t.Log(ts.Stdout.String())
+
+	rawTimeline, err := fsext.ReadFile(ts.FS, "timeline.json")
+	require.NoError(t, err)
+
+	var timeline struct {
+		BucketSize      string    `json:"bucketSize"`
+		Targets         []int64   `json:"targets"`
+		StageIterations []float64 `json:"stageIterations"`
+	}
+	require.NoError(t, json.Unmarshal(rawTimeline, &timeline))
+	assert.Equal(t, "1s", timeline.BucketSize)
+	assert.Equal(t, []int64{2, 0}, timeline.Targets)
+	require.Len(t, timeline.StageIterations, 2)
+	assert.Positive(t, timeline.StageIterations[0])
+
+	rawSummaryData, err := fsext.ReadFile(ts.FS, "data.json")
+	require.NoError(t, err)
+
+	var summaryData summary.Summary
+	require.NoError(t, json.Unmarshal(rawSummaryData, &summaryData))
+	require.NotNil(t, summaryData.Timeline)
+	assert.Equal(t, types.Duration(time.Second), summaryData.Timeline.BucketSize)
+	assert.NotEmpty(t, summaryData.Timeline.Buckets)
+	require.Contains(t, summaryData.Timeline.Scenarios, "ramp")
+	assert.Len(t, summaryData.Timeline.Scenarios["ramp"].Stages, 2)
+}
+
+func TestSummaryHTML(t *testing.T) {
+	t.Parallel()
+
+	mainScript := `
+		import { check, group } from "k6";
+
+		export const options = {
+			scenarios: {
+				sc: { executor: "shared-iterations", iterations: 1 },
+			},
+			thresholds: {
+				checks: ["rate==1"],
+			},
+		};
+
+		export default function () {
+			group("my group", () => {
+				check(true, { "TRUE is TRUE": (r) => r });
+			});
+		};
+	`
+
+	ts := NewGlobalTestState(t)
+	require.NoError(t, fsext.WriteFile(ts.FS, filepath.Join(ts.Cwd, "script.js"), []byte(mainScript), 0o644))
+	ts.CmdArgs = []string{
+		"k6", "run",
+		"--summary-mode=full",
+		"script.js",
+	}
+	ts.Env["K6_SUMMARY_HTML"] = "report.html"
+
+	cmd.ExecuteWithGlobalState(ts.GlobalState)
+	t.Log(ts.Stdout.String())
+
+	report, err := fsext.ReadFile(ts.FS, "report.html")
+	require.NoError(t, err)
+
+	assert.Contains(t, string(report), "All 1 thresholds passed")
+	assert.Contains(t, string(report), "<h2>Scenario sc</h2>")
+	assert.Contains(t, string(report), "<h3>Group my group</h3>")
+	assert.Contains(t, string(report), "<td>TRUE is TRUE</td><td>1</td><td>0</td><td>100.00%</td>")
+	assert.Contains(t, string(report), "<figcaption>Checks succeeded (%)</figcaption>")
+}
+
+func TestSummaryJUnit(t *testing.T) {
+	t.Parallel()
+
+	mainScript := `
+		import { check, group } from "k6";
+
+		export const options = {
+			scenarios: {
+				sc: { executor: "shared-iterations", iterations: 1 },
+			},
+			thresholds: {
+				checks: ["rate==1"],
+			},
+		};
+
+		export default function () {
+			group("my group", () => {
+				check(true, { "TRUE is TRUE": (r) => r });
+				check(false, { "FALSE is TRUE": (r) => r });
+			});
+		};
+	`
+
+	ts := NewGlobalTestState(t)
+	require.NoError(t, fsext.WriteFile(ts.FS, filepath.Join(ts.Cwd, "script.js"), []byte(mainScript), 0o644))
+	ts.CmdArgs = []string{
+		"k6", "run",
+		"--summary-mode=full",
+		"--summary-junit=junit.xml",
+		"script.js",
+	}
+	ts.ExpectedExitCode = int(exitcodes.ThresholdsHaveFailed)
+
+	cmd.ExecuteWithGlobalState(ts.GlobalState)
+	t.Log(ts.Stdout.String())
+
+	report, err := fsext.ReadFile(ts.FS, "junit.xml")
+	require.NoError(t, err)
+
+	assert.Contains(t, string(report), `<testsuites name="k6" tests="3" failures="2"`)
+	assert.Contains(t, string(report), `<testcase name="rate==1" classname="thresholds.checks">`)
+	assert.Contains(t, string(report), `<testsuite name="scenario sc::my group" tests="2" failures="1">`)
+	assert.Contains(t, string(report), `<testcase name="TRUE is TRUE" classname="scenario sc::my group"></testcase>`)
+	assert.Contains(t, string(report),
+		`<failure message="1 of 1 checks failed, 0.00% succeeded" type="check">passes=0 fails=1</failure>`)
+}
+
+func TestSummaryReportsWithFailingHandleSummary(t *testing.T) {
+	t.Parallel()
+
+	mainScript := `
+		export const options = { iterations: 1 };
+
+		export default function () {};
+
+		export const handleSummary = "oops";
+	`
+
+	ts := NewGlobalTestState(t)
+	require.NoError(t, fsext.WriteFile(ts.FS, filepath.Join(ts.Cwd, "script.js"), []byte(mainScript), 0o644))
+	ts.CmdArgs = []string{
+		"k6", "run", "--summary-json=summary.json", "--summary-html=report.html", "--summary-junit=junit.xml", "script.js",
+	}
+
+	cmd.ExecuteWithGlobalState(ts.GlobalState)
+
+	assert.Contains(t, ts.Stderr.String(), "failed to handle the end-of-test summary")
+	assert.Contains(t, ts.Stderr.String(), "handleSummary must be a function")
+	// the built-in files don't depend on handleSummary()
+	for _, path := range []string{"summary.json", "report.html", "junit.xml"} {
+		data, err := fsext.ReadFile(ts.FS, path)
+		require.NoError(t, err, path)
+		assert.NotEmpty(t, data, path)
+	}
+}
+
+func TestSummaryReportsWithNoSummary(t *testing.T) {
+	t.Parallel()
+
+	ts := NewGlobalTestState(t)
+	require.NoError(t, fsext.WriteFile(ts.FS, filepath.Join(ts.Cwd, "script.js"),
+		[]byte(`export default function () {};`), 0o644))
+	ts.CmdArgs = []string{"k6", "run", "--no-summary", "--summary-html=report.html", "--summary-junit=junit.xml", "script.js"}
+
+	cmd.ExecuteWithGlobalState(ts.GlobalState)
+
+	stderr := ts.Stderr.String()
+	assert.Contains(t, stderr, "The end-of-test summary is disabled with --no-summary, so --summary-html won't be written")
+	assert.Contains(t, stderr, "The end-of-test summary is disabled with --no-summary, so --summary-junit won't be written")
+	assert.NotContains(t, stderr, "--summary-json")
+	exists, err := fsext.Exists(ts.FS, "report.html")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
 func TestHandleSummary(t *testing.T) {
 	t.Parallel()
 	mainScript := `
@@ -2738,3 +3282,146 @@ func TestGroupsOrderInFullSummary(t *testing.T) {
 
 	assert.Regexp(t, regexp.MustCompile(expectedGroupsRegex), stdout)
 }
+
+func TestBreakingPointExecutor(t *testing.T) {
+	t.Parallel()
+	mainScript := `
+		import exec from "k6/execution";
+		import { Trend } from "k6/metrics";
+
+		// the latency grows with the test run duration, so the third step breaches the condition
+		const latency = new Trend("fake_latency", true);
+
+		export const options = {
+			scenarios: {
+				capacity: {
+					executor: "breaking-point",
+					startRate: 10,
+					stepRate: 10,
+					maxRate: 100,
+					stepDuration: "1s",
+					preAllocatedVUs: 5,
+					conditions: { fake_latency: ["max<2500"] },
+					gracefulStop: "0s",
+				},
+			},
+		};
+
+		let logged = false;
+		export default function () {
+			latency.add(exec.instance.currentTestRunDuration);
+			if (!logged && exec.scenario.sustainableRate !== null) {
+				console.log("sustainable rate seen by the iterations: " + exec.scenario.sustainableRate);
+				logged = true;
+			}
+		}
+
+		export function handleSummary(data) {
+			return { stdout: "sustainable_rate=" + data.metrics.sustainable_rate.values.value };
+		}
+	`
+
+	ts := NewGlobalTestState(t)
+	require.NoError(t, fsext.WriteFile(ts.FS, filepath.Join(ts.Cwd, "test.js"), []byte(mainScript), 0o644))
+	ts.CmdArgs = []string{"k6", "run", "--no-thresholds", "test.js"}
+
+	cmd.ExecuteWithGlobalState(ts.GlobalState)
+
+	stderr := ts.Stderr.String()
+	t.Log(stderr)
+	assert.Contains(t, stderr, "The highest sustainable rate of scenario capacity is 20.00 iterations/s")
+	assert.Contains(t, stderr, `msg="sustainable rate seen by the iterations: 10" source=console`)
+	assert.Contains(t, ts.Stdout.String(), "sustainable_rate=20")
+}
+
+const replayScript = `
+	import exec from "k6/execution";
+
+	export const options = {
+		scenarios: {
+			production: {
+				executor: "replay",
+				file: "traffic.csv",
+				speed: 10,
+				preAllocatedVUs: 2,
+			},
+		},
+	};
+
+	export default function () {
+		const record = exec.scenario.record;
+		console.log("replaying " + record.method + " " + record.path + " as iteration " + exec.scenario.iterationInTest);
+	}
+`
+
+const replayTraffic = "timestamp,method,path\n" +
+	"01/Mar/2024:10:00:00 +0000,GET,/\n" +
+	"01/Mar/2024:10:00:02 +0000,GET,/products\n" +
+	"01/Mar/2024:10:00:01 +0000,POST,/login\n" +
+	"01/Mar/2024:10:00:03 +0000,POST,/cart\n"
+
+func assertReplayed(t *testing.T, ts *GlobalTestState) {
+	t.Helper()
+	stderr := ts.Stderr.String()
+	t.Log(stderr)
+	assert.Contains(t, stderr, `msg="replaying GET / as iteration 0" source=console`)
+	assert.Contains(t, stderr, `msg="replaying POST /login as iteration 1" source=console`)
+	assert.Contains(t, stderr, `msg="replaying GET /products as iteration 2" source=console`)
+	assert.Contains(t, stderr, `msg="replaying POST /cart as iteration 3" source=console`)
+	assert.Regexp(t, `iterations\.+: 4 `, ts.Stdout.String())
+}
+
+func TestReplayExecutor(t *testing.T) {
+	t.Parallel()
+
+	ts := NewGlobalTestState(t)
+	require.NoError(t, fsext.WriteFile(ts.FS, filepath.Join(ts.Cwd, "test.js"), []byte(replayScript), 0o644))
+	require.NoError(t, fsext.WriteFile(ts.FS, filepath.Join(ts.Cwd, "traffic.csv"), []byte(replayTraffic), 0o644))
+	ts.CmdArgs = []string{"k6", "run", "test.js"}
+
+	cmd.ExecuteWithGlobalState(ts.GlobalState)
+
+	assertReplayed(t, ts)
+}
+
+func TestReplayExecutorFromArchive(t *testing.T) {
+	t.Parallel()
+
+	ts := NewGlobalTestState(t)
+	ts.Cwd = filepath.FromSlash("/home/someone/k6")
+	require.NoError(t, fsext.WriteFile(ts.FS, filepath.Join(ts.Cwd, "test.js"), []byte(replayScript), 0o644))
+	require.NoError(t, fsext.WriteFile(ts.FS, filepath.Join(ts.Cwd, "traffic.csv"), []byte(replayTraffic), 0o644))
+	ts.CmdArgs = []string{"k6", "archive", "-O", filepath.Join(ts.Cwd, "archive.tar"), "test.js"}
+	cmd.ExecuteWithGlobalState(ts.GlobalState)
+
+	data, err := fsext.ReadFile(ts.FS, filepath.Join(ts.Cwd, "archive.tar"))
+	require.NoError(t, err)
+	arc, err := lib.ReadArchive(bytes.NewReader(data))
+	require.NoError(t, err)
+	// the path of the log is anonymized, like the ones of the other files
+	logPath := arc.Options.Scenarios["production"].(lib.FileReadingExecutorConfig).GetFilePaths()[0] //nolint:forcetypeassert
+	assert.Equal(t, filepath.FromSlash("/home/nobody/k6/traffic.csv"), logPath)
+
+	// the log is only in the archive, which is run from another directory
+	runTS := NewGlobalTestState(t)
+	runTS.Cwd = filepath.Join(runTS.Cwd, "elsewhere")
+	require.NoError(t, fsext.WriteFile(runTS.FS, filepath.Join(runTS.Cwd, "archive.tar"), data, 0o644))
+	runTS.CmdArgs = []string{"k6", "run", "archive.tar"}
+
+	cmd.ExecuteWithGlobalState(runTS.GlobalState)
+
+	assertReplayed(t, runTS)
+}
+
+func TestReplayExecutorMissingLog(t *testing.T) {
+	t.Parallel()
+
+	ts := NewGlobalTestState(t)
+	require.NoError(t, fsext.WriteFile(ts.FS, filepath.Join(ts.Cwd, "test.js"), []byte(replayScript), 0o644))
+	ts.CmdArgs = []string{"k6", "archive", "test.js"}
+	ts.ExpectedExitCode = int(exitcodes.InvalidConfig)
+
+	cmd.ExecuteWithGlobalState(ts.GlobalState)
+
+	assert.Contains(t, ts.Stderr.String(), "scenario production: couldn't load the file")
+}
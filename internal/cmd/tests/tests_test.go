@@ -2,6 +2,7 @@
 package tests
 
 import (
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -9,6 +10,10 @@ import (
 )
 
 func TestMain(m *testing.M) {
+	// Some tests execute the test binary itself as a real k6 process.
+	if os.Getenv(k6ProcessEnvVar) != "" {
+		runK6Process()
+	}
 	Main(m)
 }
 
@@ -0,0 +1,412 @@
+// Package har converts HTTP Archive (HAR) recordings, e.g. from the browser
+// developer tools, to k6 scripts.
+package har
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.k6.io/k6/internal/converter"
+	"go.k6.io/k6/lib"
+)
+
+// DefaultMinThinkTime is the default minimal pause between the recorded
+// requests, for which a sleep() is added to the script.
+const DefaultMinThinkTime = 500 * time.Millisecond
+
+// Options are the options of the conversion.
+type Options struct {
+	// OnlyDomains keeps only the requests to these domains and their
+	// subdomains, when it isn't empty.
+	OnlyDomains []string
+	// SkipDomains skips the requests to these domains and their subdomains.
+	SkipDomains []string
+	// SkipStatic skips the requests of static assets, like images,
+	// stylesheets, scripts and fonts.
+	SkipStatic bool
+	// MinThinkTime is the minimal pause between the recorded requests, for
+	// which a sleep() is added to the script.
+	MinThinkTime time.Duration
+
+	// ScriptName and ProjectID are used for the cloud options of the script.
+	ScriptName string
+	ProjectID  string
+}
+
+// skippedRequestHeaders are set by k6 itself or handled by the cookie jar.
+var skippedRequestHeaders = map[string]struct{}{
+	"host":           {},
+	"content-length": {},
+	"cookie":         {},
+	"connection":     {},
+}
+
+var staticMimeTypePrefixes = []string{
+	"image/", "font/", "audio/", "video/", "text/css", "text/javascript",
+	"application/javascript", "application/x-javascript", "application/font",
+}
+
+var staticExtensions = map[string]struct{}{
+	".css": {}, ".js": {}, ".mjs": {}, ".map": {}, ".png": {}, ".jpg": {}, ".jpeg": {}, ".gif": {},
+	".svg": {}, ".ico": {}, ".webp": {}, ".avif": {}, ".bmp": {}, ".woff": {}, ".woff2": {},
+	".ttf": {}, ".otf": {}, ".eot": {}, ".mp3": {}, ".mp4": {}, ".webm": {},
+}
+
+// request is a recorded request, converted to the k6/http call.
+type request struct {
+	pageref string
+	// thinkTime is the pause before the request, when the user didn't wait for any request.
+	thinkTime time.Duration
+	code      string
+	// extractions are the dynamic values of the response used by later requests.
+	extractions []*correlation
+}
+
+// Convert converts the HAR recording to a k6 script.
+func Convert(h *HAR, opts Options) (string, error) {
+	if h == nil || h.Log == nil {
+		return "", errors.New("the HAR recording has no log")
+	}
+
+	entries := make([]Entry, len(h.Log.Entries))
+	copy(entries, h.Log.Entries)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].StartedDateTime.Before(entries[j].StartedDateTime)
+	})
+
+	var (
+		requests  []*request
+		corr      = newCorrelator()
+		jar       = make(map[string]struct{})
+		lastEnd   time.Time
+		thinkTime time.Duration
+	)
+	for _, e := range entries {
+		if !lastEnd.IsZero() && e.StartedDateTime.Sub(lastEnd) >= opts.MinThinkTime {
+			thinkTime += e.StartedDateTime.Sub(lastEnd)
+		}
+		if end := e.StartedDateTime.Add(time.Duration(e.Time * float64(time.Millisecond))); end.After(lastEnd) {
+			lastEnd = end
+		}
+
+		u, err := url.Parse(e.Request.URL)
+		if err != nil || !opts.keep(e, u) {
+			continue
+		}
+
+		req := &request{
+			pageref:   e.Pageref,
+			thinkTime: thinkTime,
+			code:      requestCode(e.Request, corr, jar),
+		}
+		thinkTime = 0
+
+		if e.Response != nil {
+			for _, c := range responseCookies(e.Response) {
+				jar[c] = struct{}{}
+			}
+			req.extractions = findCorrelations(e.Response)
+			corr.received(req.extractions)
+		}
+		requests = append(requests, req)
+	}
+
+	if len(requests) == 0 {
+		return "", errors.New("the HAR recording has no requests to convert")
+	}
+
+	return script(h.Log, requests, corr, opts), nil
+}
+
+// keep reports whether the entry should be converted.
+func (opts Options) keep(e Entry, u *url.URL) bool {
+	if u.Scheme != "http" && u.Scheme != "https" || e.Request.Method == http.MethodConnect {
+		return false
+	}
+
+	host := u.Hostname()
+	if len(opts.OnlyDomains) > 0 && !matchesDomain(host, opts.OnlyDomains) {
+		return false
+	}
+	if matchesDomain(host, opts.SkipDomains) {
+		return false
+	}
+
+	return !opts.SkipStatic || !isStatic(e, u)
+}
+
+func matchesDomain(host string, domains []string) bool {
+	host = strings.ToLower(host)
+	for _, d := range domains {
+		d = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(d), "*."))
+		if d != "" && (host == d || strings.HasSuffix(host, "."+d)) {
+			return true
+		}
+	}
+	return false
+}
+
+func isStatic(e Entry, u *url.URL) bool {
+	if e.Response != nil && e.Response.Content != nil {
+		mimeType := strings.ToLower(e.Response.Content.MimeType)
+		for _, prefix := range staticMimeTypePrefixes {
+			if strings.HasPrefix(mimeType, prefix) {
+				return true
+			}
+		}
+	}
+
+	_, ok := staticExtensions[strings.ToLower(path.Ext(u.Path))]
+	return ok
+}
+
+// responseCookies returns the names of the cookies set by the response,
+// which the k6 cookie jar will then send with the later requests.
+func responseCookies(resp *Response) []string {
+	names := make([]string, 0, len(resp.Cookies))
+	for _, c := range resp.Cookies {
+		names = append(names, c.Name)
+	}
+
+	header := make(http.Header)
+	for _, h := range resp.Headers {
+		if strings.EqualFold(h.Name, "Set-Cookie") {
+			header.Add("Set-Cookie", h.Value)
+		}
+	}
+	for _, c := range (&http.Response{Header: header}).Cookies() {
+		names = append(names, c.Name)
+	}
+
+	return names
+}
+
+// requestCookies returns the cookies sent with the request.
+func requestCookies(req *Request) []Cookie {
+	if len(req.Cookies) > 0 {
+		return req.Cookies
+	}
+
+	header := make(http.Header)
+	for _, h := range req.Headers {
+		if strings.EqualFold(h.Name, "Cookie") {
+			header.Add("Cookie", h.Value)
+		}
+	}
+	var cookies []Cookie
+	for _, c := range (&http.Request{Header: header}).Cookies() {
+		cookies = append(cookies, Cookie{Name: c.Name, Value: c.Value})
+	}
+	return cookies
+}
+
+func requestBody(req *Request) (string, bool) {
+	if req.PostData == nil {
+		return "", false
+	}
+	if req.PostData.Text != "" || len(req.PostData.Params) == 0 {
+		return req.PostData.Text, req.PostData.Text != ""
+	}
+
+	parts := make([]string, 0, len(req.PostData.Params))
+	for _, p := range req.PostData.Params {
+		if p.FileName != "" {
+			// the uploaded files aren't in the recording
+			return "", false
+		}
+		parts = append(parts, url.QueryEscape(p.Name)+"="+url.QueryEscape(p.Value))
+	}
+	return strings.Join(parts, "&"), true
+}
+
+// requestCode returns the k6/http call of the request.
+func requestCode(req *Request, corr *correlator, jar map[string]struct{}) string {
+	body, hasBody := requestBody(req)
+
+	var headers []converter.Param
+	headerIndex := make(map[string]int)
+	for _, h := range req.Headers {
+		name := strings.ToLower(h.Name)
+		if _, skip := skippedRequestHeaders[name]; skip || strings.HasPrefix(name, ":") {
+			continue
+		}
+		if i, ok := headerIndex[name]; ok {
+			headers[i].Expr += ` + ", " + ` + corr.expression(h.Value)
+			continue
+		}
+		headerIndex[name] = len(headers)
+		headers = append(headers, converter.Param{Name: h.Name, Expr: corr.expression(h.Value)})
+	}
+
+	// the cookies set by the earlier responses are already in the VU's cookie jar
+	var cookies []converter.Param
+	for _, c := range requestCookies(req) {
+		if _, ok := jar[c.Name]; !ok {
+			cookies = append(cookies, converter.Param{Name: c.Name, Expr: corr.expression(c.Value)})
+		}
+	}
+
+	texts := []string{req.URL, body}
+	for _, h := range req.Headers {
+		texts = append(texts, h.Value)
+	}
+	for _, c := range req.Cookies {
+		texts = append(texts, c.Value)
+	}
+
+	var b strings.Builder
+	args := []string{corr.expression(req.URL)}
+	method := strings.ToUpper(req.Method)
+	fn, hasBodyArg := converter.HTTPFunction(method)
+	if hasBodyArg {
+		if hasBody {
+			args = append(args, corr.expression(body))
+		} else {
+			args = append(args, "null")
+		}
+	}
+	corr.sending(texts...)
+
+	if fn == "request" {
+		args = append([]string{converter.JSString(method)}, args...)
+	}
+
+	if len(headers) > 0 || len(cookies) > 0 {
+		args = append(args, converter.RequestParams(headers, cookies))
+	}
+
+	fmt.Fprintf(&b, "http.%s(%s)", fn, strings.Join(args, ", "))
+	return b.String()
+}
+
+// script assembles the k6 script from the converted requests.
+func script(log *Log, requests []*request, corr *correlator, opts Options) string {
+	titles := make(map[string]string, len(log.Pages))
+	for _, p := range log.Pages {
+		title := p.Title
+		if title == "" {
+			title = p.ID
+		}
+		// the group names can't contain the group separator
+		titles[p.ID] = strings.ReplaceAll(title, lib.GroupSeparator, ":")
+	}
+
+	var (
+		usesGroups, usesSleep, usesRes bool
+		variables                      []string
+	)
+	for _, r := range requests {
+		if _, ok := titles[r.pageref]; ok {
+			usesGroups = true
+		}
+		if r.thinkTime > 0 {
+			usesSleep = true
+		}
+		for _, e := range r.extractions {
+			if e.variable != "" {
+				usesRes = true
+				variables = append(variables, e.variable)
+			}
+		}
+	}
+
+	w := &converter.ScriptWriter{}
+	if log.Creator != nil && log.Creator.Name != "" {
+		w.Line("// Generated from a HAR recording created by %s.",
+			strings.TrimSpace(log.Creator.Name+" "+log.Creator.Version))
+	} else {
+		w.Line("// Generated from a HAR recording.")
+	}
+
+	var k6Imports []string
+	if usesGroups {
+		k6Imports = append(k6Imports, "group")
+	}
+	if usesSleep {
+		k6Imports = append(k6Imports, "sleep")
+	}
+	if len(k6Imports) > 0 {
+		w.Line(`import { %s } from "k6";`, strings.Join(k6Imports, ", "))
+	}
+	w.Line(`import http from "k6/http";`)
+	w.Line("")
+	w.Line("export const options = {")
+	w.Line("  vus: 1,")
+	w.Line("  iterations: 1,")
+	if opts.ProjectID != "" {
+		w.Line("  cloud: {")
+		w.Line("    projectID: %s,", opts.ProjectID)
+		w.Line("    name: %s,", converter.JSString(opts.ScriptName))
+		w.Line("  },")
+	}
+	w.Line("};")
+	w.Line("")
+	w.Line("export default function () {")
+	w.Indent++
+	if usesRes {
+		w.Line("let res;")
+	}
+	for _, v := range variables {
+		w.Line("let %s;", v)
+	}
+
+	hasDeclarations := usesRes || len(variables) > 0
+	currentPage, inGroup := "", false
+	for i, r := range requests {
+		pageChanged := i == 0 || r.pageref != currentPage
+		if pageChanged && inGroup {
+			w.Indent--
+			w.Line("});")
+			inGroup = false
+		}
+		if i > 0 || hasDeclarations {
+			w.Line("")
+		}
+		if r.thinkTime > 0 {
+			w.Line("sleep(%s);", formatSeconds(r.thinkTime))
+			w.Line("")
+		}
+		if title, ok := titles[r.pageref]; ok && pageChanged {
+			w.Line("group(%s, function () {", converter.JSString(title))
+			w.Indent++
+			inGroup = true
+		}
+		currentPage = r.pageref
+
+		used := false
+		for _, e := range r.extractions {
+			used = used || e.variable != ""
+		}
+		if used {
+			w.Line("res = %s;", r.code)
+		} else {
+			w.Line("%s;", r.code)
+		}
+		for _, e := range r.extractions {
+			if e.variable != "" {
+				w.Line("%s = %s;", e.variable, e.extract)
+			}
+		}
+	}
+	if inGroup {
+		w.Indent--
+		w.Line("});")
+	}
+
+	w.Indent--
+	w.Line("}")
+
+	return w.String()
+}
+
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatFloat(math.Round(d.Seconds()*100)/100, 'f', -1, 64)
+}
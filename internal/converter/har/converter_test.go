@@ -0,0 +1,213 @@
+package har
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEntry(pageref string, started time.Time, method, url string, resp *Response, headers ...Header) Entry {
+	if resp == nil {
+		resp = &Response{Status: 200, Content: &Content{MimeType: "text/plain"}}
+	}
+	return Entry{
+		Pageref:         pageref,
+		StartedDateTime: started,
+		Time:            100,
+		Request:         &Request{Method: method, URL: url, Headers: headers},
+		Response:        resp,
+	}
+}
+
+func TestConvert(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	at := func(ms int) time.Time { return start.Add(time.Duration(ms) * time.Millisecond) }
+
+	loginPage := &Response{
+		Status:  200,
+		Headers: []Header{{Name: "Set-Cookie", Value: "session=s3ss10n; Path=/"}},
+		Content: &Content{
+			MimeType: "text/html; charset=utf-8",
+			Text: `<html><head><meta name="csrf-token" content="m3ta70ken"></head><body><form>` +
+				`<input type="hidden" name="csrf_token" value="abc123def456"><input name="user"></form></body></html>`,
+		},
+	}
+	loginResponse := &Response{
+		Status:  200,
+		Content: &Content{MimeType: "application/json", Text: `{"auth": {"access_token": "t0k3n+with/chars"}}`},
+	}
+
+	login := newTestEntry("page_1", at(0), "POST", "https://test.k6.io/login", loginResponse,
+		Header{Name: "Content-Type", Value: "application/x-www-form-urlencoded"},
+		Header{Name: "X-CSRF-Token", Value: "m3ta70ken"},
+		Header{Name: "Cookie", Value: "session=s3ss10n; consent=y3s1234"},
+	)
+	login.StartedDateTime = at(1000)
+	login.Request.PostData = &PostData{
+		MimeType: "application/x-www-form-urlencoded",
+		Params:   []Param{{Name: "csrf_token", Value: "abc123def456"}, {Name: "user", Value: "admin"}},
+	}
+
+	recording := &HAR{Log: &Log{
+		Creator: &Creator{Name: "Test", Version: "1.0"},
+		Pages: []Page{
+			{ID: "page_1", Title: "Login::Page"},
+			{ID: "page_2", Title: "Profile"},
+		},
+		Entries: []Entry{
+			// the entries aren't in order
+			login,
+			newTestEntry("page_1", at(0), "GET", "https://test.k6.io/login", loginPage),
+			newTestEntry("page_1", at(50), "GET", "https://test.k6.io/logo.png", nil),
+			newTestEntry("page_1", at(60), "GET", "https://cdn.example.com/app", nil),
+			newTestEntry("page_2", at(3100), "GET", "https://test.k6.io/profile?token=t0k3n%2Bwith%2Fchars", nil,
+				Header{Name: "Authorization", Value: "Bearer t0k3n+with/chars"},
+				Header{Name: ":authority", Value: "test.k6.io"},
+			),
+			newTestEntry("page_2", at(3150), "CONNECT", "https://test.k6.io:443", nil),
+			newTestEntry("", at(3200), "DELETE", "https://test.k6.io/session", nil),
+		},
+	}}
+
+	script, err := Convert(recording, Options{
+		SkipDomains:  []string{"example.com"},
+		SkipStatic:   true,
+		MinThinkTime: DefaultMinThinkTime,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, `// Generated from a HAR recording created by Test 1.0.
+import { group, sleep } from "k6";
+import http from "k6/http";
+
+export const options = {
+  vus: 1,
+  iterations: 1,
+};
+
+export default function () {
+  let res;
+  let csrfToken2;
+  let csrfToken;
+  let accessToken;
+
+  group("Login:Page", function () {
+    res = http.get("https://test.k6.io/login");
+    csrfToken2 = res.html().find("input[name=\"csrf_token\"]").first().attr("value");
+    csrfToken = res.html().find("meta[name=\"csrf-token\"]").first().attr("content");
+
+    sleep(0.84);
+
+    res = http.post("https://test.k6.io/login", "csrf_token=" + csrfToken2 + "&user=admin", {
+      headers: {
+        "Content-Type": "application/x-www-form-urlencoded",
+        "X-CSRF-Token": csrfToken,
+      },
+      cookies: {
+        "consent": "y3s1234",
+      },
+    });
+    accessToken = res.json("auth.access_token");
+  });
+
+  sleep(2);
+
+  group("Profile", function () {
+    http.get("https://test.k6.io/profile?token=" + encodeURIComponent(accessToken), {
+      headers: {
+        "Authorization": "Bearer " + accessToken,
+      },
+    });
+  });
+
+  http.del("https://test.k6.io/session", null);
+}
+`, script)
+}
+
+func TestConvertFilters(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	recording := &HAR{Log: &Log{Entries: []Entry{
+		newTestEntry("", start, "GET", "https://test.k6.io/", nil),
+		newTestEntry("", start, "GET", "https://api.test.k6.io/", nil),
+		newTestEntry("", start, "GET", "https://k6.io/", nil),
+		newTestEntry("", start, "GET", "https://other.io/", nil),
+		newTestEntry("", start, "GET", "https://test.k6.io/style.css", nil),
+		newTestEntry("", start, "PROPFIND", "https://test.k6.io/dav", nil),
+		newTestEntry("", start, "GET", "data:image/png;base64,AAAA", nil),
+	}}}
+
+	script, err := Convert(recording, Options{OnlyDomains: []string{"test.k6.io"}})
+	require.NoError(t, err)
+	assert.Contains(t, script, `http.get("https://test.k6.io/");`)
+	assert.Contains(t, script, `http.get("https://api.test.k6.io/");`)
+	assert.Contains(t, script, `http.get("https://test.k6.io/style.css");`)
+	assert.Contains(t, script, `http.request("PROPFIND", "https://test.k6.io/dav", null);`)
+	assert.NotContains(t, script, "https://k6.io/")
+	assert.NotContains(t, script, "other.io")
+	assert.NotContains(t, script, "data:")
+	assert.NotContains(t, script, "group")
+	assert.NotContains(t, script, "sleep")
+
+	_, err = Convert(recording, Options{OnlyDomains: []string{"grafana.com"}})
+	require.ErrorContains(t, err, "no requests to convert")
+}
+
+func TestConvertClientValuesArentCorrelated(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	echo := &Response{Status: 200, Content: &Content{MimeType: "application/json", Text: `{"id": "client1234"}`}}
+	recording := &HAR{Log: &Log{Entries: []Entry{
+		newTestEntry("", start, "PUT", "https://test.k6.io/items/client1234", echo),
+		newTestEntry("", start.Add(time.Second), "GET", "https://test.k6.io/items/client1234", nil),
+	}}}
+
+	script, err := Convert(recording, Options{})
+	require.NoError(t, err)
+	assert.NotContains(t, script, "res.json")
+	assert.Contains(t, script, `http.get("https://test.k6.io/items/client1234");`)
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	_, err := Decode([]byte(`{"log": {"entries": [{"startedDateTime": "2025-01-01T10:00:00.000Z"}]}}`))
+	require.ErrorContains(t, err, "the request of entry 0 is missing")
+
+	_, err = Decode([]byte(`{}`))
+	require.ErrorContains(t, err, "the log object is missing")
+
+	_, err = Decode([]byte(`[]`))
+	require.ErrorContains(t, err, "invalid HAR file")
+
+	h, err := Decode([]byte(`{"log": {"entries": [{
+		"startedDateTime": "2025-01-01T10:00:00.000+02:00",
+		"time": 12.5,
+		"request": {"method": "GET", "url": "https://test.k6.io/"}
+	}]}}`))
+	require.NoError(t, err)
+	require.Len(t, h.Log.Entries, 1)
+	assert.Equal(t, 12.5, h.Log.Entries[0].Time)
+}
+
+func TestIdentifier(t *testing.T) {
+	t.Parallel()
+
+	for name, expected := range map[string]string{
+		"csrf_token":   "csrfToken",
+		"X-CSRF-Token": "xCSRFToken",
+		"order_id":     "orderId",
+		"1st":          "value1st",
+		"ñ":            "value",
+		"new":          "newValue",
+	} {
+		assert.Equal(t, expected, identifier(name), name)
+	}
+}
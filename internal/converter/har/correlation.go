@@ -0,0 +1,403 @@
+package har
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/tidwall/gjson"
+
+	"go.k6.io/k6/internal/converter"
+)
+
+const (
+	minCorrelatedValueLength = 8
+	maxCorrelatedValueLength = 4096
+	maxJSONDepth             = 16
+)
+
+// dynamicNameRe matches the names of the headers and the HTML meta tags
+// which usually contain dynamic values like CSRF tokens and session IDs.
+var dynamicNameRe = regexp.MustCompile(`(?i)csrf|xsrf|token|session|nonce`)
+
+// correlation is a dynamic value of a response which is sent back in the
+// later requests, e.g. a CSRF token or the ID of a created resource. The
+// script has to extract it from the response while it runs instead of
+// sending the recorded value.
+type correlation struct {
+	value string
+	// name is the base for the name of the variable holding the value.
+	name string
+	// extract is the JS expression extracting the value from the response in `res`.
+	extract string
+
+	// variable is set when the value is used by a later request.
+	variable string
+}
+
+// findCorrelations returns the candidate dynamic values of the response.
+func findCorrelations(resp *Response) []*correlation {
+	if resp == nil {
+		return nil
+	}
+
+	var result []*correlation
+	for _, h := range resp.Headers {
+		if strings.EqualFold(h.Name, "Set-Cookie") || !dynamicNameRe.MatchString(h.Name) || !isDynamicValue(h.Value) {
+			continue
+		}
+		result = append(result, &correlation{
+			value:   h.Value,
+			name:    h.Name,
+			extract: fmt.Sprintf("res.headers[%s]", converter.JSString(http.CanonicalHeaderKey(h.Name))),
+		})
+	}
+
+	body, mimeType := responseBody(resp)
+	switch {
+	case strings.Contains(mimeType, "json"):
+		result = append(result, findJSONCorrelations(body)...)
+	case strings.Contains(mimeType, "html"):
+		result = append(result, findHTMLCorrelations(body)...)
+	}
+
+	return result
+}
+
+func responseBody(resp *Response) (string, string) {
+	if resp.Content == nil {
+		return "", ""
+	}
+
+	body := resp.Content.Text
+	if resp.Content.Encoding == "base64" {
+		decoded, err := base64.StdEncoding.DecodeString(body)
+		if err != nil {
+			return "", ""
+		}
+		body = string(decoded)
+	}
+
+	return body, strings.ToLower(resp.Content.MimeType)
+}
+
+func findJSONCorrelations(body string) []*correlation {
+	decoder := json.NewDecoder(strings.NewReader(body))
+	decoder.UseNumber()
+	var data any
+	if err := decoder.Decode(&data); err != nil {
+		return nil
+	}
+
+	var result []*correlation
+	var walk func(v any, path []string, depth int)
+	walk = func(v any, path []string, depth int) {
+		if depth > maxJSONDepth {
+			return
+		}
+		switch v := v.(type) {
+		case map[string]any:
+			keys := make([]string, 0, len(v))
+			for k := range v {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				walk(v[k], append(path, k), depth+1)
+			}
+		case []any:
+			for i, item := range v {
+				walk(item, append(path, strconv.Itoa(i)), depth+1)
+			}
+		case string:
+			if len(path) == 0 || !isDynamicValue(v) {
+				return
+			}
+			selector := gjsonPath(path)
+			// gjson is what res.json() uses, so make sure that the selector works
+			if gjson.Get(body, selector).String() != v {
+				return
+			}
+			result = append(result, &correlation{
+				value:   v,
+				name:    jsonName(path),
+				extract: fmt.Sprintf("res.json(%s)", converter.JSString(selector)),
+			})
+		}
+	}
+	walk(data, nil, 0)
+
+	return result
+}
+
+// jsonName returns the last key of the path, which isn't an array index.
+func jsonName(path []string) string {
+	for i := len(path) - 1; i >= 0; i-- {
+		if _, err := strconv.Atoi(path[i]); err != nil {
+			return path[i]
+		}
+	}
+	return "value"
+}
+
+func gjsonPath(path []string) string {
+	escaped := make([]string, len(path))
+	for i, p := range path {
+		var b strings.Builder
+		for _, r := range p {
+			if strings.ContainsRune(`.*?|#@\!=<>%`, r) {
+				b.WriteRune('\\')
+			}
+			b.WriteRune(r)
+		}
+		escaped[i] = b.String()
+	}
+	return strings.Join(escaped, ".")
+}
+
+func findHTMLCorrelations(body string) []*correlation {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(body))
+	if err != nil {
+		return nil
+	}
+
+	var result []*correlation
+	add := func(s *goquery.Selection, selector, name, attr string) {
+		value, ok := s.Attr(attr)
+		if !ok || !isDynamicValue(value) || strings.ContainsAny(name, `"\`) {
+			return
+		}
+		sel := fmt.Sprintf(selector, name)
+		result = append(result, &correlation{
+			value:   value,
+			name:    name,
+			extract: fmt.Sprintf("res.html().find(%s).first().attr(%s)", converter.JSString(sel), converter.JSString(attr)),
+		})
+	}
+
+	doc.Find("input[type=hidden][name]").Each(func(_ int, s *goquery.Selection) {
+		name, _ := s.Attr("name")
+		add(s, `input[name="%s"]`, name, "value")
+	})
+	doc.Find("meta[name][content]").Each(func(_ int, s *goquery.Selection) {
+		name, _ := s.Attr("name")
+		if dynamicNameRe.MatchString(name) {
+			add(s, `meta[name="%s"]`, name, "content")
+		}
+	})
+
+	return result
+}
+
+// isDynamicValue reports whether the value looks like a generated one, e.g. a
+// token or an ID, as opposed to a short or a human readable value, which could
+// be sent by the client for unrelated reasons.
+func isDynamicValue(v string) bool {
+	if len(v) < minCorrelatedValueLength || len(v) > maxCorrelatedValueLength || strings.Contains(v, "://") {
+		return false
+	}
+
+	var hasDigit, hasLetter bool
+	for _, r := range v {
+		switch {
+		case unicode.IsSpace(r):
+			return false
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsLetter(r):
+			hasLetter = true
+		}
+	}
+
+	return hasDigit && hasLetter
+}
+
+// correlator keeps track of the dynamic values received so far and replaces
+// them in the requests.
+type correlator struct {
+	// active are the correlations by their value, a value received again
+	// replaces the previous one.
+	active map[string]*correlation
+	// sent are all the requests sent so far, the values sent by the client
+	// before receiving them aren't dynamic.
+	sent      bytes.Buffer
+	variables map[string]struct{}
+}
+
+func newCorrelator() *correlator {
+	c := &correlator{
+		active:    make(map[string]*correlation),
+		variables: make(map[string]struct{}),
+	}
+	for _, reserved := range []string{"res", "http", "group", "sleep", "options"} {
+		c.variables[reserved] = struct{}{}
+	}
+	return c
+}
+
+// sending records the texts of a request sent before its response.
+func (c *correlator) sending(texts ...string) {
+	for _, t := range texts {
+		c.sent.WriteString(t)
+		c.sent.WriteByte('\n')
+	}
+}
+
+// received adds the dynamic values of a response.
+func (c *correlator) received(correlations []*correlation) {
+	sent := c.sent.String()
+	for _, corr := range correlations {
+		if strings.Contains(sent, corr.value) || strings.Contains(sent, url.QueryEscape(corr.value)) {
+			continue
+		}
+		c.active[corr.value] = corr
+	}
+}
+
+type correlationMatch struct {
+	start, end int
+	expr       string
+}
+
+// expression returns the JS expression for the text, with the dynamic values
+// replaced by their variables.
+func (c *correlator) expression(text string) string {
+	values := make([]string, 0, len(c.active))
+	for v := range c.active {
+		if strings.Contains(text, v) || strings.Contains(text, url.QueryEscape(v)) {
+			values = append(values, v)
+		}
+	}
+	if len(values) == 0 {
+		return converter.JSString(text)
+	}
+
+	// prefer the longer values, when they overlap with the shorter ones
+	sort.Slice(values, func(i, j int) bool {
+		if len(values[i]) != len(values[j]) {
+			return len(values[i]) > len(values[j])
+		}
+		return values[i] < values[j]
+	})
+
+	var matches []correlationMatch
+	overlaps := func(start, end int) bool {
+		for _, m := range matches {
+			if start < m.end && m.start < end {
+				return true
+			}
+		}
+		return false
+	}
+	for _, v := range values {
+		corr := c.active[v]
+		patterns := []struct{ value, expr string }{{v, ""}}
+		if escaped := url.QueryEscape(v); escaped != v {
+			patterns = append(patterns, struct{ value, expr string }{escaped, "encodeURIComponent(%s)"})
+		}
+		for _, p := range patterns {
+			for offset := 0; ; {
+				i := strings.Index(text[offset:], p.value)
+				if i < 0 {
+					break
+				}
+				start, end := offset+i, offset+i+len(p.value)
+				offset = end
+				if overlaps(start, end) {
+					continue
+				}
+				expr := c.variable(corr)
+				if p.expr != "" {
+					expr = fmt.Sprintf(p.expr, expr)
+				}
+				matches = append(matches, correlationMatch{start: start, end: end, expr: expr})
+			}
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].start < matches[j].start })
+
+	var parts []string
+	last := 0
+	for _, m := range matches {
+		if m.start > last {
+			parts = append(parts, converter.JSString(text[last:m.start]))
+		}
+		parts = append(parts, m.expr)
+		last = m.end
+	}
+	if last < len(text) {
+		parts = append(parts, converter.JSString(text[last:]))
+	}
+
+	return strings.Join(parts, " + ")
+}
+
+// variable returns the name of the variable of the correlation, and assigns
+// it on the first use.
+func (c *correlator) variable(corr *correlation) string {
+	if corr.variable != "" {
+		return corr.variable
+	}
+
+	base := identifier(corr.name)
+	name := base
+	for i := 2; ; i++ {
+		if _, exists := c.variables[name]; !exists {
+			break
+		}
+		name = base + strconv.Itoa(i)
+	}
+	c.variables[name] = struct{}{}
+	corr.variable = name
+
+	return name
+}
+
+// identifier converts the name to a lowerCamelCase JS identifier.
+func identifier(name string) string {
+	var b strings.Builder
+	upper := false
+	for _, r := range name {
+		switch {
+		case r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r)):
+			upper = b.Len() > 0
+		case upper:
+			b.WriteRune(unicode.ToUpper(r))
+			upper = false
+		case b.Len() == 0:
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	id := b.String()
+	if id == "" || unicode.IsDigit(rune(id[0])) {
+		id = "value" + strings.ToUpper(id[:min(len(id), 1)]) + id[min(len(id), 1):]
+	}
+	if isJSKeyword(id) {
+		id += "Value"
+	}
+
+	return id
+}
+
+func isJSKeyword(id string) bool {
+	switch id {
+	case "break", "case", "catch", "class", "const", "continue", "debugger", "default", "delete",
+		"do", "else", "export", "extends", "false", "finally", "for", "function", "if", "import",
+		"in", "instanceof", "let", "new", "null", "return", "super", "switch", "this", "throw",
+		"true", "try", "typeof", "var", "void", "while", "with", "yield", "await", "enum", "static":
+		return true
+	}
+	return false
+}
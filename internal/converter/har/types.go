@@ -0,0 +1,143 @@
+package har
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// HAR is the top-level object of a HTTP Archive (HAR) 1.2 file, see
+// http://www.softwareishard.com/blog/har-12-spec/ for the specification.
+type HAR struct {
+	Log *Log `json:"log"`
+}
+
+// Log is the root of the exported data.
+type Log struct {
+	Version string   `json:"version"`
+	Creator *Creator `json:"creator"`
+	Browser *Creator `json:"browser,omitempty"`
+	Pages   []Page   `json:"pages,omitempty"`
+	Entries []Entry  `json:"entries"`
+	Comment string   `json:"comment,omitempty"`
+}
+
+// Creator holds the information about the application that created the log.
+type Creator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Comment string `json:"comment,omitempty"`
+}
+
+// Page is a single page of the recording, the entries reference it with their Pageref.
+type Page struct {
+	ID              string    `json:"id"`
+	StartedDateTime time.Time `json:"startedDateTime"`
+	Title           string    `json:"title"`
+	Comment         string    `json:"comment,omitempty"`
+}
+
+// Entry is a single exported HTTP request and its response.
+type Entry struct {
+	Pageref         string    `json:"pageref,omitempty"`
+	StartedDateTime time.Time `json:"startedDateTime"`
+	// Time is the total elapsed time of the request in milliseconds.
+	Time     float64   `json:"time"`
+	Request  *Request  `json:"request"`
+	Response *Response `json:"response"`
+	Comment  string    `json:"comment,omitempty"`
+}
+
+// Request contains the detailed information about a performed request.
+type Request struct {
+	Method      string        `json:"method"`
+	URL         string        `json:"url"`
+	HTTPVersion string        `json:"httpVersion"`
+	Cookies     []Cookie      `json:"cookies"`
+	Headers     []Header      `json:"headers"`
+	QueryString []QueryString `json:"queryString"`
+	PostData    *PostData     `json:"postData,omitempty"`
+	Comment     string        `json:"comment,omitempty"`
+}
+
+// Response contains the detailed information about a response.
+type Response struct {
+	Status      int      `json:"status"`
+	StatusText  string   `json:"statusText"`
+	HTTPVersion string   `json:"httpVersion"`
+	Cookies     []Cookie `json:"cookies"`
+	Headers     []Header `json:"headers"`
+	Content     *Content `json:"content"`
+	RedirectURL string   `json:"redirectURL"`
+	Comment     string   `json:"comment,omitempty"`
+}
+
+// Cookie is a cookie sent with a request or set by a response.
+type Cookie struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Path     string `json:"path,omitempty"`
+	Domain   string `json:"domain,omitempty"`
+	HTTPOnly bool   `json:"httpOnly,omitempty"`
+	Secure   bool   `json:"secure,omitempty"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+// Header is a single request or response header.
+type Header struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	Comment string `json:"comment,omitempty"`
+}
+
+// QueryString is a single parameter of the query string of a request.
+type QueryString struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	Comment string `json:"comment,omitempty"`
+}
+
+// PostData describes the posted data of a request.
+type PostData struct {
+	MimeType string  `json:"mimeType"`
+	Params   []Param `json:"params,omitempty"`
+	Text     string  `json:"text"`
+	Comment  string  `json:"comment,omitempty"`
+}
+
+// Param is a single posted parameter, e.g. from an URL-encoded form.
+type Param struct {
+	Name        string `json:"name"`
+	Value       string `json:"value,omitempty"`
+	FileName    string `json:"fileName,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+	Comment     string `json:"comment,omitempty"`
+}
+
+// Content describes the content of a response.
+type Content struct {
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+// Decode decodes and validates a HAR file.
+func Decode(data []byte) (*HAR, error) {
+	h := &HAR{}
+	if err := json.Unmarshal(data, h); err != nil {
+		return nil, fmt.Errorf("invalid HAR file: %w", err)
+	}
+	if h.Log == nil {
+		return nil, errors.New("invalid HAR file: the log object is missing")
+	}
+	for i, e := range h.Log.Entries {
+		if e.Request == nil {
+			return nil, fmt.Errorf("invalid HAR file: the request of entry %d is missing", i)
+		}
+	}
+
+	return h, nil
+}
@@ -0,0 +1,554 @@
+// Package openapi generates k6 scripts from OpenAPI 3 documents, which
+// exercise each operation of the API with k6/http.
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.k6.io/k6/internal/converter"
+	"go.k6.io/k6/lib"
+)
+
+const (
+	// defaultBaseURL is used when the document doesn't have any servers.
+	defaultBaseURL = "http://localhost"
+
+	formMediaType = "application/x-www-form-urlencoded"
+)
+
+// Options are the options of the conversion.
+type Options struct {
+	// ScriptName and ProjectID are used for the cloud options of the script.
+	ScriptName string
+	ProjectID  string
+}
+
+var (
+	pathParamRe     = regexp.MustCompile(`\{([^{}]+)\}`)
+	statusCodeRe    = regexp.MustCompile(`^[1-5]([0-9]{2}|XX)$`)
+	jsonMediaTypeRe = regexp.MustCompile(`^application/([^;]+\+)?json(;|$)`)
+)
+
+// request is a converted API operation.
+type request struct {
+	group   string
+	comment string
+	code    string
+	check   string
+}
+
+// generator generates the requests for the operations of a document.
+type generator struct {
+	doc      *Document
+	examples *exampleGenerator
+	// usesEncoding is set when the script needs the k6/encoding module for the basic authentication.
+	usesEncoding bool
+}
+
+// Convert converts the OpenAPI document to a k6 script, which sends a request
+// to every operation and checks the documented status codes of its responses.
+func Convert(doc *Document, opts Options) (string, error) {
+	if doc == nil {
+		return "", errors.New("the OpenAPI document is empty")
+	}
+
+	c := &generator{doc: doc, examples: newExampleGenerator(doc)}
+
+	paths := make([]string, 0, len(doc.Paths))
+	for p := range doc.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	var requests []*request
+	for _, p := range paths {
+		item := doc.Paths[p]
+		if item == nil {
+			continue
+		}
+		for _, o := range item.operations() {
+			req, err := c.request(p, item, o.method, o.operation)
+			if err != nil {
+				return "", fmt.Errorf("error converting the %s %s operation: %w", o.method, p, err)
+			}
+			requests = append(requests, req)
+		}
+	}
+
+	if len(requests) == 0 {
+		return "", errors.New("the OpenAPI document has no operations to convert")
+	}
+
+	return c.script(requests, opts), nil
+}
+
+func (c *generator) request(path string, item *PathItem, method string, op *Operation) (*request, error) {
+	params, err := c.parameters(item, op)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		target  = path
+		query   []string
+		headers []converter.Param
+		cookies []converter.Param
+	)
+	for _, p := range params {
+		if p.In != "path" && !p.Required {
+			continue
+		}
+		value, err := c.parameterValue(p)
+		if err != nil {
+			return nil, fmt.Errorf("error generating the value of the %s parameter: %w", p.Name, err)
+		}
+		switch p.In {
+		case "path":
+			target = strings.ReplaceAll(target, "{"+p.Name+"}", url.PathEscape(value))
+		case "query":
+			query = append(query, url.QueryEscape(p.Name)+"="+url.QueryEscape(value))
+		case "header":
+			headers = append(headers, converter.Param{Name: p.Name, Expr: converter.JSString(value)})
+		case "cookie":
+			cookies = append(cookies, converter.Param{Name: p.Name, Expr: converter.JSString(value)})
+		}
+	}
+	// the undocumented path parameters
+	target = pathParamRe.ReplaceAllStringFunc(target, func(string) string { return "1" })
+	if len(query) > 0 {
+		target += "?" + strings.Join(query, "&")
+	}
+
+	body, contentType, err := c.body(op)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		headers = append(headers, converter.Param{Name: "Content-Type", Expr: converter.JSString(contentType)})
+	}
+
+	authHeaders, authQuery := c.security(op)
+	headers = append(headers, authHeaders...)
+	urlExpr := "BASE_URL + " + converter.JSString(target)
+	if authQuery != "" {
+		separator := "?"
+		if len(query) > 0 {
+			separator = "&"
+		}
+		urlExpr = "BASE_URL + " + converter.JSString(target+separator+url.QueryEscape(authQuery)+"=") +
+			" + encodeURIComponent(__ENV.API_KEY)"
+	}
+
+	args := []string{urlExpr}
+	fn, hasBodyArg := converter.HTTPFunction(method)
+	if fn == "request" {
+		args = append([]string{converter.JSString(method)}, args...)
+	}
+	if hasBodyArg {
+		if body == "" {
+			body = "null"
+		}
+		args = append(args, body)
+	}
+	// the requests are tagged with the path template, for avoiding a separate URL tag for each resource
+	args = append(args, converter.RequestParams(headers, cookies, "tags: { name: "+converter.JSString(path)+" }"))
+
+	comment := op.Summary
+	if comment == "" {
+		comment = op.OperationID
+	}
+	if op.Deprecated {
+		comment = strings.TrimSpace(comment + " (deprecated)")
+	}
+
+	group := ""
+	if len(op.Tags) > 0 {
+		group = op.Tags[0]
+	}
+
+	return &request{
+		group:   group,
+		comment: strings.Join(strings.Fields(comment), " "),
+		code:    fmt.Sprintf("http.%s(%s)", fn, strings.Join(args, ", ")),
+		check:   statusCheck(method, path, op.Responses),
+	}, nil
+}
+
+// parameters returns the parameters of the operation, which override the
+// parameters of the path with the same name and location.
+func (c *generator) parameters(item *PathItem, op *Operation) ([]*Parameter, error) {
+	var result []*Parameter
+	index := make(map[string]int)
+	for _, list := range [][]*Parameter{item.Parameters, op.Parameters} {
+		for _, p := range list {
+			if p == nil {
+				continue
+			}
+			resolved, err := c.doc.parameter(p)
+			if err != nil {
+				return nil, err
+			}
+			key := resolved.In + ":" + resolved.Name
+			if i, ok := index[key]; ok {
+				result[i] = resolved
+				continue
+			}
+			index[key] = len(result)
+			result = append(result, resolved)
+		}
+	}
+	return result, nil
+}
+
+func (c *generator) parameterValue(p *Parameter) (string, error) {
+	var value any
+	switch {
+	case p.Example != nil:
+		value = normalize(p.Example)
+	case len(p.Examples) > 0:
+		value = firstExample(p.Examples)
+	default:
+		v, _, err := c.examples.value(p.Schema, 0)
+		if err != nil {
+			return "", err
+		}
+		if v == nil && p.Schema == nil {
+			v = "string"
+		}
+		value = v
+	}
+	return parameterString(value), nil
+}
+
+// parameterString formats the value with the default simple and form styles of the parameters.
+func parameterString(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []any:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = parameterString(item)
+		}
+		return strings.Join(parts, ",")
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		parts := make([]string, 0, 2*len(keys))
+		for _, k := range keys {
+			parts = append(parts, k, parameterString(v[k]))
+		}
+		return strings.Join(parts, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func firstExample(examples map[string]*Example) any {
+	names := make([]string, 0, len(examples))
+	for name, e := range examples {
+		if e != nil && e.Value != nil {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+	return normalize(examples[names[0]].Value)
+}
+
+// body returns the JS expression of the example request body and its content type.
+func (c *generator) body(op *Operation) (string, string, error) {
+	if op.RequestBody == nil {
+		return "", "", nil
+	}
+	rb, err := c.doc.requestBody(op.RequestBody)
+	if err != nil {
+		return "", "", err
+	}
+
+	mediaTypes := make([]string, 0, len(rb.Content))
+	for mt := range rb.Content {
+		mediaTypes = append(mediaTypes, mt)
+	}
+	sort.Strings(mediaTypes)
+
+	// prefer JSON, then forms and then texts
+	for _, kind := range []string{"json", "form", "text"} {
+		for _, mt := range mediaTypes {
+			if mediaTypeKind(mt) != kind || rb.Content[mt] == nil {
+				continue
+			}
+			value, err := c.mediaTypeExample(rb.Content[mt])
+			if err != nil {
+				return "", "", err
+			}
+
+			switch kind {
+			case "json":
+				return "JSON.stringify(" + jsonValue(value) + ")", mt, nil
+			case "form":
+				if _, isObject := value.(map[string]any); isObject {
+					// k6 encodes the objects as forms and sets the content type
+					return jsonValue(value), "", nil
+				}
+			default:
+				if s, isString := value.(string); isString {
+					return converter.JSString(s), mt, nil
+				}
+			}
+		}
+	}
+
+	// the other media types, like the files, don't have generated examples
+	return "", "", nil
+}
+
+func mediaTypeKind(mediaType string) string {
+	mediaType = strings.ToLower(mediaType)
+	switch {
+	case jsonMediaTypeRe.MatchString(mediaType):
+		return "json"
+	case strings.HasPrefix(mediaType, formMediaType):
+		return "form"
+	case strings.HasPrefix(mediaType, "text/"):
+		return "text"
+	default:
+		return ""
+	}
+}
+
+func (c *generator) mediaTypeExample(mt *MediaType) (any, error) {
+	switch {
+	case mt.Example != nil:
+		return normalize(mt.Example), nil
+	case firstExample(mt.Examples) != nil:
+		return firstExample(mt.Examples), nil
+	default:
+		v, _, err := c.examples.value(mt.Schema, 0)
+		return v, err
+	}
+}
+
+// security returns the authentication headers and the name of the API key query
+// parameter of the first security requirement of the operation, the script
+// reads the credentials from the environment variables.
+func (c *generator) security(op *Operation) ([]converter.Param, string) {
+	requirements := c.doc.Security
+	if op.Security != nil {
+		requirements = *op.Security
+	}
+	if len(requirements) == 0 {
+		return nil, ""
+	}
+
+	names := make([]string, 0, len(requirements[0]))
+	for name := range requirements[0] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var (
+		headers []converter.Param
+		query   string
+	)
+	for _, name := range names {
+		scheme := c.doc.Components.SecuritySchemes[name]
+		if scheme == nil {
+			continue
+		}
+		switch strings.ToLower(scheme.Type) {
+		case "http":
+			switch strings.ToLower(scheme.Scheme) {
+			case "bearer":
+				headers = append(headers, converter.Param{Name: "Authorization", Expr: `"Bearer " + __ENV.API_TOKEN`})
+			case "basic":
+				c.usesEncoding = true
+				headers = append(headers, converter.Param{
+					Name: "Authorization",
+					Expr: `"Basic " + encoding.b64encode(__ENV.API_USERNAME + ":" + __ENV.API_PASSWORD)`,
+				})
+			}
+		case "oauth2", "openidconnect":
+			headers = append(headers, converter.Param{Name: "Authorization", Expr: `"Bearer " + __ENV.API_TOKEN`})
+		case "apikey":
+			switch scheme.In {
+			case "header":
+				headers = append(headers, converter.Param{Name: scheme.Name, Expr: "__ENV.API_KEY"})
+			case "query":
+				if query == "" {
+					query = scheme.Name
+				}
+			}
+		}
+	}
+
+	return headers, query
+}
+
+// statusCheck returns the check of the documented status codes of the
+// successful responses, or of all the documented ones when there aren't any.
+func statusCheck(method, path string, responses map[string]*Response) string {
+	var success, documented []string
+	for code := range responses {
+		code = strings.ToUpper(code)
+		if !statusCodeRe.MatchString(code) {
+			// e.g. the default response
+			continue
+		}
+		documented = append(documented, code)
+		if code[0] == '2' || code[0] == '3' {
+			success = append(success, code)
+		}
+	}
+
+	codes := success
+	if len(codes) == 0 {
+		codes = documented
+	}
+	if len(codes) == 0 {
+		codes = []string{"2XX"}
+	}
+	sort.Strings(codes)
+
+	conditions := make([]string, len(codes))
+	for i, code := range codes {
+		if strings.HasSuffix(code, "XX") {
+			low := int(code[0]-'0') * 100
+			conditions[i] = fmt.Sprintf("(r.status >= %d && r.status < %d)", low, low+100)
+		} else {
+			conditions[i] = "r.status === " + code
+		}
+	}
+	condition := strings.Join(conditions, " || ")
+	if len(conditions) == 1 {
+		condition = strings.Trim(condition, "()")
+	}
+
+	name := fmt.Sprintf("%s %s status is %s", method, path, strings.Join(codes, " or "))
+	return fmt.Sprintf("check(res, {\n  %s: (r) => %s,\n});", converter.JSString(name), condition)
+}
+
+// baseURL returns the URL of the first server, with the default values of its variables.
+func (c *generator) baseURL() string {
+	if len(c.doc.Servers) == 0 || c.doc.Servers[0].URL == "" {
+		return defaultBaseURL
+	}
+
+	server := c.doc.Servers[0]
+	u := pathParamRe.ReplaceAllStringFunc(server.URL, func(v string) string {
+		if variable, ok := server.Variables[strings.Trim(v, "{}")]; ok {
+			return variable.Default
+		}
+		return v
+	})
+	if !strings.Contains(u, "://") {
+		// the relative URLs are relative to the location of the document
+		u = defaultBaseURL + "/" + strings.TrimPrefix(u, "/")
+	}
+	return strings.TrimSuffix(u, "/")
+}
+
+// script assembles the k6 script from the converted requests.
+func (c *generator) script(requests []*request, opts Options) string {
+	// the groups in the order of their first operation
+	var groups []string
+	byGroup := make(map[string][]*request)
+	for _, r := range requests {
+		if _, ok := byGroup[r.group]; !ok {
+			groups = append(groups, r.group)
+		}
+		byGroup[r.group] = append(byGroup[r.group], r)
+	}
+	usesGroups := len(groups) > 1 || groups[0] != ""
+
+	w := &converter.ScriptWriter{}
+	if title := strings.TrimSpace(c.doc.Info.Title + " " + c.doc.Info.Version); title != "" {
+		w.Line("// Generated from the OpenAPI document of %s.", strings.Join(strings.Fields(title), " "))
+	} else {
+		w.Line("// Generated from an OpenAPI document.")
+	}
+	if usesGroups {
+		w.Line(`import { check, group } from "k6";`)
+	} else {
+		w.Line(`import { check } from "k6";`)
+	}
+	if c.usesEncoding {
+		w.Line(`import encoding from "k6/encoding";`)
+	}
+	w.Line(`import http from "k6/http";`)
+	w.Line("")
+	w.Line("export const options = {")
+	w.Line("  vus: 1,")
+	w.Line("  iterations: 1,")
+	w.Line("  thresholds: {")
+	w.Line(`    checks: ["rate==1"],`)
+	w.Line("  },")
+	if opts.ProjectID != "" {
+		w.Line("  cloud: {")
+		w.Line("    projectID: %s,", opts.ProjectID)
+		w.Line("    name: %s,", converter.JSString(opts.ScriptName))
+		w.Line("  },")
+	}
+	w.Line("};")
+	w.Line("")
+	w.Line("const BASE_URL = __ENV.BASE_URL || %s;", converter.JSString(c.baseURL()))
+	w.Line("")
+	w.Line("export default function () {")
+	w.Indent++
+	w.Line("let res;")
+
+	for _, g := range groups {
+		w.Line("")
+		if g != "" {
+			// the group names can't contain the group separator
+			w.Line("group(%s, function () {", converter.JSString(strings.ReplaceAll(g, lib.GroupSeparator, ":")))
+			w.Indent++
+		}
+		for i, r := range byGroup[g] {
+			if i > 0 {
+				w.Line("")
+			}
+			if r.comment != "" {
+				w.Line("// %s", r.comment)
+			}
+			w.Line("res = %s;", r.code)
+			w.Line("%s", r.check)
+		}
+		if g != "" {
+			w.Indent--
+			w.Line("});")
+		}
+	}
+
+	w.Indent--
+	w.Line("}")
+
+	return w.String()
+}
+
+// jsonValue returns the example value as an indented JSON, which is also a JS literal.
+func jsonValue(v any) string {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		// the decoded YAML values can always be encoded
+		return "null"
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
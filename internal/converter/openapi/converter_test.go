@@ -0,0 +1,382 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/grafana/sobek"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const petstore = `
+openapi: 3.0.3
+info:
+  title: Petstore
+  version: 1.0.0
+servers:
+  - url: https://{env}.petstore.example.com/v1/
+    variables:
+      env:
+        default: api
+security:
+  - bearerAuth: []
+paths:
+  /pets/{petId}:
+    parameters:
+      - $ref: '#/components/parameters/PetID'
+    get:
+      summary: Info for a specific pet
+      operationId: showPetById
+      tags: [pets]
+      responses:
+        200:
+          description: The pet
+        default:
+          description: An error
+    delete:
+      tags: [pets]
+      deprecated: true
+      security: []
+      responses:
+        '204':
+          description: Deleted
+        '404':
+          description: Not found
+  /pets:
+    get:
+      summary: List all pets
+      tags: [pets]
+      parameters:
+        - name: limit
+          in: query
+          required: true
+          schema:
+            type: integer
+            minimum: 1
+        - name: search
+          in: query
+          schema:
+            type: string
+        - name: X-Request-ID
+          in: header
+          required: true
+          schema:
+            type: string
+            format: uuid
+      responses:
+        '200':
+          description: The pets
+        '2XX':
+          description: Other successes
+    post:
+      tags: [pets]
+      requestBody:
+        $ref: '#/components/requestBodies/NewPet'
+      responses:
+        '201':
+          description: Created
+  /health:
+    head:
+      responses:
+        default:
+          description: Healthy
+  /login:
+    post:
+      security:
+        - apiKey: []
+      requestBody:
+        content:
+          application/x-www-form-urlencoded:
+            schema:
+              type: object
+              properties:
+                user:
+                  type: string
+                  example: admin
+      responses:
+        '500':
+          description: Only errors
+components:
+  securitySchemes:
+    bearerAuth:
+      type: http
+      scheme: bearer
+    apiKey:
+      type: apiKey
+      in: query
+      name: api_key
+  parameters:
+    PetID:
+      name: petId
+      in: path
+      required: true
+      schema:
+        type: string
+      example: my pet
+  requestBodies:
+    NewPet:
+      required: true
+      content:
+        text/plain:
+          schema:
+            type: string
+        application/json:
+          schema:
+            $ref: '#/components/schemas/Pet'
+  schemas:
+    Pet:
+      allOf:
+        - $ref: '#/components/schemas/Node'
+        - type: object
+          required: [name]
+          properties:
+            name:
+              type: string
+              example: doggie
+            birthday:
+              type: string
+              format: date
+            kind:
+              type: [string, "null"]
+              enum: [dog, cat]
+            tags:
+              type: array
+              items:
+                type: string
+    Node:
+      type: object
+      properties:
+        id:
+          type: integer
+          readOnly: true
+        weight:
+          type: number
+          default: 1.5
+        parent:
+          $ref: '#/components/schemas/Node'
+        children:
+          type: array
+          items:
+            $ref: '#/components/schemas/Node'
+`
+
+func TestConvert(t *testing.T) {
+	t.Parallel()
+
+	doc, err := Decode([]byte(petstore))
+	require.NoError(t, err)
+
+	script, err := Convert(doc, Options{})
+	require.NoError(t, err)
+
+	assert.Equal(t, `// Generated from the OpenAPI document of Petstore 1.0.0.
+import { check, group } from "k6";
+import http from "k6/http";
+
+export const options = {
+  vus: 1,
+  iterations: 1,
+  thresholds: {
+    checks: ["rate==1"],
+  },
+};
+
+const BASE_URL = __ENV.BASE_URL || "https://api.petstore.example.com/v1";
+
+export default function () {
+  let res;
+
+  res = http.head(BASE_URL + "/health", {
+    headers: {
+      "Authorization": "Bearer " + __ENV.API_TOKEN,
+    },
+    tags: { name: "/health" },
+  });
+  check(res, {
+    "HEAD /health status is 2XX": (r) => r.status >= 200 && r.status < 300,
+  });
+
+  res = http.post(BASE_URL + "/login?api_key=" + encodeURIComponent(__ENV.API_KEY), {
+    "user": "admin"
+  }, {
+    tags: { name: "/login" },
+  });
+  check(res, {
+    "POST /login status is 500": (r) => r.status === 500,
+  });
+
+  group("pets", function () {
+    // List all pets
+    res = http.get(BASE_URL + "/pets?limit=1", {
+      headers: {
+        "X-Request-ID": "00000000-0000-4000-8000-000000000000",
+        "Authorization": "Bearer " + __ENV.API_TOKEN,
+      },
+      tags: { name: "/pets" },
+    });
+    check(res, {
+      "GET /pets status is 200 or 2XX": (r) => r.status === 200 || (r.status >= 200 && r.status < 300),
+    });
+
+    res = http.post(BASE_URL + "/pets", JSON.stringify({
+      "birthday": "2025-01-01",
+      "children": [],
+      "kind": "dog",
+      "name": "doggie",
+      "tags": [
+        "string"
+      ],
+      "weight": 1.5
+    }), {
+      headers: {
+        "Content-Type": "application/json",
+        "Authorization": "Bearer " + __ENV.API_TOKEN,
+      },
+      tags: { name: "/pets" },
+    });
+    check(res, {
+      "POST /pets status is 201": (r) => r.status === 201,
+    });
+
+    // Info for a specific pet
+    res = http.get(BASE_URL + "/pets/my%20pet", {
+      headers: {
+        "Authorization": "Bearer " + __ENV.API_TOKEN,
+      },
+      tags: { name: "/pets/{petId}" },
+    });
+    check(res, {
+      "GET /pets/{petId} status is 200": (r) => r.status === 200,
+    });
+
+    // (deprecated)
+    res = http.del(BASE_URL + "/pets/my%20pet", null, {
+      tags: { name: "/pets/{petId}" },
+    });
+    check(res, {
+      "DELETE /pets/{petId} status is 204": (r) => r.status === 204,
+    });
+  });
+}
+`, script)
+
+	_, err = sobek.ParseModule("script.js", script, nil)
+	require.NoError(t, err)
+}
+
+func TestConvertCloudOptionsAndBasicAuth(t *testing.T) {
+	t.Parallel()
+
+	doc, err := Decode([]byte(`{
+		"openapi": "3.1.0",
+		"info": {"title": "API"},
+		"servers": [{"url": "/api"}],
+		"components": {"securitySchemes": {"basic": {"type": "http", "scheme": "basic"}}},
+		"paths": {"/items": {"put": {
+			"security": [{"basic": []}],
+			"requestBody": {"content": {"application/merge-patch+json": {"example": {"a": [1, 2]}}}}
+		}}}
+	}`))
+	require.NoError(t, err)
+
+	script, err := Convert(doc, Options{ScriptName: "api.js", ProjectID: "123"})
+	require.NoError(t, err)
+
+	assert.Contains(t, script, `import encoding from "k6/encoding";`)
+	assert.Contains(t, script, "projectID: 123,")
+	assert.Contains(t, script, `name: "api.js",`)
+	assert.Contains(t, script, `const BASE_URL = __ENV.BASE_URL || "http://localhost/api";`)
+	assert.Contains(t, script, `"Content-Type": "application/merge-patch+json",`)
+	assert.Contains(t, script, `"Basic " + encoding.b64encode(__ENV.API_USERNAME + ":" + __ENV.API_PASSWORD)`)
+	assert.NotContains(t, script, "group")
+
+	_, err = sobek.ParseModule("script.js", script, nil)
+	require.NoError(t, err)
+}
+
+func TestConvertErrors(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name, document, expectedErr string
+	}{
+		{
+			name:        "no operations",
+			document:    `{"openapi": "3.0.0", "paths": {}}`,
+			expectedErr: "no operations to convert",
+		},
+		{
+			name: "missing schema",
+			document: `{"openapi": "3.0.0", "paths": {"/": {"post": {"requestBody": {"content": {
+				"application/json": {"schema": {"$ref": "#/components/schemas/Missing"}}
+			}}}}}}`,
+			expectedErr: `error converting the POST / operation: invalid schema reference "#/components/schemas/Missing"`,
+		},
+		{
+			name: "external parameter",
+			document: `{"openapi": "3.0.0", "paths": {"/": {"get": {
+				"parameters": [{"$ref": "common.yaml#/components/parameters/ID"}]
+			}}}}`,
+			expectedErr: `unsupported reference "common.yaml#/components/parameters/ID"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			doc, err := Decode([]byte(tc.document))
+			require.NoError(t, err)
+			_, err = Convert(doc, Options{})
+			require.ErrorContains(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	_, err := Decode([]byte(`swagger: "2.0"`))
+	require.ErrorContains(t, err, "the openapi version field is missing")
+
+	_, err = Decode([]byte(`openapi: 2.0.0`))
+	require.ErrorContains(t, err, `unsupported OpenAPI version "2.0.0"`)
+
+	_, err = Decode([]byte(`[]`))
+	require.ErrorContains(t, err, "invalid OpenAPI document")
+}
+
+func TestExampleValue(t *testing.T) {
+	t.Parallel()
+
+	doc, err := Decode([]byte(`
+openapi: 3.1.0
+components:
+  schemas:
+    Tree:
+      type: object
+      properties:
+        created:
+          example: 2025-02-03T04:05:06Z
+        size:
+          oneOf:
+            - $ref: '#/components/schemas/Tree'
+            - type: integer
+              minimum: 2.5
+        leaves:
+          type: array
+          minItems: 2
+          items:
+            type: boolean
+`))
+	require.NoError(t, err)
+
+	v, ok, err := newExampleGenerator(doc).value(&Schema{Ref: "#/components/schemas/Tree"}, 0)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, map[string]any{
+		"created": "2025-02-03T04:05:06Z",
+		"size":    int64(3),
+		"leaves":  []any{true, true},
+	}, v)
+}
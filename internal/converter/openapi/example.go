@@ -0,0 +1,222 @@
+package openapi
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	// maxRefDepth limits the chains of references to references.
+	maxRefDepth = 32
+	// maxSchemaDepth limits the nesting of the generated example values.
+	maxSchemaDepth = 16
+)
+
+// exampleGenerator generates the example values of the schemas.
+type exampleGenerator struct {
+	doc *Document
+	// refs are the schema references being generated, a reference to one of
+	// them is a cycle, e.g. a tree node with its children.
+	refs map[string]bool
+}
+
+func newExampleGenerator(doc *Document) *exampleGenerator {
+	return &exampleGenerator{doc: doc, refs: make(map[string]bool)}
+}
+
+// value returns the example value of the schema, preferring the documented
+// examples and defaults. It returns false, when the value can't be generated
+// because of a cycle, and it should be left out of its parent.
+func (g *exampleGenerator) value(s *Schema, depth int) (any, bool, error) {
+	if s == nil {
+		return nil, true, nil
+	}
+	if depth > maxSchemaDepth {
+		return nil, false, nil
+	}
+
+	if s.Ref != "" {
+		if g.refs[s.Ref] {
+			return nil, false, nil
+		}
+		resolved, err := g.doc.schema(s)
+		if err != nil {
+			return nil, false, err
+		}
+		g.refs[s.Ref] = true
+		defer delete(g.refs, s.Ref)
+		s = resolved
+	}
+
+	switch {
+	case s.Example != nil:
+		return normalize(s.Example), true, nil
+	case len(s.Examples) > 0:
+		return normalize(s.Examples[0]), true, nil
+	case s.Const != nil:
+		return normalize(s.Const), true, nil
+	case s.Default != nil:
+		return normalize(s.Default), true, nil
+	case len(s.Enum) > 0:
+		return normalize(s.Enum[0]), true, nil
+	case len(s.AllOf) > 0:
+		return g.allOf(s, depth)
+	}
+
+	for _, alternatives := range [][]*Schema{s.OneOf, s.AnyOf} {
+		for _, alt := range alternatives {
+			v, ok, err := g.value(alt, depth+1)
+			if err != nil || ok {
+				return v, ok, err
+			}
+		}
+	}
+
+	switch s.typeName() {
+	case "object":
+		return g.object(s, nil, depth)
+	case "array":
+		item, ok, err := g.value(s.Items, depth+1)
+		if err != nil {
+			return nil, false, err
+		}
+		items := make([]any, 0, max(s.MinItems, 1))
+		for ok && len(items) < cap(items) {
+			items = append(items, item)
+		}
+		return items, true, nil
+	case "string":
+		return stringExample(s.Format), true, nil
+	case "integer":
+		if s.Minimum != nil {
+			return int64(math.Ceil(*s.Minimum)), true, nil
+		}
+		return 0, true, nil
+	case "number":
+		if s.Minimum != nil {
+			return *s.Minimum, true, nil
+		}
+		return 0, true, nil
+	case "boolean":
+		return true, true, nil
+	default:
+		return nil, true, nil
+	}
+}
+
+// allOf merges the example objects of the subschemas and the properties of the schema itself.
+func (g *exampleGenerator) allOf(s *Schema, depth int) (any, bool, error) {
+	merged := make(map[string]any)
+	for _, sub := range s.AllOf {
+		v, ok, err := g.value(sub, depth+1)
+		if err != nil {
+			return nil, false, err
+		}
+		if !ok {
+			continue
+		}
+		obj, isObject := v.(map[string]any)
+		if !isObject {
+			// the allOf of a single non-object schema, e.g. for adding a description to a reference
+			return v, true, nil
+		}
+		for k, pv := range obj {
+			merged[k] = pv
+		}
+	}
+
+	return g.object(s, merged, depth)
+}
+
+func (g *exampleGenerator) object(s *Schema, result map[string]any, depth int) (any, bool, error) {
+	if result == nil {
+		result = make(map[string]any, len(s.Properties))
+	}
+
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		prop := s.Properties[name]
+		if prop == nil {
+			continue
+		}
+		if prop.ReadOnly {
+			// the server sets the read-only properties, like the IDs
+			continue
+		}
+		v, ok, err := g.value(prop, depth+1)
+		if err != nil {
+			return nil, false, err
+		}
+		if ok {
+			result[name] = v
+		}
+	}
+
+	return result, true, nil
+}
+
+func stringExample(format string) string {
+	switch format {
+	case "date-time":
+		return "2025-01-01T00:00:00Z"
+	case "date":
+		return "2025-01-01"
+	case "time":
+		return "00:00:00Z"
+	case "email":
+		return "user@example.com"
+	case "uuid":
+		return "00000000-0000-4000-8000-000000000000"
+	case "uri", "url":
+		return "https://example.com"
+	case "hostname":
+		return "example.com"
+	case "ipv4":
+		return "192.0.2.1"
+	case "ipv6":
+		return "2001:db8::1"
+	case "byte":
+		return "c3RyaW5n"
+	default:
+		return "string"
+	}
+}
+
+// normalize converts the example values decoded from the YAML documents to the JSON values.
+func normalize(v any) any {
+	switch v := v.(type) {
+	case time.Time:
+		// the unquoted dates and timestamps of the YAML documents
+		if v.Hour() == 0 && v.Minute() == 0 && v.Second() == 0 && v.Nanosecond() == 0 {
+			return v.Format(time.DateOnly)
+		}
+		return v.Format(time.RFC3339Nano)
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for k, item := range v {
+			result[k] = normalize(item)
+		}
+		return result
+	case map[any]any:
+		result := make(map[string]any, len(v))
+		for k, item := range v {
+			result[fmt.Sprint(k)] = normalize(item)
+		}
+		return result
+	case []any:
+		result := make([]any, len(v))
+		for i, item := range v {
+			result[i] = normalize(item)
+		}
+		return result
+	default:
+		return v
+	}
+}
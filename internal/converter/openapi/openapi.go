@@ -0,0 +1,252 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Document is the subset of an OpenAPI 3 document needed for generating scripts,
+// see https://spec.openapis.org/oas/v3.1.0 for the specification.
+type Document struct {
+	OpenAPI    string                `yaml:"openapi"`
+	Info       Info                  `yaml:"info"`
+	Servers    []Server              `yaml:"servers"`
+	Paths      map[string]*PathItem  `yaml:"paths"`
+	Components Components            `yaml:"components"`
+	Security   []map[string][]string `yaml:"security"`
+}
+
+// Info is the metadata of the API.
+type Info struct {
+	Title   string `yaml:"title"`
+	Version string `yaml:"version"`
+}
+
+// Server is a server of the API, whose URL can contain variables.
+type Server struct {
+	URL       string                    `yaml:"url"`
+	Variables map[string]ServerVariable `yaml:"variables"`
+}
+
+// ServerVariable is a variable of a server URL.
+type ServerVariable struct {
+	Default string `yaml:"default"`
+}
+
+// Components holds the reusable objects of the document.
+type Components struct {
+	Schemas         map[string]*Schema         `yaml:"schemas"`
+	Parameters      map[string]*Parameter      `yaml:"parameters"`
+	RequestBodies   map[string]*RequestBody    `yaml:"requestBodies"`
+	Responses       map[string]*Response       `yaml:"responses"`
+	SecuritySchemes map[string]*SecurityScheme `yaml:"securitySchemes"`
+}
+
+// PathItem holds the operations of a single path.
+type PathItem struct {
+	Parameters []*Parameter `yaml:"parameters"`
+	Get        *Operation   `yaml:"get"`
+	Put        *Operation   `yaml:"put"`
+	Post       *Operation   `yaml:"post"`
+	Delete     *Operation   `yaml:"delete"`
+	Options    *Operation   `yaml:"options"`
+	Head       *Operation   `yaml:"head"`
+	Patch      *Operation   `yaml:"patch"`
+	Trace      *Operation   `yaml:"trace"`
+}
+
+// operations returns the operations of the path by their method, in the order of the specification.
+func (p *PathItem) operations() []methodOperation {
+	var result []methodOperation
+	for _, o := range []methodOperation{
+		{"GET", p.Get}, {"PUT", p.Put}, {"POST", p.Post}, {"DELETE", p.Delete},
+		{"OPTIONS", p.Options}, {"HEAD", p.Head}, {"PATCH", p.Patch}, {"TRACE", p.Trace},
+	} {
+		if o.operation != nil {
+			result = append(result, o)
+		}
+	}
+	return result
+}
+
+type methodOperation struct {
+	method    string
+	operation *Operation
+}
+
+// Operation is a single API operation on a path.
+type Operation struct {
+	OperationID string                 `yaml:"operationId"`
+	Summary     string                 `yaml:"summary"`
+	Tags        []string               `yaml:"tags"`
+	Parameters  []*Parameter           `yaml:"parameters"`
+	RequestBody *RequestBody           `yaml:"requestBody"`
+	Responses   map[string]*Response   `yaml:"responses"`
+	Deprecated  bool                   `yaml:"deprecated"`
+	Security    *[]map[string][]string `yaml:"security"`
+}
+
+// Parameter is a path, query, header or cookie parameter of an operation.
+type Parameter struct {
+	Ref      string              `yaml:"$ref"`
+	Name     string              `yaml:"name"`
+	In       string              `yaml:"in"`
+	Required bool                `yaml:"required"`
+	Schema   *Schema             `yaml:"schema"`
+	Example  any                 `yaml:"example"`
+	Examples map[string]*Example `yaml:"examples"`
+}
+
+// RequestBody is the request body of an operation.
+type RequestBody struct {
+	Ref      string                `yaml:"$ref"`
+	Required bool                  `yaml:"required"`
+	Content  map[string]*MediaType `yaml:"content"`
+}
+
+// Response is a documented response of an operation.
+type Response struct {
+	Ref         string `yaml:"$ref"`
+	Description string `yaml:"description"`
+}
+
+// MediaType is the schema and the examples of a content type.
+type MediaType struct {
+	Schema   *Schema             `yaml:"schema"`
+	Example  any                 `yaml:"example"`
+	Examples map[string]*Example `yaml:"examples"`
+}
+
+// Example is a named example value.
+type Example struct {
+	Value any `yaml:"value"`
+}
+
+// SecurityScheme is a security scheme of the API.
+type SecurityScheme struct {
+	Type   string `yaml:"type"`
+	Scheme string `yaml:"scheme"`
+	Name   string `yaml:"name"`
+	In     string `yaml:"in"`
+}
+
+// Schema is the subset of a JSON schema needed for generating example values.
+type Schema struct {
+	Ref        string             `yaml:"$ref"`
+	Type       any                `yaml:"type"` // a string, or a list of strings since OpenAPI 3.1
+	Format     string             `yaml:"format"`
+	Properties map[string]*Schema `yaml:"properties"`
+	Required   []string           `yaml:"required"`
+	Items      *Schema            `yaml:"items"`
+	ReadOnly   bool               `yaml:"readOnly"`
+	Enum       []any              `yaml:"enum"`
+	Const      any                `yaml:"const"`
+	Example    any                `yaml:"example"`
+	Examples   []any              `yaml:"examples"`
+	Default    any                `yaml:"default"`
+	AllOf      []*Schema          `yaml:"allOf"`
+	OneOf      []*Schema          `yaml:"oneOf"`
+	AnyOf      []*Schema          `yaml:"anyOf"`
+	Minimum    *float64           `yaml:"minimum"`
+	MinItems   int                `yaml:"minItems"`
+}
+
+// typeName returns the type of the schema, the first non-null one of the
+// OpenAPI 3.1 type lists.
+func (s *Schema) typeName() string {
+	switch t := s.Type.(type) {
+	case string:
+		return t
+	case []any:
+		for _, v := range t {
+			if name, ok := v.(string); ok && name != "null" {
+				return name
+			}
+		}
+	}
+
+	switch {
+	case len(s.Properties) > 0:
+		return "object"
+	case s.Items != nil:
+		return "array"
+	}
+	return ""
+}
+
+// Decode decodes an OpenAPI 3 document in the YAML or the JSON format.
+func Decode(data []byte) (*Document, error) {
+	doc := &Document{}
+	if err := yaml.Unmarshal(data, doc); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+	if doc.OpenAPI == "" {
+		return nil, errors.New("invalid OpenAPI document: the openapi version field is missing, " +
+			"only OpenAPI 3 documents are supported")
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		return nil, fmt.Errorf("unsupported OpenAPI version %q, only OpenAPI 3 documents are supported", doc.OpenAPI)
+	}
+
+	return doc, nil
+}
+
+// refName returns the name of the component referenced by a local reference,
+// e.g. Pet for #/components/schemas/Pet.
+func refName(ref, kind string) (string, error) {
+	prefix := "#/components/" + kind + "/"
+	if !strings.HasPrefix(ref, prefix) {
+		return "", fmt.Errorf("unsupported reference %q, only the references to #/components/%s are supported", ref, kind)
+	}
+	// the JSON pointer escaping
+	name := strings.NewReplacer("~1", "/", "~0", "~").Replace(strings.TrimPrefix(ref, prefix))
+	return name, nil
+}
+
+func (doc *Document) parameter(p *Parameter) (*Parameter, error) {
+	for depth := 0; p.Ref != ""; depth++ {
+		name, err := refName(p.Ref, "parameters")
+		if err != nil {
+			return nil, err
+		}
+		resolved, ok := doc.Components.Parameters[name]
+		if !ok || depth > maxRefDepth {
+			return nil, fmt.Errorf("invalid parameter reference %q", p.Ref)
+		}
+		p = resolved
+	}
+	return p, nil
+}
+
+func (doc *Document) requestBody(b *RequestBody) (*RequestBody, error) {
+	for depth := 0; b.Ref != ""; depth++ {
+		name, err := refName(b.Ref, "requestBodies")
+		if err != nil {
+			return nil, err
+		}
+		resolved, ok := doc.Components.RequestBodies[name]
+		if !ok || depth > maxRefDepth {
+			return nil, fmt.Errorf("invalid request body reference %q", b.Ref)
+		}
+		b = resolved
+	}
+	return b, nil
+}
+
+func (doc *Document) schema(s *Schema) (*Schema, error) {
+	for depth := 0; s.Ref != ""; depth++ {
+		name, err := refName(s.Ref, "schemas")
+		if err != nil {
+			return nil, err
+		}
+		resolved, ok := doc.Components.Schemas[name]
+		if !ok || depth > maxRefDepth {
+			return nil, fmt.Errorf("invalid schema reference %q", s.Ref)
+		}
+		s = resolved
+	}
+	return s, nil
+}
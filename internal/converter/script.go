@@ -0,0 +1,114 @@
+// Package converter contains the helpers shared by the converters generating
+// k6 scripts, e.g. from HAR recordings or OpenAPI documents.
+package converter
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// HTTPFunction returns the k6/http function for the method, and whether it
+// has a body argument. The request() function, which is returned for the
+// other methods, takes the method as its first argument.
+func HTTPFunction(method string) (string, bool) {
+	switch method {
+	case http.MethodGet:
+		return "get", false
+	case http.MethodHead:
+		return "head", false
+	case http.MethodPost:
+		return "post", true
+	case http.MethodPut:
+		return "put", true
+	case http.MethodPatch:
+		return "patch", true
+	case http.MethodDelete:
+		return "del", true
+	case http.MethodOptions:
+		return "options", true
+	default:
+		return "request", true
+	}
+}
+
+// Param is a header or a cookie of a request, with the JS expression of its value.
+type Param struct {
+	Name string
+	Expr string
+}
+
+// RequestParams returns the params argument of a request with the headers and
+// the cookies, followed by the given extra properties, e.g. the tags.
+func RequestParams(headers, cookies []Param, extra ...string) string {
+	var p strings.Builder
+	p.WriteString("{\n")
+	for _, section := range []struct {
+		name   string
+		params []Param
+	}{{"headers", headers}, {"cookies", cookies}} {
+		if len(section.params) == 0 {
+			continue
+		}
+		fmt.Fprintf(&p, "  %s: {\n", section.name)
+		for _, h := range section.params {
+			fmt.Fprintf(&p, "    %s: %s,\n", JSString(h.Name), h.Expr)
+		}
+		p.WriteString("  },\n")
+	}
+	for _, e := range extra {
+		fmt.Fprintf(&p, "  %s,\n", e)
+	}
+	p.WriteString("}")
+	return p.String()
+}
+
+// ScriptWriter writes the lines of a script with the current indentation.
+type ScriptWriter struct {
+	strings.Builder
+	Indent int
+}
+
+// Line writes the formatted line, the args are optional. The multiline code,
+// like the params of the requests, is indented too.
+func (w *ScriptWriter) Line(format string, args ...any) {
+	if format == "" {
+		w.WriteString("\n")
+		return
+	}
+
+	prefix := strings.Repeat("  ", w.Indent)
+	text := format
+	if len(args) > 0 {
+		text = fmt.Sprintf(format, args...)
+	}
+	w.WriteString(prefix + strings.ReplaceAll(text, "\n", "\n"+prefix) + "\n")
+}
+
+// JSString returns the string as a JS string literal.
+func JSString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == '\u2028' || r == '\u2029' || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,70 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPFunction(t *testing.T) {
+	t.Parallel()
+
+	for method, expected := range map[string]struct {
+		fn      string
+		hasBody bool
+	}{
+		"GET":    {"get", false},
+		"HEAD":   {"head", false},
+		"POST":   {"post", true},
+		"DELETE": {"del", true},
+		"TRACE":  {"request", true},
+	} {
+		fn, hasBody := HTTPFunction(method)
+		assert.Equal(t, expected.fn, fn, method)
+		assert.Equal(t, expected.hasBody, hasBody, method)
+	}
+}
+
+func TestRequestParams(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "{\n}", RequestParams(nil, nil))
+	assert.Equal(t, `{
+  headers: {
+    "Accept": "*/*",
+  },
+  cookies: {
+    "session": token,
+  },
+  tags: { name: "/users" },
+}`, RequestParams(
+		[]Param{{Name: "Accept", Expr: `"*/*"`}},
+		[]Param{{Name: "session", Expr: "token"}},
+		`tags: { name: "/users" }`,
+	))
+}
+
+func TestScriptWriter(t *testing.T) {
+	t.Parallel()
+
+	w := &ScriptWriter{}
+	w.Line("export default function () {")
+	w.Indent++
+	w.Line("http.get(%s, {\n  tags: {},\n});", JSString("https://test.k6.io"))
+	w.Indent--
+	w.Line("}")
+	w.Line("")
+	assert.Equal(t, `export default function () {
+  http.get("https://test.k6.io", {
+    tags: {},
+  });
+}
+
+`, w.String())
+}
+
+func TestJSString(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, `"a\"b\\c\n\u0000\u2028"`, JSString("a\"b\\c\n\x00\u2028"))
+}
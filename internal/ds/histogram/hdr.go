@@ -3,6 +3,7 @@ package histogram
 import (
 	"math"
 	"math/bits"
+	"slices"
 )
 
 const (
@@ -32,15 +33,18 @@ type Hdr struct {
 	// Buckets stores the counters for each bin of the histogram.
 	// It does not include counters for the untrackable values,
 	// because they contain exception cases and require to be tracked in a dedicated way.
-	Buckets map[uint32]uint32
+	//
+	// The counters are 64-bit, so they don't wrap around on the long tests,
+	// e.g. of the trend sinks, which count all of the values of the test.
+	Buckets map[uint32]uint64
 
 	// ExtraLowBucket counts occurrences of observed values smaller
 	// than the minimum trackable value.
-	ExtraLowBucket uint32
+	ExtraLowBucket uint64
 
 	// ExtraHighBucket counts occurrences of observed values bigger
 	// than the maximum trackable value.
-	ExtraHighBucket uint32
+	ExtraHighBucket uint64
 
 	// Max is the absolute observed maximum value.
 	Max float64
@@ -52,18 +56,22 @@ type Hdr struct {
 	Sum float64
 
 	// Count is counts the amount of observed values.
-	Count uint32
+	Count uint64
 
 	// MinimumResolution represents resolution used by Hdr.
 	// In principle, it is a multiplier factor for the tracked values.
 	MinimumResolution float64
+
+	// sortedIndexes are the sorted indexes of the buckets, which are sorted by
+	// ValueAt() only when a new bucket was added since the last time.
+	sortedIndexes []uint32
 }
 
 // NewHdr creates a new Hdr histogram with default settings.
 func NewHdr() *Hdr {
 	return &Hdr{
 		MinimumResolution: defaultMinimumResolution,
-		Buckets:           make(map[uint32]uint32),
+		Buckets:           make(map[uint32]uint64),
 		Max:               -math.MaxFloat64,
 		Min:               math.MaxFloat64,
 	}
@@ -74,6 +82,68 @@ func (h *Hdr) Add(v float64) {
 	h.addToBucket(v)
 }
 
+// ValueAt returns an estimation of the observed value with the given 0-based
+// rank, i.e. the value that would be at that index if all of the observed
+// values were sorted.
+//
+// The estimation is the middle point of the bucket where the value was counted,
+// limited by the observed minimum and maximum values. So, for values tracked
+// with the default resolution, the error is at most the bigger of 1/256 (~0.4%)
+// of the actual value or half of the MinimumResolution.
+func (h *Hdr) ValueAt(rank uint64) float64 {
+	if h.Count == 0 {
+		return 0
+	}
+
+	seen := h.ExtraLowBucket
+	if rank < seen {
+		return h.Min
+	}
+
+	if h.sortedIndexes == nil {
+		h.sortedIndexes = make([]uint32, 0, len(h.Buckets))
+		for index := range h.Buckets {
+			h.sortedIndexes = append(h.sortedIndexes, index)
+		}
+		slices.Sort(h.sortedIndexes)
+	}
+
+	for _, index := range h.sortedIndexes {
+		seen += h.Buckets[index]
+		if rank < seen {
+			v := bucketMidpoint(index) * h.MinimumResolution
+			return math.Max(h.Min, math.Min(h.Max, v))
+		}
+	}
+
+	return h.Max
+}
+
+// bucketMidpoint returns the middle point of the range of values that
+// resolveBucketIndex() maps to the provided bucket index. It is the inverse of
+// resolveBucketIndex(), so the returned value is not multiplied by the
+// resolution.
+func bucketMidpoint(index uint32) float64 {
+	const k = 7
+
+	if index < 256 {
+		// the bucket contains the values in the (index-1, index] range
+		if index == 0 {
+			return 0
+		}
+		return float64(index) - 0.5
+	}
+
+	// index = (nkdiff << k) + (upscaled >> nkdiff), where (upscaled >> nkdiff)
+	// is always in the [128, 256) range, so the bucket contains all of the
+	// values in the (sub<<nkdiff - 1, (sub+1)<<nkdiff - 1] range.
+	nkdiff := uint64(index>>k) - 1
+	sub := uint64(index) - nkdiff<<k
+	lower := float64(sub<<nkdiff) - 1
+	upper := float64((sub+1)<<nkdiff) - 1
+	return (lower + upper) / 2
+}
+
 // addToBucket increments the counter of the bucket of the provided value.
 // If the value is lower or higher than the trackable limits
 // then it is counted into specific buckets. All the stats are also updated accordingly.
@@ -99,7 +169,11 @@ func (h *Hdr) addToBucket(v float64) {
 		return
 	}
 
-	h.Buckets[resolveBucketIndex(v)]++
+	index := resolveBucketIndex(v)
+	if _, ok := h.Buckets[index]; !ok {
+		h.sortedIndexes = nil
+	}
+	h.Buckets[index]++
 }
 
 // resolveBucketIndex returns the index
@@ -62,7 +62,7 @@ func TestHistogramAddWithSimpleValues(t *testing.T) {
 		{
 			vals: []float64{0},
 			exp: &Hdr{
-				Buckets:         map[uint32]uint32{0: 1},
+				Buckets:         map[uint32]uint64{0: 1},
 				ExtraLowBucket:  0,
 				ExtraHighBucket: 0,
 				Max:             0,
@@ -74,7 +74,7 @@ func TestHistogramAddWithSimpleValues(t *testing.T) {
 		{
 			vals: []float64{8, 5},
 			exp: &Hdr{
-				Buckets:         map[uint32]uint32{5: 1, 8: 1},
+				Buckets:         map[uint32]uint64{5: 1, 8: 1},
 				ExtraLowBucket:  0,
 				ExtraHighBucket: 0,
 				Max:             8,
@@ -86,7 +86,7 @@ func TestHistogramAddWithSimpleValues(t *testing.T) {
 		{
 			vals: []float64{8, 9, 10, 5},
 			exp: &Hdr{
-				Buckets:         map[uint32]uint32{8: 1, 9: 1, 10: 1, 5: 1},
+				Buckets:         map[uint32]uint64{8: 1, 9: 1, 10: 1, 5: 1},
 				ExtraLowBucket:  0,
 				ExtraHighBucket: 0,
 				Max:             10,
@@ -98,7 +98,7 @@ func TestHistogramAddWithSimpleValues(t *testing.T) {
 		{
 			vals: []float64{100, 101},
 			exp: &Hdr{
-				Buckets:         map[uint32]uint32{100: 1, 101: 1},
+				Buckets:         map[uint32]uint64{100: 1, 101: 1},
 				ExtraLowBucket:  0,
 				ExtraHighBucket: 0,
 				Max:             101,
@@ -110,7 +110,7 @@ func TestHistogramAddWithSimpleValues(t *testing.T) {
 		{
 			vals: []float64{101, 100},
 			exp: &Hdr{
-				Buckets:         map[uint32]uint32{100: 1, 101: 1},
+				Buckets:         map[uint32]uint64{100: 1, 101: 1},
 				ExtraLowBucket:  0,
 				ExtraHighBucket: 0,
 				Max:             101,
@@ -147,7 +147,7 @@ func TestHistogramAddWithUntrackables(t *testing.T) {
 	}
 
 	exp := &Hdr{
-		Buckets:           map[uint32]uint32{1: 1, 5: 1},
+		Buckets:           map[uint32]uint64{1: 1, 5: 1},
 		ExtraLowBucket:    1,
 		ExtraHighBucket:   1,
 		Max:               9223372036854779046,
@@ -169,7 +169,7 @@ func TestHistogramAddWithMultipleOccurances(t *testing.T) {
 	}
 
 	exp := &Hdr{
-		Buckets:         map[uint32]uint32{52: 1, 104: 4},
+		Buckets:         map[uint32]uint64{52: 1, 104: 4},
 		Max:             103.6,
 		Min:             51.8,
 		ExtraLowBucket:  0,
@@ -190,7 +190,7 @@ func TestHistogramAddWithNegativeNum(t *testing.T) {
 	exp := &Hdr{
 		Max:               -2.42314,
 		Min:               -2.42314,
-		Buckets:           map[uint32]uint32{},
+		Buckets:           map[uint32]uint64{},
 		ExtraLowBucket:    1,
 		ExtraHighBucket:   0,
 		Sum:               -2.42314,
@@ -208,7 +208,7 @@ func TestHistogramAddWithMultipleNegativeNums(t *testing.T) {
 	}
 
 	exp := &Hdr{
-		Buckets:           map[uint32]uint32{},
+		Buckets:           map[uint32]uint64{},
 		ExtraLowBucket:    3,
 		ExtraHighBucket:   0,
 		Max:               -0.001,
@@ -228,7 +228,7 @@ func TestHistogramAddWithZeroToOneValues(t *testing.T) {
 	}
 
 	exp := &Hdr{
-		Buckets:           map[uint32]uint32{1: 1, 3: 1, 13: 1, 51: 1, 250: 1, 391: 2, 456: 1},
+		Buckets:           map[uint32]uint64{1: 1, 3: 1, 13: 1, 51: 1, 250: 1, 391: 2, 456: 1},
 		ExtraLowBucket:    0,
 		ExtraHighBucket:   0,
 		Max:               .803,
@@ -245,7 +245,7 @@ func TestNewHistogram(t *testing.T) {
 
 	h := NewHdr()
 	exp := &Hdr{
-		Buckets:           map[uint32]uint32{},
+		Buckets:           map[uint32]uint64{},
 		ExtraLowBucket:    0,
 		ExtraHighBucket:   0,
 		Max:               -math.MaxFloat64,
@@ -255,3 +255,75 @@ func TestNewHistogram(t *testing.T) {
 	}
 	assert.Equal(t, exp, h)
 }
+
+func TestBucketMidpoint(t *testing.T) {
+	t.Parallel()
+
+	for _, v := range []float64{0, 0.12, 1, 12.5, 255, 256, 282.29, 1029, 39751, 183000, 1 << 30, math.MaxInt32} {
+		index := resolveBucketIndex(v)
+		mid := bucketMidpoint(index)
+		// the midpoint has to be in the same bucket as the value
+		assert.Equal(t, index, resolveBucketIndex(mid), v)
+		// and within the promised relative error of it
+		assert.InDelta(t, v, mid, math.Max(v/256, 0.5), v)
+	}
+}
+
+func TestHistogramValueAt(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Empty", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, 0.0, NewHdr().ValueAt(0)) //nolint:testifylint
+	})
+
+	t.Run("Ranks", func(t *testing.T) {
+		t.Parallel()
+		h := NewHdr()
+		for _, v := range []float64{350, 0.5, 12, 12.3, 5000} {
+			h.Add(v)
+		}
+		assert.Equal(t, 0.5, h.ValueAt(0)) //nolint:testifylint // clamped to the min
+		assert.InEpsilon(t, 12, h.ValueAt(1), 1.0/256)
+		assert.InEpsilon(t, 12.3, h.ValueAt(2), 1.0/256)
+		assert.InEpsilon(t, 350, h.ValueAt(3), 1.0/256)
+		assert.InEpsilon(t, 5000, h.ValueAt(4), 1.0/256)
+		assert.Equal(t, 5000.0, h.ValueAt(10)) //nolint:testifylint // past the last rank
+	})
+
+	t.Run("Untrackables", func(t *testing.T) {
+		t.Parallel()
+		h := NewHdr()
+		h.Add(-10)
+		h.Add(5)
+		h.Add(math.MaxInt64)
+		assert.Equal(t, -10.0, h.ValueAt(0)) //nolint:testifylint
+		assert.InEpsilon(t, 5, h.ValueAt(1), 1.0/256)
+		assert.Equal(t, float64(math.MaxInt64), h.ValueAt(2)) //nolint:testifylint
+	})
+	t.Run("AddAfterValueAt", func(t *testing.T) {
+		t.Parallel()
+		h := NewHdr()
+		h.Add(10)
+		h.Add(30)
+		assert.InEpsilon(t, 30, h.ValueAt(1), 1.0/256)
+		// the new bucket is between the others
+		h.Add(20)
+		assert.InEpsilon(t, 20, h.ValueAt(1), 1.0/256)
+		assert.InEpsilon(t, 30, h.ValueAt(2), 1.0/256)
+	})
+
+	t.Run("MoreThanUint32", func(t *testing.T) {
+		t.Parallel()
+		h := NewHdr()
+		h.Add(10)
+		h.Add(20)
+		// as if the values were added many times, on a long test
+		h.Buckets[resolveBucketIndex(10/h.MinimumResolution)] = math.MaxUint32
+		h.Buckets[resolveBucketIndex(20/h.MinimumResolution)] = math.MaxUint32
+		h.Count = 2 * math.MaxUint32
+		assert.InEpsilon(t, 10, h.ValueAt(math.MaxUint32-1), 1.0/256)
+		assert.InEpsilon(t, 20, h.ValueAt(math.MaxUint32), 1.0/256)
+		assert.InEpsilon(t, 20, h.ValueAt(2*math.MaxUint32-1), 1.0/256)
+	})
+}
@@ -18,6 +18,10 @@ const (
 	IterEnd
 	// Exit is emitted when the k6 process is about to exit.
 	Exit
+	// IterError is emitted on the global event system when an iteration of a
+	// VU ends with an error. Its data is the same as the one of IterEnd, but
+	// the VUs don't wait for it to be processed.
+	IterError
 )
 
 //nolint:gochecknoglobals
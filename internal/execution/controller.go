@@ -25,6 +25,15 @@ type Controller interface {
 	Subscribe(eventID string) (wait func() error)
 }
 
+// AbortNotifier can optionally be implemented by controllers that can detect
+// on their own that the test run has to be aborted, e.g. because another
+// instance of a distributed test run was lost.
+type AbortNotifier interface {
+	// Aborted returns a channel that receives the reason for aborting the test
+	// run, if that ever happens.
+	Aborted() <-chan error
+}
+
 // SignalAndWait implements a rendezvous point / barrier, a way for all
 // instances to reach the same execution point and wait for each other, before
 // they all ~simultaneously continue with the execution.
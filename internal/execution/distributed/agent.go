@@ -0,0 +1,268 @@
+package distributed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"go.k6.io/k6/errext"
+	"go.k6.io/k6/errext/exitcodes"
+	"go.k6.io/k6/internal/execution"
+)
+
+// doneTimeout is how long an agent waits for the coordinator to acknowledge
+// that it has finished its execution.
+const doneTimeout = 10 * time.Second
+
+// AgentController implements the execution.Controller interface for a single
+// agent instance in a distributed test run. All of its methods are proxied to
+// the CoordinatorServer, which ensures that they are synchronized between all
+// of the instances.
+type AgentController struct {
+	ctx        context.Context //nolint:containedctx
+	cancel     context.CancelCauseFunc
+	client     *http.Client
+	baseURL    string
+	token      string
+	instanceID uint32
+	logger     logrus.FieldLogger
+
+	abortOnce     sync.Once
+	aborted       chan error
+	stopHeartbeat chan struct{}
+	stopOnce      sync.Once
+}
+
+var _ execution.AbortNotifier = &AgentController{}
+
+// Register connects to the coordinator at the given address and registers a
+// new agent instance there. It returns an AgentController for that instance
+// and all of the data needed to run its part of the test. The token, if it
+// isn't empty, is sent with all of the requests to the coordinator.
+func Register(
+	ctx context.Context, client *http.Client, coordinatorAddress, token string, logger logrus.FieldLogger,
+) (*AgentController, *InstanceData, error) {
+	baseURL := coordinatorAddress
+	if !strings.Contains(baseURL, "://") {
+		baseURL = "http://" + baseURL
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	ac := &AgentController{
+		ctx:           ctx,
+		cancel:        cancel,
+		client:        client,
+		baseURL:       strings.TrimSuffix(baseURL, "/"),
+		token:         token,
+		aborted:       make(chan error, 1),
+		stopHeartbeat: make(chan struct{}),
+	}
+
+	data := &InstanceData{}
+	if err := ac.call(registerPath, struct{}{}, data); err != nil {
+		cancel(nil)
+		return nil, nil, fmt.Errorf("could not register with the coordinator at %s: %w", ac.baseURL, err)
+	}
+	ac.instanceID = data.InstanceID
+	ac.logger = logger.WithField("instance", data.InstanceID)
+
+	agentTimeout := data.AgentTimeout
+	if agentTimeout <= 0 {
+		agentTimeout = DefaultAgentTimeout
+	}
+	go ac.heartbeat(agentTimeout)
+
+	return ac, data, nil
+}
+
+// heartbeat periodically lets the coordinator know that this instance is still
+// alive, until Done is called. It aborts the instance if the coordinator reports
+// that the test run was aborted, or if it couldn't be reached for agentTimeout.
+func (ac *AgentController) heartbeat(agentTimeout time.Duration) {
+	ticker := time.NewTicker(agentTimeout / heartbeatsPerTimeout)
+	defer ticker.Stop()
+
+	lastSuccess := time.Now()
+	for {
+		select {
+		case <-ac.ctx.Done():
+			return
+		case <-ac.stopHeartbeat:
+			return
+		case now := <-ticker.C:
+			ctx, cancel := context.WithTimeout(ac.ctx, agentTimeout/heartbeatsPerTimeout)
+			resp := &heartbeatResponse{}
+			err := ac.doCall(ctx, heartbeatPath, heartbeatRequest{InstanceID: ac.instanceID}, resp)
+			cancel()
+
+			switch {
+			case err == nil && resp.Error != "":
+				ac.abort(fmt.Errorf("the distributed test run was aborted because of %s", resp.Error))
+				return
+			case err == nil:
+				lastSuccess = now
+			case now.Sub(lastSuccess) > agentTimeout:
+				ac.abort(fmt.Errorf("the coordinator couldn't be reached for %s: %w", agentTimeout, err))
+				return
+			default:
+				ac.logger.WithError(err).Debug("Could not send a heartbeat to the coordinator")
+			}
+		}
+	}
+}
+
+// abort aborts the part of the test run of this instance with the given error,
+// which is also returned by all of the pending and future controller calls.
+func (ac *AgentController) abort(err error) {
+	ac.abortOnce.Do(func() {
+		ac.logger.WithError(err).Error("Aborting the test run")
+		err = errext.WithExitCodeIfNone(err, exitcodes.ExternalAbort)
+		ac.aborted <- err
+		ac.cancel(err)
+	})
+}
+
+// Aborted implements the execution.AbortNotifier interface. The returned
+// channel receives an error if the coordinator reported that the test run was
+// aborted, or if it couldn't be reached for longer than the agent timeout.
+func (ac *AgentController) Aborted() <-chan error {
+	return ac.aborted
+}
+
+// InstanceID returns the ID that the coordinator assigned to this instance.
+func (ac *AgentController) InstanceID() uint32 {
+	return ac.instanceID
+}
+
+// GetOrCreateData asks the coordinator for the data with the given ID. If this
+// is the first instance that asked for it, the callback is executed locally
+// and its result is sent back to the coordinator, so all other instances can
+// receive it.
+func (ac *AgentController) GetOrCreateData(dataID string, callback func() ([]byte, error)) ([]byte, error) {
+	ac.logger.Debugf("GetOrCreateData(%s)", dataID)
+
+	resp := &dataResponse{}
+	if err := ac.call(dataPath, dataRequest{InstanceID: ac.instanceID, DataID: dataID}, resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Create {
+		if resp.Error != "" {
+			return nil, errors.New(resp.Error)
+		}
+		return resp.Data, nil
+	}
+
+	ac.logger.Debugf("Creating the data with ID '%s'...", dataID)
+	data, err := callback()
+	result := dataResult{InstanceID: ac.instanceID, DataID: dataID, Data: data}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	if cerr := ac.call(dataPath+"/set", result, nil); cerr != nil {
+		return nil, cerr
+	}
+	return data, err
+}
+
+// Signal notifies the coordinator that this instance has reached the given
+// event ID, or that it had an error before it could reach it.
+func (ac *AgentController) Signal(eventID string, err error) error {
+	ac.logger.Debugf("Signal(%s, %v)", eventID, err)
+
+	req := signalRequest{InstanceID: ac.instanceID, EventID: eventID}
+	if err != nil {
+		req.Error = err.Error()
+	}
+	return ac.call(signalPath, req, nil)
+}
+
+// Subscribe returns a callback that waits until all instances have reached the
+// given event ID, or until one of them signaled an error for it. The
+// coordinator keeps track of every event, so no signals can be missed even if
+// the callback is called after some other instances have reached the event.
+func (ac *AgentController) Subscribe(eventID string) func() error {
+	ac.logger.Debugf("Subscribe(%s)", eventID)
+
+	return func() error {
+		ac.logger.Debugf("Waiting for all instances to reach '%s'...", eventID)
+		resp := &waitResponse{}
+		if err := ac.call(waitPath, signalRequest{InstanceID: ac.instanceID, EventID: eventID}, resp); err != nil {
+			return err
+		}
+		if resp.Error != "" {
+			return fmt.Errorf("the distributed test run failed at '%s' because of %s", eventID, resp.Error)
+		}
+		ac.logger.Debugf("All instances reached '%s'", eventID)
+		return nil
+	}
+}
+
+// Done notifies the coordinator that this instance has finished its execution
+// with the given result. Any error will also abort the test run for all of the
+// other instances that are still waiting for this one.
+func (ac *AgentController) Done(runErr error) error {
+	ac.stopOnce.Do(func() { close(ac.stopHeartbeat) })
+
+	req := doneRequest{InstanceID: ac.instanceID}
+	if runErr != nil {
+		req.Error = runErr.Error()
+		var ecerr errext.HasExitCode
+		if errors.As(runErr, &ecerr) {
+			req.ExitCode = int(ecerr.ExitCode())
+		}
+	}
+	// This is usually called after the test run context is done, so we can't
+	// use it to report the result.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ac.ctx), doneTimeout)
+	defer cancel()
+	return ac.doCall(ctx, donePath, req, nil)
+}
+
+func (ac *AgentController) call(path string, req, resp any) error {
+	return ac.doCall(ac.ctx, path, req, resp)
+}
+
+func (ac *AgentController) doCall(ctx context.Context, path string, req, resp any) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, ac.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if ac.token != "" {
+		httpReq.Header.Set("Authorization", tokenScheme+ac.token)
+	}
+
+	httpResp, err := ac.client.Do(httpReq)
+	if err != nil {
+		// return the reason the instance was aborted instead of a context error
+		if cause := context.Cause(ctx); cause != nil && !errors.Is(cause, context.Canceled) {
+			return cause
+		}
+		return err
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+
+	if httpResp.StatusCode >= http.StatusBadRequest {
+		msg, _ := io.ReadAll(httpResp.Body)
+		return fmt.Errorf("the coordinator responded with status %d: %s",
+			httpResp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	if resp == nil {
+		return nil
+	}
+	return json.NewDecoder(httpResp.Body).Decode(resp)
+}
@@ -0,0 +1,545 @@
+package distributed
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"go.k6.io/k6/errext"
+	"go.k6.io/k6/errext/exitcodes"
+	"go.k6.io/k6/lib"
+)
+
+// CoordinatorServer hands out the test archive and the execution segments to
+// the agent instances and then acts as the central synchronization point for
+// all of their execution.Controller calls.
+type CoordinatorServer struct {
+	instanceCount int
+	archive       []byte
+	ess           lib.ExecutionSegmentSequence
+	agentTimeout  time.Duration
+	regTimeout    time.Duration
+	token         string
+	logger        logrus.FieldLogger
+
+	mu            sync.Mutex
+	registered    int
+	regErr        error
+	lastSeen      map[uint32]time.Time
+	dataRegistry  map[string]*dataWaiter
+	eventRegistry map[string]*eventWaiter
+	abortErr      error
+	firstFailed   uint32
+	finished      map[uint32]doneRequest
+	allDone       chan struct{}
+}
+
+type dataWaiter struct {
+	done chan struct{}
+	data []byte
+	err  string
+}
+
+type eventWaiter struct {
+	signaled map[uint32]struct{}
+	done     chan struct{}
+	err      string
+}
+
+// NewCoordinatorServer returns a new CoordinatorServer that will wait for
+// exactly instanceCount agents. Each one of them will receive the given test
+// archive and its own part of an evenly split execution segment sequence.
+// Agents that don't send any heartbeats for agentTimeout are considered lost,
+// and the test run is aborted if not all of them have registered in
+// registrationTimeout. If the token isn't empty, the requests of the agents
+// without it are rejected.
+func NewCoordinatorServer(
+	instanceCount int, archive *lib.Archive, agentTimeout, registrationTimeout time.Duration, token string,
+	logger logrus.FieldLogger,
+) (*CoordinatorServer, error) {
+	if instanceCount < 1 {
+		return nil, fmt.Errorf("the number of instances must be at least 1, %d received", instanceCount)
+	}
+	if agentTimeout <= 0 {
+		return nil, fmt.Errorf("the agent timeout must be positive, %s received", agentTimeout)
+	}
+	if registrationTimeout <= 0 {
+		return nil, fmt.Errorf("the registration timeout must be positive, %s received", registrationTimeout)
+	}
+	if archive.Options.ExecutionSegment != nil || archive.Options.ExecutionSegmentSequence != nil {
+		return nil, errors.New("the execution segment options can't be used with distributed execution, " +
+			"the coordinator assigns them to the instances automatically")
+	}
+
+	var fullSegment *lib.ExecutionSegment // nil means the whole 0:1 segment
+	segments, err := fullSegment.Split(int64(instanceCount))
+	if err != nil {
+		return nil, err
+	}
+	ess, err := lib.NewExecutionSegmentSequence(segments...)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	if err := archive.Write(buf); err != nil {
+		return nil, fmt.Errorf("could not serialize the test archive: %w", err)
+	}
+
+	return &CoordinatorServer{
+		instanceCount: instanceCount,
+		archive:       buf.Bytes(),
+		ess:           ess,
+		agentTimeout:  agentTimeout,
+		regTimeout:    registrationTimeout,
+		token:         token,
+		logger:        logger.WithField("component", "coordinator"),
+		lastSeen:      make(map[uint32]time.Time),
+		dataRegistry:  make(map[string]*dataWaiter),
+		eventRegistry: make(map[string]*eventWaiter),
+		finished:      make(map[uint32]doneRequest),
+		allDone:       make(chan struct{}),
+	}, nil
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (cs *CoordinatorServer) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(rw, "only POST requests are supported", http.StatusMethodNotAllowed)
+		return
+	}
+	if !cs.authorized(r) {
+		http.Error(rw, "the token is missing or invalid", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.URL.Path {
+	case registerPath:
+		cs.handleRegister(rw)
+	case dataPath:
+		cs.handleGetOrCreateData(rw, r)
+	case dataPath + "/set":
+		cs.handleSetData(rw, r)
+	case signalPath:
+		cs.handleSignal(rw, r)
+	case waitPath:
+		cs.handleWait(rw, r)
+	case donePath:
+		cs.handleDone(rw, r)
+	case heartbeatPath:
+		cs.handleHeartbeat(rw, r)
+	default:
+		http.NotFound(rw, r)
+	}
+}
+
+// authorized returns whether the request has the token, if one is required.
+func (cs *CoordinatorServer) authorized(r *http.Request) bool {
+	if cs.token == "" {
+		return true
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), tokenScheme)
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(cs.token)) == 1
+}
+
+// Wait blocks until all of the instances have reported that they are done or
+// were lost, or until the given context is done. If not all of the instances
+// register within the registration timeout, the ones that did are aborted. It
+// returns the registration error or the first instance error.
+func (cs *CoordinatorServer) Wait(ctx context.Context) error {
+	ticker := time.NewTicker(cs.agentTimeout / heartbeatsPerTimeout)
+	defer ticker.Stop()
+	regTimer := time.NewTimer(cs.regTimeout)
+	defer regTimer.Stop()
+
+waitLoop:
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-cs.allDone:
+			break waitLoop
+		case now := <-ticker.C:
+			cs.checkHeartbeats(now)
+		case <-regTimer.C:
+			cs.checkRegistrations()
+		}
+	}
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if cs.regErr != nil {
+		return errext.WithExitCodeIfNone(cs.regErr, exitcodes.ExternalAbort)
+	}
+	if cs.firstFailed == 0 {
+		return nil
+	}
+	// Other instances usually fail only because they were aborted by the first
+	// one that had an error, so that is the one we report.
+	res := cs.finished[cs.firstFailed]
+	err := fmt.Errorf("instance %d finished with an error: %s", cs.firstFailed, res.Error)
+	if res.ExitCode != 0 {
+		return errext.WithExitCodeIfNone(err, exitcodes.ExitCode(res.ExitCode)) //nolint:gosec
+	}
+	return err
+}
+
+func (cs *CoordinatorServer) handleRegister(rw http.ResponseWriter) {
+	cs.mu.Lock()
+	if cs.regErr != nil {
+		cs.mu.Unlock()
+		http.Error(rw, cs.regErr.Error(), http.StatusConflict)
+		return
+	}
+	if cs.registered >= cs.instanceCount {
+		cs.mu.Unlock()
+		http.Error(rw, fmt.Sprintf("all %d instances have already registered", cs.instanceCount), http.StatusConflict)
+		return
+	}
+	segment := cs.ess[cs.registered]
+	cs.registered++
+	instanceID := uint32(cs.registered) //nolint:gosec
+	cs.lastSeen[instanceID] = time.Now()
+	cs.mu.Unlock()
+
+	cs.logger.Infof("Instance %d of %d registered, assigned execution segment %s",
+		instanceID, cs.instanceCount, segment)
+
+	writeJSON(rw, InstanceData{
+		InstanceID:               instanceID,
+		Archive:                  cs.archive,
+		ExecutionSegment:         segment.String(),
+		ExecutionSegmentSequence: cs.ess.String(),
+		AgentTimeout:             cs.agentTimeout,
+	})
+}
+
+func (cs *CoordinatorServer) handleGetOrCreateData(rw http.ResponseWriter, r *http.Request) {
+	var req dataRequest
+	if !readJSON(rw, r, &req) {
+		return
+	}
+
+	cs.mu.Lock()
+	if !cs.checkInstanceID(rw, req.InstanceID) {
+		cs.mu.Unlock()
+		return
+	}
+	if cs.abortErr != nil {
+		cs.mu.Unlock()
+		writeJSON(rw, dataResponse{Error: cs.abortErr.Error()})
+		return
+	}
+	dw, ok := cs.dataRegistry[req.DataID]
+	if !ok {
+		cs.dataRegistry[req.DataID] = &dataWaiter{done: make(chan struct{})}
+		cs.mu.Unlock()
+		cs.logger.Debugf("Instance %d will create the data with ID '%s'", req.InstanceID, req.DataID)
+		writeJSON(rw, dataResponse{Create: true})
+		return
+	}
+	cs.mu.Unlock()
+
+	select {
+	case <-r.Context().Done():
+		return
+	case <-dw.done:
+	}
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	writeJSON(rw, dataResponse{Data: dw.data, Error: dw.err})
+}
+
+func (cs *CoordinatorServer) handleSetData(rw http.ResponseWriter, r *http.Request) {
+	var req dataResult
+	if !readJSON(rw, r, &req) {
+		return
+	}
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if !cs.checkInstanceID(rw, req.InstanceID) {
+		return
+	}
+	dw, ok := cs.dataRegistry[req.DataID]
+	if !ok {
+		http.Error(rw, fmt.Sprintf("nobody requested the data with ID '%s'", req.DataID), http.StatusBadRequest)
+		return
+	}
+	select {
+	case <-dw.done:
+		http.Error(rw, fmt.Sprintf("the data with ID '%s' was already set", req.DataID), http.StatusConflict)
+		return
+	default:
+	}
+	dw.data = req.Data
+	if req.Error != "" {
+		dw.err = fmt.Sprintf("instance %d could not create the data with ID '%s': %s",
+			req.InstanceID, req.DataID, req.Error)
+		if cs.firstFailed == 0 {
+			cs.firstFailed = req.InstanceID
+		}
+	}
+	close(dw.done)
+	cs.logger.Debugf("Instance %d created the data with ID '%s'", req.InstanceID, req.DataID)
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// getEventWaiter returns the waiter for the given event ID, creating it if
+// needed. It should be called with the mutex held.
+func (cs *CoordinatorServer) getEventWaiter(eventID string) *eventWaiter {
+	ew, ok := cs.eventRegistry[eventID]
+	if !ok {
+		ew = &eventWaiter{signaled: make(map[uint32]struct{}), done: make(chan struct{})}
+		if cs.abortErr != nil {
+			ew.err = cs.abortErr.Error()
+			close(ew.done)
+		}
+		cs.eventRegistry[eventID] = ew
+	}
+	return ew
+}
+
+func (cs *CoordinatorServer) handleSignal(rw http.ResponseWriter, r *http.Request) {
+	var req signalRequest
+	if !readJSON(rw, r, &req) {
+		return
+	}
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if !cs.checkInstanceID(rw, req.InstanceID) {
+		return
+	}
+	ew := cs.getEventWaiter(req.EventID)
+	select {
+	case <-ew.done:
+		// the event was already reached by everyone or had an error
+		rw.WriteHeader(http.StatusNoContent)
+		return
+	default:
+	}
+
+	if req.Error != "" {
+		cs.logger.Debugf("Instance %d signaled event '%s' with an error: %s", req.InstanceID, req.EventID, req.Error)
+		ew.err = fmt.Sprintf("instance %d: %s", req.InstanceID, req.Error)
+		close(ew.done)
+		if cs.firstFailed == 0 {
+			cs.firstFailed = req.InstanceID
+		}
+		rw.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	ew.signaled[req.InstanceID] = struct{}{}
+	cs.logger.Debugf("Instance %d reached event '%s' (%d/%d)",
+		req.InstanceID, req.EventID, len(ew.signaled), cs.instanceCount)
+	if len(ew.signaled) >= cs.instanceCount {
+		close(ew.done)
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func (cs *CoordinatorServer) handleWait(rw http.ResponseWriter, r *http.Request) {
+	var req signalRequest
+	if !readJSON(rw, r, &req) {
+		return
+	}
+
+	cs.mu.Lock()
+	if !cs.checkInstanceID(rw, req.InstanceID) {
+		cs.mu.Unlock()
+		return
+	}
+	ew := cs.getEventWaiter(req.EventID)
+	cs.mu.Unlock()
+
+	select {
+	case <-r.Context().Done():
+		return
+	case <-ew.done:
+	}
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	writeJSON(rw, waitResponse{Error: ew.err})
+}
+
+func (cs *CoordinatorServer) handleDone(rw http.ResponseWriter, r *http.Request) {
+	var req doneRequest
+	if !readJSON(rw, r, &req) {
+		return
+	}
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if !cs.checkInstanceID(rw, req.InstanceID) {
+		return
+	}
+	if _, ok := cs.finished[req.InstanceID]; ok {
+		http.Error(rw, fmt.Sprintf("instance %d was already done", req.InstanceID), http.StatusConflict)
+		return
+	}
+	cs.finished[req.InstanceID] = req
+	if req.Error != "" {
+		cs.logger.Warnf("Instance %d finished with an error: %s", req.InstanceID, req.Error)
+		if cs.firstFailed == 0 {
+			cs.firstFailed = req.InstanceID
+		}
+		cs.abort(fmt.Errorf("instance %d aborted: %s", req.InstanceID, req.Error))
+	} else {
+		cs.logger.Infof("Instance %d finished successfully", req.InstanceID)
+	}
+
+	cs.checkAllDone()
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func (cs *CoordinatorServer) handleHeartbeat(rw http.ResponseWriter, r *http.Request) {
+	var req heartbeatRequest
+	if !readJSON(rw, r, &req) {
+		return
+	}
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if !cs.checkInstanceID(rw, req.InstanceID) {
+		return
+	}
+	if res, ok := cs.finished[req.InstanceID]; ok && res.Error != "" {
+		// the instance was either lost or it already aborted the test run
+		writeJSON(rw, heartbeatResponse{Error: res.Error})
+		return
+	}
+	cs.lastSeen[req.InstanceID] = time.Now()
+
+	resp := heartbeatResponse{}
+	if cs.abortErr != nil {
+		resp.Error = cs.abortErr.Error()
+	}
+	writeJSON(rw, resp)
+}
+
+// checkHeartbeats considers every instance that is still running and hasn't
+// sent a heartbeat for longer than the agent timeout lost, and aborts the test
+// run for all of the others.
+func (cs *CoordinatorServer) checkHeartbeats(now time.Time) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	for instanceID, lastSeen := range cs.lastSeen {
+		if _, ok := cs.finished[instanceID]; ok || now.Sub(lastSeen) <= cs.agentTimeout {
+			continue
+		}
+
+		req := doneRequest{
+			InstanceID: instanceID,
+			Error:      fmt.Sprintf("no heartbeat was received for %s, the instance was lost", cs.agentTimeout),
+			ExitCode:   int(exitcodes.ExternalAbort),
+		}
+		cs.logger.Errorf("Instance %d was lost, since no heartbeat was received from it for %s",
+			instanceID, cs.agentTimeout)
+		cs.finished[instanceID] = req
+		if cs.firstFailed == 0 {
+			cs.firstFailed = instanceID
+		}
+		cs.abort(fmt.Errorf("instance %d aborted: %s", instanceID, req.Error))
+		cs.checkAllDone()
+	}
+}
+
+// checkRegistrations aborts the test run if not all of the instances have
+// registered, since the barriers would wait for the missing ones forever.
+// The instances that did register are still waited for, so they can stop.
+func (cs *CoordinatorServer) checkRegistrations() {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if cs.registered >= cs.instanceCount {
+		return
+	}
+
+	cs.regErr = fmt.Errorf("only %d of the %d instances registered in %s",
+		cs.registered, cs.instanceCount, cs.regTimeout)
+	cs.logger.Errorf("Aborting the test run, %s", cs.regErr)
+	cs.abort(fmt.Errorf("the test run was aborted: %w", cs.regErr))
+	cs.checkAllDone()
+}
+
+// checkInstanceID responds with an error and returns false if the given
+// instance ID wasn't assigned to any of the registered instances, so that an
+// unknown instance can't be counted in place of a real one. It should be
+// called with the mutex held.
+func (cs *CoordinatorServer) checkInstanceID(rw http.ResponseWriter, instanceID uint32) bool {
+	if instanceID >= 1 && int(instanceID) <= cs.registered {
+		return true
+	}
+	http.Error(rw, fmt.Sprintf("instance %d is not registered", instanceID), http.StatusBadRequest)
+	return false
+}
+
+// checkAllDone unblocks Wait when all of the instances are done. If the
+// registration failed, only the ones that registered are waited for. It should
+// be called with the mutex held.
+func (cs *CoordinatorServer) checkAllDone() {
+	expected := cs.instanceCount
+	if cs.regErr != nil {
+		expected = cs.registered
+	}
+	if len(cs.finished) < expected {
+		return
+	}
+	select {
+	case <-cs.allDone:
+	default:
+		close(cs.allDone)
+	}
+}
+
+// abort unblocks all current and future waiters with the given error, so that
+// the remaining instances don't wait forever for one that is already gone. It
+// should be called with the mutex held.
+func (cs *CoordinatorServer) abort(err error) {
+	if cs.abortErr != nil {
+		return
+	}
+	cs.abortErr = err
+	for _, ew := range cs.eventRegistry {
+		select {
+		case <-ew.done:
+		default:
+			ew.err = err.Error()
+			close(ew.done)
+		}
+	}
+	for _, dw := range cs.dataRegistry {
+		select {
+		case <-dw.done:
+		default:
+			dw.err = err.Error()
+			close(dw.done)
+		}
+	}
+}
+
+func readJSON(rw http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		http.Error(rw, fmt.Sprintf("invalid request body: %s", err), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+func writeJSON(rw http.ResponseWriter, v any) {
+	rw.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(rw).Encode(v)
+}
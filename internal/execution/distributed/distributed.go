@@ -0,0 +1,102 @@
+// Package distributed implements the execution.Controller interface for
+// distributed k6 execution, where a single coordinator process hands out the
+// test and synchronizes multiple agent instances over HTTP.
+//
+// Agents periodically send heartbeats to the coordinator. If the coordinator
+// doesn't receive one from an agent in time, it considers the agent lost and
+// aborts the test run for all of the others, and if an agent can't reach the
+// coordinator in time, it aborts its own part of the test run.
+//
+// The coordinator hands out the test archive and the setup() data, so when it's
+// reachable from other machines, it should require a token from the agents and
+// be served over HTTPS.
+package distributed
+
+import "time"
+
+// The HTTP endpoints that the coordinator exposes and the agents use.
+const (
+	registerPath  = "/v1/register"
+	dataPath      = "/v1/data"
+	signalPath    = "/v1/signal"
+	waitPath      = "/v1/wait"
+	donePath      = "/v1/done"
+	heartbeatPath = "/v1/heartbeat"
+)
+
+// tokenScheme is the scheme of the Authorization header with the token that
+// the agents send to the coordinator.
+const tokenScheme = "Bearer "
+
+// DefaultAgentTimeout is the default duration after which an agent that
+// hasn't sent any heartbeats is considered lost.
+const DefaultAgentTimeout = 30 * time.Second
+
+// DefaultRegistrationTimeout is the default duration in which all of the agents
+// have to register with the coordinator, before it aborts the test run.
+const DefaultRegistrationTimeout = 5 * time.Minute
+
+// heartbeatsPerTimeout is how many heartbeats are sent during the agent timeout,
+// so that a few of them can be lost without the agent being considered lost.
+const heartbeatsPerTimeout = 5
+
+// InstanceData is what the coordinator sends to every agent when it registers.
+type InstanceData struct {
+	// InstanceID is the 1-based ID of the agent instance.
+	InstanceID uint32 `json:"instanceID"`
+	// Archive is the whole test archive, as written by lib.Archive.Write().
+	Archive []byte `json:"archive"`
+	// ExecutionSegment is the part of the test the instance should execute.
+	ExecutionSegment string `json:"executionSegment"`
+	// ExecutionSegmentSequence is the sequence of all of the segments.
+	ExecutionSegmentSequence string `json:"executionSegmentSequence"`
+	// AgentTimeout is the duration after which the coordinator considers an
+	// agent that hasn't sent any heartbeats lost, and vice versa.
+	AgentTimeout time.Duration `json:"agentTimeout"`
+}
+
+type dataRequest struct {
+	InstanceID uint32 `json:"instanceID"`
+	DataID     string `json:"dataID"`
+}
+
+type dataResponse struct {
+	// Create is true when the requesting instance was the first one to ask
+	// for the data ID, so it is responsible for creating the data.
+	Create bool   `json:"create,omitempty"`
+	Data   []byte `json:"data,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type dataResult struct {
+	InstanceID uint32 `json:"instanceID"`
+	DataID     string `json:"dataID"`
+	Data       []byte `json:"data,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+type signalRequest struct {
+	InstanceID uint32 `json:"instanceID"`
+	EventID    string `json:"eventID"`
+	Error      string `json:"error,omitempty"`
+}
+
+type waitResponse struct {
+	Error string `json:"error,omitempty"`
+}
+
+type doneRequest struct {
+	InstanceID uint32 `json:"instanceID"`
+	Error      string `json:"error,omitempty"`
+	ExitCode   int    `json:"exitCode,omitempty"`
+}
+
+type heartbeatRequest struct {
+	InstanceID uint32 `json:"instanceID"`
+}
+
+type heartbeatResponse struct {
+	// Error is set when the test run was aborted, so that the instance can
+	// abort its own part of it as well.
+	Error string `json:"error,omitempty"`
+}
@@ -0,0 +1,343 @@
+package distributed
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/errext"
+	"go.k6.io/k6/errext/exitcodes"
+	"go.k6.io/k6/internal/execution"
+	"go.k6.io/k6/internal/lib/testutils"
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/fsext"
+	"go.k6.io/k6/metrics"
+)
+
+var _ execution.Controller = &AgentController{}
+
+func newTestArchive(t *testing.T) *lib.Archive {
+	t.Helper()
+	return &lib.Archive{
+		Type:        "js",
+		Options:     lib.Options{SystemTags: &metrics.DefaultSystemTagSet},
+		FilenameURL: &url.URL{Scheme: "file", Path: "/path/to/a.js"},
+		Data:        []byte(`export default function() {}`),
+		PwdURL:      &url.URL{Scheme: "file", Path: "/path/to"},
+		Filesystems: map[string]fsext.Fs{
+			"file": testutils.MakeMemMapFs(t, map[string][]byte{
+				"/path/to/a.js": []byte(`export default function() {}`),
+			}),
+		},
+	}
+}
+
+func newTestCoordinator(t *testing.T, instances int) (*CoordinatorServer, *httptest.Server) {
+	t.Helper()
+	cs, err := NewCoordinatorServer(
+		instances, newTestArchive(t), DefaultAgentTimeout, DefaultRegistrationTimeout, "", testutils.NewLogger(t),
+	)
+	require.NoError(t, err)
+	srv := httptest.NewServer(cs)
+	t.Cleanup(srv.Close)
+	return cs, srv
+}
+
+func registerAgents(t *testing.T, srv *httptest.Server, instances int) []*AgentController {
+	t.Helper()
+	agents := make([]*AgentController, instances)
+	for i := range agents {
+		ac, data, err := Register(context.Background(), srv.Client(), srv.URL, "", testutils.NewLogger(t))
+		require.NoError(t, err)
+		assert.Equal(t, uint32(i+1), data.InstanceID) //nolint:gosec
+		assert.NotEmpty(t, data.Archive)
+		agents[i] = ac
+	}
+	return agents
+}
+
+func TestCoordinatorSegments(t *testing.T) {
+	t.Parallel()
+	_, srv := newTestCoordinator(t, 3)
+
+	segments := make([]string, 0, 3)
+	for range 3 {
+		_, data, err := Register(context.Background(), srv.Client(), srv.URL, "", testutils.NewLogger(t))
+		require.NoError(t, err)
+		assert.Equal(t, "0,1/3,2/3,1", data.ExecutionSegmentSequence)
+		segments = append(segments, data.ExecutionSegment)
+
+		hdr, err := tar.NewReader(bytes.NewReader(data.Archive)).Next()
+		require.NoError(t, err)
+		assert.Equal(t, "metadata.json", hdr.Name)
+	}
+	assert.Equal(t, []string{"0:1/3", "1/3:2/3", "2/3:1"}, segments)
+
+	_, _, err := Register(context.Background(), srv.Client(), srv.URL, "", testutils.NewLogger(t))
+	require.ErrorContains(t, err, "all 3 instances have already registered")
+}
+
+func TestCoordinatorRejectsExecutionSegments(t *testing.T) {
+	t.Parallel()
+	arc := newTestArchive(t)
+	segment, err := lib.NewExecutionSegmentFromString("0:1/2")
+	require.NoError(t, err)
+	arc.Options.ExecutionSegment = segment
+
+	_, err = NewCoordinatorServer(2, arc, DefaultAgentTimeout, DefaultRegistrationTimeout, "", testutils.NewLogger(t))
+	require.ErrorContains(t, err, "execution segment options can't be used")
+}
+
+func TestCoordinatorToken(t *testing.T) {
+	t.Parallel()
+	cs, err := NewCoordinatorServer(
+		1, newTestArchive(t), DefaultAgentTimeout, DefaultRegistrationTimeout, "s3cr3t", testutils.NewLogger(t),
+	)
+	require.NoError(t, err)
+	srv := httptest.NewTLSServer(cs)
+	t.Cleanup(srv.Close)
+
+	for _, token := range []string{"", "wrong"} {
+		_, _, err = Register(context.Background(), srv.Client(), srv.URL, token, testutils.NewLogger(t))
+		require.ErrorContains(t, err, "the coordinator responded with status 401: the token is missing or invalid")
+	}
+
+	ac, data, err := Register(context.Background(), srv.Client(), srv.URL, "s3cr3t", testutils.NewLogger(t))
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), data.InstanceID)
+	require.NoError(t, ac.Signal("test-start", nil))
+	require.NoError(t, ac.Done(nil))
+	require.NoError(t, cs.Wait(context.Background()))
+}
+
+func TestDistributedBarriersAndData(t *testing.T) {
+	t.Parallel()
+	const instances = 3
+	cs, srv := newTestCoordinator(t, instances)
+	agents := registerAgents(t, srv, instances)
+
+	var (
+		setupCalls   atomic.Int32
+		reachedSetup atomic.Int32
+		wg           sync.WaitGroup
+	)
+	results := make([][]byte, instances)
+	errs := make([]error, instances)
+	for i, ac := range agents {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := execution.SignalAndWait(ac, "init-done"); err != nil {
+				errs[i] = err
+				return
+			}
+			reachedSetup.Add(1)
+			results[i], errs[i] = ac.GetOrCreateData("setup", func() ([]byte, error) {
+				setupCalls.Add(1)
+				// every instance must have passed the barrier before the data is created
+				assert.Equal(t, int32(instances), reachedSetup.Load())
+				return []byte(`{"token":"secret"}`), nil
+			})
+			if errs[i] == nil {
+				errs[i] = execution.SignalAndWait(ac, "setup-done")
+			}
+			_ = ac.Done(errs[i])
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), setupCalls.Load())
+	for i := range agents {
+		require.NoError(t, errs[i])
+		assert.Equal(t, `{"token":"secret"}`, string(results[i]))
+	}
+	require.NoError(t, cs.Wait(context.Background()))
+}
+
+func TestDistributedErrorPropagation(t *testing.T) {
+	t.Parallel()
+	cs, srv := newTestCoordinator(t, 2)
+	agents := registerAgents(t, srv, 2)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- execution.SignalAndWait(agents[0], "setup-done")
+	}()
+
+	setupErr := errext.WithExitCodeIfNone(errors.New("setup blew up"), exitcodes.ScriptException)
+	err := execution.SignalErrorOrWait(agents[1], "setup-done", setupErr)
+	require.ErrorIs(t, err, setupErr)
+	require.NoError(t, agents[1].Done(err))
+
+	err = <-errCh
+	require.ErrorContains(t, err, "instance 2: setup blew up")
+	require.NoError(t, agents[0].Done(err))
+
+	// new barriers fail immediately after one of the instances has aborted
+	require.ErrorContains(t, execution.SignalAndWait(agents[0], "teardown-done"), "instance 2 aborted")
+
+	err = cs.Wait(context.Background())
+	require.ErrorContains(t, err, "instance 2 finished with an error: setup blew up")
+	var ecerr errext.HasExitCode
+	require.ErrorAs(t, err, &ecerr)
+	assert.Equal(t, exitcodes.ScriptException, ecerr.ExitCode())
+}
+
+func TestCoordinatorOnlyAcceptsPost(t *testing.T) {
+	t.Parallel()
+	_, srv := newTestCoordinator(t, 1)
+
+	resp, err := srv.Client().Get(srv.URL + registerPath) //nolint:noctx
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestCoordinatorAbortsOnLostAgent(t *testing.T) {
+	t.Parallel()
+	cs, err := NewCoordinatorServer(
+		2, newTestArchive(t), 300*time.Millisecond, DefaultRegistrationTimeout, "", testutils.NewLogger(t),
+	)
+	require.NoError(t, err)
+	srv := httptest.NewServer(cs)
+	t.Cleanup(srv.Close)
+
+	alive, _, err := Register(context.Background(), srv.Client(), srv.URL, "", testutils.NewLogger(t))
+	require.NoError(t, err)
+
+	// the second agent stops sending heartbeats right after it registers, as if it crashed
+	lostCtx, lost := context.WithCancel(context.Background())
+	_, _, err = Register(lostCtx, srv.Client(), srv.URL, "", testutils.NewLogger(t))
+	require.NoError(t, err)
+	lost()
+
+	waitErr := make(chan error, 1)
+	go func() {
+		waitErr <- cs.Wait(context.Background())
+	}()
+
+	err = execution.SignalAndWait(alive, "setup-done")
+	require.ErrorContains(t, err, "instance 2 aborted: no heartbeat was received for 300ms")
+
+	select {
+	case abortErr := <-alive.Aborted():
+		require.ErrorContains(t, abortErr, "instance 2 aborted")
+		var ecerr errext.HasExitCode
+		require.ErrorAs(t, abortErr, &ecerr)
+		assert.Equal(t, exitcodes.ExternalAbort, ecerr.ExitCode())
+	case <-time.After(5 * time.Second):
+		t.Fatal("the remaining agent was not aborted")
+	}
+	require.NoError(t, alive.Done(err))
+
+	err = <-waitErr
+	require.ErrorContains(t, err, "instance 2 finished with an error: no heartbeat was received")
+	var ecerr errext.HasExitCode
+	require.ErrorAs(t, err, &ecerr)
+	assert.Equal(t, exitcodes.ExternalAbort, ecerr.ExitCode())
+}
+
+func TestCoordinatorRejectsUnknownInstances(t *testing.T) {
+	t.Parallel()
+	cs, srv := newTestCoordinator(t, 2)
+	agents := registerAgents(t, srv, 1)
+
+	for _, instanceID := range []uint32{0, 2, 3} {
+		for path, req := range map[string]any{
+			dataPath:          dataRequest{InstanceID: instanceID, DataID: "setup"},
+			dataPath + "/set": dataResult{InstanceID: instanceID, DataID: "setup"},
+			signalPath:        signalRequest{InstanceID: instanceID, EventID: "setup-done"},
+			waitPath:          signalRequest{InstanceID: instanceID, EventID: "setup-done"},
+			donePath:          doneRequest{InstanceID: instanceID},
+			heartbeatPath:     heartbeatRequest{InstanceID: instanceID},
+		} {
+			body, err := json.Marshal(req)
+			require.NoError(t, err)
+			resp, err := srv.Client().Post(srv.URL+path, "application/json", bytes.NewReader(body)) //nolint:noctx
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "instance %d, %s", instanceID, path)
+		}
+	}
+
+	// the unknown instances were counted neither for the barrier nor as done
+	require.NoError(t, agents[0].Signal("setup-done", nil))
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, cs.Wait(ctx), context.DeadlineExceeded)
+}
+
+func TestCoordinatorAbortsOnMissingAgents(t *testing.T) {
+	t.Parallel()
+	cs, err := NewCoordinatorServer(
+		3, newTestArchive(t), DefaultAgentTimeout, 300*time.Millisecond, "", testutils.NewLogger(t),
+	)
+	require.NoError(t, err)
+	srv := httptest.NewServer(cs)
+	t.Cleanup(srv.Close)
+	agents := registerAgents(t, srv, 2)
+
+	waitErr := make(chan error, 1)
+	go func() {
+		waitErr <- cs.Wait(context.Background())
+	}()
+
+	// the registered agents are aborted and then waited for
+	err = execution.SignalAndWait(agents[0], "init-done")
+	require.ErrorContains(t, err, "only 2 of the 3 instances registered in 300ms")
+	_, _, err = Register(context.Background(), srv.Client(), srv.URL, "", testutils.NewLogger(t))
+	require.ErrorContains(t, err, "only 2 of the 3 instances registered")
+	for _, ac := range agents {
+		require.NoError(t, ac.Done(err))
+	}
+
+	err = <-waitErr
+	require.ErrorContains(t, err, "only 2 of the 3 instances registered in 300ms")
+	var ecerr errext.HasExitCode
+	require.ErrorAs(t, err, &ecerr)
+	assert.Equal(t, exitcodes.ExternalAbort, ecerr.ExitCode())
+}
+
+func TestAgentAbortsOnLostCoordinator(t *testing.T) {
+	t.Parallel()
+	cs, err := NewCoordinatorServer(
+		2, newTestArchive(t), 300*time.Millisecond, DefaultRegistrationTimeout, "", testutils.NewLogger(t),
+	)
+	require.NoError(t, err)
+
+	var unreachable atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if unreachable.Load() {
+			http.Error(rw, "unreachable", http.StatusServiceUnavailable)
+			return
+		}
+		cs.ServeHTTP(rw, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	ac, _, err := Register(context.Background(), srv.Client(), srv.URL, "", testutils.NewLogger(t))
+	require.NoError(t, err)
+	unreachable.Store(true)
+
+	select {
+	case abortErr := <-ac.Aborted():
+		require.ErrorContains(t, abortErr, "the coordinator couldn't be reached for 300ms")
+	case <-time.After(5 * time.Second):
+		t.Fatal("the agent was not aborted")
+	}
+
+	// all of the pending and future calls fail with the reason for the abort
+	require.ErrorContains(t, ac.Signal("setup-done", nil), "the coordinator couldn't be reached")
+}
@@ -366,6 +366,7 @@ func (e *Scheduler) runExecutor(
 		pb.WithConstProgress(0, "started"),
 	)
 	executorLogger.Debugf("Starting executor")
+	e.state.MarkScenarioStarted(executorConfig.GetName())
 	err := executor.Run(runCtx, engineOut) // executor should handle context cancel itself
 	if err == nil {
 		executorLogger.Debugf("Executor finished successfully")
@@ -22,11 +22,15 @@ import (
 	"go.k6.io/k6/internal/js/compiler"
 	"go.k6.io/k6/internal/js/eventloop"
 	"go.k6.io/k6/internal/js/modules/k6/webcrypto"
+	"go.k6.io/k6/internal/js/tc55/abort"
+	"go.k6.io/k6/internal/js/tc55/encoding"
 	"go.k6.io/k6/internal/js/tc55/timers"
+	tc55url "go.k6.io/k6/internal/js/tc55/url"
 	"go.k6.io/k6/internal/lib/consts"
 	"go.k6.io/k6/internal/loader"
 	"go.k6.io/k6/js/common"
 	"go.k6.io/k6/js/modules"
+	k6http "go.k6.io/k6/js/modules/k6/http"
 	"go.k6.io/k6/lib"
 	"go.k6.io/k6/lib/fsext"
 )
@@ -170,10 +174,17 @@ func (b *Bundle) makeArchive() *lib.Archive {
 	clonedSourceDataURL, _ := url.Parse(b.sourceData.URL.String())
 	clonedPwdURL, _ := url.Parse(b.pwd.String())
 
+	// The files read by the executors are stored with the anonymized paths in the
+	// archive, like the other files, so their paths in the options have to match
+	options := b.Options
+	options.Scenarios, _ = options.Scenarios.WithFilePaths(func(path string) (string, error) {
+		return filepath.FromSlash(lib.NormalizeAndAnonymizePath(path)), nil
+	})
+
 	arc := &lib.Archive{
 		Type:              "js",
 		Filesystems:       b.filesystems,
-		Options:           b.Options,
+		Options:           options,
 		FilenameURL:       clonedSourceDataURL,
 		Data:              b.sourceData.Data,
 		PwdURL:            clonedPwdURL,
@@ -402,14 +413,34 @@ func (b *Bundle) instantiate(vuImpl *moduleVUImpl, vuID uint64) (*BundleInstance
 }
 
 // registerGlobals registers the globals for the runtime.
-// e.g. timers and webcrypto.
+// e.g. timers, TextEncoder, URL, AbortController, webcrypto and fetch.
 func registerGlobals(vuImpl *moduleVUImpl) error {
 	err := timers.SetupGlobally(vuImpl)
 	if err != nil {
 		return err
 	}
 
-	return webcrypto.SetupGlobally(vuImpl)
+	err = encoding.SetupGlobally(vuImpl)
+	if err != nil {
+		return err
+	}
+
+	err = tc55url.SetupGlobally(vuImpl)
+	if err != nil {
+		return err
+	}
+
+	err = abort.SetupGlobally(vuImpl)
+	if err != nil {
+		return err
+	}
+
+	err = webcrypto.SetupGlobally(vuImpl)
+	if err != nil {
+		return err
+	}
+
+	return k6http.SetupFetchGlobally(vuImpl)
 }
 
 func (b *Bundle) setupJSRuntime(rt *sobek.Runtime, vuID uint64, logger logrus.FieldLogger) error {
@@ -21,7 +21,9 @@ func newConsole(logger logrus.FieldLogger) *console {
 }
 
 // Creates a console logger with its output set to the file at the provided `filepath`.
-func newFileConsole(filepath string, formatter logrus.Formatter, level logrus.Level) (*console, error) {
+func newFileConsole(
+	filepath string, formatter logrus.Formatter, level logrus.Level, hooks ...logrus.Hook,
+) (*console, error) {
 	//nolint:gosec,forbidigo // see https://github.com/grafana/k6/issues/2565
 	f, err := os.OpenFile(filepath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
 	if err != nil {
@@ -32,6 +34,9 @@ func newFileConsole(filepath string, formatter logrus.Formatter, level logrus.Le
 	l.SetLevel(level)
 	l.SetOutput(f)
 	l.SetFormatter(formatter)
+	for _, h := range hooks {
+		l.AddHook(h)
+	}
 
 	return &console{l}, nil
 }
@@ -13,6 +13,7 @@ import (
 	"go.k6.io/k6/internal/js/modules/k6/execution"
 	"go.k6.io/k6/internal/js/modules/k6/experimental/csv"
 	"go.k6.io/k6/internal/js/modules/k6/experimental/fs"
+	"go.k6.io/k6/internal/js/modules/k6/experimental/sse"
 	"go.k6.io/k6/internal/js/modules/k6/experimental/streams"
 	expws "go.k6.io/k6/internal/js/modules/k6/experimental/websockets"
 	"go.k6.io/k6/internal/js/modules/k6/grpc"
@@ -50,6 +51,7 @@ func getInternalJSModules() map[string]interface{} {
 		"k6/experimental/csv":        csv.New(),
 		"k6/experimental/fs":         fs.New(),
 		"k6/experimental/redis":      redis.New(),
+		"k6/experimental/sse":        sse.New(),
 		"k6/experimental/streams":    streams.New(),
 		"k6/experimental/websockets": expws.New(),
 
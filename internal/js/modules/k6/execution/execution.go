@@ -108,6 +108,26 @@ func (mi *ModuleInstance) newScenarioInfo() (*sobek.Object, error) {
 			p, _ := getScenarioState().ProgressFn()
 			return p
 		},
+		"sustainableRate": func() interface{} {
+			ss := getScenarioState()
+			if ss.SustainableRateFn == nil {
+				return nil
+			}
+			if rate, ok := ss.SustainableRateFn(); ok {
+				return rate
+			}
+			return nil
+		},
+		"record": func() interface{} {
+			ss := getScenarioState()
+			if ss.RecordFn == nil || vuState.GetScenarioGlobalVUIter == nil {
+				return nil
+			}
+			if record, ok := ss.RecordFn(vuState.GetScenarioGlobalVUIter()); ok {
+				return record
+			}
+			return nil
+		},
 		"iterationInInstance": func() interface{} {
 			if vuState.GetScenarioLocalVUIter == nil {
 				common.Throw(rt, errRunInInitContext)
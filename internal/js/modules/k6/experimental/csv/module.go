@@ -15,9 +15,10 @@ import (
 
 	"gopkg.in/guregu/null.v3"
 
-	"go.k6.io/k6/js/promises"
-
 	"go.k6.io/k6/internal/js/modules/k6/experimental/fs"
+	"go.k6.io/k6/internal/js/tc55/abort"
+
+	"go.k6.io/k6/js/promises"
 
 	"go.k6.io/k6/js/common"
 	"go.k6.io/k6/js/modules"
@@ -188,28 +189,53 @@ func (mi *ModuleInstance) NewParser(call sobek.ConstructorCall) *sobek.Object {
 }
 
 // Next returns the next row in the CSV file.
-func (p *Parser) Next() *sobek.Promise {
-	promise, resolve, reject := promises.New(p.vu)
-
-	go func() {
-		var record any
-		var done bool
-		var err error
+//
+// The read of the row can be canceled with the AbortSignal of the `signal` option,
+// in which case the promise is rejected with its reason if the row hasn't been read yet.
+func (p *Parser) Next(options sobek.Value) *sobek.Promise {
+	rt := p.vu.Runtime()
+	promise, resolve, reject := rt.NewPromise()
 
-		record, err = p.reader.Read()
-		if err != nil {
-			if errors.Is(err, io.EOF) {
-				resolve(parseResult{Done: true, Value: []string{}})
-				return
-			}
+	op, err := abort.Follow(rt, p.vu.Context(), abort.SignalParam(rt, options))
+	if err != nil {
+		if rejectErr := reject(err); rejectErr != nil {
+			common.Throw(rt, rejectErr)
+		}
+		return promise
+	}
 
-			reject(err)
+	callback := p.vu.RegisterCallback()
+	go func() {
+		if ctxErr := op.Context().Err(); ctxErr != nil {
+			callback(func() error {
+				if err := op.Done(); err != nil {
+					return err
+				}
+				if op.Aborted() {
+					return reject(op.Reason())
+				}
+				return reject(ctxErr)
+			})
 			return
 		}
 
-		p.currentLine.Add(1)
+		record, err := p.reader.Read()
+		if err == nil {
+			p.currentLine.Add(1)
+		}
 
-		resolve(parseResult{Done: done, Value: record})
+		callback(func() error {
+			if doneErr := op.Done(); doneErr != nil {
+				return doneErr
+			}
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					return resolve(parseResult{Done: true, Value: []string{}})
+				}
+				return reject(err)
+			}
+			return resolve(parseResult{Done: false, Value: record})
+		})
 	}()
 
 	return promise
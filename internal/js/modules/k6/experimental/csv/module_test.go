@@ -198,6 +198,36 @@ func TestParserNext(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("next with an aborted signal should reject with its reason", func(t *testing.T) {
+		t.Parallel()
+
+		r, err := newConfiguredRuntime(t)
+		require.NoError(t, err)
+
+		// Ensure the testdata.csv file is present on the test filesystem.
+		r.VU.InitEnvField.FileSystems["file"] = newTestFs(t, func(fs fsext.Fs) error {
+			return fsext.WriteFile(fs, testFilePath, []byte(csvTestData), 0o644)
+		})
+
+		_, err = r.RunOnEventLoop(wrapInAsyncLambda(fmt.Sprintf(`
+			const file = await fs.open(%q);
+			const parser = new csv.Parser(file);
+
+			const reason = await parser.next({ signal: AbortSignal.abort("stop") }).catch((e) => e);
+			if (reason !== "stop") {
+				throw new Error("Expected next to be rejected with the reason, but got " + reason);
+			}
+
+			// The aborted call didn't consume the header
+			let { done, value } = await parser.next({ signal: new AbortController().signal });
+			if (done || value[0] !== "lastname") {
+				throw new Error("Expected to read the header, but got " + value);
+			}
+		`, testFilePath)))
+
+		require.NoError(t, err)
+	})
+
 	t.Run("next with delimiter options should respect delimiter and succeed", func(t *testing.T) {
 		t.Parallel()
 
@@ -0,0 +1,148 @@
+package fs
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/js/common"
+	"go.k6.io/k6/js/promises"
+	"go.k6.io/k6/lib/fsext"
+)
+
+// DirEntry holds information about an entry of a directory.
+type DirEntry struct {
+	// Name holds the base name of the entry.
+	Name string `json:"name"`
+
+	// IsFile is true if the entry is a regular file.
+	IsFile bool `json:"isFile" js:"isFile"`
+
+	// IsDirectory is true if the entry is a directory.
+	IsDirectory bool `json:"isDirectory" js:"isDirectory"`
+}
+
+// ReadDir lists the entries of a directory, and returns a promise that will
+// resolve to an array of [DirEntry] sorted by name.
+//
+// In the init context, the directory is listed from the same file system as
+// [ModuleInstance.Open] reads files from. Outside of it, only the directories
+// writing files is allowed in can be listed.
+func (mi *ModuleInstance) ReadDir(path sobek.Value) *sobek.Promise {
+	promise, resolve, reject := promises.New(mi.vu)
+
+	if common.IsNullish(path) {
+		reject(newFsError(TypeError, "readDir() failed; reason: path cannot be null or undefined"))
+		return promise
+	}
+
+	pathStr := path.String()
+	if pathStr == "" {
+		reject(newFsError(TypeError, "readDir() failed; reason: path cannot be empty"))
+		return promise
+	}
+
+	fs := mi.writeFs
+	if mi.vu.State() == nil {
+		initFs, ok := mi.initEnv.FileSystems["file"]
+		if !ok {
+			reject(errors.New("readDir() failed; reason: unable to access the file system"))
+			return promise
+		}
+
+		fs = initFs
+		pathStr = fsext.Abs(mi.cwd(), strings.TrimPrefix(pathStr, "file://"))
+	} else {
+		var err error
+		if pathStr, err = mi.writablePath("readDir", pathStr); err != nil {
+			reject(err)
+			return promise
+		}
+	}
+
+	go func() {
+		entries, err := readDirImpl(fs, pathStr)
+		if err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(entries)
+	}()
+
+	return promise
+}
+
+func readDirImpl(fs fsext.Fs, path string) ([]DirEntry, error) {
+	if exists, err := fsext.Exists(fs, path); err != nil {
+		return nil, fmt.Errorf("readDir() failed, unable to verify if %q exists; reason: %w", path, err)
+	} else if !exists {
+		return nil, newFsError(NotFoundError, fmt.Sprintf("no such file or directory %q", path))
+	}
+
+	if isDir, err := fsext.IsDir(fs, path); err != nil {
+		return nil, fmt.Errorf("readDir() failed, unable to verify if %q is a directory; reason: %w", path, err)
+	} else if !isDir {
+		return nil, newFsError(InvalidResourceError, fmt.Sprintf("cannot list %q: it is not a directory", path))
+	}
+
+	infos, err := fsext.ReadDir(fs, path)
+	if err != nil {
+		return nil, fmt.Errorf("readDir() failed, unable to list %q; reason: %w", path, err)
+	}
+
+	entries := make([]DirEntry, 0, len(infos))
+	for _, info := range infos {
+		entries = append(entries, DirEntry{
+			Name:        info.Name(),
+			IsFile:      info.Mode().IsRegular(),
+			IsDirectory: info.IsDir(),
+		})
+	}
+
+	return entries, nil
+}
+
+// ScratchDir creates, if needed, a scratch directory inside the first directory
+// writing files is allowed in, and returns a promise that will resolve to its path.
+//
+// The directory is specific to the VU, named after its global ID so that it's
+// unique across the instances of a distributed test, unless the `shared` option
+// is set, in which case the same directory is used by all of them.
+func (mi *ModuleInstance) ScratchDir(options sobek.Value) *sobek.Promise {
+	promise, resolve, reject := promises.New(mi.vu)
+
+	dirs, err := mi.allowedDirs("scratchDir")
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	name := "vu-" + strconv.FormatUint(mi.vu.State().VUIDGlobal, 10)
+	if !common.IsNullish(options) {
+		if shared := options.ToObject(mi.vu.Runtime()).Get("shared"); shared != nil && shared.ToBoolean() {
+			name = "shared"
+		}
+	}
+
+	path, err := mi.writablePath("scratchDir", filepath.Join(dirs[0], "scratch", name))
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		if err := mi.writeFs.MkdirAll(path, 0o755); err != nil {
+			reject(fmt.Errorf("scratchDir() failed, unable to create %q; reason: %w", path, err))
+			return
+		}
+
+		resolve(path)
+	}()
+
+	return promise
+}
@@ -0,0 +1,184 @@
+package fs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/lib/fsext"
+)
+
+func TestReadDir(t *testing.T) {
+	t.Parallel()
+
+	t.Run("listing a directory in the init context should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		runtime, err := newConfiguredRuntime(t)
+		require.NoError(t, err)
+
+		runtime.VU.InitEnvField.FileSystems["file"] = newTestFs(t, func(fs fsext.Fs) error {
+			if err := fsext.WriteFile(fs, "/fixtures/b.json", []byte("{}"), 0o644); err != nil {
+				return err
+			}
+			if err := fsext.WriteFile(fs, "/fixtures/a.json", []byte("{}"), 0o644); err != nil {
+				return err
+			}
+
+			return fs.Mkdir("/fixtures/nested", 0o755)
+		})
+
+		_, err = runtime.RunOnEventLoop(wrapInAsyncLambda(`
+			const entries = await fs.readDir('fixtures');
+
+			const got = JSON.stringify(entries);
+			const want = JSON.stringify([
+				{ name: 'a.json', isFile: true, isDirectory: false },
+				{ name: 'b.json', isFile: true, isDirectory: false },
+				{ name: 'nested', isFile: false, isDirectory: true },
+			]);
+			if (got !== want) {
+				throw 'unexpected entries ' + got + '; expected ' + want;
+			}
+		`))
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("listing a missing directory should fail", func(t *testing.T) {
+		t.Parallel()
+
+		runtime, err := newConfiguredRuntime(t)
+		require.NoError(t, err)
+
+		_, err = runtime.RunOnEventLoop(wrapInAsyncLambda(`
+			try {
+				const entries = await fs.readDir('/fixtures');
+				throw 'unexpected promise resolution with result: ' + entries;
+			} catch (err) {
+				if (err.name !== 'NotFoundError') {
+					throw 'unexpected error: ' + err;
+				}
+			}
+		`))
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("listing a file should fail", func(t *testing.T) {
+		t.Parallel()
+
+		runtime, err := newConfiguredRuntime(t)
+		require.NoError(t, err)
+
+		runtime.VU.InitEnvField.FileSystems["file"] = newTestFs(t, func(fs fsext.Fs) error {
+			return fsext.WriteFile(fs, "/"+testFileName, []byte("Bonjour, le monde"), 0o644)
+		})
+
+		_, err = runtime.RunOnEventLoop(wrapInAsyncLambda(`
+			try {
+				const entries = await fs.readDir('bonjour.txt');
+				throw 'unexpected promise resolution with result: ' + entries;
+			} catch (err) {
+				if (err.name !== 'InvalidResourceError') {
+					throw 'unexpected error: ' + err;
+				}
+			}
+		`))
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("listing an allowed directory in the VU context should succeed", func(t *testing.T) {
+		t.Parallel()
+
+		runtime, writeFs := newWritableRuntime(t, "/results")
+		require.NoError(t, fsext.WriteFile(writeFs, "/results/ids.txt", []byte("42"), 0o644))
+
+		_, err := runtime.RunOnEventLoop(wrapInAsyncLambda(`
+			const entries = await fs.readDir('/results');
+			if (entries.length !== 1 || entries[0].name !== 'ids.txt' || !entries[0].isFile) {
+				throw 'unexpected entries ' + JSON.stringify(entries);
+			}
+		`))
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("listing a directory that isn't allowed in the VU context should fail", func(t *testing.T) {
+		t.Parallel()
+
+		runtime, writeFs := newWritableRuntime(t, "/results")
+		require.NoError(t, writeFs.MkdirAll("/fixtures", 0o755))
+
+		_, err := runtime.RunOnEventLoop(wrapInAsyncLambda(`
+			try {
+				const entries = await fs.readDir('/fixtures');
+				throw 'unexpected promise resolution with result: ' + entries;
+			} catch (err) {
+				if (err.name !== 'ForbiddenError') {
+					throw 'unexpected error: ' + err;
+				}
+			}
+		`))
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestScratchDir(t *testing.T) {
+	t.Parallel()
+
+	t.Run("scratch directories should be created per VU or shared", func(t *testing.T) {
+		t.Parallel()
+
+		runtime, writeFs := newWritableRuntime(t, "/results", "/other")
+
+		_, err := runtime.RunOnEventLoop(wrapInAsyncLambda(`
+			const dir = await fs.scratchDir();
+			if (dir !== '/results/scratch/vu-7') {
+				throw 'unexpected scratch directory ' + dir;
+			}
+
+			const shared = await fs.scratchDir({ shared: true });
+			if (shared !== '/results/scratch/shared') {
+				throw 'unexpected shared scratch directory ' + shared;
+			}
+
+			const file = await fs.open(dir + '/ids.txt', { write: true });
+			await file.write('42');
+			await file.close();
+		`))
+		require.NoError(t, err)
+
+		for _, dir := range []string{"/results/scratch/vu-7", "/results/scratch/shared"} {
+			isDir, err := fsext.IsDir(writeFs, dir)
+			require.NoError(t, err)
+			assert.True(t, isDir, dir)
+		}
+
+		got, err := fsext.ReadFile(writeFs, "/results/scratch/vu-7/ids.txt")
+		require.NoError(t, err)
+		assert.Equal(t, "42", string(got))
+	})
+
+	t.Run("scratch directories are not available when writing is disabled", func(t *testing.T) {
+		t.Parallel()
+
+		runtime, _ := newWritableRuntime(t)
+
+		_, err := runtime.RunOnEventLoop(wrapInAsyncLambda(`
+			try {
+				const dir = await fs.scratchDir();
+				throw 'unexpected promise resolution with result: ' + dir;
+			} catch (err) {
+				if (err.name !== 'ForbiddenError') {
+					throw 'unexpected error: ' + err;
+				}
+			}
+		`))
+
+		assert.NoError(t, err)
+	})
+}
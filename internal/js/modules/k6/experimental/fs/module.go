@@ -1,6 +1,11 @@
 // Package fs provides a k6 module that allows users to interact with files from the
 // local filesystem as per the [File API design document].
 //
+// Files opened for reading are loaded in the init context and cached, so that they end up in
+// archives. Writing files, and listing the directories they are written to, is only possible
+// outside of the init context, in the directories allowed by the `--fs-allow-write` option,
+// and it's disabled by default.
+//
 // [File API design document]: https://github.com/grafana/k6/blob/master/docs/design/019-file-api.md#proposed-solution
 package fs
 
@@ -15,6 +20,7 @@ import (
 
 	"github.com/grafana/sobek"
 
+	"go.k6.io/k6/internal/js/tc55/abort"
 	"go.k6.io/k6/js/common"
 	"go.k6.io/k6/js/modules"
 	"go.k6.io/k6/js/promises"
@@ -31,6 +37,15 @@ type (
 	ModuleInstance struct {
 		vu    modules.VU
 		cache *cache
+
+		// writeFs is the file system of the VU files are written to, and
+		// the directories allowed for writing are listed from.
+		writeFs fsext.Fs
+
+		// initEnv holds the init environment of the VU, which isn't
+		// available from it anymore once out of the init context, so
+		// that paths can still be resolved relative to the script.
+		initEnv *common.InitEnvironment
 	}
 )
 
@@ -49,7 +64,14 @@ func New() *RootModule {
 // NewModuleInstance implements the modules.Module interface and returns a new
 // instance of our module for the given VU.
 func (rm *RootModule) NewModuleInstance(vu modules.VU) modules.Instance {
-	return &ModuleInstance{vu: vu, cache: rm.cache}
+	initEnv := vu.InitEnv()
+
+	return &ModuleInstance{
+		vu:      vu,
+		cache:   rm.cache,
+		writeFs: writableFs(initEnv),
+		initEnv: initEnv,
+	}
 }
 
 // Exports implements the modules.Module interface and returns the exports of
@@ -57,7 +79,9 @@ func (rm *RootModule) NewModuleInstance(vu modules.VU) modules.Instance {
 func (mi *ModuleInstance) Exports() modules.Exports {
 	return modules.Exports{
 		Named: map[string]any{
-			"open": mi.Open,
+			"open":       mi.Open,
+			"readDir":    mi.ReadDir,
+			"scratchDir": mi.ScratchDir,
 			"SeekMode": map[string]any{
 				"Start":   SeekModeStart,
 				"Current": SeekModeCurrent,
@@ -67,11 +91,22 @@ func (mi *ModuleInstance) Exports() modules.Exports {
 	}
 }
 
-// Open opens a file and returns a promise that will resolve to a [File] instance
-func (mi *ModuleInstance) Open(path sobek.Value) *sobek.Promise {
+// Open opens a file and returns a promise that will resolve to a [File] instance.
+//
+// When the `write` or `append` options are set, the file is opened for writing
+// instead, and the promise resolves to a [WritableFile] instance. It's closed
+// automatically, if it wasn't already, at the end of the iteration, or of the
+// setup or teardown, it was opened in.
+func (mi *ModuleInstance) Open(path sobek.Value, options sobek.Value) *sobek.Promise {
 	promise, resolve, reject := promises.New(mi.vu)
 
-	if mi.vu.State() != nil {
+	opts, err := parseOpenOptions(mi.vu.Runtime(), options)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	if !opts.writable() && mi.vu.State() != nil {
 		reject(newFsError(ForbiddenError, "open() failed; reason: opening a file is allowed only in the Init context"))
 		return promise
 	}
@@ -88,6 +123,27 @@ func (mi *ModuleInstance) Open(path sobek.Value) *sobek.Promise {
 		return promise
 	}
 
+	if opts.writable() {
+		resolvedPath, err := mi.writablePath("open", pathStr)
+		if err != nil {
+			reject(err)
+			return promise
+		}
+
+		ctx := mi.vu.Context()
+		go func() {
+			file, err := mi.openWritableImpl(ctx, resolvedPath, opts)
+			if err != nil {
+				reject(err)
+				return
+			}
+
+			resolve(file)
+		}()
+
+		return promise
+	}
+
 	go func() {
 		file, err := mi.openImpl(pathStr)
 		if err != nil {
@@ -222,7 +278,10 @@ func (f *File) Stat() *sobek.Promise {
 //
 // It is possible for a read to successfully return with 0 bytes.
 // This does not indicate EOF.
-func (f *File) Read(into sobek.Value) (*sobek.Promise, error) {
+//
+// The read can be canceled with the AbortSignal of the `signal` option, in
+// which case the promise is rejected with its reason if the read hasn't happened yet.
+func (f *File) Read(into sobek.Value, options sobek.Value) (*sobek.Promise, error) {
 	promise, resolve, reject := f.vu.Runtime().NewPromise()
 
 	if common.IsNullish(into) {
@@ -249,13 +308,35 @@ func (f *File) Read(into sobek.Value) (*sobek.Promise, error) {
 	intoBytes := ab.Bytes()
 	buffer := make([]byte, len(intoBytes))
 
+	rt := f.vu.Runtime()
+	op, err := abort.Follow(rt, f.vu.Context(), abort.SignalParam(rt, options))
+	if err != nil {
+		return promise, reject(newFsError(TypeError, "read() failed; reason: "+err.Error()))
+	}
+
 	// We register a callback to be executed by the VU's runtime.
 	// This ensures that the modification of the JS runtime's `into` buffer
 	// occurs on the main thread, during the promise's resolution.
 	callback := f.vu.RegisterCallback()
 	go func() {
+		if ctxErr := op.Context().Err(); ctxErr != nil {
+			callback(func() error {
+				if err := op.Done(); err != nil {
+					return err
+				}
+				if op.Aborted() {
+					return reject(op.Reason())
+				}
+				return reject(ctxErr)
+			})
+			return
+		}
+
 		n, readErr := f.ReadSeekStater.Read(buffer)
 		callback(func() error {
+			if err := op.Done(); err != nil {
+				return err
+			}
 			_ = copy(intoBytes[0:n], buffer)
 
 			// Read was successful, resolve early with the number of
@@ -328,6 +328,37 @@ func TestFile(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	t.Run("read called with an aborted signal should reject with its reason", func(t *testing.T) {
+		t.Parallel()
+
+		runtime, err := newConfiguredRuntime(t)
+		require.NoError(t, err)
+
+		testFilePath := fsext.FilePathSeparator + testFileName
+		fs := newTestFs(t, func(fs fsext.Fs) error {
+			return fsext.WriteFile(fs, testFilePath, []byte("012"), 0o644)
+		})
+		runtime.VU.InitEnvField.FileSystems["file"] = fs
+
+		_, err = runtime.RunOnEventLoop(wrapInAsyncLambda(fmt.Sprintf(`
+			const file = await fs.open(%q);
+			let buffer = new Uint8Array(3);
+
+			const reason = await file.read(buffer, { signal: AbortSignal.abort("stop") }).catch((e) => e);
+			if (reason !== "stop") {
+				throw 'expected read to be rejected with the reason, got ' + reason + ' instead';
+			}
+
+			// The aborted read didn't consume the file.
+			let bytesRead = await file.read(buffer, { signal: new AbortController().signal });
+			if (bytesRead !== 3 || buffer[0] !== 48) {
+				throw 'expected read to return 3, got ' + bytesRead + ' instead';
+			}
+		`, testFilePath)))
+
+		assert.NoError(t, err)
+	})
+
 	t.Run("read called with invalid argument should fail", func(t *testing.T) {
 		t.Parallel()
 
@@ -0,0 +1,369 @@
+package fs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/grafana/sobek"
+	"github.com/spf13/afero"
+
+	"go.k6.io/k6/js/common"
+	"go.k6.io/k6/js/modules"
+	"go.k6.io/k6/js/promises"
+	"go.k6.io/k6/lib/fsext"
+)
+
+// openOptions holds the options a file can be opened with.
+type openOptions struct {
+	// write opens the file for writing.
+	write bool
+
+	// append opens the file for writing, with every write appended
+	// to the end of the file.
+	append bool
+
+	// create creates the file if it doesn't exist yet. It defaults to true.
+	create bool
+
+	// truncate truncates the file to a size of 0 when it's opened.
+	truncate bool
+}
+
+// writable returns whether the options open the file for writing.
+func (o openOptions) writable() bool {
+	return o.write || o.append
+}
+
+// parseOpenOptions parses the options object [ModuleInstance.Open] was called with.
+func parseOpenOptions(rt *sobek.Runtime, options sobek.Value) (openOptions, error) {
+	opts := openOptions{create: true}
+	if common.IsNullish(options) {
+		return opts, nil
+	}
+
+	obj := options.ToObject(rt)
+	for name, field := range map[string]*bool{
+		"write":    &opts.write,
+		"append":   &opts.append,
+		"create":   &opts.create,
+		"truncate": &opts.truncate,
+	} {
+		if v := obj.Get(name); !common.IsNullish(v) {
+			*field = v.ToBoolean()
+		}
+	}
+
+	if opts.truncate && (opts.append || !opts.write) {
+		return opts, newFsError(TypeError, "open() failed; reason: the truncate option requires the write option, "+
+			"and can't be combined with the append option")
+	}
+
+	return opts, nil
+}
+
+// flags returns the flags to open a file with the options.
+func (o openOptions) flags() int {
+	flags := os.O_WRONLY
+	if o.append {
+		flags |= os.O_APPEND
+	}
+	if o.create {
+		flags |= os.O_CREATE
+	}
+	if o.truncate {
+		flags |= os.O_TRUNC
+	}
+
+	return flags
+}
+
+// writableFs returns the file system of the VU files are written to, handling
+// the leading separator k6 adds to absolute paths on Windows.
+func writableFs(initEnv *common.InitEnvironment) fsext.Fs {
+	if initEnv == nil || initEnv.TestPreInitState == nil || initEnv.WritableFS == nil {
+		return nil
+	}
+
+	fs := initEnv.WritableFS
+	if runtime.GOOS == "windows" {
+		fs = fsext.NewTrimFilePathSeparatorFs(fs)
+	}
+
+	return fs
+}
+
+// cwd returns the path relative paths are resolved against, which is the
+// directory of the entrypoint script.
+func (mi *ModuleInstance) cwd() string {
+	if mi.initEnv == nil || mi.initEnv.CWD == nil {
+		return fsext.FilePathSeparator
+	}
+
+	return mi.initEnv.CWD.Path
+}
+
+// allowedDirs returns the absolute paths of the directories writing files is
+// allowed in, or an error if writing files isn't allowed at all.
+//
+// The directories of the command line and the environment are already resolved
+// against the working directory of k6, only the ones set in other ways, e.g. by
+// the tests, are resolved here relative to the entrypoint script.
+func (mi *ModuleInstance) allowedDirs(method string) ([]string, error) {
+	state := mi.vu.State()
+	if state == nil {
+		return nil, newFsError(ForbiddenError, method+"() failed; reason: writing files and listing the "+
+			"directories they are written to is not allowed in the Init context")
+	}
+
+	dirs := make([]string, 0, len(state.Options.FSAllowWrite))
+	for _, dir := range state.Options.FSAllowWrite {
+		if dir = strings.TrimSpace(dir); dir != "" {
+			dirs = append(dirs, fsext.Abs(mi.cwd(), dir))
+		}
+	}
+
+	if len(dirs) == 0 {
+		return nil, newFsError(ForbiddenError, method+"() failed; reason: writing files is disabled, "+
+			"it can be enabled for a directory with the --fs-allow-write option")
+	}
+
+	if mi.writeFs == nil {
+		return nil, errors.New(method + "() failed; reason: unable to access the file system")
+	}
+
+	return dirs, nil
+}
+
+// writablePath resolves the given path relative to the entrypoint script, and
+// returns it if it's located inside one of the directories writing files is allowed in.
+func (mi *ModuleInstance) writablePath(method, path string) (string, error) {
+	dirs, err := mi.allowedDirs(method)
+	if err != nil {
+		return "", err
+	}
+
+	path = fsext.Abs(mi.cwd(), strings.TrimPrefix(path, "file://"))
+	for _, dir := range dirs {
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+
+		if err := mi.checkNoSymlink(method, dir, rel); err != nil {
+			return "", err
+		}
+
+		return path, nil
+	}
+
+	return "", newFsError(ForbiddenError, fmt.Sprintf(
+		"%s() failed; reason: %q is outside of the directories allowed by the --fs-allow-write option", method, path,
+	))
+}
+
+// checkNoSymlink ensures that none of the existing elements of the relative path
+// inside of the allowed directory is a symbolic link, which could point outside of it.
+func (mi *ModuleInstance) checkNoSymlink(method, dir, rel string) error {
+	lstater, ok := mi.writeFs.(afero.Lstater)
+	if !ok || rel == "." {
+		return nil
+	}
+
+	current := dir
+	for _, elem := range strings.Split(rel, string(filepath.Separator)) {
+		current = filepath.Join(current, elem)
+
+		info, _, err := lstater.LstatIfPossible(current)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s() failed, unable to verify %q; reason: %w", method, current, err)
+		}
+
+		if info.Mode()&fs.ModeSymlink != 0 {
+			return newFsError(ForbiddenError, fmt.Sprintf(
+				"%s() failed; reason: %q is a symbolic link, which is not allowed in the writable directories",
+				method, current,
+			))
+		}
+	}
+
+	return nil
+}
+
+// openWritableImpl opens the file for writing, and closes it once the given
+// context is done, if it wasn't already.
+func (mi *ModuleInstance) openWritableImpl(ctx context.Context, path string, opts openOptions) (*WritableFile, error) {
+	if isDir, err := fsext.IsDir(mi.writeFs, path); err == nil && isDir {
+		return nil, newFsError(
+			InvalidResourceError,
+			fmt.Sprintf("cannot open %q: opening a directory is not supported", path),
+		)
+	}
+
+	f, err := mi.writeFs.OpenFile(path, opts.flags(), 0o644)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, newFsError(NotFoundError, fmt.Sprintf("no such file or directory %q", path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open() failed, unable to open %q for writing; reason: %w", path, err)
+	}
+
+	// The first operation on the file doesn't have to wait for any other.
+	last := make(chan struct{})
+	close(last)
+
+	file := &WritableFile{Path: path, file: f, vu: mi.vu, last: last}
+	file.stopAutoClose = context.AfterFunc(ctx, func() {
+		if file.closed.CompareAndSwap(false, true) {
+			file.enqueue(func() { _ = file.file.Close() })
+		}
+	})
+
+	return file, nil
+}
+
+// WritableFile represents a file opened for writing, and exposes methods to interact with it.
+//
+// The operations on the file are performed in the order they are called in,
+// without having to wait for the previous one to be done.
+type WritableFile struct {
+	// Path holds the absolute path of the file.
+	Path string `json:"path"`
+
+	// file holds the underlying file, from the file system writes go to.
+	file afero.File
+
+	// vu holds a reference to the VU this file is associated with.
+	vu modules.VU
+
+	// mu guards last, as the file can be closed from outside of the event
+	// loop once the context it was opened in is done.
+	mu sync.Mutex
+
+	// last is closed once the last operation called on the file is done.
+	last chan struct{}
+
+	// closed is set once the file is closed, by Close or once the context it
+	// was opened in is done, after which no further operations are allowed.
+	closed atomic.Bool
+
+	// stopAutoClose stops the file from being closed once the context it
+	// was opened in is done.
+	stopAutoClose func() bool
+}
+
+// Write writes the given data, either a string, an ArrayBuffer or a Uint8Array,
+// to the file.
+//
+// Resolves to the number of bytes written.
+func (f *WritableFile) Write(data sobek.Value) *sobek.Promise {
+	promise, resolve, reject := promises.New(f.vu)
+
+	if f.closed.Load() {
+		reject(newFsError(InvalidResourceError, "write() failed; reason: the file is closed"))
+		return promise
+	}
+
+	if common.IsNullish(data) {
+		reject(newFsError(TypeError, "write() failed; reason: data argument cannot be null or undefined"))
+		return promise
+	}
+
+	b, err := common.ToBytes(data.Export())
+	if err != nil {
+		reject(newFsError(TypeError, "write() failed; reason: data argument must be a string, "+
+			"an ArrayBuffer or a Uint8Array"))
+		return promise
+	}
+
+	// The data is copied as the JS buffer it may come from can be
+	// modified before the write happens.
+	b = bytes.Clone(b)
+
+	f.enqueue(func() {
+		n, err := f.file.Write(b)
+		if err != nil {
+			reject(fmt.Errorf("write() failed; reason: %w", err))
+			return
+		}
+
+		resolve(n)
+	})
+
+	return promise
+}
+
+// Stat returns a promise that will resolve to a [FileInfo] instance describing
+// the file, once the previous writes are done.
+func (f *WritableFile) Stat() *sobek.Promise {
+	promise, resolve, reject := promises.New(f.vu)
+
+	if f.closed.Load() {
+		reject(newFsError(InvalidResourceError, "stat() failed; reason: the file is closed"))
+		return promise
+	}
+
+	f.enqueue(func() {
+		info, err := f.file.Stat()
+		if err != nil {
+			reject(fmt.Errorf("stat() failed; reason: %w", err))
+			return
+		}
+
+		resolve(&FileInfo{Name: filepath.Base(f.Path), Size: info.Size()})
+	})
+
+	return promise
+}
+
+// Close closes the file, once the previous writes are done.
+//
+// Files opened for writing should always be closed once they aren't needed anymore.
+func (f *WritableFile) Close() *sobek.Promise {
+	promise, resolve, reject := promises.New(f.vu)
+
+	if !f.closed.CompareAndSwap(false, true) {
+		reject(newFsError(InvalidResourceError, "close() failed; reason: the file is already closed"))
+		return promise
+	}
+	f.stopAutoClose()
+
+	f.enqueue(func() {
+		if err := f.file.Close(); err != nil {
+			reject(fmt.Errorf("close() failed; reason: %w", err))
+			return
+		}
+
+		resolve(sobek.Undefined())
+	})
+
+	return promise
+}
+
+// enqueue runs the operation in a goroutine, once the previous one is done.
+//
+// It is called from the event loop, except when closing the file once the context
+// it was opened in is done.
+func (f *WritableFile) enqueue(operation func()) {
+	f.mu.Lock()
+	previous, done := f.last, make(chan struct{})
+	f.last = done
+	f.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		<-previous
+		operation()
+	}()
+}
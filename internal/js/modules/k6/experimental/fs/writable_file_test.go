@@ -0,0 +1,297 @@
+package fs
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/internal/js/compiler"
+	"go.k6.io/k6/js/modulestest"
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/fsext"
+	"go.k6.io/k6/metrics"
+)
+
+func TestOpenWritable(t *testing.T) {
+	t.Parallel()
+
+	t.Run("writing is disabled by default", func(t *testing.T) {
+		t.Parallel()
+
+		runtime, _ := newWritableRuntime(t)
+
+		_, err := runtime.RunOnEventLoop(wrapInAsyncLambda(`
+			try {
+				const file = await fs.open('/results/ids.txt', { write: true });
+				throw 'unexpected promise resolution with result: ' + file;
+			} catch (err) {
+				if (err.name !== 'ForbiddenError') {
+					throw 'unexpected error: ' + err;
+				}
+			}
+		`))
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("writing in the init context should fail", func(t *testing.T) {
+		t.Parallel()
+
+		runtime, err := newConfiguredRuntime(t)
+		require.NoError(t, err)
+
+		_, err = runtime.RunOnEventLoop(wrapInAsyncLambda(`
+			try {
+				const file = await fs.open('/results/ids.txt', { append: true });
+				throw 'unexpected promise resolution with result: ' + file;
+			} catch (err) {
+				if (err.name !== 'ForbiddenError') {
+					throw 'unexpected error: ' + err;
+				}
+			}
+		`))
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("writing outside of the allowed directories should fail", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []string{"/ids.txt", "/results-other/ids.txt", "/results/../ids.txt", "../ids.txt"}
+
+		for _, path := range tests {
+			t.Run(path, func(t *testing.T) {
+				t.Parallel()
+
+				runtime, _ := newWritableRuntime(t, "results")
+
+				_, err := runtime.RunOnEventLoop(wrapInAsyncLambda(fmt.Sprintf(`
+					try {
+						const file = await fs.open(%q, { write: true });
+						throw 'unexpected promise resolution with result: ' + file;
+					} catch (err) {
+						if (err.name !== 'ForbiddenError') {
+							throw 'unexpected error: ' + err;
+						}
+					}
+				`, path)))
+
+				assert.NoError(t, err)
+			})
+		}
+	})
+
+	t.Run("appending to a file should write in order", func(t *testing.T) {
+		t.Parallel()
+
+		runtime, writeFs := newWritableRuntime(t, "/results")
+		require.NoError(t, writeFs.MkdirAll("/results", 0o755))
+		require.NoError(t, fsext.WriteFile(writeFs, "/results/ids.txt", []byte("0\n"), 0o644))
+
+		_, err := runtime.RunOnEventLoop(wrapInAsyncLambda(`
+			const file = await fs.open('results/ids.txt', { append: true });
+			if (file.path !== '/results/ids.txt') {
+				throw 'unexpected file path ' + file.path;
+			}
+
+			const writes = [];
+			for (let i = 1; i <= 20; i++) {
+				writes.push(file.write(i + '\n'));
+			}
+			writes.push(file.write(new Uint8Array([0x65, 0x6e, 0x64])));
+
+			const written = await Promise.all(writes);
+			if (written[0] !== 2 || written[20] !== 3) {
+				throw 'unexpected number of bytes written: ' + written;
+			}
+
+			const info = await file.stat();
+			if (info.name !== 'ids.txt') {
+				throw 'unexpected file name ' + info.name;
+			}
+
+			await file.close();
+		`))
+		require.NoError(t, err)
+
+		want := "0\n"
+		for i := 1; i <= 20; i++ {
+			want += fmt.Sprintf("%d\n", i)
+		}
+		want += "end"
+
+		got, err := fsext.ReadFile(writeFs, "/results/ids.txt")
+		require.NoError(t, err)
+		assert.Equal(t, want, string(got))
+	})
+
+	t.Run("truncating a file should replace its content", func(t *testing.T) {
+		t.Parallel()
+
+		runtime, writeFs := newWritableRuntime(t, "/results")
+		require.NoError(t, writeFs.MkdirAll("/results", 0o755))
+		require.NoError(t, fsext.WriteFile(writeFs, "/results/ids.txt", []byte("previous run"), 0o644))
+
+		_, err := runtime.RunOnEventLoop(wrapInAsyncLambda(`
+			const file = await fs.open('/results/ids.txt', { write: true, truncate: true });
+			await file.write('42');
+
+			const info = await file.stat();
+			if (info.size !== 2) {
+				throw 'unexpected file size ' + info.size;
+			}
+
+			await file.close();
+		`))
+		require.NoError(t, err)
+
+		got, err := fsext.ReadFile(writeFs, "/results/ids.txt")
+		require.NoError(t, err)
+		assert.Equal(t, "42", string(got))
+	})
+
+	t.Run("opening a missing file without the create option should fail", func(t *testing.T) {
+		t.Parallel()
+
+		runtime, writeFs := newWritableRuntime(t, "/results")
+		require.NoError(t, writeFs.MkdirAll("/results", 0o755))
+
+		_, err := runtime.RunOnEventLoop(wrapInAsyncLambda(`
+			try {
+				const file = await fs.open('/results/ids.txt', { write: true, create: false });
+				throw 'unexpected promise resolution with result: ' + file;
+			} catch (err) {
+				if (err.name !== 'NotFoundError') {
+					throw 'unexpected error: ' + err;
+				}
+			}
+		`))
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("truncate without write should fail", func(t *testing.T) {
+		t.Parallel()
+
+		runtime, _ := newWritableRuntime(t, "/results")
+
+		_, err := runtime.RunOnEventLoop(wrapInAsyncLambda(`
+			try {
+				const file = await fs.open('/results/ids.txt', { append: true, truncate: true });
+				throw 'unexpected promise resolution with result: ' + file;
+			} catch (err) {
+				if (err.name !== 'TypeError') {
+					throw 'unexpected error: ' + err;
+				}
+			}
+		`))
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("writing to a closed file should fail", func(t *testing.T) {
+		t.Parallel()
+
+		runtime, writeFs := newWritableRuntime(t, "/results")
+		require.NoError(t, writeFs.MkdirAll("/results", 0o755))
+
+		_, err := runtime.RunOnEventLoop(wrapInAsyncLambda(`
+			const file = await fs.open('/results/ids.txt', { write: true });
+			const pending = file.write('written before closing');
+			await file.close();
+			await pending;
+
+			try {
+				await file.write('written after closing');
+				throw 'unexpected write after closing the file';
+			} catch (err) {
+				if (err.name !== 'InvalidResourceError') {
+					throw 'unexpected error: ' + err;
+				}
+			}
+		`))
+		require.NoError(t, err)
+
+		got, err := fsext.ReadFile(writeFs, "/results/ids.txt")
+		require.NoError(t, err)
+		assert.Equal(t, "written before closing", string(got))
+	})
+}
+
+func TestWritableFileClosedWithContext(t *testing.T) {
+	t.Parallel()
+
+	runtime, writeFs := newWritableRuntime(t, "/results")
+	require.NoError(t, writeFs.MkdirAll("/results", 0o755))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runtime.VU.CtxField = ctx
+
+	_, err := runtime.RunOnEventLoop(wrapInAsyncLambda(`
+		globalThis.file = await fs.open('/results/ids.txt', { write: true });
+		file.write('written before the end of the iteration');
+	`))
+	require.NoError(t, err)
+
+	file, ok := runtime.VU.Runtime().Get("file").Export().(*WritableFile)
+	require.True(t, ok)
+	assert.False(t, file.closed.Load())
+
+	cancel()
+	require.Eventually(t, file.closed.Load, time.Second, 10*time.Millisecond)
+	runtime.VU.CtxField = context.Background()
+
+	_, err = runtime.RunOnEventLoop(wrapInAsyncLambda(`
+		try {
+			await file.write('written after the end of the iteration');
+			throw 'unexpected write after the end of the iteration';
+		} catch (err) {
+			if (err.name !== 'InvalidResourceError') {
+				throw 'unexpected error: ' + err;
+			}
+		}
+	`))
+	require.NoError(t, err)
+
+	got, err := fsext.ReadFile(writeFs, "/results/ids.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "written before the end of the iteration", string(got))
+}
+
+// newWritableRuntime returns a runtime in the VU context, allowed to write in the
+// given directories of the in-memory file system it returns, with a CWD of "/".
+func newWritableRuntime(t *testing.T, allowWrite ...string) (*modulestest.Runtime, fsext.Fs) {
+	t.Helper()
+
+	writeFs := fsext.NewMemMapFs()
+
+	runtime := modulestest.NewRuntime(t)
+	err := runtime.SetupModuleSystem(
+		map[string]any{"k6/experimental/fs": New()}, nil, compiler.New(runtime.VU.InitEnv().Logger),
+	)
+	require.NoError(t, err)
+
+	runtime.VU.InitEnvField.WritableFS = writeFs
+	runtime.VU.InitEnvField.FileSystems = map[string]fsext.Fs{
+		"file": fsext.NewMemMapFs(),
+	}
+	runtime.VU.InitEnvField.CWD = &url.URL{Scheme: "file", Path: fsext.FilePathSeparator}
+
+	_, err = runtime.VU.Runtime().RunString(initGlobals)
+	require.NoError(t, err)
+
+	runtime.MoveToVUContext(&lib.State{
+		Options:    lib.Options{FSAllowWrite: allowWrite},
+		VUID:       1,
+		VUIDGlobal: 7,
+		Tags:       lib.NewVUStateTags(metrics.NewRegistry().RootTagSet()),
+	})
+
+	return runtime, writeFs
+}
@@ -0,0 +1,58 @@
+package sse
+
+import "github.com/grafana/sobek"
+
+const (
+	openEvent    = "open"
+	messageEvent = "message"
+	errorEvent   = "error"
+)
+
+// listener is a JS event handler, it returns sobek.Value *and* error in order
+// to return error on exception instead of panic
+type listener func(sobek.Value) (sobek.Value, error)
+
+// eventListeners keeps track of the listeners for each event type. Unlike the
+// WebSocket ones, the event types aren't known in advance, as the server can
+// send events with any name.
+type eventListeners struct {
+	// on keeps the listeners set with the on* properties, i.e. onopen, onmessage and onerror
+	on   map[string]listener
+	list map[string][]listener
+}
+
+func newEventListeners() *eventListeners {
+	return &eventListeners{
+		on:   make(map[string]listener),
+		list: make(map[string][]listener),
+	}
+}
+
+// add adds a listener for the event type
+func (l *eventListeners) add(eventType string, fn listener) {
+	l.list[eventType] = append(l.list[eventType], fn)
+}
+
+// setOn sets the listener of an on* property, a nil one unsets it
+func (l *eventListeners) setOn(eventType string, fn listener) {
+	if fn == nil {
+		delete(l.on, eventType)
+		return
+	}
+	l.on[eventType] = fn
+}
+
+// getOn returns the listener of an on* property
+func (l *eventListeners) getOn(eventType string) listener {
+	return l.on[eventType]
+}
+
+// all returns all the listeners for the event type
+func (l *eventListeners) all(eventType string) []listener {
+	on, ok := l.on[eventType]
+	if !ok {
+		return l.list[eventType]
+	}
+
+	return append([]listener{on}, l.list[eventType]...)
+}
@@ -0,0 +1,74 @@
+package sse
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/js/common"
+	httpModule "go.k6.io/k6/js/modules/k6/http"
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/metrics"
+)
+
+// sseParams represent the parameters bag for EventSource, which is the
+// EventSourceInit dictionary with some k6 specific additions
+type sseParams struct {
+	headers         http.Header
+	cookieJar       *cookiejar.Jar
+	tagsAndMeta     *metrics.TagsAndMeta
+	withCredentials bool
+}
+
+// buildParams builds the EventSource params
+func buildParams(state *lib.State, rt *sobek.Runtime, raw sobek.Value) (*sseParams, error) {
+	tagsAndMeta := state.Tags.GetCurrentValues()
+
+	parsed := &sseParams{
+		headers:     make(http.Header),
+		cookieJar:   state.CookieJar,
+		tagsAndMeta: &tagsAndMeta,
+	}
+
+	parsed.headers.Set("User-Agent", state.Options.UserAgent.String)
+
+	if common.IsNullish(raw) {
+		return parsed, nil
+	}
+
+	params := raw.ToObject(rt)
+	for _, k := range params.Keys() {
+		switch k {
+		case "headers":
+			headersV := params.Get(k)
+			if common.IsNullish(headersV) {
+				continue
+			}
+			headersObj := headersV.ToObject(rt)
+			for _, key := range headersObj.Keys() {
+				parsed.headers.Set(key, headersObj.Get(key).String())
+			}
+		case "tags":
+			if err := common.ApplyCustomUserTags(rt, parsed.tagsAndMeta, params.Get(k)); err != nil {
+				return nil, fmt.Errorf("invalid EventSource tags option: %w", err)
+			}
+		case "jar":
+			jarV := params.Get(k)
+			if common.IsNullish(jarV) {
+				continue
+			}
+			if v, ok := jarV.Export().(*httpModule.CookieJar); ok {
+				parsed.cookieJar = v.Jar
+			}
+		case "withCredentials":
+			// there are no CORS checks in k6, so it's only reflected in the property
+			parsed.withCredentials = params.Get(k).ToBoolean()
+		default:
+			return nil, fmt.Errorf("unknown EventSource's option %s", k)
+		}
+	}
+
+	return parsed, nil
+}
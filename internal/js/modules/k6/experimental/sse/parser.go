@@ -0,0 +1,166 @@
+package sse
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// event is an event that was dispatched from the event stream
+type event struct {
+	eventType   string
+	data        string
+	lastEventID string
+	t           time.Time
+}
+
+// parser interprets an event stream as defined in
+// https://html.spec.whatwg.org/multipage/server-sent-events.html#event-stream-interpretation
+//
+// It should be reset for every new connection, but it keeps the last event ID
+// and the reconnection time between them, as they're used to reconnect.
+type parser struct {
+	r *bufio.Reader
+
+	// whether the previous line ended with a CR, so a following LF is part of it
+	skipLF bool
+	// whether the first line of the stream was already read, which can start with a BOM
+	started bool
+
+	data        strings.Builder
+	eventType   string
+	lastEventID string
+
+	retry time.Duration
+}
+
+func newParser(retry time.Duration) *parser {
+	return &parser{retry: retry}
+}
+
+// reset starts the parsing of a new stream.
+func (p *parser) reset(r io.Reader) {
+	p.r = bufio.NewReader(r)
+	p.skipLF = false
+	p.started = false
+	p.data.Reset()
+	p.eventType = ""
+}
+
+// next returns the next event from the stream or an error, which is io.EOF if
+// the stream ended. An event that wasn't terminated with a blank line before
+// the stream ended is discarded.
+func (p *parser) next() (*event, error) {
+	for {
+		line, err := p.readLine()
+		if err != nil {
+			return nil, err
+		}
+		if ev := p.processLine(line); ev != nil {
+			return ev, nil
+		}
+	}
+}
+
+// readLine reads a line that ends with a CRLF, a LF or a CR. A CR can be the
+// last byte that was received, so a LF after it is skipped on the next call,
+// instead of waiting for it.
+func (p *parser) readLine() (string, error) {
+	var line []byte
+	for {
+		b, err := p.r.ReadByte()
+		if err != nil {
+			return "", err
+		}
+		if p.skipLF {
+			p.skipLF = false
+			if b == '\n' {
+				continue
+			}
+		}
+		switch b {
+		case '\r':
+			p.skipLF = true
+			return p.finishLine(line), nil
+		case '\n':
+			return p.finishLine(line), nil
+		default:
+			line = append(line, b)
+		}
+	}
+}
+
+func (p *parser) finishLine(line []byte) string {
+	if !p.started {
+		p.started = true
+		line = bytes.TrimPrefix(line, []byte("\ufeff"))
+	}
+	return strings.ToValidUTF8(string(line), "\ufffd")
+}
+
+func (p *parser) processLine(line string) *event {
+	if line == "" {
+		return p.dispatch()
+	}
+	if line[0] == ':' { // a comment
+		return nil
+	}
+
+	field, value, found := strings.Cut(line, ":")
+	if found {
+		value = strings.TrimPrefix(value, " ")
+	}
+
+	switch field {
+	case "event":
+		p.eventType = value
+	case "data":
+		p.data.WriteString(value)
+		p.data.WriteByte('\n')
+	case "id":
+		if !strings.ContainsRune(value, 0) {
+			p.lastEventID = value
+		}
+	case "retry":
+		if !isASCIIDigits(value) {
+			break
+		}
+		if ms, err := strconv.ParseInt(value, 10, 64); err == nil && ms <= math.MaxInt64/int64(time.Millisecond) {
+			p.retry = time.Duration(ms) * time.Millisecond
+		}
+	}
+	return nil
+}
+
+func (p *parser) dispatch() *event {
+	data := p.data.String()
+	eventType := p.eventType
+	p.data.Reset()
+	p.eventType = ""
+
+	if data == "" {
+		return nil
+	}
+	if eventType == "" {
+		eventType = "message"
+	}
+	return &event{
+		eventType:   eventType,
+		data:        strings.TrimSuffix(data, "\n"),
+		lastEventID: p.lastEventID,
+		t:           time.Now(),
+	}
+}
+
+func isASCIIDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,133 @@
+package sse
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParser(t *testing.T) {
+	t.Parallel()
+
+	type expectedEvent struct {
+		eventType, data, lastEventID string
+	}
+
+	testCases := []struct {
+		name   string
+		stream string
+		events []expectedEvent
+		retry  time.Duration
+	}{
+		{
+			name:   "simple",
+			stream: "data: hello\n\n",
+			events: []expectedEvent{{"message", "hello", ""}},
+		},
+		{
+			name:   "multiline data",
+			stream: "data: YHOO\ndata: +2\ndata: 10\n\n",
+			events: []expectedEvent{{"message", "YHOO\n+2\n10", ""}},
+		},
+		{
+			name:   "named events and ids",
+			stream: ": a comment\nevent: add\nid: 1\ndata: 73857293\n\nevent: remove\ndata: 2153\n\n",
+			events: []expectedEvent{{"add", "73857293", "1"}, {"remove", "2153", "1"}},
+		},
+		{
+			name:   "empty id resets it",
+			stream: "id: 1\ndata: first\n\nid\ndata: second\n\n",
+			events: []expectedEvent{{"message", "first", "1"}, {"message", "second", ""}},
+		},
+		{
+			name:   "id with null is ignored",
+			stream: "id: 1\n\nid: 2\x00\ndata: x\n\n",
+			events: []expectedEvent{{"message", "x", "1"}},
+		},
+		{
+			name:   "only one leading space is removed",
+			stream: "data:test\n\ndata:  test\n\n",
+			events: []expectedEvent{{"message", "test", ""}, {"message", " test", ""}},
+		},
+		{
+			name:   "empty data lines",
+			stream: "data\n\ndata\ndata\n\ndata:\n",
+			events: []expectedEvent{{"message", "", ""}, {"message", "\n", ""}},
+		},
+		{
+			name:   "event without data is not dispatched",
+			stream: "event: foo\n\ndata: bar\n\n",
+			events: []expectedEvent{{"message", "bar", ""}},
+		},
+		{
+			name:   "line endings",
+			stream: "data: a\r\ndata: b\rdata: c\n\r\ndata: d\r\r",
+			events: []expectedEvent{{"message", "a\nb\nc", ""}, {"message", "d", ""}},
+		},
+		{
+			name:   "bom",
+			stream: "\ufeffdata: bom\n\n",
+			events: []expectedEvent{{"message", "bom", ""}},
+		},
+		{
+			name:   "retry",
+			stream: "retry: 1500\n\nretry: 1x\n\nretry: -1\n\n",
+			retry:  1500 * time.Millisecond,
+		},
+		{
+			name:   "unknown fields are ignored",
+			stream: "foo: bar\ndata: baz\n\n",
+			events: []expectedEvent{{"message", "baz", ""}},
+		},
+		{
+			name:   "unfinished event is discarded",
+			stream: "data: finished\n\ndata: unfinished",
+			events: []expectedEvent{{"message", "finished", ""}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := newParser(defaultRetry)
+			p.reset(strings.NewReader(tc.stream))
+
+			var events []expectedEvent
+			for {
+				ev, err := p.next()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				require.NoError(t, err)
+				events = append(events, expectedEvent{ev.eventType, ev.data, ev.lastEventID})
+			}
+
+			assert.Equal(t, tc.events, events)
+			if tc.retry != 0 {
+				assert.Equal(t, tc.retry, p.retry)
+			}
+		})
+	}
+}
+
+func TestParserKeepsLastEventIDOnReset(t *testing.T) {
+	t.Parallel()
+
+	p := newParser(defaultRetry)
+	p.reset(strings.NewReader("id: 42\nevent: foo\ndata: unfinished\n"))
+	_, err := p.next()
+	require.ErrorIs(t, err, io.EOF)
+
+	p.reset(strings.NewReader("data: next\n\n"))
+	ev, err := p.next()
+	require.NoError(t, err)
+	assert.Equal(t, "message", ev.eventType)
+	assert.Equal(t, "next", ev.data)
+	assert.Equal(t, "42", ev.lastEventID)
+}
@@ -0,0 +1,467 @@
+// Package sse implements the EventSource API for Server-Sent Events, as defined in
+// https://html.spec.whatwg.org/multipage/server-sent-events.html
+package sse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/grafana/sobek"
+	"github.com/mstoykov/k6-taskqueue-lib/taskqueue"
+
+	"go.k6.io/k6/js/common"
+	"go.k6.io/k6/js/modules"
+	"go.k6.io/k6/lib/netext"
+	"go.k6.io/k6/metrics"
+)
+
+// RootModule is the root module for the sse API
+type RootModule struct{}
+
+// EventSourceAPI is the module instance implementing the EventSource API
+type EventSourceAPI struct {
+	vu      modules.VU
+	metrics *instanceMetrics
+}
+
+var (
+	_ modules.Module   = &RootModule{}
+	_ modules.Instance = &EventSourceAPI{}
+)
+
+// New sse root module
+func New() *RootModule {
+	return &RootModule{}
+}
+
+// NewModuleInstance returns a new instance of the module
+func (r *RootModule) NewModuleInstance(vu modules.VU) modules.Instance {
+	m, err := registerMetrics(vu.InitEnv().Registry)
+	if err != nil {
+		common.Throw(vu.Runtime(), fmt.Errorf("failed to register SSE module metrics: %w", err))
+	}
+
+	return &EventSourceAPI{
+		vu:      vu,
+		metrics: m,
+	}
+}
+
+// Exports implements the modules.Instance interface's Exports
+func (r *EventSourceAPI) Exports() modules.Exports {
+	rt := r.vu.Runtime()
+	constructor := rt.ToValue(r.eventSource).ToObject(rt)
+	defineReadyStates(rt, constructor)
+
+	return modules.Exports{
+		Named: map[string]interface{}{
+			"EventSource": constructor,
+		},
+	}
+}
+
+// ReadyState is the EventSource specification's readyState
+type ReadyState uint8
+
+const (
+	// CONNECTING is the state while the connection is being established or reestablished
+	CONNECTING ReadyState = iota
+	// OPEN is the state while events are being received
+	OPEN
+	// CLOSED is the state after the connection failed or was closed with close()
+	CLOSED
+)
+
+// defaultRetry is the reconnection time until the server sets it with a retry field
+const defaultRetry = 3 * time.Second
+
+type eventSource struct {
+	vu      modules.VU
+	metrics *instanceMetrics
+
+	url            *url.URL
+	params         *sseParams
+	tq             *taskqueue.TaskQueue
+	obj            *sobek.Object // the object that is given to js to interact with the EventSource
+	cancel         context.CancelFunc
+	eventListeners *eventListeners
+
+	// fields that should be seen by js only be updated on the event loop
+	readyState ReadyState
+}
+
+func (r *EventSourceAPI) eventSource(c sobek.ConstructorCall) *sobek.Object {
+	rt := r.vu.Runtime()
+	state := r.vu.State()
+	if state == nil {
+		common.Throw(rt, errors.New("EventSource can't be created in the init context"))
+	}
+
+	u, err := parseURL(c.Argument(0))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+
+	params, err := buildParams(state, rt, c.Argument(1))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+
+	systemTags := state.Options.SystemTags
+	params.tagsAndMeta.SetSystemTagOrMetaIfEnabled(systemTags, metrics.TagMethod, http.MethodGet)
+	nameTagValue, nameTagManuallySet := params.tagsAndMeta.Tags.Get(metrics.TagName.String())
+	// After k6 v0.41.0, the `name` and `url` tags have the exact same values:
+	if nameTagManuallySet {
+		params.tagsAndMeta.SetSystemTagOrMetaIfEnabled(systemTags, metrics.TagURL, nameTagValue)
+	} else {
+		params.tagsAndMeta.SetSystemTagOrMetaIfEnabled(systemTags, metrics.TagURL, u.String())
+		params.tagsAndMeta.SetSystemTagOrMetaIfEnabled(systemTags, metrics.TagName, u.String())
+	}
+
+	ctx, cancel := context.WithCancel(r.vu.Context())
+	es := &eventSource{
+		vu:             r.vu,
+		metrics:        r.metrics,
+		url:            u,
+		params:         params,
+		tq:             taskqueue.New(r.vu.RegisterCallback),
+		obj:            rt.NewObject(),
+		cancel:         cancel,
+		eventListeners: newEventListeners(),
+		readyState:     CONNECTING,
+	}
+
+	defineEventSource(rt, es)
+
+	go es.run(ctx)
+	return es.obj
+}
+
+// parseURL parses the url from the first constructor calls argument or returns an error
+func parseURL(urlValue sobek.Value) (*url.URL, error) {
+	if common.IsNullish(urlValue) {
+		return nil, errors.New("EventSource requires a url")
+	}
+
+	urlString := urlValue.String()
+	u, err := url.Parse(urlString)
+	if err != nil {
+		return nil, fmt.Errorf("EventSource requires valid url, but got %q which resulted in %w", urlString, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("EventSource requires url with scheme http or https, but got %q", u.Scheme)
+	}
+
+	return u, nil
+}
+
+// must is a small helper that will panic if err is not nil.
+func must(rt *sobek.Runtime, err error) {
+	if err != nil {
+		common.Throw(rt, err)
+	}
+}
+
+func defineReadyStates(rt *sobek.Runtime, o *sobek.Object) {
+	for name, value := range map[string]ReadyState{"CONNECTING": CONNECTING, "OPEN": OPEN, "CLOSED": CLOSED} {
+		must(rt, o.DefineDataProperty(name, rt.ToValue(uint(value)), sobek.FLAG_FALSE, sobek.FLAG_FALSE, sobek.FLAG_TRUE))
+	}
+}
+
+// defineEventSource defines all properties and methods for the EventSource
+func defineEventSource(rt *sobek.Runtime, es *eventSource) {
+	defineReadyStates(rt, es.obj)
+	must(rt, es.obj.DefineDataProperty(
+		"addEventListener", rt.ToValue(es.addEventListener), sobek.FLAG_FALSE, sobek.FLAG_FALSE, sobek.FLAG_TRUE))
+	must(rt, es.obj.DefineDataProperty(
+		"close", rt.ToValue(es.close), sobek.FLAG_FALSE, sobek.FLAG_FALSE, sobek.FLAG_TRUE))
+	must(rt, es.obj.DefineDataProperty(
+		"url", rt.ToValue(es.url.String()), sobek.FLAG_FALSE, sobek.FLAG_FALSE, sobek.FLAG_TRUE))
+	must(rt, es.obj.DefineDataProperty(
+		"withCredentials", rt.ToValue(es.params.withCredentials), sobek.FLAG_FALSE, sobek.FLAG_FALSE, sobek.FLAG_TRUE))
+	must(rt, es.obj.DefineAccessorProperty( // this needs to be with an accessor as we change the value
+		"readyState", rt.ToValue(func() sobek.Value {
+			return rt.ToValue((uint)(es.readyState))
+		}), nil, sobek.FLAG_FALSE, sobek.FLAG_TRUE))
+
+	setOn := func(property string, eventType string) {
+		must(rt, es.obj.DefineAccessorProperty(
+			property, rt.ToValue(func() sobek.Value {
+				if on := es.eventListeners.getOn(eventType); on != nil {
+					return rt.ToValue(on)
+				}
+				return sobek.Null()
+			}), rt.ToValue(func(call sobek.FunctionCall) sobek.Value {
+				arg := call.Argument(0)
+
+				// it's possible to unset handlers by setting them to null
+				if common.IsNullish(arg) {
+					es.eventListeners.setOn(eventType, nil)
+
+					return nil
+				}
+
+				fn, isFunc := sobek.AssertFunction(arg)
+				if !isFunc {
+					common.Throw(rt, fmt.Errorf("a value for '%s' should be callable", property))
+				}
+
+				es.eventListeners.setOn(eventType, func(v sobek.Value) (sobek.Value, error) {
+					return fn(sobek.Undefined(), v)
+				})
+
+				return nil
+			}), sobek.FLAG_FALSE, sobek.FLAG_TRUE))
+	}
+
+	setOn("onopen", openEvent)
+	setOn("onmessage", messageEvent)
+	setOn("onerror", errorEvent)
+}
+
+// run connects to the event stream and reestablishes the connection every
+// time it's lost, until it fails, the EventSource is closed or the VU is done.
+func (es *eventSource) run(ctx context.Context) {
+	defer es.tq.Close()
+
+	p := newParser(defaultRetry)
+	for reconnecting := false; ; reconnecting = true {
+		if reconnecting {
+			es.pushSample(ctx, es.metrics.Reconnects, es.params.tagsAndMeta, time.Now(), 1)
+		}
+		if !es.connect(ctx, p) {
+			return
+		}
+
+		timer := time.NewTimer(p.retry)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// connect makes a request for the event stream and dispatches its events,
+// it returns whether the connection should be reestablished after it ends.
+func (es *eventSource) connect(ctx context.Context, p *parser) bool {
+	state := es.vu.State()
+
+	var remoteAddr net.Addr
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) { remoteAddr = info.Conn.RemoteAddr() },
+	}
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(ctx, trace), http.MethodGet, es.url.String(), nil)
+	if err != nil {
+		es.queueFail(err)
+		return false
+	}
+	req.Header = es.params.headers.Clone()
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Cache-Control", "no-store")
+	if p.lastEventID != "" {
+		req.Header.Set("Last-Event-ID", p.lastEventID)
+	}
+
+	client := &http.Client{Transport: state.Transport}
+	// this is needed because of how interfaces work and that client.Jar is http.Cookiejar
+	if es.params.cookieJar != nil {
+		client.Jar = es.params.cookieJar
+	}
+
+	start := time.Now()
+	res, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return false
+		}
+		es.queueReestablish(err)
+		return true
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	tagsAndMeta := es.connectionTags(res, remoteAddr)
+	if res.StatusCode != http.StatusOK {
+		es.queueFail(fmt.Errorf("EventSource got an unexpected response status %q", res.Status))
+		return false
+	}
+	if mediaType, _, _ := mime.ParseMediaType(res.Header.Get("Content-Type")); mediaType != "text/event-stream" {
+		es.queueFail(fmt.Errorf("EventSource got an unexpected response content type %q", res.Header.Get("Content-Type")))
+		return false
+	}
+
+	es.queueAnnounce()
+
+	origin := res.Request.URL.Scheme + "://" + res.Request.URL.Host
+	p.reset(res.Body)
+	for first := true; ; first = false {
+		ev, err := p.next()
+		if err != nil {
+			if ctx.Err() != nil {
+				return false
+			}
+			if errors.Is(err, io.EOF) {
+				err = errors.New("the event stream was closed by the server")
+			}
+			es.queueReestablish(err)
+			return true
+		}
+
+		if first {
+			es.pushSample(ctx, es.metrics.TimeToFirstEvent, tagsAndMeta, ev.t, metrics.D(ev.t.Sub(start)))
+		}
+		es.pushSample(ctx, es.metrics.EventsReceived, tagsAndMeta, ev.t, 1)
+		es.queueEvent(ev, origin)
+	}
+}
+
+// connectionTags returns the tags and metadata of the samples for the events
+// of a connection, which are tagged like the k6/http requests.
+func (es *eventSource) connectionTags(res *http.Response, remoteAddr net.Addr) *metrics.TagsAndMeta {
+	tagsAndMeta := es.params.tagsAndMeta.Clone()
+	systemTags := es.vu.State().Options.SystemTags
+
+	tagsAndMeta.SetSystemTagOrMetaIfEnabled(systemTags, metrics.TagStatus, strconv.Itoa(res.StatusCode))
+	tagsAndMeta.SetSystemTagOrMetaIfEnabled(systemTags, metrics.TagProto, res.Proto)
+	if res.TLS != nil {
+		tlsInfo, _ := netext.ParseTLSConnState(res.TLS)
+		tagsAndMeta.SetSystemTagOrMetaIfEnabled(systemTags, metrics.TagTLSVersion, tlsInfo.Version)
+	}
+	if remoteAddr != nil {
+		if ip, _, err := net.SplitHostPort(remoteAddr.String()); err == nil {
+			tagsAndMeta.SetSystemTagOrMetaIfEnabled(systemTags, metrics.TagIP, ip)
+		}
+	}
+
+	return &tagsAndMeta
+}
+
+func (es *eventSource) pushSample(
+	ctx context.Context, metric *metrics.Metric, tagsAndMeta *metrics.TagsAndMeta, t time.Time, value float64,
+) {
+	metrics.PushIfNotDone(ctx, es.vu.State().Samples, metrics.Sample{
+		TimeSeries: metrics.TimeSeries{
+			Metric: metric,
+			Tags:   tagsAndMeta.Tags,
+		},
+		Time:     t,
+		Metadata: tagsAndMeta.Metadata,
+		Value:    value,
+	})
+}
+
+// documented https://html.spec.whatwg.org/multipage/server-sent-events.html#announce-the-connection
+func (es *eventSource) queueAnnounce() {
+	es.tq.Queue(func() error {
+		if es.readyState == CLOSED {
+			return nil
+		}
+		es.readyState = OPEN
+		return es.callEventListeners(openEvent, es.newEvent(openEvent, time.Now()))
+	})
+}
+
+// documented https://html.spec.whatwg.org/multipage/server-sent-events.html#reestablish-the-connection
+func (es *eventSource) queueReestablish(err error) {
+	es.tq.Queue(func() error {
+		if es.readyState == CLOSED {
+			return nil
+		}
+		es.readyState = CONNECTING
+		return es.callErrorListeners(err)
+	})
+}
+
+// documented https://html.spec.whatwg.org/multipage/server-sent-events.html#fail-the-connection
+func (es *eventSource) queueFail(err error) {
+	es.tq.Queue(func() error {
+		if es.readyState == CLOSED {
+			return nil
+		}
+		es.readyState = CLOSED
+		return es.callErrorListeners(err)
+	})
+}
+
+func (es *eventSource) queueEvent(ev *event, origin string) {
+	es.tq.Queue(func() error {
+		if es.readyState == CLOSED {
+			return nil
+		}
+
+		rt := es.vu.Runtime()
+		o := es.newEvent(ev.eventType, ev.t)
+		must(rt, o.DefineDataProperty("data", rt.ToValue(ev.data), sobek.FLAG_FALSE, sobek.FLAG_FALSE, sobek.FLAG_TRUE))
+		must(rt, o.DefineDataProperty("origin", rt.ToValue(origin), sobek.FLAG_FALSE, sobek.FLAG_FALSE, sobek.FLAG_TRUE))
+		must(rt, o.DefineDataProperty(
+			"lastEventId", rt.ToValue(ev.lastEventID), sobek.FLAG_FALSE, sobek.FLAG_FALSE, sobek.FLAG_TRUE))
+
+		return es.callEventListeners(ev.eventType, o)
+	})
+}
+
+// newEvent return an event implementing "implements" https://dom.spec.whatwg.org/#event
+// needs to be called on the event loop
+func (es *eventSource) newEvent(eventType string, t time.Time) *sobek.Object {
+	rt := es.vu.Runtime()
+	o := rt.NewObject()
+
+	must(rt, o.DefineAccessorProperty("type", rt.ToValue(func() string {
+		return eventType
+	}), nil, sobek.FLAG_FALSE, sobek.FLAG_TRUE))
+	must(rt, o.DefineAccessorProperty("target", rt.ToValue(func() interface{} {
+		return es.obj
+	}), nil, sobek.FLAG_FALSE, sobek.FLAG_TRUE))
+	must(rt, o.DefineAccessorProperty("timestamp", rt.ToValue(func() float64 {
+		return float64(t.UnixNano()) / 1_000_000 // milliseconds as double as per the spec
+		// https://w3c.github.io/hr-time/#dom-domhighrestimestamp
+	}), nil, sobek.FLAG_FALSE, sobek.FLAG_TRUE))
+
+	return o
+}
+
+func (es *eventSource) callErrorListeners(e error) error {
+	rt := es.vu.Runtime()
+
+	ev := es.newEvent(errorEvent, time.Now())
+	must(rt, ev.DefineDataProperty("error", rt.ToValue(e.Error()), sobek.FLAG_FALSE, sobek.FLAG_FALSE, sobek.FLAG_TRUE))
+	return es.callEventListeners(errorEvent, ev)
+}
+
+func (es *eventSource) callEventListeners(eventType string, ev *sobek.Object) error {
+	for _, listener := range es.eventListeners.all(eventType) {
+		if _, err := listener(ev); err != nil {
+			es.close()
+			return err
+		}
+	}
+	return nil
+}
+
+func (es *eventSource) addEventListener(eventType string, handler func(sobek.Value) (sobek.Value, error)) {
+	// TODO support options https://developer.mozilla.org/en-US/docs/Web/API/EventTarget/addEventListener#parameters
+	if handler == nil {
+		common.Throw(es.vu.Runtime(), fmt.Errorf("handler for event type %q isn't a callable function", eventType))
+	}
+
+	es.eventListeners.add(eventType, handler)
+}
+
+// close aborts the connection and stops the reconnecting, no more events are
+// dispatched after it
+func (es *eventSource) close() {
+	es.readyState = CLOSED
+	es.cancel()
+}
@@ -0,0 +1,315 @@
+package sse
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/internal/lib/testutils/httpmultibin"
+	"go.k6.io/k6/js/modulestest"
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/metrics"
+)
+
+type testState struct {
+	tb      *httpmultibin.HTTPMultiBin
+	runtime *modulestest.Runtime
+	samples chan metrics.SampleContainer
+
+	callRecorder *callRecorder
+}
+
+// callRecorder a helper type that records all calls
+type callRecorder struct {
+	sync.Mutex
+	calls []string
+}
+
+// Call records a call
+func (r *callRecorder) Call(text string) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.calls = append(r.calls, text)
+}
+
+// Recorded returns a copy of the recorded calls
+func (r *callRecorder) Recorded() []string {
+	r.Lock()
+	defer r.Unlock()
+
+	result := []string{}
+	result = append(result, r.calls...)
+
+	return result
+}
+
+func newTestState(t testing.TB) testState {
+	runtime := modulestest.NewRuntime(t)
+	tb := httpmultibin.NewHTTPMultiBin(t)
+
+	samples := make(chan metrics.SampleContainer, 1000)
+	state := &lib.State{
+		Dialer:    tb.Dialer,
+		Transport: tb.HTTPTransport,
+		Options: lib.Options{
+			SystemTags: metrics.NewSystemTagSet(
+				metrics.TagURL,
+				metrics.TagName,
+				metrics.TagMethod,
+				metrics.TagProto,
+				metrics.TagStatus,
+			),
+			UserAgent: null.StringFrom("TestUserAgent"),
+		},
+		Samples:        samples,
+		TLSConfig:      tb.TLSClientConfig,
+		BuiltinMetrics: runtime.BuiltinMetrics,
+		Tags:           lib.NewVUStateTags(runtime.VU.InitEnvField.Registry.RootTagSet()),
+	}
+
+	recorder := &callRecorder{
+		calls: make([]string, 0),
+	}
+
+	m := new(RootModule).NewModuleInstance(runtime.VU)
+	require.NoError(t, runtime.VU.RuntimeField.Set("EventSource", m.Exports().Named["EventSource"]))
+	require.NoError(t, runtime.VU.RuntimeField.Set("call", recorder.Call))
+
+	runtime.MoveToVUContext(state)
+	return testState{
+		runtime:      runtime,
+		tb:           tb,
+		samples:      samples,
+		callRecorder: recorder,
+	}
+}
+
+// addStreamHandler adds a handler that writes the stream and then keeps the
+// connection open until the client closes it.
+func (ts *testState) addStreamHandler(uri string, stream func(req *http.Request) string) {
+	ts.tb.Mux.HandleFunc(uri, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprint(w, stream(req))
+		w.(http.Flusher).Flush()
+		<-req.Context().Done()
+	}))
+}
+
+func countSamples(sampleContainers []metrics.SampleContainer, metricName string) (int, []metrics.Sample) {
+	var found []metrics.Sample
+	for _, sampleContainer := range sampleContainers {
+		for _, sample := range sampleContainer.GetSamples() {
+			if sample.Metric.Name == metricName {
+				found = append(found, sample)
+			}
+		}
+	}
+	return len(found), found
+}
+
+func TestBasic(t *testing.T) {
+	t.Parallel()
+	ts := newTestState(t)
+	sr := ts.tb.Replacer.Replace
+	ts.addStreamHandler("/sse", func(req *http.Request) string {
+		assert.Equal(t, "text/event-stream", req.Header.Get("Accept"))
+		assert.Equal(t, "TestUserAgent", req.Header.Get("User-Agent"))
+		assert.Equal(t, "bar", req.Header.Get("X-Foo"))
+		return ": comment\nevent: greeting\nid: 1\ndata: hi\n\ndata: first\ndata: second\n\n"
+	})
+
+	_, err := ts.runtime.RunOnEventLoop(sr(`
+		var es = new EventSource("HTTPBIN_URL/sse", { headers: { "X-Foo": "bar" } });
+		call("readyState " + es.readyState);
+		es.onopen = () => call("open " + es.readyState);
+		es.addEventListener("greeting", (e) => {
+			call(e.type + " " + e.data + " " + e.lastEventId + " " + (e.origin === "HTTPBIN_URL"));
+		});
+		es.onmessage = (e) => {
+			call(e.type + " " + JSON.stringify(e.data) + " " + e.lastEventId);
+			es.close();
+			call("closed " + es.readyState);
+		};
+	`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"readyState 0",
+		"open 1",
+		"greeting hi 1 true",
+		`message "first\nsecond" 1`,
+		"closed 2",
+	}, ts.callRecorder.Recorded())
+
+	samples := metrics.GetBufferedSamples(ts.samples)
+	count, ttfe := countSamples(samples, "sse_time_to_first_event")
+	require.Equal(t, 1, count)
+	assert.Equal(t, map[string]string{
+		"url":    sr("HTTPBIN_URL/sse"),
+		"name":   sr("HTTPBIN_URL/sse"),
+		"method": "GET",
+		"status": "200",
+		"proto":  "HTTP/1.1",
+	}, ttfe[0].Tags.Map())
+	count, _ = countSamples(samples, "sse_events_received")
+	assert.Equal(t, 2, count)
+	count, _ = countSamples(samples, "sse_reconnects")
+	assert.Equal(t, 0, count)
+}
+
+func TestReconnect(t *testing.T) {
+	t.Parallel()
+	ts := newTestState(t)
+	sr := ts.tb.Replacer.Replace
+
+	var requests int
+	ts.tb.Mux.HandleFunc("/sse", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "text/event-stream")
+		if requests == 1 {
+			// end the stream after the first event, so the client reconnects
+			_, _ = fmt.Fprint(w, "retry: 10\nid: 42\ndata: first\n\n")
+			return
+		}
+		_, _ = fmt.Fprintf(w, "data: after %s\n\n", req.Header.Get("Last-Event-ID"))
+		w.(http.Flusher).Flush()
+		<-req.Context().Done()
+	}))
+
+	_, err := ts.runtime.RunOnEventLoop(sr(`
+		var es = new EventSource("HTTPBIN_URL/sse");
+		es.onopen = () => call("open");
+		es.onerror = (e) => call("error " + es.readyState + " " + e.error);
+		es.onmessage = (e) => {
+			call("message " + e.data);
+			if (e.data !== "first") {
+				es.close();
+			}
+		};
+	`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"open",
+		"message first",
+		"error 0 the event stream was closed by the server",
+		"open",
+		"message after 42",
+	}, ts.callRecorder.Recorded())
+
+	samples := metrics.GetBufferedSamples(ts.samples)
+	count, _ := countSamples(samples, "sse_reconnects")
+	assert.Equal(t, 1, count)
+	count, _ = countSamples(samples, "sse_time_to_first_event")
+	assert.Equal(t, 2, count)
+	count, _ = countSamples(samples, "sse_events_received")
+	assert.Equal(t, 2, count)
+}
+
+func TestFailConnection(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		handler http.HandlerFunc
+		err     string
+	}{
+		"status": {
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			},
+			err: `EventSource got an unexpected response status "204 No Content"`,
+		},
+		"content type": {
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "text/plain")
+				_, _ = w.Write([]byte("data: nope\n\n"))
+			},
+			err: `EventSource got an unexpected response content type "text/plain"`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ts := newTestState(t)
+			sr := ts.tb.Replacer.Replace
+			ts.tb.Mux.HandleFunc("/sse", tc.handler)
+
+			// the connection isn't reestablished, so the event loop ends without close()
+			_, err := ts.runtime.RunOnEventLoop(sr(`
+				var es = new EventSource("HTTPBIN_URL/sse");
+				es.onopen = () => call("open");
+				es.onmessage = () => call("message");
+				es.onerror = (e) => call("error " + es.readyState + " " + e.error);
+			`))
+			require.NoError(t, err)
+			assert.Equal(t, []string{"error 2 " + tc.err}, ts.callRecorder.Recorded())
+		})
+	}
+}
+
+func TestExceptionInListener(t *testing.T) {
+	t.Parallel()
+	ts := newTestState(t)
+	sr := ts.tb.Replacer.Replace
+	ts.addStreamHandler("/sse", func(_ *http.Request) string {
+		return "data: hi\n\n"
+	})
+
+	_, err := ts.runtime.RunOnEventLoop(sr(`
+		var es = new EventSource("HTTPBIN_URL/sse");
+		es.onmessage = () => { throw new Error("boom"); };
+	`))
+	require.ErrorContains(t, err, "boom")
+}
+
+func TestInvalidArguments(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		script string
+		err    string
+	}{
+		"no url": {
+			script: `new EventSource()`,
+			err:    "EventSource requires a url",
+		},
+		"ws url": {
+			script: `new EventSource("ws://example.com")`,
+			err:    `EventSource requires url with scheme http or https, but got "ws"`,
+		},
+		"unknown option": {
+			script: `new EventSource("http://example.com", { foo: "bar" })`,
+			err:    "unknown EventSource's option foo",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ts := newTestState(t)
+			_, err := ts.runtime.RunOnEventLoop(tc.script)
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestReadyStateConstants(t *testing.T) {
+	t.Parallel()
+	ts := newTestState(t)
+	sr := ts.tb.Replacer.Replace
+	ts.addStreamHandler("/sse", func(_ *http.Request) string { return "" })
+
+	_, err := ts.runtime.RunOnEventLoop(sr(`
+		call([EventSource.CONNECTING, EventSource.OPEN, EventSource.CLOSED].join(","));
+		var es = new EventSource("HTTPBIN_URL/sse", { withCredentials: true });
+		call([es.CONNECTING, es.OPEN, es.CLOSED, es.withCredentials].join(","));
+		es.close();
+	`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0,1,2", "0,1,2,true"}, ts.callRecorder.Recorded())
+}
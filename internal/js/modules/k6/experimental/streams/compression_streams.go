@@ -0,0 +1,461 @@
+package streams
+
+import (
+	"bytes"
+	"compress/flate"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/js/common"
+	"go.k6.io/k6/lib/netext/httpext"
+)
+
+// CompressionStream is a [TransformStream] compressing the bytes written to its writable side
+// into the chunks read from its readable side, as defined by the [Compression Standard].
+//
+// On top of the standard formats ("gzip", "deflate" and "deflate-raw"), it supports the
+// "br" and "zstd" formats, as the HTTP requests of k6 do.
+//
+// [Compression Standard]: https://compression.spec.whatwg.org/#compressionstream
+type CompressionStream struct {
+	// Readable is the JS object of the readable side of the stream
+	Readable *sobek.Object `js:"readable"`
+
+	// Writable is the JS object of the writable side of the stream
+	Writable *sobek.Object `js:"writable"`
+}
+
+// DecompressionStream is a [TransformStream] decompressing the bytes written to its writable
+// side into the chunks read from its readable side, as defined by the [Compression Standard].
+//
+// It supports the same formats as the [CompressionStream].
+//
+// [Compression Standard]: https://compression.spec.whatwg.org/#decompressionstream
+type DecompressionStream struct {
+	// Readable is the JS object of the readable side of the stream
+	Readable *sobek.Object `js:"readable"`
+
+	// Writable is the JS object of the writable side of the stream
+	Writable *sobek.Object `js:"writable"`
+}
+
+// compressionFormatDeflateRaw is the format of the raw DEFLATE data, without the zlib
+// header and trailer of the "deflate" format. It isn't a content encoding of HTTP.
+const compressionFormatDeflateRaw = "deflate-raw"
+
+// newCompressionWriter returns a writer compressing the data written to it in the given
+// format into w.
+func newCompressionWriter(format string, w io.Writer) (io.WriteCloser, error) {
+	if format == compressionFormatDeflateRaw {
+		return flate.NewWriter(w, flate.DefaultCompression)
+	}
+
+	compression, err := httpext.CompressionTypeString(format)
+	if err != nil {
+		return nil, err
+	}
+
+	return httpext.NewCompressionWriter(compression, w)
+}
+
+// newDecompressionReader returns a reader decompressing the data read from r, which is
+// compressed in the given format.
+func newDecompressionReader(format string, r io.Reader) (io.Reader, error) {
+	if format == compressionFormatDeflateRaw {
+		return flate.NewReader(r), nil
+	}
+
+	compression, err := httpext.CompressionTypeString(format)
+	if err != nil {
+		return nil, err
+	}
+
+	return httpext.NewDecompressionReader(compression, r)
+}
+
+// validateCompressionFormat throws a TypeError if the given format isn't supported.
+func validateCompressionFormat(rt *sobek.Runtime, format sobek.Value) string {
+	if common.IsNullish(format) {
+		throw(rt, newTypeError(rt, "a compression format is required"))
+	}
+
+	if f := format.String(); f == compressionFormatDeflateRaw {
+		return f
+	} else if _, err := httpext.CompressionTypeString(f); err == nil {
+		return f
+	}
+
+	throw(rt, newTypeError(rt, fmt.Sprintf("unsupported compression format %q", format.String())))
+	return ""
+}
+
+// NewCompressionStream is the constructor for the [CompressionStream] object.
+//
+// [CompressionStream]: https://compression.spec.whatwg.org/#compressionstream
+func (mi *ModuleInstance) NewCompressionStream(call sobek.ConstructorCall) *sobek.Object {
+	rt := mi.vu.Runtime()
+
+	// 1. If format is unsupported in CompressionStream, then throw a TypeError.
+	// 2. Set this's format to format.
+	format := validateCompressionFormat(rt, call.Argument(0))
+
+	var (
+		stream *TransformStream
+		output bytes.Buffer
+	)
+
+	compressor, err := newCompressionWriter(format, &output)
+	if err != nil {
+		throw(rt, newTypeError(rt, err.Error()))
+	}
+
+	// 3. Let transformAlgorithm be an algorithm which takes a chunk argument and runs the compress
+	// and enqueue a chunk algorithm with this and chunk.
+	transformAlgorithm := func(chunk sobek.Value) *sobek.Promise {
+		data := bufferSourceBytes(rt, chunk)
+		if _, err := compressor.Write(data); err != nil {
+			throw(rt, newTypeError(rt, err.Error()))
+		}
+		enqueueBytes(stream.controller, &output)
+
+		return newResolvedPromise(mi.vu, sobek.Undefined())
+	}
+
+	// 4. Let flushAlgorithm be an algorithm which takes no argument and runs the compress flush
+	// and enqueue algorithm with this.
+	flushAlgorithm := func() *sobek.Promise {
+		if err := compressor.Close(); err != nil {
+			throw(rt, newTypeError(rt, err.Error()))
+		}
+		enqueueBytes(stream.controller, &output)
+
+		return newResolvedPromise(mi.vu, sobek.Undefined())
+	}
+
+	// 5. Set this's transform to a new TransformStream.
+	// 6. Set up this's transform with transformAlgorithm set to transformAlgorithm and flushAlgorithm
+	// set to flushAlgorithm.
+	stream = mi.newTransformStreamFromAlgorithms(transformAlgorithm, flushAlgorithm, nil)
+
+	return newGenericTransformStreamObject(rt, &CompressionStream{
+		Readable: stream.Readable,
+		Writable: stream.Writable,
+	}, call.This.Prototype())
+}
+
+// NewDecompressionStream is the constructor for the [DecompressionStream] object.
+//
+// The data is decompressed in a goroutine, to which the chunks written to the stream are
+// handed over as they arrive, so the compressed data never needs to be fully in memory.
+//
+// [DecompressionStream]: https://compression.spec.whatwg.org/#decompressionstream
+func (mi *ModuleInstance) NewDecompressionStream(call sobek.ConstructorCall) *sobek.Object {
+	rt := mi.vu.Runtime()
+
+	// 1. If format is unsupported in DecompressionStream, then throw a TypeError.
+	// 2. Set this's format to format.
+	format := validateCompressionFormat(rt, call.Argument(0))
+
+	var stream *TransformStream
+	decompressor := newStreamDecompressor(mi.vu.Context(), format)
+
+	// 3. Let transformAlgorithm be an algorithm which takes a chunk argument and runs the decompress
+	// and enqueue a chunk algorithm with this and chunk.
+	transformAlgorithm := func(chunk sobek.Value) *sobek.Promise {
+		// The chunk is copied, as its buffer may be modified by the script while it's decompressed.
+		data := bytes.Clone(bufferSourceBytes(rt, chunk))
+
+		return mi.decompressAsync(stream, decompressor, func() error {
+			return decompressor.write(data)
+		})
+	}
+
+	// 4. Let flushAlgorithm be an algorithm which takes no argument and runs the decompress flush
+	// and enqueue algorithm with this.
+	flushAlgorithm := func() *sobek.Promise {
+		decompressor.closeInput()
+
+		return mi.decompressAsync(stream, decompressor, decompressor.wait)
+	}
+
+	// The decompression goroutine must stop when the stream is canceled or aborted.
+	cancelAlgorithm := func(sobek.Value) *sobek.Promise {
+		decompressor.closeInput()
+
+		return newResolvedPromise(mi.vu, sobek.Undefined())
+	}
+
+	// 5. Set this's transform to a new TransformStream.
+	// 6. Set up this's transform with transformAlgorithm set to transformAlgorithm and flushAlgorithm
+	// set to flushAlgorithm.
+	stream = mi.newTransformStreamFromAlgorithms(transformAlgorithm, flushAlgorithm, cancelAlgorithm)
+
+	return newGenericTransformStreamObject(rt, &DecompressionStream{
+		Readable: stream.Readable,
+		Writable: stream.Writable,
+	}, call.This.Prototype())
+}
+
+// decompressAsync runs the given decompression step off the event loop, and returns a promise
+// resolved once it's done and the data decompressed so far is enqueued to the stream.
+func (mi *ModuleInstance) decompressAsync(
+	stream *TransformStream,
+	decompressor *streamDecompressor,
+	step func() error,
+) *sobek.Promise {
+	rt := mi.vu.Runtime()
+	promise := newPromiseCapability(rt)
+
+	callback := mi.vu.RegisterCallback()
+	go func() {
+		err := step()
+		callback(func() error {
+			// The readable side may have been canceled in the meantime.
+			output := decompressor.takeOutput()
+			if stream.readableController().canCloseOrEnqueue() {
+				enqueueBytes(stream.controller, bytes.NewBuffer(output))
+			}
+
+			if err != nil {
+				promise.reject(newTypeError(rt, "unable to decompress the data: "+err.Error()))
+				return nil
+			}
+
+			promise.resolve(sobek.Undefined())
+			return nil
+		})
+	}()
+
+	return promise.promise
+}
+
+// newGenericTransformStreamObject creates the [sobek.Object] representing a stream exposing
+// the readable and writable sides of a transform stream, with the given prototype.
+func newGenericTransformStreamObject(rt *sobek.Runtime, stream any, proto *sobek.Object) *sobek.Object {
+	streamObj := rt.ToValue(stream).ToObject(rt)
+
+	if err := streamObj.SetPrototype(proto); err != nil {
+		common.Throw(rt, newError(RuntimeError, err.Error()))
+	}
+
+	return streamObj
+}
+
+// bufferSourceBytes returns the bytes of the given BufferSource chunk, or throws
+// a TypeError if the chunk isn't a BufferSource.
+func bufferSourceBytes(rt *sobek.Runtime, chunk sobek.Value) []byte {
+	if !common.IsNullish(chunk) {
+		if buffer, ok := chunk.Export().(sobek.ArrayBuffer); ok {
+			return buffer.Bytes()
+		}
+
+		if view, ok := toArrayBufferView(rt, chunk); ok {
+			return view.buffer.Bytes()[view.byteOffset : view.byteOffset+view.byteLength]
+		}
+	}
+
+	throw(rt, newTypeError(rt, "the chunk must be an ArrayBuffer or an ArrayBufferView"))
+	return nil
+}
+
+// enqueueBytes enqueues the bytes of the given buffer, if any, as a Uint8Array chunk
+// of the readable side of the stream, and resets the buffer.
+func enqueueBytes(controller *TransformStreamDefaultController, buffer *bytes.Buffer) {
+	if buffer.Len() == 0 {
+		return
+	}
+
+	rt := controller.stream.runtime
+	data := bytes.Clone(buffer.Bytes())
+	buffer.Reset()
+
+	array, err := newUint8Array(rt, rt.NewArrayBuffer(data), 0, len(data))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+
+	controller.enqueue(array)
+}
+
+// errTrailingData is the error of the data written to a decompression stream after the
+// end of the compressed data.
+var errTrailingData = errors.New("unexpected data after the end of the compressed data")
+
+// streamDecompressor decompresses the chunks handed over to it in a goroutine.
+//
+// The chunks are handed over one at a time, and each write waits for the decoder to have
+// consumed the whole chunk, so the output of a chunk is mostly available once it's written.
+type streamDecompressor struct {
+	// chunks hands over the compressed chunks to the decoder, and is closed at the end of the data
+	chunks chan []byte
+
+	// drained is signaled by the decoder each time it has consumed a whole chunk
+	drained chan struct{}
+
+	// done is closed once the decoder has stopped
+	done chan struct{}
+
+	// closed is whether the chunks channel is closed; it's only accessed on the event loop
+	closed bool
+
+	// err is the error the decoder stopped with, which is set before done is closed
+	err error
+
+	// reader is the reader the decoder reads the chunks from
+	reader *chunksReader
+
+	mu     sync.Mutex
+	output bytes.Buffer
+}
+
+// newStreamDecompressor starts decompressing the data of the given format in a goroutine,
+// which stops at the end of the data or when the context is done.
+func newStreamDecompressor(ctx context.Context, format string) *streamDecompressor {
+	d := &streamDecompressor{
+		chunks:  make(chan []byte),
+		drained: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	d.reader = &chunksReader{ctx: ctx, chunks: d.chunks, drained: d.drained}
+
+	go func() {
+		defer close(d.done)
+
+		decoder, err := newDecompressionReader(format, d.reader)
+		if err != nil {
+			d.err = err
+			return
+		}
+
+		_, err = io.Copy(decompressorOutput{d}, decoder)
+		switch closer := decoder.(type) {
+		case io.Closer:
+			_ = closer.Close()
+		case interface{ Close() }:
+			closer.Close()
+		}
+		d.err = err
+	}()
+
+	return d
+}
+
+// write hands over the given chunk to the decoder, and waits for it to be consumed.
+func (d *streamDecompressor) write(chunk []byte) error {
+	if len(chunk) == 0 {
+		return nil
+	}
+
+	select {
+	case d.chunks <- chunk:
+	case <-d.done:
+		return d.stoppedError()
+	}
+
+	select {
+	case <-d.drained:
+		return nil
+	case <-d.done:
+		if d.err == nil && len(d.reader.current) == 0 {
+			return nil
+		}
+		return d.stoppedError()
+	}
+}
+
+// stoppedError returns the error of writing to the decoder once it has stopped.
+func (d *streamDecompressor) stoppedError() error {
+	if d.err != nil {
+		return d.err
+	}
+
+	return errTrailingData
+}
+
+// closeInput signals the end of the compressed data to the decoder.
+func (d *streamDecompressor) closeInput() {
+	if d.closed {
+		return
+	}
+
+	d.closed = true
+	close(d.chunks)
+}
+
+// wait waits for the decoder to stop, and returns its error, if any.
+func (d *streamDecompressor) wait() error {
+	<-d.done
+	return d.err
+}
+
+// takeOutput returns the data decompressed since the last call.
+func (d *streamDecompressor) takeOutput() []byte {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	output := bytes.Clone(d.output.Bytes())
+	d.output.Reset()
+
+	return output
+}
+
+// decompressorOutput is the writer of the decompressed data of a [streamDecompressor].
+type decompressorOutput struct {
+	d *streamDecompressor
+}
+
+func (o decompressorOutput) Write(p []byte) (int, error) {
+	o.d.mu.Lock()
+	defer o.d.mu.Unlock()
+
+	return o.d.output.Write(p)
+}
+
+// chunksReader is the reader of the chunks handed over to a [streamDecompressor].
+type chunksReader struct {
+	ctx     context.Context //nolint:containedctx
+	chunks  <-chan []byte
+	drained chan<- struct{}
+
+	// current is the rest of the chunk being read
+	current []byte
+
+	// pending is whether a chunk was received and its consumption not signaled yet
+	pending bool
+}
+
+func (r *chunksReader) Read(p []byte) (int, error) {
+	for len(r.current) == 0 {
+		if r.pending {
+			select {
+			case r.drained <- struct{}{}:
+			case <-r.ctx.Done():
+				return 0, r.ctx.Err()
+			}
+			r.pending = false
+		}
+
+		select {
+		case chunk, ok := <-r.chunks:
+			if !ok {
+				return 0, io.EOF
+			}
+			r.current, r.pending = chunk, true
+		case <-r.ctx.Done():
+			return 0, r.ctx.Err()
+		}
+	}
+
+	n := copy(p, r.current)
+	r.current = r.current[n:]
+
+	return n, nil
+}
+
+// Ensure the decompressor's reader is an [io.Reader].
+var _ io.Reader = &chunksReader{}
@@ -0,0 +1,216 @@
+package streams
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+
+	"github.com/grafana/sobek"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/js/modulestest"
+)
+
+func TestCompressionStreamRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range []string{"gzip", "deflate", "deflate-raw", "br", "zstd"} {
+		t.Run(format, func(t *testing.T) {
+			t.Parallel()
+
+			got := runAsyncScript(t, `async () => {
+  const format = "`+format+`";
+  const text = "k6 streams ".repeat(10000);
+  const rs = new ReadableStream({
+    start(controller) {
+      // The text is split in several chunks, to check they're compressed as a whole.
+      for (let i = 0; i < text.length; i += 4096) {
+        const part = text.slice(i, i + 4096);
+        const chunk = new Uint8Array(part.length);
+        for (let j = 0; j < part.length; j++) {
+          chunk[j] = part.charCodeAt(j);
+        }
+        controller.enqueue(chunk);
+      }
+      controller.close();
+    },
+  });
+
+  let compressed = 0;
+  const reader = rs
+    .pipeThrough(new CompressionStream(format))
+    .pipeThrough(new TransformStream({
+      transform(chunk, controller) {
+        compressed += chunk.byteLength;
+        controller.enqueue(chunk);
+      },
+    }))
+    .pipeThrough(new DecompressionStream(format))
+    .getReader();
+
+  let result = "";
+  while (true) {
+    const { value, done } = await reader.read();
+    if (done) {
+      break;
+    }
+    result += String.fromCharCode(...value);
+  }
+
+  return [result === text, compressed < text.length].join(",");
+}`)
+			assert.Equal(t, "true,true", got.String())
+		})
+	}
+}
+
+func TestDecompressionStream(t *testing.T) {
+	t.Parallel()
+
+	t.Run("compressed by Go", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		_, err := io.WriteString(w, "Hello, World!")
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		r := modulestest.NewRuntime(t)
+		m := new(RootModule).NewModuleInstance(r.VU)
+		for k, v := range m.Exports().Named {
+			require.NoError(t, r.VU.Runtime().Set(k, v))
+		}
+		require.NoError(t, r.VU.Runtime().Set("data", r.VU.Runtime().NewArrayBuffer(buf.Bytes())))
+
+		var ret sobek.Value
+		err = r.EventLoop.Start(func() (err error) {
+			ret, err = r.VU.Runtime().RunString(`(async () => {
+  const ds = new DecompressionStream("gzip");
+  const writer = ds.writable.getWriter();
+  writer.write(data);
+  writer.close();
+  const { value } = await ds.readable.getReader().read();
+  return String.fromCharCode(...value);
+})()`)
+			return err
+		})
+		require.NoError(t, err)
+
+		p, ok := ret.Export().(*sobek.Promise)
+		require.True(t, ok)
+		require.Equal(t, sobek.PromiseStateFulfilled, p.State(), p.Result())
+		assert.Equal(t, "Hello, World!", p.Result().String())
+	})
+
+	t.Run("invalid data", func(t *testing.T) {
+		t.Parallel()
+
+		got := runAsyncScript(t, `async () => {
+  const ds = new DecompressionStream("deflate");
+  const writer = ds.writable.getWriter();
+  writer.write(new Uint8Array([1, 2, 3, 4, 5, 6, 7, 8])).catch(() => {});
+  writer.close().catch(() => {});
+  try {
+    await ds.readable.getReader().read();
+  } catch (e) {
+    return e.name;
+  }
+}`)
+		assert.Equal(t, "TypeError", got.String())
+	})
+
+	t.Run("trailing data", func(t *testing.T) {
+		t.Parallel()
+
+		got := runAsyncScript(t, `async () => {
+  const reader = new ReadableStream({
+    start(controller) {
+      controller.enqueue(new Uint8Array([1, 2, 3]));
+      controller.close();
+    },
+  }).pipeThrough(new CompressionStream("gzip")).getReader();
+  const chunks = [];
+  while (true) {
+    const { value, done } = await reader.read();
+    if (done) {
+      break;
+    }
+    chunks.push(...value);
+  }
+  chunks.push(42);
+
+  const ds = new DecompressionStream("gzip");
+  const writer = ds.writable.getWriter();
+  writer.write(new Uint8Array(chunks)).catch(() => {});
+  writer.close().catch(() => {});
+  const dsReader = ds.readable.getReader();
+  try {
+    while (!(await dsReader.read()).done) {}
+  } catch (e) {
+    return e.name;
+  }
+}`)
+		assert.Equal(t, "TypeError", got.String())
+	})
+
+	t.Run("non BufferSource chunk", func(t *testing.T) {
+		t.Parallel()
+
+		got := runAsyncScript(t, `async () => {
+  const ds = new DecompressionStream("gzip");
+  // The writes are only processed once the readable side is read from.
+  ds.readable.getReader().read().catch(() => {});
+  try {
+    await ds.writable.getWriter().write("not bytes");
+  } catch (e) {
+    return e.name;
+  }
+}`)
+		assert.Equal(t, "TypeError", got.String())
+	})
+}
+
+func TestCompressionStreamUnsupportedFormat(t *testing.T) {
+	t.Parallel()
+
+	got := runAsyncScript(t, `async () => {
+  const errors = [];
+  for (const constructor of [CompressionStream, DecompressionStream]) {
+    try {
+      new constructor("lzma");
+    } catch (e) {
+      errors.push(e.name);
+    }
+  }
+  return errors.join(",");
+}`)
+	assert.Equal(t, "TypeError,TypeError", got.String())
+}
+
+func TestCompressionStreamWebPlatformTests(t *testing.T) {
+	t.Parallel()
+
+	// The tests detaching the buffers through a window, and the IDL ones, aren"t included.
+	suites := []string{
+		"compression-bad-chunks.tentative.any.js",
+		"compression-constructor-error.tentative.any.js",
+		"compression-including-empty-chunk.tentative.any.js",
+		"compression-large-flush-output.any.js",
+		"compression-multiple-chunks.tentative.any.js",
+		"compression-output-length.tentative.any.js",
+		"compression-stream.tentative.any.js",
+		"decompression-bad-chunks.tentative.any.js",
+		"decompression-buffersource.tentative.any.js",
+		"decompression-constructor-error.tentative.any.js",
+		"decompression-correct-input.tentative.any.js",
+		"decompression-corrupt-input.tentative.any.js",
+		"decompression-empty-input.tentative.any.js",
+		"decompression-split-chunk.tentative.any.js",
+		"decompression-uint8array-output.tentative.any.js",
+	}
+
+	runWebPlatformTests(t, "compression", suites)
+}
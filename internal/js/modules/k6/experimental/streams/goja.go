@@ -31,6 +31,21 @@ func newRejectedPromise(vu modules.VU, with any) *sobek.Promise {
 	return promise
 }
 
+// promiseResolve returns the given value if it is a promise, or a new promise resolved with it otherwise.
+func promiseResolve(vu modules.VU, with sobek.Value) *sobek.Promise {
+	if !common.IsNullish(with) {
+		if p, ok := with.Export().(*sobek.Promise); ok {
+			return p
+		}
+	}
+
+	if with == nil {
+		with = sobek.Undefined()
+	}
+
+	return newResolvedPromise(vu, with)
+}
+
 // promiseThen facilitates instantiating a new promise and defining callbacks for to be executed
 // on fulfillment as well as rejection, directly from Go.
 func promiseThen(
@@ -39,7 +54,7 @@ func promiseThen(
 	onFulfilled, onRejected func(sobek.Value),
 ) (*sobek.Promise, error) {
 	val, err := rt.RunString(
-		`(function(promise, onFulfilled, onRejected) { return promise.then(onFulfilled, onRejected) })`)
+		`(function(promise, onFulfilled, onRejected) { return promise.then((v) => onFulfilled(v), onRejected && ((r) => onRejected(r))) })`)
 	if err != nil {
 		return nil, newError(RuntimeError, "unable to initialize promiseThen internal helper function")
 	}
@@ -113,3 +128,209 @@ func setReadOnlyPropertyOf(obj *sobek.Object, objName, propName string, propValu
 func isObject(val sobek.Value) bool {
 	return val != nil && val.ExportType() != nil && val.ExportType().Kind() == reflect.Map
 }
+
+// arrayBufferView holds the internal slots of an [ArrayBufferView], which is either
+// a typed array or a DataView.
+//
+// [ArrayBufferView]: https://webidl.spec.whatwg.org/#ArrayBufferView
+type arrayBufferView struct {
+	// obj is the view itself.
+	obj *sobek.Object
+
+	// buffer is the view's [[ViewedArrayBuffer]].
+	buffer sobek.ArrayBuffer
+
+	byteOffset int
+	byteLength int
+
+	// elementSize is the size in bytes of the elements of a typed array, or 1 for a DataView.
+	elementSize int
+
+	// length is the number of elements of a typed array, or the byte length of a DataView.
+	length int
+
+	// constructor is the constructor of the view, used to create views of the same type.
+	constructor *sobek.Object
+}
+
+// isArrayBufferView determines whether the given [sobek.Value] is a typed array or a DataView.
+func isArrayBufferView(rt *sobek.Runtime, val sobek.Value) bool {
+	obj, ok := val.(*sobek.Object)
+	if !ok {
+		return false
+	}
+
+	isView, ok := sobek.AssertFunction(rt.Get("ArrayBuffer").ToObject(rt).Get("isView"))
+	if !ok {
+		return false
+	}
+
+	result, err := isView(sobek.Undefined(), obj)
+	return err == nil && result.ToBoolean()
+}
+
+// toArrayBufferView returns the internal slots of the given typed array or DataView,
+// or false if the value is neither of them.
+func toArrayBufferView(rt *sobek.Runtime, val sobek.Value) (arrayBufferView, bool) {
+	if !isArrayBufferView(rt, val) {
+		return arrayBufferView{}, false
+	}
+
+	obj := val.ToObject(rt)
+	buffer, ok := obj.Get("buffer").Export().(sobek.ArrayBuffer)
+	if !ok {
+		return arrayBufferView{}, false
+	}
+
+	view := arrayBufferView{
+		obj:         obj,
+		buffer:      buffer,
+		byteOffset:  int(obj.Get("byteOffset").ToInteger()),
+		byteLength:  int(obj.Get("byteLength").ToInteger()),
+		elementSize: 1,
+		constructor: rt.Get("DataView").ToObject(rt),
+	}
+	view.length = view.byteLength
+
+	// Only typed arrays have the [[TypedArrayName]] internal slot, which
+	// is surfaced through the BYTES_PER_ELEMENT property.
+	if bytesPerElement := obj.Get("BYTES_PER_ELEMENT"); !common.IsNullish(bytesPerElement) {
+		view.elementSize = int(bytesPerElement.ToInteger())
+		view.length = int(obj.Get("length").ToInteger())
+		view.constructor = obj.Get("constructor").ToObject(rt)
+	}
+
+	return view, true
+}
+
+// isTypedArray returns true if the view is a typed array, and false if it is a DataView.
+func (view arrayBufferView) isTypedArray() bool {
+	return !common.IsNullish(view.obj.Get("BYTES_PER_ELEMENT"))
+}
+
+// newArrayBufferView constructs a view of the given type over the bytes of the buffer
+// starting at byteOffset, with the given number of elements.
+func newArrayBufferView(
+	rt *sobek.Runtime,
+	constructor *sobek.Object,
+	buffer sobek.ArrayBuffer,
+	byteOffset, length int,
+) (*sobek.Object, error) {
+	return rt.New(constructor, rt.ToValue(buffer), rt.ToValue(byteOffset), rt.ToValue(length))
+}
+
+// newUint8Array constructs a Uint8Array over the bytes of the buffer starting at byteOffset.
+func newUint8Array(rt *sobek.Runtime, buffer sobek.ArrayBuffer, byteOffset, byteLength int) (*sobek.Object, error) {
+	return newArrayBufferView(rt, rt.Get("Uint8Array").ToObject(rt), buffer, byteOffset, byteLength)
+}
+
+// transferArrayBuffer implements the [TransferArrayBuffer] abstract operation.
+//
+// The returned buffer takes over the bytes of the given one, which is detached.
+//
+// [TransferArrayBuffer]: https://tc39.es/proposal-arraybuffer-transfer/#sec-transferarraybuffer
+func transferArrayBuffer(rt *sobek.Runtime, buffer sobek.ArrayBuffer) (sobek.ArrayBuffer, error) {
+	// 1. Assert: ! IsDetachedBuffer(O) is false.
+	if buffer.Detached() {
+		return sobek.ArrayBuffer{}, newTypeError(rt, "cannot transfer a detached ArrayBuffer")
+	}
+
+	// 2. Let arrayBufferData be O.[[ArrayBufferData]].
+	data := buffer.Bytes()
+
+	// 3. Perform ? DetachArrayBuffer(O).
+	buffer.Detach()
+
+	// 4. Return a new ArrayBuffer object whose [[ArrayBufferData]] is arrayBufferData.
+	return rt.NewArrayBuffer(data), nil
+}
+
+// cloneArrayBuffer returns a new ArrayBuffer holding a copy of byteLength bytes
+// of the given buffer, starting at byteOffset.
+func cloneArrayBuffer(rt *sobek.Runtime, buffer sobek.ArrayBuffer, byteOffset, byteLength int) (sobek.ArrayBuffer, error) {
+	if buffer.Detached() {
+		return sobek.ArrayBuffer{}, newTypeError(rt, "cannot clone a detached ArrayBuffer")
+	}
+
+	data := make([]byte, byteLength)
+	copy(data, buffer.Bytes()[byteOffset:byteOffset+byteLength])
+
+	return rt.NewArrayBuffer(data), nil
+}
+
+// promiseCapability holds a promise along with the functions to resolve and reject it.
+type promiseCapability struct {
+	promise     *sobek.Promise
+	resolveFunc func(any) error
+	rejectFunc  func(any) error
+}
+
+// newPromiseCapability instantiates a new pending promise.
+func newPromiseCapability(rt *sobek.Runtime) *promiseCapability {
+	promise, resolve, reject := rt.NewPromise()
+	return &promiseCapability{promise: promise, resolveFunc: resolve, rejectFunc: reject}
+}
+
+// resolve resolves the promise with the given value, unless it is already settled.
+func (p *promiseCapability) resolve(value any) {
+	if err := p.resolveFunc(value); err != nil {
+		panic(err)
+	}
+}
+
+// reject rejects the promise with the given reason, unless it is already settled.
+func (p *promiseCapability) reject(reason any) {
+	if err := p.rejectFunc(rejectionReason(reason)); err != nil {
+		panic(err)
+	}
+}
+
+// rejectionReason returns the value a promise must be rejected with for the given error,
+// which unwraps the original JS errors wrapped in a [jsError].
+func rejectionReason(e any) any {
+	if jsErr, ok := e.(*jsError); ok {
+		return jsErr.Err()
+	}
+
+	if ex, ok := e.(*sobek.Exception); ok {
+		return ex.Value()
+	}
+
+	return e
+}
+
+// setPromiseIsHandled marks the given promise as handled, so its rejection isn't reported.
+//
+// See https://github.com/dop251/goja/issues/565
+func setPromiseIsHandled(rt *sobek.Runtime, promise *sobek.Promise) {
+	doNothing := func(sobek.Value) {}
+	if _, err := promiseThen(rt, promise, doNothing, doNothing); err != nil {
+		common.Throw(rt, newError(RuntimeError, err.Error()))
+	}
+}
+
+// uponPromise reacts to the settlement of the given promise with the given steps, any of which can be nil,
+// and marks it as handled.
+func uponPromise(rt *sobek.Runtime, promise *sobek.Promise, onFulfilled, onRejected func(sobek.Value)) {
+	if onFulfilled == nil {
+		onFulfilled = func(sobek.Value) {}
+	}
+	if onRejected == nil {
+		onRejected = func(sobek.Value) {}
+	}
+
+	if _, err := promiseThen(rt, promise, onFulfilled, onRejected); err != nil {
+		common.Throw(rt, newError(RuntimeError, err.Error()))
+	}
+}
+
+// promiseFromCall returns the result of calling the given function, converted to a promise,
+// or a promise rejected with the thrown exception if the function throws.
+func promiseFromCall(vu modules.VU, call func() (sobek.Value, error)) *sobek.Promise {
+	v, err := call()
+	if err != nil {
+		return newRejectedPromise(vu, rejectionReason(err))
+	}
+
+	return promiseResolve(vu, v)
+}
@@ -2,6 +2,7 @@
 package streams
 
 import (
+	"bytes"
 	"errors"
 	"io"
 
@@ -17,6 +18,10 @@ type (
 	// ModuleInstance is the module instance that will be created for each VU.
 	ModuleInstance struct {
 		vu modules.VU
+
+		// constructors holds the classes exported by the module. They are created once per VU,
+		// so the streams the module creates internally share the prototypes of the exported ones.
+		constructors map[string]*sobek.Object
 	}
 )
 
@@ -33,18 +38,41 @@ func New() *RootModule {
 
 // NewModuleInstance creates a new instance of the module for a specific VU.
 func (rm *RootModule) NewModuleInstance(vu modules.VU) modules.Instance {
-	return &ModuleInstance{
+	mi := &ModuleInstance{
 		vu: vu,
 	}
+
+	rt := vu.Runtime()
+	mi.constructors = map[string]*sobek.Object{
+		"ReadableStream":              rt.ToValue(mi.NewReadableStream).ToObject(rt),
+		"ReadableStreamDefaultReader": rt.ToValue(mi.NewReadableStreamDefaultReader).ToObject(rt),
+		"ReadableStreamBYOBReader":    rt.ToValue(mi.NewReadableStreamBYOBReader).ToObject(rt),
+		"WritableStream":              rt.ToValue(mi.NewWritableStream).ToObject(rt),
+		"WritableStreamDefaultWriter": rt.ToValue(mi.NewWritableStreamDefaultWriter).ToObject(rt),
+		"TransformStream":             rt.ToValue(mi.NewTransformStream).ToObject(rt),
+		"CountQueuingStrategy":        rt.ToValue(mi.NewCountQueuingStrategy).ToObject(rt),
+		"ByteLengthQueuingStrategy":   rt.ToValue(mi.NewByteLengthQueuingStrategy).ToObject(rt),
+		"CompressionStream":           rt.ToValue(mi.NewCompressionStream).ToObject(rt),
+		"DecompressionStream":         rt.ToValue(mi.NewDecompressionStream).ToObject(rt),
+	}
+
+	return mi
 }
 
 // Exports returns the module exports, that will be available in the runtime.
 func (mi *ModuleInstance) Exports() modules.Exports {
-	return modules.Exports{Named: map[string]interface{}{
-		"ReadableStream":              mi.NewReadableStream,
-		"CountQueuingStrategy":        mi.NewCountQueuingStrategy,
-		"ReadableStreamDefaultReader": mi.NewReadableStreamDefaultReader,
-	}}
+	named := make(map[string]interface{}, len(mi.constructors))
+	for name, constructor := range mi.constructors {
+		named[name] = constructor
+	}
+
+	return modules.Exports{Named: named}
+}
+
+// prototypeOf returns the prototype of the instances of the given exported class.
+func (mi *ModuleInstance) prototypeOf(name string) *sobek.Object {
+	rt := mi.vu.Runtime()
+	return mi.constructors[name].Get("prototype").ToObject(rt)
 }
 
 // NewReadableStream is the constructor for the ReadableStream object.
@@ -92,8 +120,21 @@ func newReadableStream(vu modules.VU, call sobek.ConstructorCall) *sobek.Object
 	stream.initialize()
 
 	// 4. If underlyingSourceDict["type"] is "bytes":
-	if underlyingSourceDict.Type == "bytes" {
-		common.Throw(stream.runtime, newError(NotSupportedError, "'bytes' stream is not supported yet"))
+	if underlyingSourceDict.Type == ReadableStreamTypeBytes {
+		// 4.1. If strategy["size"] exists, throw a RangeError exception.
+		if !common.IsNullish(strategy.Get("size")) {
+			throw(rt, newRangeError(rt, "the size function of the strategy of a byte stream must be undefined"))
+		}
+
+		// 4.2. Let highWaterMark be ? ExtractHighWaterMark(strategy, 0).
+		highWaterMark := extractHighWaterMark(rt, strategy, 0)
+
+		// 4.3. Perform ? SetUpReadableByteStreamControllerFromUnderlyingSource(...).
+		stream.setupReadableByteStreamControllerFromUnderlyingSource(
+			underlyingSource,
+			underlyingSourceDict,
+			highWaterMark,
+		)
 	} else { // 5. Otherwise,
 		// 5.1. Assert: underlyingSourceDict["type"] does not exist.
 		if underlyingSourceDict.Type != "" {
@@ -115,11 +156,17 @@ func newReadableStream(vu modules.VU, call sobek.ConstructorCall) *sobek.Object
 		)
 	}
 
+	return newReadableStreamObject(stream, call.This.Prototype())
+}
+
+// newReadableStreamObject creates the [sobek.Object] representing the stream,
+// with the given prototype.
+func newReadableStreamObject(stream *ReadableStream, proto *sobek.Object) *sobek.Object {
+	rt := stream.runtime
 	streamObj := rt.ToValue(stream).ToObject(rt)
 
-	proto := call.This.Prototype()
 	if proto.Get("locked") == nil {
-		err = proto.DefineAccessorProperty("locked", rt.ToValue(func() sobek.Value {
+		err := proto.DefineAccessorProperty("locked", rt.ToValue(func() sobek.Value {
 			return rt.ToValue(stream.Locked)
 		}), nil, sobek.FLAG_FALSE, sobek.FLAG_TRUE)
 		if err != nil {
@@ -127,10 +174,11 @@ func newReadableStream(vu modules.VU, call sobek.ConstructorCall) *sobek.Object
 		}
 	}
 
-	err = streamObj.SetPrototype(proto)
+	err := streamObj.SetPrototype(proto)
 	if err != nil {
 		common.Throw(rt, newError(RuntimeError, err.Error()))
 	}
+	stream.obj = streamObj
 
 	return streamObj
 }
@@ -140,25 +188,33 @@ func initializeStrategy(rt *sobek.Runtime, call sobek.ConstructorCall) *sobek.Ob
 	// Either if the strategy is not provided or if it doesn't have a 'highWaterMark',
 	// we need to set its default value (highWaterMark=1).
 	// https://streams.spec.whatwg.org/#rs-prototype
+	//
+	// Except for byte streams, whose default highWaterMark is 0, and which
+	// don't have a size function, unless it is manually specified.
+	isBytes := false
+	if len(call.Arguments) > 0 && !common.IsNullish(call.Arguments[0]) {
+		srcArg := call.Arguments[0].ToObject(rt)
+		srcTypeArg := srcArg.Get("type")
+		isBytes = !common.IsNullish(srcTypeArg) && srcTypeArg.String() == ReadableStreamTypeBytes
+	}
+
 	strArg := rt.NewObject()
 	if len(call.Arguments) > 1 && !common.IsNullish(call.Arguments[1]) {
 		strArg = call.Arguments[1].ToObject(rt)
 	}
 	if common.IsNullish(strArg.Get("highWaterMark")) {
-		if err := strArg.Set("highWaterMark", rt.ToValue(1)); err != nil {
+		defaultHWM := 1
+		if isBytes {
+			defaultHWM = 0
+		}
+		if err := strArg.Set("highWaterMark", rt.ToValue(defaultHWM)); err != nil {
 			common.Throw(rt, newError(RuntimeError, err.Error()))
 		}
 	}
 
-	// If the stream type is 'bytes', we don't want the size function.
-	// Except, when it is manually specified.
 	size := rt.ToValue(defaultSizeFunc)
-	if len(call.Arguments) > 0 && !common.IsNullish(call.Arguments[0]) {
-		srcArg := call.Arguments[0].ToObject(rt)
-		srcTypeArg := srcArg.Get("type")
-		if !common.IsNullish(srcTypeArg) && srcTypeArg.String() == ReadableStreamTypeBytes {
-			size = nil
-		}
+	if isBytes {
+		size = nil
 	}
 	if strArg.Get("size") != nil {
 		size = strArg.Get("size")
@@ -186,9 +242,36 @@ func newCountQueuingStrategy(
 	rt *sobek.Runtime,
 	call sobek.ConstructorCall,
 	size sobek.Value,
+) *sobek.Object {
+	return newQueuingStrategy(rt, "CountQueuingStrategy", call, size)
+}
+
+// NewByteLengthQueuingStrategy is the constructor for the [ByteLengthQueuingStrategy] object.
+//
+// [ByteLengthQueuingStrategy]: https://streams.spec.whatwg.org/#blqs-class
+func (mi *ModuleInstance) NewByteLengthQueuingStrategy(call sobek.ConstructorCall) *sobek.Object {
+	rt := mi.vu.Runtime()
+
+	// The size of a chunk is its byteLength property, whatever its type is.
+	size := rt.ToValue(func(chunk sobek.Value) sobek.Value {
+		if common.IsNullish(chunk) {
+			throw(rt, newTypeError(rt, "cannot read the byteLength of "+chunk.String()))
+		}
+		return chunk.ToObject(rt).Get("byteLength")
+	})
+
+	return newQueuingStrategy(rt, "ByteLengthQueuingStrategy", call, size)
+}
+
+// newQueuingStrategy creates a queuing strategy object named objName, with the highWaterMark
+// of the given constructor call's argument, and the given 'size' property if it isn't nullish.
+func newQueuingStrategy(
+	rt *sobek.Runtime,
+	objName string,
+	call sobek.ConstructorCall,
+	size sobek.Value,
 ) *sobek.Object {
 	obj := rt.NewObject()
-	objName := "CountQueuingStrategy"
 
 	if len(call.Arguments) != 1 {
 		throw(rt, newTypeError(rt, objName+" takes a single argument"))
@@ -291,18 +374,240 @@ func (mi *ModuleInstance) NewReadableStreamDefaultReader(call sobek.ConstructorC
 	return object
 }
 
+// NewReadableStreamBYOBReader is the constructor for the [ReadableStreamBYOBReader] object.
+//
+// [ReadableStreamBYOBReader]: https://streams.spec.whatwg.org/#byob-reader-class
+func (mi *ModuleInstance) NewReadableStreamBYOBReader(call sobek.ConstructorCall) *sobek.Object {
+	rt := mi.vu.Runtime()
+
+	if len(call.Arguments) != 1 {
+		throw(rt, newTypeError(rt, "ReadableStreamBYOBReader takes a single argument"))
+	}
+
+	stream, ok := call.Argument(0).Export().(*ReadableStream)
+	if !ok {
+		throw(rt, newTypeError(rt, "ReadableStreamBYOBReader argument must be a ReadableStream"))
+	}
+
+	// 1. Perform ? SetUpReadableStreamBYOBReader(this, stream).
+	reader := &ReadableStreamBYOBReader{}
+	reader.setup(stream)
+
+	object, err := NewReadableStreamBYOBReaderObject(reader)
+	if err != nil {
+		throw(rt, err)
+	}
+
+	return object
+}
+
+// NewWritableStream is the constructor for the [WritableStream] object.
+//
+// [WritableStream]: https://streams.spec.whatwg.org/#ws-class
+func (mi *ModuleInstance) NewWritableStream(call sobek.ConstructorCall) *sobek.Object {
+	var (
+		// 1. If underlyingSink is missing, set it to null.
+		underlyingSink *sobek.Object
+
+		rt = mi.vu.Runtime()
+
+		err                error
+		underlyingSinkDict UnderlyingSink
+	)
+
+	// As for the readable streams, the queuing strategy is validated first.
+	strategy := initializeStrategy(rt, call)
+
+	// 2. Let underlyingSinkDict be underlyingSink, converted to an IDL value of type UnderlyingSink.
+	if len(call.Arguments) > 0 && !sobek.IsUndefined(call.Arguments[0]) {
+		if !isObject(call.Arguments[0]) {
+			throw(rt, newTypeError(rt, "underlyingSink must be an object"))
+		}
+
+		underlyingSink = call.Arguments[0].ToObject(rt)
+
+		// 3. If underlyingSinkDict["type"] exists, throw a RangeError exception.
+		underlyingSinkDict, err = NewUnderlyingSinkFromObject(rt, underlyingSink)
+		if err != nil {
+			throw(rt, err)
+		}
+	}
+
+	// 4. Perform ! InitializeWritableStream(this).
+	stream := &WritableStream{
+		runtime: rt,
+		vu:      mi.vu,
+	}
+	stream.initialize()
+
+	// 5. Let sizeAlgorithm be ! ExtractSizeAlgorithm(strategy).
+	sizeAlgorithm := extractSizeAlgorithm(rt, strategy)
+
+	// 6. Let highWaterMark be ? ExtractHighWaterMark(strategy, 1).
+	highWaterMark := extractHighWaterMark(rt, strategy, 1)
+
+	// 7. Perform ? SetUpWritableStreamDefaultControllerFromUnderlyingSink(...).
+	stream.setupDefaultControllerFromUnderlyingSink(underlyingSink, underlyingSinkDict, highWaterMark, sizeAlgorithm)
+
+	return newWritableStreamObject(stream, call.This.Prototype())
+}
+
+// newWritableStreamObject creates the [sobek.Object] representing the stream,
+// with the given prototype.
+func newWritableStreamObject(stream *WritableStream, proto *sobek.Object) *sobek.Object {
+	rt := stream.runtime
+	streamObj := rt.ToValue(stream).ToObject(rt)
+
+	if err := streamObj.SetPrototype(proto); err != nil {
+		common.Throw(rt, newError(RuntimeError, err.Error()))
+	}
+	stream.obj = streamObj
+
+	return streamObj
+}
+
+// NewWritableStreamDefaultWriter is the constructor for the [WritableStreamDefaultWriter] object.
+//
+// [WritableStreamDefaultWriter]: https://streams.spec.whatwg.org/#default-writer-class
+func (mi *ModuleInstance) NewWritableStreamDefaultWriter(call sobek.ConstructorCall) *sobek.Object {
+	rt := mi.vu.Runtime()
+
+	if len(call.Arguments) != 1 {
+		throw(rt, newTypeError(rt, "WritableStreamDefaultWriter takes a single argument"))
+	}
+
+	stream, ok := call.Argument(0).Export().(*WritableStream)
+	if !ok {
+		throw(rt, newTypeError(rt, "WritableStreamDefaultWriter argument must be a WritableStream"))
+	}
+
+	// 1. Perform ? SetUpWritableStreamDefaultWriter(this, stream).
+	writer := &WritableStreamDefaultWriter{}
+	writer.setup(stream)
+
+	object, err := NewWritableStreamDefaultWriterObject(writer)
+	if err != nil {
+		throw(rt, err)
+	}
+
+	return object
+}
+
 // NewReadableStreamFromReader is the equivalent of [NewReadableStreamDefaultReader] but to initialize
 // a new [ReadableStream] from a given [io.Reader] in Go code.
 // It is useful for those situations when a [io.Reader] needs to be surfaced up to the JS runtime.
 func NewReadableStreamFromReader(vu modules.VU, reader io.Reader) *sobek.Object {
 	rt := vu.Runtime()
 	return newReadableStream(vu, sobek.ConstructorCall{
-		Arguments: []sobek.Value{rt.ToValue(underlyingSourceFromReader(vu, reader))},
+		Arguments: []sobek.Value{rt.ToValue(underlyingSourceFromReader(vu, reader, func(chunk []byte) sobek.Value {
+			return rt.ToValue(string(chunk))
+		}))},
+		This: rt.NewObject(),
+	})
+}
+
+// NewUint8ArrayReadableStreamFromReader is the equivalent of [NewReadableStreamFromReader], but
+// the chunks of the stream are Uint8Array objects instead of strings, as the Fetch API and the
+// other Web APIs working with bytes expect.
+func NewUint8ArrayReadableStreamFromReader(vu modules.VU, reader io.Reader) *sobek.Object {
+	rt := vu.Runtime()
+	return newReadableStream(vu, sobek.ConstructorCall{
+		Arguments: []sobek.Value{rt.ToValue(underlyingSourceFromReader(vu, reader, func(chunk []byte) sobek.Value {
+			return uint8ArrayChunk(rt, chunk)
+		}))},
+		This: rt.NewObject(),
+	})
+}
+
+// NewUint8ArrayReadableStreamFromReadCloser is the equivalent of [NewUint8ArrayReadableStreamFromReader]
+// for the readers that block on I/O, like the bodies of HTTP responses: they are read off the event
+// loop, only when the stream pulls a chunk, and closed when the stream is canceled.
+//
+// The onEnd function is called on the event loop once the reader is exhausted, reading it fails or
+// the stream is canceled, with the error of the reader in the second case, and nil otherwise. For
+// an error, it returns the reason the stream is errored with.
+func NewUint8ArrayReadableStreamFromReadCloser(
+	vu modules.VU, reader io.ReadCloser, onEnd func(error) sobek.Value,
+) *sobek.Object {
+	rt := vu.Runtime()
+
+	var ended bool
+	buf := make([]byte, 16*1024) // the pulls don't overlap
+	end := func(err error) sobek.Value {
+		ended = true
+		return onEnd(err)
+	}
+
+	underlyingSource := rt.NewObject()
+	if err := underlyingSource.Set("pull", rt.ToValue(func(controller *sobek.Object) *sobek.Promise {
+		promise, resolve, reject := rt.NewPromise()
+		callback := vu.RegisterCallback()
+		go func() {
+			n, err := reader.Read(buf)
+			data := bytes.Clone(buf[:n])
+			callback(func() error {
+				if ended { // the stream was canceled while reading
+					return resolve(sobek.Undefined())
+				}
+				if chunk := uint8ArrayChunk(rt, data); chunk != nil {
+					cEnqueue, _ := sobek.AssertFunction(controller.Get("enqueue"))
+					if _, enqueueErr := cEnqueue(controller, chunk); enqueueErr != nil {
+						return enqueueErr
+					}
+					// the reactions to the chunk, which run once it's enqueued, can cancel the stream
+					if ended {
+						return resolve(sobek.Undefined())
+					}
+				}
+				switch {
+				case errors.Is(err, io.EOF):
+					cClose, _ := sobek.AssertFunction(controller.Get("close"))
+					if _, closeErr := cClose(controller); closeErr != nil {
+						return closeErr
+					}
+					end(nil)
+				case err != nil:
+					// rejecting the promise of pull errors the stream
+					return reject(end(err))
+				}
+				return resolve(sobek.Undefined())
+			})
+		}()
+		return promise
+	})); err != nil {
+		throw(rt, err)
+	}
+	if err := underlyingSource.Set("cancel", rt.ToValue(func(sobek.Value) *sobek.Promise {
+		if !ended {
+			_ = reader.Close()
+			end(nil)
+		}
+		return newResolvedPromise(vu, sobek.Undefined())
+	})); err != nil {
+		throw(rt, err)
+	}
+
+	return newReadableStream(vu, sobek.ConstructorCall{
+		Arguments: []sobek.Value{underlyingSource},
 		This:      rt.NewObject(),
 	})
 }
 
-func underlyingSourceFromReader(vu modules.VU, reader io.Reader) *sobek.Object {
+// uint8ArrayChunk returns a Uint8Array with the bytes of the chunk, or nil if it's empty.
+func uint8ArrayChunk(rt *sobek.Runtime, chunk []byte) sobek.Value {
+	if len(chunk) == 0 {
+		return nil
+	}
+	array, err := rt.New(rt.Get("Uint8Array"), rt.ToValue(rt.NewArrayBuffer(chunk)))
+	if err != nil {
+		panic(err)
+	}
+	return array
+}
+
+// underlyingSourceFromReader returns an underlying source pulling the chunks from the reader,
+// which are converted to JS values with toChunk. The nil values aren't enqueued.
+func underlyingSourceFromReader(vu modules.VU, reader io.Reader, toChunk func([]byte) sobek.Value) *sobek.Object {
 	rt := vu.Runtime()
 
 	underlyingSource := vu.Runtime().NewObject()
@@ -317,9 +622,11 @@ func underlyingSourceFromReader(vu modules.VU, reader io.Reader) *sobek.Object {
 			panic(err)
 		}
 
-		_, enqueueErr := cEnqueue(nil, rt.ToValue(string(buf[:n])))
-		if enqueueErr != nil {
-			panic(enqueueErr)
+		if chunk := toChunk(buf[:n]); chunk != nil {
+			_, enqueueErr := cEnqueue(nil, chunk)
+			if enqueueErr != nil {
+				panic(enqueueErr)
+			}
 		}
 
 		if err == io.EOF {
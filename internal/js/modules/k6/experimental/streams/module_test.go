@@ -2,6 +2,8 @@ package streams
 
 import (
 	"bytes"
+	"errors"
+	"io"
 	"testing"
 
 	"github.com/grafana/sobek"
@@ -39,3 +41,149 @@ func TestNewReadableStreamFromReader(t *testing.T) {
 	require.True(t, ok)
 	assert.Equal(t, exp, p.Result().String())
 }
+
+func TestNewUint8ArrayReadableStreamFromReader(t *testing.T) {
+	t.Parallel()
+
+	exp := "Hello, World!"
+
+	r := modulestest.NewRuntime(t)
+	rs := NewUint8ArrayReadableStreamFromReader(r.VU, bytes.NewReader([]byte(exp)))
+	require.NoError(t, r.VU.Runtime().Set("rs", rs))
+
+	// The chunks are read until the end of the stream, so the empty
+	// chunk of the end of the reader must not be enqueued.
+	var ret sobek.Value
+	err := r.EventLoop.Start(func() (err error) {
+		ret, err = r.VU.Runtime().RunString(`(async () => {
+  const reader = rs.getReader();
+  const chunks = [];
+  while (true) {
+    const {value, done} = await reader.read();
+    if (done) {
+      return chunks.join("");
+    }
+    if (!(value instanceof Uint8Array) || value.length === 0) {
+      throw new Error("unexpected chunk: " + value);
+    }
+    chunks.push(String.fromCharCode(...value));
+  }
+})()`)
+		return err
+	})
+	assert.NoError(t, err)
+
+	p, ok := ret.Export().(*sobek.Promise)
+	require.True(t, ok)
+	require.Equal(t, sobek.PromiseStateFulfilled, p.State(), p.Result())
+	assert.Equal(t, exp, p.Result().String())
+}
+
+func TestNewUint8ArrayReadableStreamFromReadCloser(t *testing.T) {
+	t.Parallel()
+
+	run := func(t *testing.T, writes func(*io.PipeWriter), script string) (sobek.Value, []error) {
+		t.Helper()
+		r := modulestest.NewRuntime(t)
+		pr, pw := io.Pipe()
+		go writes(pw)
+
+		var ends []error
+		rs := NewUint8ArrayReadableStreamFromReadCloser(r.VU, pr, func(err error) sobek.Value {
+			ends = append(ends, err)
+			if err != nil {
+				return r.VU.Runtime().ToValue("failed: " + err.Error())
+			}
+			return nil
+		})
+		require.NoError(t, r.VU.Runtime().Set("rs", rs))
+
+		var ret sobek.Value
+		err := r.EventLoop.Start(func() (err error) {
+			ret, err = r.VU.Runtime().RunString("(async () => {" + script + "})()")
+			return err
+		})
+		require.NoError(t, err)
+		p, ok := ret.Export().(*sobek.Promise)
+		require.True(t, ok)
+		require.Equal(t, sobek.PromiseStateFulfilled, p.State(), p.Result())
+		return p.Result(), ends
+	}
+
+	t.Run("Read", func(t *testing.T) {
+		t.Parallel()
+		result, ends := run(t, func(pw *io.PipeWriter) {
+			_, _ = pw.Write([]byte("Hello, "))
+			_, _ = pw.Write([]byte("World!"))
+			_ = pw.Close()
+		}, `
+  const reader = rs.getReader();
+  const chunks = [];
+  while (true) {
+    const {value, done} = await reader.read();
+    if (done) {
+      return chunks.join("|");
+    }
+    chunks.push(String.fromCharCode(...value));
+  }`)
+		assert.Equal(t, "Hello, |World!", result.String())
+		assert.Equal(t, []error{nil}, ends)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+		result, ends := run(t, func(pw *io.PipeWriter) {
+			_, _ = pw.Write([]byte("Hello"))
+			_ = pw.CloseWithError(errors.New("connection reset"))
+		}, `
+  const reader = rs.getReader();
+  await reader.read();
+  return await reader.read().catch((e) => e);`)
+		assert.Equal(t, "failed: connection reset", result.String())
+		require.Len(t, ends, 1)
+		assert.ErrorContains(t, ends[0], "connection reset")
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		t.Parallel()
+		written := make(chan error, 1)
+		result, ends := run(t, func(pw *io.PipeWriter) {
+			_, _ = pw.Write([]byte("Hello"))
+			// blocks until the reader is closed by cancel
+			_, err := pw.Write([]byte("World"))
+			written <- err
+		}, `
+  const reader = rs.getReader();
+  const {value} = await reader.read();
+  await reader.cancel();
+  return String.fromCharCode(...value);`)
+		assert.Equal(t, "Hello", result.String())
+		assert.Equal(t, []error{nil}, ends)
+		assert.ErrorIs(t, <-written, io.ErrClosedPipe)
+	})
+}
+
+// runAsyncScript runs the given async function expression with the module's exports
+// available as globals, and returns the value its promise is fulfilled with.
+func runAsyncScript(t *testing.T, script string) sobek.Value {
+	t.Helper()
+
+	r := modulestest.NewRuntime(t)
+	m := new(RootModule).NewModuleInstance(r.VU)
+	for k, v := range m.Exports().Named {
+		require.NoError(t, r.VU.Runtime().Set(k, v))
+	}
+
+	var ret sobek.Value
+	err := r.EventLoop.Start(func() (err error) {
+		ret, err = r.VU.Runtime().RunString("(" + script + ")()")
+		return err
+	})
+	require.NoError(t, err)
+
+	p, ok := ret.Export().(*sobek.Promise)
+	require.True(t, ok)
+	require.Equal(t, sobek.PromiseStateFulfilled, p.State(), p.Result())
+
+	return p.Result()
+}
@@ -0,0 +1,1555 @@
+package streams
+
+import (
+	"github.com/grafana/sobek"
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/js/common"
+)
+
+// ReadableByteStreamController is the controller of a readable byte stream. It has methods
+// to control the stream's state and internal queue, and it allows the underlying source to
+// write directly into the buffers provided by the consumers of the stream (BYOB readers).
+//
+// For more details, see the [specification].
+//
+// [specification]: https://streams.spec.whatwg.org/#rbs-controller-class
+type ReadableByteStreamController struct {
+	// autoAllocateChunkSize is a positive integer, when the automatic buffer allocation
+	// feature is enabled, or zero otherwise.
+	autoAllocateChunkSize int64
+
+	// byobRequest is a [ReadableStreamBYOBRequest] instance representing the current
+	// BYOB pull request, or nil if there are no pending requests.
+	byobRequest *ReadableStreamBYOBRequest
+
+	// cancelAlgorithm is a promise-returning algorithm, taking one argument (the cancel
+	// reason), which communicates a requested cancellation to the underlying byte source.
+	cancelAlgorithm UnderlyingSourceCancelCallback
+
+	// closeRequested is a boolean flag indicating whether the stream has been closed by its
+	// underlying byte source, but still has chunks in its internal queue that have not yet
+	// been read.
+	closeRequested bool
+
+	// pullAgain is a boolean flag set to true if the stream's mechanisms requested a call
+	// to the underlying byte source's pull algorithm to pull more data, but the pull could
+	// not yet be done since a previous call is still executing.
+	pullAgain bool
+
+	// pullAlgorithm is a promise-returning algorithm that pulls data from the underlying byte source.
+	pullAlgorithm UnderlyingSourcePullCallback
+
+	// pulling is a boolean flag set to true while the underlying byte source's pull algorithm
+	// is executing and the returned promise has not yet fulfilled, used to prevent reentrant calls.
+	pulling bool
+
+	// pendingPullIntos is a list of pull-into descriptors.
+	pendingPullIntos []*pullIntoDescriptor
+
+	// queue is a list representing the stream's internal queue of chunks.
+	queue []readableByteStreamQueueEntry
+
+	// queueTotalSize is the total size, in bytes, of all the chunks stored in queue.
+	queueTotalSize float64
+
+	// started is a boolean flag indicating whether the underlying byte source has finished starting.
+	started bool
+
+	// strategyHWM is a number supplied to the constructor as part of the stream's queuing
+	// strategy, indicating the point at which the stream will apply backpressure to its
+	// underlying byte source.
+	strategyHWM float64
+
+	// stream is the readable stream that this controller controls.
+	stream *ReadableStream
+
+	// obj is the JS object representing the controller.
+	obj *sobek.Object
+}
+
+// Ensure that ReadableByteStreamController implements the ReadableStreamController interface.
+var _ ReadableStreamController = &ReadableByteStreamController{}
+
+// readableByteStreamQueueEntry encapsulates the important aspects of a chunk for
+// the specific case of readable byte streams.
+//
+// See the [specification] for more details.
+//
+// [specification]: https://streams.spec.whatwg.org/#readable-byte-stream-queue-entry
+type readableByteStreamQueueEntry struct {
+	buffer     sobek.ArrayBuffer
+	byteOffset int
+	byteLength int
+}
+
+// readerType is the type of the reader that initiated a pull-into request.
+type readerType string
+
+const (
+	readerTypeDefault readerType = "default"
+	readerTypeBYOB    readerType = "byob"
+	readerTypeNone    readerType = "none"
+)
+
+// pullIntoDescriptor represents pending BYOB pull requests.
+//
+// See the [specification] for more details.
+//
+// [specification]: https://streams.spec.whatwg.org/#pull-into-descriptor
+type pullIntoDescriptor struct {
+	buffer           sobek.ArrayBuffer
+	bufferByteLength int
+	byteOffset       int
+	byteLength       int
+	bytesFilled      int
+	minimumFill      int
+	elementSize      int
+	viewConstructor  *sobek.Object
+	readerType       readerType
+}
+
+// NewReadableByteStreamControllerObject creates a new [sobek.Object] from a
+// [ReadableByteStreamController] instance.
+func NewReadableByteStreamControllerObject(controller *ReadableByteStreamController) (*sobek.Object, error) {
+	rt := controller.stream.runtime
+	obj := rt.NewObject()
+	objName := "ReadableByteStreamController"
+
+	err := obj.DefineAccessorProperty("byobRequest", rt.ToValue(func() sobek.Value {
+		request := controller.getBYOBRequest()
+		if request == nil {
+			return sobek.Null()
+		}
+		return request.obj
+	}), nil, sobek.FLAG_FALSE, sobek.FLAG_TRUE)
+	if err != nil {
+		return nil, err
+	}
+
+	err = obj.DefineAccessorProperty("desiredSize", rt.ToValue(func() sobek.Value {
+		desiredSize := controller.getDesiredSize()
+		if !desiredSize.Valid {
+			return sobek.Null()
+		}
+		return rt.ToValue(desiredSize.Float64)
+	}), nil, sobek.FLAG_FALSE, sobek.FLAG_TRUE)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := setReadOnlyPropertyOf(obj, objName, "close", rt.ToValue(controller.Close)); err != nil {
+		return nil, err
+	}
+
+	if err := setReadOnlyPropertyOf(obj, objName, "enqueue", rt.ToValue(controller.Enqueue)); err != nil {
+		return nil, err
+	}
+
+	if err := setReadOnlyPropertyOf(obj, objName, "error", rt.ToValue(controller.Error)); err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+// Close closes the stream.
+//
+// It implements the ReadableByteStreamController.close() [specification] algorithm.
+//
+// [specification]: https://streams.spec.whatwg.org/#rbs-controller-close
+func (controller *ReadableByteStreamController) Close() {
+	rt := controller.stream.runtime
+
+	// 1. If this.[[closeRequested]] is true, throw a TypeError exception.
+	if controller.closeRequested {
+		throw(rt, newTypeError(rt, "the stream is already closing"))
+	}
+
+	// 2. If this.[[stream]].[[state]] is not "readable", throw a TypeError exception.
+	if controller.stream.state != ReadableStreamStateReadable {
+		throw(rt, newTypeError(rt, "the stream is not readable"))
+	}
+
+	// 3. Perform ? ReadableByteStreamControllerClose(this).
+	controller.close()
+}
+
+// Enqueue enqueues a chunk to the stream's internal queue.
+//
+// It implements the ReadableByteStreamController.enqueue(chunk) [specification] algorithm.
+//
+// [specification]: https://streams.spec.whatwg.org/#rbs-controller-enqueue
+func (controller *ReadableByteStreamController) Enqueue(chunk sobek.Value) {
+	rt := controller.stream.runtime
+
+	view, ok := toArrayBufferView(rt, chunk)
+	if !ok {
+		throw(rt, newTypeError(rt, "chunk must be an ArrayBufferView"))
+	}
+
+	// 1. If chunk.[[ByteLength]] is 0, throw a TypeError exception.
+	if view.byteLength == 0 {
+		throw(rt, newTypeError(rt, "chunk must have a non-zero byteLength"))
+	}
+
+	// 2. If chunk.[[ViewedArrayBuffer]].[[ArrayBufferByteLength]] is 0, throw a TypeError exception.
+	if len(view.buffer.Bytes()) == 0 {
+		throw(rt, newTypeError(rt, "chunk's buffer must have a non-zero byteLength"))
+	}
+
+	// 3. If this.[[closeRequested]] is true, throw a TypeError exception.
+	if controller.closeRequested {
+		throw(rt, newTypeError(rt, "the stream is already closing"))
+	}
+
+	// 4. If this.[[stream]].[[state]] is not "readable", throw a TypeError exception.
+	if controller.stream.state != ReadableStreamStateReadable {
+		throw(rt, newTypeError(rt, "the stream is not readable"))
+	}
+
+	// 5. Return ? ReadableByteStreamControllerEnqueue(this, chunk).
+	controller.enqueue(view)
+}
+
+// Error signals that the stream has been errored, and performs the necessary cleanup steps.
+//
+// It implements the ReadableByteStreamController.error(e) [specification] algorithm.
+//
+// [specification]: https://streams.spec.whatwg.org/#rbs-controller-error
+func (controller *ReadableByteStreamController) Error(err sobek.Value) {
+	if err == nil {
+		err = sobek.Undefined()
+	}
+	controller.error(err)
+}
+
+// cancelSteps implements the [ReadableByteStreamController [[CancelSteps]]] algorithm.
+//
+// [ReadableByteStreamController [[CancelSteps]]]: https://streams.spec.whatwg.org/#rbs-controller-private-cancel
+func (controller *ReadableByteStreamController) cancelSteps(reason any) *sobek.Promise {
+	// 1. Perform ! ReadableByteStreamControllerClearPendingPullIntos(this).
+	controller.clearPendingPullIntos()
+
+	// 2. Perform ! ResetQueue(this).
+	controller.resetQueue()
+
+	// 3. Let result be the result of performing this.[[cancelAlgorithm]], passing in reason.
+	result := controller.cancelAlgorithm(reason)
+
+	// 4. Perform ! ReadableByteStreamControllerClearAlgorithms(this).
+	controller.clearAlgorithms()
+
+	// 5. Return result.
+	if p, ok := result.Export().(*sobek.Promise); ok {
+		return p
+	}
+
+	return newRejectedPromise(controller.stream.vu, newError(RuntimeError, "cancel algorithm error"))
+}
+
+// pullSteps implements the [ReadableByteStreamController [[PullSteps]]] algorithm.
+//
+// [ReadableByteStreamController [[PullSteps]]]: https://streams.spec.whatwg.org/#rbs-controller-private-pull
+func (controller *ReadableByteStreamController) pullSteps(readRequest ReadRequest) {
+	rt := controller.stream.runtime
+
+	// 1. Let stream be this.[[stream]].
+	stream := controller.stream
+
+	// 2. Assert: ! ReadableStreamHasDefaultReader(stream) is true.
+	if !stream.hasDefaultReader() {
+		common.Throw(rt, newError(AssertionError, "stream does not have a default reader"))
+	}
+
+	// 3. If this.[[queueTotalSize]] > 0,
+	if controller.queueTotalSize > 0 {
+		// 3.1. Assert: ! ReadableStreamGetNumReadRequests(stream) is 0.
+		if stream.getNumReadRequests() != 0 {
+			common.Throw(rt, newError(AssertionError, "stream has pending read requests"))
+		}
+
+		// 3.2. Perform ! ReadableByteStreamControllerFillReadRequestFromQueue(this, readRequest).
+		controller.fillReadRequestFromQueue(readRequest)
+
+		// 3.3. Return.
+		return
+	}
+
+	// 4. Let autoAllocateChunkSize be this.[[autoAllocateChunkSize]].
+	autoAllocateChunkSize := int(controller.autoAllocateChunkSize)
+
+	// 5. If autoAllocateChunkSize is not undefined,
+	if autoAllocateChunkSize > 0 {
+		// 5.1. Let buffer be Construct(%ArrayBuffer%, « autoAllocateChunkSize »).
+		buffer := rt.NewArrayBuffer(make([]byte, autoAllocateChunkSize))
+
+		// 5.3. Let pullIntoDescriptor be a new pull-into descriptor with...
+		descriptor := &pullIntoDescriptor{
+			buffer:           buffer,
+			bufferByteLength: autoAllocateChunkSize,
+			byteOffset:       0,
+			byteLength:       autoAllocateChunkSize,
+			bytesFilled:      0,
+			minimumFill:      1,
+			elementSize:      1,
+			viewConstructor:  rt.Get("Uint8Array").ToObject(rt),
+			readerType:       readerTypeDefault,
+		}
+
+		// 5.4. Append pullIntoDescriptor to this.[[pendingPullIntos]].
+		controller.pendingPullIntos = append(controller.pendingPullIntos, descriptor)
+	}
+
+	// 6. Perform ! ReadableStreamAddReadRequest(stream, readRequest).
+	stream.addReadRequest(readRequest)
+
+	// 7. Perform ! ReadableByteStreamControllerCallPullIfNeeded(this).
+	controller.callPullIfNeeded()
+}
+
+// releaseSteps implements the [ReadableByteStreamController [[ReleaseSteps]]] algorithm.
+//
+// [ReadableByteStreamController [[ReleaseSteps]]]: https://streams.spec.whatwg.org/#abstract-opdef-readablebytestreamcontroller-releasesteps
+func (controller *ReadableByteStreamController) releaseSteps() {
+	// 1. If this.[[pendingPullIntos]] is not empty,
+	if len(controller.pendingPullIntos) > 0 {
+		// 1.1. Let firstPendingPullInto be this.[[pendingPullIntos]][0].
+		firstPendingPullInto := controller.pendingPullIntos[0]
+
+		// 1.2. Set firstPendingPullInto’s reader type to "none".
+		firstPendingPullInto.readerType = readerTypeNone
+
+		// 1.3. Set this.[[pendingPullIntos]] to the list « firstPendingPullInto ».
+		controller.pendingPullIntos = []*pullIntoDescriptor{firstPendingPullInto}
+	}
+}
+
+func (controller *ReadableByteStreamController) toObject() (*sobek.Object, error) {
+	if controller.obj != nil {
+		return controller.obj, nil
+	}
+
+	obj, err := NewReadableByteStreamControllerObject(controller)
+	if err != nil {
+		return nil, err
+	}
+	controller.obj = obj
+
+	return obj, nil
+}
+
+// callPullIfNeeded implements the [ReadableByteStreamControllerCallPullIfNeeded] algorithm.
+//
+// [ReadableByteStreamControllerCallPullIfNeeded]: https://streams.spec.whatwg.org/#readable-byte-stream-controller-call-pull-if-needed
+func (controller *ReadableByteStreamController) callPullIfNeeded() {
+	rt := controller.stream.runtime
+
+	// 1. Let shouldPull be ! ReadableByteStreamControllerShouldCallPull(controller).
+	// 2. If shouldPull is false, return.
+	if !controller.shouldCallPull() {
+		return
+	}
+
+	// 3. If controller.[[pulling]] is true,
+	if controller.pulling {
+		// 3.1. Set controller.[[pullAgain]] to true.
+		controller.pullAgain = true
+		// 3.2. Return.
+		return
+	}
+
+	// 4. Assert: controller.[[pullAgain]] is false.
+	if controller.pullAgain {
+		common.Throw(rt, newError(AssertionError, "controller.pullAgain is true"))
+	}
+
+	// 5. Set controller.[[pulling]] to true.
+	controller.pulling = true
+
+	// 6. Let pullPromise be the result of performing controller.[[pullAlgorithm]].
+	controllerObj, err := controller.toObject()
+	if err != nil {
+		common.Throw(rt, newError(RuntimeError, err.Error()))
+	}
+	pullPromise := controller.pullAlgorithm(controllerObj)
+
+	_, err = promiseThen(rt, pullPromise,
+		// 7. Upon fulfillment of pullPromise,
+		func(sobek.Value) {
+			// 7.1. Set controller.[[pulling]] to false.
+			controller.pulling = false
+
+			// 7.2. If controller.[[pullAgain]] is true,
+			if controller.pullAgain {
+				// 7.2.1. Set controller.[[pullAgain]] to false.
+				controller.pullAgain = false
+				// 7.2.2. Perform ! ReadableByteStreamControllerCallPullIfNeeded(controller).
+				controller.callPullIfNeeded()
+			}
+		},
+		// 8. Upon rejection of pullPromise with reason e,
+		func(reason sobek.Value) {
+			// 8.1. Perform ! ReadableByteStreamControllerError(controller, e).
+			controller.error(reason)
+		},
+	)
+	if err != nil {
+		common.Throw(rt, err)
+	}
+}
+
+// shouldCallPull implements the [ReadableByteStreamControllerShouldCallPull] algorithm.
+//
+// [ReadableByteStreamControllerShouldCallPull]: https://streams.spec.whatwg.org/#readable-byte-stream-controller-should-call-pull
+func (controller *ReadableByteStreamController) shouldCallPull() bool {
+	// 1. Let stream be controller.[[stream]].
+	stream := controller.stream
+
+	// 2. If stream.[[state]] is not "readable", return false.
+	if stream.state != ReadableStreamStateReadable {
+		return false
+	}
+
+	// 3. If controller.[[closeRequested]] is true, return false.
+	if controller.closeRequested {
+		return false
+	}
+
+	// 4. If controller.[[started]] is false, return false.
+	if !controller.started {
+		return false
+	}
+
+	// 5. If ! ReadableStreamHasDefaultReader(stream) is true and
+	// ! ReadableStreamGetNumReadRequests(stream) > 0, return true.
+	if stream.hasDefaultReader() && stream.getNumReadRequests() > 0 {
+		return true
+	}
+
+	// 6. If ! ReadableStreamHasBYOBReader(stream) is true and
+	// ! ReadableStreamGetNumReadIntoRequests(stream) > 0, return true.
+	if stream.hasBYOBReader() && stream.getNumReadIntoRequests() > 0 {
+		return true
+	}
+
+	// 7. Let desiredSize be ! ReadableByteStreamControllerGetDesiredSize(controller).
+	desiredSize := controller.getDesiredSize()
+
+	// 8. Assert: desiredSize is not null.
+	if !desiredSize.Valid {
+		common.Throw(stream.runtime, newError(AssertionError, "desiredSize is null"))
+	}
+
+	// 9. If desiredSize > 0, return true.
+	// 10. Return false.
+	return desiredSize.Float64 > 0
+}
+
+// getDesiredSize implements the [ReadableByteStreamControllerGetDesiredSize] algorithm.
+//
+// [ReadableByteStreamControllerGetDesiredSize]: https://streams.spec.whatwg.org/#readable-byte-stream-controller-get-desired-size
+func (controller *ReadableByteStreamController) getDesiredSize() null.Float {
+	switch controller.stream.state {
+	case ReadableStreamStateErrored:
+		return null.NewFloat(0, false)
+	case ReadableStreamStateClosed:
+		return null.NewFloat(0, true)
+	default:
+		return null.NewFloat(controller.strategyHWM-controller.queueTotalSize, true)
+	}
+}
+
+// getBYOBRequest implements the [ReadableByteStreamControllerGetBYOBRequest] algorithm.
+//
+// [ReadableByteStreamControllerGetBYOBRequest]: https://streams.spec.whatwg.org/#abstract-opdef-readablebytestreamcontrollergetbyobrequest
+func (controller *ReadableByteStreamController) getBYOBRequest() *ReadableStreamBYOBRequest {
+	rt := controller.stream.runtime
+
+	// 1. If controller.[[byobRequest]] is null and controller.[[pendingPullIntos]] is not empty,
+	if controller.byobRequest == nil && len(controller.pendingPullIntos) > 0 {
+		// 1.1. Let firstDescriptor be controller.[[pendingPullIntos]][0].
+		firstDescriptor := controller.pendingPullIntos[0]
+
+		// 1.2. Let view be ! Construct(%Uint8Array%, « firstDescriptor’s buffer,
+		// firstDescriptor’s byte offset + firstDescriptor’s bytes filled,
+		// firstDescriptor’s byte length − firstDescriptor’s bytes filled »).
+		view, err := newUint8Array(rt,
+			firstDescriptor.buffer,
+			firstDescriptor.byteOffset+firstDescriptor.bytesFilled,
+			firstDescriptor.byteLength-firstDescriptor.bytesFilled,
+		)
+		if err != nil {
+			common.Throw(rt, err)
+		}
+
+		// 1.3. Let byobRequest be a new ReadableStreamBYOBRequest.
+		// 1.4. Set byobRequest.[[controller]] to controller.
+		// 1.5. Set byobRequest.[[view]] to view.
+		byobRequest := &ReadableStreamBYOBRequest{controller: controller, view: view, runtime: rt}
+		byobRequest.obj, err = NewReadableStreamBYOBRequestObject(byobRequest)
+		if err != nil {
+			common.Throw(rt, err)
+		}
+
+		// 1.6. Set controller.[[byobRequest]] to byobRequest.
+		controller.byobRequest = byobRequest
+	}
+
+	// 2. Return controller.[[byobRequest]].
+	return controller.byobRequest
+}
+
+// clearAlgorithms implements the [ReadableByteStreamControllerClearAlgorithms] algorithm.
+//
+// [ReadableByteStreamControllerClearAlgorithms]: https://streams.spec.whatwg.org/#readable-byte-stream-controller-clear-algorithms
+func (controller *ReadableByteStreamController) clearAlgorithms() {
+	// 1. Set controller.[[pullAlgorithm]] to undefined.
+	controller.pullAlgorithm = nil
+
+	// 2. Set controller.[[cancelAlgorithm]] to undefined.
+	controller.cancelAlgorithm = nil
+}
+
+// clearPendingPullIntos implements the [ReadableByteStreamControllerClearPendingPullIntos] algorithm.
+//
+// [ReadableByteStreamControllerClearPendingPullIntos]: https://streams.spec.whatwg.org/#readable-byte-stream-controller-clear-pending-pull-intos
+func (controller *ReadableByteStreamController) clearPendingPullIntos() {
+	// 1. Perform ! ReadableByteStreamControllerInvalidateBYOBRequest(controller).
+	controller.invalidateBYOBRequest()
+
+	// 2. Set controller.[[pendingPullIntos]] to a new empty list.
+	controller.pendingPullIntos = nil
+}
+
+// resetQueue implements the [ResetQueue] algorithm for the byte queue of the controller.
+//
+// [ResetQueue]: https://streams.spec.whatwg.org/#reset-queue
+func (controller *ReadableByteStreamController) resetQueue() {
+	controller.queue = nil
+	controller.queueTotalSize = 0
+}
+
+// close implements the [ReadableByteStreamControllerClose] algorithm.
+//
+// [ReadableByteStreamControllerClose]: https://streams.spec.whatwg.org/#readable-byte-stream-controller-close
+func (controller *ReadableByteStreamController) close() {
+	rt := controller.stream.runtime
+
+	// 1. Let stream be controller.[[stream]].
+	stream := controller.stream
+
+	// 2. If controller.[[closeRequested]] is true or stream.[[state]] is not "readable", return.
+	if controller.closeRequested || stream.state != ReadableStreamStateReadable {
+		return
+	}
+
+	// 3. If controller.[[queueTotalSize]] > 0,
+	if controller.queueTotalSize > 0 {
+		// 3.1. Set controller.[[closeRequested]] to true.
+		controller.closeRequested = true
+		// 3.2. Return.
+		return
+	}
+
+	// 4. If controller.[[pendingPullIntos]] is not empty,
+	if len(controller.pendingPullIntos) > 0 {
+		// 4.1. Let firstPendingPullInto be controller.[[pendingPullIntos]][0].
+		firstPendingPullInto := controller.pendingPullIntos[0]
+
+		// 4.2. If the remainder after dividing firstPendingPullInto’s bytes filled by
+		// firstPendingPullInto’s element size is not 0,
+		if firstPendingPullInto.bytesFilled%firstPendingPullInto.elementSize != 0 {
+			// 4.2.1. Let e be a new TypeError exception.
+			e := newTypeError(rt, "insufficient bytes to fill the elements of the given buffer")
+			// 4.2.2. Perform ! ReadableByteStreamControllerError(controller, e).
+			controller.error(e)
+			// 4.2.3. Throw e.
+			throw(rt, e)
+		}
+	}
+
+	// 5. Perform ! ReadableByteStreamControllerClearAlgorithms(controller).
+	controller.clearAlgorithms()
+
+	// 6. Perform ! ReadableStreamClose(stream).
+	stream.close()
+}
+
+// enqueue implements the [ReadableByteStreamControllerEnqueue] algorithm.
+//
+// [ReadableByteStreamControllerEnqueue]: https://streams.spec.whatwg.org/#readable-byte-stream-controller-enqueue
+func (controller *ReadableByteStreamController) enqueue(chunk arrayBufferView) {
+	rt := controller.stream.runtime
+
+	// 1. Let stream be controller.[[stream]].
+	stream := controller.stream
+
+	// 2. If controller.[[closeRequested]] is true or stream.[[state]] is not "readable", return.
+	if controller.closeRequested || stream.state != ReadableStreamStateReadable {
+		return
+	}
+
+	// 3. Let buffer be chunk.[[ViewedArrayBuffer]].
+	// 4. Let byteOffset be chunk.[[ByteOffset]].
+	// 5. Let byteLength be chunk.[[ByteLength]].
+	buffer, byteOffset, byteLength := chunk.buffer, chunk.byteOffset, chunk.byteLength
+
+	// 6. If ! IsDetachedBuffer(buffer) is true, throw a TypeError exception.
+	if buffer.Detached() {
+		throw(rt, newTypeError(rt, "chunk's buffer is detached"))
+	}
+
+	// 7. Let transferredBuffer be ? TransferArrayBuffer(buffer).
+	transferredBuffer, err := transferArrayBuffer(rt, buffer)
+	if err != nil {
+		throw(rt, err)
+	}
+
+	// 8. If controller.[[pendingPullIntos]] is not empty,
+	if len(controller.pendingPullIntos) > 0 {
+		// 8.1. Let firstPendingPullInto be controller.[[pendingPullIntos]][0].
+		firstPendingPullInto := controller.pendingPullIntos[0]
+
+		// 8.2. If ! IsDetachedBuffer(firstPendingPullInto’s buffer) is true, throw a TypeError exception.
+		if firstPendingPullInto.buffer.Detached() {
+			throw(rt, newTypeError(rt, "the BYOB request's buffer is detached"))
+		}
+
+		// 8.3. Perform ! ReadableByteStreamControllerInvalidateBYOBRequest(controller).
+		controller.invalidateBYOBRequest()
+
+		// 8.4. Set firstPendingPullInto’s buffer to ! TransferArrayBuffer(firstPendingPullInto’s buffer).
+		firstPendingPullInto.buffer, err = transferArrayBuffer(rt, firstPendingPullInto.buffer)
+		if err != nil {
+			throw(rt, err)
+		}
+
+		// 8.5. If firstPendingPullInto’s reader type is "none", perform
+		// ? ReadableByteStreamControllerEnqueueDetachedPullIntoToQueue(controller, firstPendingPullInto).
+		if firstPendingPullInto.readerType == readerTypeNone {
+			controller.enqueueDetachedPullIntoToQueue(firstPendingPullInto)
+		}
+	}
+
+	switch {
+	// 9. If ! ReadableStreamHasDefaultReader(stream) is true,
+	case stream.hasDefaultReader():
+		// 9.1. Perform ! ReadableByteStreamControllerProcessReadRequestsUsingQueue(controller).
+		controller.processReadRequestsUsingQueue()
+
+		// 9.2. If ! ReadableStreamGetNumReadRequests(stream) is 0,
+		if stream.getNumReadRequests() == 0 {
+			// 9.2.1. Assert: controller.[[pendingPullIntos]] is empty.
+			// 9.2.2. Perform ! ReadableByteStreamControllerEnqueueChunkToQueue(controller,
+			// transferredBuffer, byteOffset, byteLength).
+			controller.enqueueChunkToQueue(transferredBuffer, byteOffset, byteLength)
+		} else { // 9.3. Otherwise,
+			// 9.3.2. If controller.[[pendingPullIntos]] is not empty,
+			if len(controller.pendingPullIntos) > 0 {
+				// 9.3.2.1. Assert: controller.[[pendingPullIntos]][0]'s reader type is "default".
+				// 9.3.2.2. Perform ! ReadableByteStreamControllerShiftPendingPullInto(controller).
+				controller.shiftPendingPullInto()
+			}
+
+			// 9.3.3. Let transferredView be ! Construct(%Uint8Array%, « transferredBuffer, byteOffset, byteLength »).
+			transferredView, err := newUint8Array(rt, transferredBuffer, byteOffset, byteLength)
+			if err != nil {
+				throw(rt, err)
+			}
+
+			// 9.3.4. Perform ! ReadableStreamFulfillReadRequest(stream, transferredView, false).
+			stream.fulfillReadRequest(transferredView, false)
+		}
+
+	// 10. Otherwise, if ! ReadableStreamHasBYOBReader(stream) is true,
+	case stream.hasBYOBReader():
+		// 10.1. Perform ! ReadableByteStreamControllerEnqueueChunkToQueue(controller,
+		// transferredBuffer, byteOffset, byteLength).
+		controller.enqueueChunkToQueue(transferredBuffer, byteOffset, byteLength)
+
+		// 10.2. Let filledPullIntos be the result of performing
+		// ! ReadableByteStreamControllerProcessPullIntoDescriptorsUsingQueue(controller).
+		filledPullIntos := controller.processPullIntoDescriptorsUsingQueue()
+
+		// 10.3. For each filledPullInto of filledPullIntos,
+		for _, filledPullInto := range filledPullIntos {
+			// 10.3.1. Perform ! ReadableByteStreamControllerCommitPullIntoDescriptor(stream, filledPullInto).
+			controller.commitPullIntoDescriptor(filledPullInto)
+		}
+
+	// 11. Otherwise,
+	default:
+		// 11.1. Assert: ! IsReadableStreamLocked(stream) is false.
+		// 11.2. Perform ! ReadableByteStreamControllerEnqueueChunkToQueue(controller,
+		// transferredBuffer, byteOffset, byteLength).
+		controller.enqueueChunkToQueue(transferredBuffer, byteOffset, byteLength)
+	}
+
+	// 12. Perform ! ReadableByteStreamControllerCallPullIfNeeded(controller).
+	controller.callPullIfNeeded()
+}
+
+// enqueueChunkToQueue implements the [ReadableByteStreamControllerEnqueueChunkToQueue] algorithm.
+//
+// [ReadableByteStreamControllerEnqueueChunkToQueue]: https://streams.spec.whatwg.org/#readable-byte-stream-controller-enqueue-chunk-to-queue
+func (controller *ReadableByteStreamController) enqueueChunkToQueue(
+	buffer sobek.ArrayBuffer,
+	byteOffset, byteLength int,
+) {
+	// 1. Append a new readable byte stream queue entry with buffer buffer, byte offset
+	// byteOffset, and byte length byteLength to controller.[[queue]].
+	controller.queue = append(controller.queue, readableByteStreamQueueEntry{
+		buffer:     buffer,
+		byteOffset: byteOffset,
+		byteLength: byteLength,
+	})
+
+	// 2. Set controller.[[queueTotalSize]] to controller.[[queueTotalSize]] + byteLength.
+	controller.queueTotalSize += float64(byteLength)
+}
+
+// enqueueClonedChunkToQueue implements the [ReadableByteStreamControllerEnqueueClonedChunkToQueue] algorithm.
+//
+// [ReadableByteStreamControllerEnqueueClonedChunkToQueue]: https://streams.spec.whatwg.org/#abstract-opdef-readablebytestreamcontrollerenqueueclonedchunktoqueue
+func (controller *ReadableByteStreamController) enqueueClonedChunkToQueue(
+	buffer sobek.ArrayBuffer,
+	byteOffset, byteLength int,
+) {
+	// 1. Let cloneResult be CloneArrayBuffer(buffer, byteOffset, byteLength, %ArrayBuffer%).
+	clone, err := cloneArrayBuffer(controller.stream.runtime, buffer, byteOffset, byteLength)
+
+	// 2. If cloneResult is an abrupt completion,
+	if err != nil {
+		// 2.1. Perform ! ReadableByteStreamControllerError(controller, cloneResult.[[Value]]).
+		controller.error(err)
+		// 2.2. Return cloneResult.
+		throw(controller.stream.runtime, err)
+	}
+
+	// 3. Perform ! ReadableByteStreamControllerEnqueueChunkToQueue(controller,
+	// cloneResult.[[Value]], 0, byteLength).
+	controller.enqueueChunkToQueue(clone, 0, byteLength)
+}
+
+// enqueueDetachedPullIntoToQueue implements the [ReadableByteStreamControllerEnqueueDetachedPullIntoToQueue] algorithm.
+//
+// [ReadableByteStreamControllerEnqueueDetachedPullIntoToQueue]: https://streams.spec.whatwg.org/#abstract-opdef-readablebytestreamcontrollerenqueuedetachedpullintotoqueue
+func (controller *ReadableByteStreamController) enqueueDetachedPullIntoToQueue(descriptor *pullIntoDescriptor) {
+	// 1. Assert: pullIntoDescriptor’s reader type is "none".
+	if descriptor.readerType != readerTypeNone {
+		common.Throw(controller.stream.runtime, newError(AssertionError, "pull-into descriptor's reader type is not none"))
+	}
+
+	// 2. If pullIntoDescriptor’s bytes filled > 0, perform ? ReadableByteStreamControllerEnqueueClonedChunkToQueue(
+	// controller, pullIntoDescriptor’s buffer, pullIntoDescriptor’s byte offset, pullIntoDescriptor’s bytes filled).
+	if descriptor.bytesFilled > 0 {
+		controller.enqueueClonedChunkToQueue(descriptor.buffer, descriptor.byteOffset, descriptor.bytesFilled)
+	}
+
+	// 3. Perform ! ReadableByteStreamControllerShiftPendingPullInto(controller).
+	controller.shiftPendingPullInto()
+}
+
+// error implements the [ReadableByteStreamControllerError] algorithm.
+//
+// [ReadableByteStreamControllerError]: https://streams.spec.whatwg.org/#readable-byte-stream-controller-error
+func (controller *ReadableByteStreamController) error(e any) {
+	// 1. Let stream be controller.[[stream]].
+	stream := controller.stream
+
+	// 2. If stream.[[state]] is not "readable", return.
+	if stream.state != ReadableStreamStateReadable {
+		return
+	}
+
+	// 3. Perform ! ReadableByteStreamControllerClearPendingPullIntos(controller).
+	controller.clearPendingPullIntos()
+
+	// 4. Perform ! ResetQueue(controller).
+	controller.resetQueue()
+
+	// 5. Perform ! ReadableByteStreamControllerClearAlgorithms(controller).
+	controller.clearAlgorithms()
+
+	// 6. Perform ! ReadableStreamError(stream, e).
+	stream.error(e)
+}
+
+// fillHeadPullIntoDescriptor implements the [ReadableByteStreamControllerFillHeadPullIntoDescriptor] algorithm.
+//
+// [ReadableByteStreamControllerFillHeadPullIntoDescriptor]: https://streams.spec.whatwg.org/#readable-byte-stream-controller-fill-head-pull-into-descriptor
+func (controller *ReadableByteStreamController) fillHeadPullIntoDescriptor(size int, descriptor *pullIntoDescriptor) {
+	// 1. Assert: either controller.[[pendingPullIntos]] is empty, or
+	// controller.[[pendingPullIntos]][0] is pullIntoDescriptor.
+	// 2. Assert: controller.[[byobRequest]] is null.
+	// 3. Set pullIntoDescriptor’s bytes filled to bytes filled + size.
+	descriptor.bytesFilled += size
+}
+
+// fillPullIntoDescriptorFromQueue implements the [ReadableByteStreamControllerFillPullIntoDescriptorFromQueue] algorithm.
+//
+// [ReadableByteStreamControllerFillPullIntoDescriptorFromQueue]: https://streams.spec.whatwg.org/#readable-byte-stream-controller-fill-pull-into-descriptor-from-queue
+func (controller *ReadableByteStreamController) fillPullIntoDescriptorFromQueue(descriptor *pullIntoDescriptor) bool {
+	// 1. Let maxBytesToCopy be min(controller.[[queueTotalSize]],
+	// pullIntoDescriptor’s byte length − pullIntoDescriptor’s bytes filled).
+	maxBytesToCopy := min(int(controller.queueTotalSize), descriptor.byteLength-descriptor.bytesFilled)
+
+	// 2. Let maxBytesFilled be pullIntoDescriptor’s bytes filled + maxBytesToCopy.
+	maxBytesFilled := descriptor.bytesFilled + maxBytesToCopy
+
+	// 3. Let totalBytesToCopyRemaining be maxBytesToCopy.
+	totalBytesToCopyRemaining := maxBytesToCopy
+
+	// 4. Let ready be false.
+	ready := false
+
+	// 6. Let remainderBytes be the remainder after dividing maxBytesFilled by pullIntoDescriptor’s element size.
+	remainderBytes := maxBytesFilled % descriptor.elementSize
+
+	// 7. Let maxAlignedBytes be maxBytesFilled − remainderBytes.
+	maxAlignedBytes := maxBytesFilled - remainderBytes
+
+	// 8. If maxAlignedBytes ≥ pullIntoDescriptor’s minimum fill,
+	if maxAlignedBytes >= descriptor.minimumFill {
+		// 8.1. Set totalBytesToCopyRemaining to maxAlignedBytes − pullIntoDescriptor’s bytes filled.
+		totalBytesToCopyRemaining = maxAlignedBytes - descriptor.bytesFilled
+		// 8.2. Set ready to true.
+		ready = true
+	}
+
+	// 10. While totalBytesToCopyRemaining > 0,
+	for totalBytesToCopyRemaining > 0 {
+		// 10.1. Let headOfQueue be queue[0].
+		headOfQueue := &controller.queue[0]
+
+		// 10.2. Let bytesToCopy be min(totalBytesToCopyRemaining, headOfQueue’s byte length).
+		bytesToCopy := min(totalBytesToCopyRemaining, headOfQueue.byteLength)
+
+		// 10.3. Let destStart be pullIntoDescriptor’s byte offset + pullIntoDescriptor’s bytes filled.
+		destStart := descriptor.byteOffset + descriptor.bytesFilled
+
+		// 10.4. Perform ! CopyDataBlockBytes(pullIntoDescriptor’s buffer.[[ArrayBufferData]], destStart,
+		// headOfQueue’s buffer.[[ArrayBufferData]], headOfQueue’s byte offset, bytesToCopy).
+		copy(
+			descriptor.buffer.Bytes()[destStart:destStart+bytesToCopy],
+			headOfQueue.buffer.Bytes()[headOfQueue.byteOffset:headOfQueue.byteOffset+bytesToCopy],
+		)
+
+		// 10.5. If headOfQueue’s byte length is bytesToCopy,
+		if headOfQueue.byteLength == bytesToCopy {
+			// 10.5.1. Remove queue[0].
+			controller.queue = controller.queue[1:]
+		} else { // 10.6. Otherwise,
+			// 10.6.1. Set headOfQueue’s byte offset to headOfQueue’s byte offset + bytesToCopy.
+			headOfQueue.byteOffset += bytesToCopy
+			// 10.6.2. Set headOfQueue’s byte length to headOfQueue’s byte length − bytesToCopy.
+			headOfQueue.byteLength -= bytesToCopy
+		}
+
+		// 10.7. Set controller.[[queueTotalSize]] to controller.[[queueTotalSize]] − bytesToCopy.
+		controller.queueTotalSize -= float64(bytesToCopy)
+
+		// 10.8. Perform ! ReadableByteStreamControllerFillHeadPullIntoDescriptor(controller, bytesToCopy, pullIntoDescriptor).
+		controller.fillHeadPullIntoDescriptor(bytesToCopy, descriptor)
+
+		// 10.9. Set totalBytesToCopyRemaining to totalBytesToCopyRemaining − bytesToCopy.
+		totalBytesToCopyRemaining -= bytesToCopy
+	}
+
+	// 11. If ready is false,
+	if !ready {
+		// 11.1. Assert: controller.[[queueTotalSize]] is 0.
+		// 11.2. Assert: pullIntoDescriptor’s bytes filled > 0.
+		// 11.3. Assert: pullIntoDescriptor’s bytes filled < pullIntoDescriptor’s minimum fill.
+		if controller.queueTotalSize != 0 {
+			common.Throw(controller.stream.runtime, newError(AssertionError, "the queue is not empty"))
+		}
+	}
+
+	// 12. Return ready.
+	return ready
+}
+
+// fillReadRequestFromQueue implements the [ReadableByteStreamControllerFillReadRequestFromQueue] algorithm.
+//
+// [ReadableByteStreamControllerFillReadRequestFromQueue]: https://streams.spec.whatwg.org/#abstract-opdef-readablebytestreamcontrollerfillreadrequestfromqueue
+func (controller *ReadableByteStreamController) fillReadRequestFromQueue(readRequest ReadRequest) {
+	rt := controller.stream.runtime
+
+	// 1. Assert: controller.[[queueTotalSize]] > 0.
+	if controller.queueTotalSize <= 0 {
+		common.Throw(rt, newError(AssertionError, "the queue is empty"))
+	}
+
+	// 2. Let entry be controller.[[queue]][0].
+	entry := controller.queue[0]
+
+	// 3. Remove entry from controller.[[queue]].
+	controller.queue = controller.queue[1:]
+
+	// 4. Set controller.[[queueTotalSize]] to controller.[[queueTotalSize]] − entry’s byte length.
+	controller.queueTotalSize -= float64(entry.byteLength)
+
+	// 5. Perform ! ReadableByteStreamControllerHandleQueueDrain(controller).
+	controller.handleQueueDrain()
+
+	// 6. Let view be ! Construct(%Uint8Array%, « entry’s buffer, entry’s byte offset, entry’s byte length »).
+	view, err := newUint8Array(rt, entry.buffer, entry.byteOffset, entry.byteLength)
+	if err != nil {
+		common.Throw(rt, err)
+	}
+
+	// 7. Perform readRequest’s chunk steps, given view.
+	readRequest.chunkSteps(view)
+}
+
+// handleQueueDrain implements the [ReadableByteStreamControllerHandleQueueDrain] algorithm.
+//
+// [ReadableByteStreamControllerHandleQueueDrain]: https://streams.spec.whatwg.org/#readable-byte-stream-controller-handle-queue-drain
+func (controller *ReadableByteStreamController) handleQueueDrain() {
+	// 1. Assert: controller.[[stream]].[[state]] is "readable".
+	if controller.stream.state != ReadableStreamStateReadable {
+		common.Throw(controller.stream.runtime, newError(AssertionError, "the stream is not readable"))
+	}
+
+	// 2. If controller.[[queueTotalSize]] is 0 and controller.[[closeRequested]] is true,
+	if controller.queueTotalSize == 0 && controller.closeRequested {
+		// 2.1. Perform ! ReadableByteStreamControllerClearAlgorithms(controller).
+		controller.clearAlgorithms()
+		// 2.2. Perform ! ReadableStreamClose(controller.[[stream]]).
+		controller.stream.close()
+	} else { // 3. Otherwise,
+		// 3.1. Perform ! ReadableByteStreamControllerCallPullIfNeeded(controller).
+		controller.callPullIfNeeded()
+	}
+}
+
+// invalidateBYOBRequest implements the [ReadableByteStreamControllerInvalidateBYOBRequest] algorithm.
+//
+// [ReadableByteStreamControllerInvalidateBYOBRequest]: https://streams.spec.whatwg.org/#readable-byte-stream-controller-invalidate-byob-request
+func (controller *ReadableByteStreamController) invalidateBYOBRequest() {
+	// 1. If controller.[[byobRequest]] is null, return.
+	if controller.byobRequest == nil {
+		return
+	}
+
+	// 2. Set controller.[[byobRequest]].[[controller]] to undefined.
+	controller.byobRequest.controller = nil
+
+	// 3. Set controller.[[byobRequest]].[[view]] to null.
+	controller.byobRequest.view = nil
+
+	// 4. Set controller.[[byobRequest]] to null.
+	controller.byobRequest = nil
+}
+
+// processPullIntoDescriptorsUsingQueue implements the
+// [ReadableByteStreamControllerProcessPullIntoDescriptorsUsingQueue] algorithm.
+//
+// [ReadableByteStreamControllerProcessPullIntoDescriptorsUsingQueue]: https://streams.spec.whatwg.org/#readable-byte-stream-controller-process-pull-into-descriptors-using-queue
+func (controller *ReadableByteStreamController) processPullIntoDescriptorsUsingQueue() []*pullIntoDescriptor {
+	// 1. Assert: controller.[[closeRequested]] is false.
+	if controller.closeRequested {
+		common.Throw(controller.stream.runtime, newError(AssertionError, "close was requested"))
+	}
+
+	// 2. Let filledPullIntos be a new empty list.
+	var filledPullIntos []*pullIntoDescriptor
+
+	// 3. While controller.[[pendingPullIntos]] is not empty,
+	for len(controller.pendingPullIntos) > 0 {
+		// 3.1. If controller.[[queueTotalSize]] is 0, then break.
+		if controller.queueTotalSize == 0 {
+			break
+		}
+
+		// 3.2. Let pullIntoDescriptor be controller.[[pendingPullIntos]][0].
+		descriptor := controller.pendingPullIntos[0]
+
+		// 3.3. If ! ReadableByteStreamControllerFillPullIntoDescriptorFromQueue(controller, pullIntoDescriptor) is true,
+		if controller.fillPullIntoDescriptorFromQueue(descriptor) {
+			// 3.3.1. Perform ! ReadableByteStreamControllerShiftPendingPullInto(controller).
+			controller.shiftPendingPullInto()
+			// 3.3.2. Append pullIntoDescriptor to filledPullIntos.
+			filledPullIntos = append(filledPullIntos, descriptor)
+		}
+	}
+
+	// 4. Return filledPullIntos.
+	return filledPullIntos
+}
+
+// processReadRequestsUsingQueue implements the [ReadableByteStreamControllerProcessReadRequestsUsingQueue] algorithm.
+//
+// [ReadableByteStreamControllerProcessReadRequestsUsingQueue]: https://streams.spec.whatwg.org/#abstract-opdef-readablebytestreamcontrollerprocessreadrequestsusingqueue
+func (controller *ReadableByteStreamController) processReadRequestsUsingQueue() {
+	// 1. Let reader be controller.[[stream]].[[reader]].
+	// 2. Assert: reader implements ReadableStreamDefaultReader.
+	reader, ok := controller.stream.reader.(*ReadableStreamDefaultReader)
+	if !ok {
+		common.Throw(controller.stream.runtime, newError(AssertionError, "reader is not a ReadableStreamDefaultReader"))
+	}
+
+	// 3. While reader.[[readRequests]] is not empty,
+	for len(reader.readRequests) > 0 {
+		// 3.1. If controller.[[queueTotalSize]] is 0, return.
+		if controller.queueTotalSize == 0 {
+			return
+		}
+
+		// 3.2. Let readRequest be reader.[[readRequests]][0].
+		readRequest := reader.readRequests[0]
+
+		// 3.3. Remove readRequest from reader.[[readRequests]].
+		reader.readRequests = reader.readRequests[1:]
+
+		// 3.4. Perform ! ReadableByteStreamControllerFillReadRequestFromQueue(controller, readRequest).
+		controller.fillReadRequestFromQueue(readRequest)
+	}
+}
+
+// pullInto implements the [ReadableByteStreamControllerPullInto] algorithm.
+//
+// [ReadableByteStreamControllerPullInto]: https://streams.spec.whatwg.org/#readable-byte-stream-controller-pull-into
+func (controller *ReadableByteStreamController) pullInto(view arrayBufferView, minimum int, readIntoRequest ReadIntoRequest) {
+	rt := controller.stream.runtime
+
+	// 1. Let stream be controller.[[stream]].
+	stream := controller.stream
+
+	// 2-4. Let elementSize and ctor be the element size and the constructor of the view.
+	elementSize, ctor := view.elementSize, view.constructor
+
+	// 5. Let minimumFill be min × elementSize.
+	minimumFill := minimum * elementSize
+
+	// 6. Assert: minimumFill ≥ 0 and minimumFill ≤ view.[[ByteLength]].
+	// 7. Assert: the remainder after dividing minimumFill by elementSize is 0.
+	if minimumFill < 0 || minimumFill > view.byteLength {
+		common.Throw(rt, newError(AssertionError, "invalid minimum fill"))
+	}
+
+	// 8. Let byteOffset be view.[[ByteOffset]].
+	// 9. Let byteLength be view.[[ByteLength]].
+	byteOffset, byteLength := view.byteOffset, view.byteLength
+
+	// 10. Let bufferResult be TransferArrayBuffer(view.[[ViewedArrayBuffer]]).
+	buffer, err := transferArrayBuffer(rt, view.buffer)
+
+	// 11. If bufferResult is an abrupt completion,
+	if err != nil {
+		// 11.1. Perform readIntoRequest’s error steps, given bufferResult.[[Value]].
+		readIntoRequest.errorSteps(err)
+		// 11.2. Return.
+		return
+	}
+
+	// 13. Let pullIntoDescriptor be a new pull-into descriptor with...
+	descriptor := &pullIntoDescriptor{
+		buffer:           buffer,
+		bufferByteLength: len(buffer.Bytes()),
+		byteOffset:       byteOffset,
+		byteLength:       byteLength,
+		bytesFilled:      0,
+		minimumFill:      minimumFill,
+		elementSize:      elementSize,
+		viewConstructor:  ctor,
+		readerType:       readerTypeBYOB,
+	}
+
+	// 14. If controller.[[pendingPullIntos]] is not empty,
+	if len(controller.pendingPullIntos) > 0 {
+		// 14.1. Append pullIntoDescriptor to controller.[[pendingPullIntos]].
+		controller.pendingPullIntos = append(controller.pendingPullIntos, descriptor)
+
+		// 14.2. Perform ! ReadableStreamAddReadIntoRequest(stream, readIntoRequest).
+		stream.addReadIntoRequest(readIntoRequest)
+
+		// 14.3. Return.
+		return
+	}
+
+	// 15. If stream.[[state]] is "closed",
+	if stream.state == ReadableStreamStateClosed {
+		// 15.1. Let emptyView be ! Construct(ctor, « pullIntoDescriptor’s buffer,
+		// pullIntoDescriptor’s byte offset, 0 »).
+		emptyView, err := newArrayBufferView(rt, ctor, descriptor.buffer, descriptor.byteOffset, 0)
+		if err != nil {
+			common.Throw(rt, err)
+		}
+
+		// 15.2. Perform readIntoRequest’s close steps, given emptyView.
+		readIntoRequest.closeSteps(emptyView)
+
+		// 15.3. Return.
+		return
+	}
+
+	// 16. If controller.[[queueTotalSize]] > 0,
+	if controller.queueTotalSize > 0 {
+		// 16.1. If ! ReadableByteStreamControllerFillPullIntoDescriptorFromQueue(controller,
+		// pullIntoDescriptor) is true,
+		if controller.fillPullIntoDescriptorFromQueue(descriptor) {
+			// 16.1.1. Let filledView be ! ReadableByteStreamControllerConvertPullIntoDescriptor(pullIntoDescriptor).
+			filledView := controller.convertPullIntoDescriptor(descriptor)
+
+			// 16.1.2. Perform ! ReadableByteStreamControllerHandleQueueDrain(controller).
+			controller.handleQueueDrain()
+
+			// 16.1.3. Perform readIntoRequest’s chunk steps, given filledView.
+			readIntoRequest.chunkSteps(filledView)
+
+			// 16.1.4. Return.
+			return
+		}
+
+		// 16.2. If controller.[[closeRequested]] is true,
+		if controller.closeRequested {
+			// 16.2.1. Let e be a new TypeError exception.
+			e := newTypeError(rt, "insufficient bytes to fill the elements of the given buffer")
+
+			// 16.2.2. Perform ! ReadableByteStreamControllerError(controller, e).
+			controller.error(e)
+
+			// 16.2.3. Perform readIntoRequest’s error steps, given e.
+			readIntoRequest.errorSteps(e.Err())
+
+			// 16.2.4. Return.
+			return
+		}
+	}
+
+	// 17. Append pullIntoDescriptor to controller.[[pendingPullIntos]].
+	controller.pendingPullIntos = append(controller.pendingPullIntos, descriptor)
+
+	// 18. Perform ! ReadableStreamAddReadIntoRequest(stream, readIntoRequest).
+	stream.addReadIntoRequest(readIntoRequest)
+
+	// 19. Perform ! ReadableByteStreamControllerCallPullIfNeeded(controller).
+	controller.callPullIfNeeded()
+}
+
+// convertPullIntoDescriptor implements the [ReadableByteStreamControllerConvertPullIntoDescriptor] algorithm.
+//
+// [ReadableByteStreamControllerConvertPullIntoDescriptor]: https://streams.spec.whatwg.org/#readable-byte-stream-controller-convert-pull-into-descriptor
+func (controller *ReadableByteStreamController) convertPullIntoDescriptor(descriptor *pullIntoDescriptor) *sobek.Object {
+	rt := controller.stream.runtime
+
+	// 1. Let bytesFilled be pullIntoDescriptor’s bytes filled.
+	// 2. Let elementSize be pullIntoDescriptor’s element size.
+	bytesFilled, elementSize := descriptor.bytesFilled, descriptor.elementSize
+
+	// 3. Assert: bytesFilled ≤ pullIntoDescriptor’s byte length.
+	// 4. Assert: the remainder after dividing bytesFilled by elementSize is 0.
+	if bytesFilled > descriptor.byteLength || bytesFilled%elementSize != 0 {
+		common.Throw(rt, newError(AssertionError, "invalid bytes filled"))
+	}
+
+	// 5. Let buffer be ! TransferArrayBuffer(pullIntoDescriptor’s buffer).
+	buffer, err := transferArrayBuffer(rt, descriptor.buffer)
+	if err != nil {
+		throw(rt, err)
+	}
+
+	// 6. Return ! Construct(pullIntoDescriptor’s view constructor,
+	// « buffer, pullIntoDescriptor’s byte offset, bytesFilled ÷ elementSize »).
+	view, err := newArrayBufferView(rt, descriptor.viewConstructor, buffer, descriptor.byteOffset, bytesFilled/elementSize)
+	if err != nil {
+		common.Throw(rt, err)
+	}
+
+	return view
+}
+
+// commitPullIntoDescriptor implements the [ReadableByteStreamControllerCommitPullIntoDescriptor] algorithm.
+//
+// [ReadableByteStreamControllerCommitPullIntoDescriptor]: https://streams.spec.whatwg.org/#readable-byte-stream-controller-commit-pull-into-descriptor
+func (controller *ReadableByteStreamController) commitPullIntoDescriptor(descriptor *pullIntoDescriptor) {
+	rt := controller.stream.runtime
+	stream := controller.stream
+
+	// 1. Assert: stream.[[state]] is not "errored".
+	// 2. Assert: pullIntoDescriptor.reader type is not "none".
+	if stream.state == ReadableStreamStateErrored || descriptor.readerType == readerTypeNone {
+		common.Throw(rt, newError(AssertionError, "cannot commit the pull-into descriptor"))
+	}
+
+	// 3. Let done be false.
+	done := false
+
+	// 4. If stream.[[state]] is "closed",
+	if stream.state == ReadableStreamStateClosed {
+		// 4.1. Assert: the remainder after dividing pullIntoDescriptor’s bytes filled
+		// by pullIntoDescriptor’s element size is 0.
+		// 4.2. Set done to true.
+		done = true
+	}
+
+	// 5. Let filledView be ! ReadableByteStreamControllerConvertPullIntoDescriptor(pullIntoDescriptor).
+	filledView := controller.convertPullIntoDescriptor(descriptor)
+
+	// 6. If pullIntoDescriptor’s reader type is "default",
+	if descriptor.readerType == readerTypeDefault {
+		// 6.1. Perform ! ReadableStreamFulfillReadRequest(stream, filledView, done).
+		stream.fulfillReadRequest(filledView, done)
+	} else { // 7. Otherwise,
+		// 7.2. Perform ! ReadableStreamFulfillReadIntoRequest(stream, filledView, done).
+		stream.fulfillReadIntoRequest(filledView, done)
+	}
+}
+
+// shiftPendingPullInto implements the [ReadableByteStreamControllerShiftPendingPullInto] algorithm.
+//
+// [ReadableByteStreamControllerShiftPendingPullInto]: https://streams.spec.whatwg.org/#readable-byte-stream-controller-shift-pending-pull-into
+func (controller *ReadableByteStreamController) shiftPendingPullInto() *pullIntoDescriptor {
+	// 1. Assert: controller.[[byobRequest]] is null.
+	if controller.byobRequest != nil {
+		common.Throw(controller.stream.runtime, newError(AssertionError, "the BYOB request is not null"))
+	}
+
+	// 2. Let descriptor be controller.[[pendingPullIntos]][0].
+	descriptor := controller.pendingPullIntos[0]
+
+	// 3. Remove descriptor from controller.[[pendingPullIntos]].
+	controller.pendingPullIntos = controller.pendingPullIntos[1:]
+
+	// 4. Return descriptor.
+	return descriptor
+}
+
+// respond implements the [ReadableByteStreamControllerRespond] algorithm.
+//
+// [ReadableByteStreamControllerRespond]: https://streams.spec.whatwg.org/#readable-byte-stream-controller-respond
+func (controller *ReadableByteStreamController) respond(bytesWritten int) {
+	rt := controller.stream.runtime
+
+	// 1. Assert: controller.[[pendingPullIntos]] is not empty.
+	if len(controller.pendingPullIntos) == 0 {
+		common.Throw(rt, newError(AssertionError, "there are no pending BYOB requests"))
+	}
+
+	// 2. Let firstDescriptor be controller.[[pendingPullIntos]][0].
+	firstDescriptor := controller.pendingPullIntos[0]
+
+	// 3. Let state be controller.[[stream]].[[state]].
+	state := controller.stream.state
+
+	// 4. If state is "closed",
+	if state == ReadableStreamStateClosed {
+		// 4.1. If bytesWritten is not 0, throw a TypeError exception.
+		if bytesWritten != 0 {
+			throw(rt, newTypeError(rt, "bytesWritten must be 0 when the stream is closed"))
+		}
+	} else { // 5. Otherwise,
+		// 5.2. If bytesWritten is 0, throw a TypeError exception.
+		if bytesWritten == 0 {
+			throw(rt, newTypeError(rt, "bytesWritten must be greater than 0 when the stream is readable"))
+		}
+
+		// 5.3. If firstDescriptor’s bytes filled + bytesWritten > firstDescriptor’s byte length,
+		// throw a RangeError exception.
+		if firstDescriptor.bytesFilled+bytesWritten > firstDescriptor.byteLength {
+			throw(rt, newRangeError(rt, "bytesWritten out of range"))
+		}
+	}
+
+	// 6. Set firstDescriptor’s buffer to ! TransferArrayBuffer(firstDescriptor’s buffer).
+	buffer, err := transferArrayBuffer(rt, firstDescriptor.buffer)
+	if err != nil {
+		throw(rt, err)
+	}
+	firstDescriptor.buffer = buffer
+
+	// 7. Perform ? ReadableByteStreamControllerRespondInternal(controller, bytesWritten).
+	controller.respondInternal(bytesWritten)
+}
+
+// respondWithNewView implements the [ReadableByteStreamControllerRespondWithNewView] algorithm.
+//
+// [ReadableByteStreamControllerRespondWithNewView]: https://streams.spec.whatwg.org/#readable-byte-stream-controller-respond-with-new-view
+func (controller *ReadableByteStreamController) respondWithNewView(view arrayBufferView) {
+	rt := controller.stream.runtime
+
+	// 1. Assert: controller.[[pendingPullIntos]] is not empty.
+	// 2. Assert: ! IsDetachedBuffer(view.[[ViewedArrayBuffer]]) is false.
+	if len(controller.pendingPullIntos) == 0 || view.buffer.Detached() {
+		common.Throw(rt, newError(AssertionError, "cannot respond with a new view"))
+	}
+
+	// 3. Let firstDescriptor be controller.[[pendingPullIntos]][0].
+	firstDescriptor := controller.pendingPullIntos[0]
+
+	// 4. Let state be controller.[[stream]].[[state]].
+	state := controller.stream.state
+
+	// 5. If state is "closed",
+	if state == ReadableStreamStateClosed {
+		// 5.1. If view.[[ByteLength]] is not 0, throw a TypeError exception.
+		if view.byteLength != 0 {
+			throw(rt, newTypeError(rt, "the view's length must be 0 when the stream is closed"))
+		}
+	} else { // 6. Otherwise,
+		// 6.2. If view.[[ByteLength]] is 0, throw a TypeError exception.
+		if view.byteLength == 0 {
+			throw(rt, newTypeError(rt, "the view's length must be greater than 0 when the stream is readable"))
+		}
+	}
+
+	// 7. If firstDescriptor’s byte offset + firstDescriptor’ bytes filled is not view.[[ByteOffset]],
+	// throw a RangeError exception.
+	if firstDescriptor.byteOffset+firstDescriptor.bytesFilled != view.byteOffset {
+		throw(rt, newRangeError(rt, "the region specified by the view does not match the BYOB request"))
+	}
+
+	// 8. If firstDescriptor’s buffer byte length is not view.[[ViewedArrayBuffer]].[[ByteLength]],
+	// throw a RangeError exception.
+	if firstDescriptor.bufferByteLength != len(view.buffer.Bytes()) {
+		throw(rt, newRangeError(rt, "the buffer of the view does not match the BYOB request"))
+	}
+
+	// 9. If firstDescriptor’s bytes filled + view.[[ByteLength]] > firstDescriptor’s byte length,
+	// throw a RangeError exception.
+	if firstDescriptor.bytesFilled+view.byteLength > firstDescriptor.byteLength {
+		throw(rt, newRangeError(rt, "the view's length is out of range"))
+	}
+
+	// 10. Let viewByteLength be view.[[ByteLength]].
+	viewByteLength := view.byteLength
+
+	// 11. Set firstDescriptor’s buffer to ? TransferArrayBuffer(view.[[ViewedArrayBuffer]]).
+	buffer, err := transferArrayBuffer(rt, view.buffer)
+	if err != nil {
+		throw(rt, err)
+	}
+	firstDescriptor.buffer = buffer
+
+	// 12. Perform ? ReadableByteStreamControllerRespondInternal(controller, viewByteLength).
+	controller.respondInternal(viewByteLength)
+}
+
+// respondInternal implements the [ReadableByteStreamControllerRespondInternal] algorithm.
+//
+// [ReadableByteStreamControllerRespondInternal]: https://streams.spec.whatwg.org/#readable-byte-stream-controller-respond-internal
+func (controller *ReadableByteStreamController) respondInternal(bytesWritten int) {
+	// 1. Let firstDescriptor be controller.[[pendingPullIntos]][0].
+	firstDescriptor := controller.pendingPullIntos[0]
+
+	// 3. Perform ! ReadableByteStreamControllerInvalidateBYOBRequest(controller).
+	controller.invalidateBYOBRequest()
+
+	// 4. Let state be controller.[[stream]].[[state]].
+	// 5. If state is "closed",
+	if controller.stream.state == ReadableStreamStateClosed {
+		// 5.2. Perform ! ReadableByteStreamControllerRespondInClosedState(controller, firstDescriptor).
+		controller.respondInClosedState(firstDescriptor)
+	} else { // 6. Otherwise,
+		// 6.3. Perform ? ReadableByteStreamControllerRespondInReadableState(controller, bytesWritten, firstDescriptor).
+		controller.respondInReadableState(bytesWritten, firstDescriptor)
+	}
+
+	// 7. Perform ! ReadableByteStreamControllerCallPullIfNeeded(controller).
+	controller.callPullIfNeeded()
+}
+
+// respondInClosedState implements the [ReadableByteStreamControllerRespondInClosedState] algorithm.
+//
+// [ReadableByteStreamControllerRespondInClosedState]: https://streams.spec.whatwg.org/#readable-byte-stream-controller-respond-in-closed-state
+func (controller *ReadableByteStreamController) respondInClosedState(firstDescriptor *pullIntoDescriptor) {
+	// 1. Assert: the remainder after dividing firstDescriptor’s bytes filled by firstDescriptor’s element size is 0.
+	// 2. If firstDescriptor’s reader type is "none", perform ! ReadableByteStreamControllerShiftPendingPullInto(controller).
+	if firstDescriptor.readerType == readerTypeNone {
+		controller.shiftPendingPullInto()
+	}
+
+	// 3. Let stream be controller.[[stream]].
+	stream := controller.stream
+
+	// 4. If ! ReadableStreamHasBYOBReader(stream) is true,
+	if stream.hasBYOBReader() {
+		// 4.1. While ! ReadableStreamGetNumReadIntoRequests(stream) > 0,
+		for stream.getNumReadIntoRequests() > 0 {
+			// 4.1.1. Let pullIntoDescriptor be ! ReadableByteStreamControllerShiftPendingPullInto(controller).
+			descriptor := controller.shiftPendingPullInto()
+
+			// 4.1.2. Perform ! ReadableByteStreamControllerCommitPullIntoDescriptor(stream, pullIntoDescriptor).
+			controller.commitPullIntoDescriptor(descriptor)
+		}
+	}
+}
+
+// respondInReadableState implements the [ReadableByteStreamControllerRespondInReadableState] algorithm.
+//
+// [ReadableByteStreamControllerRespondInReadableState]: https://streams.spec.whatwg.org/#readable-byte-stream-controller-respond-in-readable-state
+func (controller *ReadableByteStreamController) respondInReadableState(
+	bytesWritten int,
+	descriptor *pullIntoDescriptor,
+) {
+	// 1. Assert: pullIntoDescriptor’s bytes filled + bytesWritten ≤ pullIntoDescriptor’s byte length.
+	// 2. Perform ! ReadableByteStreamControllerFillHeadPullIntoDescriptor(controller, bytesWritten, pullIntoDescriptor).
+	controller.fillHeadPullIntoDescriptor(bytesWritten, descriptor)
+
+	// 3. If pullIntoDescriptor’s reader type is "none",
+	if descriptor.readerType == readerTypeNone {
+		// 3.1. Perform ? ReadableByteStreamControllerEnqueueDetachedPullIntoToQueue(controller, pullIntoDescriptor).
+		controller.enqueueDetachedPullIntoToQueue(descriptor)
+
+		// 3.2. Let filledPullIntos be the result of performing
+		// ! ReadableByteStreamControllerProcessPullIntoDescriptorsUsingQueue(controller).
+		// 3.3. For each filledPullInto of filledPullIntos,
+		for _, filledPullInto := range controller.processPullIntoDescriptorsUsingQueue() {
+			// 3.3.1. Perform ! ReadableByteStreamControllerCommitPullIntoDescriptor(controller.[[stream]], filledPullInto).
+			controller.commitPullIntoDescriptor(filledPullInto)
+		}
+
+		// 3.4. Return.
+		return
+	}
+
+	// 4. If pullIntoDescriptor’s bytes filled < pullIntoDescriptor’s minimum fill, return.
+	if descriptor.bytesFilled < descriptor.minimumFill {
+		return
+	}
+
+	// 5. Perform ! ReadableByteStreamControllerShiftPendingPullInto(controller).
+	controller.shiftPendingPullInto()
+
+	// 6. Let remainderSize be the remainder after dividing pullIntoDescriptor’s bytes filled
+	// by pullIntoDescriptor’s element size.
+	remainderSize := descriptor.bytesFilled % descriptor.elementSize
+
+	// 7. If remainderSize > 0,
+	if remainderSize > 0 {
+		// 7.1. Let end be pullIntoDescriptor’s byte offset + pullIntoDescriptor’s bytes filled.
+		end := descriptor.byteOffset + descriptor.bytesFilled
+
+		// 7.2. Perform ? ReadableByteStreamControllerEnqueueClonedChunkToQueue(controller,
+		// pullIntoDescriptor’s buffer, end − remainderSize, remainderSize).
+		controller.enqueueClonedChunkToQueue(descriptor.buffer, end-remainderSize, remainderSize)
+	}
+
+	// 8. Set pullIntoDescriptor’s bytes filled to pullIntoDescriptor’s bytes filled − remainderSize.
+	descriptor.bytesFilled -= remainderSize
+
+	// 9. Let filledPullIntos be the result of performing
+	// ! ReadableByteStreamControllerProcessPullIntoDescriptorsUsingQueue(controller).
+	filledPullIntos := controller.processPullIntoDescriptorsUsingQueue()
+
+	// 10. Perform ! ReadableByteStreamControllerCommitPullIntoDescriptor(controller.[[stream]], pullIntoDescriptor).
+	controller.commitPullIntoDescriptor(descriptor)
+
+	// 11. For each filledPullInto of filledPullIntos,
+	for _, filledPullInto := range filledPullIntos {
+		// 11.1. Perform ! ReadableByteStreamControllerCommitPullIntoDescriptor(controller.[[stream]], filledPullInto).
+		controller.commitPullIntoDescriptor(filledPullInto)
+	}
+}
+
+// ReadableStreamBYOBRequest represents a pull-into request in a [ReadableByteStreamController].
+//
+// For more details, see the [specification].
+//
+// [specification]: https://streams.spec.whatwg.org/#rs-byob-request-class
+type ReadableStreamBYOBRequest struct {
+	// controller is the parent ReadableByteStreamController instance, or nil
+	// once the request has been invalidated.
+	controller *ReadableByteStreamController
+
+	// view is the view for the region of the buffer the underlying source can write into,
+	// or nil once the request has been invalidated.
+	view *sobek.Object
+
+	// obj is the JS object representing the request.
+	obj *sobek.Object
+
+	runtime *sobek.Runtime
+}
+
+// NewReadableStreamBYOBRequestObject creates a new [sobek.Object] from a
+// [ReadableStreamBYOBRequest] instance.
+func NewReadableStreamBYOBRequestObject(request *ReadableStreamBYOBRequest) (*sobek.Object, error) {
+	rt := request.runtime
+	obj := rt.NewObject()
+	objName := "ReadableStreamBYOBRequest"
+
+	err := obj.DefineAccessorProperty("view", rt.ToValue(func() sobek.Value {
+		if request.view == nil {
+			return sobek.Null()
+		}
+		return request.view
+	}), nil, sobek.FLAG_FALSE, sobek.FLAG_TRUE)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := setReadOnlyPropertyOf(obj, objName, "respond", rt.ToValue(request.Respond)); err != nil {
+		return nil, err
+	}
+
+	if err := setReadOnlyPropertyOf(obj, objName, "respondWithNewView", rt.ToValue(request.RespondWithNewView)); err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+// Respond signals to the associated readable byte stream that bytesWritten bytes
+// were written into the view of the request.
+//
+// It implements the ReadableStreamBYOBRequest.respond(bytesWritten) [specification] algorithm.
+//
+// [specification]: https://streams.spec.whatwg.org/#rs-byob-request-respond
+func (request *ReadableStreamBYOBRequest) Respond(bytesWritten sobek.Value) {
+	rt := request.runtime
+
+	// 1. If this.[[controller]] is undefined, throw a TypeError exception.
+	if request.controller == nil {
+		throw(rt, newTypeError(rt, "the BYOB request has been invalidated"))
+	}
+
+	if common.IsNullish(bytesWritten) {
+		throw(rt, newTypeError(rt, "bytesWritten must be a non-negative integer"))
+	}
+	written := bytesWritten.ToFloat()
+	if written < 0 || written != float64(int64(written)) {
+		throw(rt, newTypeError(rt, "bytesWritten must be a non-negative integer"))
+	}
+
+	view, _ := toArrayBufferView(rt, request.view)
+
+	// 2. If ! IsDetachedBuffer(this.[[view]].[[ArrayBuffer]]) is true, throw a TypeError exception.
+	if view.buffer.Detached() {
+		throw(rt, newTypeError(rt, "the view's buffer is detached"))
+	}
+
+	// 5. Perform ? ReadableByteStreamControllerRespond(this.[[controller]], bytesWritten).
+	request.controller.respond(int(written))
+}
+
+// RespondWithNewView signals to the associated readable byte stream that the given view
+// was written into, instead of the view of the request.
+//
+// It implements the ReadableStreamBYOBRequest.respondWithNewView(view) [specification] algorithm.
+//
+// [specification]: https://streams.spec.whatwg.org/#rs-byob-request-respond-with-new-view
+func (request *ReadableStreamBYOBRequest) RespondWithNewView(view sobek.Value) {
+	rt := request.runtime
+
+	// 1. If this.[[controller]] is undefined, throw a TypeError exception.
+	if request.controller == nil {
+		throw(rt, newTypeError(rt, "the BYOB request has been invalidated"))
+	}
+
+	newView, ok := toArrayBufferView(rt, view)
+	if !ok {
+		throw(rt, newTypeError(rt, "view must be an ArrayBufferView"))
+	}
+
+	// 2. If ! IsDetachedBuffer(view.[[ViewedArrayBuffer]]) is true, throw a TypeError exception.
+	if newView.buffer.Detached() {
+		throw(rt, newTypeError(rt, "the view's buffer is detached"))
+	}
+
+	// 3. Return ? ReadableByteStreamControllerRespondWithNewView(this.[[controller]], view).
+	request.controller.respondWithNewView(newView)
+}
@@ -0,0 +1,151 @@
+package streams
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadableByteStream(t *testing.T) {
+	t.Parallel()
+
+	t.Run("default reader", func(t *testing.T) {
+		t.Parallel()
+
+		got := runAsyncScript(t, `async () => {
+  const rs = new ReadableStream({
+    type: "bytes",
+    start(controller) {
+      controller.enqueue(new Uint8Array([1, 2, 3]));
+      controller.close();
+    },
+  });
+  const reader = rs.getReader();
+  const { value } = await reader.read();
+  const { done } = await reader.read();
+  return [value instanceof Uint8Array, value.join(""), done].join(",");
+}`)
+		assert.Equal(t, "true,123,true", got.String())
+	})
+
+	t.Run("BYOB reader", func(t *testing.T) {
+		t.Parallel()
+
+		got := runAsyncScript(t, `async () => {
+  let pulls = 0;
+  const rs = new ReadableStream({
+    type: "bytes",
+    pull(controller) {
+      const view = controller.byobRequest.view;
+      view[0] = ++pulls;
+      view[1] = pulls;
+      controller.byobRequest.respond(2);
+      if (pulls === 2) {
+        controller.close();
+      }
+    },
+  });
+  const reader = rs.getReader({ mode: "byob" });
+  const results = [];
+  let buffer = new ArrayBuffer(4);
+  while (true) {
+    const { value, done } = await reader.read(new Uint8Array(buffer));
+    if (done) {
+      break;
+    }
+    results.push(value.join(""));
+    buffer = value.buffer;
+  }
+  return results.join(",");
+}`)
+		assert.Equal(t, "11,22", got.String())
+	})
+
+	t.Run("BYOB reader with enqueued chunks", func(t *testing.T) {
+		t.Parallel()
+
+		got := runAsyncScript(t, `async () => {
+  const rs = new ReadableStream({
+    type: "bytes",
+    start(controller) {
+      controller.enqueue(new Uint8Array([1, 2, 3, 4, 5]));
+      controller.close();
+    },
+  });
+  const reader = rs.getReader({ mode: "byob" });
+  const first = await reader.read(new Uint8Array(3));
+  const second = await reader.read(new Uint8Array(3));
+  const third = await reader.read(new Uint8Array(3));
+  return [first.value.join(""), second.value.join(""), third.done].join(",");
+}`)
+		assert.Equal(t, "123,45,true", got.String())
+	})
+
+	t.Run("autoAllocateChunkSize", func(t *testing.T) {
+		t.Parallel()
+
+		got := runAsyncScript(t, `async () => {
+  const rs = new ReadableStream({
+    type: "bytes",
+    autoAllocateChunkSize: 8,
+    pull(controller) {
+      const view = controller.byobRequest.view;
+      view[0] = 42;
+      controller.byobRequest.respond(1);
+    },
+  });
+  const { value } = await rs.getReader().read();
+  return [value.buffer.byteLength, value.byteLength, value[0]].join(",");
+}`)
+		assert.Equal(t, "8,1,42", got.String())
+	})
+
+	t.Run("BYOB reader on default stream", func(t *testing.T) {
+		t.Parallel()
+
+		got := runAsyncScript(t, `async () => {
+  try {
+    new ReadableStream().getReader({ mode: "byob" });
+  } catch (e) {
+    return e.name;
+  }
+}`)
+		assert.Equal(t, "TypeError", got.String())
+	})
+}
+
+func TestByteLengthQueuingStrategy(t *testing.T) {
+	t.Parallel()
+
+	got := runAsyncScript(t, `async () => {
+  const strategy = new ByteLengthQueuingStrategy({ highWaterMark: 16 });
+  const ws = new WritableStream({ write() { return new Promise(() => {}); } }, strategy);
+  const writer = ws.getWriter();
+  writer.write(new Uint8Array(10));
+  writer.write(new Uint8Array(10));
+  return [strategy.highWaterMark, strategy.size(new ArrayBuffer(5)), writer.desiredSize].join(",");
+}`)
+	assert.Equal(t, "16,5,-4", got.String())
+}
+
+func TestReadableByteStreamWebPlatformTests(t *testing.T) {
+	t.Parallel()
+
+	suites := []string{
+		"bad-buffers-and-views.any.js",
+		"construct-byob-request.any.js",
+		"enqueue-with-detached-buffer.any.js",
+		"general.any.js",
+		"non-transferable-buffers.any.js",
+		"respond-after-enqueue.any.js",
+		"tee.any.js",
+	}
+
+	runWebPlatformTests(t, "streams/readable-byte-streams", suites)
+}
+
+func TestQueuingStrategiesWebPlatformTests(t *testing.T) {
+	t.Parallel()
+
+	runWebPlatformTests(t, "streams", []string{"queuing-strategies.any.js"})
+}
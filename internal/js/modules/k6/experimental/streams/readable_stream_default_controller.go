@@ -471,6 +471,15 @@ func (controller *ReadableStreamDefaultController) shouldCallPull() bool {
 	return false
 }
 
+// hasBackpressure implements the [specification]'s ReadableStreamDefaultControllerHasBackpressure algorithm
+//
+// [specification]: https://streams.spec.whatwg.org/#rs-default-controller-has-backpressure
+func (controller *ReadableStreamDefaultController) hasBackpressure() bool {
+	// 1. If ! ReadableStreamDefaultControllerShouldCallPull(controller) is true, return false.
+	// 2. Otherwise, return true.
+	return !controller.shouldCallPull()
+}
+
 func (controller *ReadableStreamDefaultController) getDesiredSize() null.Float {
 	state := controller.stream.state
 
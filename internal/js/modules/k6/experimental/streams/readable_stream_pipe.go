@@ -0,0 +1,492 @@
+package streams
+
+import (
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/js/common"
+)
+
+// pipeOptions holds the options of the pipeTo and pipeThrough operations.
+//
+// [specification]: https://streams.spec.whatwg.org/#dictdef-streampipeoptions
+type pipeOptions struct {
+	preventAbort  bool
+	preventCancel bool
+	preventClose  bool
+	signal        *sobek.Object
+}
+
+// newPipeOptionsFromValue converts the given value to the options of a pipe operation.
+func newPipeOptionsFromValue(rt *sobek.Runtime, value sobek.Value) (pipeOptions, error) {
+	var options pipeOptions
+
+	if common.IsNullish(value) {
+		return options, nil
+	}
+
+	obj, ok := value.(*sobek.Object)
+	if !ok {
+		return options, newTypeError(rt, "the pipe options must be an object")
+	}
+
+	options.preventAbort = obj.Get("preventAbort") != nil && obj.Get("preventAbort").ToBoolean()
+	options.preventCancel = obj.Get("preventCancel") != nil && obj.Get("preventCancel").ToBoolean()
+	options.preventClose = obj.Get("preventClose") != nil && obj.Get("preventClose").ToBoolean()
+
+	if signal := obj.Get("signal"); !sobek.IsUndefined(signal) && signal != nil {
+		signalObj, ok := signal.(*sobek.Object)
+		if !ok {
+			return options, newTypeError(rt, "the signal option must be an AbortSignal")
+		}
+		if _, ok := sobek.AssertFunction(signalObj.Get("addEventListener")); !ok {
+			return options, newTypeError(rt, "the signal option must be an AbortSignal")
+		}
+		options.signal = signalObj
+	}
+
+	return options, nil
+}
+
+// PipeTo implements the [pipeTo] operation.
+//
+// [pipeTo]: https://streams.spec.whatwg.org/#rs-pipe-to
+func (stream *ReadableStream) PipeTo(destination sobek.Value, options sobek.Value) *sobek.Promise {
+	rt := stream.runtime
+
+	dest, ok := exportWritableStream(destination)
+	if !ok {
+		return newRejectedPromise(stream.vu, newTypeError(rt, "the destination must be a WritableStream").Err())
+	}
+
+	pipeOpts, err := newPipeOptionsFromValue(rt, options)
+	if err != nil {
+		return newRejectedPromise(stream.vu, rejectionReason(err))
+	}
+
+	// 1. If ! IsReadableStreamLocked(this) is true, return a promise rejected with a TypeError exception.
+	if stream.isLocked() {
+		return newRejectedPromise(stream.vu, newTypeError(rt, "cannot pipe a locked stream").Err())
+	}
+
+	// 2. If ! IsWritableStreamLocked(destination) is true, return a promise rejected with a TypeError exception.
+	if dest.isLocked() {
+		return newRejectedPromise(stream.vu, newTypeError(rt, "cannot pipe to a locked stream").Err())
+	}
+
+	// 3. Let signal be options["signal"] if it exists, or undefined otherwise.
+	// 4. Return ! ReadableStreamPipeTo(this, destination, options["preventClose"], options["preventAbort"],
+	// options["preventCancel"], signal).
+	return stream.pipeTo(dest, pipeOpts)
+}
+
+// PipeThrough implements the [pipeThrough] operation.
+//
+// [pipeThrough]: https://streams.spec.whatwg.org/#rs-pipe-through
+func (stream *ReadableStream) PipeThrough(transform sobek.Value, options sobek.Value) sobek.Value {
+	rt := stream.runtime
+
+	transformObj, ok := transform.(*sobek.Object)
+	if !ok {
+		throw(rt, newTypeError(rt, "the transform stream must be an object"))
+	}
+
+	readable := transformObj.Get("readable")
+	if _, ok := exportReadableStream(readable); !ok {
+		throw(rt, newTypeError(rt, "the readable side of the transform stream must be a ReadableStream"))
+	}
+
+	writable, ok := exportWritableStream(transformObj.Get("writable"))
+	if !ok {
+		throw(rt, newTypeError(rt, "the writable side of the transform stream must be a WritableStream"))
+	}
+
+	pipeOpts, err := newPipeOptionsFromValue(rt, options)
+	if err != nil {
+		throw(rt, err)
+	}
+
+	// 1. If ! IsReadableStreamLocked(this) is true, throw a TypeError exception.
+	if stream.isLocked() {
+		throw(rt, newTypeError(rt, "cannot pipe a locked stream"))
+	}
+
+	// 2. If ! IsWritableStreamLocked(transform["writable"]) is true, throw a TypeError exception.
+	if writable.isLocked() {
+		throw(rt, newTypeError(rt, "cannot pipe to a locked stream"))
+	}
+
+	// 3. Let signal be options["signal"] if it exists, or undefined otherwise.
+	// 4. Let promise be ! ReadableStreamPipeTo(this, transform["writable"], options["preventClose"],
+	// options["preventAbort"], options["preventCancel"], signal).
+	promise := stream.pipeTo(writable, pipeOpts)
+
+	// 5. Set promise.[[PromiseIsHandled]] to true.
+	setPromiseIsHandled(rt, promise)
+
+	// 6. Return transform["readable"].
+	return readable
+}
+
+// exportReadableStream returns the [ReadableStream] represented by the given value, if any.
+func exportReadableStream(value sobek.Value) (*ReadableStream, bool) {
+	if common.IsNullish(value) {
+		return nil, false
+	}
+
+	stream, ok := value.Export().(*ReadableStream)
+	return stream, ok
+}
+
+// exportWritableStream returns the [WritableStream] represented by the given value, if any.
+func exportWritableStream(value sobek.Value) (*WritableStream, bool) {
+	if common.IsNullish(value) {
+		return nil, false
+	}
+
+	stream, ok := value.Export().(*WritableStream)
+	return stream, ok
+}
+
+// pipeTo implements the specification's [ReadableStreamPipeTo] abstract operation.
+//
+// [ReadableStreamPipeTo]: https://streams.spec.whatwg.org/#readable-stream-pipe-to
+func (stream *ReadableStream) pipeTo(dest *WritableStream, options pipeOptions) *sobek.Promise {
+	rt := stream.runtime
+	source := stream
+
+	// 1-5. Assert: source implements ReadableStream, dest implements WritableStream, and
+	// preventClose, preventAbort, and preventCancel are all booleans.
+	// 6. If signal was not given, let signal be undefined.
+	signal := options.signal
+
+	// 7. Assert: either signal is undefined, or signal implements AbortSignal.
+	// 8. Assert: ! IsReadableStreamLocked(source) is false.
+	// 9. Assert: ! IsWritableStreamLocked(dest) is false.
+	// 10. If source.[[controller]] implements ReadableByteStreamController, let reader be either
+	// ! AcquireReadableStreamBYOBReader(source) or ! AcquireReadableStreamDefaultReader(source),
+	// at the user agent’s discretion.
+	// 11. Otherwise, let reader be ! AcquireReadableStreamDefaultReader(source).
+	reader := source.acquireDefaultReader()
+
+	// 12. Let writer be ! AcquireWritableStreamDefaultWriter(dest).
+	writer := dest.acquireDefaultWriter()
+
+	// 13. Set source.[[disturbed]] to true.
+	source.disturbed = true
+
+	// 14. Let shuttingDown be false.
+	shuttingDown := false
+
+	// 15. Let promise be a new promise.
+	promise := newPromiseCapability(rt)
+
+	// currentWrite is the promise of the last write to dest, the shutdown waits for.
+	currentWrite := newResolvedPromise(stream.vu, sobek.Undefined())
+
+	var abortListener sobek.Value
+
+	// finalize performs the finalize steps, given an optional error.
+	finalize := func(isError bool, e sobek.Value) {
+		// 1. Perform ! WritableStreamDefaultWriterRelease(writer).
+		writer.release()
+
+		// 2. If reader implements ReadableStreamBYOBReader, perform ! ReadableStreamBYOBReaderRelease(reader).
+		// 3. Otherwise, perform ! ReadableStreamDefaultReaderRelease(reader).
+		reader.release()
+
+		// 4. If signal is not undefined, remove abortAlgorithm from signal.
+		if signal != nil && abortListener != nil {
+			if removeEventListener, ok := sobek.AssertFunction(signal.Get("removeEventListener")); ok {
+				if _, err := removeEventListener(signal, rt.ToValue("abort"), abortListener); err != nil {
+					common.Throw(rt, err)
+				}
+			}
+		}
+
+		// 5. If error was given, reject promise with error.
+		// 6. Otherwise, resolve promise with undefined.
+		if isError {
+			promise.reject(e)
+		} else {
+			promise.resolve(sobek.Undefined())
+		}
+	}
+
+	// waitForWritesToFinish waits for the last write of the pipe, including the ones performed
+	// while waiting, to finish before running the given steps.
+	var waitForWritesToFinish func(then func())
+	waitForWritesToFinish = func(then func()) {
+		oldCurrentWrite := currentWrite
+		uponPromise(rt, currentWrite, func(sobek.Value) {
+			if oldCurrentWrite != currentWrite {
+				waitForWritesToFinish(then)
+				return
+			}
+			then()
+		}, nil)
+	}
+
+	// shutdownWithAction shuts down the pipe with an action, and an optional original error.
+	shutdownWithAction := func(action func() *sobek.Promise, originalIsError bool, originalError sobek.Value) {
+		// 1. If shuttingDown is true, abort these substeps.
+		if shuttingDown {
+			return
+		}
+
+		// 2. Set shuttingDown to true.
+		shuttingDown = true
+
+		// 4. Let p be the result of performing action.
+		// 5. Upon fulfillment of p, finalize, passing along originalError if it was given.
+		// 6. Upon rejection of p with reason newError, finalize with newError.
+		doTheRest := func() {
+			uponPromise(rt, action(),
+				func(sobek.Value) { finalize(originalIsError, originalError) },
+				func(newError sobek.Value) { finalize(true, newError) },
+			)
+		}
+
+		// 3. If dest.[[state]] is "writable" and ! WritableStreamCloseQueuedOrInFlight(dest) is false,
+		// 3.1. If any chunks have been read but not yet written, write them to dest.
+		// 3.2. Wait until every chunk that has been read has been written (i.e. the corresponding promises
+		// have settled).
+		if dest.state == WritableStreamStateWritable && !dest.closeQueuedOrInFlight() {
+			waitForWritesToFinish(doTheRest)
+			return
+		}
+
+		doTheRest()
+	}
+
+	// shutdown shuts down the pipe without any action, and with an optional error.
+	shutdown := func(isError bool, e sobek.Value) {
+		// 1. If shuttingDown is true, abort these substeps.
+		if shuttingDown {
+			return
+		}
+
+		// 2. Set shuttingDown to true.
+		shuttingDown = true
+
+		// 3. If dest.[[state]] is "writable" and ! WritableStreamCloseQueuedOrInFlight(dest) is false,
+		// wait until every chunk that has been read has been written.
+		// 4. Finalize, passing along error if it was given.
+		if dest.state == WritableStreamStateWritable && !dest.closeQueuedOrInFlight() {
+			waitForWritesToFinish(func() { finalize(isError, e) })
+			return
+		}
+
+		finalize(isError, e)
+	}
+
+	// 16. If signal is not undefined,
+	if signal != nil {
+		// 16.1. Let abortAlgorithm be the following steps:
+		abortAlgorithm := func() {
+			// 16.1.1. Let error be signal’s abort reason.
+			e := signal.Get("reason")
+			if e == nil {
+				e = sobek.Undefined()
+			}
+
+			// 16.1.2. Let actions be an empty ordered set.
+			var actions []func() *sobek.Promise
+
+			// 16.1.3. If preventAbort is false, append the following action to actions:
+			if !options.preventAbort {
+				actions = append(actions, func() *sobek.Promise {
+					// 16.1.3.1. If dest.[[state]] is "writable", return ! WritableStreamAbort(dest, error).
+					if dest.state == WritableStreamStateWritable {
+						return dest.abort(e)
+					}
+					// 16.1.3.2. Otherwise, return a promise resolved with undefined.
+					return newResolvedPromise(stream.vu, sobek.Undefined())
+				})
+			}
+
+			// 16.1.4. If preventCancel is false, append the following action to actions:
+			if !options.preventCancel {
+				actions = append(actions, func() *sobek.Promise {
+					// 16.1.4.1. If source.[[state]] is "readable", return ! ReadableStreamCancel(source, error).
+					if source.state == ReadableStreamStateReadable {
+						return source.cancel(e)
+					}
+					// 16.1.4.2. Otherwise, return a promise resolved with undefined.
+					return newResolvedPromise(stream.vu, sobek.Undefined())
+				})
+			}
+
+			// 16.1.5. Shutdown with an action consisting of getting a promise to wait for all of the actions
+			// in actions, and with error.
+			shutdownWithAction(func() *sobek.Promise {
+				return waitForAllPromises(rt, actions)
+			}, true, e)
+		}
+
+		// 16.2. If signal is aborted, perform abortAlgorithm and return promise.
+		if signal.Get("aborted") != nil && signal.Get("aborted").ToBoolean() {
+			abortAlgorithm()
+			return promise.promise
+		}
+
+		// 16.3. Add abortAlgorithm to signal.
+		abortListener = rt.ToValue(func(sobek.FunctionCall) sobek.Value {
+			abortAlgorithm()
+			return sobek.Undefined()
+		})
+		addEventListener, _ := sobek.AssertFunction(signal.Get("addEventListener"))
+		if _, err := addEventListener(signal, rt.ToValue("abort"), abortListener); err != nil {
+			common.Throw(rt, err)
+		}
+	}
+
+	// 17. In parallel but not really; see #905, using reader and writer, read all chunks from source
+	// and write them to dest. Due to the locking provided by the reader and writer, the exact manner
+	// in which this happens is not observable to author code, and so there is flexibility in how this
+	// is done.
+	var pipeStep func()
+	pipeStep = func() {
+		if shuttingDown {
+			return
+		}
+
+		// Backpressure must be enforced, so the chunks are only read once dest is ready for them.
+		uponPromise(rt, writer.readyPromise.promise, func(sobek.Value) {
+			if shuttingDown {
+				return
+			}
+
+			reader.read(ReadRequest{
+				chunkSteps: func(chunk any) {
+					currentWrite = writer.write(rt.ToValue(chunk))
+					setPromiseIsHandled(rt, currentWrite)
+
+					// The next chunk is read once the current read request is done.
+					uponPromise(rt, newResolvedPromise(stream.vu, sobek.Undefined()), func(sobek.Value) {
+						pipeStep()
+					}, nil)
+				},
+				// The closing and the errors are handled by the reactions on the closed promises.
+				closeSteps: func() {},
+				errorSteps: func(any) {},
+			})
+		}, nil)
+	}
+
+	// 18. Errors must be propagated forward: if source.[[state]] is or becomes "errored", then
+	isOrBecomesErrored(rt, source.state == ReadableStreamStateErrored, source.storedError, reader.closedPromise,
+		func(storedError sobek.Value) {
+			if !options.preventAbort {
+				// 18.1. If preventAbort is false, shutdown with an action of ! WritableStreamAbort(dest,
+				// source.[[storedError]]) and with source.[[storedError]].
+				shutdownWithAction(func() *sobek.Promise { return dest.abort(storedError) }, true, storedError)
+			} else {
+				// 18.2. Otherwise, shutdown with source.[[storedError]].
+				shutdown(true, storedError)
+			}
+		})
+
+	// 19. Errors must be propagated backward: if dest.[[state]] is or becomes "errored", then
+	isOrBecomesErrored(rt, dest.state == WritableStreamStateErrored, dest.storedError, writer.closedPromise.promise,
+		func(storedError sobek.Value) {
+			if !options.preventCancel {
+				// 19.1. If preventCancel is false, shutdown with an action of ! ReadableStreamCancel(source,
+				// dest.[[storedError]]) and with dest.[[storedError]].
+				shutdownWithAction(func() *sobek.Promise { return source.cancel(storedError) }, true, storedError)
+			} else {
+				// 19.2. Otherwise, shutdown with dest.[[storedError]].
+				shutdown(true, storedError)
+			}
+		})
+
+	// 20. Closing must be propagated forward: if source.[[state]] is or becomes "closed", then
+	isOrBecomesClosed(rt, source.state == ReadableStreamStateClosed, reader.closedPromise, func() {
+		if !options.preventClose {
+			// 20.1. If preventClose is false, shutdown with an action of
+			// ! WritableStreamDefaultWriterCloseWithErrorPropagation(writer).
+			shutdownWithAction(writer.closeWithErrorPropagation, false, nil)
+		} else {
+			// 20.2. Otherwise, shutdown.
+			shutdown(false, nil)
+		}
+	})
+
+	// 21. Closing must be propagated backward: if ! WritableStreamCloseQueuedOrInFlight(dest) is true
+	// or dest.[[state]] is "closed", then
+	if dest.closeQueuedOrInFlight() || dest.state == WritableStreamStateClosed {
+		// 21.1. Assert: no chunks have been read or written.
+		// 21.2. Let destClosed be a new TypeError.
+		destClosed := newTypeError(rt, "the destination stream is closed").Err()
+
+		if !options.preventCancel {
+			// 21.3. If preventCancel is false, shutdown with an action of ! ReadableStreamCancel(source,
+			// destClosed) and with destClosed.
+			shutdownWithAction(func() *sobek.Promise { return source.cancel(destClosed) }, true, destClosed)
+		} else {
+			// 21.4. Otherwise, shutdown with destClosed.
+			shutdown(true, destClosed)
+		}
+	}
+
+	pipeStep()
+
+	// 22. Return promise.
+	return promise.promise
+}
+
+// isOrBecomesErrored runs the given action with the stored error of a stream, immediately if the
+// stream is errored, or once the given closed promise of its reader or writer is rejected.
+func isOrBecomesErrored(
+	rt *sobek.Runtime,
+	errored bool,
+	storedError any,
+	closedPromise *sobek.Promise,
+	action func(storedError sobek.Value),
+) {
+	if errored {
+		action(rt.ToValue(rejectionReason(storedError)))
+		return
+	}
+
+	uponPromise(rt, closedPromise, nil, action)
+}
+
+// isOrBecomesClosed runs the given action immediately if a stream is closed, or once the given
+// closed promise of its reader is fulfilled.
+func isOrBecomesClosed(rt *sobek.Runtime, closed bool, closedPromise *sobek.Promise, action func()) {
+	if closed {
+		action()
+		return
+	}
+
+	uponPromise(rt, closedPromise, func(sobek.Value) { action() }, nil)
+}
+
+// waitForAllPromises implements the specification's [wait for all] algorithm, on the promises
+// returned by the given actions, and returns a promise resolved once all of them are fulfilled,
+// or rejected as soon as one of them is rejected.
+//
+// [wait for all]: https://webidl.spec.whatwg.org/#wait-for-all
+func waitForAllPromises(rt *sobek.Runtime, actions []func() *sobek.Promise) *sobek.Promise {
+	promise := newPromiseCapability(rt)
+
+	remaining := len(actions)
+	if remaining == 0 {
+		promise.resolve(sobek.Undefined())
+		return promise.promise
+	}
+
+	for _, action := range actions {
+		uponPromise(rt, action(),
+			func(sobek.Value) {
+				remaining--
+				if remaining == 0 {
+					promise.resolve(sobek.Undefined())
+				}
+			},
+			func(r sobek.Value) { promise.reject(r) },
+		)
+	}
+
+	return promise.promise
+}
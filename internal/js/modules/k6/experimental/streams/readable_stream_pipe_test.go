@@ -0,0 +1,210 @@
+package streams
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadableStreamPipe(t *testing.T) {
+	t.Parallel()
+
+	t.Run("pipeThrough and pipeTo", func(t *testing.T) {
+		t.Parallel()
+
+		got := runAsyncScript(t, `async () => {
+  const chunks = [];
+  let closed = false;
+  const rs = new ReadableStream({
+    start(controller) {
+      ["a", "b", "c"].forEach((chunk) => controller.enqueue(chunk));
+      controller.close();
+    },
+  });
+  await rs
+    .pipeThrough(new TransformStream({
+      transform(chunk, controller) { controller.enqueue(chunk.toUpperCase()); },
+    }))
+    .pipeTo(new WritableStream({
+      write(chunk) { chunks.push(chunk); },
+      close() { closed = true; },
+    }));
+  return chunks.join("") + "," + closed + "," + rs.locked;
+}`)
+		assert.Equal(t, "ABC,true,false", got.String())
+	})
+
+	t.Run("preventClose", func(t *testing.T) {
+		t.Parallel()
+
+		got := runAsyncScript(t, `async () => {
+  const rs = new ReadableStream({ start(controller) { controller.close(); } });
+  const ws = new WritableStream();
+  await rs.pipeTo(ws, { preventClose: true });
+  const writer = ws.getWriter();
+  await writer.write("a");
+  return "writable";
+}`)
+		assert.Equal(t, "writable", got.String())
+	})
+
+	t.Run("errors propagate forward", func(t *testing.T) {
+		t.Parallel()
+
+		got := runAsyncScript(t, `async () => {
+  let abortReason;
+  const rs = new ReadableStream({ start(controller) { controller.error("boom"); } });
+  const ws = new WritableStream({ abort(reason) { abortReason = reason; } });
+  try {
+    await rs.pipeTo(ws);
+  } catch (e) {
+    return e + "," + abortReason;
+  }
+}`)
+		assert.Equal(t, "boom,boom", got.String())
+	})
+
+	t.Run("errors propagate backward", func(t *testing.T) {
+		t.Parallel()
+
+		got := runAsyncScript(t, `async () => {
+  let cancelReason;
+  const rs = new ReadableStream({
+    pull(controller) { controller.enqueue("a"); },
+    cancel(reason) { cancelReason = reason.message; },
+  });
+  const ws = new WritableStream({ write() { throw new Error("boom"); } });
+  try {
+    await rs.pipeTo(ws);
+  } catch (e) {
+    return e.message + "," + cancelReason;
+  }
+}`)
+		assert.Equal(t, "boom,boom", got.String())
+	})
+
+	t.Run("abort signal", func(t *testing.T) {
+		t.Parallel()
+
+		got := runAsyncScript(t, `async () => {
+  let cancelReason, abortReason;
+  const rs = new ReadableStream({
+    pull(controller) { controller.enqueue("a"); },
+    cancel(reason) { cancelReason = reason; },
+  });
+  const ws = new WritableStream({ abort(reason) { abortReason = reason; } });
+  const controller = new AbortController();
+  const piping = rs.pipeTo(ws, { signal: controller.signal });
+  controller.abort("stop");
+  try {
+    await piping;
+  } catch (e) {
+    return [e, cancelReason, abortReason].join(",");
+  }
+}`)
+		assert.Equal(t, "stop,stop,stop", got.String())
+	})
+
+	t.Run("locked streams", func(t *testing.T) {
+		t.Parallel()
+
+		got := runAsyncScript(t, `async () => {
+  const rs = new ReadableStream();
+  const ws = new WritableStream();
+  ws.getWriter();
+  try {
+    await rs.pipeTo(ws);
+  } catch (e) {
+    return e.name + "," + rs.locked;
+  }
+}`)
+		assert.Equal(t, "TypeError,false", got.String())
+	})
+}
+
+func TestReadableStreamTee(t *testing.T) {
+	t.Parallel()
+
+	t.Run("default stream", func(t *testing.T) {
+		t.Parallel()
+
+		got := runAsyncScript(t, `async () => {
+  const rs = new ReadableStream({
+    start(controller) {
+      controller.enqueue("a");
+      controller.enqueue("b");
+      controller.close();
+    },
+  });
+  const [branch1, branch2] = rs.tee();
+  const read = async (stream) => {
+    const reader = stream.getReader();
+    const chunks = [];
+    while (true) {
+      const { value, done } = await reader.read();
+      if (done) {
+        return chunks.join("");
+      }
+      chunks.push(value);
+    }
+  };
+  return [rs.locked, await read(branch1), await read(branch2)].join(",");
+}`)
+		assert.Equal(t, "true,ab,ab", got.String())
+	})
+
+	t.Run("byte stream", func(t *testing.T) {
+		t.Parallel()
+
+		got := runAsyncScript(t, `async () => {
+  const rs = new ReadableStream({
+    type: "bytes",
+    start(controller) {
+      controller.enqueue(new Uint8Array([1, 2, 3]));
+      controller.close();
+    },
+  });
+  const [branch1, branch2] = rs.tee();
+  const { value: chunk1 } = await branch1.getReader().read();
+  const { value: chunk2 } = await branch2.getReader().read();
+  chunk1[0] = 42;
+  return [chunk1.join(""), chunk2.join(""), chunk1.buffer !== chunk2.buffer].join(",");
+}`)
+		assert.Equal(t, "4223,123,true", got.String())
+	})
+
+	t.Run("cancel both branches", func(t *testing.T) {
+		t.Parallel()
+
+		got := runAsyncScript(t, `async () => {
+  let cancelReason;
+  const rs = new ReadableStream({ cancel(reason) { cancelReason = reason; } });
+  const [branch1, branch2] = rs.tee();
+  branch1.cancel("one");
+  await branch2.cancel("two");
+  return cancelReason.join(",");
+}`)
+		assert.Equal(t, "one,two", got.String())
+	})
+}
+
+func TestPipingWebPlatformTests(t *testing.T) {
+	t.Parallel()
+
+	suites := []string{
+		"abort.any.js",
+		"close-propagation-backward.any.js",
+		"close-propagation-forward.any.js",
+		"error-propagation-backward.any.js",
+		"error-propagation-forward.any.js",
+		"flow-control.any.js",
+		"general.any.js",
+		"multiple-propagation.any.js",
+		"pipe-through.any.js",
+		"then-interception.any.js",
+		"throwing-options.any.js",
+		"transform-streams.any.js",
+	}
+
+	runWebPlatformTests(t, "streams/piping", suites)
+}
@@ -124,7 +124,10 @@ func (reader *BaseReadableStreamReader) release() {
 	}
 
 	var streamReader *BaseReadableStreamReader
-	if v, ok := stream.reader.(*ReadableStreamDefaultReader); ok {
+	switch v := stream.reader.(type) {
+	case *ReadableStreamDefaultReader:
+		streamReader = &v.BaseReadableStreamReader
+	case *ReadableStreamBYOBReader:
 		streamReader = &v.BaseReadableStreamReader
 	}
 
@@ -0,0 +1,248 @@
+package streams
+
+import (
+	"github.com/grafana/sobek"
+)
+
+// tee implements the specification's [ReadableStreamTee] abstract operation.
+//
+// Byte streams are teed with the default algorithm, cloning the chunks handed to the second
+// branch, so both branches are default readable streams.
+//
+// [ReadableStreamTee]: https://streams.spec.whatwg.org/#readable-stream-tee
+func (stream *ReadableStream) tee(cloneForBranch2 bool) [2]*ReadableStream {
+	// 1. Assert: stream implements ReadableStream.
+	// 2. Assert: cloneForBranch2 is a boolean.
+	// 3. If stream.[[controller]] implements ReadableByteStreamController, return ? ReadableByteStreamTee(stream).
+	if _, isByteStream := stream.controller.(*ReadableByteStreamController); isByteStream {
+		return stream.defaultTee(true)
+	}
+
+	// 4. Return ? ReadableStreamDefaultTee(stream, cloneForBranch2).
+	return stream.defaultTee(cloneForBranch2)
+}
+
+// defaultTee implements the specification's [ReadableStreamDefaultTee] abstract operation.
+//
+// [ReadableStreamDefaultTee]: https://streams.spec.whatwg.org/#abstract-opdef-readablestreamdefaulttee
+//
+//nolint:funlen
+func (stream *ReadableStream) defaultTee(cloneForBranch2 bool) [2]*ReadableStream {
+	rt := stream.runtime
+
+	// 1. Assert: stream implements ReadableStream.
+	// 2. Assert: cloneForBranch2 is a boolean.
+	// 3. Let reader be ? AcquireReadableStreamDefaultReader(stream).
+	reader := stream.acquireDefaultReader()
+
+	// 4. Let reading be false.
+	reading := false
+
+	// 5. Let readAgain be false.
+	readAgain := false
+
+	// 6. Let canceled1 be false.
+	// 7. Let canceled2 be false.
+	canceled := [2]bool{}
+
+	// 8. Let reason1 be undefined.
+	// 9. Let reason2 be undefined.
+	reasons := [2]sobek.Value{sobek.Undefined(), sobek.Undefined()}
+
+	// 10. Let branch1 be undefined.
+	// 11. Let branch2 be undefined.
+	var branches [2]*ReadableStream
+
+	// 12. Let cancelPromise be a new promise.
+	cancelPromise := newPromiseCapability(rt)
+
+	branchController := func(i int) *ReadableStreamDefaultController {
+		controller, ok := branches[i].controller.(*ReadableStreamDefaultController)
+		if !ok {
+			throw(rt, newError(AssertionError, "the controller of a tee branch is not a ReadableStreamDefaultController"))
+		}
+		return controller
+	}
+
+	// 13. Let pullAlgorithm be the following steps:
+	var pullAlgorithm func(*sobek.Object) *sobek.Promise
+	pullAlgorithm = func(*sobek.Object) *sobek.Promise {
+		// 13.1. If reading is true,
+		if reading {
+			// 13.1.1. Set readAgain to true.
+			readAgain = true
+
+			// 13.1.2. Return a promise resolved with undefined.
+			return newResolvedPromise(stream.vu, sobek.Undefined())
+		}
+
+		// 13.2. Set reading to true.
+		reading = true
+
+		// 13.3. Let readRequest be a read request with the following items:
+		readRequest := ReadRequest{
+			chunkSteps: func(chunk any) {
+				// 13.3.1. Queue a microtask to perform the following steps:
+				uponPromise(rt, newResolvedPromise(stream.vu, sobek.Undefined()), func(sobek.Value) {
+					// 13.3.1.1. Set readAgain to false.
+					readAgain = false
+
+					// 13.3.1.2. Let chunk1 and chunk2 be chunk.
+					chunk1 := rt.ToValue(chunk)
+					chunk2 := chunk1
+
+					// 13.3.1.3. If canceled2 is false and cloneForBranch2 is true,
+					if !canceled[1] && cloneForBranch2 {
+						// 13.3.1.3.1. Let cloneResult be StructuredClone(chunk2).
+						cloneResult, err := cloneChunk(rt, chunk2)
+
+						// 13.3.1.3.2. If cloneResult is an abrupt completion,
+						if err != nil {
+							// 13.3.1.3.2.1. Perform ! ReadableStreamDefaultControllerError(branch1.[[controller]],
+							// cloneResult.[[Value]]).
+							branchController(0).error(err)
+
+							// 13.3.1.3.2.2. Perform ! ReadableStreamDefaultControllerError(branch2.[[controller]],
+							// cloneResult.[[Value]]).
+							branchController(1).error(err)
+
+							// 13.3.1.3.2.3. Resolve cancelPromise with ! ReadableStreamCancel(stream, cloneResult.[[Value]]).
+							cancelPromise.resolve(stream.cancel(rt.ToValue(rejectionReason(err))))
+
+							// 13.3.1.3.2.4. Return.
+							return
+						}
+
+						// 13.3.1.3.3. Otherwise, set chunk2 to cloneResult.[[Value]].
+						chunk2 = cloneResult
+					}
+
+					// 13.3.1.4. If canceled1 is false, perform ! ReadableStreamDefaultControllerEnqueue(
+					// branch1.[[controller]], chunk1).
+					// 13.3.1.5. If canceled2 is false, perform ! ReadableStreamDefaultControllerEnqueue(
+					// branch2.[[controller]], chunk2).
+					for i, c := range [2]sobek.Value{chunk1, chunk2} {
+						if canceled[i] {
+							continue
+						}
+						if err := branchController(i).enqueue(c); err != nil {
+							throw(rt, err)
+						}
+					}
+
+					// 13.3.1.6. Set reading to false.
+					reading = false
+
+					// 13.3.1.7. If readAgain is true, perform pullAlgorithm.
+					if readAgain {
+						pullAlgorithm(nil)
+					}
+				}, nil)
+			},
+			closeSteps: func() {
+				// 13.3.1. Set reading to false.
+				reading = false
+
+				// 13.3.2. If canceled1 is false, perform ! ReadableStreamDefaultControllerClose(branch1.[[controller]]).
+				// 13.3.3. If canceled2 is false, perform ! ReadableStreamDefaultControllerClose(branch2.[[controller]]).
+				for i := range branches {
+					if !canceled[i] {
+						branchController(i).close()
+					}
+				}
+
+				// 13.3.4. If canceled1 is false or canceled2 is false, resolve cancelPromise with undefined.
+				if !canceled[0] || !canceled[1] {
+					cancelPromise.resolve(sobek.Undefined())
+				}
+			},
+			errorSteps: func(any) {
+				// 13.3.1. Set reading to false.
+				reading = false
+			},
+		}
+
+		// 13.4. Perform ! ReadableStreamDefaultReaderRead(reader, readRequest).
+		reader.read(readRequest)
+
+		// 13.5. Return a promise resolved with undefined.
+		return newResolvedPromise(stream.vu, sobek.Undefined())
+	}
+
+	// 14. Let cancel1Algorithm be the following steps, taking a reason argument:
+	// 15. Let cancel2Algorithm be the following steps, taking a reason argument:
+	cancelAlgorithm := func(i int) UnderlyingSourceCancelCallback {
+		return func(reason any) sobek.Value {
+			// 14.1. Set canceled1 to true.
+			canceled[i] = true
+
+			// 14.2. Set reason1 to reason.
+			reasons[i] = rt.ToValue(reason)
+
+			// 14.3. If canceled2 is true,
+			if canceled[1-i] {
+				// 14.3.1. Let compositeReason be ! CreateArrayFromList(« reason1, reason2 »).
+				compositeReason := rt.NewArray(reasons[0], reasons[1])
+
+				// 14.3.2. Let cancelResult be ! ReadableStreamCancel(stream, compositeReason).
+				cancelResult := stream.cancel(compositeReason)
+
+				// 14.3.3. Resolve cancelPromise with cancelResult.
+				cancelPromise.resolve(cancelResult)
+			}
+
+			// 14.4. Return cancelPromise.
+			return rt.ToValue(cancelPromise.promise)
+		}
+	}
+
+	// 16. Let startAlgorithm be an algorithm that returns undefined.
+	startAlgorithm := func(*sobek.Object) sobek.Value {
+		return sobek.Undefined()
+	}
+
+	// 17. Set branch1 to ! CreateReadableStream(startAlgorithm, pullAlgorithm, cancel1Algorithm).
+	// 18. Set branch2 to ! CreateReadableStream(startAlgorithm, pullAlgorithm, cancel2Algorithm).
+	proto := stream.obj.Prototype()
+	for i := range branches {
+		branches[i] = createReadableStream(stream.vu, proto, startAlgorithm, pullAlgorithm, cancelAlgorithm(i), 1, nil)
+	}
+
+	// 19. Upon rejection of reader.[[closedPromise]] with reason r,
+	uponPromise(rt, reader.closedPromise, nil, func(r sobek.Value) {
+		// 19.1. Perform ! ReadableStreamDefaultControllerError(branch1.[[controller]], r).
+		branchController(0).error(r)
+
+		// 19.2. Perform ! ReadableStreamDefaultControllerError(branch2.[[controller]], r).
+		branchController(1).error(r)
+
+		// 19.3. If canceled1 is false or canceled2 is false, resolve cancelPromise with undefined.
+		if !canceled[0] || !canceled[1] {
+			cancelPromise.resolve(sobek.Undefined())
+		}
+	})
+
+	// 20. Return « branch1, branch2 ».
+	return branches
+}
+
+// cloneChunk returns a copy of the given chunk, for the chunks of byte streams,
+// which are array buffer views, or the chunk itself otherwise.
+func cloneChunk(rt *sobek.Runtime, chunk sobek.Value) (sobek.Value, error) {
+	view, ok := toArrayBufferView(rt, chunk)
+	if !ok {
+		return chunk, nil
+	}
+
+	buffer, err := cloneArrayBuffer(rt, view.buffer, view.byteOffset, view.byteLength)
+	if err != nil {
+		return nil, err
+	}
+
+	clone, err := newUint8Array(rt, buffer, 0, view.byteLength)
+	if err != nil {
+		return nil, err
+	}
+
+	return clone, nil
+}
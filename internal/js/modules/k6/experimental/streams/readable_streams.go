@@ -38,6 +38,9 @@ type ReadableStream struct {
 
 	Source *sobek.Object
 
+	// obj is the JS object representing the stream.
+	obj *sobek.Object
+
 	runtime *sobek.Runtime
 	vu      modules.VU
 }
@@ -81,16 +84,28 @@ func (stream *ReadableStream) GetReader(options *sobek.Object) sobek.Value {
 	}
 
 	// 3. Return ? AcquireReadableStreamBYOBReader(this).
-	common.Throw(stream.runtime, newError(NotSupportedError, "'byob' mode is not supported yet"))
-	return sobek.Undefined()
+	byobReader := stream.acquireBYOBReader()
+	byobReaderObj, err := NewReadableStreamBYOBReaderObject(byobReader)
+	if err != nil {
+		common.Throw(stream.runtime, err)
+	}
+
+	return byobReaderObj
+}
+
+// Disturbed returns whether the stream has been read from or canceled.
+func (stream *ReadableStream) Disturbed() bool {
+	return stream.disturbed
 }
 
 // Tee implements the [tee] operation.
 //
 // [tee]: https://streams.spec.whatwg.org/#rs-tee
 func (stream *ReadableStream) Tee() sobek.Value {
-	common.Throw(stream.runtime, newError(NotSupportedError, "'tee()' is not supported yet"))
-	return sobek.Undefined()
+	// 1. Return ? ReadableStreamTee(this, false).
+	branches := stream.tee(false)
+
+	return stream.runtime.NewArray(branches[0].obj, branches[1].obj)
 }
 
 // ReadableStreamState represents the current state of a ReadableStream
@@ -351,6 +366,160 @@ func (stream *ReadableStream) setupDefaultController(
 	}
 }
 
+// setupReadableByteStreamControllerFromUnderlyingSource implements the [specification]'s
+// SetUpReadableByteStreamControllerFromUnderlyingSource abstract operation.
+//
+// [specification]: https://streams.spec.whatwg.org/#set-up-readable-byte-stream-controller-from-underlying-source
+func (stream *ReadableStream) setupReadableByteStreamControllerFromUnderlyingSource(
+	underlyingSource *sobek.Object,
+	underlyingSourceDict UnderlyingSource,
+	highWaterMark float64,
+) {
+	rt := stream.runtime
+
+	// 1. Let controller be a new ReadableByteStreamController.
+	controller := &ReadableByteStreamController{}
+
+	// 2. Let startAlgorithm be an algorithm that returns undefined.
+	var startAlgorithm UnderlyingSourceStartCallback = func(*sobek.Object) sobek.Value {
+		return sobek.Undefined()
+	}
+
+	// 3. Let pullAlgorithm be an algorithm that returns a promise resolved with undefined.
+	var pullAlgorithm UnderlyingSourcePullCallback = func(*sobek.Object) *sobek.Promise {
+		return newResolvedPromise(stream.vu, sobek.Undefined())
+	}
+
+	// 4. Let cancelAlgorithm be an algorithm that returns a promise resolved with undefined.
+	var cancelAlgorithm UnderlyingSourceCancelCallback = func(any) sobek.Value {
+		return rt.ToValue(newResolvedPromise(stream.vu, sobek.Undefined()))
+	}
+
+	// 5. If underlyingSourceDict["start"] exists, then set startAlgorithm to an algorithm which returns
+	// the result of invoking underlyingSourceDict["start"] with argument list « controller » and callback
+	// this value underlyingSource.
+	if underlyingSourceDict.startSet {
+		startAlgorithm = stream.startAlgorithm(underlyingSource, underlyingSourceDict)
+	}
+
+	// 6. If underlyingSourceDict["pull"] exists, then set pullAlgorithm to an algorithm which returns
+	// the result of invoking underlyingSourceDict["pull"] with argument list « controller » and callback
+	// this value underlyingSource.
+	if underlyingSourceDict.pullSet {
+		pullAlgorithm = stream.pullAlgorithm(underlyingSource, underlyingSourceDict)
+	}
+
+	// 7. If underlyingSourceDict["cancel"] exists, then set cancelAlgorithm to an algorithm which takes
+	// an argument reason and returns the result of invoking underlyingSourceDict["cancel"] with argument
+	// list « reason » and callback this value underlyingSource.
+	if underlyingSourceDict.cancelSet {
+		cancelAlgorithm = stream.cancelAlgorithm(underlyingSource, underlyingSourceDict)
+	}
+
+	// 8. Let autoAllocateChunkSize be underlyingSourceDict["autoAllocateChunkSize"], if it exists, or undefined otherwise.
+	autoAllocateChunkSize := underlyingSourceDict.AutoAllocateChunkSize
+
+	// 9. If autoAllocateChunkSize is 0, then throw a TypeError exception.
+	if autoAllocateChunkSize.Valid && autoAllocateChunkSize.Int64 == 0 {
+		throw(rt, newTypeError(rt, "autoAllocateChunkSize must be greater than 0"))
+	}
+
+	// 10. Perform ? SetUpReadableByteStreamController(...).
+	stream.setupByteController(
+		controller,
+		startAlgorithm,
+		pullAlgorithm,
+		cancelAlgorithm,
+		highWaterMark,
+		autoAllocateChunkSize.Int64,
+	)
+}
+
+// setupByteController implements the specification's [SetUpReadableByteStreamController] abstract operation.
+//
+// [SetUpReadableByteStreamController]: https://streams.spec.whatwg.org/#set-up-readable-byte-stream-controller
+func (stream *ReadableStream) setupByteController(
+	controller *ReadableByteStreamController,
+	startAlgorithm UnderlyingSourceStartCallback,
+	pullAlgorithm UnderlyingSourcePullCallback,
+	cancelAlgorithm UnderlyingSourceCancelCallback,
+	highWaterMark float64,
+	autoAllocateChunkSize int64,
+) {
+	rt := stream.runtime
+
+	// 1. Assert: stream.[[controller]] is undefined.
+	if stream.controller != nil {
+		common.Throw(rt, newError(AssertionError, "stream.[[controller]] is not undefined"))
+	}
+
+	// 3. Set controller.[[stream]] to stream.
+	controller.stream = stream
+
+	// 4. Set controller.[[pullAgain]] and controller.[[pulling]] to false.
+	controller.pullAgain, controller.pulling = false, false
+
+	// 5. Set controller.[[byobRequest]] to null.
+	controller.byobRequest = nil
+
+	// 6. Perform ! ResetQueue(controller).
+	controller.resetQueue()
+
+	// 7. Set controller.[[closeRequested]] and controller.[[started]] to false.
+	controller.closeRequested, controller.started = false, false
+
+	// 8. Set controller.[[strategyHWM]] to highWaterMark.
+	controller.strategyHWM = highWaterMark
+
+	// 9. Set controller.[[pullAlgorithm]] to pullAlgorithm.
+	controller.pullAlgorithm = pullAlgorithm
+
+	// 10. Set controller.[[cancelAlgorithm]] to cancelAlgorithm.
+	controller.cancelAlgorithm = cancelAlgorithm
+
+	// 11. Set controller.[[autoAllocateChunkSize]] to autoAllocateChunkSize.
+	controller.autoAllocateChunkSize = autoAllocateChunkSize
+
+	// 12. Set controller.[[pendingPullIntos]] to a new empty list.
+	controller.pendingPullIntos = nil
+
+	// 13. Set stream.[[controller]] to controller.
+	stream.controller = controller
+
+	// 14. Let startResult be the result of performing startAlgorithm.
+	controllerObj, err := controller.toObject()
+	if err != nil {
+		common.Throw(rt, newError(RuntimeError, err.Error()))
+	}
+	startResult := startAlgorithm(controllerObj)
+
+	// 15. Let startPromise be a promise resolved with startResult.
+	startPromise := promiseResolve(stream.vu, startResult)
+
+	_, err = promiseThen(rt, startPromise,
+		// 16. Upon fulfillment of startPromise,
+		func(sobek.Value) {
+			// 16.1. Set controller.[[started]] to true.
+			controller.started = true
+			// 16.2. Assert: controller.[[pulling]] is false.
+			// 16.3. Assert: controller.[[pullAgain]] is false.
+			if controller.pulling || controller.pullAgain {
+				common.Throw(rt, newError(AssertionError, "controller is pulling"))
+			}
+			// 16.4. Perform ! ReadableByteStreamControllerCallPullIfNeeded(controller).
+			controller.callPullIfNeeded()
+		},
+		// 17. Upon rejection of startPromise with reason r,
+		func(r sobek.Value) {
+			// 17.1. Perform ! ReadableByteStreamControllerError(controller, r).
+			controller.error(r)
+		},
+	)
+	if err != nil {
+		common.Throw(rt, err)
+	}
+}
+
 // acquireDefaultReader implements the specification's [AcquireReadableStreamDefaultReader] algorithm.
 //
 // [AcquireReadableStreamDefaultReader]: https://streams.spec.whatwg.org/#acquire-readable-stream-reader
@@ -411,7 +580,19 @@ func (stream *ReadableStream) cancel(reason sobek.Value) *sobek.Promise {
 
 	// 5. Let reader be stream.[[reader]].
 	// 6. If reader is not undefined and reader implements ReadableStreamBYOBReader,
-	// Not implemented yet: ReadableStreamBYOBReader is not supported yet.
+	if byobReader, ok := stream.reader.(*ReadableStreamBYOBReader); ok {
+		// 6.1. Let readIntoRequests be reader.[[readIntoRequests]].
+		readIntoRequests := byobReader.readIntoRequests
+
+		// 6.2. Set reader.[[readIntoRequests]] to an empty list.
+		byobReader.readIntoRequests = []ReadIntoRequest{}
+
+		// 6.3. For each readIntoRequest of readIntoRequests,
+		for _, readIntoRequest := range readIntoRequests {
+			// 6.3.1. Perform readIntoRequest’s close steps, given undefined.
+			readIntoRequest.closeSteps(sobek.Undefined())
+		}
+	}
 
 	// 7. Let sourceCancelPromise be ! stream.[[controller]].[[CancelSteps]](reason).
 	sourceCancelPromise := stream.controller.cancelSteps(reason)
@@ -533,9 +714,15 @@ func (stream *ReadableStream) error(e any) {
 		return
 	}
 
-	// 9. OTHERWISE, reader is a ReadableStreamBYOBReader
+	// 9. Otherwise,
 	// 9.1. Assert: reader implements ReadableStreamBYOBReader.
-	common.Throw(stream.vu.Runtime(), newError(NotSupportedError, "ReadableStreamBYOBReader is not supported yet"))
+	byobReader, ok := reader.(*ReadableStreamBYOBReader)
+	if !ok {
+		common.Throw(stream.vu.Runtime(), newError(AssertionError, "reader is not a ReadableStreamBYOBReader"))
+	}
+
+	// 9.2. Perform ! ReadableStreamBYOBReaderErrorReadIntoRequests(reader, e).
+	byobReader.errorReadIntoRequests(e)
 }
 
 // fulfillReadRequest implements the [ReadableStreamFulfillReadRequest()] algorithm.
@@ -607,3 +794,139 @@ func (stream *ReadableStream) hasDefaultReader() bool {
 	_, ok := reader.(*ReadableStreamDefaultReader)
 	return ok
 }
+
+// acquireBYOBReader implements the specification's [AcquireReadableStreamBYOBReader] algorithm.
+//
+// [AcquireReadableStreamBYOBReader]: https://streams.spec.whatwg.org/#acquire-readable-stream-byob-reader
+func (stream *ReadableStream) acquireBYOBReader() *ReadableStreamBYOBReader {
+	// 1. Let reader be a new ReadableStreamBYOBReader.
+	reader := &ReadableStreamBYOBReader{}
+
+	// 2. Perform ? SetUpReadableStreamBYOBReader(reader, stream).
+	reader.setup(stream)
+
+	// 3. Return reader.
+	return reader
+}
+
+// addReadIntoRequest implements the specification's [ReadableStreamAddReadIntoRequest] algorithm.
+//
+// [ReadableStreamAddReadIntoRequest]: https://streams.spec.whatwg.org/#readable-stream-add-read-into-request
+func (stream *ReadableStream) addReadIntoRequest(readIntoRequest ReadIntoRequest) {
+	// 1. Assert: stream.[[reader]] implements ReadableStreamBYOBReader.
+	byobReader, ok := stream.reader.(*ReadableStreamBYOBReader)
+	if !ok {
+		readIntoRequest.errorSteps(newError(AssertionError, "reader is not a ReadableStreamBYOBReader"))
+		return
+	}
+
+	// 2. Assert: stream.[[state]] is "readable" or "closed".
+	if stream.state != ReadableStreamStateReadable && stream.state != ReadableStreamStateClosed {
+		readIntoRequest.errorSteps(newError(AssertionError, "stream is not readable or closed"))
+		return
+	}
+
+	// 3. Append readRequest to stream.[[reader]].[[readIntoRequests]].
+	byobReader.readIntoRequests = append(byobReader.readIntoRequests, readIntoRequest)
+}
+
+// fulfillReadIntoRequest implements the [ReadableStreamFulfillReadIntoRequest] algorithm.
+//
+// [ReadableStreamFulfillReadIntoRequest]: https://streams.spec.whatwg.org/#readable-stream-fulfill-read-into-request
+func (stream *ReadableStream) fulfillReadIntoRequest(chunk any, done bool) {
+	// 1. Assert: ! ReadableStreamHasBYOBReader(stream) is true.
+	// 2. Let reader be stream.[[reader]].
+	reader, ok := stream.reader.(*ReadableStreamBYOBReader)
+	if !ok {
+		common.Throw(stream.runtime, newError(AssertionError, "stream does not have a BYOB reader"))
+	}
+
+	// 3. Assert: reader.[[readIntoRequests]] is not empty.
+	if len(reader.readIntoRequests) == 0 {
+		common.Throw(stream.runtime, newError(AssertionError, "reader.[[readIntoRequests]] is empty"))
+	}
+
+	// 4. Let readIntoRequest be reader.[[readIntoRequests]][0].
+	readIntoRequest := reader.readIntoRequests[0]
+
+	// 5. Remove readIntoRequest from reader.[[readIntoRequests]].
+	reader.readIntoRequests = reader.readIntoRequests[1:]
+
+	if done {
+		// 6. If done is true, perform readIntoRequest’s close steps, given chunk.
+		readIntoRequest.closeSteps(chunk)
+	} else {
+		// 7. Otherwise, perform readIntoRequest’s chunk steps, given chunk.
+		readIntoRequest.chunkSteps(chunk)
+	}
+}
+
+// getNumReadIntoRequests implements the [ReadableStreamGetNumReadIntoRequests] algorithm.
+//
+// [ReadableStreamGetNumReadIntoRequests]: https://streams.spec.whatwg.org/#readable-stream-get-num-read-into-requests
+func (stream *ReadableStream) getNumReadIntoRequests() int {
+	// 1. Assert: ! ReadableStreamHasBYOBReader(stream) is true.
+	reader, ok := stream.reader.(*ReadableStreamBYOBReader)
+	if !ok {
+		common.Throw(stream.runtime, newError(AssertionError, "stream does not have a BYOB reader"))
+	}
+
+	// 2. Return stream.[[reader]].[[readIntoRequests]]'s size.
+	return len(reader.readIntoRequests)
+}
+
+// hasBYOBReader implements the [ReadableStreamHasBYOBReader] algorithm.
+//
+// [ReadableStreamHasBYOBReader]: https://streams.spec.whatwg.org/#readable-stream-has-byob-reader
+func (stream *ReadableStream) hasBYOBReader() bool {
+	// 1. Let reader be stream.[[reader]].
+	// 2. If reader is undefined, return false.
+	// 3. If reader implements ReadableStreamBYOBReader, return true.
+	// 4. Return false.
+	_, ok := stream.reader.(*ReadableStreamBYOBReader)
+	return ok
+}
+
+// createReadableStream implements the specification's [CreateReadableStream] abstract operation.
+//
+// The stream's object is created with the given prototype.
+//
+// [CreateReadableStream]: https://streams.spec.whatwg.org/#create-readable-stream
+func createReadableStream(
+	vu modules.VU,
+	proto *sobek.Object,
+	startAlgorithm UnderlyingSourceStartCallback,
+	pullAlgorithm UnderlyingSourcePullCallback,
+	cancelAlgorithm UnderlyingSourceCancelCallback,
+	highWaterMark float64,
+	sizeAlgorithm SizeAlgorithm,
+) *ReadableStream {
+	rt := vu.Runtime()
+
+	// 1. If highWaterMark was not passed, set it to 1.
+	// 2. If sizeAlgorithm was not passed, set it to an algorithm that returns 1.
+	if sizeAlgorithm == nil {
+		sizeAlgorithm = extractSizeAlgorithm(rt, rt.NewObject())
+	}
+
+	// 3. Assert: ! IsNonNegativeNumber(highWaterMark) is true.
+	// 4. Let stream be a new ReadableStream.
+	stream := &ReadableStream{
+		runtime: rt,
+		vu:      vu,
+	}
+
+	// 5. Perform ! InitializeReadableStream(stream).
+	stream.initialize()
+
+	// 6. Let controller be a new ReadableStreamDefaultController.
+	controller := &ReadableStreamDefaultController{}
+
+	// 7. Perform ? SetUpReadableStreamDefaultController(...).
+	stream.setupDefaultController(controller, startAlgorithm, pullAlgorithm, cancelAlgorithm, highWaterMark, sizeAlgorithm)
+
+	newReadableStreamObject(stream, proto)
+
+	// 8. Return stream.
+	return stream
+}
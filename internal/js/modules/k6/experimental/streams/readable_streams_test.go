@@ -1,9 +1,15 @@
 package streams
 
 import (
+	"bufio"
+	"bytes"
 	"os"
+	"path"
+	"slices"
+	"strings"
 	"testing"
 
+	"go.k6.io/k6/internal/js/tc55/encoding"
 	"go.k6.io/k6/js/modules"
 	"go.k6.io/k6/js/modulestest"
 
@@ -13,12 +19,15 @@ import (
 
 const webPlatformTestSuite = "tests/wpt"
 
+// The Streams-specific test utilities, which are loaded before every suite.
+var streamsTestUtilities = []string{ //nolint:gochecknoglobals
+	"resources/rs-test-templates.js",
+	"resources/rs-utils.js",
+	"resources/test-utils.js",
+}
+
 func TestReadableStream(t *testing.T) {
 	t.Parallel()
-	if _, err := os.Stat(webPlatformTestSuite); err != nil { //nolint:forbidigo
-		t.Skipf("If you want to run Streams tests, you need to run the 'checkout.sh` script in the directory to get "+
-			"https://github.com/web-platform-tests/wpt at the correct last tested commit (%v)", err)
-	}
 
 	suites := []string{
 		"bad-strategies.any.js",
@@ -33,18 +42,69 @@ func TestReadableStream(t *testing.T) {
 		"templated.any.js",
 	}
 
+	runWebPlatformTests(t, "streams/readable-streams", suites)
+}
+
+// runWebPlatformTests runs the given suites of a catalog of the Web Platform
+// Tests, each one after the scripts that it declares with META comments.
+func runWebPlatformTests(t *testing.T, catalog string, suites []string) {
+	t.Helper()
+	if _, err := os.Stat(webPlatformTestSuite); err != nil { //nolint:forbidigo
+		t.Skipf("If you want to run Streams tests, you need to run the 'checkout.sh` script in the directory to get "+
+			"https://github.com/web-platform-tests/wpt at the correct last tested commit (%v)", err)
+	}
+
+	base := path.Join(webPlatformTestSuite, catalog)
 	for _, suite := range suites {
 		t.Run(suite, func(t *testing.T) {
 			t.Parallel()
 			ts := newConfiguredRuntime(t)
+			for _, script := range metaScripts(t, base, suite) {
+				compileAndRun(t, ts, path.Dir(script), path.Base(script))
+			}
 			gotErr := ts.EventLoop.Start(func() error {
-				return executeTestScript(ts.VU, webPlatformTestSuite+"/streams/readable-streams", suite)
+				return executeTestScript(ts.VU, base, suite)
 			})
 			assert.NoError(t, gotErr)
 		})
 	}
 }
 
+// metaScripts returns the paths of the scripts a suite declares with
+// "// META: script=" comments, see https://web-platform-tests.org/writing-tests/testharness.html,
+// except for the ones that newConfiguredRuntime already loaded.
+func metaScripts(t testing.TB, base, suite string) []string {
+	data, err := os.ReadFile(path.Join(base, suite)) //nolint:forbidigo
+	require.NoError(t, err)
+
+	loaded := []string{path.Join(webPlatformTestSuite, "resources/testharness.js")}
+	for _, file := range streamsTestUtilities {
+		loaded = append(loaded, path.Join(webPlatformTestSuite, "streams", file))
+	}
+
+	var scripts []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "// META:") {
+			break
+		}
+		script, ok := strings.CutPrefix(strings.TrimSpace(strings.TrimPrefix(line, "// META:")), "script=")
+		if !ok {
+			continue
+		}
+		if strings.HasPrefix(script, "/") {
+			script = path.Join(webPlatformTestSuite, script)
+		} else {
+			script = path.Join(base, script)
+		}
+		if !slices.Contains(loaded, script) {
+			scripts = append(scripts, script)
+		}
+	}
+	return scripts
+}
+
 func newConfiguredRuntime(t testing.TB) *modulestest.Runtime {
 	rt := modulestest.NewRuntime(t)
 
@@ -54,22 +114,19 @@ func newConfiguredRuntime(t testing.TB) *modulestest.Runtime {
 	_, err := rt.VU.Runtime().RunString("var self = this;")
 	require.NoError(t, err)
 
-	// We also want the streams module exports to be globally available.
+	// We also want the streams module exports to be globally available,
+	// together with the TextEncoder and TextDecoder used by some of the tests.
 	m := new(RootModule).NewModuleInstance(rt.VU)
 	for k, v := range m.Exports().Named {
 		require.NoError(t, rt.VU.RuntimeField.Set(k, v))
 	}
+	require.NoError(t, encoding.SetupGlobally(rt.VU))
 
 	// Then, we register the Web Platform Tests harness.
 	compileAndRun(t, rt, webPlatformTestSuite, "resources/testharness.js")
 
 	// And the Streams-specific test utilities.
-	files := []string{
-		"resources/rs-test-templates.js",
-		"resources/rs-utils.js",
-		"resources/test-utils.js",
-	}
-	for _, file := range files {
+	for _, file := range streamsTestUtilities {
 		compileAndRun(t, rt, webPlatformTestSuite+"/streams", file)
 	}
 
@@ -9,7 +9,7 @@ cd ./wpt
 git init
 git remote add origin https://github.com/web-platform-tests/wpt
 git sparse-checkout init --cone
-git sparse-checkout set resources streams
+git sparse-checkout set resources streams compression
 git fetch origin --depth=1 "${sha}"
 git checkout ${sha}
 
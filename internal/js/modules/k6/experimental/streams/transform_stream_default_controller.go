@@ -0,0 +1,228 @@
+package streams
+
+import (
+	"github.com/grafana/sobek"
+)
+
+// TransformStreamDefaultController is the default controller for a [TransformStream]. It has
+// methods to manipulate the associated readable and writable sides, and it's handed to the
+// transformer.
+//
+// For more details, see the [specification].
+//
+// [specification]: https://streams.spec.whatwg.org/#ts-default-controller-class
+type TransformStreamDefaultController struct {
+	// cancelAlgorithm is a promise-returning algorithm, taking one argument (the reason for
+	// cancellation), which communicates a requested cancellation to the transformer
+	cancelAlgorithm TransformerCancelCallback
+
+	// finishPromise is a promise which resolves on completion of either the cancel algorithm
+	// or the flush algorithm
+	finishPromise *promiseCapability
+
+	// flushAlgorithm is a promise-returning algorithm which communicates a requested close
+	// to the transformer
+	flushAlgorithm TransformerFlushCallback
+
+	// stream is the transform stream that this controller controls
+	stream *TransformStream
+
+	// transformAlgorithm is a promise-returning algorithm, taking one argument (the chunk
+	// to transform), which requests the transformer perform its transformation
+	transformAlgorithm TransformerTransformCallback
+
+	// obj is the JS object representing the controller, handed to the transformer
+	obj *sobek.Object
+}
+
+// NewTransformStreamDefaultControllerObject creates a new [sobek.Object] from a
+// [TransformStreamDefaultController] instance.
+func NewTransformStreamDefaultControllerObject(controller *TransformStreamDefaultController) (*sobek.Object, error) {
+	rt := controller.stream.runtime
+	obj := rt.NewObject()
+	objName := "TransformStreamDefaultController"
+
+	err := obj.DefineAccessorProperty("desiredSize", rt.ToValue(func() sobek.Value {
+		desiredSize := controller.stream.readableController().getDesiredSize()
+		if !desiredSize.Valid {
+			return sobek.Null()
+		}
+		return rt.ToValue(desiredSize.Float64)
+	}), nil, sobek.FLAG_FALSE, sobek.FLAG_TRUE)
+	if err != nil {
+		return nil, err
+	}
+
+	// Exposing the properties of the [TransformStreamDefaultController] interface
+	if err := setReadOnlyPropertyOf(obj, objName, "enqueue", rt.ToValue(controller.Enqueue)); err != nil {
+		return nil, err
+	}
+
+	if err := setReadOnlyPropertyOf(obj, objName, "error", rt.ToValue(controller.Error)); err != nil {
+		return nil, err
+	}
+
+	if err := setReadOnlyPropertyOf(obj, objName, "terminate", rt.ToValue(controller.Terminate)); err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+// Enqueue enqueues the given chunk in the readable side of the stream.
+//
+// It implements the TransformStreamDefaultController.enqueue(chunk) [specification] algorithm.
+//
+// [specification]: https://streams.spec.whatwg.org/#ts-default-controller-enqueue
+func (controller *TransformStreamDefaultController) Enqueue(chunk sobek.Value) {
+	if chunk == nil {
+		chunk = sobek.Undefined()
+	}
+
+	// 1. Perform ? TransformStreamDefaultControllerEnqueue(this, chunk).
+	controller.enqueue(chunk)
+}
+
+// Error errors both the readable side and the writable side of the stream.
+//
+// It implements the TransformStreamDefaultController.error(e) [specification] algorithm.
+//
+// [specification]: https://streams.spec.whatwg.org/#ts-default-controller-error
+func (controller *TransformStreamDefaultController) Error(e sobek.Value) {
+	if e == nil {
+		e = sobek.Undefined()
+	}
+
+	// 1. Perform ? TransformStreamDefaultControllerError(this, e).
+	controller.stream.error(e)
+}
+
+// Terminate closes the readable side and errors the writable side of the stream.
+//
+// It implements the TransformStreamDefaultController.terminate() [specification] algorithm.
+//
+// [specification]: https://streams.spec.whatwg.org/#ts-default-controller-terminate
+func (controller *TransformStreamDefaultController) Terminate() {
+	// 1. Perform ? TransformStreamDefaultControllerTerminate(this).
+	controller.terminate()
+}
+
+// toObject returns the [sobek.Object] representing the controller, creating it on first use.
+func (controller *TransformStreamDefaultController) toObject() (*sobek.Object, error) {
+	if controller.obj != nil {
+		return controller.obj, nil
+	}
+
+	obj, err := NewTransformStreamDefaultControllerObject(controller)
+	if err != nil {
+		return nil, err
+	}
+	controller.obj = obj
+
+	return obj, nil
+}
+
+// clearAlgorithms implements the specification's [TransformStreamDefaultControllerClearAlgorithms]
+// abstract operation.
+//
+// [TransformStreamDefaultControllerClearAlgorithms]: https://streams.spec.whatwg.org/#transform-stream-default-controller-clear-algorithms
+func (controller *TransformStreamDefaultController) clearAlgorithms() {
+	// 1. Set controller.[[transformAlgorithm]] to undefined.
+	controller.transformAlgorithm = nil
+
+	// 2. Set controller.[[flushAlgorithm]] to undefined.
+	controller.flushAlgorithm = nil
+
+	// 3. Set controller.[[cancelAlgorithm]] to undefined.
+	controller.cancelAlgorithm = nil
+}
+
+// enqueue implements the specification's [TransformStreamDefaultControllerEnqueue] abstract operation.
+//
+// [TransformStreamDefaultControllerEnqueue]: https://streams.spec.whatwg.org/#transform-stream-default-controller-enqueue
+func (controller *TransformStreamDefaultController) enqueue(chunk sobek.Value) {
+	rt := controller.stream.runtime
+
+	// 1. Let stream be controller.[[stream]].
+	stream := controller.stream
+
+	// 2. Let readableController be stream.[[readable]].[[controller]].
+	readableController := stream.readableController()
+
+	// 3. If ! ReadableStreamDefaultControllerCanCloseOrEnqueue(readableController) is false, throw a TypeError exception.
+	if !readableController.canCloseOrEnqueue() {
+		throw(rt, newTypeError(rt, "the readable side cannot be enqueued to"))
+	}
+
+	// 4. Let enqueueResult be ReadableStreamDefaultControllerEnqueue(readableController, chunk).
+	var enqueueResult error
+	if ex := rt.Try(func() { enqueueResult = readableController.enqueue(chunk) }); ex != nil {
+		enqueueResult = ex
+	}
+
+	// 5. If enqueueResult is an abrupt completion,
+	if enqueueResult != nil {
+		// 5.1. Perform ! TransformStreamErrorWritableAndUnblockWrite(stream, enqueueResult.[[Value]]).
+		stream.errorWritableAndUnblockWrite(rt.ToValue(rejectionReason(enqueueResult)))
+
+		// 5.2. Throw stream.[[readable]].[[storedError]].
+		panic(rt.ToValue(rejectionReason(stream.readable.storedError)))
+	}
+
+	// 6. Let backpressure be ! ReadableStreamDefaultControllerHasBackpressure(readableController).
+	backpressure := readableController.hasBackpressure()
+
+	// 7. If backpressure is not stream.[[backpressure]],
+	if backpressure != stream.backpressure {
+		// 7.1. Assert: backpressure is true.
+		// 7.2. Perform ! TransformStreamSetBackpressure(stream, true).
+		stream.setBackpressure(true)
+	}
+}
+
+// performTransform implements the specification's [TransformStreamDefaultControllerPerformTransform]
+// abstract operation.
+//
+// [TransformStreamDefaultControllerPerformTransform]: https://streams.spec.whatwg.org/#transform-stream-default-controller-perform-transform
+func (controller *TransformStreamDefaultController) performTransform(chunk sobek.Value) *sobek.Promise {
+	rt := controller.stream.runtime
+
+	// 1. Let transformPromise be the result of performing controller.[[transformAlgorithm]], passing chunk.
+	transformPromise := controller.transformAlgorithm(chunk)
+
+	// 2. Return the result of reacting to transformPromise with the following rejection steps given the argument r:
+	promise := newPromiseCapability(rt)
+	uponPromise(rt, transformPromise,
+		func(sobek.Value) {
+			promise.resolve(sobek.Undefined())
+		},
+		func(r sobek.Value) {
+			// 2.1. Perform ! TransformStreamError(controller.[[stream]], r).
+			controller.stream.error(r)
+			// 2.2. Throw r.
+			promise.reject(r)
+		},
+	)
+
+	return promise.promise
+}
+
+// terminate implements the specification's [TransformStreamDefaultControllerTerminate] abstract operation.
+//
+// [TransformStreamDefaultControllerTerminate]: https://streams.spec.whatwg.org/#transform-stream-default-controller-terminate
+func (controller *TransformStreamDefaultController) terminate() {
+	rt := controller.stream.runtime
+
+	// 1. Let stream be controller.[[stream]].
+	stream := controller.stream
+
+	// 2. Let readableController be stream.[[readable]].[[controller]].
+	// 3. Perform ! ReadableStreamDefaultControllerClose(readableController).
+	stream.readableController().close()
+
+	// 4. Let error be a TypeError exception indicating that the stream has been terminated.
+	e := newTypeError(rt, "the transform stream has been terminated")
+
+	// 5. Perform ! TransformStreamErrorWritableAndUnblockWrite(stream, error).
+	stream.errorWritableAndUnblockWrite(e.Err())
+}
@@ -0,0 +1,744 @@
+package streams
+
+import (
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/js/common"
+	"go.k6.io/k6/js/modules"
+)
+
+// TransformStream is a concrete instance of the general [transform stream] concept.
+//
+// It consists of a pair of streams: a writable stream, known as its writable side, and
+// a readable stream, known as its readable side. Writing to the writable side results
+// in new data being made available for reading from the readable side.
+//
+// [transform stream]: https://streams.spec.whatwg.org/#ts-class
+type TransformStream struct {
+	// Readable is the JS object of the readable side of the stream
+	Readable *sobek.Object `js:"readable"`
+
+	// Writable is the JS object of the writable side of the stream
+	Writable *sobek.Object `js:"writable"`
+
+	// backpressure is whether there was backpressure on the readable side the last time
+	// it was observed
+	backpressure bool
+
+	// backpressureChangePromise is a promise which is fulfilled and replaced every time the
+	// value of backpressure changes
+	backpressureChangePromise *promiseCapability
+
+	// controller holds the [TransformStreamDefaultController] created with the ability to
+	// control the readable and writable sides of the stream
+	controller *TransformStreamDefaultController
+
+	// readable is the readable side of the stream
+	readable *ReadableStream
+
+	// writable is the writable side of the stream
+	writable *WritableStream
+
+	runtime *sobek.Runtime
+	vu      modules.VU
+}
+
+// NewTransformStream is the constructor for the [TransformStream] object.
+//
+// [TransformStream]: https://streams.spec.whatwg.org/#ts-class
+func (mi *ModuleInstance) NewTransformStream(call sobek.ConstructorCall) *sobek.Object {
+	var (
+		// 1. If transformer is missing, set it to null.
+		transformer *sobek.Object
+
+		rt = mi.vu.Runtime()
+
+		err             error
+		transformerDict Transformer
+	)
+
+	// 2. Let transformerDict be transformer, converted to an IDL value of type Transformer.
+	// 3. If transformerDict["readableType"] exists, throw a RangeError exception.
+	// 4. If transformerDict["writableType"] exists, throw a RangeError exception.
+	if len(call.Arguments) > 0 && !sobek.IsUndefined(call.Arguments[0]) {
+		if !isObject(call.Arguments[0]) {
+			throw(rt, newTypeError(rt, "transformer must be an object"))
+		}
+
+		transformer = call.Arguments[0].ToObject(rt)
+		transformerDict, err = NewTransformerFromObject(rt, transformer)
+		if err != nil {
+			throw(rt, err)
+		}
+	}
+
+	writableStrategy := queuingStrategyArgument(rt, call.Argument(1), "writableStrategy")
+	readableStrategy := queuingStrategyArgument(rt, call.Argument(2), "readableStrategy")
+
+	// 5. Let readableHighWaterMark be ? ExtractHighWaterMark(readableStrategy, 0).
+	readableHighWaterMark := extractHighWaterMark(rt, readableStrategy, 0)
+
+	// 6. Let readableSizeAlgorithm be ! ExtractSizeAlgorithm(readableStrategy).
+	readableSizeAlgorithm := extractSizeAlgorithm(rt, readableStrategy)
+
+	// 7. Let writableHighWaterMark be ? ExtractHighWaterMark(writableStrategy, 1).
+	writableHighWaterMark := extractHighWaterMark(rt, writableStrategy, 1)
+
+	// 8. Let writableSizeAlgorithm be ! ExtractSizeAlgorithm(writableStrategy).
+	writableSizeAlgorithm := extractSizeAlgorithm(rt, writableStrategy)
+
+	// 9. Let startPromise be a new promise.
+	startPromise := newPromiseCapability(rt)
+
+	// 10. Perform ! InitializeTransformStream(...).
+	stream := &TransformStream{runtime: rt, vu: mi.vu}
+	stream.initialize(
+		mi.prototypeOf("ReadableStream"),
+		mi.prototypeOf("WritableStream"),
+		startPromise.promise,
+		writableHighWaterMark,
+		writableSizeAlgorithm,
+		readableHighWaterMark,
+		readableSizeAlgorithm,
+	)
+
+	// 11. Perform ? SetUpTransformStreamDefaultControllerFromTransformer(this, transformer, transformerDict).
+	stream.setupDefaultControllerFromTransformer(transformer, transformerDict)
+
+	// 12. If transformerDict["start"] exists, then resolve startPromise with the result of invoking
+	// transformerDict["start"] with argument list « this.[[controller]] » and callback this value transformer.
+	// 13. Otherwise, resolve startPromise with undefined.
+	startResult := sobek.Undefined()
+	if start, ok := sobek.AssertFunction(transformerDict.Start); ok {
+		controllerObj, err := stream.controller.toObject()
+		if err != nil {
+			common.Throw(rt, newError(RuntimeError, err.Error()))
+		}
+
+		startResult, err = start(transformer, controllerObj)
+		if err != nil {
+			panic(err)
+		}
+	}
+	startPromise.resolve(startResult)
+
+	return newTransformStreamObject(stream, call.This.Prototype())
+}
+
+// newTransformStreamObject creates the [sobek.Object] representing the stream,
+// with the given prototype.
+func newTransformStreamObject(stream *TransformStream, proto *sobek.Object) *sobek.Object {
+	rt := stream.runtime
+	streamObj := rt.ToValue(stream).ToObject(rt)
+
+	if err := streamObj.SetPrototype(proto); err != nil {
+		common.Throw(rt, newError(RuntimeError, err.Error()))
+	}
+
+	return streamObj
+}
+
+// queuingStrategyArgument returns the given queuing strategy argument as an object, which is
+// empty if the argument is missing.
+func queuingStrategyArgument(rt *sobek.Runtime, arg sobek.Value, name string) *sobek.Object {
+	if common.IsNullish(arg) {
+		return rt.NewObject()
+	}
+
+	if !isObject(arg) {
+		throw(rt, newTypeError(rt, name+" must be an object"))
+	}
+
+	return arg.ToObject(rt)
+}
+
+// initialize implements the specification's [InitializeTransformStream] abstract operation.
+//
+// The readable and writable sides are created with the given prototypes.
+//
+// [InitializeTransformStream]: https://streams.spec.whatwg.org/#initialize-transform-stream
+func (stream *TransformStream) initialize(
+	readableProto, writableProto *sobek.Object,
+	startPromise *sobek.Promise,
+	writableHighWaterMark float64,
+	writableSizeAlgorithm SizeAlgorithm,
+	readableHighWaterMark float64,
+	readableSizeAlgorithm SizeAlgorithm,
+) {
+	rt := stream.runtime
+
+	// 1. Let startAlgorithm be an algorithm that returns startPromise.
+	startSinkAlgorithm := func(*sobek.Object) sobek.Value { return rt.ToValue(startPromise) }
+	startSourceAlgorithm := func(*sobek.Object) sobek.Value { return rt.ToValue(startPromise) }
+
+	// 2. Let writeAlgorithm be the following steps, taking a chunk argument:
+	writeAlgorithm := func(chunk sobek.Value, _ *sobek.Object) *sobek.Promise {
+		// 2.1. Return ! TransformStreamDefaultSinkWriteAlgorithm(stream, chunk).
+		return stream.sinkWriteAlgorithm(chunk)
+	}
+
+	// 3. Let abortAlgorithm be the following steps, taking a reason argument:
+	abortAlgorithm := func(reason sobek.Value) *sobek.Promise {
+		// 3.1. Return ! TransformStreamDefaultSinkAbortAlgorithm(stream, reason).
+		return stream.sinkAbortAlgorithm(reason)
+	}
+
+	// 4. Let closeAlgorithm be the following steps:
+	closeAlgorithm := func() *sobek.Promise {
+		// 4.1. Return ! TransformStreamDefaultSinkCloseAlgorithm(stream).
+		return stream.sinkCloseAlgorithm()
+	}
+
+	// 5. Set stream.[[writable]] to ! CreateWritableStream(startAlgorithm, writeAlgorithm, closeAlgorithm,
+	// abortAlgorithm, writableHighWaterMark, writableSizeAlgorithm).
+	stream.writable = createWritableStream(
+		stream.vu,
+		writableProto,
+		startSinkAlgorithm,
+		writeAlgorithm,
+		closeAlgorithm,
+		abortAlgorithm,
+		writableHighWaterMark,
+		writableSizeAlgorithm,
+	)
+	stream.Writable = stream.writable.obj
+
+	// 6. Let pullAlgorithm be the following steps:
+	pullAlgorithm := func(*sobek.Object) *sobek.Promise {
+		// 6.1. Return ! TransformStreamDefaultSourcePullAlgorithm(stream).
+		return stream.sourcePullAlgorithm()
+	}
+
+	// 7. Let cancelAlgorithm be the following steps, taking a reason argument:
+	cancelAlgorithm := func(reason any) sobek.Value {
+		// 7.1. Return ! TransformStreamDefaultSourceCancelAlgorithm(stream, reason).
+		return rt.ToValue(stream.sourceCancelAlgorithm(rt.ToValue(rejectionReason(reason))))
+	}
+
+	// 8. Set stream.[[readable]] to ! CreateReadableStream(startAlgorithm, pullAlgorithm, cancelAlgorithm,
+	// readableHighWaterMark, readableSizeAlgorithm).
+	stream.readable = createReadableStream(
+		stream.vu,
+		readableProto,
+		startSourceAlgorithm,
+		pullAlgorithm,
+		cancelAlgorithm,
+		readableHighWaterMark,
+		readableSizeAlgorithm,
+	)
+	stream.Readable = stream.readable.obj
+
+	// 9. Set stream.[[backpressure]] and stream.[[backpressureChangePromise]] to undefined.
+	stream.backpressure = false
+	stream.backpressureChangePromise = nil
+
+	// 10. Perform ! TransformStreamSetBackpressure(stream, true).
+	stream.setBackpressure(true)
+
+	// 11. Set stream.[[controller]] to undefined.
+	stream.controller = nil
+}
+
+// setupDefaultControllerFromTransformer implements the specification's
+// [SetUpTransformStreamDefaultControllerFromTransformer] abstract operation.
+//
+// [SetUpTransformStreamDefaultControllerFromTransformer]: https://streams.spec.whatwg.org/#set-up-transform-stream-default-controller-from-transformer
+func (stream *TransformStream) setupDefaultControllerFromTransformer(
+	transformer *sobek.Object,
+	transformerDict Transformer,
+) {
+	rt := stream.runtime
+
+	// 1. Let controller be a new TransformStreamDefaultController.
+	controller := &TransformStreamDefaultController{}
+
+	// 2. Let transformAlgorithm be the following steps, taking a chunk argument:
+	var transformAlgorithm TransformerTransformCallback = func(chunk sobek.Value) *sobek.Promise {
+		// 2.1. Let result be TransformStreamDefaultControllerEnqueue(controller, chunk).
+		var result *sobek.Exception
+		if result = rt.Try(func() { controller.enqueue(chunk) }); result != nil {
+			// 2.2. If result is an abrupt completion, return a promise rejected with result.[[Value]].
+			return newRejectedPromise(stream.vu, result.Value())
+		}
+
+		// 2.3. Otherwise, return a promise resolved with undefined.
+		return newResolvedPromise(stream.vu, sobek.Undefined())
+	}
+
+	// 3. Let flushAlgorithm be an algorithm which returns a promise resolved with undefined.
+	var flushAlgorithm TransformerFlushCallback = func() *sobek.Promise {
+		return newResolvedPromise(stream.vu, sobek.Undefined())
+	}
+
+	// 4. Let cancelAlgorithm be an algorithm which returns a promise resolved with undefined.
+	var cancelAlgorithm TransformerCancelCallback = func(sobek.Value) *sobek.Promise {
+		return newResolvedPromise(stream.vu, sobek.Undefined())
+	}
+
+	// controllerObj is the object handed to the transformer, created once the controller is set up.
+	var controllerObj *sobek.Object
+
+	// 5. If transformerDict["transform"] exists, set transformAlgorithm to an algorithm which takes an
+	// argument chunk and returns the result of invoking transformerDict["transform"] with argument list
+	// « chunk, controller » and callback this value transformer.
+	if transform, ok := sobek.AssertFunction(transformerDict.Transform); ok {
+		transformAlgorithm = func(chunk sobek.Value) *sobek.Promise {
+			return promiseFromCall(stream.vu, func() (sobek.Value, error) {
+				return transform(transformer, chunk, controllerObj)
+			})
+		}
+	}
+
+	// 6. If transformerDict["flush"] exists, set flushAlgorithm to an algorithm which returns the result
+	// of invoking transformerDict["flush"] with argument list « controller » and callback this value transformer.
+	if flush, ok := sobek.AssertFunction(transformerDict.Flush); ok {
+		flushAlgorithm = func() *sobek.Promise {
+			return promiseFromCall(stream.vu, func() (sobek.Value, error) {
+				return flush(transformer, controllerObj)
+			})
+		}
+	}
+
+	// 7. If transformerDict["cancel"] exists, set cancelAlgorithm to an algorithm which takes an argument
+	// reason and returns the result of invoking transformerDict["cancel"] with argument list « reason »
+	// and callback this value transformer.
+	if cancel, ok := sobek.AssertFunction(transformerDict.Cancel); ok {
+		cancelAlgorithm = func(reason sobek.Value) *sobek.Promise {
+			return promiseFromCall(stream.vu, func() (sobek.Value, error) {
+				return cancel(transformer, reason)
+			})
+		}
+	}
+
+	// 8. Perform ! SetUpTransformStreamDefaultController(stream, controller, transformAlgorithm,
+	// flushAlgorithm, cancelAlgorithm).
+	stream.setupDefaultController(controller, transformAlgorithm, flushAlgorithm, cancelAlgorithm)
+
+	var err error
+	if controllerObj, err = controller.toObject(); err != nil {
+		common.Throw(rt, newError(RuntimeError, err.Error()))
+	}
+}
+
+// setupDefaultController implements the specification's [SetUpTransformStreamDefaultController]
+// abstract operation.
+//
+// [SetUpTransformStreamDefaultController]: https://streams.spec.whatwg.org/#set-up-transform-stream-default-controller
+func (stream *TransformStream) setupDefaultController(
+	controller *TransformStreamDefaultController,
+	transformAlgorithm TransformerTransformCallback,
+	flushAlgorithm TransformerFlushCallback,
+	cancelAlgorithm TransformerCancelCallback,
+) {
+	// 1. Assert: stream implements TransformStream.
+	// 2. Assert: stream.[[controller]] is undefined.
+	if stream.controller != nil {
+		common.Throw(stream.runtime, newError(AssertionError, "stream.[[controller]] is not undefined"))
+	}
+
+	// 3. Set controller.[[stream]] to stream.
+	controller.stream = stream
+
+	// 4. Set stream.[[controller]] to controller.
+	stream.controller = controller
+
+	// 5. Set controller.[[transformAlgorithm]] to transformAlgorithm.
+	controller.transformAlgorithm = transformAlgorithm
+
+	// 6. Set controller.[[flushAlgorithm]] to flushAlgorithm.
+	controller.flushAlgorithm = flushAlgorithm
+
+	// 7. Set controller.[[cancelAlgorithm]] to cancelAlgorithm.
+	controller.cancelAlgorithm = cancelAlgorithm
+}
+
+// error implements the specification's [TransformStreamError] abstract operation.
+//
+// [TransformStreamError]: https://streams.spec.whatwg.org/#transform-stream-error
+func (stream *TransformStream) error(e sobek.Value) {
+	// 1. Perform ! ReadableStreamDefaultControllerError(stream.[[readable]].[[controller]], e).
+	stream.readableController().error(e)
+
+	// 2. Perform ! TransformStreamErrorWritableAndUnblockWrite(stream, e).
+	stream.errorWritableAndUnblockWrite(e)
+}
+
+// errorWritableAndUnblockWrite implements the specification's [TransformStreamErrorWritableAndUnblockWrite]
+// abstract operation.
+//
+// [TransformStreamErrorWritableAndUnblockWrite]: https://streams.spec.whatwg.org/#transform-stream-error-writable-and-unblock-write
+func (stream *TransformStream) errorWritableAndUnblockWrite(e sobek.Value) {
+	// 1. Perform ! TransformStreamDefaultControllerClearAlgorithms(stream.[[controller]]).
+	stream.controller.clearAlgorithms()
+
+	// 2. Perform ! WritableStreamDefaultControllerErrorIfNeeded(stream.[[writable]].[[controller]], e).
+	stream.writable.controller.errorIfNeeded(e)
+
+	// 3. Perform ! TransformStreamUnblockWrite(stream).
+	stream.unblockWrite()
+}
+
+// setBackpressure implements the specification's [TransformStreamSetBackpressure] abstract operation.
+//
+// [TransformStreamSetBackpressure]: https://streams.spec.whatwg.org/#transform-stream-set-backpressure
+func (stream *TransformStream) setBackpressure(backpressure bool) {
+	// 1. Assert: stream.[[backpressure]] is not backpressure.
+	// 2. If stream.[[backpressureChangePromise]] is not undefined, resolve stream.[[backpressureChangePromise]]
+	// with undefined.
+	if stream.backpressureChangePromise != nil {
+		stream.backpressureChangePromise.resolve(sobek.Undefined())
+	}
+
+	// 3. Set stream.[[backpressureChangePromise]] to a new promise.
+	stream.backpressureChangePromise = newPromiseCapability(stream.runtime)
+
+	// 4. Set stream.[[backpressure]] to backpressure.
+	stream.backpressure = backpressure
+}
+
+// unblockWrite implements the specification's [TransformStreamUnblockWrite] abstract operation.
+//
+// [TransformStreamUnblockWrite]: https://streams.spec.whatwg.org/#transform-stream-unblock-write
+func (stream *TransformStream) unblockWrite() {
+	// 1. If stream.[[backpressure]] is true, perform ! TransformStreamSetBackpressure(stream, false).
+	if stream.backpressure {
+		stream.setBackpressure(false)
+	}
+}
+
+// readableController returns the controller of the readable side of the stream.
+func (stream *TransformStream) readableController() *ReadableStreamDefaultController {
+	controller, ok := stream.readable.controller.(*ReadableStreamDefaultController)
+	if !ok {
+		common.Throw(stream.runtime, newError(AssertionError, "the readable side has no default controller"))
+	}
+
+	return controller
+}
+
+// sinkWriteAlgorithm implements the specification's [TransformStreamDefaultSinkWriteAlgorithm]
+// abstract operation.
+//
+// [TransformStreamDefaultSinkWriteAlgorithm]: https://streams.spec.whatwg.org/#transform-stream-default-sink-write-algorithm
+func (stream *TransformStream) sinkWriteAlgorithm(chunk sobek.Value) *sobek.Promise {
+	rt := stream.runtime
+
+	// 1. Assert: stream.[[writable]].[[state]] is "writable".
+	// 2. Let controller be stream.[[controller]].
+	controller := stream.controller
+
+	// 3. If stream.[[backpressure]] is true,
+	if stream.backpressure {
+		// 3.1. Let backpressureChangePromise be stream.[[backpressureChangePromise]].
+		// 3.2. Assert: backpressureChangePromise is not undefined.
+		backpressureChangePromise := stream.backpressureChangePromise
+
+		// 3.3. Return the result of reacting to backpressureChangePromise with the following fulfillment steps:
+		promise := newPromiseCapability(rt)
+		uponPromise(rt, backpressureChangePromise.promise, func(sobek.Value) {
+			// 3.3.1. Let writable be stream.[[writable]].
+			writable := stream.writable
+
+			// 3.3.2. Let state be writable.[[state]].
+			// 3.3.3. If state is "erroring", throw writable.[[storedError]].
+			if writable.state == WritableStreamStateErroring {
+				promise.reject(writable.storedErrorValue())
+				return
+			}
+
+			// 3.3.4. Assert: state is "writable".
+			// 3.3.5. Return ! TransformStreamDefaultControllerPerformTransform(controller, chunk).
+			uponPromise(rt, controller.performTransform(chunk),
+				func(v sobek.Value) { promise.resolve(v) },
+				func(r sobek.Value) { promise.reject(r) },
+			)
+		}, func(r sobek.Value) { promise.reject(r) })
+
+		return promise.promise
+	}
+
+	// 4. Return ! TransformStreamDefaultControllerPerformTransform(controller, chunk).
+	return controller.performTransform(chunk)
+}
+
+// sinkAbortAlgorithm implements the specification's [TransformStreamDefaultSinkAbortAlgorithm]
+// abstract operation.
+//
+// [TransformStreamDefaultSinkAbortAlgorithm]: https://streams.spec.whatwg.org/#transform-stream-default-sink-abort-algorithm
+func (stream *TransformStream) sinkAbortAlgorithm(reason sobek.Value) *sobek.Promise {
+	rt := stream.runtime
+
+	// 1. Let controller be stream.[[controller]].
+	controller := stream.controller
+
+	// 2. If controller.[[finishPromise]] is not undefined, return controller.[[finishPromise]].
+	if controller.finishPromise != nil {
+		return controller.finishPromise.promise
+	}
+
+	// 3. Let readable be stream.[[readable]].
+	readable := stream.readable
+
+	// 4. Let controller.[[finishPromise]] be a new promise.
+	finishPromise := newPromiseCapability(rt)
+	controller.finishPromise = finishPromise
+
+	// 5. Let cancelPromise be the result of performing controller.[[cancelAlgorithm]], passing reason.
+	cancelPromise := controller.cancelAlgorithm(reason)
+
+	// 6. Perform ! TransformStreamDefaultControllerClearAlgorithms(controller).
+	controller.clearAlgorithms()
+
+	// 7. React to cancelPromise:
+	uponPromise(rt, cancelPromise,
+		// 7.1. If cancelPromise was fulfilled, then:
+		func(sobek.Value) {
+			// 7.1.1. If readable.[[state]] is "errored", reject controller.[[finishPromise]] with
+			// readable.[[storedError]].
+			if readable.state == ReadableStreamStateErrored {
+				finishPromise.reject(readable.storedError)
+				return
+			}
+
+			// 7.1.2. Otherwise:
+			// 7.1.2.1. Perform ! ReadableStreamDefaultControllerError(readable.[[controller]], reason).
+			stream.readableController().error(reason)
+			// 7.1.2.2. Resolve controller.[[finishPromise]] with undefined.
+			finishPromise.resolve(sobek.Undefined())
+		},
+		// 7.2. If cancelPromise was rejected with reason r, then:
+		func(r sobek.Value) {
+			// 7.2.1. Perform ! ReadableStreamDefaultControllerError(readable.[[controller]], r).
+			stream.readableController().error(r)
+			// 7.2.2. Reject controller.[[finishPromise]] with r.
+			finishPromise.reject(r)
+		},
+	)
+
+	// 8. Return controller.[[finishPromise]].
+	return finishPromise.promise
+}
+
+// sinkCloseAlgorithm implements the specification's [TransformStreamDefaultSinkCloseAlgorithm]
+// abstract operation.
+//
+// [TransformStreamDefaultSinkCloseAlgorithm]: https://streams.spec.whatwg.org/#transform-stream-default-sink-close-algorithm
+func (stream *TransformStream) sinkCloseAlgorithm() *sobek.Promise {
+	rt := stream.runtime
+
+	// 1. Let controller be stream.[[controller]].
+	controller := stream.controller
+
+	// 2. If controller.[[finishPromise]] is not undefined, return controller.[[finishPromise]].
+	if controller.finishPromise != nil {
+		return controller.finishPromise.promise
+	}
+
+	// 3. Let readable be stream.[[readable]].
+	readable := stream.readable
+
+	// 4. Let controller.[[finishPromise]] be a new promise.
+	finishPromise := newPromiseCapability(rt)
+	controller.finishPromise = finishPromise
+
+	// 5. Let flushPromise be the result of performing controller.[[flushAlgorithm]].
+	flushPromise := controller.flushAlgorithm()
+
+	// 6. Perform ! TransformStreamDefaultControllerClearAlgorithms(controller).
+	controller.clearAlgorithms()
+
+	// 7. React to flushPromise:
+	uponPromise(rt, flushPromise,
+		// 7.1. If flushPromise was fulfilled, then:
+		func(sobek.Value) {
+			// 7.1.1. If readable.[[state]] is "errored", reject controller.[[finishPromise]] with
+			// readable.[[storedError]].
+			if readable.state == ReadableStreamStateErrored {
+				finishPromise.reject(readable.storedError)
+				return
+			}
+
+			// 7.1.2. Otherwise:
+			// 7.1.2.1. Perform ! ReadableStreamDefaultControllerClose(readable.[[controller]]).
+			stream.readableController().close()
+			// 7.1.2.2. Resolve controller.[[finishPromise]] with undefined.
+			finishPromise.resolve(sobek.Undefined())
+		},
+		// 7.2. If flushPromise was rejected with reason r, then:
+		func(r sobek.Value) {
+			// 7.2.1. Perform ! ReadableStreamDefaultControllerError(readable.[[controller]], r).
+			stream.readableController().error(r)
+			// 7.2.2. Reject controller.[[finishPromise]] with r.
+			finishPromise.reject(r)
+		},
+	)
+
+	// 8. Return controller.[[finishPromise]].
+	return finishPromise.promise
+}
+
+// sourcePullAlgorithm implements the specification's [TransformStreamDefaultSourcePullAlgorithm]
+// abstract operation.
+//
+// [TransformStreamDefaultSourcePullAlgorithm]: https://streams.spec.whatwg.org/#transform-stream-default-source-pull
+func (stream *TransformStream) sourcePullAlgorithm() *sobek.Promise {
+	// 1. Assert: stream.[[backpressure]] is true.
+	// 2. Assert: stream.[[backpressureChangePromise]] is not undefined.
+	if !stream.backpressure || stream.backpressureChangePromise == nil {
+		common.Throw(stream.runtime, newError(AssertionError, "the stream has no backpressure"))
+	}
+
+	// 3. Perform ! TransformStreamSetBackpressure(stream, false).
+	stream.setBackpressure(false)
+
+	// 4. Return stream.[[backpressureChangePromise]].
+	return stream.backpressureChangePromise.promise
+}
+
+// sourceCancelAlgorithm implements the specification's [TransformStreamDefaultSourceCancelAlgorithm]
+// abstract operation.
+//
+// [TransformStreamDefaultSourceCancelAlgorithm]: https://streams.spec.whatwg.org/#transform-stream-default-source-cancel
+func (stream *TransformStream) sourceCancelAlgorithm(reason sobek.Value) *sobek.Promise {
+	rt := stream.runtime
+
+	// 1. Let controller be stream.[[controller]].
+	controller := stream.controller
+
+	// 2. If controller.[[finishPromise]] is not undefined, return controller.[[finishPromise]].
+	if controller.finishPromise != nil {
+		return controller.finishPromise.promise
+	}
+
+	// 3. Let writable be stream.[[writable]].
+	writable := stream.writable
+
+	// 4. Let controller.[[finishPromise]] be a new promise.
+	finishPromise := newPromiseCapability(rt)
+	controller.finishPromise = finishPromise
+
+	// 5. Let cancelPromise be the result of performing controller.[[cancelAlgorithm]], passing reason.
+	cancelPromise := controller.cancelAlgorithm(reason)
+
+	// 6. Perform ! TransformStreamDefaultControllerClearAlgorithms(controller).
+	controller.clearAlgorithms()
+
+	// 7. React to cancelPromise:
+	uponPromise(rt, cancelPromise,
+		// 7.1. If cancelPromise was fulfilled, then:
+		func(sobek.Value) {
+			// 7.1.1. If writable.[[state]] is "errored", reject controller.[[finishPromise]] with
+			// writable.[[storedError]].
+			if writable.state == WritableStreamStateErrored {
+				finishPromise.reject(writable.storedErrorValue())
+				return
+			}
+
+			// 7.1.2. Otherwise:
+			// 7.1.2.1. Perform ! WritableStreamDefaultControllerErrorIfNeeded(writable.[[controller]], reason).
+			writable.controller.errorIfNeeded(reason)
+			// 7.1.2.2. Perform ! TransformStreamUnblockWrite(stream).
+			stream.unblockWrite()
+			// 7.1.2.3. Resolve controller.[[finishPromise]] with undefined.
+			finishPromise.resolve(sobek.Undefined())
+		},
+		// 7.2. If cancelPromise was rejected with reason r, then:
+		func(r sobek.Value) {
+			// 7.2.1. Perform ! WritableStreamDefaultControllerErrorIfNeeded(writable.[[controller]], r).
+			writable.controller.errorIfNeeded(r)
+			// 7.2.2. Perform ! TransformStreamUnblockWrite(stream).
+			stream.unblockWrite()
+			// 7.2.3. Reject controller.[[finishPromise]] with r.
+			finishPromise.reject(r)
+		},
+	)
+
+	// 8. Return controller.[[finishPromise]].
+	return finishPromise.promise
+}
+
+// newTransformStreamFromAlgorithms implements the specification's [set up a TransformStream]
+// algorithm, used by the other specifications to create transform streams from Go algorithms.
+//
+// The algorithms may throw, in which case the stream is errored with the thrown exception.
+//
+// [set up a TransformStream]: https://streams.spec.whatwg.org/#transformstream-set-up
+func (mi *ModuleInstance) newTransformStreamFromAlgorithms(
+	transformAlgorithm TransformerTransformCallback,
+	flushAlgorithm TransformerFlushCallback,
+	cancelAlgorithm TransformerCancelCallback,
+) *TransformStream {
+	rt := mi.vu.Runtime()
+
+	// 1. Let writableHighWaterMark be 1.
+	// 2. Let writableSizeAlgorithm be an algorithm that returns 1.
+	// 3. Let readableHighWaterMark be 0.
+	// 4. Let readableSizeAlgorithm be an algorithm that returns 1.
+	sizeAlgorithm := extractSizeAlgorithm(rt, rt.NewObject())
+
+	// 5. Let transformAlgorithmWrapper be an algorithm that runs these steps given a value chunk:
+	transformAlgorithmWrapper := func(chunk sobek.Value) *sobek.Promise {
+		var result *sobek.Promise
+		// 5.1. Let result be the result of running transformAlgorithm given chunk.
+		// 5.2. If result is a Promise, then return result.
+		// 5.3. Return a promise resolved with undefined.
+		if ex := rt.Try(func() { result = transformAlgorithm(chunk) }); ex != nil {
+			return newRejectedPromise(mi.vu, ex.Value())
+		}
+		return result
+	}
+
+	// 6. Let flushAlgorithmWrapper be an algorithm that runs these steps:
+	flushAlgorithmWrapper := func() *sobek.Promise {
+		var result *sobek.Promise
+		// 6.1. Let result be the result of running flushAlgorithm, if flushAlgorithm was given, or null otherwise.
+		// 6.2. If result is a Promise, then return result.
+		// 6.3. Return a promise resolved with undefined.
+		if ex := rt.Try(func() { result = flushAlgorithm() }); ex != nil {
+			return newRejectedPromise(mi.vu, ex.Value())
+		}
+		return result
+	}
+
+	// 7. Let cancelAlgorithmWrapper be an algorithm that runs these steps given a value reason:
+	cancelAlgorithmWrapper := func(reason sobek.Value) *sobek.Promise {
+		if cancelAlgorithm == nil {
+			return newResolvedPromise(mi.vu, sobek.Undefined())
+		}
+
+		var result *sobek.Promise
+		// 7.1. Let result be the result of running cancelAlgorithm given reason, if cancelAlgorithm was given,
+		// or null otherwise.
+		// 7.2. If result is a Promise, then return result.
+		// 7.3. Return a promise resolved with undefined.
+		if ex := rt.Try(func() { result = cancelAlgorithm(reason) }); ex != nil {
+			return newRejectedPromise(mi.vu, ex.Value())
+		}
+		return result
+	}
+
+	// 8. Let startPromise be a promise resolved with undefined.
+	startPromise := newResolvedPromise(mi.vu, sobek.Undefined())
+
+	// 9. Perform ! InitializeTransformStream(stream, startPromise, writableHighWaterMark, writableSizeAlgorithm,
+	// readableHighWaterMark, readableSizeAlgorithm).
+	stream := &TransformStream{runtime: rt, vu: mi.vu}
+	stream.initialize(
+		mi.prototypeOf("ReadableStream"),
+		mi.prototypeOf("WritableStream"),
+		startPromise,
+		1,
+		sizeAlgorithm,
+		0,
+		sizeAlgorithm,
+	)
+
+	// 10. Let controller be a new TransformStreamDefaultController.
+	controller := &TransformStreamDefaultController{}
+
+	// 11. Perform ! SetUpTransformStreamDefaultController(stream, controller, transformAlgorithmWrapper,
+	// flushAlgorithmWrapper, cancelAlgorithmWrapper).
+	stream.setupDefaultController(controller, transformAlgorithmWrapper, flushAlgorithmWrapper, cancelAlgorithmWrapper)
+
+	return stream
+}
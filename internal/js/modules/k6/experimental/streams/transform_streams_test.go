@@ -0,0 +1,105 @@
+package streams
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransformStream(t *testing.T) {
+	t.Parallel()
+
+	t.Run("transform and flush", func(t *testing.T) {
+		t.Parallel()
+
+		got := runAsyncScript(t, `async () => {
+  const ts = new TransformStream({
+    transform(chunk, controller) { controller.enqueue(chunk.toUpperCase()); },
+    flush(controller) { controller.enqueue("!"); },
+  });
+  const writer = ts.writable.getWriter();
+  writer.write("a");
+  writer.write("b");
+  writer.close();
+  const reader = ts.readable.getReader();
+  const chunks = [];
+  while (true) {
+    const { value, done } = await reader.read();
+    if (done) {
+      return chunks.join("");
+    }
+    chunks.push(value);
+  }
+}`)
+		assert.Equal(t, "AB!", got.String())
+	})
+
+	t.Run("identity", func(t *testing.T) {
+		t.Parallel()
+
+		got := runAsyncScript(t, `async () => {
+  const ts = new TransformStream();
+  const writer = ts.writable.getWriter();
+  writer.write(42);
+  const { value } = await ts.readable.getReader().read();
+  return value;
+}`)
+		assert.Equal(t, int64(42), got.ToInteger())
+	})
+
+	t.Run("terminate", func(t *testing.T) {
+		t.Parallel()
+
+		got := runAsyncScript(t, `async () => {
+  const ts = new TransformStream({
+    transform(chunk, controller) {
+      controller.enqueue(chunk);
+      controller.terminate();
+    },
+  });
+  const writer = ts.writable.getWriter();
+  const reader = ts.readable.getReader();
+  writer.write("a");
+  const first = await reader.read();
+  const second = await reader.read();
+  try {
+    await writer.write("b");
+  } catch (e) {
+    return [first.value, second.done, e.name].join(",");
+  }
+}`)
+		assert.Equal(t, "a,true,TypeError", got.String())
+	})
+
+	t.Run("invalid transformer", func(t *testing.T) {
+		t.Parallel()
+
+		got := runAsyncScript(t, `async () => {
+  try {
+    new TransformStream({ readableType: "bytes" });
+  } catch (e) {
+    return e.name;
+  }
+}`)
+		assert.Equal(t, "RangeError", got.String())
+	})
+}
+
+func TestTransformStreamWebPlatformTests(t *testing.T) {
+	t.Parallel()
+
+	// As for the readable streams, the tests patching the globals aren"t included.
+	suites := []string{
+		"backpressure.any.js",
+		"errors.any.js",
+		"flush.any.js",
+		"general.any.js",
+		"lipfuzz.any.js",
+		"properties.any.js",
+		"reentrant-strategies.any.js",
+		"strategies.any.js",
+		"terminate.any.js",
+	}
+
+	runWebPlatformTests(t, "streams/transform-streams", suites)
+}
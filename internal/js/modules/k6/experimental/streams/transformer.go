@@ -0,0 +1,89 @@
+package streams
+
+import (
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/js/common"
+)
+
+// Transformer represents the transformer of a TransformStream, and defines how the chunks
+// written to the writable side of the stream are transformed into the chunks read from its
+// readable side.
+//
+// [specification]: https://streams.spec.whatwg.org/#dictdef-transformer
+type Transformer struct {
+	// Start is called immediately during the creation of a TransformStream.
+	//
+	// Typically, this is used to enqueue prefix chunks. If the setup process is asynchronous,
+	// it can return a Promise to signal success or failure.
+	Start sobek.Value
+
+	// Transform is called when a new chunk originally written to the writable side is ready
+	// to be transformed.
+	//
+	// If no transform() method is supplied, the identity transform is used, which enqueues
+	// chunks unchanged from the writable side to the readable side.
+	Transform sobek.Value
+
+	// Flush is called after all chunks written to the writable side have been transformed by
+	// successfully passing through transform(), and the writable side is about to be closed.
+	Flush sobek.Value
+
+	// Cancel is called when the readable side is cancelled, or the writable side is aborted.
+	Cancel sobek.Value
+}
+
+// TransformerStartCallback is a function that is called immediately during the creation of a TransformStream.
+type TransformerStartCallback func(controller *sobek.Object) sobek.Value
+
+// TransformerTransformCallback is a function that is called when a new chunk is ready to be transformed.
+type TransformerTransformCallback func(chunk sobek.Value) *sobek.Promise
+
+// TransformerFlushCallback is a function that is called once all the chunks written to the
+// writable side have been transformed.
+type TransformerFlushCallback func() *sobek.Promise
+
+// TransformerCancelCallback is a function that is called when the readable side is cancelled, or the
+// writable side is aborted.
+type TransformerCancelCallback func(reason sobek.Value) *sobek.Promise
+
+// NewTransformerFromObject creates a new Transformer from a sobek.Object.
+func NewTransformerFromObject(rt *sobek.Runtime, obj *sobek.Object) (Transformer, error) {
+	var transformer Transformer
+
+	if common.IsNullish(obj) {
+		// If the user didn't provide a transformer, use the identity one.
+		return transformer, nil
+	}
+
+	// The members are converted in the lexicographical order of their names,
+	// as specified by Web IDL for dictionaries.
+	members := []struct {
+		name  string
+		value *sobek.Value
+	}{
+		{"cancel", &transformer.Cancel},
+		{"flush", &transformer.Flush},
+		{"start", &transformer.Start},
+		{"transform", &transformer.Transform},
+	}
+	for _, member := range members {
+		value := obj.Get(member.name)
+		if common.IsNullish(value) {
+			continue
+		}
+		if _, isFunc := sobek.AssertFunction(value); !isFunc {
+			return transformer, newTypeError(rt, "transformer."+member.name+" must be a function")
+		}
+		*member.value = value
+	}
+
+	// The readableType and writableType members are reserved for future use, and they must not be set.
+	for _, member := range []string{"readableType", "writableType"} {
+		if v := obj.Get(member); v != nil && !sobek.IsUndefined(v) {
+			return transformer, newRangeError(rt, "invalid transformer "+member)
+		}
+	}
+
+	return transformer, nil
+}
@@ -0,0 +1,89 @@
+package streams
+
+import (
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/js/common"
+)
+
+// UnderlyingSink represents the underlying sink of a WritableStream, and defines how
+// the data written to the stream is written to the underlying sink.
+//
+// [specification]: https://streams.spec.whatwg.org/#dictdef-underlyingsink
+type UnderlyingSink struct {
+	// Start is called immediately during the creation of a WritableStream.
+	//
+	// Typically, this is used to acquire access to the underlying sink resource being represented.
+	// If the setup process is asynchronous, it can return a Promise to signal success or
+	// failure; a rejected promise will error the stream.
+	Start sobek.Value
+
+	// Write is called when a new chunk of data is ready to be written to the underlying sink.
+	//
+	// The stream implementation guarantees that this function will be called only after previous
+	// writes have succeeded, and never before start() has succeeded or after close() or abort()
+	// have been called.
+	Write sobek.Value
+
+	// Close is called after the producer signals, via writer.close(), that they are done writing
+	// chunks to the stream, and subsequently all queued-up writes have successfully completed.
+	Close sobek.Value
+
+	// Abort is called after the producer signals, via stream.abort() or writer.abort(), that they
+	// wish to abort the stream. It takes as its argument the same value as was passed to those
+	// methods by the producer.
+	Abort sobek.Value
+}
+
+// UnderlyingSinkStartCallback is a function that is called immediately during the creation of a WritableStream.
+type UnderlyingSinkStartCallback func(controller *sobek.Object) sobek.Value
+
+// UnderlyingSinkWriteCallback is a function that is called when a new chunk of data is ready to be written
+// to the underlying sink.
+type UnderlyingSinkWriteCallback func(chunk sobek.Value, controller *sobek.Object) *sobek.Promise
+
+// UnderlyingSinkCloseCallback is a function that is called once all the queued-up writes have completed
+// after the producer closed the stream.
+type UnderlyingSinkCloseCallback func() *sobek.Promise
+
+// UnderlyingSinkAbortCallback is a function that is called when the producer aborts the stream.
+type UnderlyingSinkAbortCallback func(reason sobek.Value) *sobek.Promise
+
+// NewUnderlyingSinkFromObject creates a new UnderlyingSink from a sobek.Object.
+func NewUnderlyingSinkFromObject(rt *sobek.Runtime, obj *sobek.Object) (UnderlyingSink, error) {
+	var underlyingSink UnderlyingSink
+
+	if common.IsNullish(obj) {
+		// If the user didn't provide an underlying sink, use the default one.
+		return underlyingSink, nil
+	}
+
+	// The members are converted in the lexicographical order of their names,
+	// as specified by Web IDL for dictionaries.
+	members := []struct {
+		name  string
+		value *sobek.Value
+	}{
+		{"abort", &underlyingSink.Abort},
+		{"close", &underlyingSink.Close},
+		{"start", &underlyingSink.Start},
+		{"write", &underlyingSink.Write},
+	}
+	for _, member := range members {
+		value := obj.Get(member.name)
+		if common.IsNullish(value) {
+			continue
+		}
+		if _, isFunc := sobek.AssertFunction(value); !isFunc {
+			return underlyingSink, newTypeError(rt, "underlyingSink."+member.name+" must be a function")
+		}
+		*member.value = value
+	}
+
+	// The type member is reserved for future use, and it must not be set.
+	if !sobek.IsUndefined(obj.Get("type")) && obj.Get("type") != nil {
+		return underlyingSink, newRangeError(rt, "invalid underlying sink type")
+	}
+
+	return underlyingSink, nil
+}
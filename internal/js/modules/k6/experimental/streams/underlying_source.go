@@ -95,6 +95,15 @@ func NewUnderlyingSourceFromObject(rt *sobek.Runtime, obj *sobek.Object) (Underl
 		return underlyingSource, newTypeError(rt, "invalid underlying source object")
 	}
 
+	// The dictionary member is camel-cased, so it isn't mapped by ExportTo.
+	if autoAllocateChunkSize := obj.Get("autoAllocateChunkSize"); !common.IsNullish(autoAllocateChunkSize) {
+		size := autoAllocateChunkSize.ToFloat()
+		if size < 0 || size != float64(int64(size)) {
+			return underlyingSource, newTypeError(rt, "autoAllocateChunkSize must be a non-negative integer")
+		}
+		underlyingSource.AutoAllocateChunkSize = null.IntFrom(int64(size))
+	}
+
 	if underlyingSource.Start != nil {
 		underlyingSource.startSet = true
 	}
@@ -0,0 +1,433 @@
+package streams
+
+import (
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/js/common"
+)
+
+// WritableStreamDefaultController is the default controller for a [WritableStream]. It has
+// methods to control the stream's state, and it's handed to the underlying sink.
+//
+// For more details, see the [specification].
+//
+// [specification]: https://streams.spec.whatwg.org/#ws-default-controller-class
+type WritableStreamDefaultController struct {
+	// abortAlgorithm is a promise-returning algorithm, taking one argument (the abort reason),
+	// which communicates a requested abort to the underlying sink
+	abortAlgorithm UnderlyingSinkAbortCallback
+
+	// abortController is the AbortController instance whose signal is exposed to the underlying
+	// sink, allowing it to abort its pending operations when the stream is aborted
+	abortController *sobek.Object
+
+	// closeAlgorithm is a promise-returning algorithm which communicates a requested close to
+	// the underlying sink
+	closeAlgorithm UnderlyingSinkCloseCallback
+
+	// queue is a list representing the stream's internal queue of chunks, in which the close
+	// request is represented by a nil value
+	queue *QueueWithSizes
+
+	// started is a boolean flag indicating whether the underlying sink has finished starting
+	started bool
+
+	// strategyHWM is a number supplied by the creator of the stream as part of the stream's
+	// queuing strategy, indicating the point at which the stream will apply backpressure to
+	// its underlying sink
+	strategyHWM float64
+
+	// strategySizeAlgorithm is an algorithm to calculate the size of enqueued chunks, as part
+	// of the stream's queuing strategy
+	strategySizeAlgorithm SizeAlgorithm
+
+	// stream is the writable stream that this controller controls
+	stream *WritableStream
+
+	// writeAlgorithm is a promise-returning algorithm, taking one argument (the chunk to write),
+	// which writes data to the underlying sink
+	writeAlgorithm UnderlyingSinkWriteCallback
+
+	// obj is the JS object representing the controller, handed to the underlying sink
+	obj *sobek.Object
+}
+
+// NewWritableStreamDefaultControllerObject creates a new [sobek.Object] from a
+// [WritableStreamDefaultController] instance.
+func NewWritableStreamDefaultControllerObject(controller *WritableStreamDefaultController) (*sobek.Object, error) {
+	rt := controller.stream.runtime
+	obj := rt.NewObject()
+	objName := "WritableStreamDefaultController"
+
+	err := obj.DefineAccessorProperty("signal", rt.ToValue(func() sobek.Value {
+		if controller.abortController == nil {
+			return sobek.Undefined()
+		}
+		return controller.abortController.Get("signal")
+	}), nil, sobek.FLAG_FALSE, sobek.FLAG_TRUE)
+	if err != nil {
+		return nil, err
+	}
+
+	// Exposing the properties of the [WritableStreamDefaultController] interface
+	if err := setReadOnlyPropertyOf(obj, objName, "error", rt.ToValue(controller.Error)); err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+// Error errors the stream, making all future interactions with it fail with the given error.
+//
+// It implements the WritableStreamDefaultController.error(e) [specification] algorithm.
+//
+// [specification]: https://streams.spec.whatwg.org/#ws-default-controller-error
+func (controller *WritableStreamDefaultController) Error(e sobek.Value) {
+	if e == nil {
+		e = sobek.Undefined()
+	}
+
+	// 1. Let state be this.[[stream]].[[state]].
+	// 2. If state is not "writable", return.
+	if controller.stream.state != WritableStreamStateWritable {
+		return
+	}
+
+	// 3. Perform ! WritableStreamDefaultControllerError(this, e).
+	controller.error(e)
+}
+
+// abortSteps implements the controller's [[AbortSteps]](reason) internal method.
+//
+// [[AbortSteps]]: https://streams.spec.whatwg.org/#ws-default-controller-private-abort
+func (controller *WritableStreamDefaultController) abortSteps(reason sobek.Value) *sobek.Promise {
+	// 1. Let result be the result of performing this.[[abortAlgorithm]], passing reason.
+	result := controller.abortAlgorithm(reason)
+
+	// 2. Perform ! WritableStreamDefaultControllerClearAlgorithms(this).
+	controller.clearAlgorithms()
+
+	// 3. Return result.
+	return result
+}
+
+// errorSteps implements the controller's [[ErrorSteps]]() internal method.
+//
+// [[ErrorSteps]]: https://streams.spec.whatwg.org/#ws-default-controller-private-error
+func (controller *WritableStreamDefaultController) errorSteps() {
+	// 1. Perform ! ResetQueue(this).
+	controller.queue.Reset()
+}
+
+// signalAbort signals abort on the controller's abort controller with the given reason.
+func (controller *WritableStreamDefaultController) signalAbort(reason sobek.Value) {
+	if controller.abortController == nil {
+		return
+	}
+
+	abort, ok := sobek.AssertFunction(controller.abortController.Get("abort"))
+	if !ok {
+		return
+	}
+
+	if _, err := abort(controller.abortController, reason); err != nil {
+		common.Throw(controller.stream.runtime, err)
+	}
+}
+
+// toObject returns the [sobek.Object] representing the controller, creating it on first use.
+func (controller *WritableStreamDefaultController) toObject() (*sobek.Object, error) {
+	if controller.obj != nil {
+		return controller.obj, nil
+	}
+
+	obj, err := NewWritableStreamDefaultControllerObject(controller)
+	if err != nil {
+		return nil, err
+	}
+	controller.obj = obj
+
+	return obj, nil
+}
+
+// advanceQueueIfNeeded implements the specification's [WritableStreamDefaultControllerAdvanceQueueIfNeeded]
+// abstract operation.
+//
+// [WritableStreamDefaultControllerAdvanceQueueIfNeeded]: https://streams.spec.whatwg.org/#writable-stream-default-controller-advance-queue-if-needed
+func (controller *WritableStreamDefaultController) advanceQueueIfNeeded() {
+	// 1. Let stream be controller.[[stream]].
+	stream := controller.stream
+
+	// 2. If controller.[[started]] is false, return.
+	if !controller.started {
+		return
+	}
+
+	// 3. If stream.[[inFlightWriteRequest]] is not undefined, return.
+	if stream.inFlightWriteRequest != nil {
+		return
+	}
+
+	// 4. Let state be stream.[[state]].
+	state := stream.state
+
+	// 5. Assert: state is not "closed" or "errored".
+	// 6. If state is "erroring",
+	if state == WritableStreamStateErroring {
+		// 6.1. Perform ! WritableStreamFinishErroring(stream).
+		stream.finishErroring()
+		// 6.2. Return.
+		return
+	}
+
+	// 7. If controller.[[queue]] is empty, return.
+	if controller.queue.Len() == 0 {
+		return
+	}
+
+	// 8. Let value be ! PeekQueueValue(controller).
+	value, err := controller.queue.Peek()
+	if err != nil {
+		common.Throw(stream.runtime, newError(AssertionError, err.Error()))
+	}
+
+	// 9. If value is the close sentinel, perform ! WritableStreamDefaultControllerProcessClose(controller).
+	if value == nil {
+		controller.processClose()
+		return
+	}
+
+	// 10. Otherwise, perform ! WritableStreamDefaultControllerProcessWrite(controller, value).
+	controller.processWrite(value)
+}
+
+// clearAlgorithms implements the specification's [WritableStreamDefaultControllerClearAlgorithms]
+// abstract operation.
+//
+// [WritableStreamDefaultControllerClearAlgorithms]: https://streams.spec.whatwg.org/#writable-stream-default-controller-clear-algorithms
+func (controller *WritableStreamDefaultController) clearAlgorithms() {
+	// 1. Set controller.[[writeAlgorithm]] to undefined.
+	controller.writeAlgorithm = nil
+
+	// 2. Set controller.[[closeAlgorithm]] to undefined.
+	controller.closeAlgorithm = nil
+
+	// 3. Set controller.[[abortAlgorithm]] to undefined.
+	controller.abortAlgorithm = nil
+
+	// 4. Set controller.[[strategySizeAlgorithm]] to undefined.
+	controller.strategySizeAlgorithm = nil
+}
+
+// close implements the specification's [WritableStreamDefaultControllerClose] abstract operation.
+//
+// [WritableStreamDefaultControllerClose]: https://streams.spec.whatwg.org/#writable-stream-default-controller-close
+func (controller *WritableStreamDefaultController) close() {
+	// 1. Perform ! EnqueueValueWithSize(controller, close sentinel, 0).
+	if err := controller.queue.Enqueue(nil, 0); err != nil {
+		common.Throw(controller.stream.runtime, err)
+	}
+
+	// 2. Perform ! WritableStreamDefaultControllerAdvanceQueueIfNeeded(controller).
+	controller.advanceQueueIfNeeded()
+}
+
+// error implements the specification's [WritableStreamDefaultControllerError] abstract operation.
+//
+// [WritableStreamDefaultControllerError]: https://streams.spec.whatwg.org/#writable-stream-default-controller-error
+func (controller *WritableStreamDefaultController) error(e sobek.Value) {
+	// 1. Let stream be controller.[[stream]].
+	// 2. Assert: stream.[[state]] is "writable".
+	// 3. Perform ! WritableStreamDefaultControllerClearAlgorithms(controller).
+	controller.clearAlgorithms()
+
+	// 4. Perform ! WritableStreamStartErroring(stream, error).
+	controller.stream.startErroring(e)
+}
+
+// errorIfNeeded implements the specification's [WritableStreamDefaultControllerErrorIfNeeded]
+// abstract operation.
+//
+// [WritableStreamDefaultControllerErrorIfNeeded]: https://streams.spec.whatwg.org/#writable-stream-default-controller-error-if-needed
+func (controller *WritableStreamDefaultController) errorIfNeeded(e sobek.Value) {
+	// 1. If controller.[[stream]].[[state]] is "writable", perform ! WritableStreamDefaultControllerError(controller, error).
+	if controller.stream.state == WritableStreamStateWritable {
+		controller.error(e)
+	}
+}
+
+// getBackpressure implements the specification's [WritableStreamDefaultControllerGetBackpressure]
+// abstract operation.
+//
+// [WritableStreamDefaultControllerGetBackpressure]: https://streams.spec.whatwg.org/#writable-stream-default-controller-get-backpressure
+func (controller *WritableStreamDefaultController) getBackpressure() bool {
+	// 1. Let desiredSize be ! WritableStreamDefaultControllerGetDesiredSize(controller).
+	// 2. Return true if desiredSize ≤ 0, or false otherwise.
+	return controller.getDesiredSize() <= 0
+}
+
+// getChunkSize implements the specification's [WritableStreamDefaultControllerGetChunkSize]
+// abstract operation.
+//
+// [WritableStreamDefaultControllerGetChunkSize]: https://streams.spec.whatwg.org/#writable-stream-default-controller-get-chunk-size
+func (controller *WritableStreamDefaultController) getChunkSize(chunk sobek.Value) float64 {
+	// 1. If controller.[[strategySizeAlgorithm]] is undefined, then:
+	if controller.strategySizeAlgorithm == nil {
+		// 1.1. Assert: controller.[[stream]].[[state]] is not "writable".
+		// 1.2. Return 1.
+		return 1
+	}
+
+	// 2. Let returnValue be the result of performing controller.[[strategySizeAlgorithm]], passing in chunk,
+	// and interpreting the result as a completion record.
+	returnValue, err := controller.strategySizeAlgorithm(sobek.Undefined(), chunk)
+
+	// 3. If returnValue is an abrupt completion,
+	if err != nil {
+		// 3.1. Perform ! WritableStreamDefaultControllerErrorIfNeeded(controller, returnValue.[[Value]]).
+		controller.errorIfNeeded(controller.stream.runtime.ToValue(rejectionReason(err)))
+		// 3.2. Return 1.
+		return 1
+	}
+
+	// 4. Return returnValue.[[Value]].
+	return returnValue.ToFloat()
+}
+
+// getDesiredSize implements the specification's [WritableStreamDefaultControllerGetDesiredSize]
+// abstract operation.
+//
+// [WritableStreamDefaultControllerGetDesiredSize]: https://streams.spec.whatwg.org/#writable-stream-default-controller-get-desired-size
+func (controller *WritableStreamDefaultController) getDesiredSize() float64 {
+	// 1. Return controller.[[strategyHWM]] − controller.[[queueTotalSize]].
+	return controller.strategyHWM - controller.queue.QueueTotalSize
+}
+
+// processClose implements the specification's [WritableStreamDefaultControllerProcessClose]
+// abstract operation.
+//
+// [WritableStreamDefaultControllerProcessClose]: https://streams.spec.whatwg.org/#writable-stream-default-controller-process-close
+func (controller *WritableStreamDefaultController) processClose() {
+	rt := controller.stream.runtime
+
+	// 1. Let stream be controller.[[stream]].
+	stream := controller.stream
+
+	// 2. Perform ! WritableStreamMarkCloseRequestInFlight(stream).
+	stream.markCloseRequestInFlight()
+
+	// 3. Perform ! DequeueValue(controller).
+	// 4. Assert: controller.[[queue]] is empty.
+	if _, err := controller.queue.Dequeue(); err != nil {
+		common.Throw(rt, err)
+	}
+
+	// 5. Let sinkClosePromise be the result of performing controller.[[closeAlgorithm]].
+	sinkClosePromise := controller.closeAlgorithm()
+
+	// 6. Perform ! WritableStreamDefaultControllerClearAlgorithms(controller).
+	controller.clearAlgorithms()
+
+	uponPromise(rt, sinkClosePromise,
+		// 7. Upon fulfillment of sinkClosePromise,
+		func(sobek.Value) {
+			// 7.1. Perform ! WritableStreamFinishInFlightClose(stream).
+			stream.finishInFlightClose()
+		},
+		// 8. Upon rejection of sinkClosePromise with reason reason,
+		func(reason sobek.Value) {
+			// 8.1. Perform ! WritableStreamFinishInFlightCloseWithError(stream, reason).
+			stream.finishInFlightCloseWithError(reason)
+		},
+	)
+}
+
+// processWrite implements the specification's [WritableStreamDefaultControllerProcessWrite]
+// abstract operation.
+//
+// [WritableStreamDefaultControllerProcessWrite]: https://streams.spec.whatwg.org/#writable-stream-default-controller-process-write
+func (controller *WritableStreamDefaultController) processWrite(chunk sobek.Value) {
+	rt := controller.stream.runtime
+
+	// 1. Let stream be controller.[[stream]].
+	stream := controller.stream
+
+	// 2. Perform ! WritableStreamMarkFirstWriteRequestInFlight(stream).
+	stream.markFirstWriteRequestInFlight()
+
+	controllerObj, err := controller.toObject()
+	if err != nil {
+		common.Throw(rt, newError(RuntimeError, err.Error()))
+	}
+
+	// 3. Let sinkWritePromise be the result of performing controller.[[writeAlgorithm]], passing in chunk.
+	sinkWritePromise := controller.writeAlgorithm(chunk, controllerObj)
+
+	uponPromise(rt, sinkWritePromise,
+		// 4. Upon fulfillment of sinkWritePromise,
+		func(sobek.Value) {
+			// 4.1. Perform ! WritableStreamFinishInFlightWrite(stream).
+			stream.finishInFlightWrite()
+
+			// 4.2. Let state be stream.[[state]].
+			state := stream.state
+
+			// 4.3. Assert: state is "writable" or "erroring".
+			// 4.4. Perform ! DequeueValue(controller).
+			if _, err := controller.queue.Dequeue(); err != nil {
+				common.Throw(rt, err)
+			}
+
+			// 4.5. If ! WritableStreamCloseQueuedOrInFlight(stream) is false and state is "writable",
+			if !stream.closeQueuedOrInFlight() && state == WritableStreamStateWritable {
+				// 4.5.1. Let backpressure be ! WritableStreamDefaultControllerGetBackpressure(controller).
+				backpressure := controller.getBackpressure()
+				// 4.5.2. Perform ! WritableStreamUpdateBackpressure(stream, backpressure).
+				stream.updateBackpressure(backpressure)
+			}
+
+			// 4.6. Perform ! WritableStreamDefaultControllerAdvanceQueueIfNeeded(controller).
+			controller.advanceQueueIfNeeded()
+		},
+		// 5. Upon rejection of sinkWritePromise with reason,
+		func(reason sobek.Value) {
+			// 5.1. If stream.[[state]] is "writable", perform ! WritableStreamDefaultControllerClearAlgorithms(controller).
+			if stream.state == WritableStreamStateWritable {
+				controller.clearAlgorithms()
+			}
+
+			// 5.2. Perform ! WritableStreamFinishInFlightWriteWithError(stream, reason).
+			stream.finishInFlightWriteWithError(reason)
+		},
+	)
+}
+
+// write implements the specification's [WritableStreamDefaultControllerWrite] abstract operation.
+//
+// [WritableStreamDefaultControllerWrite]: https://streams.spec.whatwg.org/#writable-stream-default-controller-write
+func (controller *WritableStreamDefaultController) write(chunk sobek.Value, chunkSize float64) {
+	// 1. Let enqueueResult be EnqueueValueWithSize(controller, chunk, chunkSize).
+	enqueueResult := controller.queue.Enqueue(chunk, chunkSize)
+
+	// 2. If enqueueResult is an abrupt completion,
+	if enqueueResult != nil {
+		// 2.1. Perform ! WritableStreamDefaultControllerErrorIfNeeded(controller, enqueueResult.[[Value]]).
+		controller.errorIfNeeded(controller.stream.runtime.ToValue(rejectionReason(enqueueResult)))
+		// 2.2. Return.
+		return
+	}
+
+	// 3. Let stream be controller.[[stream]].
+	stream := controller.stream
+
+	// 4. If ! WritableStreamCloseQueuedOrInFlight(stream) is false and stream.[[state]] is "writable",
+	if !stream.closeQueuedOrInFlight() && stream.state == WritableStreamStateWritable {
+		// 4.1. Let backpressure be ! WritableStreamDefaultControllerGetBackpressure(controller).
+		backpressure := controller.getBackpressure()
+		// 4.2. Perform ! WritableStreamUpdateBackpressure(stream, backpressure).
+		stream.updateBackpressure(backpressure)
+	}
+
+	// 5. Perform ! WritableStreamDefaultControllerAdvanceQueueIfNeeded(controller).
+	controller.advanceQueueIfNeeded()
+}
@@ -0,0 +1,423 @@
+package streams
+
+import (
+	"github.com/grafana/sobek"
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/js/common"
+	"go.k6.io/k6/js/modules"
+)
+
+// WritableStreamDefaultWriter represents a writable stream writer designed to be vended
+// by a [WritableStream] instance.
+//
+// For more details, see the [specification].
+//
+// [specification]: https://streams.spec.whatwg.org/#default-writer-class
+type WritableStreamDefaultWriter struct {
+	// closedPromise is a promise returned by the writer's closed getter
+	closedPromise *promiseCapability
+
+	// readyPromise is a promise returned by the writer's ready getter
+	readyPromise *promiseCapability
+
+	// stream is the [WritableStream] instance that owns this writer
+	stream *WritableStream
+
+	runtime *sobek.Runtime
+	vu      modules.VU
+}
+
+// NewWritableStreamDefaultWriterObject creates a new [sobek.Object] from a
+// [WritableStreamDefaultWriter] instance.
+func NewWritableStreamDefaultWriterObject(writer *WritableStreamDefaultWriter) (*sobek.Object, error) {
+	rt := writer.runtime
+	obj := rt.NewObject()
+	objName := "WritableStreamDefaultWriter"
+
+	err := obj.DefineAccessorProperty("closed", rt.ToValue(func() *sobek.Promise {
+		return writer.closedPromise.promise
+	}), nil, sobek.FLAG_FALSE, sobek.FLAG_TRUE)
+	if err != nil {
+		return nil, err
+	}
+
+	err = obj.DefineAccessorProperty("desiredSize", rt.ToValue(writer.GetDesiredSize), nil, sobek.FLAG_FALSE, sobek.FLAG_TRUE)
+	if err != nil {
+		return nil, err
+	}
+
+	err = obj.DefineAccessorProperty("ready", rt.ToValue(func() *sobek.Promise {
+		return writer.readyPromise.promise
+	}), nil, sobek.FLAG_FALSE, sobek.FLAG_TRUE)
+	if err != nil {
+		return nil, err
+	}
+
+	// Exposing the properties of the [WritableStreamDefaultWriter] interface
+	if err := setReadOnlyPropertyOf(obj, objName, "abort", rt.ToValue(writer.Abort)); err != nil {
+		return nil, err
+	}
+
+	if err := setReadOnlyPropertyOf(obj, objName, "close", rt.ToValue(writer.Close)); err != nil {
+		return nil, err
+	}
+
+	if err := setReadOnlyPropertyOf(obj, objName, "releaseLock", rt.ToValue(writer.ReleaseLock)); err != nil {
+		return nil, err
+	}
+
+	if err := setReadOnlyPropertyOf(obj, objName, "write", rt.ToValue(writer.Write)); err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+// GetDesiredSize returns the desired size to fill the stream's internal queue, or null
+// if the stream is errored or erroring.
+//
+// It implements the WritableStreamDefaultWriter.desiredSize [specification] getter.
+//
+// [specification]: https://streams.spec.whatwg.org/#default-writer-desired-size
+func (writer *WritableStreamDefaultWriter) GetDesiredSize() sobek.Value {
+	// 1. If this.[[stream]] is undefined, throw a TypeError exception.
+	if writer.stream == nil {
+		throw(writer.runtime, newTypeError(writer.runtime, "the writer has been released"))
+	}
+
+	// 2. Return ! WritableStreamDefaultWriterGetDesiredSize(this).
+	desiredSize := writer.getDesiredSize()
+	if !desiredSize.Valid {
+		return sobek.Null()
+	}
+
+	return writer.runtime.ToValue(desiredSize.Float64)
+}
+
+// Abort aborts the stream, signaling that the producer can no longer successfully write
+// to the stream.
+//
+// It implements the WritableStreamDefaultWriter.abort(reason) [specification] algorithm.
+//
+// [specification]: https://streams.spec.whatwg.org/#default-writer-abort
+func (writer *WritableStreamDefaultWriter) Abort(reason sobek.Value) *sobek.Promise {
+	// 1. If this.[[stream]] is undefined, return a promise rejected with a TypeError exception.
+	if writer.stream == nil {
+		return newRejectedPromise(writer.vu, newTypeError(writer.runtime, "the writer has been released").Err())
+	}
+
+	// 2. Return ! WritableStreamDefaultWriterAbort(this, reason).
+	return writer.stream.abort(reason)
+}
+
+// Close closes the stream, once all the previously written chunks have been processed.
+//
+// It implements the WritableStreamDefaultWriter.close() [specification] algorithm.
+//
+// [specification]: https://streams.spec.whatwg.org/#default-writer-close
+func (writer *WritableStreamDefaultWriter) Close() *sobek.Promise {
+	// 1. Let stream be this.[[stream]].
+	stream := writer.stream
+
+	// 2. If stream is undefined, return a promise rejected with a TypeError exception.
+	if stream == nil {
+		return newRejectedPromise(writer.vu, newTypeError(writer.runtime, "the writer has been released").Err())
+	}
+
+	// 3. If ! WritableStreamCloseQueuedOrInFlight(stream) is true, return a promise rejected with a TypeError exception.
+	if stream.closeQueuedOrInFlight() {
+		return newRejectedPromise(writer.vu, newTypeError(writer.runtime, "the stream is already closing").Err())
+	}
+
+	// 4. Return ! WritableStreamDefaultWriterClose(this).
+	return writer.close()
+}
+
+// ReleaseLock releases the writer's lock on the stream.
+//
+// It implements the WritableStreamDefaultWriter.releaseLock() [specification] algorithm.
+//
+// [specification]: https://streams.spec.whatwg.org/#default-writer-release-lock
+func (writer *WritableStreamDefaultWriter) ReleaseLock() {
+	// 1. Let stream be this.[[stream]].
+	// 2. If stream is undefined, return.
+	if writer.stream == nil {
+		return
+	}
+
+	// 3. Assert: stream.[[writer]] is not undefined.
+	// 4. Perform ! WritableStreamDefaultWriterRelease(this).
+	writer.release()
+}
+
+// Write writes the given chunk to the stream, and returns a promise that resolves once
+// the chunk has been written to the underlying sink.
+//
+// It implements the WritableStreamDefaultWriter.write(chunk) [specification] algorithm.
+//
+// [specification]: https://streams.spec.whatwg.org/#default-writer-write
+func (writer *WritableStreamDefaultWriter) Write(chunk sobek.Value) *sobek.Promise {
+	// 1. If this.[[stream]] is undefined, return a promise rejected with a TypeError exception.
+	if writer.stream == nil {
+		return newRejectedPromise(writer.vu, newTypeError(writer.runtime, "the writer has been released").Err())
+	}
+
+	if chunk == nil {
+		chunk = sobek.Undefined()
+	}
+
+	// 2. Return ! WritableStreamDefaultWriterWrite(this, chunk).
+	return writer.write(chunk)
+}
+
+// setup implements the specification's [SetUpWritableStreamDefaultWriter] abstract operation.
+//
+// [SetUpWritableStreamDefaultWriter]: https://streams.spec.whatwg.org/#set-up-writable-stream-default-writer
+func (writer *WritableStreamDefaultWriter) setup(stream *WritableStream) {
+	rt := stream.runtime
+	writer.runtime = rt
+	writer.vu = stream.vu
+
+	// 1. If ! IsWritableStreamLocked(stream) is true, throw a TypeError exception.
+	if stream.isLocked() {
+		throw(rt, newTypeError(rt, "the stream is locked to another writer"))
+	}
+
+	// 2. Set writer.[[stream]] to stream.
+	writer.stream = stream
+
+	// 3. Set stream.[[writer]] to writer.
+	stream.writer = writer
+	stream.Locked = true
+
+	// 4. Let state be stream.[[state]].
+	state := stream.state
+
+	switch state {
+	// 5. If state is "writable",
+	case WritableStreamStateWritable:
+		// 5.1. If ! WritableStreamCloseQueuedOrInFlight(stream) is false and stream.[[backpressure]] is true,
+		// set writer.[[readyPromise]] to a new promise.
+		// 5.2. Otherwise, set writer.[[readyPromise]] to a promise resolved with undefined.
+		writer.readyPromise = newPromiseCapability(rt)
+		if stream.closeQueuedOrInFlight() || !stream.backpressure {
+			writer.readyPromise.resolve(sobek.Undefined())
+		}
+
+		// 5.3. Set writer.[[closedPromise]] to a new promise.
+		writer.closedPromise = newPromiseCapability(rt)
+
+	// 6. Otherwise, if state is "erroring",
+	case WritableStreamStateErroring:
+		// 6.1. Set writer.[[readyPromise]] to a promise rejected with stream.[[storedError]].
+		// 6.2. Set writer.[[readyPromise]].[[PromiseIsHandled]] to true.
+		writer.readyPromise = newPromiseCapability(rt)
+		writer.readyPromise.reject(stream.storedErrorValue())
+		setPromiseIsHandled(rt, writer.readyPromise.promise)
+
+		// 6.3. Set writer.[[closedPromise]] to a new promise.
+		writer.closedPromise = newPromiseCapability(rt)
+
+	// 7. Otherwise, if state is "closed",
+	case WritableStreamStateClosed:
+		// 7.1. Set writer.[[readyPromise]] to a promise resolved with undefined.
+		writer.readyPromise = newPromiseCapability(rt)
+		writer.readyPromise.resolve(sobek.Undefined())
+
+		// 7.2. Set writer.[[closedPromise]] to a promise resolved with undefined.
+		writer.closedPromise = newPromiseCapability(rt)
+		writer.closedPromise.resolve(sobek.Undefined())
+
+	// 8. Otherwise,
+	default:
+		// 8.1. Assert: state is "errored".
+		// 8.2. Let storedError be stream.[[storedError]].
+		storedError := stream.storedErrorValue()
+
+		// 8.3. Set writer.[[readyPromise]] to a promise rejected with storedError.
+		// 8.4. Set writer.[[readyPromise]].[[PromiseIsHandled]] to true.
+		writer.readyPromise = newPromiseCapability(rt)
+		writer.readyPromise.reject(storedError)
+		setPromiseIsHandled(rt, writer.readyPromise.promise)
+
+		// 8.5. Set writer.[[closedPromise]] to a promise rejected with storedError.
+		// 8.6. Set writer.[[closedPromise]].[[PromiseIsHandled]] to true.
+		writer.closedPromise = newPromiseCapability(rt)
+		writer.closedPromise.reject(storedError)
+		setPromiseIsHandled(rt, writer.closedPromise.promise)
+	}
+}
+
+// close implements the specification's [WritableStreamDefaultWriterClose] abstract operation.
+//
+// [WritableStreamDefaultWriterClose]: https://streams.spec.whatwg.org/#writable-stream-default-writer-close
+func (writer *WritableStreamDefaultWriter) close() *sobek.Promise {
+	// 1. Let stream be writer.[[stream]].
+	// 2. Assert: stream is not undefined.
+	// 3. Return ! WritableStreamClose(stream).
+	return writer.stream.close()
+}
+
+// closeWithErrorPropagation implements the specification's [WritableStreamDefaultWriterCloseWithErrorPropagation]
+// abstract operation.
+//
+// [WritableStreamDefaultWriterCloseWithErrorPropagation]: https://streams.spec.whatwg.org/#writable-stream-default-writer-close-with-error-propagation
+func (writer *WritableStreamDefaultWriter) closeWithErrorPropagation() *sobek.Promise {
+	// 1. Let stream be writer.[[stream]].
+	// 2. Assert: stream is not undefined.
+	stream := writer.stream
+
+	// 3. Let state be stream.[[state]].
+	state := stream.state
+
+	// 4. If ! WritableStreamCloseQueuedOrInFlight(stream) is true or state is "closed",
+	// return a promise resolved with undefined.
+	if stream.closeQueuedOrInFlight() || state == WritableStreamStateClosed {
+		return newResolvedPromise(writer.vu, sobek.Undefined())
+	}
+
+	// 5. If state is "errored", return a promise rejected with stream.[[storedError]].
+	if state == WritableStreamStateErrored {
+		return newRejectedPromise(writer.vu, stream.storedErrorValue())
+	}
+
+	// 6. Assert: state is "writable" or "erroring".
+	// 7. Return ! WritableStreamDefaultWriterClose(writer).
+	return writer.close()
+}
+
+// ensureClosedPromiseRejected implements the specification's [WritableStreamDefaultWriterEnsureClosedPromiseRejected]
+// abstract operation.
+//
+// [WritableStreamDefaultWriterEnsureClosedPromiseRejected]: https://streams.spec.whatwg.org/#writable-stream-default-writer-ensure-closed-promise-rejected
+func (writer *WritableStreamDefaultWriter) ensureClosedPromiseRejected(e any) {
+	// 1. If writer.[[closedPromise]].[[PromiseState]] is "pending", reject writer.[[closedPromise]] with error.
+	// 2. Otherwise, set writer.[[closedPromise]] to a promise rejected with error.
+	if writer.closedPromise.promise.State() != sobek.PromiseStatePending {
+		writer.closedPromise = newPromiseCapability(writer.runtime)
+	}
+	writer.closedPromise.reject(e)
+
+	// 3. Set writer.[[closedPromise]].[[PromiseIsHandled]] to true.
+	setPromiseIsHandled(writer.runtime, writer.closedPromise.promise)
+}
+
+// ensureReadyPromiseRejected implements the specification's [WritableStreamDefaultWriterEnsureReadyPromiseRejected]
+// abstract operation.
+//
+// [WritableStreamDefaultWriterEnsureReadyPromiseRejected]: https://streams.spec.whatwg.org/#writable-stream-default-writer-ensure-ready-promise-rejected
+func (writer *WritableStreamDefaultWriter) ensureReadyPromiseRejected(e any) {
+	// 1. If writer.[[readyPromise]].[[PromiseState]] is "pending", reject writer.[[readyPromise]] with error.
+	// 2. Otherwise, set writer.[[readyPromise]] to a promise rejected with error.
+	if writer.readyPromise.promise.State() != sobek.PromiseStatePending {
+		writer.readyPromise = newPromiseCapability(writer.runtime)
+	}
+	writer.readyPromise.reject(e)
+
+	// 3. Set writer.[[readyPromise]].[[PromiseIsHandled]] to true.
+	setPromiseIsHandled(writer.runtime, writer.readyPromise.promise)
+}
+
+// getDesiredSize implements the specification's [WritableStreamDefaultWriterGetDesiredSize] abstract operation.
+//
+// [WritableStreamDefaultWriterGetDesiredSize]: https://streams.spec.whatwg.org/#writable-stream-default-writer-get-desired-size
+func (writer *WritableStreamDefaultWriter) getDesiredSize() null.Float {
+	// 1. Let stream be writer.[[stream]].
+	stream := writer.stream
+
+	// 2. Let state be stream.[[state]].
+	state := stream.state
+
+	// 3. If state is "errored" or "erroring", return null.
+	if state == WritableStreamStateErrored || state == WritableStreamStateErroring {
+		return null.NewFloat(0, false)
+	}
+
+	// 4. If state is "closed", return 0.
+	if state == WritableStreamStateClosed {
+		return null.FloatFrom(0)
+	}
+
+	// 5. Return ! WritableStreamDefaultControllerGetDesiredSize(stream.[[controller]]).
+	return null.FloatFrom(stream.controller.getDesiredSize())
+}
+
+// release implements the specification's [WritableStreamDefaultWriterRelease] abstract operation.
+//
+// [WritableStreamDefaultWriterRelease]: https://streams.spec.whatwg.org/#writable-stream-default-writer-release
+func (writer *WritableStreamDefaultWriter) release() {
+	// 1. Let stream be writer.[[stream]].
+	// 2. Assert: stream is not undefined.
+	stream := writer.stream
+
+	// 3. Assert: stream.[[writer]] is writer.
+	if stream.writer != writer {
+		common.Throw(writer.runtime, newError(AssertionError, "the stream is not locked to this writer"))
+	}
+
+	// 4. Let releasedError be a new TypeError.
+	releasedError := newTypeError(writer.runtime, "the writer has been released")
+
+	// 5. Perform ! WritableStreamDefaultWriterEnsureReadyPromiseRejected(writer, releasedError).
+	writer.ensureReadyPromiseRejected(releasedError)
+
+	// 6. Perform ! WritableStreamDefaultWriterEnsureClosedPromiseRejected(writer, releasedError).
+	writer.ensureClosedPromiseRejected(releasedError)
+
+	// 7. Set stream.[[writer]] to undefined.
+	stream.writer = nil
+	stream.Locked = false
+
+	// 8. Set writer.[[stream]] to undefined.
+	writer.stream = nil
+}
+
+// write implements the specification's [WritableStreamDefaultWriterWrite] abstract operation.
+//
+// [WritableStreamDefaultWriterWrite]: https://streams.spec.whatwg.org/#writable-stream-default-writer-write
+func (writer *WritableStreamDefaultWriter) write(chunk sobek.Value) *sobek.Promise {
+	// 1. Let stream be writer.[[stream]].
+	// 2. Assert: stream is not undefined.
+	stream := writer.stream
+
+	// 3. Let controller be stream.[[controller]].
+	controller := stream.controller
+
+	// 4. Let chunkSize be ! WritableStreamDefaultControllerGetChunkSize(controller, chunk).
+	chunkSize := controller.getChunkSize(chunk)
+
+	// 5. If stream is not equal to writer.[[stream]], return a promise rejected with a TypeError exception.
+	if stream != writer.stream {
+		return newRejectedPromise(writer.vu, newTypeError(writer.runtime, "the writer has been released").Err())
+	}
+
+	// 6. Let state be stream.[[state]].
+	state := stream.state
+
+	// 7. If state is "errored", return a promise rejected with stream.[[storedError]].
+	if state == WritableStreamStateErrored {
+		return newRejectedPromise(writer.vu, stream.storedErrorValue())
+	}
+
+	// 8. If ! WritableStreamCloseQueuedOrInFlight(stream) is true or state is "closed", return a promise
+	// rejected with a TypeError exception indicating that the stream is closing or closed.
+	if stream.closeQueuedOrInFlight() || state == WritableStreamStateClosed {
+		return newRejectedPromise(writer.vu, newTypeError(writer.runtime, "the stream is closing or closed").Err())
+	}
+
+	// 9. If state is "erroring", return a promise rejected with stream.[[storedError]].
+	if state == WritableStreamStateErroring {
+		return newRejectedPromise(writer.vu, stream.storedErrorValue())
+	}
+
+	// 10. Assert: state is "writable".
+	// 11. Let promise be ! WritableStreamAddWriteRequest(stream).
+	promise := stream.addWriteRequest()
+
+	// 12. Perform ! WritableStreamDefaultControllerWrite(controller, chunk, chunkSize).
+	controller.write(chunk, chunkSize)
+
+	// 13. Return promise.
+	return promise
+}
@@ -0,0 +1,120 @@
+package streams
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWritableStream(t *testing.T) {
+	t.Parallel()
+
+	t.Run("write and close", func(t *testing.T) {
+		t.Parallel()
+
+		got := runAsyncScript(t, `async () => {
+  const events = [];
+  const ws = new WritableStream({
+    write(chunk) { events.push("write:" + chunk); },
+    close() { events.push("close"); },
+  });
+  const writer = ws.getWriter();
+  events.push("locked:" + ws.locked);
+  await writer.ready;
+  await writer.write("a");
+  await writer.write("b");
+  await writer.close();
+  writer.releaseLock();
+  events.push("locked:" + ws.locked);
+  return events.join(",");
+}`)
+		assert.Equal(t, "locked:true,write:a,write:b,close,locked:false", got.String())
+	})
+
+	t.Run("backpressure", func(t *testing.T) {
+		t.Parallel()
+
+		got := runAsyncScript(t, `async () => {
+  let release;
+  const ws = new WritableStream({
+    write() { return new Promise((resolve) => { release = resolve; }); },
+  }, new CountQueuingStrategy({ highWaterMark: 2 }));
+  const writer = ws.getWriter();
+  const sizes = [writer.desiredSize];
+  writer.write("a");
+  writer.write("b");
+  sizes.push(writer.desiredSize);
+  writer.write("c");
+  sizes.push(writer.desiredSize);
+  return sizes.join(",");
+}`)
+		assert.Equal(t, "2,0,-1", got.String())
+	})
+
+	t.Run("abort", func(t *testing.T) {
+		t.Parallel()
+
+		got := runAsyncScript(t, `async () => {
+  let abortReason, signalAborted;
+  const ws = new WritableStream({
+    start(controller) { this.controller = controller; },
+    abort(reason) {
+      abortReason = reason;
+      signalAborted = this.controller.signal.aborted;
+    },
+  });
+  await ws.abort("stop");
+  try {
+    await ws.getWriter().write("a");
+  } catch (e) {
+    return [abortReason, signalAborted, e].join(",");
+  }
+}`)
+		assert.Equal(t, "stop,true,stop", got.String())
+	})
+
+	t.Run("error in sink", func(t *testing.T) {
+		t.Parallel()
+
+		got := runAsyncScript(t, `async () => {
+  const ws = new WritableStream({
+    write() { throw new Error("boom"); },
+  });
+  const writer = ws.getWriter();
+  try {
+    await writer.write("a");
+  } catch (e) {
+    try {
+      await writer.closed;
+    } catch (e2) {
+      return e.message + "," + e2.message;
+    }
+  }
+}`)
+		assert.Equal(t, "boom,boom", got.String())
+	})
+}
+
+func TestWritableStreamWebPlatformTests(t *testing.T) {
+	t.Parallel()
+
+	// The garbage collection tests aren"t included, as they need to trigger it.
+	suites := []string{
+		"aborting.any.js",
+		"bad-strategies.any.js",
+		"bad-underlying-sinks.any.js",
+		"byte-length-queuing-strategy.any.js",
+		"close.any.js",
+		"constructor.any.js",
+		"count-queuing-strategy.any.js",
+		"error.any.js",
+		"floating-point-total-queue-size.any.js",
+		"general.any.js",
+		"properties.any.js",
+		"reentrant-strategy.any.js",
+		"start.any.js",
+		"write.any.js",
+	}
+
+	runWebPlatformTests(t, "streams/writable-streams", suites)
+}
@@ -21,6 +21,7 @@ type wsParams struct {
 	tagsAndMeta       *metrics.TagsAndMeta
 	enableCompression bool
 	subprocotols      []string
+	signal            sobek.Value
 }
 
 // buildParams builds WebSocket params and configure some of them
@@ -78,6 +79,8 @@ func buildParams(state *lib.State, rt *sobek.Runtime, raw sobek.Value) (*wsParam
 			}
 
 			parsed.enableCompression = true
+		case "signal":
+			parsed.signal = params.Get(k)
 		default:
 			return nil, fmt.Errorf("unknown WebSocket's option %s", k)
 		}
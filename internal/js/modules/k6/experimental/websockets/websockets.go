@@ -17,6 +17,7 @@ import (
 	"github.com/grafana/sobek"
 	"github.com/mstoykov/k6-taskqueue-lib/taskqueue"
 	"go.k6.io/k6/internal/js/modules/k6/experimental/websockets/events"
+	"go.k6.io/k6/internal/js/tc55/abort"
 
 	"go.k6.io/k6/js/common"
 	"go.k6.io/k6/js/modules"
@@ -145,10 +146,16 @@ func (r *WebSocketsAPI) websocket(c sobek.ConstructorCall) *sobek.Object {
 		binaryType:      blobBinaryType,
 	}
 
+	// The connection attempt is canceled when the signal is aborted before the connection is established
+	op, err := abort.Follow(rt, r.vu.Context(), params.signal)
+	if err != nil {
+		common.Throw(rt, err)
+	}
+
 	// Maybe have this after the goroutine below ?!?
 	defineWebsocket(rt, w)
 
-	go w.establishConnection(params)
+	go w.establishConnection(params, op)
 	return w.obj
 }
 
@@ -260,7 +267,7 @@ type message struct {
 }
 
 // documented https://websockets.spec.whatwg.org/#concept-websocket-establish
-func (w *webSocket) establishConnection(params *wsParams) {
+func (w *webSocket) establishConnection(params *wsParams, op *abort.Operation) {
 	state := w.vu.State()
 	w.started = time.Now()
 	var tlsConfig *tls.Config
@@ -287,7 +294,13 @@ func (w *webSocket) establishConnection(params *wsParams) {
 
 	ctx := w.vu.Context()
 	start := time.Now()
-	conn, httpResponse, connErr := wsd.DialContext(ctx, w.url.String(), params.headers)
+	conn, httpResponse, connErr := wsd.DialContext(op.Context(), w.url.String(), params.headers)
+	if abortErr := abortedError(op.Context()); abortErr != nil {
+		if conn != nil {
+			_ = conn.Close()
+		}
+		conn, connErr = nil, abortErr
+	}
 	connectionEnd := time.Now()
 	connectionDuration := metrics.D(connectionEnd.Sub(start))
 
@@ -326,6 +339,9 @@ func (w *webSocket) establishConnection(params *wsParams) {
 	if connErr != nil {
 		// Pass the error to the user script before exiting immediately
 		w.tq.Queue(func() error {
+			if err := op.Done(); err != nil {
+				return err
+			}
 			return w.connectionClosedWithError(connErr)
 		})
 		w.tq.Close()
@@ -333,10 +349,22 @@ func (w *webSocket) establishConnection(params *wsParams) {
 	}
 	go w.loop()
 	w.tq.Queue(func() error {
+		if err := op.Done(); err != nil {
+			return err
+		}
 		return w.connectionConnected()
 	})
 }
 
+// abortedError returns the error of a connection attempt aborted by its signal, or nil if it wasn't.
+func abortedError(ctx context.Context) error {
+	var abortErr *abort.Error
+	if errors.As(context.Cause(ctx), &abortErr) {
+		return fmt.Errorf("connection aborted: %w", abortErr)
+	}
+	return nil
+}
+
 // emitConnectionMetrics emits the metrics for a websocket connection.
 func (w *webSocket) emitConnectionMetrics(ctx context.Context, start time.Time, duration float64) {
 	state := w.vu.State()
@@ -805,6 +805,27 @@ func TestDialError(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestDialAbort(t *testing.T) {
+	t.Parallel()
+	ts := newTestState(t)
+	sr := ts.tb.Replacer.Replace
+
+	_, err := ts.runtime.RunOnEventLoop(sr(`
+		var ws = new WebSocket("WSBIN_URL/ws-echo", null, { signal: AbortSignal.abort("stop") });
+		ws.addEventListener("open", () => {
+			throw new Error("the connection wasn't aborted");
+		})
+		ws.addEventListener("error", (e) => {
+			if (e.error !== "connection aborted: stop") {
+				throw new Error("wrong error: " + e.error);
+			}
+			globalThis.errored = true;
+		})
+	`))
+	require.NoError(t, err)
+	assert.True(t, ts.runtime.VU.Runtime().Get("errored").ToBoolean())
+}
+
 func TestOnError(t *testing.T) {
 	t.Parallel()
 	ts := newTestState(t)
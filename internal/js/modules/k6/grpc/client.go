@@ -13,6 +13,7 @@ import (
 	"strings"
 	"time"
 
+	"go.k6.io/k6/internal/js/tc55/abort"
 	"go.k6.io/k6/internal/lib/netext/grpcext"
 	"go.k6.io/k6/js/common"
 	"go.k6.io/k6/js/modules"
@@ -307,7 +308,8 @@ func (c *Client) Invoke(
 	return c.conn.Invoke(c.vu.Context(), grpcReq)
 }
 
-// AsyncInvoke creates and calls a unary RPC by fully qualified method name asynchronously
+// AsyncInvoke creates and calls a unary RPC by fully qualified method name asynchronously.
+// The call can be canceled with the AbortSignal of the `signal` param.
 func (c *Client) AsyncInvoke(
 	method string,
 	req sobek.Value,
@@ -321,11 +323,24 @@ func (c *Client) AsyncInvoke(
 		return promise, err
 	}
 
+	// The call is canceled when the signal is aborted, and the promise is rejected with its reason
+	rt := c.vu.Runtime()
+	op, err := abort.Follow(rt, c.vu.Context(), abort.SignalParam(rt, params))
+	if err != nil {
+		return promise, reject(err)
+	}
+
 	callback := c.vu.RegisterCallback()
 	go func() {
-		res, err := c.conn.Invoke(c.vu.Context(), grpcReq)
+		res, err := c.conn.Invoke(op.Context(), grpcReq)
 
 		callback(func() error {
+			if doneErr := op.Done(); doneErr != nil {
+				return doneErr
+			}
+			if op.Aborted() {
+				return reject(op.Reason())
+			}
 			if err != nil {
 				return reject(err)
 			}
@@ -404,6 +404,32 @@ func TestClient(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "AsyncInvokeAbort",
+			initString: codeBlock{code: `
+				var client = new grpc.Client();
+				client.load([], "../../../../lib/testutils/httpmultibin/grpc_testing/test.proto");`},
+			setup: func(tb *httpmultibin.HTTPMultiBin) {
+				tb.GRPCStub.EmptyCallFunc = func(ctx context.Context, _ *grpc_testing.Empty) (*grpc_testing.Empty, error) {
+					<-ctx.Done()
+					return nil, ctx.Err()
+				}
+			},
+			vuString: codeBlock{
+				code: `
+				client.connect("GRPCBIN_ADDR");
+				var controller = new AbortController();
+				client.asyncInvoke("grpc.testing.TestService/EmptyCall", {}, { signal: controller.signal }).then(function(resp) {
+					throw new Error("the call wasn't aborted: " + resp.status)
+				}, (err) => {
+					if (err !== "stop") {
+						throw new Error("unexpected error: " + err)
+					}
+				})
+				controller.abort("stop");
+				`,
+			},
+		},
 		{
 			name: "AsyncInvokeDiscardResponseMessage",
 			initString: codeBlock{code: `
@@ -61,6 +61,8 @@ func newCallParams(vu modules.VU, input sobek.Value) (*callParams, error) {
 			}
 		case "discardResponseMessage":
 			result.DiscardResponseMessage = params.Get(k).ToBoolean()
+		case "signal":
+			// the AbortSignal is followed by asyncInvoke, see Client.AsyncInvoke
 		default:
 			return result, fmt.Errorf("unknown param: %q", k)
 		}
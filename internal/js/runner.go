@@ -17,6 +17,7 @@ import (
 	"time"
 
 	"github.com/grafana/sobek"
+	"github.com/quic-go/quic-go/http3"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/net/http2"
 	"golang.org/x/time/rate"
@@ -32,6 +33,7 @@ import (
 	"go.k6.io/k6/lib"
 	"go.k6.io/k6/lib/fsext"
 	"go.k6.io/k6/lib/netext"
+	"go.k6.io/k6/lib/netext/httpext"
 	"go.k6.io/k6/lib/types"
 	"go.k6.io/k6/metrics"
 )
@@ -219,6 +221,7 @@ func (r *Runner) newVU(
 		BundleInstance: *bi,
 		Runner:         r,
 		Transport:      transport,
+		HTTP3Transport: httpext.NewHTTP3Transport(dialer, tlsConfig),
 		Dialer:         dialer,
 		CookieJar:      cookieJar,
 		TLSConfig:      tlsConfig,
@@ -232,6 +235,7 @@ func (r *Runner) newVU(
 		Logger:         vu.Runner.preInitState.Logger,
 		Options:        vu.Runner.Bundle.Options,
 		Transport:      vu.Transport,
+		HTTP3Transport: vu.HTTP3Transport,
 		Dialer:         vu.Dialer,
 		TLSConfig:      vu.TLSConfig,
 		CookieJar:      cookieJar,
@@ -432,7 +436,14 @@ func prepareHandleSummaryCall(
 	if summary != nil {
 		noColor = summary.NoColor
 		enableColors = summary.EnableColors
-		legacyDataForJS = summarizeMetricsToObject(legacy, r.Bundle.Options, r.setupData)
+		legacyData := summarizeMetricsToObject(legacy, r.Bundle.Options, r.setupData)
+		if summary.Timeline != nil {
+			legacyData["timeline"], err = summarizeTimelineToObject(summary.Timeline)
+			if err != nil {
+				return noColor, enableColors, nil, nil, "", err
+			}
+		}
+		legacyDataForJS = legacyData
 		summaryDataForJS, err = summarizeReportToObject(rt, summary)
 		summaryCode = jslibSummaryCode
 	} else { // TODO: Remove this code block once we stop supporting the legacy summary.
@@ -531,7 +542,12 @@ func (r *Runner) SetOptions(opts lib.Options) error {
 			formatter = l.Formatter
 			level = l.Level
 		}
-		c, err := newFileConsole(opts.ConsoleOutput.String, formatter, level)
+		var hooks []logrus.Hook
+		if r.preInitState.SecretsManager != nil {
+			// the console output file shouldn't have secrets either, like the rest of the logs
+			hooks = append(hooks, r.preInitState.SecretsManager.Hook())
+		}
+		c, err := newFileConsole(opts.ConsoleOutput.String, formatter, level, hooks...)
 		if err != nil {
 			return err
 		}
@@ -680,14 +696,15 @@ func (r *Runner) getTimeoutFor(stage string) time.Duration {
 type VU struct {
 	BundleInstance
 
-	Runner    *Runner
-	Transport *http.Transport
-	Dialer    *netext.Dialer
-	CookieJar *cookiejar.Jar
-	TLSConfig *tls.Config
-	ID        uint64 // local to the current instance
-	IDGlobal  uint64 // global across all instances
-	iteration int64
+	Runner         *Runner
+	Transport      *http.Transport
+	HTTP3Transport *http3.Transport
+	Dialer         *netext.Dialer
+	CookieJar      *cookiejar.Jar
+	TLSConfig      *tls.Config
+	ID             uint64 // local to the current instance
+	IDGlobal       uint64 // global across all instances
+	iteration      int64
 
 	Console    *console
 	BufferPool *lib.BufferPool
@@ -864,6 +881,12 @@ func (u *ActiveVU) RunOnce() error {
 	}
 
 	u.emitAndWaitEvent(&event.Event{Type: event.IterEnd, Data: eventIterData})
+	if err != nil && u.moduleVUImpl.events.global != nil {
+		// Iteration errors are also sent to the global event system, so they
+		// can be followed for the whole test run, e.g. by the REST API. We
+		// don't wait for them to be processed, so they don't slow down the VU.
+		u.moduleVUImpl.events.global.Emit(&event.Event{Type: event.IterError, Data: eventIterData})
+	}
 
 	// If MinIterationDuration is specified and the iteration wasn't canceled
 	// and was less than it, sleep for the remainder
@@ -942,6 +965,7 @@ func (u *VU) runFn(
 
 	if u.Runner.Bundle.Options.NoVUConnectionReuse.Bool {
 		u.Transport.CloseIdleConnections()
+		u.HTTP3Transport.CloseIdleConnections()
 	}
 
 	builtinMetrics := u.Runner.preInitState.BuiltinMetrics
@@ -82,6 +82,20 @@ func summarizeReportToObject(rt *sobek.Runtime, s *summary.Summary) (map[string]
 	return m, nil
 }
 
+// summarizeTimelineToObject transforms the timeline of the summary into plain data
+// with the same shape as in the summary data JSON file, e.g. with the times as strings.
+func summarizeTimelineToObject(t *summary.Timeline) (interface{}, error) {
+	timelineJSON, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+	var timeline interface{}
+	if err := json.Unmarshal(timelineJSON, &timeline); err != nil {
+		return nil, err
+	}
+	return timeline, nil
+}
+
 // TODO: figure out something saner... refactor the sinks and how we deal with
 // metrics in general... so much pain and misery... :sob:
 func metricValueGetter(summaryTrendStats []string) func(metrics.Sink, time.Duration) map[string]float64 {
@@ -135,10 +149,14 @@ func summarizeMetricsToObject(data *lib.LegacySummary, options lib.Options, setu
 
 	metricsData := make(map[string]interface{})
 	for name, m := range data.Metrics {
+		values := getMetricValues(m.Sink, data.TestRunDuration)
+		for key, value := range m.Thresholds.WindowValues() {
+			values[key] = value
+		}
 		metricData := map[string]interface{}{
 			"type":     m.Type.String(),
 			"contains": m.Contains.String(),
-			"values":   getMetricValues(m.Sink, data.TestRunDuration),
+			"values":   values,
 		}
 
 		if len(m.Thresholds.Thresholds) > 0 {
@@ -939,8 +939,21 @@ function renderMetricValueForThresholds(
 	info,
 	formatter,
 ) {
-	const {trendStats, trendCols, trendKeys, nonTrendValues, nonTrendExtras} = info;
-	const thresholdAgg = threshold.source.split(/[=><]/)[0].trim();
+	const {trendStats, trendCols, trendKeys, nonTrendValues, nonTrendExtras, nonTrendWindows} = info;
+	const thresholdAgg = threshold.source.split(/[=><]/)[0].trim().replace(/\s+/g, ' ');
+
+	// Time-windowed aggregations, like "p(95) over 1m", are only known for
+	// the last evaluated window, which may not have had any data.
+	if (isWindowedAggregation(thresholdAgg)) {
+		const value = (metric.type === 'trend')
+			? trendKeys[metric.name]?.[thresholdAgg]
+			: nonTrendWindows[metric.name]?.[thresholdAgg];
+
+		return [
+			formatter.decorate(thresholdAgg, 'white'),
+			formatter.decorate(value ?? '[no data]', 'cyan')
+		].join('=');
+	}
 
 	let value;
 	switch (metric.type) {
@@ -965,6 +978,16 @@ function renderMetricValueForThresholds(
 	].join('=');
 }
 
+/**
+ * Checks whether a threshold's aggregation applies to a sliding time window, e.g. "p(95) over 1m".
+ *
+ * @param {string} aggregation - The aggregation part of the threshold's source.
+ * @returns {boolean} - Whether the aggregation is time-windowed.
+ */
+function isWindowedAggregation(aggregation) {
+	return aggregation.includes(' over ');
+}
+
 /**
  * Format data for trend metrics.
  */
@@ -1042,6 +1065,7 @@ function renderTrendValue(value, stat, metric, options) {
  * @property {number} maxNameWidth - The maximum width of the metric names.
  * @property {Object} nonTrendValues - The non-trend metric values.
  * @property {Object} nonTrendExtras - The non-trend metric extras.
+ * @property {Object} nonTrendWindows - The non-trend metric time-windowed aggregation values.
  * @property {Object} trendCols - The trend columns.
  * @property {Object} trendKeys - The trend keys (values that aren't included within `trendStats`).
  * @property {number[]} trendColMaxLens - The trend column maximum lengths.
@@ -1063,6 +1087,10 @@ function computeSummaryInfo(metrics, renderContext, options) {
 	const nonTrendExtras = {};
 	const trendCols = {};
 
+	// "nonTrendWindows" stores the time-windowed aggregation values (e.g. "rate over 30s") of non-trend metrics,
+	// which are only present for thresholds.
+	const nonTrendWindows = {};
+
 	// While "trendCols" contain the values for each "trendStats" aggregation (e.g. p(90) as a sorted array,
 	// "trendKeys" is used to store specific aggregation values that aren't part of "trendStats"; mainly for thresholds.
 	const trendKeys = {};
@@ -1113,6 +1141,10 @@ function computeSummaryInfo(metrics, renderContext, options) {
 				metric,
 				options.summaryTimeUnit,
 			);
+			nonTrendWindows[name] = nonTrendWindowValuesForSum(
+				metric,
+				options.summaryTimeUnit,
+			);
 			const mainValue = values[0]; // FIXME (@oleiade) we should assert that the index exists here
 			nonTrendValues[name] = mainValue;
 			maxNonTrendValueLen = Math.max(
@@ -1139,6 +1171,7 @@ function computeSummaryInfo(metrics, renderContext, options) {
 		maxNameWidth,
 		nonTrendValues,
 		nonTrendExtras,
+		nonTrendWindows,
 		trendCols,
 		trendColMaxLens,
 		trendKeys,
@@ -1376,6 +1409,28 @@ function humanizeDuration(dur, timeUnit) {
 	return humanizeGenericDuration(dur);
 }
 
+/**
+ * Formats the time-windowed aggregation values of a non-trend metric, e.g. "rate over 30s".
+ *
+ * @param {ReportMetric} metric - The metric to format the values of.
+ * @param {string} timeUnit - The time unit for duration values.
+ * @returns {Record<string, string>} - The formatted values, by aggregation.
+ */
+function nonTrendWindowValuesForSum(metric, timeUnit) {
+	const windows = {};
+	for (const [key, value] of Object.entries(metric.values)) {
+		if (!isWindowedAggregation(key)) {
+			continue;
+		}
+
+		windows[key] = humanizeValue(value, metric, timeUnit);
+		if (metric.type === 'counter' && key.startsWith('rate')) {
+			windows[key] += '/s';
+		}
+	}
+	return windows;
+}
+
 /**
  * Returns the summary values for non-trend metrics (counter, gauge, rate).
  *
@@ -0,0 +1,262 @@
+// Package abort implements the AbortController and AbortSignal classes of the
+// DOM Standard (https://dom.spec.whatwg.org/#aborting-ongoing-activities) as globals,
+// and the helpers for the asynchronous APIs of the modules to follow an AbortSignal.
+package abort
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/internal/js/webidl"
+	"go.k6.io/k6/js/modules"
+)
+
+// The legacy codes of the DOMException names the signals are aborted with,
+// see https://webidl.spec.whatwg.org/#idl-DOMException-error-names
+const (
+	abortErrorCode   = 20
+	timeoutErrorCode = 23
+)
+
+// abortAPI holds the prototypes of the classes, which are needed to create the
+// signals returned by the static methods of AbortSignal and by AbortController.
+type abortAPI struct {
+	vu modules.VU
+	rt *sobek.Runtime
+
+	signalProto     *sobek.Object
+	controllerProto *sobek.Object
+}
+
+// SetupGlobally sets the AbortController and AbortSignal classes to be accessible globally.
+func SetupGlobally(vu modules.VU) error {
+	rt := vu.Runtime()
+	api := &abortAPI{vu: vu, rt: rt}
+
+	signalClass := rt.ToValue(func(sobek.ConstructorCall) *sobek.Object {
+		webidl.ThrowTypeError(rt, "Illegal constructor")
+		return nil
+	}).ToObject(rt)
+	api.signalProto = signalClass.Get("prototype").ToObject(rt)
+	api.defineSignalClass(signalClass)
+
+	controllerClass := rt.ToValue(api.controllerConstructor).ToObject(rt)
+	api.controllerProto = controllerClass.Get("prototype").ToObject(rt)
+	api.defineControllerPrototype()
+
+	mapping := map[string]any{
+		"AbortSignal":     signalClass,
+		"AbortController": controllerClass,
+	}
+	for k, v := range mapping {
+		if err := rt.Set(k, v); err != nil {
+			return fmt.Errorf("error setting up %q globally: %w", k, err)
+		}
+	}
+	return nil
+}
+
+// newError returns an error with the name and the legacy code of a DOMException.
+func (api *abortAPI) newError(name, message string, code int) sobek.Value {
+	rt := api.rt
+	obj, err := rt.New(rt.Get("Error"), rt.ToValue(message))
+	webidl.Must(rt, err)
+	webidl.Must(rt, obj.DefineDataProperty("name", rt.ToValue(name), sobek.FLAG_TRUE, sobek.FLAG_FALSE, sobek.FLAG_TRUE))
+	webidl.Must(rt, obj.DefineDataProperty("code", rt.ToValue(code), sobek.FLAG_TRUE, sobek.FLAG_FALSE, sobek.FLAG_TRUE))
+	return obj
+}
+
+func (api *abortAPI) defineSignalClass(ctor *sobek.Object) {
+	rt := api.rt
+	proto := api.signalProto
+
+	webidl.DefineMethod(rt, ctor, "abort", func(call sobek.FunctionCall) sobek.Value {
+		s := api.newSignal()
+		s.aborted = true
+		s.reason = api.reasonOrDefault(call.Argument(0))
+		return s.obj
+	})
+	webidl.DefineMethod(rt, ctor, "timeout", func(call sobek.FunctionCall) sobek.Value {
+		webidl.RequireArguments(rt, call, "AbortSignal.timeout", 1)
+		ms := call.Argument(0).ToFloat()
+		if math.IsNaN(ms) || math.IsInf(ms, 0) || ms < 0 || ms > math.MaxInt64/float64(time.Millisecond) {
+			webidl.ThrowTypeError(rt, "the timeout must be a finite and positive number of milliseconds")
+		}
+		s := api.newSignal()
+		s.deadline = time.Now().Add(time.Duration(ms * float64(time.Millisecond)))
+		return s.obj
+	})
+	webidl.DefineMethod(rt, ctor, "any", func(call sobek.FunctionCall) sobek.Value {
+		webidl.RequireArguments(rt, call, "AbortSignal.any", 1)
+		var signals []*abortSignal
+		api.forEachIterated(call.Argument(0), func(v sobek.Value) {
+			s, ok := v.Export().(*abortSignal)
+			if !ok {
+				webidl.ThrowTypeError(rt, "AbortSignal.any only accepts AbortSignal objects")
+			}
+			signals = append(signals, s)
+		})
+		return api.anySignal(signals).obj
+	})
+
+	webidl.DefineGetter(rt, proto, "aborted", func(call sobek.FunctionCall) sobek.Value {
+		return rt.ToValue(api.thisSignal(call.This).isAborted())
+	})
+	webidl.DefineGetter(rt, proto, "reason", func(call sobek.FunctionCall) sobek.Value {
+		s := api.thisSignal(call.This)
+		s.isAborted()
+		if s.reason == nil {
+			return sobek.Undefined()
+		}
+		return s.reason
+	})
+	webidl.DefineMethod(rt, proto, "throwIfAborted", func(call sobek.FunctionCall) sobek.Value {
+		if s := api.thisSignal(call.This); s.isAborted() {
+			panic(s.reason)
+		}
+		return sobek.Undefined()
+	})
+	webidl.Must(rt, proto.DefineAccessorProperty("onabort",
+		rt.ToValue(func(call sobek.FunctionCall) sobek.Value {
+			if s := api.thisSignal(call.This); s.onabort != nil {
+				return s.onabort
+			}
+			return sobek.Null()
+		}),
+		rt.ToValue(func(call sobek.FunctionCall) sobek.Value {
+			api.thisSignal(call.This).setEventHandler(call.Argument(0))
+			return sobek.Undefined()
+		}),
+		sobek.FLAG_TRUE, sobek.FLAG_TRUE))
+	webidl.DefineMethod(rt, proto, "addEventListener", func(call sobek.FunctionCall) sobek.Value {
+		webidl.RequireArguments(rt, call, "addEventListener", 2)
+		once := false
+		if options, ok := call.Argument(2).(*sobek.Object); ok {
+			once = options.Get("once").ToBoolean()
+		}
+		api.thisSignal(call.This).addEventListener(call.Argument(0).String(), call.Argument(1), once)
+		return sobek.Undefined()
+	})
+	webidl.DefineMethod(rt, proto, "removeEventListener", func(call sobek.FunctionCall) sobek.Value {
+		webidl.RequireArguments(rt, call, "removeEventListener", 2)
+		api.thisSignal(call.This).removeEventListener(call.Argument(0).String(), call.Argument(1))
+		return sobek.Undefined()
+	})
+	webidl.DefineToStringTag(rt, proto, "AbortSignal")
+}
+
+// reasonOrDefault returns the reason a signal is aborted with, which is an
+// AbortError if none is given.
+func (api *abortAPI) reasonOrDefault(reason sobek.Value) sobek.Value {
+	if reason == nil || sobek.IsUndefined(reason) {
+		return api.newError("AbortError", "signal is aborted without reason", abortErrorCode)
+	}
+	return reason
+}
+
+func (api *abortAPI) thisSignal(this sobek.Value) *abortSignal {
+	s, ok := this.Export().(*abortSignal)
+	if !ok {
+		webidl.ThrowTypeError(api.rt, "Illegal invocation")
+	}
+	return s
+}
+
+// anySignal returns a signal which is aborted when any of the signals is,
+// see https://dom.spec.whatwg.org/#create-a-dependent-abort-signal
+func (api *abortAPI) anySignal(signals []*abortSignal) *abortSignal {
+	result := api.newSignal()
+	for _, s := range signals {
+		if s.isAborted() {
+			result.aborted = true
+			result.reason = s.reason
+			return result
+		}
+	}
+
+	result.dependent = true
+	for _, s := range signals {
+		sources := []*abortSignal{s}
+		if s.dependent {
+			sources = s.sources
+		}
+		for _, source := range sources {
+			if !source.aborted && !result.hasSource(source) {
+				result.sources = append(result.sources, source)
+				source.dependents = append(source.dependents, result)
+			}
+		}
+	}
+	return result
+}
+
+type abortController struct {
+	webidl.NoOwnProperties
+
+	signal *abortSignal
+}
+
+func (api *abortAPI) controllerConstructor(call sobek.ConstructorCall) *sobek.Object {
+	c := &abortController{signal: api.newSignal()}
+	return webidl.NewInstance(api.rt, c, call.This.Prototype())
+}
+
+func (api *abortAPI) defineControllerPrototype() {
+	rt := api.rt
+	proto := api.controllerProto
+
+	thisController := func(this sobek.Value) *abortController {
+		c, ok := this.Export().(*abortController)
+		if !ok {
+			webidl.ThrowTypeError(rt, "Illegal invocation")
+		}
+		return c
+	}
+
+	webidl.DefineGetter(rt, proto, "signal", func(call sobek.FunctionCall) sobek.Value {
+		return thisController(call.This).signal.obj
+	})
+	webidl.DefineMethod(rt, proto, "abort", func(call sobek.FunctionCall) sobek.Value {
+		c := thisController(call.This)
+		if err := c.signal.signalAbort(api.reasonOrDefault(call.Argument(0))); err != nil {
+			panic(err)
+		}
+		return sobek.Undefined()
+	})
+	webidl.DefineToStringTag(rt, proto, "AbortController")
+}
+
+// forEachIterated calls the callback with each value of the iterable.
+func (api *abortAPI) forEachIterated(iterable sobek.Value, callback func(sobek.Value)) {
+	rt := api.rt
+	obj, ok := iterable.(*sobek.Object)
+	if !ok {
+		webidl.ThrowTypeError(rt, "the value isn't iterable")
+	}
+	iteratorMethod, ok := sobek.AssertFunction(obj.GetSymbol(sobek.SymIterator))
+	if !ok {
+		webidl.ThrowTypeError(rt, "the value isn't iterable")
+	}
+	iteratorValue, err := iteratorMethod(obj)
+	webidl.Must(rt, err)
+	iterator, ok := iteratorValue.(*sobek.Object)
+	if !ok {
+		webidl.ThrowTypeError(rt, "the iterator isn't an object")
+	}
+	next, ok := sobek.AssertFunction(iterator.Get("next"))
+	if !ok {
+		webidl.ThrowTypeError(rt, "the iterator doesn't have a next method")
+	}
+	for {
+		result, err := next(iterator)
+		webidl.Must(rt, err)
+		resultObj := result.ToObject(rt)
+		if resultObj.Get("done").ToBoolean() {
+			return
+		}
+		callback(resultObj.Get("value"))
+	}
+}
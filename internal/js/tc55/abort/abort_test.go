@@ -0,0 +1,196 @@
+package abort_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/internal/js/tc55/abort"
+	"go.k6.io/k6/js/modulestest"
+)
+
+func TestAbortController(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		script string
+	}{
+		{
+			name: "abort",
+			script: `
+				var controller = new AbortController();
+				var signal = controller.signal;
+				if (signal.aborted || signal.reason !== undefined || controller.signal !== signal) {
+					throw new Error("wrong initial state");
+				}
+				if (String(controller) !== "[object AbortController]" || String(signal) !== "[object AbortSignal]") {
+					throw new Error("wrong string tags");
+				}
+				controller.abort();
+				if (!signal.aborted || signal.reason.name !== "AbortError" || signal.reason.code !== 20) {
+					throw new Error("wrong default reason: " + signal.reason);
+				}
+				var reason = signal.reason;
+				controller.abort("ignored");
+				if (signal.reason !== reason) { throw new Error("the signal was aborted twice"); }
+
+				var thrown;
+				try { signal.throwIfAborted(); } catch (e) { thrown = e; }
+				if (thrown !== reason) { throw new Error("throwIfAborted didn't throw the reason"); }
+			`,
+		},
+		{
+			name: "listeners",
+			script: `
+				var controller = new AbortController();
+				var signal = controller.signal;
+				var calls = [];
+				var listener = (e) => calls.push("listener:" + e.type + ":" + (e.target === signal));
+				signal.addEventListener("abort", listener);
+				signal.addEventListener("abort", listener);
+				signal.onabort = () => calls.push("onabort");
+				signal.addEventListener("abort", { handleEvent() { calls.push("handleEvent"); } }, { once: true });
+				var removed = () => calls.push("removed");
+				signal.addEventListener("abort", removed);
+				signal.removeEventListener("abort", removed);
+				controller.abort("reason");
+
+				if (calls.join() !== "listener:abort:true,onabort,handleEvent") { throw new Error("wrong calls: " + calls); }
+				if (signal.reason !== "reason") { throw new Error("wrong reason: " + signal.reason); }
+			`,
+		},
+		{
+			name: "illegal",
+			script: `
+				var thrown = false;
+				try { new AbortSignal(); } catch (e) { thrown = e instanceof TypeError; }
+				if (!thrown) { throw new Error("the AbortSignal constructor didn't throw a TypeError"); }
+				thrown = false;
+				try { AbortSignal.prototype.throwIfAborted.call({}); } catch (e) { thrown = e instanceof TypeError; }
+				if (!thrown) { throw new Error("an illegal invocation didn't throw a TypeError"); }
+			`,
+		},
+		{
+			name: "static abort",
+			script: `
+				var signal = AbortSignal.abort("reason");
+				if (!signal.aborted || signal.reason !== "reason") { throw new Error("the signal isn't aborted"); }
+				if (AbortSignal.abort().reason.name !== "AbortError") { throw new Error("wrong default reason"); }
+			`,
+		},
+		{
+			name: "any",
+			script: `
+				var first = new AbortController();
+				var second = new AbortController();
+				var signal = AbortSignal.any([first.signal, second.signal]);
+				var dependent = AbortSignal.any([signal]);
+				var calls = [];
+				signal.onabort = () => calls.push("signal");
+				dependent.onabort = () => calls.push("dependent");
+				second.abort("second");
+				first.abort("first");
+				if (signal.reason !== "second" || dependent.reason !== "second") { throw new Error("wrong reasons"); }
+				if (calls.join() !== "signal,dependent") { throw new Error("wrong calls: " + calls); }
+
+				if (AbortSignal.any([AbortSignal.abort("aborted")]).reason !== "aborted") {
+					throw new Error("an already aborted signal wasn't followed");
+				}
+				var thrown = false;
+				try { AbortSignal.any([{}]); } catch (e) { thrown = e instanceof TypeError; }
+				if (!thrown) { throw new Error("a wrong signal didn't throw a TypeError"); }
+			`,
+		},
+		{
+			name: "expired timeout",
+			script: `
+				var signal = AbortSignal.timeout(0);
+				if (!signal.aborted || signal.reason.name !== "TimeoutError" || signal.reason.code !== 23) {
+					throw new Error("the signal isn't aborted: " + signal.reason);
+				}
+				if (!AbortSignal.any([AbortSignal.timeout(0)]).aborted) {
+					throw new Error("the dependent signal isn't aborted");
+				}
+				var thrown = false;
+				try { AbortSignal.timeout(-1); } catch (e) { thrown = e instanceof TypeError; }
+				if (!thrown) { throw new Error("a negative timeout didn't throw a TypeError"); }
+			`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			runtime := modulestest.NewRuntime(t)
+			_, err := runtime.VU.Runtime().RunString(tt.script)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestAbortSignalTimeout(t *testing.T) {
+	t.Parallel()
+
+	runtime := modulestest.NewRuntime(t)
+	start := time.Now()
+	_, err := runtime.RunOnEventLoop(`
+		var signal = AbortSignal.any([AbortSignal.timeout(50)]);
+		signal.addEventListener("abort", () => {
+			if (signal.reason.name !== "TimeoutError") { throw new Error("wrong reason: " + signal.reason); }
+			globalThis.fired = true;
+		});
+
+		// A signal nobody listens to doesn't keep the event loop running
+		var unused = AbortSignal.timeout(60000);
+		var listener = () => {};
+		unused.addEventListener("abort", listener);
+		unused.removeEventListener("abort", listener);
+	`)
+	require.NoError(t, err)
+	assert.True(t, runtime.VU.Runtime().Get("fired").ToBoolean())
+	assert.Less(t, time.Since(start), 10*time.Second)
+}
+
+func TestFollow(t *testing.T) {
+	t.Parallel()
+
+	runtime := modulestest.NewRuntime(t)
+	rt := runtime.VU.Runtime()
+
+	controller, err := rt.RunString(`new AbortController()`)
+	require.NoError(t, err)
+	signal := controller.ToObject(rt).Get("signal")
+
+	op, err := abort.Follow(rt, context.Background(), signal)
+	require.NoError(t, err)
+	require.NoError(t, op.Context().Err())
+
+	require.NoError(t, rt.Set("controller", controller))
+	_, err = rt.RunString(`
+		var reason = new Error("stop");
+		reason.name = "StopError";
+		controller.abort(reason);
+	`)
+	require.NoError(t, err)
+
+	assert.True(t, op.Aborted())
+	assert.Equal(t, "StopError: stop", op.Reason().String())
+	var abortErr *abort.Error
+	require.True(t, errors.As(context.Cause(op.Context()), &abortErr))
+	assert.Equal(t, "StopError: stop", abortErr.Error())
+	require.NoError(t, op.Done())
+
+	op, err = abort.Follow(rt, context.Background(), nil)
+	require.NoError(t, err)
+	require.NoError(t, op.Done())
+	assert.False(t, op.Aborted())
+
+	_, err = abort.Follow(rt, context.Background(), rt.ToValue("bogus"))
+	require.Error(t, err)
+}
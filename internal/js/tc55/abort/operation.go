@@ -0,0 +1,135 @@
+package abort
+
+import (
+	"context"
+	"errors"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/internal/js/webidl"
+	"go.k6.io/k6/js/common"
+)
+
+// Error is the cause of the cancellation of the context of an operation
+// aborted by its signal. Its message is the reason of the abort.
+type Error struct {
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return e.Reason
+}
+
+// Operation ties an asynchronous operation of a module to the AbortSignal it was
+// started with: its context is canceled, with an *Error as the cause, as soon as the
+// signal is aborted. Any object with the interface of AbortSignal is accepted as the
+// signal, and an operation started without a signal is never aborted.
+//
+// All the methods but Context must be called on the event loop.
+type Operation struct {
+	rt     *sobek.Runtime
+	ctx    context.Context //nolint:containedctx
+	cancel context.CancelCauseFunc
+
+	signal   *sobek.Object
+	listener sobek.Value
+	aborted  bool
+	reason   sobek.Value
+}
+
+// SignalParam returns the signal of the params of an asynchronous operation, or nil if it doesn't have one.
+func SignalParam(rt *sobek.Runtime, params sobek.Value) sobek.Value {
+	if common.IsNullish(params) {
+		return nil
+	}
+	signal := params.ToObject(rt).Get("signal")
+	if common.IsNullish(signal) {
+		return nil
+	}
+	return signal
+}
+
+// Follow starts following the signal for an operation running with a context derived from
+// the parent one. The returned operation must be marked as done once it has finished.
+func Follow(rt *sobek.Runtime, parent context.Context, signal sobek.Value) (*Operation, error) {
+	ctx, cancel := context.WithCancelCause(parent)
+	op := &Operation{rt: rt, ctx: ctx, cancel: cancel}
+	if common.IsNullish(signal) {
+		return op, nil
+	}
+
+	obj, ok := signal.(*sobek.Object)
+	if !ok {
+		cancel(nil)
+		return nil, errors.New("the signal must be an AbortSignal")
+	}
+	addEventListener, ok := sobek.AssertFunction(obj.Get("addEventListener"))
+	if !ok {
+		cancel(nil)
+		return nil, errors.New("the signal must be an AbortSignal")
+	}
+	op.signal = obj
+
+	if obj.Get("aborted").ToBoolean() {
+		op.abort()
+		return op, nil
+	}
+	op.listener = rt.ToValue(func(sobek.FunctionCall) sobek.Value {
+		op.abort()
+		return sobek.Undefined()
+	})
+	if _, err := addEventListener(obj, rt.ToValue("abort"), op.listener); err != nil {
+		cancel(nil)
+		return nil, err
+	}
+	return op, nil
+}
+
+func (op *Operation) abort() {
+	if op.aborted {
+		return
+	}
+	op.aborted = true
+	op.reason = op.signal.Get("reason")
+	if common.IsNullish(op.reason) {
+		abortError, err := op.rt.New(op.rt.Get("Error"), op.rt.ToValue("the operation was aborted"))
+		webidl.Must(op.rt, err)
+		webidl.Must(op.rt, abortError.Set("name", "AbortError"))
+		op.reason = abortError
+	}
+	op.cancel(&Error{Reason: op.reason.String()})
+}
+
+// Context returns the context of the operation, which is canceled when the signal is aborted.
+func (op *Operation) Context() context.Context {
+	return op.ctx
+}
+
+// Aborted returns whether the signal was aborted before the operation was done.
+func (op *Operation) Aborted() bool {
+	return op.aborted
+}
+
+// Reason returns the reason the signal was aborted with, which the promise
+// of the operation is expected to be rejected with.
+func (op *Operation) Reason() sobek.Value {
+	return op.reason
+}
+
+// Done stops following the signal and releases the resources of the context.
+// The signal can't abort the operation anymore after it's done.
+func (op *Operation) Done() error {
+	op.cancel(nil)
+	if op.listener == nil {
+		return nil
+	}
+	listener := op.listener
+	op.listener = nil
+	removeEventListener, ok := sobek.AssertFunction(op.signal.Get("removeEventListener"))
+	if !ok {
+		return nil
+	}
+	_, err := removeEventListener(op.signal, op.rt.ToValue("abort"), listener)
+	return err
+}
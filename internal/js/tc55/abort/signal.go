@@ -0,0 +1,270 @@
+package abort
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/internal/js/webidl"
+)
+
+// abortSignal is the Go side of the AbortSignal objects, see https://dom.spec.whatwg.org/#interface-AbortSignal
+type abortSignal struct {
+	webidl.NoOwnProperties
+
+	api *abortAPI
+	obj *sobek.Object
+
+	aborted bool
+	reason  sobek.Value
+
+	onabort   sobek.Value
+	listeners []*eventListener
+
+	// The signals a dependent signal created with AbortSignal.any follows,
+	// and the dependent signals following a signal.
+	dependent  bool
+	sources    []*abortSignal
+	dependents []*abortSignal
+
+	// The deadline of the signals created with AbortSignal.timeout. Their timer only
+	// runs while something waits for them to be aborted, i.e. while they, or the
+	// signals depending on them, have abort listeners. This way a signal nobody
+	// listens to anymore doesn't keep the event loop, and so the iteration, running.
+	deadline  time.Time
+	retained  int
+	stopTimer chan struct{}
+}
+
+// eventListener is a listener added with addEventListener, or the listener
+// calling the onabort event handler when the callback is nil.
+type eventListener struct {
+	eventType string
+	callback  sobek.Value
+	once      bool
+	removed   bool
+	retaining bool
+}
+
+func (api *abortAPI) newSignal() *abortSignal {
+	s := &abortSignal{api: api}
+	s.obj = webidl.NewInstance(api.rt, s, api.signalProto)
+	return s
+}
+
+// isAborted returns whether the signal is aborted, after aborting it if its timeout,
+// or the one of a signal it depends on, has expired while its timer wasn't running.
+func (s *abortSignal) isAborted() bool {
+	if s.aborted {
+		return true
+	}
+	for _, source := range append([]*abortSignal{s}, s.sources...) {
+		if !source.aborted && !source.deadline.IsZero() && !time.Now().Before(source.deadline) {
+			if err := source.signalAbort(source.api.timeoutError()); err != nil {
+				panic(err)
+			}
+		}
+	}
+	return s.aborted
+}
+
+func (api *abortAPI) timeoutError() sobek.Value {
+	return api.newError("TimeoutError", "signal timed out", timeoutErrorCode)
+}
+
+func (s *abortSignal) hasSource(source *abortSignal) bool {
+	return slices.Contains(s.sources, source)
+}
+
+// signalAbort aborts the signal and the signals depending on it, see https://dom.spec.whatwg.org/#abortsignal-signal-abort
+func (s *abortSignal) signalAbort(reason sobek.Value) error {
+	if s.aborted {
+		return nil
+	}
+	s.aborted = true
+	s.reason = reason
+	s.stop()
+
+	var dependentsToAbort []*abortSignal
+	for _, d := range s.dependents {
+		if !d.aborted {
+			d.aborted = true
+			d.reason = reason
+			d.stop()
+			dependentsToAbort = append(dependentsToAbort, d)
+		}
+	}
+
+	err := s.fireAbort()
+	for _, d := range dependentsToAbort {
+		if dErr := d.fireAbort(); err == nil {
+			err = dErr
+		}
+	}
+	return err
+}
+
+// fireAbort calls the abort listeners in the order they were added. All of them are
+// called even if some throw, and the first exception is returned.
+func (s *abortSignal) fireAbort() error {
+	rt := s.api.rt
+	event := rt.NewObject()
+	webidl.Must(rt, event.Set("type", "abort"))
+	webidl.Must(rt, event.Set("target", s.obj))
+	webidl.Must(rt, event.Set("currentTarget", s.obj))
+
+	var firstErr error
+	for _, l := range slices.Clone(s.listeners) {
+		if l.removed || l.eventType != "abort" {
+			continue
+		}
+		if l.once {
+			s.removeListener(l)
+		}
+		if err := s.callListener(l, event); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (s *abortSignal) callListener(l *eventListener, event *sobek.Object) error {
+	callback, this := l.callback, sobek.Value(s.obj)
+	if callback == nil {
+		callback = s.onabort
+	}
+	fn, ok := sobek.AssertFunction(callback)
+	if !ok {
+		obj, isObject := callback.(*sobek.Object)
+		if !isObject {
+			return nil
+		}
+		if fn, ok = sobek.AssertFunction(obj.Get("handleEvent")); !ok {
+			return errors.New("the event listener doesn't have a handleEvent method")
+		}
+		this = obj
+	}
+	_, err := fn(this, event)
+	return err
+}
+
+func (s *abortSignal) addEventListener(eventType string, callback sobek.Value, once bool) {
+	if callback == nil || sobek.IsNull(callback) || sobek.IsUndefined(callback) {
+		return
+	}
+	for _, l := range s.listeners {
+		if l.eventType == eventType && l.callback != nil && l.callback.SameAs(callback) {
+			return
+		}
+	}
+	s.appendListener(&eventListener{eventType: eventType, callback: callback, once: once})
+}
+
+func (s *abortSignal) removeEventListener(eventType string, callback sobek.Value) {
+	for _, l := range s.listeners {
+		if l.eventType == eventType && l.callback != nil && l.callback.SameAs(callback) {
+			s.removeListener(l)
+			return
+		}
+	}
+}
+
+// setEventHandler sets the onabort event handler, which is called at the position
+// of the listeners it had when it was first set, see https://html.spec.whatwg.org/#event-handler-attributes
+func (s *abortSignal) setEventHandler(handler sobek.Value) {
+	if _, ok := handler.(*sobek.Object); !ok {
+		handler = nil
+	}
+	s.onabort = handler
+
+	i := slices.IndexFunc(s.listeners, func(l *eventListener) bool { return l.callback == nil })
+	switch {
+	case handler == nil && i >= 0:
+		s.removeListener(s.listeners[i])
+	case handler != nil && i < 0:
+		s.appendListener(&eventListener{eventType: "abort"})
+	}
+}
+
+func (s *abortSignal) appendListener(l *eventListener) {
+	if l.eventType == "abort" && !s.aborted {
+		l.retaining = true
+		s.retain()
+	}
+	s.listeners = append(s.listeners, l)
+}
+
+func (s *abortSignal) removeListener(l *eventListener) {
+	l.removed = true
+	s.listeners = slices.DeleteFunc(s.listeners, func(other *eventListener) bool { return other == l })
+	if l.retaining {
+		l.retaining = false
+		s.release()
+	}
+}
+
+// retain is called when something starts waiting for the signal to be aborted,
+// and starts the timers of the signal and of the signals it depends on.
+func (s *abortSignal) retain() {
+	s.retained++
+	if s.retained > 1 {
+		return
+	}
+	for _, source := range s.sources {
+		source.retain()
+	}
+	s.start()
+}
+
+// release is called when something stops waiting for the signal to be aborted.
+func (s *abortSignal) release() {
+	s.retained--
+	if s.retained > 0 {
+		return
+	}
+	for _, source := range s.sources {
+		source.release()
+	}
+	s.stop()
+}
+
+// start starts the timer of a signal created with AbortSignal.timeout, which
+// aborts the signal on the event loop once its deadline is reached.
+func (s *abortSignal) start() {
+	if s.deadline.IsZero() || s.aborted || s.stopTimer != nil {
+		return
+	}
+
+	vu := s.api.vu
+	stopTimer := make(chan struct{})
+	s.stopTimer = stopTimer
+	callback := vu.RegisterCallback()
+	ctx := vu.Context()
+	timer := time.NewTimer(time.Until(s.deadline))
+	go func() {
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			callback(func() error {
+				if s.stopTimer == stopTimer {
+					s.stopTimer = nil
+				}
+				return s.signalAbort(s.api.timeoutError())
+			})
+		case <-stopTimer:
+			callback(func() error { return nil })
+		case <-ctx.Done():
+			callback(func() error { return nil })
+		}
+	}()
+}
+
+// stop stops the timer of the signal if it's running.
+func (s *abortSignal) stop() {
+	if s.stopTimer != nil {
+		close(s.stopTimer)
+		s.stopTimer = nil
+	}
+}
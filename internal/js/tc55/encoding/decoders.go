@@ -0,0 +1,214 @@
+package encoding
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/transform"
+)
+
+// utf8Decoder implements the UTF-8 decoder of the standard, which replaces each maximal
+// invalid subsequence with a single U+FFFD, see https://encoding.spec.whatwg.org/#utf-8-decoder
+type utf8Decoder struct {
+	fatal bool
+
+	codePoint   rune
+	bytesSeen   int
+	bytesNeeded int
+	lowerBound  byte
+	upperBound  byte
+}
+
+func (d *utf8Decoder) reset() {
+	d.codePoint, d.bytesSeen, d.bytesNeeded = 0, 0, 0
+	d.lowerBound, d.upperBound = 0x80, 0xBF
+}
+
+func (d *utf8Decoder) decode(chunk []byte, flush bool) (string, error) {
+	var output strings.Builder
+	output.Grow(len(chunk))
+
+	for i := 0; i < len(chunk); i++ {
+		b := chunk[i]
+		if d.bytesNeeded == 0 {
+			switch {
+			case b <= 0x7F:
+				output.WriteByte(b)
+			case b >= 0xC2 && b <= 0xDF:
+				d.bytesNeeded, d.codePoint = 1, rune(b&0x1F)
+			case b >= 0xE0 && b <= 0xEF:
+				if b == 0xE0 {
+					d.lowerBound = 0xA0
+				} else if b == 0xED {
+					d.upperBound = 0x9F
+				}
+				d.bytesNeeded, d.codePoint = 2, rune(b&0xF)
+			case b >= 0xF0 && b <= 0xF4:
+				if b == 0xF0 {
+					d.lowerBound = 0x90
+				} else if b == 0xF4 {
+					d.upperBound = 0x8F
+				}
+				d.bytesNeeded, d.codePoint = 3, rune(b&0x7)
+			default:
+				if d.fatal {
+					return "", errInvalidData
+				}
+				output.WriteRune(utf8.RuneError)
+			}
+			continue
+		}
+
+		if b < d.lowerBound || b > d.upperBound {
+			// The byte isn't part of the sequence, so it's processed again after the error
+			d.reset()
+			if d.fatal {
+				return "", errInvalidData
+			}
+			output.WriteRune(utf8.RuneError)
+			i--
+			continue
+		}
+
+		d.lowerBound, d.upperBound = 0x80, 0xBF
+		d.codePoint = d.codePoint<<6 | rune(b&0x3F)
+		d.bytesSeen++
+		if d.bytesSeen == d.bytesNeeded {
+			output.WriteRune(d.codePoint)
+			d.reset()
+		}
+	}
+
+	if flush && d.bytesNeeded != 0 {
+		d.reset()
+		if d.fatal {
+			return "", errInvalidData
+		}
+		output.WriteRune(utf8.RuneError)
+	}
+	return output.String(), nil
+}
+
+// utf16Decoder implements the UTF-16LE and UTF-16BE decoders of the standard,
+// see https://encoding.spec.whatwg.org/#shared-utf-16-decoder
+type utf16Decoder struct {
+	fatal     bool
+	bigEndian bool
+
+	leadByte      int
+	leadSurrogate int
+}
+
+func (d *utf16Decoder) reset() {
+	d.leadByte, d.leadSurrogate = -1, -1
+}
+
+func (d *utf16Decoder) decode(chunk []byte, flush bool) (string, error) {
+	var output strings.Builder
+	output.Grow(len(chunk))
+
+	for _, b := range chunk {
+		if d.leadByte == -1 {
+			d.leadByte = int(b)
+			continue
+		}
+		codeUnit := d.leadByte | int(b)<<8
+		if d.bigEndian {
+			codeUnit = d.leadByte<<8 | int(b)
+		}
+		d.leadByte = -1
+
+		if d.leadSurrogate != -1 {
+			leadSurrogate := d.leadSurrogate
+			d.leadSurrogate = -1
+			if codeUnit >= 0xDC00 && codeUnit <= 0xDFFF {
+				output.WriteRune(rune(0x10000 + (leadSurrogate-0xD800)<<10 + (codeUnit - 0xDC00)))
+				continue
+			}
+			// The lead surrogate is unpaired, and the code unit is processed as a new one
+			if d.fatal {
+				d.reset()
+				return "", errInvalidData
+			}
+			output.WriteRune(utf8.RuneError)
+		}
+
+		switch {
+		case codeUnit >= 0xD800 && codeUnit <= 0xDBFF:
+			d.leadSurrogate = codeUnit
+		case codeUnit >= 0xDC00 && codeUnit <= 0xDFFF:
+			if d.fatal {
+				d.reset()
+				return "", errInvalidData
+			}
+			output.WriteRune(utf8.RuneError)
+		default:
+			output.WriteRune(rune(codeUnit))
+		}
+	}
+
+	if flush && (d.leadByte != -1 || d.leadSurrogate != -1) {
+		d.reset()
+		if d.fatal {
+			return "", errInvalidData
+		}
+		output.WriteRune(utf8.RuneError)
+	}
+	return output.String(), nil
+}
+
+// legacyDecoder decodes the legacy encodings with their x/text implementations, keeping
+// the bytes of an incomplete sequence at the end of a chunk until the next one.
+//
+// The decoders of x/text replace the invalid sequences with U+FFFD, which only gb18030
+// has a valid sequence for, so in fatal mode its presence in the output is handled
+// as the input being invalid.
+type legacyDecoder struct {
+	fatal    bool
+	encoding encoding.Encoding
+
+	transformer transform.Transformer
+	pending     []byte
+}
+
+func (d *legacyDecoder) reset() {
+	d.transformer = nil
+	d.pending = nil
+}
+
+func (d *legacyDecoder) decode(chunk []byte, flush bool) (string, error) {
+	if d.transformer == nil {
+		d.transformer = d.encoding.NewDecoder()
+	}
+	src := append(d.pending, chunk...) //nolint:gocritic // pending isn't used after this
+	d.pending = nil
+
+	var output []byte
+	dst := make([]byte, 3*len(src)+utf8.UTFMax)
+	for {
+		nDst, nSrc, err := d.transformer.Transform(dst, src, flush)
+		output = append(output, dst[:nDst]...)
+		src = src[nSrc:]
+
+		if errors.Is(err, transform.ErrShortDst) {
+			dst = make([]byte, 2*len(dst))
+			continue
+		}
+		if errors.Is(err, transform.ErrShortSrc) {
+			d.pending = append([]byte(nil), src...)
+		} else if err != nil {
+			return "", err
+		}
+		break
+	}
+	if flush {
+		d.reset()
+	}
+
+	if d.fatal && strings.ContainsRune(string(output), utf8.RuneError) {
+		return "", errInvalidData
+	}
+	return string(output), nil
+}
@@ -0,0 +1,57 @@
+// Package encoding implements the TextEncoder and TextDecoder classes of the
+// Encoding Standard (https://encoding.spec.whatwg.org) as globals.
+package encoding
+
+import (
+	"fmt"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/js/modules"
+)
+
+// SetupGlobally sets the TextEncoder and TextDecoder classes to be accessible globally.
+func SetupGlobally(vu modules.VU) error {
+	rt := vu.Runtime()
+
+	encoder := rt.ToValue(newTextEncoder).ToObject(rt)
+	defineTextEncoderPrototype(rt, encoder.Get("prototype").ToObject(rt))
+
+	decoder := rt.ToValue(newTextDecoder).ToObject(rt)
+	defineTextDecoderPrototype(rt, decoder.Get("prototype").ToObject(rt))
+
+	mapping := map[string]any{
+		"TextEncoder": encoder,
+		"TextDecoder": decoder,
+	}
+	for k, v := range mapping {
+		if err := rt.Set(k, v); err != nil {
+			return fmt.Errorf("error setting up %q globally: %w", k, err)
+		}
+	}
+	return nil
+}
+
+// bufferSourceBytes returns the bytes of an ArrayBuffer or an ArrayBufferView,
+// sharing their memory.
+func bufferSourceBytes(rt *sobek.Runtime, v sobek.Value) ([]byte, bool) {
+	obj, ok := v.(*sobek.Object)
+	if !ok {
+		return nil, false
+	}
+	if ab, ok := obj.Export().(sobek.ArrayBuffer); ok {
+		return ab.Bytes(), true
+	}
+
+	isView, _ := sobek.AssertFunction(rt.Get("ArrayBuffer").ToObject(rt).Get("isView"))
+	if result, err := isView(sobek.Undefined(), obj); err != nil || !result.ToBoolean() {
+		return nil, false
+	}
+	ab, ok := obj.Get("buffer").Export().(sobek.ArrayBuffer)
+	if !ok {
+		return nil, false
+	}
+	offset := obj.Get("byteOffset").ToInteger()
+	length := obj.Get("byteLength").ToInteger()
+	return ab.Bytes()[offset : offset+length], true
+}
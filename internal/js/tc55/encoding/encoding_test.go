@@ -0,0 +1,150 @@
+package encoding_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/internal/js/tc55/encoding"
+	"go.k6.io/k6/js/modulestest"
+)
+
+func TestTextEncoder(t *testing.T) {
+	t.Parallel()
+
+	runtime := modulestest.NewRuntime(t)
+	_, err := runtime.VU.Runtime().RunString(`
+		var encoder = new TextEncoder();
+		if (encoder.encoding !== "utf-8") { throw new Error("wrong encoding: " + encoder.encoding); }
+		if (String(encoder) !== "[object TextEncoder]") { throw new Error("wrong string tag: " + String(encoder)); }
+
+		var encoded = encoder.encode("a€😀\uD800");
+		if (!(encoded instanceof Uint8Array)) { throw new Error("the result isn't an Uint8Array"); }
+		var expected = "97,226,130,172,240,159,152,128,239,191,189";
+		if (encoded.join(",") !== expected) { throw new Error("wrong bytes: " + encoded.join(",")); }
+		if (encoder.encode().length !== 0) { throw new Error("encoding undefined isn't empty"); }
+
+		var destination = new Uint8Array(8);
+		var result = encoder.encodeInto("a€😀b", destination.subarray(1));
+		if (result.read !== 2 || result.written !== 4) { throw new Error("wrong result: " + JSON.stringify(result)); }
+		if (destination.join(",") !== "0,97,226,130,172,0,0,0") { throw new Error("wrong destination: " + destination); }
+
+		var thrown = false;
+		try { encoder.encodeInto("a", new Uint16Array(1)); } catch (e) { thrown = e instanceof TypeError; }
+		if (!thrown) { throw new Error("encoding into an Uint16Array didn't throw a TypeError"); }
+	`)
+	require.NoError(t, err)
+}
+
+func TestTextDecoder(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		script string
+	}{
+		{
+			name: "utf-8",
+			script: `
+				var decoder = new TextDecoder();
+				if (decoder.encoding !== "utf-8" || decoder.fatal || decoder.ignoreBOM) { throw new Error("wrong attributes"); }
+				var decoded = decoder.decode(new Uint8Array([0xEF, 0xBB, 0xBF, 0x61, 0xE2, 0x82, 0xAC]));
+				if (decoded !== "a€") { throw new Error("wrong result: " + decoded); }
+				decoded = decoder.decode(new Uint8Array([0x61, 0xF0, 0x9F, 0x98, 0x62, 0xFF]).buffer);
+				if (decoded !== "a\uFFFDb\uFFFD") { throw new Error("wrong replacement: " + escape(decoded)); }
+				if (decoder.decode() !== "") { throw new Error("decoding undefined isn't empty"); }
+			`,
+		},
+		{
+			name: "ignoreBOM",
+			script: `
+				var decoder = new TextDecoder("utf-8", {ignoreBOM: true});
+				var decoded = decoder.decode(new Uint8Array([0xEF, 0xBB, 0xBF, 0x61]));
+				if (decoded !== "\uFEFFa") { throw new Error("wrong result: " + escape(decoded)); }
+			`,
+		},
+		{
+			name: "fatal",
+			script: `
+				var decoder = new TextDecoder("utf-8", {fatal: true});
+				var thrown = false;
+				try { decoder.decode(new Uint8Array([0x61, 0xC0])); } catch (e) { thrown = e instanceof TypeError; }
+				if (!thrown) { throw new Error("invalid data didn't throw a TypeError"); }
+				if (decoder.decode(new Uint8Array([0x61])) !== "a") { throw new Error("the decoder wasn't reset"); }
+			`,
+		},
+		{
+			name: "streaming",
+			script: `
+				var decoder = new TextDecoder("utf-16le");
+				var bytes = [0xFF, 0xFE, 0x3D, 0xD8, 0x00, 0xDE, 0x61, 0x00];
+				var decoded = "";
+				for (var i = 0; i < bytes.length; i++) {
+					decoded += decoder.decode(new Uint8Array([bytes[i]]), {stream: true});
+				}
+				decoded += decoder.decode();
+				if (decoded !== "😀a") { throw new Error("wrong result: " + escape(decoded)); }
+
+				decoder.decode(new Uint8Array([0x3D]), {stream: true});
+				decoded = decoder.decode();
+				if (decoded !== "\uFFFD") { throw new Error("an incomplete sequence wasn't replaced: " + escape(decoded)); }
+			`,
+		},
+		{
+			name: "utf-16be",
+			script: `
+				var decoder = new TextDecoder("UTF-16BE");
+				var decoded = decoder.decode(new Uint8Array([0x00, 0x61, 0xDC, 0x00, 0x00, 0x62]));
+				if (decoded !== "a\uFFFDb") { throw new Error("wrong result: " + escape(decoded)); }
+			`,
+		},
+		{
+			name: "legacy",
+			script: `
+				var decoder = new TextDecoder("latin1");
+				if (decoder.encoding !== "windows-1252") { throw new Error("wrong encoding: " + decoder.encoding); }
+				var decoded = decoder.decode(new Uint8Array([0x63, 0x61, 0x66, 0xE9, 0x80]));
+				if (decoded !== "café€") { throw new Error("wrong result: " + decoded); }
+
+				decoder = new TextDecoder("shift_jis");
+				decoded = decoder.decode(new Uint8Array([0x82]), {stream: true});
+				decoded += decoder.decode(new Uint8Array([0xA0]));
+				if (decoded !== "あ") { throw new Error("wrong streamed result: " + escape(decoded)); }
+
+				decoder = new TextDecoder("euc-kr", {fatal: true});
+				var thrown = false;
+				try { decoder.decode(new Uint8Array([0xFF, 0xFF])); } catch (e) { thrown = e instanceof TypeError; }
+				if (!thrown) { throw new Error("invalid data didn't throw a TypeError"); }
+			`,
+		},
+		{
+			name: "invalid label",
+			script: `
+				for (var label of ["bogus", "replacement", "iso-2022-kr"]) {
+					var thrown = false;
+					try { new TextDecoder(label); } catch (e) { thrown = e instanceof RangeError; }
+					if (!thrown) { throw new Error(label + " didn't throw a RangeError"); }
+				}
+			`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			runtime := modulestest.NewRuntime(t)
+			_, err := runtime.VU.Runtime().RunString(tt.script)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestDecodeUTF8(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "a€", encoding.DecodeUTF8([]byte("a\xE2\x82\xAC")))
+	// Each maximal subpart of an invalid sequence is replaced with a single U+FFFD
+	assert.Equal(t, "\uFFFDa\uFFFD\uFFFD", encoding.DecodeUTF8([]byte("\xF0\x9F\x98a\xED\xA0")))
+}
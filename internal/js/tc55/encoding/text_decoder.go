@@ -0,0 +1,153 @@
+package encoding
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/grafana/sobek"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+
+	"go.k6.io/k6/internal/js/webidl"
+)
+
+// errInvalidData is returned by the decoders in fatal mode when the input isn't valid for the encoding.
+var errInvalidData = errors.New("the encoded data isn't valid")
+
+// decoder decodes a stream of bytes in chunks, keeping the state of an incomplete
+// sequence at the end of a chunk until the next one, see https://encoding.spec.whatwg.org/#decoder
+type decoder interface {
+	// decode decodes the chunk, finishing the stream if flush is true.
+	decode(chunk []byte, flush bool) (string, error)
+	// reset discards the state of the stream.
+	reset()
+}
+
+// textDecoder is the Go side of the TextDecoder objects, see
+// https://encoding.spec.whatwg.org/#interface-textdecoder
+type textDecoder struct {
+	webidl.NoOwnProperties
+
+	encoding  string
+	fatal     bool
+	ignoreBOM bool
+
+	decoder    decoder
+	doNotFlush bool
+	bomSeen    bool
+}
+
+func newTextDecoder(call sobek.ConstructorCall, rt *sobek.Runtime) *sobek.Object {
+	label := "utf-8"
+	if v := call.Argument(0); !sobek.IsUndefined(v) {
+		label = v.String()
+	}
+	td := &textDecoder{}
+	if v := call.Argument(1); !sobek.IsUndefined(v) && !sobek.IsNull(v) {
+		options := v.ToObject(rt)
+		td.fatal = options.Get("fatal") != nil && options.Get("fatal").ToBoolean()
+		td.ignoreBOM = options.Get("ignoreBOM") != nil && options.Get("ignoreBOM").ToBoolean()
+	}
+
+	enc, err := htmlindex.Get(label)
+	if err != nil || enc == encoding.Replacement {
+		webidl.ThrowRangeError(rt, "the encoding %q isn't supported", label)
+	}
+	td.encoding, _ = htmlindex.Name(enc)
+	td.decoder = newDecoder(td.encoding, enc, td.fatal)
+
+	return webidl.NewInstance(rt, td, call.This.Prototype())
+}
+
+// newDecoder returns the decoder of an encoding. The UTF-8 and UTF-16 ones implement the
+// error handling of the standard, while the legacy encodings are decoded with x/text.
+func newDecoder(name string, enc encoding.Encoding, fatal bool) decoder {
+	switch name {
+	case "utf-8":
+		return &utf8Decoder{fatal: fatal, lowerBound: 0x80, upperBound: 0xBF}
+	case "utf-16le":
+		return &utf16Decoder{fatal: fatal, leadByte: -1, leadSurrogate: -1}
+	case "utf-16be":
+		return &utf16Decoder{fatal: fatal, bigEndian: true, leadByte: -1, leadSurrogate: -1}
+	default:
+		return &legacyDecoder{fatal: fatal, encoding: enc}
+	}
+}
+
+// decode decodes a chunk of the stream, see https://encoding.spec.whatwg.org/#dom-textdecoder-decode
+func (td *textDecoder) decode(chunk []byte, stream bool) (string, error) {
+	if !td.doNotFlush {
+		td.decoder.reset()
+		td.bomSeen = false
+	}
+	td.doNotFlush = stream
+
+	output, err := td.decoder.decode(chunk, !stream)
+	if err != nil {
+		td.decoder.reset()
+		td.doNotFlush = false
+		return "", err
+	}
+
+	// The BOM is removed only from the start of the stream, and only
+	// for the UTF-8 and UTF-16 encodings.
+	if !td.bomSeen && output != "" {
+		td.bomSeen = true
+		if !td.ignoreBOM && strings.HasPrefix(td.encoding, "utf-") {
+			output = strings.TrimPrefix(output, "\uFEFF")
+		}
+	}
+	return output, nil
+}
+
+func thisTextDecoder(rt *sobek.Runtime, this sobek.Value) *textDecoder {
+	td, ok := this.Export().(*textDecoder)
+	if !ok {
+		webidl.ThrowTypeError(rt, "Illegal invocation")
+	}
+	return td
+}
+
+func defineTextDecoderPrototype(rt *sobek.Runtime, proto *sobek.Object) {
+	webidl.DefineGetter(rt, proto, "encoding", func(call sobek.FunctionCall) sobek.Value {
+		return rt.ToValue(thisTextDecoder(rt, call.This).encoding)
+	})
+	webidl.DefineGetter(rt, proto, "fatal", func(call sobek.FunctionCall) sobek.Value {
+		return rt.ToValue(thisTextDecoder(rt, call.This).fatal)
+	})
+	webidl.DefineGetter(rt, proto, "ignoreBOM", func(call sobek.FunctionCall) sobek.Value {
+		return rt.ToValue(thisTextDecoder(rt, call.This).ignoreBOM)
+	})
+	webidl.DefineMethod(rt, proto, "decode", func(call sobek.FunctionCall) sobek.Value {
+		td := thisTextDecoder(rt, call.This)
+
+		var input []byte
+		if v := call.Argument(0); !sobek.IsUndefined(v) {
+			var ok bool
+			if input, ok = bufferSourceBytes(rt, v); !ok {
+				webidl.ThrowTypeError(rt, "the input must be an ArrayBuffer or an ArrayBufferView")
+			}
+		}
+		var stream bool
+		if v := call.Argument(1); !sobek.IsUndefined(v) && !sobek.IsNull(v) {
+			if s := v.ToObject(rt).Get("stream"); s != nil {
+				stream = s.ToBoolean()
+			}
+		}
+
+		output, err := td.decode(input, stream)
+		if err != nil {
+			webidl.ThrowTypeError(rt, "the encoded data isn't valid %s", td.encoding)
+		}
+		return rt.ToValue(output)
+	})
+	webidl.DefineToStringTag(rt, proto, "TextDecoder")
+}
+
+// DecodeUTF8 decodes the UTF-8 bytes, replacing the invalid sequences with U+FFFD, as the
+// "UTF-8 decode without BOM" algorithm of the standard does. It's used by other Web APIs
+// implemented in Go, which have to decode bytes in the same way as TextDecoder.
+func DecodeUTF8(b []byte) string {
+	output, _ := (&utf8Decoder{lowerBound: 0x80, upperBound: 0xBF}).decode(b, true)
+	return output
+}
@@ -0,0 +1,81 @@
+package encoding
+
+import (
+	"unicode/utf8"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/internal/js/webidl"
+)
+
+// textEncoder is the Go side of the TextEncoder objects, see
+// https://encoding.spec.whatwg.org/#interface-textencoder
+type textEncoder struct {
+	webidl.NoOwnProperties
+}
+
+func newTextEncoder(call sobek.ConstructorCall, rt *sobek.Runtime) *sobek.Object {
+	return webidl.NewInstance(rt, &textEncoder{}, call.This.Prototype())
+}
+
+func thisTextEncoder(rt *sobek.Runtime, this sobek.Value) *textEncoder {
+	enc, ok := this.Export().(*textEncoder)
+	if !ok {
+		webidl.ThrowTypeError(rt, "Illegal invocation")
+	}
+	return enc
+}
+
+func defineTextEncoderPrototype(rt *sobek.Runtime, proto *sobek.Object) {
+	webidl.DefineGetter(rt, proto, "encoding", func(call sobek.FunctionCall) sobek.Value {
+		thisTextEncoder(rt, call.This)
+		return rt.ToValue("utf-8")
+	})
+	webidl.DefineMethod(rt, proto, "encode", func(call sobek.FunctionCall) sobek.Value {
+		thisTextEncoder(rt, call.This)
+		var input string
+		if v := call.Argument(0); !sobek.IsUndefined(v) {
+			// The conversion of the string replaces the lone surrogates with U+FFFD,
+			// as the conversion to an USVString does.
+			input = v.String()
+		}
+		array, err := rt.New(rt.Get("Uint8Array"), rt.ToValue(rt.NewArrayBuffer([]byte(input))))
+		webidl.Must(rt, err)
+		return array
+	})
+	webidl.DefineMethod(rt, proto, "encodeInto", func(call sobek.FunctionCall) sobek.Value {
+		thisTextEncoder(rt, call.This)
+		source := call.Argument(0).String()
+		destination := call.Argument(1)
+		if !rt.InstanceOf(destination, rt.Get("Uint8Array").ToObject(rt)) {
+			webidl.ThrowTypeError(rt, "the destination must be an Uint8Array")
+		}
+		dst, _ := bufferSourceBytes(rt, destination)
+
+		read, written := encodeInto(source, dst)
+		result := rt.NewObject()
+		webidl.Must(rt, result.Set("read", read))
+		webidl.Must(rt, result.Set("written", written))
+		return result
+	})
+	webidl.DefineToStringTag(rt, proto, "TextEncoder")
+}
+
+// encodeInto writes as many whole code points of the source as fit in the destination, returning
+// the number of UTF-16 code units read from the source and the number of bytes written, see
+// https://encoding.spec.whatwg.org/#dom-textencoder-encodeinto
+func encodeInto(source string, dst []byte) (read, written int) {
+	for _, r := range source {
+		size := utf8.RuneLen(r)
+		if written+size > len(dst) {
+			break
+		}
+		utf8.EncodeRune(dst[written:], r)
+		written += size
+		read++
+		if r >= 0x10000 {
+			read++ // the code points outside the BMP are a surrogate pair in UTF-16
+		}
+	}
+	return read, written
+}
@@ -0,0 +1,38 @@
+// Package tests runs part of the Web Platform Tests suite for the k6's native globals
+// implementing the web standards, e.g. TextEncoder, TextDecoder, URL and URLSearchParams.
+package tests
+
+import (
+	"testing"
+)
+
+func TestEncodingWebPlatformTestSuite(t *testing.T) {
+	t.Parallel()
+
+	// The tests using a SharedArrayBuffer aren't included, as it isn't supported
+	files := []string{
+		"api-basics.any.js",
+		"api-invalid-label.any.js",
+		"api-replacement-encodings.any.js",
+		"api-surrogates-utf8.any.js",
+		"textdecoder-arguments.any.js",
+		"textdecoder-byte-order-marks.any.js",
+		"textdecoder-eof.any.js",
+		"textdecoder-fatal.any.js",
+		"textdecoder-fatal-streaming.any.js",
+		"textdecoder-ignorebom.any.js",
+		"textdecoder-labels.any.js",
+		"textdecoder-utf16-surrogates.any.js",
+		"textencoder-constructor-non-utf.any.js",
+		"textencoder-utf16-surrogates.any.js",
+		"unsupported-encodings.any.js",
+	}
+
+	for _, file := range files {
+		t.Run(file, func(t *testing.T) {
+			t.Parallel()
+
+			runWebPlatformTest(t, "encoding", file)
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package tests
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/grafana/sobek"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/js/modulestest"
+)
+
+const webPlatformTestSuite = "./wpt"
+
+// The globals of a window that the test harness and the tests expect.
+const initGlobals = `
+	var self = this;
+	var location = { search: "" };
+`
+
+// newConfiguredRuntime initializes a new test setup, with the
+// Web Platform testharness and the globals under test set up.
+func newConfiguredRuntime(t testing.TB) *modulestest.Runtime {
+	rt := modulestest.NewRuntime(t)
+
+	_, err := rt.VU.Runtime().RunString(initGlobals)
+	require.NoError(t, err)
+
+	compileAndRun(t, rt, webPlatformTestSuite+"/resources", "testharness.js")
+
+	return rt
+}
+
+// runWebPlatformTest runs a test file of the suite, after the scripts it declares
+// with META comments, and checks that all its tests passed.
+func runWebPlatformTest(t *testing.T, catalog, file string) {
+	t.Helper()
+
+	if _, err := os.Stat(webPlatformTestSuite); err != nil { //nolint:forbidigo
+		t.Skipf("If you want to run the Web Platform Tests, you need to run the 'checkout.sh` script in the directory "+
+			"to get https://github.com/web-platform-tests/wpt at the correct commit (%v)", err)
+	}
+
+	ts := newConfiguredRuntime(t)
+	base := path.Join(webPlatformTestSuite, catalog)
+
+	gotErr := ts.EventLoop.Start(func() error {
+		rt := ts.VU.Runtime()
+		require.NoError(t, rt.Set("fetch", fetchFile(rt, base)))
+
+		// https://web-platform-tests.org/writing-tests/testharness-api.html#callbacks
+		// gets back the Test instance for each test when it finishes
+		cal, ok := sobek.AssertFunction(rt.Get("add_result_callback"))
+		require.True(t, ok)
+		_, err := cal(sobek.Undefined(), rt.ToValue(func(test *sobek.Object) {
+			status := test.Get("status").ToInteger()
+			t.Run(test.Get("name").String(), func(t *testing.T) {
+				assert.Equal(t, "null", test.Get("message").String())
+				require.EqualValues(t, 0, status) // 0 is a PASS, all other values are some kind of failures
+			})
+		}))
+		require.NoError(t, err)
+
+		for _, script := range metaScripts(t, base, file) {
+			compileAndRun(t, ts, path.Dir(script), path.Base(script))
+		}
+		compileAndRun(t, ts, base, file)
+		return nil
+	})
+	require.NoError(t, gotErr)
+}
+
+// metaScripts returns the paths of the scripts a test file declares with
+// "// META: script=" comments, see https://web-platform-tests.org/writing-tests/testharness.html
+func metaScripts(t testing.TB, base, file string) []string {
+	data, err := os.ReadFile(path.Join(base, file)) //nolint:forbidigo
+	require.NoError(t, err)
+
+	var scripts []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "// META:") {
+			break
+		}
+		script, ok := strings.CutPrefix(strings.TrimSpace(strings.TrimPrefix(line, "// META:")), "script=")
+		if !ok || script == "/resources/testharness.js" { // the harness is already loaded
+			continue
+		}
+		if strings.HasPrefix(script, "/") {
+			scripts = append(scripts, path.Join(webPlatformTestSuite, script))
+		} else {
+			scripts = append(scripts, path.Join(base, script))
+		}
+	}
+	return scripts
+}
+
+// fetchFile returns a replacement of fetch for the tests loading their JSON data,
+// which resolves with a response reading the file relative to the test file.
+func fetchFile(rt *sobek.Runtime, base string) func(string) *sobek.Promise {
+	return func(file string) *sobek.Promise {
+		promise, resolve, reject := rt.NewPromise()
+		data, err := os.ReadFile(path.Join(base, file)) //nolint:forbidigo
+		if err != nil {
+			_ = reject(err)
+			return promise
+		}
+
+		response := rt.NewObject()
+		_ = response.Set("json", func() *sobek.Promise {
+			promise, resolve, reject := rt.NewPromise()
+			var v any
+			if err := json.Unmarshal(data, &v); err != nil {
+				_ = reject(err)
+			} else {
+				_ = resolve(v)
+			}
+			return promise
+		})
+		_ = resolve(response)
+		return promise
+	}
+}
+
+func compileAndRun(t testing.TB, runtime *modulestest.Runtime, base, file string) {
+	program, err := modulestest.CompileFile(base, file)
+	require.NoError(t, err)
+
+	_, err = runtime.VU.Runtime().RunProgram(program)
+	require.NoError(t, err)
+}
@@ -0,0 +1,37 @@
+package tests
+
+import (
+	"testing"
+)
+
+func TestURLWebPlatformTestSuite(t *testing.T) {
+	t.Parallel()
+
+	files := []string{
+		"url-constructor.any.js",
+		"url-origin.any.js",
+		"url-setters.any.js",
+		"url-searchparams.any.js",
+		"url-statics-canparse.any.js",
+		"url-tojson.any.js",
+		"urlsearchparams-append.any.js",
+		"urlsearchparams-constructor.any.js",
+		"urlsearchparams-delete.any.js",
+		"urlsearchparams-foreach.any.js",
+		"urlsearchparams-get.any.js",
+		"urlsearchparams-getall.any.js",
+		"urlsearchparams-has.any.js",
+		"urlsearchparams-set.any.js",
+		"urlsearchparams-size.any.js",
+		"urlsearchparams-sort.any.js",
+		"urlsearchparams-stringifier.any.js",
+	}
+
+	for _, file := range files {
+		t.Run(file, func(t *testing.T) {
+			t.Parallel()
+
+			runWebPlatformTest(t, "url", file)
+		})
+	}
+}
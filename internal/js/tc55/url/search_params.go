@@ -0,0 +1,355 @@
+package url
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/internal/js/tc55/encoding"
+	"go.k6.io/k6/internal/js/webidl"
+)
+
+// nameValue is a pair of the list of a URLSearchParams.
+type nameValue struct {
+	name, value string
+}
+
+// searchParams is the Go side of the URLSearchParams objects, see
+// https://url.spec.whatwg.org/#interface-urlsearchparams
+type searchParams struct {
+	webidl.NoOwnProperties
+
+	list []nameValue
+	// url is the URL object the params are the query of, if any
+	url *urlObject
+}
+
+// update sets the serialized params as the query of the URL, see
+// https://url.spec.whatwg.org/#concept-urlsearchparams-update
+func (sp *searchParams) update() {
+	if sp.url == nil {
+		return
+	}
+	// Setting an empty search sets the query to null, as the update steps do for empty params
+	sp.url.url.SetSearch(serializeQuery(sp.list))
+}
+
+// parseQuery parses an application/x-www-form-urlencoded string, see
+// https://url.spec.whatwg.org/#concept-urlencoded-parser
+func parseQuery(input string) []nameValue {
+	var list []nameValue
+	for _, sequence := range strings.Split(input, "&") {
+		if sequence == "" {
+			continue
+		}
+		name, value, _ := strings.Cut(sequence, "=")
+		list = append(list, nameValue{
+			name:  encoding.DecodeUTF8(percentDecode(strings.ReplaceAll(name, "+", " "))),
+			value: encoding.DecodeUTF8(percentDecode(strings.ReplaceAll(value, "+", " "))),
+		})
+	}
+	return list
+}
+
+// percentDecode decodes the percent-encoded bytes of the input, leaving the
+// invalid sequences as they are, see https://url.spec.whatwg.org/#percent-decode
+func percentDecode(input string) []byte {
+	output := make([]byte, 0, len(input))
+	for i := 0; i < len(input); i++ {
+		if input[i] == '%' && i+2 < len(input) && isHex(input[i+1]) && isHex(input[i+2]) {
+			output = append(output, unhex(input[i+1])<<4|unhex(input[i+2]))
+			i += 2
+			continue
+		}
+		output = append(output, input[i])
+	}
+	return output
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+func unhex(c byte) byte {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0'
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10
+	default:
+		return c - 'A' + 10
+	}
+}
+
+// serializeQuery serializes the list as an application/x-www-form-urlencoded string, see
+// https://url.spec.whatwg.org/#concept-urlencoded-serializer
+func serializeQuery(list []nameValue) string {
+	var output strings.Builder
+	for i, pair := range list {
+		if i > 0 {
+			output.WriteByte('&')
+		}
+		writeFormURLEncoded(&output, pair.name)
+		output.WriteByte('=')
+		writeFormURLEncoded(&output, pair.value)
+	}
+	return output.String()
+}
+
+func writeFormURLEncoded(output *strings.Builder, s string) {
+	const upperhex = "0123456789ABCDEF"
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == ' ':
+			output.WriteByte('+')
+		case c == '*' || c == '-' || c == '.' || c == '_' ||
+			'0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z':
+			output.WriteByte(c)
+		default:
+			output.WriteByte('%')
+			output.WriteByte(upperhex[c>>4])
+			output.WriteByte(upperhex[c&0xF])
+		}
+	}
+}
+
+// compareCodeUnits compares the strings by their UTF-16 code units, as the sort of the params does.
+func compareCodeUnits(a, b string) int {
+	return slices.Compare(utf16.Encode([]rune(a)), utf16.Encode([]rune(b)))
+}
+
+// searchParamsConstructor creates a URLSearchParams from a sequence of pairs,
+// a record or a string, see https://url.spec.whatwg.org/#dom-urlsearchparams-urlsearchparams
+func (api *urlAPI) searchParamsConstructor(call sobek.ConstructorCall) *sobek.Object {
+	rt := api.rt
+	sp := &searchParams{}
+
+	init := call.Argument(0)
+	switch obj, isObject := init.(*sobek.Object); {
+	case sobek.IsUndefined(init):
+	case isObject && isIterable(obj):
+		api.forEachIterated(obj, func(pair sobek.Value) {
+			pairObj, ok := pair.(*sobek.Object)
+			if !ok {
+				webidl.ThrowTypeError(rt, "the pairs to initialize URLSearchParams must be sequences")
+			}
+			var values []string
+			api.forEachIterated(pairObj, func(v sobek.Value) { values = append(values, v.String()) })
+			if len(values) != 2 {
+				webidl.ThrowTypeError(rt, "the pairs to initialize URLSearchParams must have exactly two values")
+			}
+			sp.list = append(sp.list, nameValue{name: values[0], value: values[1]})
+		})
+	case isObject:
+		for _, key := range obj.Keys() {
+			sp.list = append(sp.list, nameValue{name: rt.ToValue(key).String(), value: obj.Get(key).String()})
+		}
+	default:
+		sp.list = parseQuery(strings.TrimPrefix(init.String(), "?"))
+	}
+
+	return webidl.NewInstance(rt, sp, call.This.Prototype())
+}
+
+func isIterable(obj *sobek.Object) bool {
+	_, ok := sobek.AssertFunction(obj.GetSymbol(sobek.SymIterator))
+	return ok
+}
+
+// forEachIterated calls the callback with each value of the iterable object.
+func (api *urlAPI) forEachIterated(iterable *sobek.Object, callback func(sobek.Value)) {
+	rt := api.rt
+	iteratorMethod, ok := sobek.AssertFunction(iterable.GetSymbol(sobek.SymIterator))
+	if !ok {
+		webidl.ThrowTypeError(rt, "the value isn't iterable")
+	}
+	iteratorValue, err := iteratorMethod(iterable)
+	webidl.Must(rt, err)
+	iterator, ok := iteratorValue.(*sobek.Object)
+	if !ok {
+		webidl.ThrowTypeError(rt, "the iterator isn't an object")
+	}
+	next, ok := sobek.AssertFunction(iterator.Get("next"))
+	if !ok {
+		webidl.ThrowTypeError(rt, "the iterator doesn't have a next method")
+	}
+	for {
+		result, err := next(iterator)
+		webidl.Must(rt, err)
+		resultObj := result.ToObject(rt)
+		if resultObj.Get("done").ToBoolean() {
+			return
+		}
+		callback(resultObj.Get("value"))
+	}
+}
+
+func (api *urlAPI) thisSearchParams(this sobek.Value) *searchParams {
+	sp, ok := this.Export().(*searchParams)
+	if !ok {
+		webidl.ThrowTypeError(api.rt, "Illegal invocation")
+	}
+	return sp
+}
+
+// searchParamsIterator is the Go side of the iterators of the URLSearchParams objects, which iterate
+// over the current list of the params, so they reflect the changes made while iterating.
+type searchParamsIterator struct {
+	webidl.NoOwnProperties
+
+	params *searchParams
+	kind   string
+	index  int
+}
+
+func (api *urlAPI) newIterator(sp *searchParams, kind string) *sobek.Object {
+	return webidl.NewInstance(api.rt, &searchParamsIterator{params: sp, kind: kind}, api.iteratorProto)
+}
+
+func (api *urlAPI) defineIteratorPrototype() {
+	rt := api.rt
+
+	// The prototype of the iterators inherits from %IteratorPrototype%, see
+	// https://webidl.spec.whatwg.org/#es-iterator-prototype-object
+	iteratorPrototype, err := rt.RunString("Object.getPrototypeOf(Object.getPrototypeOf([][Symbol.iterator]()))")
+	webidl.Must(rt, err)
+	api.iteratorProto = rt.CreateObject(iteratorPrototype.ToObject(rt))
+
+	webidl.DefineMethod(rt, api.iteratorProto, "next", func(call sobek.FunctionCall) sobek.Value {
+		it, ok := call.This.Export().(*searchParamsIterator)
+		if !ok {
+			webidl.ThrowTypeError(rt, "Illegal invocation")
+		}
+		result := rt.NewObject()
+		if it.index >= len(it.params.list) {
+			webidl.Must(rt, result.Set("value", sobek.Undefined()))
+			webidl.Must(rt, result.Set("done", true))
+			return result
+		}
+		pair := it.params.list[it.index]
+		it.index++
+
+		var value any
+		switch it.kind {
+		case "keys":
+			value = pair.name
+		case "values":
+			value = pair.value
+		default:
+			value = rt.NewArray(pair.name, pair.value)
+		}
+		webidl.Must(rt, result.Set("value", value))
+		webidl.Must(rt, result.Set("done", false))
+		return result
+	})
+	webidl.DefineToStringTag(rt, api.iteratorProto, "URLSearchParams Iterator")
+}
+
+//nolint:funlen
+func (api *urlAPI) defineSearchParamsPrototype() {
+	rt := api.rt
+	proto := api.searchParamsProto
+	api.defineIteratorPrototype()
+
+	webidl.DefineGetter(rt, proto, "size", func(call sobek.FunctionCall) sobek.Value {
+		return rt.ToValue(len(api.thisSearchParams(call.This).list))
+	})
+	webidl.DefineMethod(rt, proto, "append", func(call sobek.FunctionCall) sobek.Value {
+		sp := api.thisSearchParams(call.This)
+		webidl.RequireArguments(rt, call, "append", 2)
+		sp.list = append(sp.list, nameValue{name: call.Argument(0).String(), value: call.Argument(1).String()})
+		sp.update()
+		return sobek.Undefined()
+	})
+	webidl.DefineMethod(rt, proto, "delete", func(call sobek.FunctionCall) sobek.Value {
+		sp := api.thisSearchParams(call.This)
+		webidl.RequireArguments(rt, call, "delete", 1)
+		name, value := call.Argument(0).String(), call.Argument(1)
+		sp.list = slices.DeleteFunc(sp.list, func(pair nameValue) bool {
+			return pair.name == name && (sobek.IsUndefined(value) || pair.value == value.String())
+		})
+		sp.update()
+		return sobek.Undefined()
+	})
+	webidl.DefineMethod(rt, proto, "get", func(call sobek.FunctionCall) sobek.Value {
+		sp := api.thisSearchParams(call.This)
+		webidl.RequireArguments(rt, call, "get", 1)
+		name := call.Argument(0).String()
+		for _, pair := range sp.list {
+			if pair.name == name {
+				return rt.ToValue(pair.value)
+			}
+		}
+		return sobek.Null()
+	})
+	webidl.DefineMethod(rt, proto, "getAll", func(call sobek.FunctionCall) sobek.Value {
+		sp := api.thisSearchParams(call.This)
+		webidl.RequireArguments(rt, call, "getAll", 1)
+		name := call.Argument(0).String()
+		values := make([]any, 0)
+		for _, pair := range sp.list {
+			if pair.name == name {
+				values = append(values, pair.value)
+			}
+		}
+		return rt.NewArray(values...)
+	})
+	webidl.DefineMethod(rt, proto, "has", func(call sobek.FunctionCall) sobek.Value {
+		sp := api.thisSearchParams(call.This)
+		webidl.RequireArguments(rt, call, "has", 1)
+		name, value := call.Argument(0).String(), call.Argument(1)
+		return rt.ToValue(slices.ContainsFunc(sp.list, func(pair nameValue) bool {
+			return pair.name == name && (sobek.IsUndefined(value) || pair.value == value.String())
+		}))
+	})
+	webidl.DefineMethod(rt, proto, "set", func(call sobek.FunctionCall) sobek.Value {
+		sp := api.thisSearchParams(call.This)
+		webidl.RequireArguments(rt, call, "set", 2)
+		name, value := call.Argument(0).String(), call.Argument(1).String()
+		// The value of the first pair with the name is replaced, and the other pairs with it are removed
+		index := slices.IndexFunc(sp.list, func(pair nameValue) bool { return pair.name == name })
+		if index == -1 {
+			sp.list = append(sp.list, nameValue{name: name, value: value})
+		} else {
+			sp.list[index].value = value
+			sp.list = append(sp.list[:index+1], slices.DeleteFunc(sp.list[index+1:], func(pair nameValue) bool {
+				return pair.name == name
+			})...)
+		}
+		sp.update()
+		return sobek.Undefined()
+	})
+	webidl.DefineMethod(rt, proto, "sort", func(call sobek.FunctionCall) sobek.Value {
+		sp := api.thisSearchParams(call.This)
+		slices.SortStableFunc(sp.list, func(a, b nameValue) int { return compareCodeUnits(a.name, b.name) })
+		sp.update()
+		return sobek.Undefined()
+	})
+	webidl.DefineMethod(rt, proto, "toString", func(call sobek.FunctionCall) sobek.Value {
+		return rt.ToValue(serializeQuery(api.thisSearchParams(call.This).list))
+	})
+	webidl.DefineMethod(rt, proto, "forEach", func(call sobek.FunctionCall) sobek.Value {
+		sp := api.thisSearchParams(call.This)
+		callback, ok := sobek.AssertFunction(call.Argument(0))
+		if !ok {
+			webidl.ThrowTypeError(rt, "the callback of forEach must be a function")
+		}
+		for i := 0; i < len(sp.list); i++ {
+			pair := sp.list[i]
+			_, err := callback(call.Argument(1), rt.ToValue(pair.value), rt.ToValue(pair.name), call.This)
+			webidl.Must(rt, err)
+		}
+		return sobek.Undefined()
+	})
+	for _, kind := range []string{"entries", "keys", "values"} {
+		webidl.DefineMethod(rt, proto, kind, func(call sobek.FunctionCall) sobek.Value {
+			return api.newIterator(api.thisSearchParams(call.This), kind)
+		})
+	}
+	webidl.Must(rt, proto.DefineDataPropertySymbol(
+		sobek.SymIterator, proto.Get("entries"), sobek.FLAG_TRUE, sobek.FLAG_FALSE, sobek.FLAG_TRUE))
+	webidl.DefineToStringTag(rt, proto, "URLSearchParams")
+}
@@ -0,0 +1,180 @@
+// Package url implements the URL and URLSearchParams classes of the
+// URL Standard (https://url.spec.whatwg.org) as globals.
+package url
+
+import (
+	"fmt"
+
+	"github.com/grafana/sobek"
+	whatwgurl "github.com/nlnwa/whatwg-url/url"
+
+	"go.k6.io/k6/internal/js/webidl"
+	"go.k6.io/k6/js/modules"
+)
+
+// urlAPI holds the prototypes of the classes, which are needed to
+// create the objects returned by the methods of other classes.
+type urlAPI struct {
+	rt *sobek.Runtime
+
+	urlProto          *sobek.Object
+	searchParamsProto *sobek.Object
+	iteratorProto     *sobek.Object
+}
+
+// SetupGlobally sets the URL and URLSearchParams classes to be accessible globally.
+func SetupGlobally(vu modules.VU) error {
+	rt := vu.Runtime()
+	api := &urlAPI{rt: rt}
+
+	urlClass := rt.ToValue(api.urlConstructor).ToObject(rt)
+	api.urlProto = urlClass.Get("prototype").ToObject(rt)
+	api.defineURLClass(urlClass)
+
+	searchParamsClass := rt.ToValue(api.searchParamsConstructor).ToObject(rt)
+	api.searchParamsProto = searchParamsClass.Get("prototype").ToObject(rt)
+	api.defineSearchParamsPrototype()
+
+	mapping := map[string]any{
+		"URL":             urlClass,
+		"URLSearchParams": searchParamsClass,
+	}
+	for k, v := range mapping {
+		if err := rt.Set(k, v); err != nil {
+			return fmt.Errorf("error setting up %q globally: %w", k, err)
+		}
+	}
+	return nil
+}
+
+// urlObject is the Go side of the URL objects, see https://url.spec.whatwg.org/#url-class
+type urlObject struct {
+	webidl.NoOwnProperties
+
+	url             *whatwgurl.Url
+	searchParams    *searchParams
+	searchParamsObj *sobek.Object
+}
+
+// parseURL parses the URL with an optional base, see https://url.spec.whatwg.org/#api-url-parser
+func parseURL(input string, base sobek.Value) (*whatwgurl.Url, error) {
+	if base == nil || sobek.IsUndefined(base) {
+		return whatwgurl.Parse(input)
+	}
+	baseURL, err := whatwgurl.Parse(base.String())
+	if err != nil {
+		return nil, err
+	}
+	return baseURL.Parse(input)
+}
+
+func (api *urlAPI) newURLObject(u *whatwgurl.Url, proto *sobek.Object) *sobek.Object {
+	obj := &urlObject{url: u}
+	obj.searchParams = &searchParams{list: parseQuery(u.Query()), url: obj}
+	obj.searchParamsObj = webidl.NewInstance(api.rt, obj.searchParams, api.searchParamsProto)
+	return webidl.NewInstance(api.rt, obj, proto)
+}
+
+func (api *urlAPI) urlConstructor(call sobek.ConstructorCall) *sobek.Object {
+	input := call.Argument(0).String()
+	u, err := parseURL(input, call.Argument(1))
+	if err != nil {
+		webidl.ThrowTypeError(api.rt, "invalid URL %q", input)
+	}
+	return api.newURLObject(u, call.This.Prototype())
+}
+
+func (api *urlAPI) thisURL(this sobek.Value) *urlObject {
+	u, ok := this.Export().(*urlObject)
+	if !ok {
+		webidl.ThrowTypeError(api.rt, "Illegal invocation")
+	}
+	return u
+}
+
+// origin returns the serialization of the origin of the URL, see https://url.spec.whatwg.org/#concept-url-origin
+func origin(u *whatwgurl.Url) string {
+	switch u.Scheme() {
+	case "blob":
+		if pathURL, err := whatwgurl.Parse(u.Pathname()); err == nil &&
+			(pathURL.Scheme() == "http" || pathURL.Scheme() == "https") {
+			return origin(pathURL)
+		}
+		return "null"
+	case "ftp", "http", "https", "ws", "wss":
+		return u.Scheme() + "://" + u.Host()
+	default:
+		return "null"
+	}
+}
+
+//nolint:funlen
+func (api *urlAPI) defineURLClass(ctor *sobek.Object) {
+	rt := api.rt
+	proto := api.urlProto
+
+	webidl.DefineMethod(rt, ctor, "parse", func(call sobek.FunctionCall) sobek.Value {
+		u, err := parseURL(call.Argument(0).String(), call.Argument(1))
+		if err != nil {
+			return sobek.Null()
+		}
+		return api.newURLObject(u, api.urlProto)
+	})
+	webidl.DefineMethod(rt, ctor, "canParse", func(call sobek.FunctionCall) sobek.Value {
+		_, err := parseURL(call.Argument(0).String(), call.Argument(1))
+		return rt.ToValue(err == nil)
+	})
+
+	// The attributes with a setter, which gets the setter argument converted to an USVString
+	attributes := []struct {
+		name   string
+		getter func(*whatwgurl.Url) string
+		setter func(*urlObject, string)
+	}{
+		{"href", func(u *whatwgurl.Url) string { return u.Href(false) }, func(obj *urlObject, v string) {
+			u, err := whatwgurl.Parse(v)
+			if err != nil {
+				webidl.ThrowTypeError(rt, "invalid URL %q", v)
+			}
+			obj.url = u
+			obj.searchParams.list = parseQuery(u.Query())
+		}},
+		{"protocol", (*whatwgurl.Url).Protocol, func(obj *urlObject, v string) { obj.url.SetProtocol(v) }},
+		{"username", (*whatwgurl.Url).Username, func(obj *urlObject, v string) { obj.url.SetUsername(v) }},
+		{"password", (*whatwgurl.Url).Password, func(obj *urlObject, v string) { obj.url.SetPassword(v) }},
+		{"host", (*whatwgurl.Url).Host, func(obj *urlObject, v string) { obj.url.SetHost(v) }},
+		{"hostname", (*whatwgurl.Url).Hostname, func(obj *urlObject, v string) { obj.url.SetHostname(v) }},
+		{"port", (*whatwgurl.Url).Port, func(obj *urlObject, v string) { obj.url.SetPort(v) }},
+		{"pathname", (*whatwgurl.Url).Pathname, func(obj *urlObject, v string) { obj.url.SetPathname(v) }},
+		{"search", (*whatwgurl.Url).Search, func(obj *urlObject, v string) {
+			obj.url.SetSearch(v)
+			obj.searchParams.list = parseQuery(obj.url.Query())
+		}},
+		{"hash", (*whatwgurl.Url).Hash, func(obj *urlObject, v string) { obj.url.SetHash(v) }},
+	}
+	for _, attr := range attributes {
+		getter := func(call sobek.FunctionCall) sobek.Value {
+			return rt.ToValue(attr.getter(api.thisURL(call.This).url))
+		}
+		setter := func(call sobek.FunctionCall) sobek.Value {
+			attr.setter(api.thisURL(call.This), call.Argument(0).String())
+			return sobek.Undefined()
+		}
+		webidl.Must(rt, proto.DefineAccessorProperty(attr.name, rt.ToValue(getter), rt.ToValue(setter),
+			sobek.FLAG_TRUE, sobek.FLAG_TRUE))
+	}
+
+	webidl.DefineGetter(rt, proto, "origin", func(call sobek.FunctionCall) sobek.Value {
+		return rt.ToValue(origin(api.thisURL(call.This).url))
+	})
+	webidl.DefineGetter(rt, proto, "searchParams", func(call sobek.FunctionCall) sobek.Value {
+		return api.thisURL(call.This).searchParamsObj
+	})
+	webidl.DefineMethod(rt, proto, "toString", func(call sobek.FunctionCall) sobek.Value {
+		return rt.ToValue(api.thisURL(call.This).url.Href(false))
+	})
+	webidl.DefineMethod(rt, proto, "toJSON", func(call sobek.FunctionCall) sobek.Value {
+		return rt.ToValue(api.thisURL(call.This).url.Href(false))
+	})
+	webidl.DefineToStringTag(rt, proto, "URL")
+}
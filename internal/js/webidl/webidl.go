@@ -0,0 +1,84 @@
+// Package webidl contains the helpers shared by the Web APIs implemented in Go,
+// like fetch, TextEncoder or URL, to define their classes the way the WebIDL
+// standard (https://webidl.spec.whatwg.org) maps the interfaces to JavaScript,
+// with the attributes and methods on the prototypes.
+package webidl
+
+import (
+	"fmt"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/js/common"
+)
+
+// NoOwnProperties implements sobek.DynamicObject for the Go values of the
+// instances of the classes. They don't have own properties, as all their attributes
+// and methods are defined on the prototypes, and the values can be retrieved
+// from the objects with Export.
+type NoOwnProperties struct{}
+
+// Get implements sobek.DynamicObject.
+func (NoOwnProperties) Get(string) sobek.Value { return nil }
+
+// Set implements sobek.DynamicObject.
+func (NoOwnProperties) Set(string, sobek.Value) bool { return false }
+
+// Has implements sobek.DynamicObject.
+func (NoOwnProperties) Has(string) bool { return false }
+
+// Delete implements sobek.DynamicObject.
+func (NoOwnProperties) Delete(string) bool { return true }
+
+// Keys implements sobek.DynamicObject.
+func (NoOwnProperties) Keys() []string { return nil }
+
+// NewInstance returns a new object with the given Go value and prototype.
+func NewInstance(rt *sobek.Runtime, d sobek.DynamicObject, proto *sobek.Object) *sobek.Object {
+	obj := rt.NewDynamicObject(d)
+	Must(rt, obj.SetPrototype(proto))
+	return obj
+}
+
+// DefineMethod defines a writable, enumerable and configurable method on the object.
+func DefineMethod(rt *sobek.Runtime, obj *sobek.Object, name string, method any) {
+	Must(rt, obj.DefineDataProperty(name, rt.ToValue(method), sobek.FLAG_TRUE, sobek.FLAG_TRUE, sobek.FLAG_TRUE))
+}
+
+// DefineGetter defines an enumerable and configurable read-only attribute on the object.
+func DefineGetter(rt *sobek.Runtime, obj *sobek.Object, name string, getter func(sobek.FunctionCall) sobek.Value) {
+	Must(rt, obj.DefineAccessorProperty(name, rt.ToValue(getter), nil, sobek.FLAG_TRUE, sobek.FLAG_TRUE))
+}
+
+// DefineToStringTag sets the name of the class that Object.prototype.toString
+// returns for its instances.
+func DefineToStringTag(rt *sobek.Runtime, proto *sobek.Object, name string) {
+	Must(rt, proto.DefineDataPropertySymbol(
+		sobek.SymToStringTag, rt.ToValue(name), sobek.FLAG_FALSE, sobek.FLAG_FALSE, sobek.FLAG_TRUE))
+}
+
+// RequireArguments throws a TypeError if the method got fewer arguments than the required ones.
+func RequireArguments(rt *sobek.Runtime, call sobek.FunctionCall, method string, required int) {
+	if len(call.Arguments) < required {
+		ThrowTypeError(rt, "%s requires %d arguments, but only %d were given", method, required, len(call.Arguments))
+	}
+}
+
+// Must throws the error as a JavaScript exception, if it isn't nil.
+func Must(rt *sobek.Runtime, err error) {
+	if err != nil {
+		common.Throw(rt, err)
+	}
+}
+
+// ThrowTypeError throws a TypeError with the formatted message.
+func ThrowTypeError(rt *sobek.Runtime, format string, args ...any) {
+	panic(rt.NewTypeError("%s", fmt.Sprintf(format, args...)))
+}
+
+// ThrowRangeError throws a RangeError with the formatted message.
+func ThrowRangeError(rt *sobek.Runtime, format string, args ...any) {
+	rangeError, err := rt.New(rt.Get("RangeError"), rt.ToValue(fmt.Sprintf(format, args...)))
+	Must(rt, err)
+	panic(rangeError)
+}
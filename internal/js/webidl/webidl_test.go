@@ -0,0 +1,62 @@
+package webidl
+
+import (
+	"testing"
+
+	"github.com/grafana/sobek"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type counter struct {
+	NoOwnProperties
+	value int64
+}
+
+func TestDefineClass(t *testing.T) {
+	t.Parallel()
+
+	rt := sobek.New()
+	proto := rt.NewObject()
+	thisCounter := func(this sobek.Value) *counter {
+		c, ok := this.Export().(*counter)
+		if !ok {
+			ThrowTypeError(rt, "the object isn't a Counter")
+		}
+		return c
+	}
+	DefineGetter(rt, proto, "value", func(call sobek.FunctionCall) sobek.Value {
+		return rt.ToValue(thisCounter(call.This).value)
+	})
+	DefineMethod(rt, proto, "add", func(call sobek.FunctionCall) sobek.Value {
+		RequireArguments(rt, call, "Counter.add", 1)
+		thisCounter(call.This).value += call.Argument(0).ToInteger()
+		return sobek.Undefined()
+	})
+	DefineToStringTag(rt, proto, "Counter")
+	require.NoError(t, rt.Set("counter", NewInstance(rt, &counter{value: 1}, proto)))
+
+	v, err := rt.RunString(`
+		counter.add(2);
+		[counter.value, Object.keys(counter).length, Object.prototype.toString.call(counter)].join()
+	`)
+	require.NoError(t, err)
+	assert.Equal(t, "3,0,[object Counter]", v.String())
+
+	_, err = rt.RunString(`counter.add()`)
+	require.ErrorContains(t, err, "TypeError: Counter.add requires 1 arguments, but only 0 were given")
+
+	_, err = rt.RunString(`Object.getOwnPropertyDescriptor(Object.getPrototypeOf(counter), "value").get.call({})`)
+	require.ErrorContains(t, err, "TypeError: the object isn't a Counter")
+}
+
+func TestThrowRangeError(t *testing.T) {
+	t.Parallel()
+
+	rt := sobek.New()
+	require.NoError(t, rt.Set("fail", func() { ThrowRangeError(rt, "%d is out of range", 42) }))
+
+	v, err := rt.RunString(`try { fail() } catch (e) { e instanceof RangeError && e.message }`)
+	require.NoError(t, err)
+	assert.Equal(t, "42 is out of range", v.String())
+}
@@ -0,0 +1,282 @@
+package summary
+
+import (
+	_ "embed" // this is used to embed the template of the HTML report
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+//go:embed report.html
+var reportTemplate string
+
+// The size of the charts of the HTML report, in SVG units.
+const (
+	chartWidth   = 600
+	chartHeight  = 200
+	chartPadding = 30
+)
+
+type htmlReport struct {
+	TestRunDuration  string
+	Thresholds       []htmlThreshold
+	FailedThresholds int
+	Root             htmlGroup
+	Scenarios        []htmlGroup
+	Charts           []htmlChart
+}
+
+type htmlThreshold struct {
+	Metric string
+	Source string
+	Values string
+	Ok     bool
+}
+
+type htmlGroup struct {
+	Name    string
+	Checks  []htmlCheck
+	Metrics []htmlMetric
+	Groups  []htmlGroup
+}
+
+type htmlCheck struct {
+	Name   string
+	Passes int64
+	Fails  int64
+	Rate   string
+}
+
+type htmlMetric struct {
+	Section string
+	Name    string
+	Values  string
+}
+
+type htmlChart struct {
+	Title  string
+	Series []htmlSeries
+	MaxY   string
+	EndX   string
+}
+
+type htmlSeries struct {
+	Name   string
+	Color  string
+	Points string
+}
+
+// RenderHTML writes the summary as a self-contained HTML report, with the thresholds,
+// the checks and the metrics of the test run, its groups and its scenarios. If the
+// summary has a timeline, the report also has charts of how the main metrics changed.
+func RenderHTML(w io.Writer, s *Summary) error {
+	tmpl, err := template.New("report").Parse(reportTemplate)
+	if err != nil {
+		return err
+	}
+
+	report := htmlReport{
+		TestRunDuration: time.Duration(s.TestRunDuration).Round(time.Millisecond).String(),
+		Root:            newHTMLGroup("", s.Group),
+		Charts:          newHTMLCharts(s.Timeline),
+	}
+
+	metricNames := make([]string, 0, len(s.Thresholds))
+	for name := range s.Thresholds {
+		metricNames = append(metricNames, name)
+	}
+	sort.Strings(metricNames)
+	for _, name := range metricNames {
+		mt := s.Thresholds[name]
+		for _, threshold := range mt.Thresholds {
+			report.Thresholds = append(report.Thresholds, htmlThreshold{
+				Metric: name,
+				Source: threshold.Source,
+				Values: formatMetricValues(mt.Metric),
+				Ok:     threshold.Ok,
+			})
+			if !threshold.Ok {
+				report.FailedThresholds++
+			}
+		}
+	}
+
+	scenarioNames := make([]string, 0, len(s.Scenarios))
+	for name := range s.Scenarios {
+		scenarioNames = append(scenarioNames, name)
+	}
+	sort.Strings(scenarioNames)
+	for _, name := range scenarioNames {
+		report.Scenarios = append(report.Scenarios, newHTMLGroup(name, s.Scenarios[name]))
+	}
+
+	return tmpl.Execute(w, report)
+}
+
+func newHTMLGroup(name string, g Group) htmlGroup {
+	group := htmlGroup{Name: name}
+
+	if g.Checks != nil {
+		for _, check := range g.Checks.OrderedChecks {
+			rate := "-"
+			if total := check.Passes + check.Fails; total > 0 {
+				rate = fmt.Sprintf("%.2f%%", float64(check.Passes)/float64(total)*100)
+			}
+			group.Checks = append(group.Checks, htmlCheck{
+				Name:   check.Name,
+				Passes: check.Passes,
+				Fails:  check.Fails,
+				Rate:   rate,
+			})
+		}
+	}
+
+	sections := []struct {
+		name    string
+		metrics map[string]Metric
+	}{
+		{"HTTP", g.Metrics.HTTP},
+		{"Execution", g.Metrics.Execution},
+		{"Network", g.Metrics.Network},
+		{"Browser", g.Metrics.Browser},
+		{"Web Vitals", g.Metrics.WebVitals},
+		{"gRPC", g.Metrics.Grpc},
+		{"WebSocket", g.Metrics.WebSocket},
+		{"Custom", g.Metrics.Custom},
+	}
+	for _, section := range sections {
+		names := make([]string, 0, len(section.metrics))
+		for metricName := range section.metrics {
+			names = append(names, metricName)
+		}
+		sort.Strings(names)
+		for _, metricName := range names {
+			group.Metrics = append(group.Metrics, htmlMetric{
+				Section: section.name,
+				Name:    metricName,
+				Values:  formatMetricValues(section.metrics[metricName]),
+			})
+		}
+	}
+
+	for _, groupName := range g.GroupsOrder {
+		group.Groups = append(group.Groups, newHTMLGroup(groupName, g.Groups[groupName]))
+	}
+
+	return group
+}
+
+func formatMetricValues(m Metric) string {
+	keys := make([]string, 0, len(m.Values))
+	for key := range m.Values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	values := make([]string, 0, len(keys))
+	for _, key := range keys {
+		values = append(values, key+"="+formatMetricValue(m.MetricInfo, key, m.Values[key]))
+	}
+	return strings.Join(values, " ")
+}
+
+func formatMetricValue(info MetricInfo, key string, value float64) string {
+	switch {
+	case key == "count" || key == "passes" || key == "fails":
+		return fmt.Sprintf("%.0f", value)
+	case key == "rate" && info.Type == "rate":
+		return fmt.Sprintf("%.2f%%", value*100)
+	case key == "rate":
+		return fmt.Sprintf("%.2f/s", value)
+	case info.Contains == "time":
+		return (time.Duration(value * float64(time.Millisecond))).Round(time.Microsecond).String()
+	case info.Contains == "data":
+		return fmt.Sprintf("%.1f kB", value/1000)
+	case value == math.Trunc(value):
+		return fmt.Sprintf("%.0f", value)
+	default:
+		return fmt.Sprintf("%.2f", value)
+	}
+}
+
+func newHTMLCharts(timeline *Timeline) []htmlChart {
+	if timeline == nil || len(timeline.Buckets) == 0 {
+		return nil
+	}
+
+	chartDefs := []struct {
+		title  string
+		metric string
+		keys   []string
+		scale  float64
+	}{
+		{"HTTP request duration (ms)", "http_req_duration", []string{"p(95)", "p(90)", "med", "avg"}, 1},
+		{"VUs", "vus", []string{"max", "min"}, 1},
+		{"Iterations per second", "iterations", []string{"rate"}, 1},
+		{"HTTP requests per second", "http_reqs", []string{"rate"}, 1},
+		{"Failed HTTP requests (%)", "http_req_failed", []string{"rate"}, 100},
+		{"Checks succeeded (%)", "checks", []string{"rate"}, 100},
+	}
+	colors := []string{"#cf222e", "#e58f00", "#0969da", "#1a7f37"}
+
+	start := timeline.Buckets[0].Time
+	endX := timeline.Buckets[len(timeline.Buckets)-1].Time.Add(time.Duration(timeline.BucketSize)).Sub(start).Seconds()
+
+	var charts []htmlChart
+	for _, def := range chartDefs {
+		maxY := 0.0
+		found := false
+		for _, bucket := range timeline.Buckets {
+			for _, key := range def.keys {
+				if value, ok := bucket.Metrics[def.metric][key]; ok {
+					found = true
+					maxY = math.Max(maxY, value*def.scale)
+				}
+			}
+		}
+		if !found {
+			continue
+		}
+		if maxY == 0 {
+			maxY = 1
+		}
+
+		chart := htmlChart{Title: def.title, MaxY: formatChartValue(maxY), EndX: fmt.Sprintf("%.0fs", endX)}
+		for i, key := range def.keys {
+			points := make([]string, 0, len(timeline.Buckets))
+			for _, bucket := range timeline.Buckets {
+				value, ok := bucket.Metrics[def.metric][key]
+				if !ok {
+					continue
+				}
+				// The values are placed in the middle of their buckets
+				offset := bucket.Time.Sub(start).Seconds() + time.Duration(timeline.BucketSize).Seconds()/2
+				x := chartPadding + offset/endX*(chartWidth-chartPadding)
+				y := chartHeight - chartPadding - value*def.scale/maxY*(chartHeight-2*chartPadding)
+				points = append(points, fmt.Sprintf("%.1f,%.1f", x, y))
+			}
+			if len(points) == 0 {
+				continue
+			}
+			chart.Series = append(chart.Series, htmlSeries{
+				Name:   key,
+				Color:  colors[i%len(colors)],
+				Points: strings.Join(points, " "),
+			})
+		}
+		charts = append(charts, chart)
+	}
+
+	return charts
+}
+
+func formatChartValue(value float64) string {
+	if value < 10 {
+		return fmt.Sprintf("%.2f", value)
+	}
+	return fmt.Sprintf("%.0f", value)
+}
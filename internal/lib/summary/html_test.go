@@ -0,0 +1,67 @@
+package summary
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/lib/types"
+)
+
+func TestRenderHTML(t *testing.T) {
+	t.Parallel()
+
+	s := New()
+	s.TestRunDuration = types.Duration(90 * time.Second)
+	s.Thresholds["http_req_duration"] = MetricThresholds{
+		Metric: NewMetricFrom(
+			MetricInfo{Name: "http_req_duration", Type: "trend", Contains: "time"},
+			map[string]float64{"p(95)": 123.5},
+		),
+		Thresholds: []Threshold{{Source: "p(95)<100", Ok: false}, {Source: "avg<500", Ok: true}},
+	}
+	s.Metrics.Execution["iterations"] = NewMetricFrom(
+		MetricInfo{Name: "iterations", Type: "counter", Contains: "default"},
+		map[string]float64{"count": 42, "rate": 0.4667},
+	)
+	s.Checks = NewChecks()
+	s.Checks.OrderedChecks = []*Check{{Name: "status is 200", Passes: 3, Fails: 1}}
+
+	group := NewGroup()
+	group.Checks = NewChecks()
+	group.Checks.OrderedChecks = []*Check{{Name: "<b>logged in</b>", Passes: 1}}
+	scenario := NewGroup()
+	scenario.Groups["login"] = group
+	scenario.GroupsOrder = []string{"login"}
+	s.Scenarios["my_scenario"] = scenario
+
+	start := time.Unix(100, 0)
+	s.Timeline = &Timeline{
+		BucketSize: types.Duration(time.Second),
+		Buckets: []TimelineBucket{
+			{Time: start, Metrics: map[string]map[string]float64{"vus": {"min": 1, "max": 5}}},
+			{Time: start.Add(time.Second), Metrics: map[string]map[string]float64{"vus": {"min": 5, "max": 10}}},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, RenderHTML(buf, s))
+	report := buf.String()
+
+	assert.Contains(t, report, "Test run duration: 1m30s")
+	assert.Contains(t, report, "1 of 2 thresholds failed")
+	assert.Contains(t, report, "<td>p(95)&lt;100</td>")
+	assert.Contains(t, report, "p(95)=123.5ms")
+	assert.Contains(t, report, "count=42 rate=0.47/s")
+	assert.Contains(t, report, "<td>status is 200</td><td>3</td><td class=\"failed\">1</td><td>75.00%</td>")
+	assert.Contains(t, report, "<h2>Scenario my_scenario</h2>")
+	assert.Contains(t, report, "<h3>Group login</h3>")
+	assert.Contains(t, report, "&lt;b&gt;logged in&lt;/b&gt;")
+	assert.Contains(t, report, "<figcaption>VUs</figcaption>")
+	assert.Contains(t, report, `<polyline fill="none" stroke="#cf222e" stroke-width="1.5" points="172.5,100.0 457.5,30.0">`)
+	assert.NotContains(t, report, "ZgotmplZ")
+	assert.NotContains(t, report, "HTTP request duration")
+}
@@ -0,0 +1,196 @@
+package summary
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// groupSeparator is the separator of the group names in the group paths,
+// the same as lib.GroupSeparator, which can't be imported here.
+const groupSeparator = "::"
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func (ts *junitTestSuite) addCase(tc junitTestCase) {
+	ts.Tests++
+	if tc.Failure != nil {
+		ts.Failures++
+	}
+	ts.Cases = append(ts.Cases, tc)
+}
+
+// RenderJUnit writes the thresholds and the checks of the summary as a JUnit XML report.
+//
+// The thresholds are in the "thresholds" test suite, with a test case for each of them.
+// The checks are in a test suite for each scenario and group path, with a test case for
+// each check, which fails if any of its runs failed. The checks of the scenarios and the
+// groups are only known with the full summary mode, otherwise all of them are in the
+// "checks" test suite.
+func RenderJUnit(w io.Writer, s *Summary) error {
+	report := junitTestSuites{
+		Name: "k6",
+		Time: fmt.Sprintf("%.3f", time.Duration(s.TestRunDuration).Seconds()),
+	}
+
+	thresholdsSuite := junitTestSuite{Name: "thresholds"}
+	metricNames := make([]string, 0, len(s.Thresholds))
+	for name := range s.Thresholds {
+		metricNames = append(metricNames, name)
+	}
+	sort.Strings(metricNames)
+	for _, name := range metricNames {
+		mt := s.Thresholds[name]
+		for _, threshold := range mt.Thresholds {
+			tc := junitTestCase{Name: threshold.Source, Classname: "thresholds." + name}
+			if !threshold.Ok {
+				observed := formatMetricValues(mt.Metric)
+				tc.Failure = &junitFailure{
+					Message: fmt.Sprintf("%s: %s failed, observed %s", name, threshold.Source, observed),
+					Type:    "threshold",
+					Text:    observed,
+				}
+			}
+			thresholdsSuite.addCase(tc)
+		}
+	}
+	if thresholdsSuite.Tests > 0 {
+		report.Suites = append(report.Suites, thresholdsSuite)
+	}
+
+	// The checks of the root group and of the scenarios also include the checks
+	// of their nested groups and, for the root group, the checks of all scenarios,
+	// so these are subtracted to get the checks that are only their own.
+	scenarioNames := make([]string, 0, len(s.Scenarios))
+	scenarios := make([]Group, 0, len(s.Scenarios))
+	for name, scenario := range s.Scenarios {
+		scenarioNames = append(scenarioNames, name)
+		scenarios = append(scenarios, scenario)
+	}
+	sort.Strings(scenarioNames)
+
+	report.Suites = appendJUnitCheckSuites(report.Suites, "checks", s.Group, scenarios)
+	for _, name := range scenarioNames {
+		report.Suites = appendJUnitCheckSuites(report.Suites, "scenario "+name, s.Scenarios[name], nil)
+	}
+
+	for _, suite := range report.Suites {
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// appendJUnitCheckSuites appends the test suites of the own checks of the group
+// and of its nested groups, recursively. The extra groups are the ones whose
+// checks are also included in the checks of the group, besides its nested ones.
+func appendJUnitCheckSuites(suites []junitTestSuite, name string, g Group, extra []Group) []junitTestSuite {
+	suite := junitTestSuite{Name: name}
+	for _, check := range ownChecks(g, extra) {
+		tc := junitTestCase{Name: check.Name, Classname: name}
+		if check.Fails > 0 {
+			total := check.Passes + check.Fails
+			tc.Failure = &junitFailure{
+				Message: fmt.Sprintf("%d of %d checks failed, %.2f%% succeeded",
+					check.Fails, total, float64(check.Passes)/float64(total)*100),
+				Type: "check",
+				Text: fmt.Sprintf("passes=%d fails=%d", check.Passes, check.Fails),
+			}
+		}
+		suite.addCase(tc)
+	}
+	if suite.Tests > 0 {
+		suites = append(suites, suite)
+	}
+
+	for _, groupName := range g.GroupsOrder {
+		suites = appendJUnitCheckSuites(suites, name+groupSeparator+groupName, g.Groups[groupName], nil)
+	}
+	return suites
+}
+
+// ownChecks returns the checks of the group without the ones of its nested
+// groups and of the extra groups, which the group includes.
+func ownChecks(g Group, extra []Group) []Check {
+	if g.Checks == nil {
+		return nil
+	}
+
+	others := make(map[string]Check)
+	add := func(g Group) {
+		if g.Checks == nil {
+			return
+		}
+		for _, check := range g.Checks.OrderedChecks {
+			other := others[check.Name]
+			other.Passes += check.Passes
+			other.Fails += check.Fails
+			others[check.Name] = other
+		}
+	}
+	// The checks of the nested groups are only their own, so all of them are
+	// subtracted, while the ones of the extra groups already include their
+	// nested groups.
+	var addNested func(g Group)
+	addNested = func(g Group) {
+		for _, nested := range g.Groups {
+			add(nested)
+			addNested(nested)
+		}
+	}
+	addNested(g)
+	for _, group := range extra {
+		add(group)
+	}
+
+	checks := make([]Check, 0, len(g.Checks.OrderedChecks))
+	for _, check := range g.Checks.OrderedChecks {
+		own := Check{
+			Name:   check.Name,
+			Passes: check.Passes - others[check.Name].Passes,
+			Fails:  check.Fails - others[check.Name].Fails,
+		}
+		if own.Passes > 0 || own.Fails > 0 {
+			checks = append(checks, own)
+		}
+	}
+	return checks
+}
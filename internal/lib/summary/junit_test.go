@@ -0,0 +1,75 @@
+package summary
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/lib/types"
+)
+
+func TestRenderJUnit(t *testing.T) {
+	t.Parallel()
+
+	withChecks := func(g Group, checks ...*Check) Group {
+		g.Checks = NewChecks()
+		g.Checks.OrderedChecks = checks
+		return g
+	}
+
+	s := New()
+	s.TestRunDuration = types.Duration(1500 * time.Millisecond)
+	s.Thresholds["http_req_duration"] = MetricThresholds{
+		Metric: NewMetricFrom(
+			MetricInfo{Name: "http_req_duration", Type: "trend", Contains: "time"},
+			map[string]float64{"p(95)": 123.5},
+		),
+		Thresholds: []Threshold{{Source: "p(95)<100", Ok: false}, {Source: "avg<500", Ok: true}},
+	}
+
+	// The checks of the root group and of the scenarios include the ones of
+	// their nested groups, and the root group's include the scenarios' ones.
+	s.Group = withChecks(s.Group,
+		&Check{Name: "status is 200", Passes: 5, Fails: 1},
+		&Check{Name: "body is ok", Passes: 2},
+		&Check{Name: "root check", Passes: 1},
+	)
+	login := withChecks(NewGroup(), &Check{Name: "status is 200", Passes: 3, Fails: 1})
+	scenario := withChecks(NewGroup(),
+		&Check{Name: "status is 200", Passes: 5, Fails: 1},
+		&Check{Name: "body is ok", Passes: 2},
+	)
+	scenario.Groups["login"] = login
+	scenario.GroupsOrder = []string{"login"}
+	s.Scenarios["sc"] = scenario
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, RenderJUnit(buf, s))
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="k6" tests="6" failures="2" time="1.500">
+  <testsuite name="thresholds" tests="2" failures="1">
+    <testcase name="p(95)&lt;100" classname="thresholds.http_req_duration">
+      <failure message="http_req_duration: p(95)&lt;100 failed, observed p(95)=123.5ms" type="threshold">p(95)=123.5ms</failure>
+    </testcase>
+    <testcase name="avg&lt;500" classname="thresholds.http_req_duration"></testcase>
+  </testsuite>
+  <testsuite name="checks" tests="1" failures="0">
+    <testcase name="root check" classname="checks"></testcase>
+  </testsuite>
+  <testsuite name="scenario sc" tests="2" failures="0">
+    <testcase name="status is 200" classname="scenario sc"></testcase>
+    <testcase name="body is ok" classname="scenario sc"></testcase>
+  </testsuite>
+  <testsuite name="scenario sc::login" tests="1" failures="1">
+    <testcase name="status is 200" classname="scenario sc::login">
+      <failure message="1 of 4 checks failed, 75.00% succeeded" type="check">passes=3 fails=1</failure>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+	assert.Equal(t, expected, buf.String())
+}
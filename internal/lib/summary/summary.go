@@ -4,6 +4,7 @@ import (
 	"errors"
 	"time"
 
+	"go.k6.io/k6/lib/types"
 	"go.k6.io/k6/metrics"
 )
 
@@ -94,13 +95,16 @@ func ValidateMode(val string) (m Mode, err error) {
 // Summary is the data structure that holds all the summary data (thresholds, metrics, checks, etc)
 // as well as some other information, like certain rendering options.
 type Summary struct {
-	Thresholds `js:"thresholds"`
-	Group      `js:"root_group"`
-	Scenarios  map[string]Group
+	Thresholds `js:"thresholds" json:"thresholds"`
+	Group      `js:"root_group" json:"root_group"`
+	Scenarios  map[string]Group `json:"scenarios"`
 
-	TestRunDuration time.Duration
-	NoColor         bool // TODO: drop this when noColor is part of the (runtime) options
-	EnableColors    bool
+	// Timeline is only collected when it's enabled or something needs it, e.g. the HTML report.
+	Timeline *Timeline `json:"timeline,omitempty"`
+
+	TestRunDuration types.Duration `json:"test_run_duration"`
+	NoColor         bool           `json:"-"` // TODO: drop this when noColor is part of the (runtime) options
+	EnableColors    bool           `json:"-"`
 }
 
 // New instantiates a new empty Summary.
@@ -118,16 +122,16 @@ func New() *Summary {
 // MetricInfo holds the definition of a metric that will be rendered in the summary,
 // including the name of the metric, its type (Counter, Trend, etc.) and what contains (data amounts, times, etc.).
 type MetricInfo struct {
-	Name     string
-	Type     string
-	Contains string
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Contains string `json:"contains"`
 }
 
 // Metric holds all the information needed to display a metric in the summary,
 // including its definition and its values.
 type Metric struct {
 	MetricInfo
-	Values map[string]float64
+	Values map[string]float64 `json:"values"`
 }
 
 // NewMetricFrom instantiates a new Metric for a given metrics.Sink and the metric's info.
@@ -142,24 +146,24 @@ func NewMetricFrom(info MetricInfo, values map[string]float64) Metric {
 type Metrics struct {
 	// HTTP contains summary data specific to HTTP metrics and is used
 	// to produce the summary HTTP subsection's content.
-	HTTP map[string]Metric
+	HTTP map[string]Metric `json:"http"`
 	// Execution contains summary data specific to Execution metrics and is used
 	// to produce the summary Execution subsection's content.
-	Execution map[string]Metric
+	Execution map[string]Metric `json:"execution"`
 	// Network contains summary data specific to Network metrics and is used
 	// to produce the summary Network subsection's content.
-	Network map[string]Metric
+	Network map[string]Metric `json:"network"`
 
-	Browser map[string]Metric
+	Browser map[string]Metric `json:"browser"`
 
-	WebVitals map[string]Metric
+	WebVitals map[string]Metric `json:"web_vitals"`
 
-	Grpc map[string]Metric
+	Grpc map[string]Metric `json:"grpc"`
 
-	WebSocket map[string]Metric `js:"websocket"`
+	WebSocket map[string]Metric `js:"websocket" json:"websocket"`
 
 	// Custom contains user-defined metric results as well as extensions metrics
-	Custom map[string]Metric
+	Custom map[string]Metric `json:"custom"`
 }
 
 // NewMetrics instantiates an empty collection of Metrics.
@@ -178,22 +182,22 @@ func NewMetrics() Metrics {
 
 // ChecksMetrics is the subset of checks-specific metrics.
 type ChecksMetrics struct {
-	Total   Metric `js:"checks_total"`
-	Success Metric `js:"checks_succeeded"`
-	Fail    Metric `js:"checks_failed"`
+	Total   Metric `js:"checks_total" json:"checks_total"`
+	Success Metric `js:"checks_succeeded" json:"checks_succeeded"`
+	Fail    Metric `js:"checks_failed" json:"checks_failed"`
 }
 
 // Check holds the information to be rendered in the summary for a single check.
 type Check struct {
-	Name   string `js:"name"`
-	Passes int64  `js:"passes"`
-	Fails  int64  `js:"fails"`
+	Name   string `js:"name" json:"name"`
+	Passes int64  `js:"passes" json:"passes"`
+	Fails  int64  `js:"fails" json:"fails"`
 }
 
 // Checks holds the checks to be rendered in the summary.
 type Checks struct {
-	Metrics       ChecksMetrics
-	OrderedChecks []*Check
+	Metrics       ChecksMetrics `json:"metrics"`
+	OrderedChecks []*Check      `json:"ordered_checks"`
 }
 
 // NewChecks instantiates an empty set of Checks.
@@ -220,14 +224,14 @@ func NewChecks() *Checks {
 
 // Threshold holds the information of a threshold to be rendered in the summary.
 type Threshold struct {
-	Source string `js:"source"`
-	Ok     bool   `js:"ok"`
+	Source string `js:"source" json:"source"`
+	Ok     bool   `js:"ok" json:"ok"`
 }
 
 // MetricThresholds is the collection of Threshold that belongs to the same metric.
 type MetricThresholds struct {
-	Metric     Metric      `js:"metric"`
-	Thresholds []Threshold `js:"thresholds"`
+	Metric     Metric      `js:"metric" json:"metric"`
+	Thresholds []Threshold `js:"thresholds" json:"thresholds"`
 }
 
 // Thresholds is a collection of MetricThresholds that will be rendered in the summary.
@@ -241,10 +245,11 @@ func NewThresholds() Thresholds {
 
 // Group is a group of metrics and subgroups (recursive) that will be rendered in the summary.
 type Group struct {
-	Checks      *Checks // Not always present, thus we use a pointer.
-	Metrics     Metrics
-	Groups      map[string]Group
-	GroupsOrder []string // Groups names with the order to be displayed in the summary. Typically same as in code.
+	Checks  *Checks          `json:"checks,omitempty"` // Not always present, thus we use a pointer.
+	Metrics Metrics          `json:"metrics"`
+	Groups  map[string]Group `json:"groups"`
+	// Groups names with the order to be displayed in the summary. Typically same as in code.
+	GroupsOrder []string `json:"groups_order"`
 }
 
 // NewGroup instantiates an empty Group.
@@ -255,3 +260,35 @@ func NewGroup() Group {
 		GroupsOrder: make([]string, 0),
 	}
 }
+
+// Timeline holds the values of the main metrics for each time bucket of the test run,
+// so it's possible to see how they changed during the test. The values are also split
+// by scenario and, for the scenarios with stages, by stage.
+type Timeline struct {
+	BucketSize types.Duration              `json:"bucket_size"`
+	Buckets    []TimelineBucket            `json:"buckets"`
+	Scenarios  map[string]ScenarioTimeline `json:"scenarios,omitempty"`
+}
+
+// ScenarioTimeline holds the values of the main metrics for the samples of a single
+// scenario, for each time bucket of the test run and for each stage of the scenario.
+type ScenarioTimeline struct {
+	Buckets []TimelineBucket `json:"buckets"`
+	Stages  []TimelineStage  `json:"stages,omitempty"`
+}
+
+// TimelineStage holds the values of the metrics for the samples of a single stage of
+// a scenario, from the time it started and for its configured duration.
+type TimelineStage struct {
+	Start    time.Time                     `json:"start"`
+	Duration types.Duration                `json:"duration"`
+	Target   int64                         `json:"target"`
+	Metrics  map[string]map[string]float64 `json:"metrics"`
+}
+
+// TimelineBucket holds the values of the metrics for the samples of a single time bucket,
+// by metric name. The buckets without any samples are omitted.
+type TimelineBucket struct {
+	Time    time.Time                     `json:"time"`
+	Metrics map[string]map[string]float64 `json:"metrics"`
+}
@@ -31,6 +31,13 @@ type MetricsEngine struct {
 	metricsWithThresholds   []*metrics.Metric
 	breachedThresholdsCount uint32
 
+	// The sliding windows of the metrics with time-windowed thresholds
+	windows map[*metrics.Metric]*slidingWindow
+	timeNow func() time.Time
+
+	// The active observations of the executors, by their (parent) metrics
+	observations map[*metrics.Metric][]*observedMetric
+
 	// TODO: completely refactor:
 	//   - make these private, add a method to export the raw data
 	//   - do not use an unnecessary map for the observed metrics
@@ -46,6 +53,8 @@ func NewMetricsEngine(registry *metrics.Registry, logger logrus.FieldLogger) (*M
 		registry:        registry,
 		logger:          logger.WithField("component", "metrics-engine"),
 		ObservedMetrics: make(map[string]*metrics.Metric),
+		windows:         make(map[*metrics.Metric]*slidingWindow),
+		timeNow:         time.Now,
 	}
 
 	return me, nil
@@ -105,6 +114,14 @@ func (me *MetricsEngine) getThresholdMetricOrSubmetric(name string) (*metrics.Me
 	return sm.Metric, nil
 }
 
+// addToWindow adds the sample to the sliding window of the metric, if it has
+// any time-windowed thresholds.
+func (me *MetricsEngine) addToWindow(metric *metrics.Metric, sample metrics.Sample) {
+	if window, ok := me.windows[metric]; ok {
+		window.add(sample)
+	}
+}
+
 func (me *MetricsEngine) markObserved(metric *metrics.Metric) {
 	if !metric.Observed {
 		metric.Observed = true
@@ -132,6 +149,9 @@ func (me *MetricsEngine) InitSubMetricsAndThresholds(options lib.Options, onlyLo
 
 		metric.Thresholds = thresholds
 		me.metricsWithThresholds = append(me.metricsWithThresholds, metric)
+		if windows := thresholds.Windows(); len(windows) > 0 {
+			me.windows[metric] = newSlidingWindow(metric, windows)
+		}
 
 		// Mark the metric (and the parent metric, if we're dealing with a
 		// submetric) as observed, so they are shown in the end-of-test summary,
@@ -221,6 +241,7 @@ func (me *MetricsEngine) evaluateThresholds(
 	defer me.MetricsLock.Unlock()
 
 	t := getCurrentTestRunDuration()
+	now := me.timeNow()
 
 	me.logger.Debugf("Running thresholds on %d metrics...", len(me.metricsWithThresholds))
 	for _, m := range me.metricsWithThresholds {
@@ -231,7 +252,12 @@ func (me *MetricsEngine) evaluateThresholds(
 		}
 		m.Tainted = null.BoolFrom(false)
 
-		succ, err := m.Thresholds.Run(m.Sink, t)
+		var windowSinks map[time.Duration]metrics.Sink
+		if window, ok := me.windows[m]; ok {
+			windowSinks = window.sinks(now)
+		}
+
+		succ, err := m.Thresholds.RunWithWindows(m.Sink, windowSinks, t)
 		if err != nil {
 			me.logger.WithField("metric_name", m.Name).WithError(err).Error("Threshold error")
 			continue
@@ -151,6 +151,55 @@ func TestMetricsEngineEvaluateIgnoreEmptySink(t *testing.T) {
 	assert.Empty(t, breached)
 }
 
+func TestMetricsEngineEvaluateWindowedThresholds(t *testing.T) {
+	t.Parallel()
+
+	me := newTestMetricsEngine(t)
+	m1, err := me.registry.NewMetric("m1", metrics.Trend)
+	require.NoError(t, err)
+
+	ths := metrics.NewThresholds([]string{"p(95) over 1m<100", "p(95)<1000"})
+	require.NoError(t, ths.Parse())
+	ths.Thresholds[0].AbortOnFail = true
+	require.NoError(t, me.InitSubMetricsAndThresholds(lib.Options{
+		Thresholds: map[string]metrics.Thresholds{"m1": ths},
+	}, false))
+
+	start := time.Unix(1700000000, 0)
+	addSample := func(offset time.Duration, value float64) {
+		sample := metrics.Sample{Time: start.Add(offset), Value: value}
+		m1.Sink.Add(sample)
+		me.addToWindow(m1, sample)
+	}
+	evaluateAt := func(offset time.Duration) ([]string, bool) {
+		me.timeNow = func() time.Time { return start.Add(offset) }
+		return me.evaluateThresholds(false, func() time.Duration { return offset })
+	}
+
+	// The slow requests at the start are enough to fail the threshold
+	// while they are part of the window.
+	for i := range 10 {
+		addSample(time.Duration(i)*time.Second, 500)
+	}
+	breached, abort := evaluateAt(10 * time.Second)
+	assert.True(t, abort)
+	assert.Equal(t, []string{"m1"}, breached)
+	assert.Equal(t, map[string]float64{"p(95) over 1m": 500}, m1.Thresholds.WindowValues())
+
+	// Once they are out of the window, only the recent requests matter for
+	// aborting, even if the whole test run aggregation is still dominated by
+	// the slow ones, but the threshold stays failed for the end-of-test result.
+	for i := range 5 {
+		addSample(time.Duration(65+i)*time.Second, 20)
+	}
+	m1.Thresholds.Abort = false // the abort is sticky, once triggered
+	breached, abort = evaluateAt(70 * time.Second)
+	assert.False(t, abort)
+	assert.Equal(t, []string{"m1"}, breached)
+	assert.Equal(t, map[string]float64{"p(95) over 1m": 20}, m1.Thresholds.WindowValues())
+	assert.Len(t, me.windows[m1].samples, 5)
+}
+
 func newTestMetricsEngine(t *testing.T) *MetricsEngine {
 	m, err := NewMetricsEngine(metrics.NewRegistry(), testutils.NewLogger(t))
 	require.NoError(t, err)
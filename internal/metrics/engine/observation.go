@@ -0,0 +1,101 @@
+package engine
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/metrics"
+)
+
+var _ lib.MetricsObserver = &MetricsEngine{}
+
+// observedMetric aggregates the samples of a metric, or of a sub-metric when it
+// has tags, that were emitted after the start of its observation. Its sink is
+// separate from the one used for the end-of-test summary and the thresholds.
+type observedMetric struct {
+	tags  *metrics.TagSet
+	start time.Time
+	sink  metrics.Sink
+}
+
+// ObserveMetrics implements the lib.MetricsObserver interface. The samples are
+// aggregated as they are ingested, so the ones emitted right before the
+// observation is stopped could still be missing from its sinks.
+func (me *MetricsEngine) ObserveMetrics(names []string) (func() map[string]metrics.Sink, error) {
+	metricsByName := make(map[string]*metrics.Metric, len(names))
+	observations := make(map[string]*observedMetric, len(names))
+	start := me.timeNow()
+	for _, name := range names {
+		metric, tags, err := me.getObservedMetric(name)
+		if err != nil {
+			return nil, err
+		}
+		metricsByName[name] = metric
+		observations[name] = &observedMetric{tags: tags, start: start, sink: metric.NewSink()}
+	}
+
+	me.MetricsLock.Lock()
+	defer me.MetricsLock.Unlock()
+	if me.observations == nil {
+		me.observations = make(map[*metrics.Metric][]*observedMetric)
+	}
+	for name, om := range observations {
+		me.observations[metricsByName[name]] = append(me.observations[metricsByName[name]], om)
+	}
+
+	return func() map[string]metrics.Sink {
+		me.MetricsLock.Lock()
+		defer me.MetricsLock.Unlock()
+
+		sinks := make(map[string]metrics.Sink, len(observations))
+		for name, om := range observations {
+			metric := metricsByName[name]
+			me.observations[metric] = slices.DeleteFunc(me.observations[metric], func(o *observedMetric) bool {
+				return o == om
+			})
+			if len(me.observations[metric]) == 0 {
+				delete(me.observations, metric)
+			}
+			sinks[name] = om.sink
+		}
+		return sinks
+	}, nil
+}
+
+// getObservedMetric returns the metric with the given name and, if the name
+// is of a sub-metric, the tags its samples should have. Unlike the thresholds,
+// it doesn't add a sub-metric, so it won't be shown in the end-of-test summary.
+func (me *MetricsEngine) getObservedMetric(name string) (*metrics.Metric, *metrics.TagSet, error) {
+	metricName, tagExpressions, err := metrics.ParseMetricName(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	metric := me.registry.Get(metricName)
+	if metric == nil {
+		return nil, nil, fmt.Errorf("metric '%s' does not exist in the script", metricName)
+	}
+	if len(tagExpressions) == 0 {
+		return metric, nil, nil
+	}
+
+	tags := me.registry.RootTagSet()
+	for _, kv := range tagExpressions {
+		k, v, _ := strings.Cut(kv, ":")
+		tags = tags.With(strings.Trim(strings.TrimSpace(k), `"'`), strings.Trim(strings.TrimSpace(v), `"'`))
+	}
+	return metric, tags, nil
+}
+
+// addToObservations adds the sample to the sinks of the active observations of
+// its metric, when it matches their start time and tags.
+func (me *MetricsEngine) addToObservations(metric *metrics.Metric, sample metrics.Sample) {
+	for _, om := range me.observations[metric] {
+		if sample.Time.Before(om.start) || (om.tags != nil && !sample.Tags.Contains(om.tags)) {
+			continue
+		}
+		om.sink.Add(sample)
+	}
+}
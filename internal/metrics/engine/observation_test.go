@@ -0,0 +1,59 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.k6.io/k6/metrics"
+)
+
+func TestObserveMetrics(t *testing.T) {
+	t.Parallel()
+
+	piState := newTestPreInitState(t)
+	testMetric, err := piState.Registry.NewMetric("test_metric", metrics.Trend)
+	require.NoError(t, err)
+
+	me, err := NewMetricsEngine(piState.Registry, piState.Logger)
+	require.NoError(t, err)
+	now := time.Now()
+	me.timeNow = func() time.Time { return now }
+
+	_, err = me.ObserveMetrics([]string{"unknown_metric"})
+	require.ErrorContains(t, err, "metric 'unknown_metric' does not exist in the script")
+	_, err = me.ObserveMetrics([]string{"test_metric{a:1"})
+	require.ErrorIs(t, err, metrics.ErrMetricNameParsing)
+
+	stop, err := me.ObserveMetrics([]string{"test_metric", "test_metric{a: '1'}"})
+	require.NoError(t, err)
+
+	ingester := me.CreateIngester()
+	require.NoError(t, ingester.Start())
+	addSample := func(at time.Time, value float64, tags map[string]string) {
+		ingester.AddMetricSamples([]metrics.SampleContainer{metrics.Sample{
+			TimeSeries: metrics.TimeSeries{
+				Metric: testMetric,
+				Tags:   piState.Registry.RootTagSet().WithTagsFromMap(tags),
+			},
+			Time:  at,
+			Value: value,
+		}})
+	}
+	addSample(now.Add(-time.Second), 1, map[string]string{"a": "1"}) // emitted before the observation
+	addSample(now, 10, map[string]string{"a": "1", "b": "2"})
+	addSample(now.Add(time.Second), 20, map[string]string{"a": "2"})
+	require.NoError(t, ingester.Stop())
+
+	sinks := stop()
+	require.Len(t, sinks, 2)
+	assert.Equal(t, 30.0, sinks["test_metric"].(*metrics.TrendSink).Total())         //nolint:forcetypeassert
+	assert.Equal(t, 10.0, sinks["test_metric{a: '1'}"].(*metrics.TrendSink).Total()) //nolint:forcetypeassert
+	assert.Empty(t, me.observations)
+
+	// the observations don't change the sinks of the summary and the thresholds,
+	// nor do they add sub-metrics
+	assert.Equal(t, 31.0, testMetric.Sink.(*metrics.TrendSink).Total()) //nolint:forcetypeassert
+	assert.Empty(t, testMetric.Submetrics)
+}
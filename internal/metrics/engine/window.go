@@ -0,0 +1,61 @@
+package engine
+
+import (
+	"time"
+
+	"go.k6.io/k6/metrics"
+)
+
+// windowSample is the part of a metric sample that the sinks need.
+type windowSample struct {
+	time  time.Time
+	value float64
+}
+
+// slidingWindow keeps the most recent samples of a metric that has
+// time-windowed thresholds, like `p(95) over 1m < 300`, so that their
+// aggregations can be computed over only the last part of the test run.
+//
+// The samples are kept as they are, instead of being pre-aggregated, because
+// not every sink can be merged (e.g. the exact percentiles of a Trend). So,
+// the memory usage is proportional to the number of samples in the longest window.
+type slidingWindow struct {
+	metric  *metrics.Metric
+	windows []time.Duration // sorted, so the last one is the longest
+	samples []windowSample
+}
+
+func newSlidingWindow(metric *metrics.Metric, windows []time.Duration) *slidingWindow {
+	return &slidingWindow{metric: metric, windows: windows}
+}
+
+func (sw *slidingWindow) add(sample metrics.Sample) {
+	sw.samples = append(sw.samples, windowSample{time: sample.Time, value: sample.Value})
+}
+
+// sinks drops the samples that are older than the longest window and then
+// returns a new sink for each window, with the samples that are still in it.
+func (sw *slidingWindow) sinks(now time.Time) map[time.Duration]metrics.Sink {
+	oldest := now.Add(-sw.windows[len(sw.windows)-1])
+	expired := 0
+	for expired < len(sw.samples) && sw.samples[expired].time.Before(oldest) {
+		expired++
+	}
+	sw.samples = append(sw.samples[:0], sw.samples[expired:]...)
+
+	sinks := make(map[time.Duration]metrics.Sink, len(sw.windows))
+	for _, window := range sw.windows {
+		sink := sw.metric.NewSink()
+		start := now.Add(-window)
+		for _, s := range sw.samples {
+			// Samples are flushed in batches, so they may not be completely
+			// sorted by time; checking each of them keeps the windows exact.
+			if s.time.Before(start) {
+				continue
+			}
+			sink.Add(metrics.Sample{Time: s.time, Value: s.value})
+		}
+		sinks[window] = sink
+	}
+	return sinks
+}
@@ -0,0 +1,115 @@
+package parquet
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/guregu/null.v3"
+
+	"github.com/mstoykov/envconfig"
+)
+
+// Config is the config for the parquet output
+type Config struct {
+	FileName     null.String `json:"file_name" envconfig:"K6_PARQUET_FILENAME"`
+	RowGroupSize null.Int    `json:"row_group_size" envconfig:"K6_PARQUET_ROW_GROUP_SIZE"`
+	Compression  null.String `json:"compression" envconfig:"K6_PARQUET_COMPRESSION"`
+}
+
+// NewConfig creates a new Config instance with default values for some fields.
+func NewConfig() Config {
+	return Config{
+		FileName:     null.NewString("file.parquet", false),
+		RowGroupSize: null.NewInt(100000, false),
+		Compression:  null.NewString("snappy", false),
+	}
+}
+
+// Apply merges two configs by overwriting properties in the old config
+func (c Config) Apply(cfg Config) Config {
+	if cfg.FileName.Valid {
+		c.FileName = cfg.FileName
+	}
+	if cfg.RowGroupSize.Valid {
+		c.RowGroupSize = cfg.RowGroupSize
+	}
+	if cfg.Compression.Valid {
+		c.Compression = cfg.Compression
+	}
+	return c
+}
+
+// ParseArg takes an arg string and converts it to a config
+func ParseArg(arg string) (Config, error) {
+	c := NewConfig()
+
+	if !strings.Contains(arg, "=") {
+		c.FileName = null.StringFrom(arg)
+		return c, nil
+	}
+
+	pairs := strings.Split(arg, ",")
+	for _, pair := range pairs {
+		k, v, _ := strings.Cut(pair, "=")
+		if v == "" {
+			return c, fmt.Errorf("couldn't parse %q as argument for parquet output", arg)
+		}
+		switch k {
+		case "fileName":
+			c.FileName = null.StringFrom(v)
+		case "rowGroupSize":
+			size, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return c, fmt.Errorf("couldn't parse the row group size %q: %w", v, err)
+			}
+			c.RowGroupSize = null.IntFrom(size)
+		case "compression":
+			c.Compression = null.StringFrom(v)
+		default:
+			return c, fmt.Errorf("unknown key %q as argument for parquet output", k)
+		}
+	}
+
+	return c, nil
+}
+
+// GetConsolidatedConfig combines {default config values + JSON config +
+// environment vars + arg config values}, and returns the final result.
+func GetConsolidatedConfig(
+	jsonRawConf json.RawMessage, env map[string]string, arg string,
+) (Config, error) {
+	result := NewConfig()
+	if jsonRawConf != nil {
+		jsonConf := Config{}
+		if err := json.Unmarshal(jsonRawConf, &jsonConf); err != nil {
+			return result, err
+		}
+		result = result.Apply(jsonConf)
+	}
+
+	envConfig := Config{}
+	if err := envconfig.Process("", &envConfig, func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}); err != nil {
+		// TODO: get rid of envconfig and actually use the env parameter...
+		return result, err
+	}
+	result = result.Apply(envConfig)
+
+	if arg != "" {
+		urlConf, err := ParseArg(arg)
+		if err != nil {
+			return result, err
+		}
+		result = result.Apply(urlConf)
+	}
+
+	if result.RowGroupSize.Int64 <= 0 {
+		return result, fmt.Errorf("the row group size must be positive, got %d", result.RowGroupSize.Int64)
+	}
+
+	return result, nil
+}
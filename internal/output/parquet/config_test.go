@@ -0,0 +1,102 @@
+package parquet
+
+import (
+	"testing"
+
+	"gopkg.in/guregu/null.v3"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewConfig(t *testing.T) {
+	t.Parallel()
+
+	config := NewConfig()
+	assert.Equal(t, "file.parquet", config.FileName.String)
+	assert.Equal(t, int64(100000), config.RowGroupSize.Int64)
+	assert.Equal(t, "snappy", config.Compression.String)
+}
+
+func TestParseArg(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		config      Config
+		expectedErr bool
+	}{
+		"test_file.parquet": {
+			config: Config{
+				FileName:     null.StringFrom("test_file.parquet"),
+				RowGroupSize: null.NewInt(100000, false),
+				Compression:  null.NewString("snappy", false),
+			},
+		},
+		"rowGroupSize=500,compression=zstd": {
+			config: Config{
+				FileName:     null.NewString("file.parquet", false),
+				RowGroupSize: null.IntFrom(500),
+				Compression:  null.StringFrom("zstd"),
+			},
+		},
+		"fileName=test.parquet,compression=none": {
+			config: Config{
+				FileName:     null.StringFrom("test.parquet"),
+				RowGroupSize: null.NewInt(100000, false),
+				Compression:  null.StringFrom("none"),
+			},
+		},
+		"filename=test.parquet": {
+			expectedErr: true,
+		},
+		"rowGroupSize=many": {
+			expectedErr: true,
+		},
+		"fileName=": {
+			expectedErr: true,
+		},
+	}
+
+	for arg, testCase := range cases {
+		t.Run(arg, func(t *testing.T) {
+			t.Parallel()
+
+			config, err := ParseArg(arg)
+
+			if testCase.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.config, config)
+		})
+	}
+}
+
+func TestGetConsolidatedConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("precedence", func(t *testing.T) {
+		t.Parallel()
+
+		config, err := GetConsolidatedConfig(
+			[]byte(`{"file_name":"json.parquet","row_group_size":10,"compression":"gzip"}`),
+			map[string]string{"K6_PARQUET_ROW_GROUP_SIZE": "20", "K6_PARQUET_COMPRESSION": "zstd"},
+			"compression=none",
+		)
+		require.NoError(t, err)
+		assert.Equal(t, Config{
+			FileName:     null.StringFrom("json.parquet"),
+			RowGroupSize: null.IntFrom(20),
+			Compression:  null.StringFrom("none"),
+		}, config)
+	})
+
+	t.Run("invalid row group size", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := GetConsolidatedConfig(nil, nil, "rowGroupSize=0")
+		require.ErrorContains(t, err, "the row group size must be positive")
+	})
+}
@@ -0,0 +1,2 @@
+// Package parquet implements an output writing the metric samples to a file in the Parquet columnar format
+package parquet
@@ -0,0 +1,115 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+)
+
+// maxBitPackedGroups is the maximum number of groups of 8 values in a single
+// bit-packed run, the same limit that most Parquet writers use.
+const maxBitPackedGroups = 63
+
+// appendHybrid appends the values encoded with the RLE/bit-packing hybrid encoding,
+// which is used for the definition levels and the dictionary indices. The runs of at
+// least 8 equal values are run-length encoded and the rest of the values are bit-packed.
+func appendHybrid(dst []byte, values []uint32, bitWidth int) []byte {
+	var pending []uint32
+	flushPending := func(final bool) {
+		for len(pending) > 0 {
+			n := min(len(pending), maxBitPackedGroups*8)
+			if !final && n%8 != 0 {
+				return
+			}
+			dst = appendBitPacked(dst, pending[:n], bitWidth)
+			pending = pending[n:]
+		}
+	}
+
+	for i := 0; i < len(values); {
+		run := 1
+		for i+run < len(values) && values[i+run] == values[i] {
+			run++
+		}
+		// The bit-packed runs are made of whole groups of 8 values, so a
+		// run-length encoded run can only start after a complete group.
+		if run >= 8 && len(pending)%8 == 0 {
+			flushPending(false)
+			dst = binary.AppendUvarint(dst, uint64(run)<<1) //nolint:gosec
+			dst = appendLittleEndian(dst, values[i], (bitWidth+7)/8)
+			i += run
+			continue
+		}
+		pending = append(pending, values[i])
+		i++
+	}
+	flushPending(true)
+
+	return dst
+}
+
+// appendBitPacked appends a bit-packed run of the values, padded with zeros to a multiple of 8.
+func appendBitPacked(dst []byte, values []uint32, bitWidth int) []byte {
+	groups := (len(values) + 7) / 8
+	dst = binary.AppendUvarint(dst, uint64(groups)<<1|1) //nolint:gosec
+
+	var (
+		buffer uint64
+		bits   int
+	)
+	for i := range groups * 8 {
+		var v uint32
+		if i < len(values) {
+			v = values[i]
+		}
+		buffer |= uint64(v) << bits
+		bits += bitWidth
+		for bits >= 8 {
+			dst = append(dst, byte(buffer))
+			buffer >>= 8
+			bits -= 8
+		}
+	}
+	return dst
+}
+
+func appendLittleEndian(dst []byte, v uint32, size int) []byte {
+	for range size {
+		dst = append(dst, byte(v))
+		v >>= 8
+	}
+	return dst
+}
+
+// encodeDefinitionLevels encodes the definition levels of an optional column, as they're
+// written in the data pages, i.e. with the RLE encoding and prefixed by their length.
+func encodeDefinitionLevels(buf *bytes.Buffer, defined []bool) {
+	levels := make([]uint32, len(defined))
+	for i, d := range defined {
+		if d {
+			levels[i] = 1
+		}
+	}
+	encoded := appendHybrid(nil, levels, 1)
+	buf.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(encoded)))) //nolint:gosec
+	buf.Write(encoded)
+}
+
+func encodePlainInt64(buf *bytes.Buffer, values []int64) {
+	for _, v := range values {
+		buf.Write(binary.LittleEndian.AppendUint64(nil, uint64(v))) //nolint:gosec
+	}
+}
+
+func encodePlainDouble(buf *bytes.Buffer, values []float64) {
+	for _, v := range values {
+		buf.Write(binary.LittleEndian.AppendUint64(nil, math.Float64bits(v)))
+	}
+}
+
+func encodePlainString(buf *bytes.Buffer, values []string) {
+	for _, v := range values {
+		buf.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(v)))) //nolint:gosec
+		buf.WriteString(v)
+	}
+}
@@ -0,0 +1,78 @@
+package parquet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppendHybrid(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		values   []uint32
+		bitWidth int
+		expected []byte
+	}{
+		{
+			name:     "run-length encoded",
+			values:   []uint32{1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
+			bitWidth: 1,
+			expected: []byte{0x14, 0x01},
+		},
+		{
+			name:     "bit-packed and padded",
+			values:   []uint32{0, 1, 2, 3},
+			bitWidth: 2,
+			expected: []byte{0x03, 0xe4, 0x00},
+		},
+		{
+			name:     "bit-packed followed by run-length encoded",
+			values:   []uint32{0, 1, 2, 3, 4, 5, 6, 7, 3, 3, 3, 3, 3, 3, 3, 3, 3},
+			bitWidth: 4,
+			expected: []byte{0x03, 0x10, 0x32, 0x54, 0x76, 0x12, 0x03},
+		},
+		{
+			name:     "short run after an incomplete group is bit-packed",
+			values:   []uint32{1, 0, 0, 0, 0, 0, 0, 0, 0},
+			bitWidth: 1,
+			expected: []byte{0x05, 0x01, 0x00},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, appendHybrid(nil, tc.values, tc.bitWidth))
+		})
+	}
+}
+
+func TestThriftWriter(t *testing.T) {
+	t.Parallel()
+
+	var w thriftWriter
+	w.beginStruct()
+	w.i32Field(1, 1)
+	w.stringField(4, "ab")
+	w.i64Field(20, -1)
+	w.boolField(21, true)
+	w.listField(22, thriftI32, 2)
+	w.i32(3)
+	w.i32(-3)
+	w.structField(23)
+	w.i32Field(1, 0)
+	w.endStruct()
+	w.endStruct()
+
+	assert.Equal(t, []byte{
+		0x15, 0x02, // field 1, i32 1
+		0x38, 0x02, 'a', 'b', // field 4, binary "ab"
+		0x06, 0x28, 0x01, // field 20, with the long form of the header, i64 -1
+		0x11,                   // field 21, bool true
+		0x19, 0x25, 0x06, 0x05, // field 22, list of 2 i32, 3 and -3
+		0x1c, 0x15, 0x00, 0x00, // field 23, struct with field 1, i32 0
+		0x00, // stop
+	}, w.bytes())
+}
@@ -0,0 +1,235 @@
+package parquet
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"go.k6.io/k6/internal/build"
+	"go.k6.io/k6/metrics"
+	"go.k6.io/k6/output"
+)
+
+const flushPeriod = time.Second
+
+// The indices of the columns that every written file has, which are followed
+// by the columns of the tags.
+const (
+	colMetricName = iota
+	colTimestamp
+	colMetricValue
+	colTags
+)
+
+// Output implements the lib.Output interface for saving the metric samples to a
+// Parquet file, with a column for each indexable system tag and dictionary-encoded
+// string columns, so it can be loaded straight into tools like DuckDB or pandas.
+type Output struct {
+	output.SampleBuffer
+
+	periodicFlusher *output.PeriodicFlusher
+	logger          logrus.FieldLogger
+
+	fname        string
+	file         io.WriteCloser
+	writer       *fileWriter
+	rowGroupSize int
+
+	resTags     []string
+	ignoredTags []string
+	columns     []columnBuffer
+	rows        int
+
+	// writeErr is the first error while writing the file, after which
+	// nothing else is written, as the file can't be valid anymore.
+	writeErr error
+}
+
+// New creates a new instance of the parquet output
+func New(params output.Params) (output.Output, error) {
+	return newOutput(params)
+}
+
+func newOutput(params output.Params) (*Output, error) {
+	config, err := GetConsolidatedConfig(params.JSONConfig, params.Environment, params.ConfigArgument)
+	if err != nil {
+		return nil, err
+	}
+
+	resTags, ignoredTags, err := buildTagSets(params)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := []columnDef{
+		colMetricName:  {name: "metric_name", kind: stringColumn},
+		colTimestamp:   {name: "timestamp", kind: timestampColumn},
+		colMetricValue: {name: "metric_value", kind: doubleColumn},
+	}
+	for _, tag := range resTags {
+		columns = append(columns, columnDef{name: tag, kind: stringColumn, optional: true})
+	}
+	columns = append(columns,
+		columnDef{name: "extra_tags", kind: stringColumn, optional: true},
+		columnDef{name: "metadata", kind: stringColumn, optional: true},
+	)
+
+	fname := config.FileName.String
+	file, err := params.FS.Create(fname)
+	if err != nil {
+		return nil, err
+	}
+	writer, err := newFileWriter(file, columns, config.Compression.String, "k6 version "+build.Version)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	return &Output{
+		logger: params.Logger.WithFields(logrus.Fields{
+			"output":   "parquet",
+			"filename": fname,
+		}),
+		fname:        fname,
+		file:         file,
+		writer:       writer,
+		rowGroupSize: int(config.RowGroupSize.Int64),
+		resTags:      resTags,
+		ignoredTags:  ignoredTags,
+		columns:      make([]columnBuffer, len(columns)),
+	}, nil
+}
+
+// buildTagSets builds the sets of the system tags that have their own columns and of
+// the ignored ones, like the csv output does.
+func buildTagSets(params output.Params) ([]string, []string, error) {
+	resTags := []string{}
+	ignoredTags := []string{}
+	for tag, flag := range params.ScriptOptions.SystemTags.Map() {
+		systemTag, err := metrics.SystemTagString(tag)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// The non-indexable system tags are added to the "metadata" column
+		if metrics.NonIndexableSystemTags.Has(systemTag) {
+			continue
+		}
+
+		if flag {
+			resTags = append(resTags, tag)
+		} else {
+			ignoredTags = append(ignoredTags, tag)
+		}
+	}
+
+	sort.Strings(resTags)
+	sort.Strings(ignoredTags)
+
+	return resTags, ignoredTags, nil
+}
+
+// Description returns a human-readable description of the output.
+func (o *Output) Description() string {
+	return fmt.Sprintf("parquet (%s)", o.fname)
+}
+
+// Start starts a new output.PeriodicFlusher
+func (o *Output) Start() error {
+	o.logger.Debug("Starting...")
+	pf, err := output.NewPeriodicFlusher(flushPeriod, o.flushMetrics)
+	if err != nil {
+		return err
+	}
+	o.logger.Debug("Started!")
+	o.periodicFlusher = pf
+	return nil
+}
+
+// Stop flushes any remaining metrics, writes the last row group and the metadata
+// of the file, and closes it.
+func (o *Output) Stop() error {
+	o.logger.Debug("Stopping...")
+	defer o.logger.Debug("Stopped!")
+	o.periodicFlusher.Stop()
+
+	if o.rows > 0 {
+		o.writeRowGroup()
+	}
+	if o.writeErr == nil {
+		o.writeErr = o.writer.close()
+	}
+	if err := o.file.Close(); err != nil && o.writeErr == nil {
+		o.writeErr = err
+	}
+	return o.writeErr
+}
+
+func (o *Output) flushMetrics() {
+	for _, sc := range o.GetBufferedSamples() {
+		for _, sample := range sc.GetSamples() {
+			o.addRow(sample)
+			if o.rows >= o.rowGroupSize {
+				o.writeRowGroup()
+			}
+		}
+	}
+}
+
+func (o *Output) addRow(sample metrics.Sample) {
+	o.columns[colMetricName].strings = append(o.columns[colMetricName].strings, sample.Metric.Name)
+	o.columns[colTimestamp].int64s = append(o.columns[colTimestamp].int64s, sample.Time.UnixMicro())
+	o.columns[colMetricValue].doubles = append(o.columns[colMetricValue].doubles, sample.Value)
+
+	sampleTags := sample.Tags.Map()
+	for i, tag := range o.resTags {
+		value, ok := sampleTags[tag]
+		o.columns[colTags+i].addOptional(value, ok)
+	}
+
+	var extraTags []string
+	for tag, value := range sampleTags {
+		if !isStringInSlice(o.resTags, tag) && !isStringInSlice(o.ignoredTags, tag) {
+			extraTags = append(extraTags, tag+"="+value)
+		}
+	}
+	o.columns[len(o.columns)-2].addOptional(joinSorted(extraTags), len(extraTags) > 0)
+
+	metadata := make([]string, 0, len(sample.Metadata))
+	for key, value := range sample.Metadata {
+		metadata = append(metadata, key+"="+value)
+	}
+	o.columns[len(o.columns)-1].addOptional(joinSorted(metadata), len(metadata) > 0)
+
+	o.rows++
+}
+
+// joinSorted joins the key-value pairs in the same format as the csv output, but
+// sorted, so the same sets of them are always the same value in the dictionaries.
+func joinSorted(pairs []string) string {
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+func (o *Output) writeRowGroup() {
+	if o.writeErr == nil {
+		o.writeErr = o.writer.writeRowGroup(o.columns, o.rows)
+		if o.writeErr != nil {
+			o.logger.WithError(o.writeErr).Error("Parquet: Error writing to file, no more samples will be written")
+		}
+	}
+
+	for i := range o.columns {
+		o.columns[i].reset()
+	}
+	o.rows = 0
+}
+
+func isStringInSlice(slice []string, str string) bool {
+	index := sort.SearchStrings(slice, str)
+	return index < len(slice) && slice[index] == str
+}
@@ -0,0 +1,92 @@
+package parquet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/internal/lib/testutils"
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/fsext"
+	"go.k6.io/k6/metrics"
+	"go.k6.io/k6/output"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	registry := metrics.NewRegistry()
+	testMetric, err := registry.NewMetric("my_metric", metrics.Gauge)
+	require.NoError(t, err)
+
+	samples := make([]metrics.SampleContainer, 0, 3)
+	for i := range 3 {
+		tags := map[string]string{"check": "val1", "url": "val2"}
+		if i == 2 {
+			tags["error"] = "val3"
+		}
+		samples = append(samples, metrics.Sample{
+			TimeSeries: metrics.TimeSeries{
+				Metric: testMetric,
+				Tags:   registry.RootTagSet().WithTagsFromMap(tags),
+			},
+			Metadata: map[string]string{"z": "3", "y": "2"},
+			Time:     time.Unix(1562324643, 0),
+			Value:    float64(i),
+		})
+	}
+
+	mem := fsext.NewMemMapFs()
+	out, err := newOutput(output.Params{
+		Logger:         testutils.NewLogger(t),
+		FS:             mem,
+		ConfigArgument: "fileName=test.parquet,rowGroupSize=2,compression=none",
+		ScriptOptions: lib.Options{
+			SystemTags: metrics.NewSystemTagSet(metrics.TagError | metrics.TagCheck | metrics.TagVU),
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "parquet (test.parquet)", out.Description())
+
+	require.NoError(t, out.Start())
+	out.AddMetricSamples(samples)
+	require.NoError(t, out.Stop())
+
+	// The samples don't fit in a single row group
+	require.Len(t, out.writer.rowGroups, 2)
+	assert.Equal(t, int64(2), out.writer.rowGroups[0].numRows)
+	assert.Equal(t, int64(1), out.writer.rowGroups[1].numRows)
+	assert.Equal(t, int64(3), out.writer.numRows)
+
+	data, err := fsext.ReadFile(mem, "test.parquet")
+	require.NoError(t, err)
+	pf := readParquetFile(t, data)
+	assert.Equal(t, int64(3), pf.meta.int(t, 3))
+
+	timestamp := time.Unix(1562324643, 0).UnixMicro()
+	assert.Equal(t, map[string][]any{
+		"metric_name":  {"my_metric", "my_metric", "my_metric"},
+		"timestamp":    {timestamp, timestamp, timestamp},
+		"metric_value": {0.0, 1.0, 2.0},
+		"check":        {"val1", "val1", "val1"},
+		"error":        {nil, nil, "val3"},
+		"extra_tags":   {"url=val2", "url=val2", "url=val2"},
+		"metadata":     {"y=2&z=3", "y=2&z=3", "y=2&z=3"},
+	}, pf.columns)
+}
+
+func TestNewOutputInvalidCompression(t *testing.T) {
+	t.Parallel()
+
+	_, err := newOutput(output.Params{
+		Logger:         testutils.NewLogger(t),
+		FS:             fsext.NewMemMapFs(),
+		ConfigArgument: "compression=lz4",
+		ScriptOptions: lib.Options{
+			SystemTags: &metrics.DefaultSystemTagSet,
+		},
+	})
+	require.ErrorContains(t, err, "lz4")
+}
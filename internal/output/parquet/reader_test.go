@@ -0,0 +1,527 @@
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"flag"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// This file has a minimal Parquet reader, independent from the writer, which decodes the
+// files written by it in the way the other Parquet readers do, to test them end to end.
+
+var update = flag.Bool("update", false, "update the golden-*.parquet files") //nolint:gochecknoglobals
+
+// decodedStruct is a decoded Thrift struct, with the values of its fields by their IDs.
+// The integers are int64, the binaries []byte, the lists []any and the structs decodedStruct.
+type decodedStruct map[int16]any
+
+func (s decodedStruct) int(t *testing.T, id int16) int64 {
+	t.Helper()
+	v, ok := s[id].(int64)
+	require.Truef(t, ok, "the field %d isn't an integer: %#v", id, s[id])
+	return v
+}
+
+func (s decodedStruct) string(t *testing.T, id int16) string {
+	t.Helper()
+	v, ok := s[id].([]byte)
+	require.Truef(t, ok, "the field %d isn't a binary: %#v", id, s[id])
+	return string(v)
+}
+
+func (s decodedStruct) list(t *testing.T, id int16) []any {
+	t.Helper()
+	v, ok := s[id].([]any)
+	require.Truef(t, ok, "the field %d isn't a list: %#v", id, s[id])
+	return v
+}
+
+func (s decodedStruct) structs(t *testing.T, id int16) []decodedStruct {
+	t.Helper()
+	var result []decodedStruct
+	for _, v := range s.list(t, id) {
+		elem, ok := v.(decodedStruct)
+		require.Truef(t, ok, "an element of the field %d isn't a struct: %#v", id, v)
+		result = append(result, elem)
+	}
+	return result
+}
+
+func (s decodedStruct) strct(t *testing.T, id int16) decodedStruct {
+	t.Helper()
+	v, ok := s[id].(decodedStruct)
+	require.Truef(t, ok, "the field %d isn't a struct: %#v", id, s[id])
+	return v
+}
+
+// thriftReader decodes the structs serialized with the Thrift compact protocol.
+type thriftReader struct {
+	t   *testing.T
+	buf *bytes.Reader
+}
+
+func (r *thriftReader) varint() uint64 {
+	v, err := binary.ReadUvarint(r.buf)
+	require.NoError(r.t, err)
+	return v
+}
+
+func (r *thriftReader) zigzag() int64 {
+	v := r.varint()
+	return int64(v>>1) ^ -int64(v&1) //nolint:gosec
+}
+
+func (r *thriftReader) byte() byte {
+	b, err := r.buf.ReadByte()
+	require.NoError(r.t, err)
+	return b
+}
+
+func (r *thriftReader) value(valueType byte) any {
+	switch valueType {
+	case thriftBoolTrue:
+		return true
+	case thriftBoolFalse:
+		return false
+	case thriftI32, thriftI64:
+		return r.zigzag()
+	case thriftBinary:
+		data := make([]byte, r.varint())
+		_, err := io.ReadFull(r.buf, data)
+		require.NoError(r.t, err)
+		return data
+	case thriftList:
+		header := r.byte()
+		size, elemType := uint64(header>>4), header&0x0f
+		if size == 15 {
+			size = r.varint()
+		}
+		list := make([]any, 0, size)
+		for range size {
+			list = append(list, r.value(elemType))
+		}
+		return list
+	case thriftStruct:
+		return r.readStruct()
+	default:
+		r.t.Fatalf("unexpected Thrift type %d", valueType)
+		return nil
+	}
+}
+
+func (r *thriftReader) readStruct() decodedStruct {
+	s := decodedStruct{}
+	var lastID int16
+	for {
+		header := r.byte()
+		if header == 0 {
+			return s
+		}
+		id := lastID + int16(header>>4)
+		if header>>4 == 0 {
+			id = int16(r.zigzag()) //nolint:gosec
+		}
+		_, duplicate := s[id]
+		require.Falsef(r.t, duplicate, "the field %d is repeated", id)
+		s[id] = r.value(header & 0x0f)
+		lastID = id
+	}
+}
+
+// decodeThrift decodes a struct at the start of data, and returns it with its size.
+func decodeThrift(t *testing.T, data []byte) (decodedStruct, int) {
+	t.Helper()
+	r := &thriftReader{t: t, buf: bytes.NewReader(data)}
+	s := r.readStruct()
+	return s, len(data) - r.buf.Len()
+}
+
+// decodeHybrid decodes count values encoded with the RLE/bit-packing hybrid encoding.
+func decodeHybrid(t *testing.T, r *bytes.Reader, bitWidth, count int) []uint32 {
+	t.Helper()
+	values := make([]uint32, 0, count)
+	for len(values) < count {
+		header, err := binary.ReadUvarint(r)
+		require.NoError(t, err)
+
+		if header&1 == 0 {
+			value := make([]byte, (bitWidth+7)/8)
+			_, err = io.ReadFull(r, value)
+			require.NoError(t, err)
+			var v uint32
+			for i, b := range value {
+				v |= uint32(b) << (8 * i)
+			}
+			for range header >> 1 {
+				values = append(values, v)
+			}
+			continue
+		}
+
+		packed := make([]byte, int(header>>1)*bitWidth) //nolint:gosec
+		_, err = io.ReadFull(r, packed)
+		require.NoError(t, err)
+		for i := range int(header>>1) * 8 { //nolint:gosec
+			var v uint32
+			for bit := range bitWidth {
+				pos := i*bitWidth + bit
+				v |= uint32(packed[pos/8]>>(pos%8)&1) << bit
+			}
+			values = append(values, v)
+		}
+	}
+	// the last bit-packed run is padded to a multiple of 8 values
+	return values[:count]
+}
+
+func decompress(t *testing.T, codec int64, data []byte) []byte {
+	t.Helper()
+	switch codec {
+	case codecUncompressed:
+		return data
+	case codecSnappy:
+		decoded, err := snappy.Decode(nil, data)
+		require.NoError(t, err)
+		return decoded
+	case codecGzip:
+		gr, err := gzip.NewReader(bytes.NewReader(data))
+		require.NoError(t, err)
+		decoded, err := io.ReadAll(gr)
+		require.NoError(t, err)
+		return decoded
+	case codecZstd:
+		decoder, err := zstd.NewReader(nil)
+		require.NoError(t, err)
+		defer decoder.Close()
+		decoded, err := decoder.DecodeAll(data, nil)
+		require.NoError(t, err)
+		return decoded
+	default:
+		t.Fatalf("unexpected codec %d", codec)
+		return nil
+	}
+}
+
+// decodePlain decodes count values of the physical type with the plain encoding.
+func decodePlain(t *testing.T, r *bytes.Reader, physicalType int64, count int) []any {
+	t.Helper()
+	values := make([]any, 0, count)
+	for range count {
+		switch physicalType {
+		case typeInt64:
+			var v int64
+			require.NoError(t, binary.Read(r, binary.LittleEndian, &v))
+			values = append(values, v)
+		case typeDouble:
+			var v uint64
+			require.NoError(t, binary.Read(r, binary.LittleEndian, &v))
+			values = append(values, math.Float64frombits(v))
+		case typeByteArray:
+			var length uint32
+			require.NoError(t, binary.Read(r, binary.LittleEndian, &length))
+			v := make([]byte, length)
+			_, err := io.ReadFull(r, v)
+			require.NoError(t, err)
+			values = append(values, string(v))
+		default:
+			t.Fatalf("unexpected physical type %d", physicalType)
+		}
+	}
+	return values
+}
+
+// page is a decoded page of a column chunk.
+type page struct {
+	header decodedStruct
+	data   []byte // decompressed
+	size   int    // of the header and the compressed data in the file
+}
+
+func readPage(t *testing.T, file []byte, offset, codec int64) page {
+	t.Helper()
+	header, headerSize := decodeThrift(t, file[offset:])
+	compressedSize := header.int(t, 3)
+	start := offset + int64(headerSize)
+	data := decompress(t, codec, file[start:start+compressedSize])
+	require.Len(t, data, int(header.int(t, 2)), "the uncompressed size of the page")
+	return page{header: header, data: data, size: headerSize + int(compressedSize)}
+}
+
+// parquetFile is a decoded Parquet file, with its metadata and its values by column,
+// where nil is an undefined value of an optional column.
+type parquetFile struct {
+	meta    decodedStruct
+	schema  []decodedStruct
+	columns map[string][]any
+}
+
+func readParquetFile(t *testing.T, file []byte) parquetFile {
+	t.Helper()
+	require.Greater(t, len(file), 12)
+	require.Equal(t, magic, string(file[:4]))
+	require.Equal(t, magic, string(file[len(file)-4:]))
+
+	metaSize := int(binary.LittleEndian.Uint32(file[len(file)-8:]))
+	metaStart := len(file) - 8 - metaSize
+	require.GreaterOrEqual(t, metaStart, 4)
+	meta, size := decodeThrift(t, file[metaStart:len(file)-8])
+	require.Equal(t, metaSize, size, "the whole metadata is decoded")
+
+	pf := parquetFile{meta: meta, columns: make(map[string][]any)}
+	schema := meta.structs(t, 2)
+	require.Equal(t, int64(len(schema)-1), schema[0].int(t, 5), "the number of children of the root")
+	pf.schema = schema[1:]
+
+	expectedOffset := int64(4)
+	for _, rg := range meta.structs(t, 4) {
+		require.Equal(t, expectedOffset, rg.int(t, 5), "the offset of the row group")
+		var totalSize, totalCompressed int64
+		chunks := rg.structs(t, 1)
+		require.Len(t, chunks, len(pf.schema))
+		for i, chunk := range chunks {
+			chunkMeta := chunk.strct(t, 3)
+			require.Equal(t, expectedOffset, chunk.int(t, 2), "the offset of the column chunk")
+			values, chunkSize := readColumnChunk(t, file, pf.schema[i], chunkMeta, rg.int(t, 3))
+			pf.columns[pf.schema[i].string(t, 4)] = append(pf.columns[pf.schema[i].string(t, 4)], values...)
+
+			expectedOffset += chunkSize
+			totalSize += chunkMeta.int(t, 6)
+			totalCompressed += chunkMeta.int(t, 7)
+		}
+		require.Equal(t, totalSize, rg.int(t, 2))
+		require.Equal(t, totalCompressed, rg.int(t, 6))
+	}
+	require.Equal(t, int64(metaStart), expectedOffset, "the metadata is right after the row groups")
+
+	return pf
+}
+
+// readColumnChunk decodes the values of a column chunk, and returns them with its size in the file.
+func readColumnChunk(
+	t *testing.T, file []byte, schema, chunkMeta decodedStruct, numRows int64,
+) ([]any, int64) {
+	t.Helper()
+	require.Equal(t, schema.string(t, 4), string(chunkMeta.list(t, 3)[0].([]byte))) //nolint:forcetypeassert
+	physicalType := chunkMeta.int(t, 1)
+	require.Equal(t, schema.int(t, 1), physicalType)
+	require.Equal(t, numRows, chunkMeta.int(t, 5))
+	codec := chunkMeta.int(t, 4)
+
+	offset := chunkMeta.int(t, 9)
+	var dictionary []any
+	var chunkSize, uncompressedSize int64
+	if dictOffset, ok := chunkMeta[11]; ok {
+		require.Less(t, dictOffset, offset)
+		dictPage := readPage(t, file, dictOffset.(int64), codec) //nolint:forcetypeassert
+		require.Equal(t, int64(pageTypeDictionary), dictPage.header.int(t, 1))
+		dictHeader := dictPage.header.strct(t, 7)
+		require.Equal(t, int64(encodingPlain), dictHeader.int(t, 2))
+		dictionary = decodePlain(t, bytes.NewReader(dictPage.data), physicalType, int(dictHeader.int(t, 1)))
+		require.Equal(t, offset, dictOffset.(int64)+int64(dictPage.size), //nolint:forcetypeassert
+			"the data page is right after the dictionary page")
+		chunkSize += int64(dictPage.size)
+		uncompressedSize += int64(dictPage.size - int(dictPage.header.int(t, 3)) + len(dictPage.data))
+	}
+
+	dataPage := readPage(t, file, offset, codec)
+	require.Equal(t, int64(pageTypeData), dataPage.header.int(t, 1))
+	dataHeader := dataPage.header.strct(t, 5)
+	require.Equal(t, numRows, dataHeader.int(t, 1))
+	chunkSize += int64(dataPage.size)
+	uncompressedSize += int64(dataPage.size - int(dataPage.header.int(t, 3)) + len(dataPage.data))
+	require.Equal(t, chunkSize, chunkMeta.int(t, 7), "the compressed size of the column chunk")
+	require.Equal(t, uncompressedSize, chunkMeta.int(t, 6), "the uncompressed size of the column chunk")
+
+	r := bytes.NewReader(dataPage.data)
+	defined := make([]uint32, numRows)
+	nonNull := int(numRows)
+	if schema.int(t, 3) == repetitionOptional {
+		var levelsSize uint32
+		require.NoError(t, binary.Read(r, binary.LittleEndian, &levelsSize))
+		levels := make([]byte, levelsSize)
+		_, err := io.ReadFull(r, levels)
+		require.NoError(t, err)
+		defined = decodeHybrid(t, bytes.NewReader(levels), 1, int(numRows))
+		nonNull = 0
+		for _, d := range defined {
+			nonNull += int(d)
+		}
+	} else {
+		for i := range defined {
+			defined[i] = 1
+		}
+	}
+
+	var values []any
+	switch encoding := dataHeader.int(t, 2); encoding {
+	case encodingPlain:
+		values = decodePlain(t, r, physicalType, nonNull)
+	case encodingRLEDictionary:
+		require.NotNil(t, dictionary, "the dictionary encoded values need a dictionary page")
+		bitWidth, err := r.ReadByte()
+		require.NoError(t, err)
+		for _, index := range decodeHybrid(t, r, int(bitWidth), nonNull) {
+			require.Less(t, int(index), len(dictionary))
+			values = append(values, dictionary[index])
+		}
+	default:
+		t.Fatalf("unexpected encoding %d", encoding)
+	}
+	require.Zero(t, r.Len(), "the whole page is decoded")
+
+	result := make([]any, 0, numRows)
+	for _, d := range defined {
+		if d == 0 {
+			result = append(result, nil)
+			continue
+		}
+		result = append(result, values[0])
+		values = values[1:]
+	}
+	return result, chunkSize
+}
+
+// testRows returns the columns, the number of rows of each row group and their buffers,
+// and the expected values of the columns of the test files. The values are generated in
+// the same way by testdata/verify_golden.py.
+func testRows() ([]columnDef, []int, [][]columnBuffer, map[string][]any) {
+	columns := []columnDef{
+		{name: "timestamp", kind: timestampColumn},
+		{name: "value", kind: doubleColumn},
+		{name: "repeated", kind: stringColumn},
+		{name: "distinct", kind: stringColumn},
+		{name: "optional", kind: stringColumn, optional: true},
+		{name: "empty", kind: stringColumn, optional: true},
+	}
+	rowGroups := []int{20, 3}
+
+	expected := make(map[string][]any)
+	var buffers [][]columnBuffer
+	row := 0
+	for _, numRows := range rowGroups {
+		group := make([]columnBuffer, len(columns))
+		for range numRows {
+			timestamp := int64(1562324643000000 + row)
+			value := float64(row) / 4
+			repeated := []string{"http_reqs", "http_req_duration", "vus"}[row%3]
+			distinct := "value-" + string(rune('a'+row))
+			optional, defined := "", row%4 != 1
+			if defined {
+				// long enough runs for the run-length encoding
+				optional = []string{"ok", "failed"}[row/10%2]
+			}
+
+			group[0].int64s = append(group[0].int64s, timestamp)
+			group[1].doubles = append(group[1].doubles, value)
+			group[2].strings = append(group[2].strings, repeated)
+			group[3].strings = append(group[3].strings, distinct)
+			group[4].addOptional(optional, defined)
+			group[5].addOptional("", false)
+
+			expected["timestamp"] = append(expected["timestamp"], timestamp)
+			expected["value"] = append(expected["value"], value)
+			expected["repeated"] = append(expected["repeated"], repeated)
+			expected["distinct"] = append(expected["distinct"], distinct)
+			if defined {
+				expected["optional"] = append(expected["optional"], optional)
+			} else {
+				expected["optional"] = append(expected["optional"], nil)
+			}
+			expected["empty"] = append(expected["empty"], nil)
+			row++
+		}
+		buffers = append(buffers, group)
+	}
+	return columns, rowGroups, buffers, expected
+}
+
+func writeTestFile(t *testing.T, compression string) (*fileWriter, []byte) {
+	t.Helper()
+
+	columns, rowGroups, buffers, _ := testRows()
+	buf := &bytes.Buffer{}
+	fw, err := newFileWriter(buf, columns, compression, "k6 test")
+	require.NoError(t, err)
+	for i, numRows := range rowGroups {
+		require.NoError(t, fw.writeRowGroup(buffers[i], numRows))
+	}
+	require.NoError(t, fw.close())
+	return fw, buf.Bytes()
+}
+
+func TestWriterRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	columns, rowGroups, _, expected := testRows()
+	row := len(expected["timestamp"])
+
+	for _, compression := range []string{"none", "snappy", "gzip", "zstd"} {
+		t.Run(compression, func(t *testing.T) {
+			t.Parallel()
+
+			fw, file := writeTestFile(t, compression)
+
+			pf := readParquetFile(t, file)
+			assert.Equal(t, int64(fileMetaDataVersion), pf.meta.int(t, 1))
+			assert.Equal(t, int64(row), pf.meta.int(t, 3))
+			assert.Equal(t, "k6 test", pf.meta.string(t, 6))
+			assert.Len(t, pf.meta.structs(t, 4), len(rowGroups))
+
+			require.Len(t, pf.schema, len(columns))
+			for i, def := range columns {
+				assert.Equal(t, def.name, pf.schema[i].string(t, 4))
+				assert.Equal(t, int64(def.kind.physicalType()), pf.schema[i].int(t, 1))
+				if def.optional {
+					assert.Equal(t, int64(repetitionOptional), pf.schema[i].int(t, 3))
+				} else {
+					assert.Equal(t, int64(repetitionRequired), pf.schema[i].int(t, 3))
+				}
+			}
+
+			for i, chunk := range pf.meta.structs(t, 4)[0].structs(t, 1) {
+				chunkMeta := chunk.strct(t, 3)
+				assert.Equal(t, int64(fw.codec), chunkMeta.int(t, 4))
+				// only the columns with few distinct values are dictionary encoded
+				_, hasDictionary := chunkMeta[11]
+				assert.Equal(t, columns[i].name == "repeated" || columns[i].name == "optional",
+					hasDictionary, columns[i].name)
+			}
+
+			assert.Equal(t, expected, pf.columns)
+		})
+	}
+}
+
+// TestWriterGolden checks that the written files don't change from the ones in testdata,
+// which are checked with other Parquet readers by testdata/verify_golden.py. After a
+// change of the format, the files can be written again with the -update flag, and then
+// they have to be checked again.
+func TestWriterGolden(t *testing.T) {
+	t.Parallel()
+
+	for _, compression := range []string{"none", "snappy", "gzip", "zstd"} {
+		t.Run(compression, func(t *testing.T) {
+			t.Parallel()
+
+			_, file := writeTestFile(t, compression)
+			golden := filepath.Join("testdata", "golden-"+compression+".parquet")
+			if *update {
+				require.NoError(t, os.WriteFile(golden, file, 0o644)) //nolint:forbidigo
+			}
+			expected, err := os.ReadFile(golden) //nolint:forbidigo
+			require.NoError(t, err)
+			assert.True(t, bytes.Equal(expected, file), "the written file differs from %s", golden)
+		})
+	}
+}
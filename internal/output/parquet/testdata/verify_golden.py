@@ -0,0 +1,98 @@
+#!/usr/bin/env python3
+"""Checks the golden-*.parquet files with pyarrow and DuckDB.
+
+The files are written by TestWriterGolden in reader_test.go, and they have to
+be checked again every time they are written with the -update flag:
+
+    pip install pyarrow duckdb
+    python3 internal/output/parquet/testdata/verify_golden.py
+
+The expected rows are generated here in the same way as by testRows in
+reader_test.go, so the files are checked against readers that don't share any
+code with the writer.
+"""
+
+import datetime
+import os
+import sys
+
+import duckdb
+import pyarrow as pa
+import pyarrow.parquet as pq
+
+COMPRESSIONS = {
+    "none": "UNCOMPRESSED",
+    "snappy": "SNAPPY",
+    "gzip": "GZIP",
+    "zstd": "ZSTD",
+}
+ROW_GROUPS = [20, 3]
+
+SCHEMA = pa.schema(
+    [
+        pa.field("timestamp", pa.timestamp("us", tz="UTC"), nullable=False),
+        pa.field("value", pa.float64(), nullable=False),
+        pa.field("repeated", pa.string(), nullable=False),
+        pa.field("distinct", pa.string(), nullable=False),
+        pa.field("optional", pa.string(), nullable=True),
+        pa.field("empty", pa.string(), nullable=True),
+    ]
+)
+
+
+def expected_rows():
+    rows = []
+    for row in range(sum(ROW_GROUPS)):
+        micros = 1562324643000000 + row
+        rows.append(
+            {
+                "timestamp": datetime.datetime.fromtimestamp(micros // 10**6, datetime.timezone.utc)
+                + datetime.timedelta(microseconds=micros % 10**6),
+                "value": row / 4,
+                "repeated": ["http_reqs", "http_req_duration", "vus"][row % 3],
+                "distinct": "value-" + chr(ord("a") + row),
+                "optional": ["ok", "failed"][row // 10 % 2] if row % 4 != 1 else None,
+                "empty": None,
+            }
+        )
+    return rows
+
+
+def check_pyarrow(path, codec, expected):
+    pf = pq.ParquetFile(path)
+    assert pf.schema_arrow.equals(SCHEMA), pf.schema_arrow
+    assert pf.metadata.created_by == "k6 test", pf.metadata.created_by
+    assert [pf.metadata.row_group(i).num_rows for i in range(pf.num_row_groups)] == ROW_GROUPS
+
+    for i in range(pf.num_row_groups):
+        rg = pf.metadata.row_group(i)
+        for j in range(rg.num_columns):
+            assert rg.column(j).compression == codec, (i, j, rg.column(j).compression)
+
+    assert pf.read().to_pylist() == expected
+
+
+def check_duckdb(path, expected):
+    con = duckdb.connect()
+    cursor = con.execute("SELECT * FROM read_parquet(?)", [path])
+    names = [d[0] for d in cursor.description]
+    rows = [dict(zip(names, r)) for r in cursor.fetchall()]
+    assert len(rows) == len(expected), len(rows)
+    for got, want in zip(rows, expected):
+        # DuckDB returns the timestamps in the session time zone.
+        got["timestamp"] = got["timestamp"].astimezone(datetime.timezone.utc)
+        assert got == want, (got, want)
+
+
+def main():
+    testdata = os.path.dirname(os.path.abspath(__file__))
+    expected = expected_rows()
+    for compression, codec in COMPRESSIONS.items():
+        path = os.path.join(testdata, "golden-" + compression + ".parquet")
+        check_pyarrow(path, codec, expected)
+        check_duckdb(path, expected)
+        print("ok", os.path.basename(path))
+
+
+if __name__ == "__main__":
+    sys.exit(main())
@@ -0,0 +1,108 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// The types of the Thrift compact protocol, which is used for the metadata of the Parquet files.
+const (
+	thriftBoolTrue  = 1
+	thriftBoolFalse = 2
+	thriftI32       = 5
+	thriftI64       = 6
+	thriftBinary    = 8
+	thriftList      = 9
+	thriftStruct    = 12
+)
+
+// thriftWriter serializes Thrift structs with the compact protocol. Only the
+// subset of it that's needed for the Parquet metadata is implemented.
+type thriftWriter struct {
+	buf bytes.Buffer
+
+	// lastFieldIDs is the stack of the IDs of the last written fields of the
+	// nested structs, as the field IDs are written as deltas.
+	lastFieldIDs []int16
+}
+
+func (t *thriftWriter) bytes() []byte {
+	return t.buf.Bytes()
+}
+
+func (t *thriftWriter) varint(v uint64) {
+	t.buf.Write(binary.AppendUvarint(nil, v))
+}
+
+func (t *thriftWriter) zigzag(v int64) {
+	t.varint(uint64((v << 1) ^ (v >> 63))) //nolint:gosec
+}
+
+func (t *thriftWriter) fieldHeader(id int16, fieldType byte) {
+	last := &t.lastFieldIDs[len(t.lastFieldIDs)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | fieldType)
+	} else {
+		t.buf.WriteByte(fieldType)
+		t.zigzag(int64(id))
+	}
+	*last = id
+}
+
+// beginStruct starts a struct, either a top-level one or an element of a list.
+func (t *thriftWriter) beginStruct() {
+	t.lastFieldIDs = append(t.lastFieldIDs, 0)
+}
+
+func (t *thriftWriter) endStruct() {
+	t.buf.WriteByte(0) // stop field
+	t.lastFieldIDs = t.lastFieldIDs[:len(t.lastFieldIDs)-1]
+}
+
+func (t *thriftWriter) structField(id int16) {
+	t.fieldHeader(id, thriftStruct)
+	t.beginStruct()
+}
+
+func (t *thriftWriter) boolField(id int16, v bool) {
+	if v {
+		t.fieldHeader(id, thriftBoolTrue)
+	} else {
+		t.fieldHeader(id, thriftBoolFalse)
+	}
+}
+
+func (t *thriftWriter) i32Field(id int16, v int32) {
+	t.fieldHeader(id, thriftI32)
+	t.zigzag(int64(v))
+}
+
+func (t *thriftWriter) i64Field(id int16, v int64) {
+	t.fieldHeader(id, thriftI64)
+	t.zigzag(v)
+}
+
+func (t *thriftWriter) stringField(id int16, v string) {
+	t.fieldHeader(id, thriftBinary)
+	t.string(v)
+}
+
+// listField starts a list field, whose elements have to be written right after it.
+func (t *thriftWriter) listField(id int16, elemType byte, size int) {
+	t.fieldHeader(id, thriftList)
+	if size < 15 {
+		t.buf.WriteByte(byte(size)<<4 | elemType)
+	} else {
+		t.buf.WriteByte(0xf0 | elemType)
+		t.varint(uint64(size)) //nolint:gosec
+	}
+}
+
+func (t *thriftWriter) i32(v int32) {
+	t.zigzag(int64(v))
+}
+
+func (t *thriftWriter) string(v string) {
+	t.varint(uint64(len(v)))
+	t.buf.WriteString(v)
+}
@@ -0,0 +1,407 @@
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/bits"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// magic is written at the start and at the end of the Parquet files.
+const magic = "PAR1"
+
+// The values of the enums and the IDs of the union fields of the Parquet format that are used.
+const (
+	typeInt64     = 2
+	typeDouble    = 5
+	typeByteArray = 6
+
+	repetitionRequired = 0
+	repetitionOptional = 1
+
+	convertedTypeUTF8            = 0
+	convertedTypeTimestampMicros = 10
+
+	logicalTypeString    = 1
+	logicalTypeTimestamp = 8
+	timeUnitMicros       = 2
+
+	encodingPlain         = 0
+	encodingRLE           = 3
+	encodingRLEDictionary = 8
+
+	pageTypeData       = 0
+	pageTypeDictionary = 2
+
+	codecUncompressed = 0
+	codecSnappy       = 1
+	codecGzip         = 2
+	codecZstd         = 6
+)
+
+// fileMetaDataVersion is the version of the Parquet format of the written files.
+const fileMetaDataVersion = 1
+
+// maxDictionaryFraction limits the size of the dictionaries of the string columns to a
+// fraction of the number of values, e.g. up to half of them, otherwise the values are
+// encoded as they are, as the dictionary would be larger than what it saves.
+const maxDictionaryFraction = 2
+
+// columnKind is the kind of the values of a column, which defines its Parquet types.
+type columnKind int
+
+const (
+	timestampColumn columnKind = iota // microseconds since the UNIX epoch, in UTC
+	doubleColumn
+	stringColumn
+)
+
+func (k columnKind) physicalType() int32 {
+	switch k {
+	case timestampColumn:
+		return typeInt64
+	case doubleColumn:
+		return typeDouble
+	default:
+		return typeByteArray
+	}
+}
+
+// columnDef is the definition of a column of the written Parquet file.
+type columnDef struct {
+	name     string
+	kind     columnKind
+	optional bool
+}
+
+// columnBuffer holds the values of a column for the rows of a row group. Only one
+// of the slices of values is used, depending on the kind of the column, and only
+// the values of the rows where the column is defined are kept.
+type columnBuffer struct {
+	int64s  []int64
+	doubles []float64
+	strings []string
+	defined []bool // only used for the optional columns
+}
+
+// addOptional adds the value of an optional string column, if it's defined.
+func (c *columnBuffer) addOptional(value string, defined bool) {
+	c.defined = append(c.defined, defined)
+	if defined {
+		c.strings = append(c.strings, value)
+	}
+}
+
+func (c *columnBuffer) reset() {
+	c.int64s = c.int64s[:0]
+	c.doubles = c.doubles[:0]
+	c.strings = c.strings[:0]
+	c.defined = c.defined[:0]
+}
+
+// fileWriter writes a Parquet file, one row group at a time. The metadata of the file,
+// including its schema, is written at the end, when the writer is closed.
+type fileWriter struct {
+	w         io.Writer
+	offset    int64
+	columns   []columnDef
+	codec     int32
+	compress  func([]byte) ([]byte, error)
+	createdBy string
+
+	numRows   int64
+	rowGroups []rowGroupMeta
+}
+
+type rowGroupMeta struct {
+	columns         []columnChunkMeta
+	numRows         int64
+	offset          int64
+	totalSize       int64
+	totalCompressed int64
+}
+
+type columnChunkMeta struct {
+	encodings        []int32
+	numValues        int64
+	uncompressedSize int64
+	compressedSize   int64
+	dataPageOffset   int64
+	dictPageOffset   int64 // -1 if the column chunk doesn't have a dictionary page
+}
+
+func newFileWriter(w io.Writer, columns []columnDef, compression, createdBy string) (*fileWriter, error) {
+	fw := &fileWriter{
+		w:         w,
+		columns:   columns,
+		createdBy: createdBy,
+	}
+
+	switch compression {
+	case "none":
+		fw.codec = codecUncompressed
+		fw.compress = func(data []byte) ([]byte, error) { return data, nil }
+	case "snappy":
+		fw.codec = codecSnappy
+		fw.compress = func(data []byte) ([]byte, error) { return snappy.Encode(nil, data), nil }
+	case "gzip":
+		fw.codec = codecGzip
+		fw.compress = func(data []byte) ([]byte, error) {
+			buf := &bytes.Buffer{}
+			gw := gzip.NewWriter(buf)
+			if _, err := gw.Write(data); err != nil {
+				return nil, err
+			}
+			if err := gw.Close(); err != nil {
+				return nil, err
+			}
+			return buf.Bytes(), nil
+		}
+	case "zstd":
+		encoder, err := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		fw.codec = codecZstd
+		fw.compress = func(data []byte) ([]byte, error) { return encoder.EncodeAll(data, nil), nil }
+	default:
+		return nil, fmt.Errorf("unsupported compression %q, it should be one of none, snappy, gzip or zstd", compression)
+	}
+
+	if err := fw.write([]byte(magic)); err != nil {
+		return nil, err
+	}
+	return fw, nil
+}
+
+func (fw *fileWriter) write(data []byte) error {
+	n, err := fw.w.Write(data)
+	fw.offset += int64(n)
+	return err
+}
+
+// writeRowGroup writes a row group with the buffered values of the columns, which
+// have to be in the same order as the definitions of the columns.
+func (fw *fileWriter) writeRowGroup(buffers []columnBuffer, numRows int) error {
+	rg := rowGroupMeta{numRows: int64(numRows), offset: fw.offset}
+	for i, def := range fw.columns {
+		chunk, err := fw.writeColumnChunk(def, &buffers[i], numRows)
+		if err != nil {
+			return fmt.Errorf("couldn't write the column %q: %w", def.name, err)
+		}
+		rg.columns = append(rg.columns, chunk)
+		rg.totalSize += chunk.uncompressedSize
+		rg.totalCompressed += chunk.compressedSize
+	}
+	fw.rowGroups = append(fw.rowGroups, rg)
+	fw.numRows += int64(numRows)
+	return nil
+}
+
+func (fw *fileWriter) writeColumnChunk(def columnDef, buf *columnBuffer, numRows int) (columnChunkMeta, error) {
+	chunk := columnChunkMeta{
+		encodings:      []int32{encodingPlain, encodingRLE},
+		numValues:      int64(numRows),
+		dictPageOffset: -1,
+	}
+
+	page := &bytes.Buffer{}
+	if def.optional {
+		encodeDefinitionLevels(page, buf.defined)
+	}
+
+	encoding := int32(encodingPlain)
+	switch def.kind {
+	case timestampColumn:
+		encodePlainInt64(page, buf.int64s)
+	case doubleColumn:
+		encodePlainDouble(page, buf.doubles)
+	case stringColumn:
+		dictionary, indices := buildDictionary(buf.strings)
+		if len(dictionary) == 0 || len(dictionary) > max(1, len(buf.strings)/maxDictionaryFraction) {
+			encodePlainString(page, buf.strings)
+			break
+		}
+
+		dictPage := &bytes.Buffer{}
+		encodePlainString(dictPage, dictionary)
+		chunk.dictPageOffset = fw.offset
+		if err := fw.writePage(&chunk, pageTypeDictionary, len(dictionary), encodingPlain, dictPage.Bytes()); err != nil {
+			return chunk, err
+		}
+
+		bitWidth := max(1, bits.Len32(uint32(len(dictionary)-1))) //nolint:gosec
+		page.WriteByte(byte(bitWidth))
+		page.Write(appendHybrid(nil, indices, bitWidth))
+		encoding = encodingRLEDictionary
+		chunk.encodings = append(chunk.encodings, encodingRLEDictionary)
+	}
+
+	chunk.dataPageOffset = fw.offset
+	err := fw.writePage(&chunk, pageTypeData, numRows, encoding, page.Bytes())
+	return chunk, err
+}
+
+// buildDictionary returns the distinct values, in the order of their first
+// appearance, and the indices of the values in the dictionary.
+func buildDictionary(values []string) ([]string, []uint32) {
+	var dictionary []string
+	positions := make(map[string]uint32)
+	indices := make([]uint32, len(values))
+	for i, v := range values {
+		pos, ok := positions[v]
+		if !ok {
+			pos = uint32(len(dictionary)) //nolint:gosec
+			positions[v] = pos
+			dictionary = append(dictionary, v)
+		}
+		indices[i] = pos
+	}
+	return dictionary, indices
+}
+
+func (fw *fileWriter) writePage(chunk *columnChunkMeta, pageType, numValues int, encoding int32, data []byte) error {
+	compressed, err := fw.compress(data)
+	if err != nil {
+		return err
+	}
+
+	header := &thriftWriter{}
+	header.beginStruct()
+	header.i32Field(1, int32(pageType))        //nolint:gosec
+	header.i32Field(2, int32(len(data)))       //nolint:gosec
+	header.i32Field(3, int32(len(compressed))) //nolint:gosec
+	if pageType == pageTypeDictionary {
+		header.structField(7)
+		header.i32Field(1, int32(numValues)) //nolint:gosec
+		header.i32Field(2, encoding)
+		header.endStruct()
+	} else {
+		header.structField(5)
+		header.i32Field(1, int32(numValues)) //nolint:gosec
+		header.i32Field(2, encoding)
+		header.i32Field(3, encodingRLE) // definition levels
+		header.i32Field(4, encodingRLE) // repetition levels
+		header.endStruct()
+	}
+	header.endStruct()
+
+	if err := fw.write(header.bytes()); err != nil {
+		return err
+	}
+	if err := fw.write(compressed); err != nil {
+		return err
+	}
+
+	chunk.uncompressedSize += int64(len(header.bytes()) + len(data))
+	chunk.compressedSize += int64(len(header.bytes()) + len(compressed))
+	return nil
+}
+
+// close writes the metadata of the file, after all of its row groups.
+func (fw *fileWriter) close() error {
+	meta := &thriftWriter{}
+	meta.beginStruct()
+	meta.i32Field(1, fileMetaDataVersion)
+
+	meta.listField(2, thriftStruct, len(fw.columns)+1)
+	meta.beginStruct()
+	meta.stringField(4, "schema")
+	meta.i32Field(5, int32(len(fw.columns))) //nolint:gosec
+	meta.endStruct()
+	for _, def := range fw.columns {
+		writeSchemaElement(meta, def)
+	}
+
+	meta.i64Field(3, fw.numRows)
+
+	meta.listField(4, thriftStruct, len(fw.rowGroups))
+	for _, rg := range fw.rowGroups {
+		meta.beginStruct()
+		meta.listField(1, thriftStruct, len(rg.columns))
+		for j, chunk := range rg.columns {
+			writeColumnChunkMeta(meta, fw.columns[j], chunk, fw.codec)
+		}
+		meta.i64Field(2, rg.totalSize)
+		meta.i64Field(3, rg.numRows)
+		meta.i64Field(5, rg.offset)
+		meta.i64Field(6, rg.totalCompressed)
+		meta.endStruct()
+	}
+
+	meta.stringField(6, fw.createdBy)
+	meta.endStruct()
+
+	if err := fw.write(meta.bytes()); err != nil {
+		return err
+	}
+	if err := fw.write(binary.LittleEndian.AppendUint32(nil, uint32(len(meta.bytes())))); err != nil { //nolint:gosec
+		return err
+	}
+	return fw.write([]byte(magic))
+}
+
+func writeSchemaElement(meta *thriftWriter, def columnDef) {
+	meta.beginStruct()
+	meta.i32Field(1, def.kind.physicalType())
+	if def.optional {
+		meta.i32Field(3, repetitionOptional)
+	} else {
+		meta.i32Field(3, repetitionRequired)
+	}
+	meta.stringField(4, def.name)
+	switch def.kind {
+	case timestampColumn:
+		meta.i32Field(6, convertedTypeTimestampMicros)
+		meta.structField(10)
+		meta.structField(logicalTypeTimestamp)
+		meta.boolField(1, true) // adjusted to UTC
+		meta.structField(2)
+		meta.structField(timeUnitMicros)
+		meta.endStruct()
+		meta.endStruct()
+		meta.endStruct()
+		meta.endStruct()
+	case stringColumn:
+		meta.i32Field(6, convertedTypeUTF8)
+		meta.structField(10)
+		meta.structField(logicalTypeString)
+		meta.endStruct()
+		meta.endStruct()
+	case doubleColumn:
+	}
+	meta.endStruct()
+}
+
+func writeColumnChunkMeta(meta *thriftWriter, def columnDef, chunk columnChunkMeta, codec int32) {
+	chunkOffset := chunk.dataPageOffset
+	if chunk.dictPageOffset >= 0 {
+		chunkOffset = chunk.dictPageOffset
+	}
+
+	meta.beginStruct()
+	meta.i64Field(2, chunkOffset)
+	meta.structField(3)
+	meta.i32Field(1, def.kind.physicalType())
+	meta.listField(2, thriftI32, len(chunk.encodings))
+	for _, encoding := range chunk.encodings {
+		meta.i32(encoding)
+	}
+	meta.listField(3, thriftBinary, 1)
+	meta.string(def.name)
+	meta.i32Field(4, codec)
+	meta.i64Field(5, chunk.numValues)
+	meta.i64Field(6, chunk.uncompressedSize)
+	meta.i64Field(7, chunk.compressedSize)
+	meta.i64Field(9, chunk.dataPageOffset)
+	if chunk.dictPageOffset >= 0 {
+		meta.i64Field(11, chunk.dictPageOffset)
+	}
+	meta.endStruct()
+	meta.endStruct()
+}
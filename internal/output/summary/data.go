@@ -42,6 +42,7 @@ func (d *dataModel) storeThresholdsFor(m *metrics.Metric) {
 		d.thresholds[m.Name] = metricThresholds{
 			aggregatedMetric: relayAggregatedMetricFrom(m),
 			tt:               m.Thresholds.Thresholds,
+			windows:          &m.Thresholds,
 		}
 	}
 }
@@ -49,6 +50,10 @@ func (d *dataModel) storeThresholdsFor(m *metrics.Metric) {
 type metricThresholds struct {
 	aggregatedMetric
 	tt []*metrics.Threshold
+
+	// windows holds the metric's thresholds, to get the values
+	// of the time-windowed ones, once they are evaluated for the last time.
+	windows *metrics.Thresholds
 }
 
 type thresholds map[string]metricThresholds
@@ -137,7 +142,7 @@ type aggregatedMetric struct {
 func newAggregatedMetric(m *metrics.Metric) aggregatedMetric {
 	return aggregatedMetric{
 		MetricInfo: summaryMetricInfoFrom(m),
-		Sink:       metrics.NewSink(m.Type),
+		Sink:       m.NewSink(),
 	}
 }
 
@@ -268,6 +273,14 @@ func summaryThresholds(
 			}
 		}
 
+		// The time-windowed thresholds are evaluated over their last window,
+		// so we add those values too, e.g. "p(95) over 1m".
+		if mThresholds.windows != nil {
+			for key, value := range mThresholds.windows.WindowValues() {
+				mt.Metric.Values[key] = value
+			}
+		}
+
 		rts[mName] = mt
 	}
 
@@ -335,6 +348,7 @@ func isExecutionMetric(metricName string) bool {
 		metrics.IterationsName,
 		metrics.IterationDurationName,
 		metrics.DroppedIterationsName,
+		metrics.SustainableRateName,
 	)
 }
 
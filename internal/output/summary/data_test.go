@@ -2,8 +2,12 @@ package summary
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/metrics"
 )
 
 func Test_extractPercentileThresholdSource(t *testing.T) {
@@ -44,3 +48,27 @@ func Test_extractPercentileThresholdSource(t *testing.T) {
 		})
 	}
 }
+
+func Test_summaryThresholdsWindowValues(t *testing.T) {
+	t.Parallel()
+
+	m, err := metrics.NewRegistry().NewMetric("http_req_duration", metrics.Trend, metrics.Time)
+	require.NoError(t, err)
+	m.Thresholds = metrics.NewThresholds([]string{"p(95) over 1m<300"})
+	require.NoError(t, m.Thresholds.Parse())
+	m.Sink.Add(metrics.Sample{Value: 500})
+
+	window := m.NewSink()
+	window.Add(metrics.Sample{Value: 200})
+	_, err = m.Thresholds.RunWithWindows(m.Sink, map[time.Duration]metrics.Sink{time.Minute: window}, time.Minute)
+	require.NoError(t, err)
+
+	d := newDataModel()
+	d.storeThresholdsFor(m)
+	rts := summaryThresholds(d.thresholds, time.Minute, []string{"avg", "p(95)"})
+
+	values := rts["http_req_duration"].Metric.Values
+	assert.Equal(t, float64(500), values["p(95)"])
+	assert.Equal(t, float64(200), values["p(95) over 1m"])
+	assert.True(t, rts["http_req_duration"].Thresholds[0].Ok)
+}
@@ -8,6 +8,8 @@ import (
 	"go.k6.io/k6/internal/lib/consts"
 	"go.k6.io/k6/internal/lib/summary"
 	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/executor"
+	"go.k6.io/k6/lib/types"
 	"go.k6.io/k6/metrics"
 	"go.k6.io/k6/output"
 
@@ -27,6 +29,10 @@ type Output struct {
 
 	dataModel   dataModel
 	summaryMode summary.Mode
+
+	// timeline is only kept when it's enabled or the HTML report is requested,
+	// as it's used for its charts.
+	timeline *timeline
 }
 
 // New returns a new summary output.
@@ -36,13 +42,53 @@ func New(params output.Params) (*Output, error) {
 		return nil, err
 	}
 
-	return &Output{
+	o := &Output{
 		logger: params.Logger.WithFields(logrus.Fields{
 			"output": "summary",
 		}),
 		dataModel:   newDataModel(),
 		summaryMode: sm,
-	}, nil
+	}
+	switch {
+	case params.RuntimeOptions.SummaryTimeline.Valid:
+		o.timeline = newTimeline(
+			params.RuntimeOptions.SummaryTimeline.TimeDuration(),
+			timelineStagesFor(params.ScriptOptions.Scenarios),
+		)
+	case params.RuntimeOptions.SummaryHTML.String != "":
+		o.timeline = newTimeline(
+			timelineBucketSizeFor(params.ExecutionPlan),
+			timelineStagesFor(params.ScriptOptions.Scenarios),
+		)
+	}
+
+	return o, nil
+}
+
+// SetExecutionState sets the execution state of the test run, which is used to know
+// when the scenarios were started, so the timeline can be split by their stages.
+func (o *Output) SetExecutionState(es *lib.ExecutionState) {
+	if o.timeline != nil {
+		o.timeline.scenarioStartTime = es.GetScenarioStartTime
+	}
+}
+
+// SetExecutors sets the executors of the scenarios, whose current configs are used
+// to split the timeline by the stages, since they can be changed while the test runs.
+func (o *Output) SetExecutors(executors []lib.Executor) {
+	if o.timeline == nil {
+		return
+	}
+	configs := make(map[string]func() lib.ExecutorConfig, len(executors))
+	for _, e := range executors {
+		configs[e.GetConfig().GetName()] = e.GetConfig
+	}
+	o.timeline.scenarioStages = func(name string) []executor.Stage {
+		if getConfig, ok := configs[name]; ok {
+			return stagesOf(getConfig())
+		}
+		return nil
+	}
 }
 
 // OutputName is the name of the output.
@@ -85,6 +131,10 @@ func (o *Output) flushSample(sample metrics.Sample) {
 	// First, the sample data is stored into the metrics stored at the k6 metrics registry level.
 	o.storeSample(sample)
 
+	if o.timeline != nil {
+		o.timeline.addSample(sample)
+	}
+
 	skipGroupSamples := o.summaryMode == summary.ModeCompact || o.summaryMode == summary.ModeLegacy
 	if skipGroupSamples {
 		return
@@ -123,7 +173,7 @@ func (o *Output) Summary(
 	options lib.Options,
 ) *summary.Summary {
 	s := summary.New()
-	s.TestRunDuration = testRunDuration
+	s.TestRunDuration = types.Duration(testRunDuration)
 
 	summaryTrendStats := options.SummaryTrendStats
 
@@ -131,6 +181,10 @@ func (o *Output) Summary(
 	// all metrics, even those that have no samples, so that we can render them in the summary.
 	o.processObservedMetrics(observedMetrics)
 
+	if o.timeline != nil {
+		s.Timeline = o.timeline.summaryTimeline(summaryTrendStats)
+	}
+
 	// Populate the thresholds.
 	s.Thresholds = summaryThresholds(o.dataModel.thresholds, testRunDuration, summaryTrendStats)
 
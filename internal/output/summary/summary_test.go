@@ -12,6 +12,8 @@ import (
 	"go.k6.io/k6/internal/lib/summary"
 	"go.k6.io/k6/internal/lib/testutils"
 	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/executor"
+	"go.k6.io/k6/lib/types"
 	"go.k6.io/k6/metrics"
 	"go.k6.io/k6/output"
 )
@@ -193,7 +195,7 @@ func TestOutput_Summary(t *testing.T) {
 	}, authHTTPReqsSummaryMetric.Values)
 
 	// Other asserts
-	assert.Equal(t, testRunDuration, s.TestRunDuration)
+	assert.Equal(t, types.Duration(testRunDuration), s.TestRunDuration)
 }
 
 func TestOutput_AddMetricSamples(t *testing.T) {
@@ -343,3 +345,193 @@ func TestOutput_AddMetricSamples(t *testing.T) {
 		assert.Equal(t, []string{"something", "auth"}, o.dataModel.groupsOrder)
 	})
 }
+
+func TestOutput_SummaryTimeline(t *testing.T) {
+	t.Parallel()
+
+	t.Run("only collected when enabled or for the HTML report", func(t *testing.T) {
+		t.Parallel()
+
+		o, err := New(output.Params{Logger: testutils.NewLogger(t)})
+		require.NoError(t, err)
+		assert.Nil(t, o.timeline)
+	})
+
+	o, err := New(output.Params{
+		Logger:         testutils.NewLogger(t),
+		RuntimeOptions: lib.RuntimeOptions{SummaryHTML: null.StringFrom("report.html")},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, o.timeline)
+	assert.Equal(t, time.Second, o.timeline.bucketSize)
+
+	registry := metrics.NewRegistry()
+	iterations, err := registry.NewMetric(metrics.IterationsName, metrics.Counter)
+	require.NoError(t, err)
+	custom, err := registry.NewMetric("custom", metrics.Counter)
+	require.NoError(t, err)
+
+	start := time.Unix(100, 0)
+	for _, offset := range []time.Duration{0, 200 * time.Millisecond, 2500 * time.Millisecond} {
+		for _, metric := range []*metrics.Metric{iterations, custom} {
+			o.flushSample(metrics.Sample{
+				TimeSeries: metrics.TimeSeries{Metric: metric, Tags: registry.RootTagSet()},
+				Time:       start.Add(offset),
+				Value:      1,
+			})
+		}
+	}
+
+	s := o.Summary(3*time.Second, map[string]*metrics.Metric{}, lib.Options{
+		SummaryTrendStats: []string{"avg"},
+	})
+	require.NotNil(t, s.Timeline)
+	assert.Equal(t, &summary.Timeline{
+		BucketSize: types.Duration(time.Second),
+		Buckets: []summary.TimelineBucket{
+			{Time: start, Metrics: map[string]map[string]float64{"iterations": {"count": 2, "rate": 2}}},
+			{Time: start.Add(2 * time.Second), Metrics: map[string]map[string]float64{"iterations": {"count": 1, "rate": 1}}},
+		},
+	}, s.Timeline)
+}
+
+func TestOutput_SummaryTimelineScenarios(t *testing.T) {
+	t.Parallel()
+
+	o, err := New(output.Params{
+		Logger: testutils.NewLogger(t),
+		RuntimeOptions: lib.RuntimeOptions{
+			SummaryTimeline: types.NullDurationFrom(10 * time.Second),
+		},
+		ScriptOptions: lib.Options{
+			Scenarios: lib.ScenarioConfigs{
+				"ramp": executor.RampingVUsConfig{
+					Stages: []executor.Stage{
+						{Duration: types.NullDurationFrom(5 * time.Second), Target: null.IntFrom(10)},
+						{Duration: types.NullDurationFrom(20 * time.Second), Target: null.IntFrom(0)},
+					},
+				},
+				"constant": executor.NewConstantVUsConfig("constant"),
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, o.timeline)
+	assert.Equal(t, 10*time.Second, o.timeline.bucketSize)
+
+	start := time.Unix(100, 0)
+	o.timeline.scenarioStartTime = func(name string) (time.Time, bool) {
+		return start, name == "ramp"
+	}
+
+	registry := metrics.NewRegistry()
+	iterations, err := registry.NewMetric(metrics.IterationsName, metrics.Counter)
+	require.NoError(t, err)
+
+	add := func(scenario string, offset time.Duration) {
+		o.flushSample(metrics.Sample{
+			TimeSeries: metrics.TimeSeries{
+				Metric: iterations,
+				Tags:   registry.RootTagSet().With("scenario", scenario),
+			},
+			Time:  start.Add(offset),
+			Value: 1,
+		})
+	}
+	add("ramp", time.Second)
+	add("ramp", 7*time.Second)
+	add("ramp", 12*time.Second)
+	add("ramp", 30*time.Second) // after the end of the stages
+	add("constant", 2*time.Second)
+
+	s := o.Summary(30*time.Second, map[string]*metrics.Metric{}, lib.Options{
+		SummaryTrendStats: []string{"avg"},
+	})
+	require.NotNil(t, s.Timeline)
+
+	iterationsValues := func(count, rate float64) map[string]map[string]float64 {
+		return map[string]map[string]float64{"iterations": {"count": count, "rate": rate}}
+	}
+	assert.Equal(t, []summary.TimelineBucket{
+		{Time: start, Metrics: iterationsValues(3, 0.3)},
+		{Time: start.Add(10 * time.Second), Metrics: iterationsValues(1, 0.1)},
+		{Time: start.Add(30 * time.Second), Metrics: iterationsValues(1, 0.1)},
+	}, s.Timeline.Buckets)
+	assert.Equal(t, map[string]summary.ScenarioTimeline{
+		"ramp": {
+			Buckets: []summary.TimelineBucket{
+				{Time: start, Metrics: iterationsValues(2, 0.2)},
+				{Time: start.Add(10 * time.Second), Metrics: iterationsValues(1, 0.1)},
+				{Time: start.Add(30 * time.Second), Metrics: iterationsValues(1, 0.1)},
+			},
+			Stages: []summary.TimelineStage{
+				{Start: start, Duration: types.Duration(5 * time.Second), Target: 10, Metrics: iterationsValues(1, 0.2)},
+				{
+					Start: start.Add(5 * time.Second), Duration: types.Duration(20 * time.Second), Target: 0,
+					Metrics: iterationsValues(2, 0.1),
+				},
+			},
+		},
+		"constant": {
+			Buckets: []summary.TimelineBucket{
+				{Time: start, Metrics: iterationsValues(1, 0.1)},
+			},
+		},
+	}, s.Timeline.Scenarios)
+}
+
+func TestTimelineChangedStages(t *testing.T) {
+	t.Parallel()
+
+	stage := func(d time.Duration, target int64) executor.Stage {
+		return executor.Stage{Duration: types.NullDurationFrom(d), Target: null.IntFrom(target)}
+	}
+	stages := []executor.Stage{stage(10*time.Second, 10), stage(10*time.Second, 0)}
+	tl := newTimeline(10*time.Second, map[string][]executor.Stage{"ramp": stages})
+	start := time.Unix(100, 0)
+	tl.scenarioStartTime = func(string) (time.Time, bool) { return start, true }
+	tl.scenarioStages = func(string) []executor.Stage { return stages }
+
+	registry := metrics.NewRegistry()
+	iterations, err := registry.NewMetric(metrics.IterationsName, metrics.Counter)
+	require.NoError(t, err)
+	add := func(offset time.Duration) {
+		tl.addSample(metrics.Sample{
+			TimeSeries: metrics.TimeSeries{
+				Metric: iterations,
+				Tags:   registry.RootTagSet().With("scenario", "ramp"),
+			},
+			Time:  start.Add(offset),
+			Value: 1,
+		})
+	}
+	add(2 * time.Second)
+
+	// at 5s, the rest of the first stage and the second one are skipped and a
+	// new stage is added, like the live updates through the REST API do
+	stages = []executor.Stage{stage(5*time.Second, 5), stage(30*time.Second, 20)}
+	add(4 * time.Second)  // a sample from before the change, flushed after it
+	add(12 * time.Second) // would be in the first stage, without the change
+	add(30 * time.Second)
+
+	result := tl.summaryTimeline([]string{"avg"})
+	ramp := result.Scenarios["ramp"]
+	require.Len(t, ramp.Stages, 2)
+	assert.Equal(t, start, ramp.Stages[0].Start)
+	assert.Equal(t, types.Duration(5*time.Second), ramp.Stages[0].Duration)
+	assert.Equal(t, 2.0, ramp.Stages[0].Metrics["iterations"]["count"]) //nolint:testifylint
+	assert.Equal(t, start.Add(5*time.Second), ramp.Stages[1].Start)
+	assert.Equal(t, int64(20), ramp.Stages[1].Target)
+	assert.Equal(t, 2.0, ramp.Stages[1].Metrics["iterations"]["count"]) //nolint:testifylint
+}
+
+func TestTimelineBucketSizeFor(t *testing.T) {
+	t.Parallel()
+
+	plan := func(d time.Duration) []lib.ExecutionStep {
+		return []lib.ExecutionStep{{TimeOffset: 0, PlannedVUs: 1}, {TimeOffset: d, PlannedVUs: 0}}
+	}
+	assert.Equal(t, time.Second, timelineBucketSizeFor(nil))
+	assert.Equal(t, time.Second, timelineBucketSizeFor(plan(30*time.Second)))
+	assert.Equal(t, 10*time.Second, timelineBucketSizeFor(plan(10*time.Minute)))
+}
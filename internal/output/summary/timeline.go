@@ -0,0 +1,224 @@
+package summary
+
+import (
+	"sort"
+	"time"
+
+	"go.k6.io/k6/internal/lib/summary"
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/executor"
+	"go.k6.io/k6/lib/types"
+	"go.k6.io/k6/metrics"
+)
+
+// timelineBuckets is the number of time buckets that the test run is split into,
+// based on its maximum duration, when the size of the buckets isn't configured.
+const timelineBuckets = 60
+
+// timelineBucketSizeFor returns the size of the time buckets of the timeline
+// for the given execution plan, which is at least a second.
+func timelineBucketSizeFor(executionPlan []lib.ExecutionStep) time.Duration {
+	maxDuration, _ := lib.GetEndOffset(executionPlan)
+	return max((maxDuration / timelineBuckets).Round(time.Second), time.Second)
+}
+
+// timelineStagesFor returns the configured stages of the scenarios that have them.
+func timelineStagesFor(scenarios lib.ScenarioConfigs) map[string][]executor.Stage {
+	stages := make(map[string][]executor.Stage)
+	for name, config := range scenarios {
+		if scenarioStages := stagesOf(config); len(scenarioStages) > 0 {
+			stages[name] = scenarioStages
+		}
+	}
+	return stages
+}
+
+// stagesOf returns the stages of the scenario's config, if it has them.
+func stagesOf(config lib.ExecutorConfig) []executor.Stage {
+	switch config := config.(type) {
+	case executor.RampingVUsConfig:
+		return config.Stages
+	case *executor.RampingArrivalRateConfig:
+		return config.Stages
+	default:
+		return nil
+	}
+}
+
+// isTimelineMetric reports whether the values of the metric are aggregated for each
+// time bucket, which is only done for the main metrics, to keep the memory usage low.
+func isTimelineMetric(metricName string) bool {
+	switch metricName {
+	case metrics.HTTPReqDurationName, metrics.HTTPReqsName, metrics.HTTPReqFailedName,
+		metrics.IterationsName, metrics.IterationDurationName, metrics.VUsName, metrics.ChecksName:
+		return true
+	default:
+		return false
+	}
+}
+
+// timeline keeps the aggregated values of the main metrics for each time bucket of the
+// test run, both for all the samples and for the samples of each scenario, and for
+// each stage of the scenarios with stages.
+type timeline struct {
+	bucketSize time.Duration
+	buckets    map[int64]aggregatedMetricData
+	scenarios  map[string]*scenarioTimeline
+
+	// scenarioStages returns the current stages of a scenario, which can be changed
+	// while it runs, e.g. through the REST API, and scenarioStartTime returns when a
+	// scenario was started, so the samples can be matched with the stage they belong
+	// to. Without it, the stages aren't aggregated.
+	//
+	// The live changes of the stages keep the ones up to the time of the change, so
+	// the samples are matched with the same stages before and after it.
+	scenarioStages    func(name string) []executor.Stage
+	scenarioStartTime func(name string) (time.Time, bool)
+}
+
+type scenarioTimeline struct {
+	buckets map[int64]aggregatedMetricData
+	stages  []aggregatedMetricData
+}
+
+func newTimeline(bucketSize time.Duration, stages map[string][]executor.Stage) *timeline {
+	return &timeline{
+		bucketSize:     bucketSize,
+		buckets:        make(map[int64]aggregatedMetricData),
+		scenarios:      make(map[string]*scenarioTimeline),
+		scenarioStages: func(name string) []executor.Stage { return stages[name] },
+	}
+}
+
+func (t *timeline) addSample(sample metrics.Sample) {
+	if !isTimelineMetric(sample.Metric.Name) {
+		return
+	}
+
+	bucket := sample.Time.Truncate(t.bucketSize).UnixNano()
+	addBucketSample(t.buckets, bucket, sample)
+
+	scenarioName, hasScenario := sample.Tags.Get("scenario")
+	if !hasScenario {
+		return
+	}
+	scenario, exists := t.scenarios[scenarioName]
+	if !exists {
+		scenario = &scenarioTimeline{buckets: make(map[int64]aggregatedMetricData)}
+		t.scenarios[scenarioName] = scenario
+	}
+	addBucketSample(scenario.buckets, bucket, sample)
+
+	if stage, ok := t.stageOf(scenarioName, sample.Time); ok {
+		// the stages can be added while the scenario runs
+		if stage >= len(scenario.stages) {
+			scenario.stages = append(scenario.stages, make([]aggregatedMetricData, stage+1-len(scenario.stages))...)
+		}
+		if scenario.stages[stage] == nil {
+			scenario.stages[stage] = make(aggregatedMetricData)
+		}
+		scenario.stages[stage].addSample(sample)
+	}
+}
+
+func addBucketSample(buckets map[int64]aggregatedMetricData, bucket int64, sample metrics.Sample) {
+	if _, exists := buckets[bucket]; !exists {
+		buckets[bucket] = make(aggregatedMetricData)
+	}
+	buckets[bucket].addSample(sample)
+}
+
+// stageOf returns the index of the stage of the scenario that was running at the
+// given time, if any. The samples after the end of the last stage, e.g. during a
+// graceful ramp-down, don't belong to any stage.
+func (t *timeline) stageOf(scenarioName string, at time.Time) (int, bool) {
+	stages := t.scenarioStages(scenarioName)
+	if len(stages) == 0 || t.scenarioStartTime == nil {
+		return 0, false
+	}
+	start, started := t.scenarioStartTime(scenarioName)
+	if !started || at.Before(start) {
+		return 0, false
+	}
+
+	offset := at.Sub(start)
+	for i, stage := range stages {
+		offset -= stage.Duration.TimeDuration()
+		if offset < 0 {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// summaryTimeline returns the values of the metrics in each bucket, in chronological order.
+func (t *timeline) summaryTimeline(summaryTrendStats []string) *summary.Timeline {
+	getMetricValues := metricValueGetter(summaryTrendStats)
+
+	result := &summary.Timeline{
+		BucketSize: types.Duration(t.bucketSize),
+		Buckets:    t.summaryBuckets(t.buckets, getMetricValues),
+	}
+
+	for name, scenario := range t.scenarios {
+		if result.Scenarios == nil {
+			result.Scenarios = make(map[string]summary.ScenarioTimeline, len(t.scenarios))
+		}
+		scenarioResult := summary.ScenarioTimeline{
+			Buckets: t.summaryBuckets(scenario.buckets, getMetricValues),
+		}
+		// The stages are only aggregated once the scenario was started
+		if scenario.stages != nil {
+			start, _ := t.scenarioStartTime(name)
+			for i, stage := range t.scenarioStages(name) {
+				duration := stage.Duration.TimeDuration()
+				var data aggregatedMetricData
+				if i < len(scenario.stages) {
+					data = scenario.stages[i]
+				}
+				scenarioResult.Stages = append(scenarioResult.Stages, summary.TimelineStage{
+					Start:    start,
+					Duration: types.Duration(duration),
+					Target:   stage.Target.Int64,
+					Metrics:  summaryMetricValues(data, duration, getMetricValues),
+				})
+				start = start.Add(duration)
+			}
+		}
+		result.Scenarios[name] = scenarioResult
+	}
+
+	return result
+}
+
+func (t *timeline) summaryBuckets(
+	buckets map[int64]aggregatedMetricData,
+	getMetricValues func(metrics.Sink, time.Duration) map[string]float64,
+) []summary.TimelineBucket {
+	starts := make([]int64, 0, len(buckets))
+	for start := range buckets {
+		starts = append(starts, start)
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i] < starts[j] })
+
+	result := make([]summary.TimelineBucket, 0, len(starts))
+	for _, start := range starts {
+		result = append(result, summary.TimelineBucket{
+			Time:    time.Unix(0, start),
+			Metrics: summaryMetricValues(buckets[start], t.bucketSize, getMetricValues),
+		})
+	}
+	return result
+}
+
+func summaryMetricValues(
+	data aggregatedMetricData,
+	duration time.Duration,
+	getMetricValues func(metrics.Sink, time.Duration) map[string]float64,
+) map[string]map[string]float64 {
+	result := make(map[string]map[string]float64, len(data))
+	for name, metric := range data {
+		result[name] = getMetricValues(metric.Sink, duration)
+	}
+	return result
+}
@@ -0,0 +1,68 @@
+// Package env implements secret source that reads the secrets from the environment variables,
+// optionally only from the ones with a given prefix
+package env
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.k6.io/k6/secretsource"
+)
+
+func init() {
+	secretsource.RegisterExtension("env", func(params secretsource.Params) (secretsource.Source, error) {
+		ess := &envSecretSource{}
+		if err := ess.parseArg(params.ConfigArgument); err != nil {
+			return nil, err
+		}
+
+		ess.internal = make(map[string]string)
+		for k, v := range params.Environment {
+			if name, ok := strings.CutPrefix(k, ess.prefix); ok && name != "" {
+				ess.internal[name] = v
+			}
+		}
+		return ess, nil
+	})
+}
+
+func (ess *envSecretSource) parseArg(config string) error {
+	if config == "" {
+		return nil
+	}
+	for _, kv := range strings.Split(config, ",") {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok {
+			ess.prefix = kv
+			continue
+		}
+		switch k {
+		case "prefix":
+			ess.prefix = v
+		default:
+			return fmt.Errorf("unknown configuration key for env secret source %q", k)
+		}
+	}
+	return nil
+}
+
+type envSecretSource struct {
+	internal map[string]string
+	prefix   string
+}
+
+func (ess *envSecretSource) Description() string {
+	if ess.prefix == "" {
+		return "env source from all environment variables"
+	}
+	return fmt.Sprintf("env source from environment variables with prefix %s", ess.prefix)
+}
+
+func (ess *envSecretSource) Get(key string) (string, error) {
+	v, ok := ess.internal[key]
+	if !ok {
+		return "", errors.New("no value")
+	}
+	return v, nil
+}
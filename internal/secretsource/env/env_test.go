@@ -0,0 +1,77 @@
+package env
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.k6.io/k6/ext"
+	"go.k6.io/k6/secretsource"
+)
+
+func TestParseArg(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		input          string
+		expectedPrefix string
+		expectedError  string
+	}{
+		"empty": {
+			input: "",
+		},
+		"simple": {
+			input:          "K6_SECRET_",
+			expectedPrefix: "K6_SECRET_",
+		},
+		"prefix": {
+			input:          "prefix=K6_SECRET_",
+			expectedPrefix: "K6_SECRET_",
+		},
+		"unknownfiled": {
+			input:         "prefix=K6_SECRET_,random=bad",
+			expectedError: "unknown configuration key for env secret source \"random\"",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ess := &envSecretSource{}
+			err := ess.parseArg(testCase.input)
+			if testCase.expectedError != "" {
+				require.ErrorContains(t, err, testCase.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, testCase.expectedPrefix, ess.prefix)
+		})
+	}
+}
+
+func TestGet(t *testing.T) {
+	t.Parallel()
+
+	constructor := ext.Get(ext.SecretSourceExtension)["env"].Module.(secretsource.Constructor) //nolint:forcetypeassert
+	env := map[string]string{
+		"K6_SECRET_TOKEN": "s3cr3t",
+		"K6_SECRET_":      "empty name",
+		"HOME":            "/home/k6",
+	}
+
+	source, err := constructor(secretsource.Params{ConfigArgument: "prefix=K6_SECRET_", Environment: env})
+	require.NoError(t, err)
+
+	v, err := source.Get("TOKEN")
+	require.NoError(t, err)
+	require.Equal(t, "s3cr3t", v)
+
+	_, err = source.Get("HOME")
+	require.ErrorContains(t, err, "no value")
+	_, err = source.Get("")
+	require.ErrorContains(t, err, "no value")
+
+	source, err = constructor(secretsource.Params{Environment: env})
+	require.NoError(t, err)
+	v, err = source.Get("HOME")
+	require.NoError(t, err)
+	require.Equal(t, "/home/k6", v)
+}
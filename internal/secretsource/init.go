@@ -2,6 +2,8 @@
 package secretsource
 
 import (
-	_ "go.k6.io/k6/internal/secretsource/file" // import them for init
-	_ "go.k6.io/k6/internal/secretsource/mock" // import them for init
+	_ "go.k6.io/k6/internal/secretsource/env"   // import them for init
+	_ "go.k6.io/k6/internal/secretsource/file"  // import them for init
+	_ "go.k6.io/k6/internal/secretsource/mock"  // import them for init
+	_ "go.k6.io/k6/internal/secretsource/vault" // import them for init
 )
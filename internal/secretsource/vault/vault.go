@@ -0,0 +1,372 @@
+// Package vault implements secret source that reads the secrets from the KV version 2 secrets engine
+// of HashiCorp Vault, or a server compatible with its HTTP API, authenticating with a token or AppRole
+package vault
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go.k6.io/k6/secretsource"
+)
+
+const (
+	defaultMount        = "secret"
+	defaultAppRoleMount = "approle"
+	defaultTTL          = 5 * time.Minute
+	defaultTimeout      = 10 * time.Second
+
+	authToken   = "token"
+	authAppRole = "approle"
+)
+
+func init() {
+	secretsource.RegisterExtension("vault", newVaultSecretSourceFromParams)
+}
+
+func newVaultSecretSourceFromParams(params secretsource.Params) (secretsource.Source, error) {
+	vss := &vaultSecretSource{
+		address:      params.Environment["VAULT_ADDR"],
+		namespace:    params.Environment["VAULT_NAMESPACE"],
+		mount:        defaultMount,
+		auth:         authToken,
+		appRoleMount: defaultAppRoleMount,
+		ttl:          defaultTTL,
+		timeout:      defaultTimeout,
+		now:          time.Now,
+		cache:        make(map[string]cachedSecret),
+		pending:      make(map[string]*pendingSecret),
+	}
+	if err := vss.parseArg(params.ConfigArgument); err != nil {
+		return nil, err
+	}
+	if vss.address == "" {
+		return nil, errors.New("the address of the vault secret source is required, " +
+			"set it with the address configuration key or the VAULT_ADDR environment variable")
+	}
+	vss.address = strings.TrimSuffix(vss.address, "/")
+
+	// the credentials are only read from the environment, as the command line arguments aren't secret
+	switch vss.auth {
+	case authToken:
+		vss.token = params.Environment["VAULT_TOKEN"]
+		if vss.token == "" {
+			return nil, errors.New("the VAULT_TOKEN environment variable is required for the token authentication " +
+				"of the vault secret source")
+		}
+	case authAppRole:
+		vss.roleID = params.Environment["VAULT_ROLE_ID"]
+		vss.secretID = params.Environment["VAULT_SECRET_ID"]
+		if vss.roleID == "" {
+			return nil, errors.New("the VAULT_ROLE_ID environment variable is required for the AppRole authentication " +
+				"of the vault secret source")
+		}
+	default:
+		return nil, fmt.Errorf("unknown authentication method for vault secret source %q, "+
+			"the supported ones are token and approle", vss.auth)
+	}
+
+	vss.client = &http.Client{Timeout: vss.timeout}
+	return vss, nil
+}
+
+func (vss *vaultSecretSource) parseArg(config string) error {
+	if config == "" {
+		return nil
+	}
+	for _, kv := range strings.Split(config, ",") {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok {
+			return fmt.Errorf("parsing %q, needs =", kv)
+		}
+		var err error
+		switch k {
+		case "address":
+			vss.address = v
+		case "mount":
+			vss.mount = strings.Trim(v, "/")
+		case "path":
+			vss.path = strings.Trim(v, "/")
+		case "namespace":
+			vss.namespace = v
+		case "auth":
+			vss.auth = v
+		case "approleMount":
+			vss.appRoleMount = strings.Trim(v, "/")
+		case "ttl":
+			vss.ttl, err = time.ParseDuration(v)
+		case "timeout":
+			vss.timeout, err = time.ParseDuration(v)
+		default:
+			return fmt.Errorf("unknown configuration key for vault secret source %q", k)
+		}
+		if err != nil {
+			return fmt.Errorf("parsing the %s configuration key for vault secret source: %w", k, err)
+		}
+	}
+	return nil
+}
+
+type cachedSecret struct {
+	data    map[string]any
+	expires time.Time
+}
+
+// pendingSecret is a read of a secret from vault that is in progress, which the
+// other gets of the same secret wait for, instead of sending their own requests.
+type pendingSecret struct {
+	done chan struct{}
+	data map[string]any
+	err  error
+}
+
+type vaultSecretSource struct {
+	address      string
+	mount        string
+	path         string
+	namespace    string
+	auth         string
+	appRoleMount string
+	roleID       string
+	secretID     string
+	ttl          time.Duration
+	timeout      time.Duration
+
+	client *http.Client
+	now    func() time.Time
+
+	// mx guards the cache and the pending reads, it isn't held during the
+	// requests to vault, so the cached secrets can be read meanwhile
+	mx sync.Mutex
+	// cache has the data of the secrets by their path, multiple keys can
+	// be the fields of the same secret
+	cache map[string]cachedSecret
+	// pending has the reads in progress by the path of their secret, so that
+	// only one request for the same secret is sent, when many VUs get it at once
+	pending map[string]*pendingSecret
+
+	// tokenMx guards the token, it is held during the AppRole logins so
+	// that only one of them is done at a time
+	tokenMx sync.Mutex
+	// token is the static token, or the token of the last AppRole login
+	token        string
+	tokenExpires time.Time
+}
+
+func (vss *vaultSecretSource) Description() string {
+	return fmt.Sprintf("vault source from %s/v1/%s", vss.address, vss.mount)
+}
+
+// Uncached implements the secretsource.UncachedSource interface, since the secrets
+// are cached here only for the TTL, so the rotated ones are read again.
+func (vss *vaultSecretSource) Uncached() bool {
+	return true
+}
+
+// Get returns the field of the secret for a key in the `<secret path>/<field>` format,
+// the secret path is relative to the path configuration key.
+func (vss *vaultSecretSource) Get(key string) (string, error) {
+	secretPath, field, err := vss.splitKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := vss.getSecret(secretPath)
+	if err != nil {
+		return "", err
+	}
+
+	value, ok := data[field]
+	if !ok {
+		return "", fmt.Errorf("secret %q has no field %q", secretPath, field)
+	}
+	if s, isString := value.(string); isString {
+		return s, nil
+	}
+	// the other JSON values, like the numbers and the objects
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("encoding the field %q of secret %q: %w", field, secretPath, err)
+	}
+	return string(encoded), nil
+}
+
+// getSecret returns the data of the secret from the cache, or reads it from vault
+// when it isn't cached or its TTL passed. Only one read of the same secret is sent
+// at a time, the concurrent gets of it wait for its result.
+func (vss *vaultSecretSource) getSecret(secretPath string) (map[string]any, error) {
+	vss.mx.Lock()
+	if cached, ok := vss.cache[secretPath]; ok && vss.now().Before(cached.expires) {
+		vss.mx.Unlock()
+		return cached.data, nil
+	}
+	if pending, ok := vss.pending[secretPath]; ok {
+		vss.mx.Unlock()
+		<-pending.done
+		return pending.data, pending.err
+	}
+	pending := &pendingSecret{done: make(chan struct{})}
+	vss.pending[secretPath] = pending
+	vss.mx.Unlock()
+
+	pending.data, pending.err = vss.readSecret(secretPath)
+
+	vss.mx.Lock()
+	delete(vss.pending, secretPath)
+	if pending.err == nil && vss.ttl > 0 {
+		vss.cache[secretPath] = cachedSecret{data: pending.data, expires: vss.now().Add(vss.ttl)}
+	}
+	vss.mx.Unlock()
+	close(pending.done)
+
+	return pending.data, pending.err
+}
+
+func (vss *vaultSecretSource) splitKey(key string) (string, string, error) {
+	full := strings.Trim(vss.path+"/"+strings.Trim(key, "/"), "/")
+	i := strings.LastIndex(full, "/")
+	if i <= 0 || i == len(full)-1 {
+		return "", "", fmt.Errorf("invalid key %q for vault secret source, "+
+			"it should be in the <secret path>/<field> format, relative to the configured path", key)
+	}
+	return full[:i], full[i+1:], nil
+}
+
+func (vss *vaultSecretSource) readSecret(secretPath string) (map[string]any, error) {
+	segments := strings.Split(secretPath, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	endpoint := fmt.Sprintf("%s/v1/%s/data/%s", vss.address, vss.mount, strings.Join(segments, "/"))
+
+	var response struct {
+		Data struct {
+			Data map[string]any `json:"data"`
+		} `json:"data"`
+	}
+	status, err := vss.authenticatedRequest(http.MethodGet, endpoint, &response)
+	if status == http.StatusNotFound {
+		return nil, fmt.Errorf("secret %q not found in vault", secretPath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading secret %q from vault: %w", secretPath, err)
+	}
+	if response.Data.Data == nil {
+		// e.g. the latest version of the secret is deleted
+		return nil, fmt.Errorf("secret %q has no data in vault", secretPath)
+	}
+	return response.Data.Data, nil
+}
+
+// authenticatedRequest sends the request with the token, and retries it once with
+// a new token of a new AppRole login, when the current one isn't valid anymore.
+func (vss *vaultSecretSource) authenticatedRequest(method, endpoint string, result any) (int, error) {
+	for attempt := 0; ; attempt++ {
+		token, err := vss.ensureToken()
+		if err != nil {
+			return 0, err
+		}
+		status, err := vss.request(method, endpoint, token, nil, result)
+		if status == http.StatusForbidden && vss.auth == authAppRole && attempt == 0 {
+			vss.invalidateToken(token)
+			continue
+		}
+		return status, err
+	}
+}
+
+// invalidateToken makes the next request log in again, unless another request
+// already did it after the given token was rejected.
+func (vss *vaultSecretSource) invalidateToken(token string) {
+	vss.tokenMx.Lock()
+	defer vss.tokenMx.Unlock()
+	if vss.token == token {
+		vss.token = ""
+	}
+}
+
+// ensureToken returns the token for the requests, logging in with AppRole
+// first when there isn't a valid token.
+func (vss *vaultSecretSource) ensureToken() (string, error) {
+	vss.tokenMx.Lock()
+	defer vss.tokenMx.Unlock()
+
+	if vss.auth != authAppRole || (vss.token != "" && (vss.tokenExpires.IsZero() || vss.now().Before(vss.tokenExpires))) {
+		return vss.token, nil
+	}
+
+	body, err := json.Marshal(map[string]string{"role_id": vss.roleID, "secret_id": vss.secretID})
+	if err != nil {
+		return "", err
+	}
+	var response struct {
+		Auth struct {
+			ClientToken   string `json:"client_token"`
+			LeaseDuration int64  `json:"lease_duration"`
+		} `json:"auth"`
+	}
+	endpoint := fmt.Sprintf("%s/v1/auth/%s/login", vss.address, vss.appRoleMount)
+	if _, err := vss.request(http.MethodPost, endpoint, "", body, &response); err != nil {
+		return "", fmt.Errorf("logging in to vault with AppRole: %w", err)
+	}
+	if response.Auth.ClientToken == "" {
+		return "", errors.New("logging in to vault with AppRole: the response has no token")
+	}
+
+	vss.token = response.Auth.ClientToken
+	vss.tokenExpires = time.Time{}
+	if lease := time.Duration(response.Auth.LeaseDuration) * time.Second; lease > 0 {
+		// renew the token a bit before it expires
+		vss.tokenExpires = vss.now().Add(lease * 9 / 10)
+	}
+	return vss.token, nil
+}
+
+func (vss *vaultSecretSource) request(method, endpoint, token string, body []byte, result any) (int, error) {
+	req, err := http.NewRequest(method, endpoint, bytes.NewReader(body)) //nolint:noctx
+	if err != nil {
+		return 0, err
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if vss.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", vss.namespace)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := vss.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiError struct {
+			Errors []string `json:"errors"`
+		}
+		if json.Unmarshal(data, &apiError) == nil && len(apiError.Errors) > 0 {
+			return resp.StatusCode, fmt.Errorf("unexpected status code %d: %s",
+				resp.StatusCode, strings.Join(apiError.Errors, "; "))
+		}
+		return resp.StatusCode, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	if err := json.Unmarshal(data, result); err != nil {
+		return resp.StatusCode, fmt.Errorf("invalid response: %w", err)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,315 @@
+package vault
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.k6.io/k6/secretsource"
+)
+
+// stubVault implements the KV version 2 reads and the AppRole logins of the Vault HTTP API.
+type stubVault struct {
+	t       *testing.T
+	mx      sync.Mutex
+	tokens  map[string]bool
+	secrets map[string]map[string]any
+	reads   map[string]int
+	logins  int
+
+	// blocked has the paths which the reads of wait for their channel to be closed,
+	// after they are sent to started
+	blocked map[string]chan struct{}
+	started chan string
+}
+
+func newStubVault(t *testing.T) (*stubVault, *httptest.Server) {
+	sv := &stubVault{
+		t:      t,
+		tokens: map[string]bool{"root-token": true},
+		secrets: map[string]map[string]any{
+			"/v1/kv/data/k6/app":      {"password": "s3cr3t", "port": 5432},
+			"/v1/kv/data/k6/app/db":   {"user": "k6"},
+			"/v1/secret/data/shared":  {"key": "shared"},
+			"/v1/kv/data/k6/app/gone": nil,
+		},
+		reads:   make(map[string]int),
+		blocked: make(map[string]chan struct{}),
+		started: make(chan string, 10),
+	}
+	srv := httptest.NewServer(sv)
+	t.Cleanup(srv.Close)
+	return sv, srv
+}
+
+func (sv *stubVault) block(path string) chan struct{} {
+	sv.mx.Lock()
+	defer sv.mx.Unlock()
+	unblock := make(chan struct{})
+	sv.blocked[path] = unblock
+	return unblock
+}
+
+func (sv *stubVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	sv.mx.Lock()
+	unblock, isBlocked := sv.blocked[r.URL.Path]
+	sv.mx.Unlock()
+	if isBlocked {
+		sv.started <- r.URL.Path
+		<-unblock
+	}
+
+	sv.mx.Lock()
+	defer sv.mx.Unlock()
+
+	if r.Method == http.MethodPost && r.URL.Path == "/v1/auth/approle/login" {
+		var creds map[string]string
+		require.NoError(sv.t, json.NewDecoder(r.Body).Decode(&creds))
+		if creds["role_id"] != "role" || creds["secret_id"] != "secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"errors": ["invalid role or secret ID"]}`))
+			return
+		}
+		sv.logins++
+		token := "approle-token-" + string(rune('0'+sv.logins))
+		sv.tokens[token] = true
+		_, _ = w.Write([]byte(`{"auth": {"client_token": "` + token + `", "lease_duration": 60}}`))
+		return
+	}
+
+	if !sv.tokens[r.Header.Get("X-Vault-Token")] {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"errors": ["permission denied"]}`))
+		return
+	}
+	data, ok := sv.secrets[r.URL.Path]
+	if r.Method != http.MethodGet || !ok {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errors": []}`))
+		return
+	}
+	sv.reads[r.URL.Path]++
+	body, err := json.Marshal(map[string]any{"data": map[string]any{"data": data}})
+	require.NoError(sv.t, err)
+	_, _ = w.Write(body)
+}
+
+func TestParseArg(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		input         string
+		expected      *vaultSecretSource
+		expectedError string
+	}{
+		"empty": {
+			input:    "",
+			expected: &vaultSecretSource{mount: defaultMount, ttl: defaultTTL},
+		},
+		"all": {
+			input: "address=https://vault:8200,mount=/kv/,path=k6/app,namespace=ns,auth=approle," +
+				"approleMount=ci,ttl=1m,timeout=2s",
+			expected: &vaultSecretSource{
+				address: "https://vault:8200", mount: "kv", path: "k6/app", namespace: "ns",
+				auth: authAppRole, appRoleMount: "ci", ttl: time.Minute, timeout: 2 * time.Second,
+			},
+		},
+		"unknown": {
+			input:         "mount=kv,random=bad",
+			expectedError: "unknown configuration key for vault secret source \"random\"",
+		},
+		"no value": {
+			input:         "kv",
+			expectedError: "parsing \"kv\", needs =",
+		},
+		"invalid ttl": {
+			input:         "ttl=5",
+			expectedError: "parsing the ttl configuration key for vault secret source",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			vss := &vaultSecretSource{mount: defaultMount, ttl: defaultTTL}
+			err := vss.parseArg(testCase.input)
+			if testCase.expectedError != "" {
+				require.ErrorContains(t, err, testCase.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, testCase.expected, vss)
+		})
+	}
+}
+
+func newTestSource(t *testing.T, config string, env map[string]string) *vaultSecretSource {
+	t.Helper()
+	source, err := newVaultSecretSourceFromParams(secretsource.Params{ConfigArgument: config, Environment: env})
+	require.NoError(t, err)
+	return source.(*vaultSecretSource) //nolint:forcetypeassert
+}
+
+func TestGetWithToken(t *testing.T) {
+	t.Parallel()
+
+	sv, srv := newStubVault(t)
+	source := newTestSource(t, "mount=kv,path=k6", map[string]string{"VAULT_ADDR": srv.URL, "VAULT_TOKEN": "root-token"})
+	now := time.Now()
+	source.now = func() time.Time { return now }
+
+	v, err := source.Get("app/password")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cr3t", v)
+	v, err = source.Get("app/port")
+	require.NoError(t, err)
+	assert.Equal(t, "5432", v)
+	v, err = source.Get("app/db/user")
+	require.NoError(t, err)
+	assert.Equal(t, "k6", v)
+
+	_, err = source.Get("app/missing")
+	require.ErrorContains(t, err, `secret "k6/app" has no field "missing"`)
+	_, err = source.Get("unknown/password")
+	require.ErrorContains(t, err, `secret "k6/unknown" not found in vault`)
+	_, err = source.Get("app/gone/field")
+	require.ErrorContains(t, err, `secret "k6/app/gone" has no data in vault`)
+
+	// the fields of the same secret are read once, until the TTL passes
+	assert.Equal(t, 1, sv.reads["/v1/kv/data/k6/app"])
+	now = now.Add(defaultTTL)
+	_, err = source.Get("app/password")
+	require.NoError(t, err)
+	assert.Equal(t, 2, sv.reads["/v1/kv/data/k6/app"])
+
+	source = newTestSource(t, "", map[string]string{"VAULT_ADDR": srv.URL, "VAULT_TOKEN": "other-token"})
+	_, err = source.Get("shared/key")
+	require.ErrorContains(t, err, `reading secret "shared" from vault: unexpected status code 403: permission denied`)
+	_, err = source.Get("key")
+	require.ErrorContains(t, err, `invalid key "key" for vault secret source`)
+}
+
+func TestGetWithAppRole(t *testing.T) {
+	t.Parallel()
+
+	sv, srv := newStubVault(t)
+	source := newTestSource(t, "auth=approle,ttl=0s", map[string]string{
+		"VAULT_ADDR": srv.URL, "VAULT_ROLE_ID": "role", "VAULT_SECRET_ID": "secret",
+	})
+	now := time.Now()
+	source.now = func() time.Time { return now }
+
+	v, err := source.Get("shared/key")
+	require.NoError(t, err)
+	assert.Equal(t, "shared", v)
+	_, err = source.Get("shared/key")
+	require.NoError(t, err)
+	assert.Equal(t, 1, sv.logins)
+	assert.Equal(t, 2, sv.reads["/v1/secret/data/shared"], "the caching is disabled")
+
+	// the token is renewed before its lease expires
+	now = now.Add(55 * time.Second)
+	_, err = source.Get("shared/key")
+	require.NoError(t, err)
+	assert.Equal(t, 2, sv.logins)
+
+	// and when it's revoked
+	sv.mx.Lock()
+	sv.tokens = map[string]bool{}
+	sv.mx.Unlock()
+	_, err = source.Get("shared/key")
+	require.NoError(t, err)
+	assert.Equal(t, 3, sv.logins)
+
+	source = newTestSource(t, "auth=approle", map[string]string{
+		"VAULT_ADDR": srv.URL, "VAULT_ROLE_ID": "role", "VAULT_SECRET_ID": "wrong",
+	})
+	_, err = source.Get("shared/key")
+	require.ErrorContains(t, err,
+		"logging in to vault with AppRole: unexpected status code 400: invalid role or secret ID")
+}
+
+func TestGetConcurrently(t *testing.T) {
+	t.Parallel()
+
+	sv, srv := newStubVault(t)
+	source := newTestSource(t, "mount=kv,path=k6", map[string]string{"VAULT_ADDR": srv.URL, "VAULT_TOKEN": "root-token"})
+	_, err := source.Get("app/password")
+	require.NoError(t, err)
+
+	unblock := sv.block("/v1/kv/data/k6/app/db")
+	results := make(chan string, 2)
+	get := func() {
+		v, getErr := source.Get("app/db/user")
+		assert.NoError(t, getErr)
+		results <- v
+	}
+	go get()
+	assert.Equal(t, "/v1/kv/data/k6/app/db", <-sv.started)
+	go get()
+
+	// the cached secrets are returned while another one is read
+	v, err := source.Get("app/password")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cr3t", v)
+
+	close(unblock)
+	assert.Equal(t, "k6", <-results)
+	assert.Equal(t, "k6", <-results)
+	assert.Equal(t, 1, sv.reads["/v1/kv/data/k6/app/db"], "the concurrent gets wait for the same read")
+}
+
+func TestManagerRefreshesAfterTTL(t *testing.T) {
+	t.Parallel()
+
+	sv, srv := newStubVault(t)
+	source := newTestSource(t, "mount=kv,path=k6", map[string]string{"VAULT_ADDR": srv.URL, "VAULT_TOKEN": "root-token"})
+	now := time.Now()
+	source.now = func() time.Time { return now }
+	manager, _, err := secretsource.NewManager(map[string]secretsource.Source{"default": source})
+	require.NoError(t, err)
+
+	v, err := manager.Get(secretsource.DefaultSourceName, "app/password")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cr3t", v)
+
+	sv.mx.Lock()
+	sv.secrets["/v1/kv/data/k6/app"] = map[string]any{"password": "rotated"}
+	sv.mx.Unlock()
+	v, err = manager.Get(secretsource.DefaultSourceName, "app/password")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cr3t", v, "the TTL didn't pass yet")
+
+	now = now.Add(defaultTTL)
+	v, err = manager.Get(secretsource.DefaultSourceName, "app/password")
+	require.NoError(t, err)
+	assert.Equal(t, "rotated", v)
+	assert.Equal(t, 2, sv.reads["/v1/kv/data/k6/app"])
+	assert.Equal(t, "***SECRET_REDACTED*** ***SECRET_REDACTED***", manager.Redact("s3cr3t rotated"))
+}
+
+func TestConfigurationErrors(t *testing.T) {
+	t.Parallel()
+
+	for expectedErr, env := range map[string]map[string]string{
+		"the address of the vault secret source is required": {"VAULT_TOKEN": "token"},
+		"the VAULT_TOKEN environment variable is required":   {"VAULT_ADDR": "http://vault"},
+	} {
+		_, err := newVaultSecretSourceFromParams(secretsource.Params{Environment: env})
+		require.ErrorContains(t, err, expectedErr)
+	}
+
+	_, err := newVaultSecretSourceFromParams(secretsource.Params{
+		ConfigArgument: "auth=approle", Environment: map[string]string{"VAULT_ADDR": "http://vault"},
+	})
+	require.ErrorContains(t, err, "the VAULT_ROLE_ID environment variable is required")
+
+	_, err = newVaultSecretSourceFromParams(secretsource.Params{
+		ConfigArgument: "auth=userpass", Environment: map[string]string{"VAULT_ADDR": "http://vault"},
+	})
+	require.ErrorContains(t, err, `unknown authentication method for vault secret source "userpass"`)
+}
@@ -70,6 +70,34 @@ func TestAsyncRequest(t *testing.T) {
 		`)))
 		assert.NoError(t, err)
 	})
+	t.Run("Abort", func(t *testing.T) {
+		t.Parallel()
+		ts := newTestCase(t)
+		sr := ts.tb.Replacer.Replace
+
+		start := time.Now()
+		_, err := ts.runtime.RunOnEventLoop(wrapInAsyncLambda(sr(`
+			var controller = new AbortController();
+			var promise = http.asyncRequest("GET", "HTTPBIN_URL/delay/10", null, { signal: controller.signal, throw: false });
+			controller.abort("stop");
+			var res = await promise;
+			if (res.error_code !== 1051) { throw new Error("wrong error code: " + res.error_code); }
+			if (res.error !== "request aborted: stop") { throw new Error("wrong error: " + res.error); }
+
+			res = await http.asyncRequest("GET", "HTTPBIN_URL/delay/10", null, { signal: AbortSignal.timeout(10), throw: false });
+			if (res.error_code !== 1051 || res.error.indexOf("TimeoutError") === -1) {
+				throw new Error("wrong timeout error: " + res.error);
+			}
+
+			var err = await http.asyncRequest("GET", "HTTPBIN_URL/get", null, { signal: AbortSignal.abort() }).catch((e) => e);
+			if (String(err).indexOf("request aborted: AbortError") === -1) { throw new Error("wrong error: " + err); }
+
+			res = await http.asyncRequest("GET", "HTTPBIN_URL/get", null, { signal: AbortSignal.any([]) });
+			if (res.status !== 200) { throw new Error("wrong status: " + res.status); }
+		`)))
+		require.NoError(t, err)
+		assert.Less(t, time.Since(start), 5*time.Second)
+	})
 }
 
 func TestAsyncRequestResponseCallbackRace(t *testing.T) {
@@ -0,0 +1,329 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/grafana/sobek"
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/internal/js/modules/k6/experimental/streams"
+	"go.k6.io/k6/internal/js/tc55/abort"
+	"go.k6.io/k6/internal/js/webidl"
+	"go.k6.io/k6/js/modules"
+	"go.k6.io/k6/lib/netext/httpext"
+)
+
+// fetchAPI implements the fetch() function and the Headers, Request and Response classes
+// of the Fetch API (https://fetch.spec.whatwg.org) on top of the same machinery as the
+// k6/http requests, so they emit the same metrics and use the cookie jar of the VU.
+//
+// The requests can be tagged and configured with the members of the RequestInit
+// dictionary listed in fetchRequestParams, which have the same meaning as the
+// params of the k6/http requests. They are sent with the default client of the VU,
+// the same one as the k6/http requests, so http.setResponseCallback() applies to them.
+//
+// The bodies are streamed: fetch is resolved once the headers of the response are received,
+// and its body is received only as it's read, through a ReadableStream. The request is finished,
+// and its metrics are emitted, once the body is read completely or canceled, so the timeout of
+// the request applies to the body too. A body that isn't read until the end of the iteration is
+// discarded then, without delaying the iteration. Likewise, the chunks of a ReadableStream body
+// of a request are read only as the transport sends them. With DiscardResponseBodies, the body
+// of a response is discarded before fetch is resolved, as for the k6/http requests.
+type fetchAPI struct {
+	vu     modules.VU
+	client *Client
+
+	headersProto  *sobek.Object
+	requestProto  *sobek.Object
+	responseProto *sobek.Object
+}
+
+// SetupFetchGlobally sets the fetch function and the Headers, Request and
+// Response classes of the Fetch API to be accessible globally.
+func SetupFetchGlobally(vu modules.VU) error {
+	rt := vu.Runtime()
+	f := &fetchAPI{vu: vu}
+	f.client = vuDefaultClient(&ModuleInstance{vu: vu})
+
+	headers := rt.ToValue(f.headersConstructor).ToObject(rt)
+	f.headersProto = headers.Get("prototype").ToObject(rt)
+	f.defineHeadersPrototype()
+
+	request := rt.ToValue(f.requestConstructor).ToObject(rt)
+	f.requestProto = request.Get("prototype").ToObject(rt)
+	f.defineRequestPrototype()
+
+	response := rt.ToValue(f.responseConstructor).ToObject(rt)
+	f.responseProto = response.Get("prototype").ToObject(rt)
+	f.defineResponseClass(response)
+
+	mapping := map[string]any{
+		"fetch":    f.fetch,
+		"Headers":  headers,
+		"Request":  request,
+		"Response": response,
+	}
+	for k, v := range mapping {
+		if err := rt.Set(k, v); err != nil {
+			return fmt.Errorf("error setting up %q globally: %w", k, err)
+		}
+	}
+	return nil
+}
+
+// fetch starts a request off the event loop and returns a promise resolved with its
+// response, see https://fetch.spec.whatwg.org/#fetch-method
+func (f *fetchAPI) fetch(input, init sobek.Value) (*sobek.Promise, error) {
+	if f.vu.State() == nil {
+		return nil, ErrHTTPForbiddenInInitContext
+	}
+	rt := f.vu.Runtime()
+	promise, resolve, reject := rt.NewPromise()
+
+	var req *fetchRequest
+	if exception := rt.Try(func() { req = f.newRequest(input, init) }); exception != nil {
+		webidl.Must(rt, reject(exception.Value()))
+		return promise, nil
+	}
+
+	// The request is canceled when its signal is aborted before the response
+	op, err := abort.Follow(rt, f.vu.Context(), req.signal)
+	if err != nil {
+		webidl.Must(rt, reject(rt.NewTypeError("fetch failed: %s", err)))
+		return promise, nil
+	}
+	if op.Aborted() {
+		webidl.Must(rt, op.Done())
+		webidl.Must(rt, reject(op.Reason()))
+		return promise, nil
+	}
+
+	preq, err := f.parseRequest(req)
+	if err != nil {
+		webidl.Must(rt, op.Done())
+		webidl.Must(rt, reject(rt.NewTypeError("fetch failed: %s", err)))
+		return promise, nil
+	}
+	switch {
+	case req.body == nil:
+	case req.body.stream == nil:
+		req.body.used = true
+		preq.Body = bytes.NewBuffer(req.body.data)
+	default:
+		req.body.used = true
+		preq.BodyStream = f.streamRequestBody(req.body.stream)
+	}
+	f.send(req, preq, op, resolve, reject)
+
+	return promise, nil
+}
+
+// parseRequest converts the request to one of the k6/http machinery, applying the
+// headers and the k6-specific params in the same way as for the k6/http requests.
+func (f *fetchAPI) parseRequest(req *fetchRequest) (*httpext.ParsedHTTPRequest, error) {
+	rt := f.vu.Runtime()
+	state := f.vu.State()
+
+	headers := rt.NewObject()
+	for _, entry := range req.headers.sortedAndCombined() {
+		webidl.Must(rt, headers.Set(entry.name, entry.value))
+	}
+	params := rt.NewObject()
+	webidl.Must(rt, params.Set("headers", headers))
+	for name, value := range req.params {
+		webidl.Must(rt, params.Set(name, value))
+	}
+
+	preq, err := f.client.parseRequest(req.method, req.url, nil, params)
+	if err != nil {
+		return nil, err
+	}
+
+	preq.Throw = true
+	if req.redirect != "follow" {
+		preq.Redirects = null.IntFrom(0)
+	}
+	if state.Options.DiscardResponseBodies.Bool {
+		preq.ResponseType = httpext.ResponseTypeNone
+	} else {
+		preq.StreamResponseBody = true
+	}
+	return preq, nil
+}
+
+// send makes the request off the event loop and settles the promise of fetch with the
+// response, once its headers are received. The request is canceled when its signal is
+// aborted before the end of the body of the response.
+func (f *fetchAPI) send(
+	req *fetchRequest, preq *httpext.ParsedHTTPRequest, op *abort.Operation, resolve, reject func(any) error,
+) {
+	rt := f.vu.Runtime()
+
+	// Unlike the context of the operation, the one of the request isn't canceled at the end of
+	// the iteration once the response is received: the rest of the body is discarded instead,
+	// so the metrics of the request are still emitted.
+	ctx, cancel := context.WithCancelCause(context.WithoutCancel(op.Context()))
+	var (
+		bodyMu sync.Mutex
+		body   io.ReadCloser
+	)
+	stopFollowing := context.AfterFunc(op.Context(), func() {
+		cause := context.Cause(op.Context())
+		bodyMu.Lock()
+		b := body
+		bodyMu.Unlock()
+
+		var abortErr *abort.Error
+		if b == nil || errors.As(cause, &abortErr) {
+			cancel(cause)
+			return
+		}
+		_, _ = io.Copy(io.Discard, b)
+		_ = b.Close()
+		cancel(nil)
+	})
+	done := func() error {
+		stopFollowing()
+		cancel(nil)
+		return op.Done()
+	}
+
+	callback := f.vu.RegisterCallback()
+	go func() {
+		resp, err := httpext.MakeRequest(ctx, f.vu.State(), preq)
+		if err == nil && resp.BodyStream != nil {
+			bodyMu.Lock()
+			body = resp.BodyStream
+			bodyMu.Unlock()
+		}
+		callback(func() error {
+			var rejection sobek.Value
+			switch {
+			case op.Aborted():
+				rejection = op.Reason()
+			case err != nil:
+				rejection = fetchError(rt, err)
+			case req.redirect == "error" && isRedirectStatus(resp.Status):
+				rejection = rt.NewTypeError("fetch failed: the response to %s is a redirect", req.url.Clean())
+			}
+
+			if rejection != nil {
+				if resp != nil && resp.BodyStream != nil {
+					_ = resp.BodyStream.Close()
+				}
+				if doneErr := done(); doneErr != nil {
+					return doneErr
+				}
+				return reject(rejection)
+			}
+
+			res := responseFromHTTPext(req, resp)
+			if res.body == nil || resp.BodyStream == nil { // there's no body, or it was discarded
+				if resp.BodyStream != nil {
+					_ = resp.BodyStream.Close()
+				}
+				if doneErr := done(); doneErr != nil {
+					return doneErr
+				}
+				return resolve(f.newResponseObject(res, f.responseProto))
+			}
+
+			res.body.newStream = func() *sobek.Object {
+				return streams.NewUint8ArrayReadableStreamFromReadCloser(f.vu, resp.BodyStream, func(err error) sobek.Value {
+					aborted := op.Aborted()
+					reason := op.Reason()
+					webidl.Must(rt, done())
+					switch {
+					case err == nil:
+						return nil
+					case aborted:
+						return reason
+					default:
+						return fetchError(rt, err)
+					}
+				})
+			}
+			return resolve(f.newResponseObject(res, f.responseProto))
+		})
+	}()
+}
+
+// streamRequestBody returns a reader of the chunks of the ReadableStream, which are read from
+// it on the event loop only as the transport reads them, for sending the body of a request.
+func (f *fetchAPI) streamRequestBody(stream *sobek.Object) io.ReadCloser {
+	rt := f.vu.Runtime()
+	pr, pw := io.Pipe()
+
+	getReader, _ := sobek.AssertFunction(stream.Get("getReader"))
+	readerValue, err := getReader(stream)
+	if err != nil {
+		_ = pw.CloseWithError(err)
+		return pr
+	}
+	reader := readerValue.ToObject(rt)
+	read, _ := sobek.AssertFunction(reader.Get("read"))
+	cancelReader := func() {
+		cancelFn, _ := sobek.AssertFunction(reader.Get("cancel"))
+		_, _ = cancelFn(reader)
+	}
+
+	var readNext func()
+	readNext = func() {
+		result, err := read(reader)
+		if err != nil {
+			_ = pw.CloseWithError(err)
+			return
+		}
+		promiseThen(rt, result, func(v sobek.Value) {
+			resultObj := v.ToObject(rt)
+			if resultObj.Get("done").ToBoolean() {
+				_ = pw.Close()
+				return
+			}
+			chunk, ok := chunkBytes(rt, resultObj.Get("value"))
+			if !ok {
+				_ = pw.CloseWithError(errors.New("the chunks of the body stream must be Uint8Array objects"))
+				cancelReader()
+				return
+			}
+			callback := f.vu.RegisterCallback()
+			go func() {
+				_, writeErr := pw.Write(chunk)
+				callback(func() error {
+					if writeErr != nil { // the transport doesn't read the body anymore
+						cancelReader()
+						return nil
+					}
+					readNext()
+					return nil
+				})
+			}()
+		}, func(reason sobek.Value) {
+			_ = pw.CloseWithError(fmt.Errorf("reading the body stream failed: %s", reason))
+		})
+	}
+	readNext()
+
+	return pr
+}
+
+// fetchError returns the TypeError that a failed fetch is rejected with, with
+// the error of the request as its cause.
+func fetchError(rt *sobek.Runtime, err error) *sobek.Object {
+	typeError := rt.NewTypeError("fetch failed: %s", err)
+	webidl.Must(rt, typeError.Set("cause", rt.NewGoError(err)))
+	return typeError
+}
+
+// arrayIterator returns an iterator over the values of the array.
+func (f *fetchAPI) arrayIterator(array *sobek.Object) sobek.Value {
+	values, _ := sobek.AssertFunction(array.Get("values"))
+	iterator, err := values(array)
+	webidl.Must(f.vu.Runtime(), err)
+	return iterator
+}
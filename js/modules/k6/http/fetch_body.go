@@ -0,0 +1,269 @@
+package http
+
+import (
+	"bytes"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/internal/js/modules/k6/experimental/streams"
+	"go.k6.io/k6/internal/js/webidl"
+	"go.k6.io/k6/js/common"
+)
+
+// fetchBody is the body of a Request or a Response, see https://fetch.spec.whatwg.org/#concept-body
+//
+// The body is either buffered in data or, if it was created from a ReadableStream, only available
+// through the stream. When the body of a buffered one is read as a stream, the stream is created
+// lazily from data, so the body is read only from the stream after that. The bodies of the fetched
+// responses are received from the network by their streams, which are created lazily by newStream.
+type fetchBody struct {
+	data      []byte
+	stream    *sobek.Object
+	newStream func() *sobek.Object
+	used      bool
+}
+
+// extractBody extracts a body and its content type from a BodyInit value, see
+// https://fetch.spec.whatwg.org/#concept-bodyinit-extract
+func extractBody(rt *sobek.Runtime, init sobek.Value) (*fetchBody, string) {
+	if obj, ok := init.(*sobek.Object); ok {
+		switch v := obj.Export().(type) {
+		case sobek.ArrayBuffer:
+			return &fetchBody{data: bytes.Clone(v.Bytes())}, ""
+		case *streams.ReadableStream:
+			if v.Locked {
+				webidl.ThrowTypeError(rt, "the ReadableStream of the body is locked")
+			}
+			return &fetchBody{stream: obj}, ""
+		}
+		if data, ok := arrayBufferViewBytes(rt, obj); ok {
+			return &fetchBody{data: data}, ""
+		}
+	}
+	return &fetchBody{data: []byte(init.String())}, "text/plain;charset=UTF-8"
+}
+
+// arrayBufferViewBytes returns a copy of the bytes of a TypedArray or a DataView.
+func arrayBufferViewBytes(rt *sobek.Runtime, obj *sobek.Object) ([]byte, bool) {
+	isView, _ := sobek.AssertFunction(rt.Get("ArrayBuffer").ToObject(rt).Get("isView"))
+	if result, err := isView(sobek.Undefined(), obj); err != nil || !result.ToBoolean() {
+		return nil, false
+	}
+	buffer, ok := obj.Get("buffer").Export().(sobek.ArrayBuffer)
+	if !ok {
+		return nil, false
+	}
+	offset := obj.Get("byteOffset").ToInteger()
+	length := obj.Get("byteLength").ToInteger()
+	return bytes.Clone(buffer.Bytes()[offset : offset+length]), true
+}
+
+// isUsed returns whether the body was already consumed or its stream was read from or canceled.
+func (b *fetchBody) isUsed() bool {
+	if b == nil {
+		return false
+	}
+	if b.used {
+		return true
+	}
+	stream, ok := b.readableStream()
+	return ok && stream.Disturbed()
+}
+
+// isUnusable returns whether the body was already used or its stream is locked to a reader.
+func (b *fetchBody) isUnusable() bool {
+	if b.isUsed() {
+		return true
+	}
+	stream, ok := b.readableStream()
+	return ok && stream.Locked
+}
+
+func (b *fetchBody) readableStream() (*streams.ReadableStream, bool) {
+	if b == nil || b.stream == nil {
+		return nil, false
+	}
+	stream, ok := b.stream.Export().(*streams.ReadableStream)
+	return stream, ok
+}
+
+// bodyStream returns the body as a ReadableStream, creating it if it's needed.
+func (f *fetchAPI) bodyStream(b *fetchBody) sobek.Value {
+	if b == nil {
+		return sobek.Null()
+	}
+	switch {
+	case b.stream != nil:
+	case b.newStream != nil:
+		b.stream = b.newStream()
+		b.newStream = nil
+	default:
+		b.stream = streams.NewUint8ArrayReadableStreamFromReader(f.vu, bytes.NewReader(b.data))
+		b.data = nil
+	}
+	return b.stream
+}
+
+// consumeBody reads the whole body and converts it with convert, resolving the
+// returned promise with the result, see https://fetch.spec.whatwg.org/#concept-body-consume-body
+func (f *fetchAPI) consumeBody(b *fetchBody, convert func([]byte) (sobek.Value, error)) *sobek.Promise {
+	rt := f.vu.Runtime()
+	promise, resolve, reject := rt.NewPromise()
+
+	settle := func(data []byte) {
+		v, err := convert(data)
+		if err != nil {
+			webidl.Must(rt, reject(errorValue(rt, err)))
+			return
+		}
+		webidl.Must(rt, resolve(v))
+	}
+
+	switch {
+	case b.isUnusable():
+		webidl.Must(rt, reject(rt.NewTypeError("the body has already been used")))
+	case b == nil:
+		settle(nil)
+	case b.stream == nil && b.newStream == nil:
+		b.used = true
+		settle(b.data)
+	default:
+		stream := f.bodyStream(b).ToObject(rt)
+		b.used = true
+		f.readAllFromStream(stream, settle, func(reason sobek.Value) { webidl.Must(rt, reject(reason)) })
+	}
+
+	return promise
+}
+
+// readAllFromStream reads all the chunks of a ReadableStream asynchronously and calls
+// onDone with all their bytes or onError with the reason of the failure.
+func (f *fetchAPI) readAllFromStream(stream *sobek.Object, onDone func([]byte), onError func(sobek.Value)) {
+	rt := f.vu.Runtime()
+
+	getReader, ok := sobek.AssertFunction(stream.Get("getReader"))
+	if !ok {
+		onError(rt.NewTypeError("the body isn't a ReadableStream"))
+		return
+	}
+	readerValue, err := getReader(stream)
+	if err != nil {
+		onError(errorValue(rt, err))
+		return
+	}
+	reader := readerValue.ToObject(rt)
+	read, _ := sobek.AssertFunction(reader.Get("read"))
+
+	var (
+		data     bytes.Buffer
+		readNext func()
+	)
+	readNext = func() {
+		result, err := read(reader)
+		if err != nil {
+			onError(errorValue(rt, err))
+			return
+		}
+		promiseThen(rt, result, func(v sobek.Value) {
+			resultObj := v.ToObject(rt)
+			if resultObj.Get("done").ToBoolean() {
+				onDone(data.Bytes())
+				return
+			}
+			chunk, ok := chunkBytes(rt, resultObj.Get("value"))
+			if !ok {
+				onError(rt.NewTypeError("the chunks of the body stream must be Uint8Array objects"))
+				return
+			}
+			data.Write(chunk)
+			readNext()
+		}, onError)
+	}
+	readNext()
+}
+
+// chunkBytes returns the bytes of a chunk of a ReadableStream. Other than the Uint8Array ones
+// of the standard, the strings produced by the streams of other k6 APIs are also accepted.
+func chunkBytes(rt *sobek.Runtime, chunk sobek.Value) ([]byte, bool) {
+	if obj, ok := chunk.(*sobek.Object); ok {
+		if data, ok := arrayBufferViewBytes(rt, obj); ok {
+			return data, true
+		}
+	}
+	data, err := common.ToBytes(chunk.Export())
+	return data, err == nil
+}
+
+// promiseThen calls onFulfilled or onRejected when the promise is settled.
+func promiseThen(rt *sobek.Runtime, promise sobek.Value, onFulfilled, onRejected func(sobek.Value)) {
+	promiseObj := promise.ToObject(rt)
+	then, ok := sobek.AssertFunction(promiseObj.Get("then"))
+	if !ok {
+		panic(rt.NewTypeError("the value isn't a promise"))
+	}
+	_, err := then(promiseObj, rt.ToValue(onFulfilled), rt.ToValue(onRejected))
+	webidl.Must(rt, err)
+}
+
+func errorValue(rt *sobek.Runtime, err error) sobek.Value {
+	if exception, ok := err.(*sobek.Exception); ok { //nolint:errorlint // the exception is never wrapped
+		return exception.Value()
+	}
+	return rt.NewGoError(err)
+}
+
+// cloneBody returns a copy of the body. The stream of a streamed body is teed,
+// and the body keeps one of the branches, see https://fetch.spec.whatwg.org/#concept-body-clone
+func (f *fetchAPI) cloneBody(b *fetchBody) *fetchBody {
+	rt := f.vu.Runtime()
+	if b == nil {
+		return nil
+	}
+	if b.isUnusable() {
+		webidl.ThrowTypeError(rt, "the body has already been used")
+	}
+	if b.stream == nil && b.newStream == nil {
+		return &fetchBody{data: b.data}
+	}
+
+	stream := f.bodyStream(b).ToObject(rt)
+	tee, _ := sobek.AssertFunction(stream.Get("tee"))
+	branches, err := tee(stream)
+	webidl.Must(rt, err)
+	b.stream = branches.ToObject(rt).Get("0").ToObject(rt)
+	return &fetchBody{stream: branches.ToObject(rt).Get("1").ToObject(rt)}
+}
+
+// defineBodyMethods defines the methods of the Body interface mixin, that
+// Request and Response both include, see https://fetch.spec.whatwg.org/#body-mixin
+func (f *fetchAPI) defineBodyMethods(proto *sobek.Object, thisBody func(sobek.Value) *fetchBody) {
+	rt := f.vu.Runtime()
+
+	webidl.DefineGetter(rt, proto, "body", func(call sobek.FunctionCall) sobek.Value {
+		return f.bodyStream(thisBody(call.This))
+	})
+	webidl.DefineGetter(rt, proto, "bodyUsed", func(call sobek.FunctionCall) sobek.Value {
+		return rt.ToValue(thisBody(call.This).isUsed())
+	})
+	webidl.DefineMethod(rt, proto, "arrayBuffer", func(call sobek.FunctionCall) sobek.Value {
+		return rt.ToValue(f.consumeBody(thisBody(call.This), func(data []byte) (sobek.Value, error) {
+			return rt.ToValue(rt.NewArrayBuffer(bytes.Clone(data))), nil
+		}))
+	})
+	webidl.DefineMethod(rt, proto, "bytes", func(call sobek.FunctionCall) sobek.Value {
+		return rt.ToValue(f.consumeBody(thisBody(call.This), func(data []byte) (sobek.Value, error) {
+			return rt.New(rt.Get("Uint8Array"), rt.ToValue(rt.NewArrayBuffer(bytes.Clone(data))))
+		}))
+	})
+	webidl.DefineMethod(rt, proto, "text", func(call sobek.FunctionCall) sobek.Value {
+		return rt.ToValue(f.consumeBody(thisBody(call.This), func(data []byte) (sobek.Value, error) {
+			return rt.ToValue(string(data)), nil
+		}))
+	})
+	webidl.DefineMethod(rt, proto, "json", func(call sobek.FunctionCall) sobek.Value {
+		return rt.ToValue(f.consumeBody(thisBody(call.This), func(data []byte) (sobek.Value, error) {
+			parse, _ := sobek.AssertFunction(rt.Get("JSON").ToObject(rt).Get("parse"))
+			return parse(sobek.Undefined(), rt.ToValue(string(data)))
+		}))
+	})
+}
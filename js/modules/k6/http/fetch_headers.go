@@ -0,0 +1,269 @@
+package http
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/sobek"
+	"golang.org/x/net/http/httpguts"
+
+	"go.k6.io/k6/internal/js/webidl"
+	"go.k6.io/k6/js/common"
+)
+
+// headersGuard restricts the modifications of the headers, see
+// https://fetch.spec.whatwg.org/#headers-guard
+type headersGuard uint8
+
+const (
+	headersGuardNone headersGuard = iota
+	headersGuardImmutable
+)
+
+type headerEntry struct {
+	// name is always lowercased, as the names are case-insensitive
+	name  string
+	value string
+}
+
+// fetchHeaders is the Go side of the Headers objects of the Fetch API.
+type fetchHeaders struct {
+	webidl.NoOwnProperties
+
+	guard headersGuard
+	list  []headerEntry
+}
+
+func (h *fetchHeaders) get(name string) (string, bool) {
+	var (
+		values []string
+		found  bool
+	)
+	for _, entry := range h.list {
+		if entry.name == name {
+			values = append(values, entry.value)
+			found = true
+		}
+	}
+	return strings.Join(values, ", "), found
+}
+
+func (h *fetchHeaders) has(name string) bool {
+	return slices.ContainsFunc(h.list, func(entry headerEntry) bool { return entry.name == name })
+}
+
+func (h *fetchHeaders) append(name, value string) {
+	h.list = append(h.list, headerEntry{name: name, value: value})
+}
+
+func (h *fetchHeaders) delete(name string) {
+	h.list = slices.DeleteFunc(h.list, func(entry headerEntry) bool { return entry.name == name })
+}
+
+// set replaces the value of the first header with the name and deletes the others.
+func (h *fetchHeaders) set(name, value string) {
+	i := slices.IndexFunc(h.list, func(entry headerEntry) bool { return entry.name == name })
+	if i < 0 {
+		h.append(name, value)
+		return
+	}
+	h.list[i].value = value
+	h.list = append(h.list[:i+1], slices.DeleteFunc(h.list[i+1:], func(entry headerEntry) bool {
+		return entry.name == name
+	})...)
+}
+
+// sortedAndCombined returns the headers sorted by their names and with the values of the
+// same header combined, except for the Set-Cookie ones, as they are iterated.
+func (h *fetchHeaders) sortedAndCombined() []headerEntry {
+	names := make([]string, 0, len(h.list))
+	for _, entry := range h.list {
+		names = append(names, entry.name)
+	}
+	slices.Sort(names)
+	names = slices.Compact(names)
+
+	result := make([]headerEntry, 0, len(names))
+	for _, name := range names {
+		if name == "set-cookie" {
+			for _, entry := range h.list {
+				if entry.name == name {
+					result = append(result, entry)
+				}
+			}
+			continue
+		}
+		value, _ := h.get(name)
+		result = append(result, headerEntry{name: name, value: value})
+	}
+	return result
+}
+
+func (h *fetchHeaders) clone() *fetchHeaders {
+	return &fetchHeaders{guard: h.guard, list: slices.Clone(h.list)}
+}
+
+// normalizeHeader validates the name and the value of a header, and returns them
+// lowercased and without the leading and trailing whitespaces respectively.
+func normalizeHeader(rt *sobek.Runtime, name, value string) (string, string) {
+	value = strings.Trim(value, " \t\r\n")
+	if !httpguts.ValidHeaderFieldName(name) {
+		webidl.ThrowTypeError(rt, "invalid header name %q", name)
+	}
+	if !httpguts.ValidHeaderFieldValue(value) {
+		webidl.ThrowTypeError(rt, "invalid value for the header %q", name)
+	}
+	return strings.ToLower(name), value
+}
+
+func normalizeHeaderName(rt *sobek.Runtime, name string) string {
+	name, _ = normalizeHeader(rt, name, "")
+	return name
+}
+
+// fill fills the headers from an init value, which can be a Headers object, an iterable of
+// name-value pairs or a record, see https://fetch.spec.whatwg.org/#concept-headers-fill
+func (h *fetchHeaders) fill(rt *sobek.Runtime, init sobek.Value) {
+	if common.IsNullish(init) {
+		return
+	}
+	obj, ok := init.(*sobek.Object)
+	if !ok {
+		webidl.ThrowTypeError(rt, "the headers must be an object")
+	}
+	if other, ok := obj.Export().(*fetchHeaders); ok {
+		for _, entry := range other.list {
+			h.append(entry.name, entry.value)
+		}
+		return
+	}
+
+	if iterator := obj.GetSymbol(sobek.SymIterator); !common.IsNullish(iterator) {
+		arrayFrom, _ := sobek.AssertFunction(rt.Get("Array").ToObject(rt).Get("from"))
+		pairs, err := arrayFrom(sobek.Undefined(), obj)
+		if err != nil {
+			panic(err)
+		}
+		pairsObj := pairs.ToObject(rt)
+		for i := range pairsObj.Get("length").ToInteger() {
+			pair, ok := pairsObj.Get(strconv.FormatInt(i, 10)).(*sobek.Object)
+			if !ok || pair.Get("length").ToInteger() != 2 {
+				webidl.ThrowTypeError(rt, "the headers must be a sequence of name-value pairs")
+			}
+			h.append(normalizeHeader(rt, pair.Get("0").String(), pair.Get("1").String()))
+		}
+		return
+	}
+
+	for _, key := range obj.Keys() {
+		h.append(normalizeHeader(rt, key, obj.Get(key).String()))
+	}
+}
+
+func (f *fetchAPI) newHeadersObject(h *fetchHeaders) *sobek.Object {
+	return webidl.NewInstance(f.vu.Runtime(), h, f.headersProto)
+}
+
+func (f *fetchAPI) headersConstructor(call sobek.ConstructorCall) *sobek.Object {
+	h := &fetchHeaders{}
+	h.fill(f.vu.Runtime(), call.Argument(0))
+	return webidl.NewInstance(f.vu.Runtime(), h, call.This.Prototype())
+}
+
+func (f *fetchAPI) thisHeaders(this sobek.Value) *fetchHeaders {
+	h, ok := this.Export().(*fetchHeaders)
+	if !ok {
+		webidl.ThrowTypeError(f.vu.Runtime(), "Illegal invocation")
+	}
+	return h
+}
+
+func (f *fetchAPI) checkHeadersGuard(h *fetchHeaders) {
+	if h.guard == headersGuardImmutable {
+		webidl.ThrowTypeError(f.vu.Runtime(), "the headers are immutable")
+	}
+}
+
+//nolint:funlen
+func (f *fetchAPI) defineHeadersPrototype() {
+	rt := f.vu.Runtime()
+	proto := f.headersProto
+
+	webidl.DefineMethod(rt, proto, "append", func(call sobek.FunctionCall) sobek.Value {
+		h := f.thisHeaders(call.This)
+		name, value := normalizeHeader(rt, call.Argument(0).String(), call.Argument(1).String())
+		f.checkHeadersGuard(h)
+		h.append(name, value)
+		return sobek.Undefined()
+	})
+	webidl.DefineMethod(rt, proto, "delete", func(call sobek.FunctionCall) sobek.Value {
+		h := f.thisHeaders(call.This)
+		name := normalizeHeaderName(rt, call.Argument(0).String())
+		f.checkHeadersGuard(h)
+		h.delete(name)
+		return sobek.Undefined()
+	})
+	webidl.DefineMethod(rt, proto, "get", func(call sobek.FunctionCall) sobek.Value {
+		h := f.thisHeaders(call.This)
+		value, ok := h.get(normalizeHeaderName(rt, call.Argument(0).String()))
+		if !ok {
+			return sobek.Null()
+		}
+		return rt.ToValue(value)
+	})
+	webidl.DefineMethod(rt, proto, "getSetCookie", func(call sobek.FunctionCall) sobek.Value {
+		h := f.thisHeaders(call.This)
+		values := make([]any, 0)
+		for _, entry := range h.list {
+			if entry.name == "set-cookie" {
+				values = append(values, entry.value)
+			}
+		}
+		return rt.NewArray(values...)
+	})
+	webidl.DefineMethod(rt, proto, "has", func(call sobek.FunctionCall) sobek.Value {
+		h := f.thisHeaders(call.This)
+		return rt.ToValue(h.has(normalizeHeaderName(rt, call.Argument(0).String())))
+	})
+	webidl.DefineMethod(rt, proto, "set", func(call sobek.FunctionCall) sobek.Value {
+		h := f.thisHeaders(call.This)
+		name, value := normalizeHeader(rt, call.Argument(0).String(), call.Argument(1).String())
+		f.checkHeadersGuard(h)
+		h.set(name, value)
+		return sobek.Undefined()
+	})
+	webidl.DefineMethod(rt, proto, "forEach", func(call sobek.FunctionCall) sobek.Value {
+		h := f.thisHeaders(call.This)
+		callback, ok := sobek.AssertFunction(call.Argument(0))
+		if !ok {
+			webidl.ThrowTypeError(rt, "the callback must be a function")
+		}
+		for _, entry := range h.sortedAndCombined() {
+			if _, err := callback(call.Argument(1), rt.ToValue(entry.value), rt.ToValue(entry.name), call.This); err != nil {
+				panic(err)
+			}
+		}
+		return sobek.Undefined()
+	})
+
+	iterate := func(toValue func(headerEntry) any) func(call sobek.FunctionCall) sobek.Value {
+		return func(call sobek.FunctionCall) sobek.Value {
+			h := f.thisHeaders(call.This)
+			entries := h.sortedAndCombined()
+			values := make([]any, 0, len(entries))
+			for _, entry := range entries {
+				values = append(values, toValue(entry))
+			}
+			return f.arrayIterator(rt.NewArray(values...))
+		}
+	}
+	entries := rt.ToValue(iterate(func(entry headerEntry) any {
+		return rt.NewArray(entry.name, entry.value)
+	}))
+	webidl.DefineMethod(rt, proto, "entries", entries)
+	webidl.DefineMethod(rt, proto, "keys", iterate(func(entry headerEntry) any { return entry.name }))
+	webidl.DefineMethod(rt, proto, "values", iterate(func(entry headerEntry) any { return entry.value }))
+	webidl.Must(rt, proto.DefineDataPropertySymbol(sobek.SymIterator, entries, sobek.FLAG_TRUE, sobek.FLAG_FALSE, sobek.FLAG_TRUE))
+	webidl.DefineToStringTag(rt, proto, "Headers")
+}
@@ -0,0 +1,222 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/grafana/sobek"
+	"golang.org/x/net/http/httpguts"
+
+	"go.k6.io/k6/internal/js/webidl"
+	"go.k6.io/k6/js/common"
+	"go.k6.io/k6/lib/netext/httpext"
+)
+
+// fetchRequestParams are the k6-specific members of the RequestInit dictionary, which
+// are handled as the params of the k6/http requests with the same names.
+//
+//nolint:gochecknoglobals
+var fetchRequestParams = []string{"tags", "timeout", "jar", "responseCallback", "compression", "http3"}
+
+// fetchRequest is the Go side of the Request objects of the Fetch API.
+type fetchRequest struct {
+	webidl.NoOwnProperties
+
+	method   string
+	url      httpext.URL
+	headers  *fetchHeaders
+	body     *fetchBody
+	redirect string
+	signal   sobek.Value
+	params   map[string]sobek.Value
+
+	headersObj *sobek.Object
+}
+
+// parseFetchURL parses the URL of a request, which can be also created by http.url so
+// requests to URLs with dynamic parts are grouped by the name tag.
+func parseFetchURL(rt *sobek.Runtime, input sobek.Value) httpext.URL {
+	var (
+		u   httpext.URL
+		err error
+	)
+	if tagged, ok := input.Export().(httpext.URL); ok {
+		u = tagged
+	} else {
+		u, err = httpext.ToURL(input.String())
+	}
+	if err != nil || !u.GetURL().IsAbs() {
+		webidl.ThrowTypeError(rt, "invalid URL %q", input.String())
+	}
+	if u.GetURL().User != nil {
+		webidl.ThrowTypeError(rt, "the URL %q includes credentials, use the Authorization header instead", u.Clean())
+	}
+	return u
+}
+
+// normalizeMethod validates and normalizes the method of a request, see
+// https://fetch.spec.whatwg.org/#concept-method-normalize
+func normalizeMethod(rt *sobek.Runtime, method string) string {
+	if !httpguts.ValidHeaderFieldName(method) {
+		webidl.ThrowTypeError(rt, "invalid method %q", method)
+	}
+	upper := strings.ToUpper(method)
+	switch upper {
+	case "CONNECT", "TRACE", "TRACK":
+		webidl.ThrowTypeError(rt, "the method %q is forbidden", method)
+	case http.MethodDelete, http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPost, http.MethodPut:
+		return upper
+	}
+	return method
+}
+
+// newRequest creates a request from the arguments of the Request constructor
+// and fetch(), see https://fetch.spec.whatwg.org/#dom-request
+//
+//nolint:funlen,cyclop
+func (f *fetchAPI) newRequest(input, init sobek.Value) *fetchRequest {
+	rt := f.vu.Runtime()
+
+	req := &fetchRequest{
+		method:   http.MethodGet,
+		headers:  &fetchHeaders{},
+		redirect: "follow",
+		params:   make(map[string]sobek.Value),
+	}
+
+	inputReq, isRequest := input.Export().(*fetchRequest)
+	if isRequest {
+		req.method = inputReq.method
+		req.url = inputReq.url
+		req.headers = inputReq.headers.clone()
+		req.redirect = inputReq.redirect
+		req.signal = inputReq.signal
+		for k, v := range inputReq.params {
+			req.params[k] = v
+		}
+	} else {
+		req.url = parseFetchURL(rt, input)
+	}
+
+	var initObj *sobek.Object
+	if !common.IsNullish(init) {
+		initObj = init.ToObject(rt)
+	}
+	member := func(name string) sobek.Value {
+		if initObj == nil {
+			return nil
+		}
+		if v := initObj.Get(name); !sobek.IsUndefined(v) {
+			return v
+		}
+		return nil
+	}
+
+	if v := member("method"); v != nil {
+		req.method = normalizeMethod(rt, v.String())
+	}
+	if v := member("redirect"); v != nil {
+		switch redirect := v.String(); redirect {
+		case "follow", "error", "manual":
+			req.redirect = redirect
+		default:
+			webidl.ThrowTypeError(rt, "invalid redirect mode %q", redirect)
+		}
+	}
+	if v := member("signal"); v != nil {
+		if sobek.IsNull(v) {
+			req.signal = nil
+		} else {
+			req.signal = v
+		}
+	}
+	if v := member("headers"); v != nil {
+		req.headers = &fetchHeaders{}
+		req.headers.fill(rt, v)
+	}
+	for _, name := range fetchRequestParams {
+		if v := member(name); v != nil {
+			req.params[name] = v
+		}
+	}
+
+	bodyInit := member("body")
+	if (isRequest && inputReq.body != nil || bodyInit != nil && !sobek.IsNull(bodyInit)) &&
+		(req.method == http.MethodGet || req.method == http.MethodHead) {
+		webidl.ThrowTypeError(rt, "a request with the %s method can't have a body", req.method)
+	}
+
+	switch {
+	case bodyInit != nil && !sobek.IsNull(bodyInit):
+		body, contentType := extractBody(rt, bodyInit)
+		// only the half duplex is supported, where the body is sent before the response is received
+		if v := member("duplex"); body.stream != nil && (v == nil || v.String() != "half") {
+			webidl.ThrowTypeError(rt, `the duplex member must be "half" for a request with a ReadableStream body`)
+		}
+		req.body = body
+		if contentType != "" && !req.headers.has("content-type") {
+			req.headers.append("content-type", contentType)
+		}
+	case isRequest && inputReq.body != nil:
+		// The body of the input request is transferred to the new one
+		if inputReq.body.isUnusable() {
+			webidl.ThrowTypeError(rt, "the body of the request has already been used")
+		}
+		req.body = &fetchBody{data: inputReq.body.data, stream: inputReq.body.stream}
+		inputReq.body.used = true
+	}
+
+	return req
+}
+
+func (f *fetchAPI) newRequestObject(req *fetchRequest, proto *sobek.Object) *sobek.Object {
+	req.headersObj = f.newHeadersObject(req.headers)
+	return webidl.NewInstance(f.vu.Runtime(), req, proto)
+}
+
+func (f *fetchAPI) requestConstructor(call sobek.ConstructorCall) *sobek.Object {
+	return f.newRequestObject(f.newRequest(call.Argument(0), call.Argument(1)), call.This.Prototype())
+}
+
+func (f *fetchAPI) thisRequest(this sobek.Value) *fetchRequest {
+	req, ok := this.Export().(*fetchRequest)
+	if !ok {
+		webidl.ThrowTypeError(f.vu.Runtime(), "Illegal invocation")
+	}
+	return req
+}
+
+func (f *fetchAPI) defineRequestPrototype() {
+	rt := f.vu.Runtime()
+	proto := f.requestProto
+
+	webidl.DefineGetter(rt, proto, "method", func(call sobek.FunctionCall) sobek.Value {
+		return rt.ToValue(f.thisRequest(call.This).method)
+	})
+	webidl.DefineGetter(rt, proto, "url", func(call sobek.FunctionCall) sobek.Value {
+		return rt.ToValue(f.thisRequest(call.This).url.GetURL().String())
+	})
+	webidl.DefineGetter(rt, proto, "headers", func(call sobek.FunctionCall) sobek.Value {
+		return f.thisRequest(call.This).headersObj
+	})
+	webidl.DefineGetter(rt, proto, "redirect", func(call sobek.FunctionCall) sobek.Value {
+		return rt.ToValue(f.thisRequest(call.This).redirect)
+	})
+	webidl.DefineGetter(rt, proto, "signal", func(call sobek.FunctionCall) sobek.Value {
+		if signal := f.thisRequest(call.This).signal; signal != nil {
+			return signal
+		}
+		return sobek.Null()
+	})
+	f.defineBodyMethods(proto, func(this sobek.Value) *fetchBody {
+		return f.thisRequest(this).body
+	})
+	webidl.DefineMethod(rt, proto, "clone", func(call sobek.FunctionCall) sobek.Value {
+		req := f.thisRequest(call.This)
+		clone := *req
+		clone.headers = req.headers.clone()
+		clone.body = f.cloneBody(req.body)
+		return f.newRequestObject(&clone, f.requestProto)
+	})
+	webidl.DefineToStringTag(rt, proto, "Request")
+}
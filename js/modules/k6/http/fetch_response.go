@@ -0,0 +1,210 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/internal/js/webidl"
+	"go.k6.io/k6/js/common"
+	"go.k6.io/k6/lib/netext/httpext"
+)
+
+// fetchResponse is the Go side of the Response objects of the Fetch API.
+type fetchResponse struct {
+	webidl.NoOwnProperties
+
+	typ        string
+	url        string
+	redirected bool
+	status     int
+	statusText string
+	headers    *fetchHeaders
+	body       *fetchBody
+
+	headersObj *sobek.Object
+}
+
+// isNullBodyStatus returns whether a response with the status can't have a body, see
+// https://fetch.spec.whatwg.org/#null-body-status
+func isNullBodyStatus(status int) bool {
+	switch status {
+	case http.StatusSwitchingProtocols, http.StatusEarlyHints, http.StatusNoContent,
+		http.StatusResetContent, http.StatusNotModified:
+		return true
+	}
+	return false
+}
+
+// isRedirectStatus returns whether the status is a redirect one, see
+// https://fetch.spec.whatwg.org/#redirect-status
+func isRedirectStatus(status int) bool {
+	switch status {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
+
+// responseFromHTTPext converts a response of the k6/http machinery to a fetch one.
+func responseFromHTTPext(req *fetchRequest, resp *httpext.Response) *fetchResponse {
+	res := &fetchResponse{
+		typ:        "basic",
+		url:        resp.URL,
+		redirected: resp.URL != req.url.GetURL().String(),
+		status:     resp.Status,
+		statusText: strings.TrimSpace(strings.TrimPrefix(resp.StatusText, strconv.Itoa(resp.Status))),
+		headers:    &fetchHeaders{guard: headersGuardImmutable},
+	}
+	for name, value := range resp.Headers {
+		res.headers.append(strings.ToLower(name), value)
+	}
+	// the stream of a streamed body is set by fetch
+	if req.method != http.MethodHead && !isNullBodyStatus(resp.Status) {
+		res.body = &fetchBody{}
+	}
+	return res
+}
+
+// initializeResponse applies the ResponseInit dictionary and the body to a response, see
+// https://fetch.spec.whatwg.org/#initialize-a-response
+func (f *fetchAPI) initializeResponse(res *fetchResponse, init sobek.Value, body *fetchBody, contentType string) {
+	rt := f.vu.Runtime()
+
+	res.status = http.StatusOK
+	if !common.IsNullish(init) {
+		initObj := init.ToObject(rt)
+		if v := initObj.Get("status"); !common.IsNullish(v) {
+			res.status = int(v.ToInteger())
+		}
+		if v := initObj.Get("statusText"); !common.IsNullish(v) {
+			res.statusText = v.String()
+		}
+		if v := initObj.Get("headers"); !common.IsNullish(v) {
+			res.headers.fill(rt, v)
+		}
+	}
+
+	if res.status < 200 || res.status > 599 {
+		webidl.ThrowRangeError(rt, "the status must be in the range 200 to 599, got %d", res.status)
+	}
+	if strings.ContainsAny(res.statusText, "\r\n") {
+		webidl.ThrowTypeError(rt, "invalid status text %q", res.statusText)
+	}
+
+	if body != nil {
+		if isNullBodyStatus(res.status) {
+			webidl.ThrowTypeError(rt, "a response with the status %d can't have a body", res.status)
+		}
+		res.body = body
+		if contentType != "" && !res.headers.has("content-type") {
+			res.headers.append("content-type", contentType)
+		}
+	}
+}
+
+func (f *fetchAPI) newResponseObject(res *fetchResponse, proto *sobek.Object) *sobek.Object {
+	res.headersObj = f.newHeadersObject(res.headers)
+	return webidl.NewInstance(f.vu.Runtime(), res, proto)
+}
+
+func (f *fetchAPI) responseConstructor(call sobek.ConstructorCall) *sobek.Object {
+	res := &fetchResponse{typ: "default", headers: &fetchHeaders{}}
+
+	var (
+		body        *fetchBody
+		contentType string
+	)
+	if bodyInit := call.Argument(0); !common.IsNullish(bodyInit) {
+		body, contentType = extractBody(f.vu.Runtime(), bodyInit)
+	}
+	f.initializeResponse(res, call.Argument(1), body, contentType)
+
+	return f.newResponseObject(res, call.This.Prototype())
+}
+
+func (f *fetchAPI) thisResponse(this sobek.Value) *fetchResponse {
+	res, ok := this.Export().(*fetchResponse)
+	if !ok {
+		webidl.ThrowTypeError(f.vu.Runtime(), "Illegal invocation")
+	}
+	return res
+}
+
+//nolint:funlen
+func (f *fetchAPI) defineResponseClass(ctor *sobek.Object) {
+	rt := f.vu.Runtime()
+	proto := f.responseProto
+
+	webidl.DefineMethod(rt, ctor, "error", func(sobek.FunctionCall) sobek.Value {
+		res := &fetchResponse{typ: "error", headers: &fetchHeaders{guard: headersGuardImmutable}}
+		return f.newResponseObject(res, f.responseProto)
+	})
+	webidl.DefineMethod(rt, ctor, "json", func(call sobek.FunctionCall) sobek.Value {
+		stringify, _ := sobek.AssertFunction(rt.Get("JSON").ToObject(rt).Get("stringify"))
+		data, err := stringify(sobek.Undefined(), call.Argument(0))
+		if err != nil {
+			panic(err)
+		}
+		if sobek.IsUndefined(data) {
+			webidl.ThrowTypeError(rt, "the data can't be serialized as JSON")
+		}
+		res := &fetchResponse{typ: "default", headers: &fetchHeaders{}}
+		f.initializeResponse(res, call.Argument(1), &fetchBody{data: []byte(data.String())}, "application/json")
+		return f.newResponseObject(res, f.responseProto)
+	})
+	webidl.DefineMethod(rt, ctor, "redirect", func(call sobek.FunctionCall) sobek.Value {
+		u := parseFetchURL(rt, call.Argument(0))
+		status := http.StatusFound
+		if v := call.Argument(1); !sobek.IsUndefined(v) {
+			status = int(v.ToInteger())
+		}
+		if !isRedirectStatus(status) {
+			webidl.ThrowRangeError(rt, "invalid redirect status %d", status)
+		}
+		res := &fetchResponse{
+			typ:     "default",
+			status:  status,
+			headers: &fetchHeaders{guard: headersGuardImmutable},
+		}
+		res.headers.append("location", u.GetURL().String())
+		return f.newResponseObject(res, f.responseProto)
+	})
+
+	webidl.DefineGetter(rt, proto, "type", func(call sobek.FunctionCall) sobek.Value {
+		return rt.ToValue(f.thisResponse(call.This).typ)
+	})
+	webidl.DefineGetter(rt, proto, "url", func(call sobek.FunctionCall) sobek.Value {
+		return rt.ToValue(f.thisResponse(call.This).url)
+	})
+	webidl.DefineGetter(rt, proto, "redirected", func(call sobek.FunctionCall) sobek.Value {
+		return rt.ToValue(f.thisResponse(call.This).redirected)
+	})
+	webidl.DefineGetter(rt, proto, "status", func(call sobek.FunctionCall) sobek.Value {
+		return rt.ToValue(f.thisResponse(call.This).status)
+	})
+	webidl.DefineGetter(rt, proto, "ok", func(call sobek.FunctionCall) sobek.Value {
+		status := f.thisResponse(call.This).status
+		return rt.ToValue(status >= 200 && status <= 299)
+	})
+	webidl.DefineGetter(rt, proto, "statusText", func(call sobek.FunctionCall) sobek.Value {
+		return rt.ToValue(f.thisResponse(call.This).statusText)
+	})
+	webidl.DefineGetter(rt, proto, "headers", func(call sobek.FunctionCall) sobek.Value {
+		return f.thisResponse(call.This).headersObj
+	})
+	f.defineBodyMethods(proto, func(this sobek.Value) *fetchBody {
+		return f.thisResponse(this).body
+	})
+	webidl.DefineMethod(rt, proto, "clone", func(call sobek.FunctionCall) sobek.Value {
+		res := f.thisResponse(call.This)
+		clone := *res
+		clone.headers = res.headers.clone()
+		clone.body = f.cloneBody(res.body)
+		return f.newResponseObject(&clone, f.responseProto)
+	})
+	webidl.DefineToStringTag(rt, proto, "Response")
+}
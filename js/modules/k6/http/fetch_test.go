@@ -0,0 +1,383 @@
+package http
+
+import (
+	"net/http/cookiejar"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/internal/js/modules/k6/experimental/streams"
+	"go.k6.io/k6/js/modulestest"
+	"go.k6.io/k6/metrics"
+)
+
+func newFetchTestCase(t *testing.T) *httpTestCase {
+	t.Helper()
+	ts := newTestCase(t)
+	require.NoError(t, SetupFetchGlobally(ts.runtime.VU))
+
+	readableStream := streams.New().NewModuleInstance(ts.runtime.VU).Exports().Named["ReadableStream"]
+	require.NoError(t, ts.runtime.VU.Runtime().Set("ReadableStream", readableStream))
+	return ts
+}
+
+func TestFetchHeaders(t *testing.T) {
+	t.Parallel()
+	ts := newFetchTestCase(t)
+
+	_, err := ts.runtime.VU.Runtime().RunString(`
+		var h = new Headers({"Content-Type": "text/plain", "X-Test": " a "});
+		h.append("x-test", "b");
+		h.append("Set-Cookie", "a=1");
+		h.append("Set-Cookie", "b=2");
+
+		if (!(h instanceof Headers)) { throw new Error("not an instance of Headers"); }
+		if (String(h) !== "[object Headers]") { throw new Error("wrong string tag: " + String(h)); }
+		if (h.get("X-TEST") !== "a, b") { throw new Error("wrong combined value: " + h.get("X-TEST")); }
+		if (h.get("missing") !== null) { throw new Error("missing header isn't null"); }
+		if (!h.has("content-type") || h.has("missing")) { throw new Error("wrong has()"); }
+		if (JSON.stringify(h.getSetCookie()) !== '["a=1","b=2"]') { throw new Error("wrong getSetCookie()"); }
+
+		var entries = JSON.stringify([...h]);
+		var expected = '[["content-type","text/plain"],["set-cookie","a=1"],["set-cookie","b=2"],["x-test","a, b"]]';
+		if (entries !== expected) { throw new Error("wrong entries: " + entries); }
+
+		h.set("X-Test", "c");
+		h.delete("set-cookie");
+		if (JSON.stringify([...h.keys()]) !== '["content-type","x-test"]') { throw new Error("wrong keys: " + [...h.keys()]); }
+
+		var copy = new Headers([["a", "1"], ["b", "2"]]);
+		var values = [];
+		copy.forEach((value, name) => values.push(name + "=" + value));
+		if (values.join("&") !== "a=1&b=2") { throw new Error("wrong forEach: " + values); }
+
+		var thrown = false;
+		try { h.append("invalid name", "value"); } catch (e) { thrown = e instanceof TypeError; }
+		if (!thrown) { throw new Error("an invalid name didn't throw a TypeError"); }
+	`)
+	require.NoError(t, err)
+}
+
+func TestFetchRequestAndResponse(t *testing.T) {
+	t.Parallel()
+	ts := newFetchTestCase(t)
+
+	_, err := ts.runtime.RunOnEventLoop(wrapInAsyncLambda(`
+		var req = new Request("http://example.com/path", {method: "post", body: "data", headers: {"X-Test": "1"}});
+		if (req.method !== "POST") { throw new Error("wrong method: " + req.method); }
+		if (req.url !== "http://example.com/path") { throw new Error("wrong url: " + req.url); }
+		if (req.headers.get("content-type") !== "text/plain;charset=UTF-8") { throw new Error("wrong content type"); }
+
+		var copy = new Request(req);
+		if (!req.bodyUsed) { throw new Error("the body wasn't transferred"); }
+		if (await copy.text() !== "data") { throw new Error("wrong body of the copy"); }
+
+		var thrown = false;
+		try { new Request("http://example.com/", {body: "data"}); } catch (e) { thrown = e instanceof TypeError; }
+		if (!thrown) { throw new Error("a GET request with a body didn't throw a TypeError"); }
+
+		var res = Response.json({a: 1}, {status: 201, headers: {"X-Test": "1"}});
+		if (!(res instanceof Response) || res.status !== 201 || !res.ok) { throw new Error("wrong response"); }
+		if (res.headers.get("content-type") !== "application/json") { throw new Error("wrong content type"); }
+
+		var clone = res.clone();
+		if ((await res.json()).a !== 1) { throw new Error("wrong JSON body"); }
+		if (!res.bodyUsed) { throw new Error("the body wasn't used"); }
+		if (await clone.text() !== '{"a":1}') { throw new Error("wrong body of the clone"); }
+
+		var rejected = false;
+		await res.text().catch((e) => { rejected = e instanceof TypeError; });
+		if (!rejected) { throw new Error("reading a used body didn't reject with a TypeError"); }
+
+		thrown = false;
+		try { new Response(null, {status: 600}); } catch (e) { thrown = e instanceof RangeError; }
+		if (!thrown) { throw new Error("an invalid status didn't throw a RangeError"); }
+
+		var bytes = await new Response(new Uint8Array([1, 2, 3]).subarray(1)).bytes();
+		if (bytes.join(",") !== "2,3") { throw new Error("wrong bytes: " + bytes); }
+
+		var reader = new Response("streamed").body.getReader();
+		var chunk = await reader.read();
+		if (!(chunk.value instanceof Uint8Array) || chunk.value.length !== 8) { throw new Error("wrong chunk"); }
+
+		var redirect = Response.redirect("http://example.com/other", 301);
+		if (redirect.status !== 301 || redirect.headers.get("location") !== "http://example.com/other") {
+			throw new Error("wrong redirect response");
+		}
+	`))
+	require.NoError(t, err)
+}
+
+func TestFetch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GET", func(t *testing.T) {
+		t.Parallel()
+		ts := newFetchTestCase(t)
+		sr := ts.tb.Replacer.Replace
+
+		_, err := ts.runtime.RunOnEventLoop(wrapInAsyncLambda(sr(`
+			var res = await fetch("HTTPBIN_URL/get", {headers: {"X-Test": "value"}});
+			if (res.status !== 200 || !res.ok || res.statusText !== "OK") { throw new Error("wrong status: " + res.status); }
+			if (res.type !== "basic" || res.redirected) { throw new Error("wrong type or redirected"); }
+			if (!res.headers.get("content-type").startsWith("application/json")) { throw new Error("wrong content type"); }
+			var body = await res.json();
+			if (body.headers["X-Test"][0] !== "value") { throw new Error("wrong request headers: " + JSON.stringify(body)); }
+			if (body.headers["User-Agent"][0] !== "TestUserAgent") { throw new Error("wrong user agent: " + JSON.stringify(body)); }
+		`)))
+		require.NoError(t, err)
+
+		assertRequestMetricsEmitted(t, metrics.GetBufferedSamples(ts.samples), "GET", sr("HTTPBIN_URL/get"), 200, "")
+	})
+
+	t.Run("POST", func(t *testing.T) {
+		t.Parallel()
+		ts := newFetchTestCase(t)
+		sr := ts.tb.Replacer.Replace
+
+		_, err := ts.runtime.RunOnEventLoop(wrapInAsyncLambda(sr(`
+			var res = await fetch("HTTPBIN_URL/post", {method: "POST", body: JSON.stringify({a: 1}),
+				headers: {"Content-Type": "application/json"}});
+			var body = await res.json();
+			if (body.json.a !== 1) { throw new Error("wrong request body: " + JSON.stringify(body)); }
+
+			var stream = new ReadableStream({
+				start(controller) {
+					controller.enqueue(new Uint8Array([104, 105]));
+					controller.enqueue(new Uint8Array([33]));
+					controller.close();
+				},
+			});
+			res = await fetch(new Request("HTTPBIN_URL/post", {method: "POST", body: stream, duplex: "half"}));
+			body = await res.json();
+			if (body.data !== "hi!") { throw new Error("wrong streamed request body: " + JSON.stringify(body)); }
+
+			var thrown = false;
+			try { new Request("HTTPBIN_URL/post", {method: "POST", body: new ReadableStream()}); } catch (e) { thrown = e instanceof TypeError; }
+			if (!thrown) { throw new Error("a ReadableStream body without the duplex member didn't throw a TypeError"); }
+		`)))
+		require.NoError(t, err)
+	})
+
+	t.Run("StreamedBodies", func(t *testing.T) {
+		t.Parallel()
+		ts := newFetchTestCase(t)
+		sr := ts.tb.Replacer.Replace
+
+		start := time.Now()
+		_, err := ts.runtime.RunOnEventLoop(wrapInAsyncLambda(sr(`
+			var started = Date.now();
+			var res = await fetch("HTTPBIN_URL/drip?numbytes=4&duration=2");
+			var resolved = Date.now() - started;
+			if (resolved > 1000) { throw new Error("fetch wasn't resolved with the headers: " + resolved + "ms"); }
+
+			var reader = res.body.getReader();
+			var chunks = 0, length = 0;
+			while (true) {
+				var {value, done} = await reader.read();
+				if (done) { break; }
+				if (!(value instanceof Uint8Array)) { throw new Error("wrong chunk: " + value); }
+				chunks++;
+				length += value.length;
+			}
+			if (length !== 4 || chunks < 2) { throw new Error("the body wasn't streamed: " + chunks + " chunks of " + length + " bytes"); }
+			if (!res.bodyUsed) { throw new Error("the body wasn't used"); }
+
+			res = await fetch("HTTPBIN_URL/drip?numbytes=2&duration=1");
+			var clone = res.clone();
+			var texts = await Promise.all([res.text(), clone.text()]);
+			if (texts[0] !== "**" || texts[1] !== "**") { throw new Error("wrong bodies of the clones: " + texts); }
+
+			res = await fetch("HTTPBIN_URL/drip?numbytes=10&duration=10");
+			reader = res.body.getReader();
+			await reader.read();
+			await reader.cancel();
+
+			var controller = new AbortController();
+			res = await fetch("HTTPBIN_URL/drip?numbytes=10&duration=10", {signal: controller.signal});
+			reader = res.body.getReader();
+			await reader.read();
+			controller.abort();
+			var reason = await reader.read().catch((e) => e);
+			if (reason.name !== "AbortError") { throw new Error("wrong reason: " + reason); }
+		`)))
+		require.NoError(t, err)
+		assert.Less(t, time.Since(start), 8*time.Second) // the 10s bodies were canceled
+
+		// all the requests, including the canceled ones, were finished
+		seen := 0
+		for _, sampleContainer := range metrics.GetBufferedSamples(ts.samples) {
+			for _, sample := range sampleContainer.GetSamples() {
+				if sample.Metric.Name == metrics.HTTPReqDurationName {
+					seen++
+				}
+			}
+		}
+		assert.Equal(t, 3, seen) // the aborted request isn't measured, as with k6/http
+	})
+
+	t.Run("CookieJar", func(t *testing.T) {
+		t.Parallel()
+		ts := newFetchTestCase(t)
+		sr := ts.tb.Replacer.Replace
+		cookieJar, err := cookiejar.New(nil)
+		require.NoError(t, err)
+		ts.runtime.VU.StateField.CookieJar = cookieJar
+
+		_, err = ts.runtime.RunOnEventLoop(wrapInAsyncLambda(sr(`
+			await fetch("HTTPBIN_URL/cookies/set?key=value");
+			var body = await (await fetch("HTTPBIN_URL/cookies")).json();
+			if (body.key !== "value") { throw new Error("wrong cookies: " + JSON.stringify(body)); }
+		`)))
+		require.NoError(t, err)
+	})
+
+	t.Run("Redirects", func(t *testing.T) {
+		t.Parallel()
+		ts := newFetchTestCase(t)
+		sr := ts.tb.Replacer.Replace
+
+		_, err := ts.runtime.RunOnEventLoop(wrapInAsyncLambda(sr(`
+			var res = await fetch("HTTPBIN_URL/redirect/1");
+			if (res.status !== 200 || !res.redirected || res.url !== "HTTPBIN_URL/get") {
+				throw new Error("the redirect wasn't followed: " + res.status + " " + res.url);
+			}
+
+			res = await fetch("HTTPBIN_URL/redirect/1", {redirect: "manual"});
+			if (res.status !== 302 || res.redirected) { throw new Error("the redirect was followed: " + res.status); }
+
+			var rejected = false;
+			await fetch("HTTPBIN_URL/redirect/1", {redirect: "error"}).catch((e) => { rejected = e instanceof TypeError; });
+			if (!rejected) { throw new Error("the redirect didn't reject with a TypeError"); }
+		`)))
+		require.NoError(t, err)
+	})
+
+	t.Run("TagsAndResponseCallback", func(t *testing.T) {
+		t.Parallel()
+		ts := newFetchTestCase(t)
+		sr := ts.tb.Replacer.Replace
+
+		_, err := ts.runtime.RunOnEventLoop(wrapInAsyncLambda(sr(`
+			// the requests are finished with their bodies
+			await (await fetch("HTTPBIN_URL/status/404", {tags: {tag: "default"}})).text();
+			await (await fetch("HTTPBIN_URL/status/404", {
+				tags: {tag: "callback"},
+				responseCallback: http.expectedStatuses(404),
+			})).text();
+		`)))
+		require.NoError(t, err)
+
+		expected := map[string]string{"default": "false", "callback": "true"}
+		seen := 0
+		for _, sampleContainer := range metrics.GetBufferedSamples(ts.samples) {
+			for _, sample := range sampleContainer.GetSamples() {
+				if sample.Metric.Name != metrics.HTTPReqFailedName {
+					continue
+				}
+				tags := sample.Tags.Map()
+				assert.Equal(t, expected[tags["tag"]], tags["expected_response"])
+				seen++
+			}
+		}
+		assert.Equal(t, 2, seen)
+	})
+
+	t.Run("SetResponseCallback", func(t *testing.T) {
+		t.Parallel()
+		ts := newFetchTestCase(t)
+		sr := ts.tb.Replacer.Replace
+
+		_, err := ts.runtime.RunOnEventLoop(wrapInAsyncLambda(sr(`
+			http.setResponseCallback(http.expectedStatuses(404));
+			await fetch("HTTPBIN_URL/status/404");
+		`)))
+		require.NoError(t, err)
+
+		// the unread body is discarded at the end of the iteration, finishing the request
+		ts.runtime.CancelContext()
+		var failed []metrics.Sample
+		require.Eventually(t, func() bool {
+			for _, sampleContainer := range metrics.GetBufferedSamples(ts.samples) {
+				for _, sample := range sampleContainer.GetSamples() {
+					if sample.Metric.Name == metrics.HTTPReqFailedName {
+						failed = append(failed, sample)
+					}
+				}
+			}
+			return len(failed) > 0
+		}, 5*time.Second, 10*time.Millisecond)
+		require.Len(t, failed, 1)
+		assert.Equal(t, "true", failed[0].Tags.Map()["expected_response"])
+	})
+
+	t.Run("SharedDefaultClient", func(t *testing.T) {
+		t.Parallel()
+		// the globals are set up before the modules are imported by the scripts
+		runtime := modulestest.NewRuntime(t)
+		require.NoError(t, SetupFetchGlobally(runtime.VU))
+		mi, ok := New().NewModuleInstance(runtime.VU).(*ModuleInstance)
+		require.True(t, ok)
+
+		fetchClient := runtime.VU.Runtime().GlobalObject().GetSymbol(defaultClientSymbol).Export()
+		assert.Same(t, mi.defaultClient, fetchClient)
+	})
+
+	t.Run("Abort", func(t *testing.T) {
+		t.Parallel()
+		ts := newFetchTestCase(t)
+		sr := ts.tb.Replacer.Replace
+
+		start := time.Now()
+		_, err := ts.runtime.RunOnEventLoop(wrapInAsyncLambda(sr(`
+			function newSignal() {
+				var listeners = [];
+				return {
+					aborted: false,
+					reason: undefined,
+					addEventListener(type, listener) { listeners.push(listener); },
+					removeEventListener(type, listener) { listeners = listeners.filter((l) => l !== listener); },
+					abort(reason) { this.aborted = true; this.reason = reason; listeners.forEach((l) => l()); },
+				};
+			}
+
+			var signal = newSignal();
+			signal.abort("already aborted");
+			var reason = await fetch("HTTPBIN_URL/get", {signal: signal}).catch((e) => e);
+			if (reason !== "already aborted") { throw new Error("wrong reason: " + reason); }
+
+			signal = newSignal();
+			var promise = fetch("HTTPBIN_URL/delay/10", {signal: signal});
+			signal.abort();
+			reason = await promise.catch((e) => e);
+			if (reason.name !== "AbortError") { throw new Error("wrong reason: " + reason); }
+		`)))
+		require.NoError(t, err)
+		assert.Less(t, time.Since(start), 5*time.Second)
+	})
+
+	t.Run("NetworkError", func(t *testing.T) {
+		t.Parallel()
+		ts := newFetchTestCase(t)
+
+		_, err := ts.runtime.RunOnEventLoop(wrapInAsyncLambda(`
+			var error = await fetch("http://127.0.0.1:1/").catch((e) => e);
+			if (!(error instanceof TypeError)) { throw new Error("wrong error: " + error); }
+
+			error = await fetch("not a url").catch((e) => e);
+			if (!(error instanceof TypeError)) { throw new Error("wrong error: " + error); }
+		`))
+		require.NoError(t, err)
+	})
+
+	t.Run("InitContext", func(t *testing.T) {
+		t.Parallel()
+		runtime := modulestest.NewRuntime(t)
+		require.NoError(t, SetupFetchGlobally(runtime.VU))
+
+		_, err := runtime.VU.Runtime().RunString(`fetch("http://example.com/")`)
+		require.ErrorContains(t, err, "Making http requests in the init context is not supported")
+	})
+}
@@ -46,13 +46,7 @@ func (r *RootModule) NewModuleInstance(vu modules.VU) modules.Instance {
 	}
 	mi.defineConstants()
 
-	mi.defaultClient = &Client{
-		// TODO: configure this from lib.Options and get rid of some of the
-		// things in the VU State struct that should be here. See
-		// https://github.com/grafana/k6/issues/2293
-		moduleInstance:   mi,
-		responseCallback: defaultExpectedStatuses.match,
-	}
+	mi.defaultClient = vuDefaultClient(mi)
 
 	mustExport := func(name string, value interface{}) {
 		if err := mi.exports.Set(name, value); err != nil {
@@ -102,6 +96,38 @@ func (r *RootModule) NewModuleInstance(vu modules.VU) modules.Instance {
 	return mi
 }
 
+// defaultClientSymbol is the key of the hidden property of the global object with the
+// default client of the VU, which is shared by the k6/http module and the fetch global.
+var defaultClientSymbol = sobek.NewSymbol("k6/http default client") //nolint:gochecknoglobals
+
+// vuDefaultClient returns the default client of the VU, creating it for the module
+// instance if it doesn't exist yet. The k6/http module and the fetch global use the
+// same one, so that e.g. http.setResponseCallback() applies to the fetch requests too.
+func vuDefaultClient(mi *ModuleInstance) *Client {
+	rt := mi.vu.Runtime()
+	global := rt.GlobalObject()
+	if v := global.GetSymbol(defaultClientSymbol); v != nil {
+		if c, ok := v.Export().(*Client); ok {
+			return c
+		}
+	}
+
+	c := &Client{
+		// TODO: configure this from lib.Options and get rid of some of the
+		// things in the VU State struct that should be here. See
+		// https://github.com/grafana/k6/issues/2293
+		moduleInstance:   mi,
+		responseCallback: defaultExpectedStatuses.match,
+	}
+	err := global.DefineDataPropertySymbol(
+		defaultClientSymbol, rt.ToValue(c), sobek.FLAG_FALSE, sobek.FLAG_FALSE, sobek.FLAG_FALSE,
+	)
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	return c
+}
+
 // Exports returns the JS values this module exports.
 func (mi *ModuleInstance) Exports() modules.Exports {
 	return modules.Exports{
@@ -15,6 +15,7 @@ import (
 	"github.com/grafana/sobek"
 	"gopkg.in/guregu/null.v3"
 
+	"go.k6.io/k6/internal/js/tc55/abort"
 	"go.k6.io/k6/js/common"
 	"go.k6.io/k6/lib/netext/httpext"
 	"go.k6.io/k6/lib/types"
@@ -83,7 +84,8 @@ func (c *Client) handleParseRequestError(err error) (*Response, error) {
 }
 
 // asyncRequest makes an http request of the provided `method` and returns a promise. All the networking is done off
-// the event loop and the returned promise will be resolved with the response or rejected with an error
+// the event loop and the returned promise will be resolved with the response or rejected with an error. The request
+// can be canceled with the AbortSignal of the `signal` param.
 func (c *Client) asyncRequest(method string, url sobek.Value, args ...sobek.Value) (*sobek.Promise, error) {
 	state := c.moduleInstance.vu.State()
 	if c.moduleInstance.vu.State() == nil {
@@ -104,11 +106,20 @@ func (c *Client) asyncRequest(method string, url sobek.Value, args ...sobek.Valu
 		return p, err
 	}
 
+	// The request is canceled when the signal is aborted, and then fails with its own error code
+	op, err := abort.Follow(rt, c.moduleInstance.vu.Context(), abort.SignalParam(rt, params))
+	if err != nil {
+		return p, reject(err)
+	}
+
 	callback := c.moduleInstance.vu.RegisterCallback()
 
 	go func() {
-		resp, err := httpext.MakeRequest(c.moduleInstance.vu.Context(), state, req)
+		resp, err := httpext.MakeRequest(op.Context(), state, req)
 		callback(func() error {
+			if doneErr := op.Done(); doneErr != nil {
+				return doneErr
+			}
 			if err != nil {
 				return reject(err)
 			}
@@ -166,6 +177,7 @@ func (c *Client) parseRequest(
 		},
 		Timeout:          60 * time.Second,
 		Throw:            state.Options.Throw.Bool,
+		HTTP3:            state.Options.HTTP3.Bool,
 		Redirects:        state.Options.MaxRedirects,
 		Cookies:          make(map[string]*httpext.HTTPRequestCookie),
 		ResponseCallback: c.responseCallback,
@@ -377,6 +389,8 @@ func (c *Client) parseRequest(
 				result.Timeout = t
 			case "throw":
 				result.Throw = params.Get(k).ToBoolean()
+			case "http3":
+				result.HTTP3 = params.Get(k).ToBoolean()
 			case "responseType":
 				responseType, err := httpext.ResponseTypeString(params.Get(k).String())
 				if err != nil {
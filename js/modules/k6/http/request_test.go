@@ -2401,3 +2401,60 @@ func TestGzipped204Response(t *testing.T) {
 			`))
 	assert.NoError(t, err)
 }
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestRequestHTTP3(t *testing.T) {
+	t.Parallel()
+	ts := newTestCase(t)
+	tb := ts.tb
+	rt := ts.runtime.VU.Runtime()
+	state := ts.runtime.VU.State()
+	sr := tb.Replacer.Replace
+
+	// The HTTP/3 transport itself is tested in httpext, this only checks
+	// that the requests are routed to it according to the option and params.
+	var http3Requests int
+	state.HTTP3Transport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		http3Requests++
+		res, err := tb.HTTPTransport.RoundTrip(req)
+		if err == nil {
+			res.Proto, res.ProtoMajor, res.ProtoMinor = "HTTP/3.0", 3, 0
+		}
+		return res, err
+	})
+
+	t.Run("Param", func(t *testing.T) {
+		http3Requests = 0
+		_, err := rt.RunString(sr(`
+			var res = http.get("HTTPSBIN_URL/get", { http3: true });
+			if (res.proto !== "HTTP/3.0") {
+				throw new Error("unexpected proto: " + res.proto);
+			}
+			res = http.get("HTTPSBIN_URL/get");
+			if (res.proto === "HTTP/3.0") {
+				throw new Error("unexpected HTTP/3 request");
+			}
+		`))
+		require.NoError(t, err)
+		assert.Equal(t, 1, http3Requests)
+	})
+
+	t.Run("Option", func(t *testing.T) {
+		http3Requests = 0
+		state.Options.HTTP3 = null.BoolFrom(true)
+		defer func() { state.Options.HTTP3 = null.Bool{} }()
+
+		_, err := rt.RunString(sr(`
+			http.get("HTTPSBIN_URL/get");
+			http.get("HTTPSBIN_URL/get", { http3: false });
+			http.get("HTTPBIN_URL/get"); // plain HTTP can't be made over HTTP/3
+		`))
+		require.NoError(t, err)
+		assert.Equal(t, 1, http3Requests)
+	})
+}
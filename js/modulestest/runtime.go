@@ -10,7 +10,10 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.k6.io/k6/internal/js/compiler"
 	"go.k6.io/k6/internal/js/eventloop"
+	"go.k6.io/k6/internal/js/tc55/abort"
+	"go.k6.io/k6/internal/js/tc55/encoding"
 	"go.k6.io/k6/internal/js/tc55/timers"
+	tc55url "go.k6.io/k6/internal/js/tc55/url"
 
 	"go.k6.io/k6/internal/js/modules/k6/webcrypto"
 	"go.k6.io/k6/internal/lib/testutils"
@@ -58,6 +61,9 @@ func NewRuntime(t testing.TB) *Runtime {
 		BuiltinMetrics: metrics.RegisterBuiltinMetrics(vu.InitEnvField.Registry),
 	}
 	require.NoError(t, timers.SetupGlobally(vu))
+	require.NoError(t, encoding.SetupGlobally(vu))
+	require.NoError(t, tc55url.SetupGlobally(vu))
+	require.NoError(t, abort.SetupGlobally(vu))
 	require.NoError(t, webcrypto.SetupGlobally(vu))
 	// let's cancel again in case it has changed
 	t.Cleanup(func() { result.CancelContext() })
@@ -167,6 +167,14 @@ type ExecutionState struct {
 	// The default 0 value is used to denote that the test hasn't ended yet.
 	endTime *int64
 
+	// The times at which the scenarios were actually started, after their
+	// startTime offsets, and the scenarios that were stopped before their
+	// regular end, e.g. through the REST API, with the test run durations at
+	// which they were stopped.
+	startedScenarios map[string]time.Time
+	stoppedScenarios map[string]time.Duration
+	scenariosMx      *sync.RWMutex
+
 	// Stuff related to pausing follows. Read the docs in ExecutionScheduler for
 	// more information regarding how pausing works in k6.
 	//
@@ -226,6 +234,9 @@ func NewExecutionState(
 		interruptedIterationsCount: new(uint64),
 		startTime:                  new(int64),
 		endTime:                    new(int64),
+		startedScenarios:           make(map[string]time.Time),
+		stoppedScenarios:           make(map[string]time.Duration),
+		scenariosMx:                new(sync.RWMutex),
 		currentPauseTime:           new(int64),
 		pauseStateLock:             sync.RWMutex{},
 		totalPausedDuration:        0, // Accessed only behind the pauseStateLock
@@ -359,6 +370,47 @@ func (es *ExecutionState) HasEnded() bool {
 	return atomic.LoadInt64(es.endTime) != 0
 }
 
+// MarkScenarioStarted records the current time as the time at which the
+// scenario with the given name was started. Only the first call for a scenario
+// is recorded.
+func (es *ExecutionState) MarkScenarioStarted(name string) {
+	es.scenariosMx.Lock()
+	defer es.scenariosMx.Unlock()
+	if _, ok := es.startedScenarios[name]; !ok {
+		es.startedScenarios[name] = time.Now()
+	}
+}
+
+// GetScenarioStartTime returns the time at which the scenario with the given
+// name was started, and whether it was started at all.
+func (es *ExecutionState) GetScenarioStartTime(name string) (time.Time, bool) {
+	es.scenariosMx.RLock()
+	defer es.scenariosMx.RUnlock()
+	startTime, ok := es.startedScenarios[name]
+	return startTime, ok
+}
+
+// MarkScenarioStopped records that the scenario with the given name was
+// stopped before its regular end, at the current test run duration. Only the
+// first call for a scenario is recorded.
+func (es *ExecutionState) MarkScenarioStopped(name string) {
+	es.scenariosMx.Lock()
+	defer es.scenariosMx.Unlock()
+	if _, ok := es.stoppedScenarios[name]; !ok {
+		es.stoppedScenarios[name] = es.GetCurrentTestRunDuration()
+	}
+}
+
+// GetScenarioStopTime returns the test run duration at which the scenario with
+// the given name was stopped, and whether it was stopped before its regular
+// end at all.
+func (es *ExecutionState) GetScenarioStopTime(name string) (time.Duration, bool) {
+	es.scenariosMx.RLock()
+	defer es.scenariosMx.RUnlock()
+	stopTime, ok := es.stoppedScenarios[name]
+	return stopTime, ok
+}
+
 // IsPaused quickly returns whether the test is currently paused, by reading
 // the atomic currentPauseTime timestamp
 func (es *ExecutionState) IsPaused() bool {
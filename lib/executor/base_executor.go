@@ -3,8 +3,11 @@ package executor
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
@@ -18,23 +21,30 @@ import (
 // inside of most of the executors, for the purpose of reducing boilerplate
 // code.
 type BaseExecutor struct {
+	configMx       *sync.RWMutex
 	config         lib.ExecutorConfig
 	executionState *lib.ExecutionState
 	iterSegIndexMx *sync.Mutex
 	iterSegIndex   *lib.SegmentedIndex
 	logger         *logrus.Entry
 	progress       *pb.ProgressBar
+
+	durationsMx *sync.Mutex
+	durations   *durationContexts // nil until the executor starts running
+	stopped     bool
 }
 
 // NewBaseExecutor returns an initialized BaseExecutor
 func NewBaseExecutor(config lib.ExecutorConfig, es *lib.ExecutionState, logger *logrus.Entry) *BaseExecutor {
 	segIdx := lib.NewSegmentedIndex(es.ExecutionTuple)
 	return &BaseExecutor{
+		configMx:       new(sync.RWMutex),
 		config:         config,
 		executionState: es,
 		logger:         logger,
 		iterSegIndexMx: new(sync.Mutex),
 		iterSegIndex:   segIdx,
+		durationsMx:    new(sync.Mutex),
 		progress: pb.New(
 			pb.WithLeft(config.GetName),
 			pb.WithLogger(logger),
@@ -56,11 +66,59 @@ func (bs *BaseExecutor) Init(_ context.Context) error {
 	return nil
 }
 
-// GetConfig returns the configuration with which this executor was launched.
+// GetConfig returns the configuration with which this executor was launched,
+// or the current one, if it was changed by a live update.
 func (bs *BaseExecutor) GetConfig() lib.ExecutorConfig {
+	bs.configMx.RLock()
+	defer bs.configMx.RUnlock()
 	return bs.config
 }
 
+// setConfig replaces the configuration returned by GetConfig after a live
+// update.
+func (bs *BaseExecutor) setConfig(config lib.ExecutorConfig) {
+	bs.configMx.Lock()
+	defer bs.configMx.Unlock()
+	bs.config = config
+}
+
+// Stop stops the scenario of the executor before its regular end. No new
+// iterations are started from now on, while the running ones get the
+// graceful stop period to finish. If the scenario hasn't started yet, it
+// won't start any iterations.
+func (bs *BaseExecutor) Stop() error {
+	bs.durationsMx.Lock()
+	defer bs.durationsMx.Unlock()
+	if bs.durations != nil && !bs.durations.setRegularDuration(time.Since(bs.durations.startTime)) {
+		return fmt.Errorf("scenario %s %w", bs.GetConfig().GetName(), errScenarioFinished)
+	}
+	bs.markStopped()
+	return nil
+}
+
+// setDurations changes the regular duration and the graceful stop period of
+// the running scenario, e.g. after its stages were changed by a live update.
+func (bs *BaseExecutor) setDurations(regularDuration, gracefulStop time.Duration) error {
+	bs.durationsMx.Lock()
+	defer bs.durationsMx.Unlock()
+	if bs.stopped {
+		return errors.New("it was already stopped")
+	}
+	if bs.durations == nil || !bs.durations.setDurations(regularDuration, gracefulStop) {
+		return errors.New("its regular duration is already over")
+	}
+	return nil
+}
+
+// markStopped records that the scenario was stopped before its regular end.
+// It has to be called with the durationsMx locked.
+func (bs *BaseExecutor) markStopped() {
+	if !bs.stopped {
+		bs.stopped = true
+		bs.executionState.MarkScenarioStopped(bs.GetConfig().GetName())
+	}
+}
+
 // GetLogger returns the executor logger entry.
 func (bs *BaseExecutor) GetLogger() *logrus.Entry {
 	return bs.logger
@@ -76,7 +134,7 @@ func (bs *BaseExecutor) GetProgress() *pb.ProgressBar {
 func (bs *BaseExecutor) getMetricTags(vuID *uint64) *metrics.TagSet {
 	tags := bs.executionState.Test.RunTags
 	if bs.executionState.Test.Options.SystemTags.Has(metrics.TagScenario) {
-		tags = tags.With("scenario", bs.config.GetName())
+		tags = tags.With("scenario", bs.GetConfig().GetName())
 	}
 	if vuID != nil && bs.executionState.Test.Options.SystemTags.Has(metrics.TagVU) {
 		tags = tags.With("vu", strconv.FormatUint(*vuID, 10))
@@ -0,0 +1,659 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/internal/ui/pb"
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/types"
+	"go.k6.io/k6/metrics"
+)
+
+const breakingPointType = "breaking-point"
+
+// The possible values of the onBreach option of the breaking-point executor
+const (
+	onBreachStop    = "stop"
+	onBreachBackoff = "backoff"
+)
+
+// metricsFlushDelay is how long the breaking-point executor waits for the
+// samples of the finished iterations to get to the metrics engine. It's longer
+// than the periods in which they are sent to the outputs and ingested.
+const metricsFlushDelay = 250 * time.Millisecond
+
+func init() {
+	lib.RegisterExecutorConfigType(
+		breakingPointType,
+		func(name string, rawJSON []byte) (lib.ExecutorConfig, error) {
+			config := NewBreakingPointConfig(name)
+			err := lib.StrictJSONUnmarshal(rawJSON, &config)
+			return config, err
+		},
+	)
+}
+
+// BreakingPointConfig stores the config for the breaking-point executor, which
+// raises the arrival rate step by step, until the conditions on the metrics of
+// a step aren't met anymore.
+type BreakingPointConfig struct {
+	BaseConfig
+	StartRate    null.Int           `json:"startRate"`
+	StepRate     null.Int           `json:"stepRate"`
+	MaxRate      null.Int           `json:"maxRate"`
+	TimeUnit     types.NullDuration `json:"timeUnit"`
+	StepDuration types.NullDuration `json:"stepDuration"`
+
+	// SettleTime is how long, at most, the iterations still running at the end
+	// of a step are waited for, before its conditions are evaluated. Their
+	// samples are emitted only when they finish, and at the breaking point they
+	// are the slowest ones, so no new iterations are started in the meantime.
+	SettleTime types.NullDuration `json:"settleTime"`
+
+	// Conditions have the same format as the thresholds and they are evaluated
+	// with only the metric samples of each step. Once any of them fails, the
+	// executor either stops, or backs off and halves the range between the
+	// highest sustainable rate and the lowest failing one, until it finds the
+	// exact rate.
+	Conditions map[string][]string `json:"conditions"`
+	OnBreach   null.String         `json:"onBreach"`
+
+	// Initialize `PreAllocatedVUs` number of VUs, and if more than that are needed,
+	// they will be dynamically allocated, until `MaxVUs` is reached, which is an
+	// absolutely hard limit on the number of VUs the executor will use
+	PreAllocatedVUs null.Int `json:"preAllocatedVUs"`
+	MaxVUs          null.Int `json:"maxVUs"`
+}
+
+// NewBreakingPointConfig returns a BreakingPointConfig with default values
+func NewBreakingPointConfig(name string) *BreakingPointConfig {
+	return &BreakingPointConfig{
+		BaseConfig: NewBaseConfig(name, breakingPointType),
+		TimeUnit:   types.NewNullDuration(1*time.Second, false),
+		SettleTime: types.NewNullDuration(5*time.Second, false),
+		OnBreach:   null.NewString(onBreachStop, false),
+	}
+}
+
+// Make sure we implement the lib.ExecutorConfig interface
+var _ lib.ExecutorConfig = &BreakingPointConfig{}
+
+// GetPreAllocatedVUs is just a helper method that returns the scaled pre-allocated VUs.
+func (bpc BreakingPointConfig) GetPreAllocatedVUs(et *lib.ExecutionTuple) int64 {
+	return et.ScaleInt64(bpc.PreAllocatedVUs.Int64)
+}
+
+// GetMaxVUs is just a helper method that returns the scaled max VUs.
+func (bpc BreakingPointConfig) GetMaxVUs(et *lib.ExecutionTuple) int64 {
+	return et.ScaleInt64(bpc.MaxVUs.Int64)
+}
+
+// getMaxSteps returns the number of steps it takes to raise the rate up to
+// maxRate and, with backoff, the number of steps it takes to halve the range
+// between the sustainable and the failing rates until it can't be split anymore.
+func (bpc BreakingPointConfig) getMaxSteps() int64 {
+	steps := (bpc.MaxRate.Int64-bpc.StartRate.Int64+bpc.StepRate.Int64-1)/bpc.StepRate.Int64 + 1
+	if bpc.OnBreach.String == onBreachBackoff {
+		widestRange := max(bpc.StepRate.Int64, bpc.StartRate.Int64)
+		steps += int64(bits.Len64(uint64(widestRange - 1))) //nolint:gosec
+	}
+	return steps
+}
+
+// getMaxDuration returns the duration of the executor, if it doesn't stop
+// before running all of its possible steps and each one of them takes the
+// whole settle time.
+func (bpc BreakingPointConfig) getMaxDuration() time.Duration {
+	return time.Duration(bpc.getMaxSteps()) * (bpc.StepDuration.TimeDuration() + bpc.SettleTime.TimeDuration())
+}
+
+// GetDescription returns a human-readable description of the executor options
+func (bpc BreakingPointConfig) GetDescription(et *lib.ExecutionTuple) string {
+	maxVUsRange := fmt.Sprintf("maxVUs: %d", et.ScaleInt64(bpc.PreAllocatedVUs.Int64))
+	if bpc.MaxVUs.Int64 > bpc.PreAllocatedVUs.Int64 {
+		maxVUsRange += fmt.Sprintf("-%d", et.ScaleInt64(bpc.MaxVUs.Int64))
+	}
+	timeUnit := bpc.TimeUnit.TimeDuration()
+	startRatePerSec, _ := getArrivalRatePerSec(getScaledArrivalRate(et.Segment, bpc.StartRate.Int64, timeUnit)).Float64()
+	maxRatePerSec, _ := getArrivalRatePerSec(getScaledArrivalRate(et.Segment, bpc.MaxRate.Int64, timeUnit)).Float64()
+
+	return fmt.Sprintf("%.2f to %.2f iterations/s in %s steps, while the conditions are met%s",
+		startRatePerSec, maxRatePerSec, bpc.StepDuration.Duration, bpc.getBaseInfo(maxVUsRange))
+}
+
+// Validate makes sure all options are configured and valid
+//
+//nolint:cyclop
+func (bpc *BreakingPointConfig) Validate() []error {
+	errs := bpc.BaseConfig.Validate()
+
+	if !bpc.StartRate.Valid {
+		errs = append(errs, errors.New("the startRate value isn't specified"))
+	} else if bpc.StartRate.Int64 <= 0 {
+		errs = append(errs, errors.New("the startRate value must be more than 0"))
+	}
+	if !bpc.StepRate.Valid {
+		errs = append(errs, errors.New("the stepRate value isn't specified"))
+	} else if bpc.StepRate.Int64 <= 0 {
+		errs = append(errs, errors.New("the stepRate value must be more than 0"))
+	}
+	if !bpc.MaxRate.Valid {
+		errs = append(errs, errors.New("the maxRate value isn't specified"))
+	} else if bpc.MaxRate.Int64 < bpc.StartRate.Int64 {
+		errs = append(errs, errors.New("maxRate can't be less than startRate"))
+	}
+
+	if bpc.TimeUnit.TimeDuration() <= 0 {
+		errs = append(errs, errors.New("the timeUnit must be more than 0"))
+	}
+	if !bpc.StepDuration.Valid {
+		errs = append(errs, errors.New("the stepDuration is unspecified"))
+	} else if bpc.StepDuration.TimeDuration() < minDuration {
+		errs = append(errs, fmt.Errorf(
+			"the stepDuration must be at least %s, but is %s", minDuration, bpc.StepDuration,
+		))
+	}
+	if bpc.SettleTime.TimeDuration() < 0 {
+		errs = append(errs, errors.New("the settleTime can't be negative"))
+	}
+
+	if len(bpc.Conditions) == 0 {
+		errs = append(errs, errors.New("at least one condition has to be specified"))
+	}
+	for name, sources := range bpc.Conditions {
+		if _, err := parseBreakingPointCondition(name, sources); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if bpc.OnBreach.String != onBreachStop && bpc.OnBreach.String != onBreachBackoff {
+		errs = append(errs, fmt.Errorf("the onBreach value must be either %q or %q", onBreachStop, onBreachBackoff))
+	}
+
+	if !bpc.PreAllocatedVUs.Valid {
+		errs = append(errs, errors.New("the number of preAllocatedVUs isn't specified"))
+	} else if bpc.PreAllocatedVUs.Int64 < 0 {
+		errs = append(errs, errors.New("the number of preAllocatedVUs can't be negative"))
+	}
+
+	if !bpc.MaxVUs.Valid {
+		// TODO: don't change the config while validating
+		bpc.MaxVUs.Int64 = bpc.PreAllocatedVUs.Int64
+	} else if bpc.MaxVUs.Int64 < bpc.PreAllocatedVUs.Int64 {
+		errs = append(errs, errors.New("maxVUs can't be less than preAllocatedVUs"))
+	}
+
+	return errs
+}
+
+// parseBreakingPointCondition returns the parsed thresholds of a condition.
+func parseBreakingPointCondition(name string, sources []string) (*metrics.Thresholds, error) {
+	if _, _, err := metrics.ParseMetricName(name); err != nil {
+		return nil, fmt.Errorf("invalid metric of the condition %q: %w", name, err)
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("the condition on metric %s has no expressions", name)
+	}
+	ts := metrics.NewThresholds(sources)
+	if err := ts.Parse(); err != nil {
+		return nil, fmt.Errorf("invalid condition on metric %s: %w", name, err)
+	}
+	if len(ts.Windows()) > 0 {
+		return nil, fmt.Errorf("the conditions on metric %s can't have time windows, "+
+			"they are always evaluated with the samples of each step", name)
+	}
+	return &ts, nil
+}
+
+// GetExecutionRequirements returns the number of required VUs to run the
+// executor for its whole duration (disregarding any startTime), including the
+// maximum waiting time for any iterations to gracefully stop. This is used by
+// the execution scheduler in its VU reservation calculations, so it knows how
+// many VUs to pre-initialize.
+func (bpc BreakingPointConfig) GetExecutionRequirements(et *lib.ExecutionTuple) []lib.ExecutionStep {
+	return []lib.ExecutionStep{
+		{
+			TimeOffset:      0,
+			PlannedVUs:      uint64(et.ScaleInt64(bpc.PreAllocatedVUs.Int64)),                                   //nolint:gosec
+			MaxUnplannedVUs: uint64(et.ScaleInt64(bpc.MaxVUs.Int64) - et.ScaleInt64(bpc.PreAllocatedVUs.Int64)), //nolint:gosec
+		}, {
+			TimeOffset:      bpc.getMaxDuration() + bpc.GracefulStop.TimeDuration(),
+			PlannedVUs:      0,
+			MaxUnplannedVUs: 0,
+		},
+	}
+}
+
+// NewExecutor creates a new BreakingPoint executor
+func (bpc BreakingPointConfig) NewExecutor(
+	es *lib.ExecutionState, logger *logrus.Entry,
+) (lib.Executor, error) {
+	conditions := make(map[string]*metrics.Thresholds, len(bpc.Conditions))
+	for name, sources := range bpc.Conditions {
+		ts, err := parseBreakingPointCondition(name, sources)
+		if err != nil {
+			return nil, err
+		}
+		conditions[name] = ts
+	}
+	return &BreakingPoint{
+		BaseExecutor: NewBaseExecutor(&bpc, es, logger),
+		config:       bpc,
+		conditions:   conditions,
+	}, nil
+}
+
+// HasWork reports whether there is any work to be done for the given execution segment.
+func (bpc BreakingPointConfig) HasWork(et *lib.ExecutionTuple) bool {
+	return bpc.GetMaxVUs(et) > 0
+}
+
+// BreakingPoint raises the arrival rate step by step, while the metric samples
+// of each step meet the conditions, to find the highest sustainable rate.
+//
+// Each k6 instance evaluates the conditions with its own metric samples, so in
+// a distributed test the instances could find different sustainable rates.
+type BreakingPoint struct {
+	*BaseExecutor
+	config     BreakingPointConfig
+	et         *lib.ExecutionTuple
+	conditions map[string]*metrics.Thresholds
+	observer   lib.MetricsObserver
+
+	// The unscaled rates, per timeUnit, of the current step and the highest
+	// one that met the conditions, or 0
+	currentRate, sustainableRate atomic.Int64
+}
+
+// Make sure we implement the lib.Executor and lib.MetricsObservingExecutor interfaces.
+var (
+	_ lib.Executor                 = &BreakingPoint{}
+	_ lib.MetricsObservingExecutor = &BreakingPoint{}
+)
+
+// SetMetricsObserver sets the observer of the metric samples of each step.
+func (bp *BreakingPoint) SetMetricsObserver(observer lib.MetricsObserver) {
+	bp.observer = observer
+}
+
+// Init values needed for the execution
+func (bp *BreakingPoint) Init(_ context.Context) error {
+	for name, ts := range bp.conditions {
+		if err := ts.Validate(name, bp.executionState.Test.Registry); err != nil {
+			return fmt.Errorf("invalid condition of scenario %s: %w", bp.config.Name, err)
+		}
+	}
+
+	// err should always be nil, because Init() won't be called for executors
+	// with no work, as determined by their config's HasWork() method.
+	et, err := bp.executionState.ExecutionTuple.GetNewExecutionTupleFromValue(bp.config.MaxVUs.Int64)
+	bp.et = et
+	bp.iterSegIndex = lib.NewSegmentedIndex(et)
+
+	return err
+}
+
+// GetSustainableRate returns the highest arrival rate, in iterations per
+// second, at which the metric samples of a step met all the conditions.
+func (bp *BreakingPoint) GetSustainableRate() (float64, bool) {
+	rate := bp.sustainableRate.Load()
+	if rate == 0 {
+		return 0, false
+	}
+	perSec, _ := getArrivalRatePerSec(big.NewRat(rate, int64(bp.config.TimeUnit.TimeDuration()))).Float64()
+	return perSec, true
+}
+
+// Run executes the steps of increasing arrival rates, until a condition fails
+// or maxRate is reached.
+//
+//nolint:funlen,gocognit,cyclop
+func (bp *BreakingPoint) Run(parentCtx context.Context, out chan<- metrics.SampleContainer) (err error) {
+	if bp.observer == nil {
+		return fmt.Errorf("the %s executor of scenario %s needs the metrics of the test run, "+
+			"but they aren't observed", breakingPointType, bp.config.Name)
+	}
+
+	gracefulStop := bp.config.GetGracefulStop()
+	stepDuration := bp.config.StepDuration.TimeDuration()
+	settleTime := bp.config.SettleTime.TimeDuration()
+	maxDuration := bp.config.getMaxDuration()
+	timeUnit := bp.config.TimeUnit.TimeDuration()
+	preAllocatedVUs := bp.config.GetPreAllocatedVUs(bp.executionState.ExecutionTuple)
+	maxVUs := bp.config.GetMaxVUs(bp.executionState.ExecutionTuple)
+	metricNames := make([]string, 0, len(bp.conditions))
+	for name := range bp.conditions {
+		metricNames = append(metricNames, name)
+	}
+	sort.Strings(metricNames)
+
+	// Make sure the log and the progress bar have accurate information
+	bp.logger.WithFields(logrus.Fields{
+		"maxVUs": maxVUs, "preAllocatedVUs": preAllocatedVUs, "stepDuration": stepDuration,
+		"maxDuration": maxDuration, "type": bp.config.GetType(),
+	}).Debug("Starting executor run...")
+
+	activeVUsWg := &sync.WaitGroup{}
+
+	returnedVUs := make(chan struct{})
+	waitOnProgressChannel := make(chan struct{})
+	startTime, maxDurationCtx, regDurationCtx, cancel := bp.getDurationContexts(parentCtx, maxDuration, gracefulStop)
+	defer func() {
+		cancel()
+		<-waitOnProgressChannel
+	}()
+
+	vusPool := newActiveVUPool(bp.executionState)
+	defer func() {
+		// Make sure all VUs aren't executing iterations anymore, for the cancel()
+		// below to deactivate them.
+		<-returnedVUs
+		// first close the vusPool so we wait for the gracefulShutdown
+		vusPool.Close()
+		cancel()
+		activeVUsWg.Wait()
+	}()
+	activeVUsCount := uint64(0)
+
+	search := &rateSearch{config: bp.config, rate: bp.config.StartRate.Int64}
+	var finished atomic.Bool
+	vusFmt := pb.GetFixedLengthIntFormat(maxVUs)
+	progressFn := func() (float64, []string) {
+		spent := time.Since(startTime)
+		currActiveVUs := atomic.LoadUint64(&activeVUsCount)
+		progVUs := fmt.Sprintf(vusFmt+"/"+vusFmt+" VUs",
+			vusPool.Running(), currActiveVUs)
+		ratePerSec, _ := getArrivalRatePerSec(
+			getScaledArrivalRate(bp.et.Segment, bp.currentRate.Load(), timeUnit),
+		).Float64()
+		progIters := fmt.Sprintf("%.2f iters/s", ratePerSec)
+
+		right := []string{progVUs, maxDuration.String(), progIters}
+
+		if finished.Load() || spent > maxDuration {
+			return 1, right
+		}
+
+		spentDuration := pb.GetFixedLengthDuration(spent, maxDuration)
+		progDur := fmt.Sprintf("%s/%s", spentDuration, maxDuration)
+		right[1] = progDur
+
+		return math.Min(1, float64(spent)/float64(maxDuration)), right
+	}
+	bp.progress.Modify(pb.WithProgress(progressFn))
+	maxDurationCtx = lib.WithScenarioState(maxDurationCtx, &lib.ScenarioState{
+		Name:              bp.config.Name,
+		Executor:          bp.config.Type,
+		StartTime:         startTime,
+		ProgressFn:        progressFn,
+		SustainableRateFn: bp.GetSustainableRate,
+	})
+
+	go func() {
+		trackProgress(parentCtx, maxDurationCtx, regDurationCtx, bp, progressFn)
+		close(waitOnProgressChannel)
+	}()
+
+	returnVU := func(u lib.InitializedVU) {
+		// Return the VU without decreasing the global active VU counter, which
+		// is done in the goroutine started by activeVUPool.AddVU, whenever the
+		// VU finishes running an iteration. This results in a more accurate
+		// report of VUs that are _actually_ active.
+		bp.executionState.ReturnVU(u, false)
+		activeVUsWg.Done()
+	}
+
+	runIterationBasic := getIterationRunner(bp.executionState, bp.logger)
+	activateVU := func(initVU lib.InitializedVU) lib.ActiveVU {
+		activeVUsWg.Add(1)
+		activeVU := initVU.Activate(getVUActivationParams(
+			maxDurationCtx, bp.config.BaseConfig, returnVU,
+			bp.nextIterationCounters,
+		))
+		atomic.AddUint64(&activeVUsCount, 1)
+		vusPool.AddVU(maxDurationCtx, activeVU, runIterationBasic)
+		return activeVU
+	}
+
+	remainingUnplannedVUs := maxVUs - preAllocatedVUs
+	makeUnplannedVUCh := make(chan struct{})
+	defer close(makeUnplannedVUCh)
+	go func() {
+		defer close(returnedVUs)
+		for range makeUnplannedVUCh {
+			bp.logger.Debug("Starting initialization of an unplanned VU...")
+			initVU, err := bp.executionState.GetUnplannedVU(maxDurationCtx, bp.logger)
+			if err != nil {
+				// TODO figure out how to return it to the Run goroutine
+				bp.logger.WithError(err).Error("Error while allocating unplanned VU")
+			} else {
+				bp.logger.Debug("The unplanned VU finished initializing successfully!")
+				activateVU(initVU)
+			}
+		}
+	}()
+
+	// Get the pre-allocated VUs in the local buffer
+	for i := int64(0); i < preAllocatedVUs; i++ {
+		initVU, err := bp.executionState.GetPlannedVU(bp.logger, false)
+		if err != nil {
+			return err
+		}
+		activateVU(initVU)
+	}
+
+	droppedIterationMetric := bp.executionState.Test.BuiltinMetrics.DroppedIterations
+	sustainableRateMetric := bp.executionState.Test.BuiltinMetrics.SustainableRate
+	shownWarning := false
+	metricTags := bp.getMetricTags(nil)
+	startIteration := func() {
+		if vusPool.TryRunIteration() {
+			return
+		}
+
+		// Since there aren't any free VUs available, consider this iteration
+		// dropped - we aren't going to try to recover it, but
+
+		metrics.PushIfNotDone(parentCtx, out, metrics.Sample{
+			TimeSeries: metrics.TimeSeries{
+				Metric: droppedIterationMetric,
+				Tags:   metricTags,
+			},
+			Time:  time.Now(),
+			Value: 1,
+		})
+
+		// We'll try to start allocating another VU in the background,
+		// non-blockingly, if we have remainingUnplannedVUs...
+		if remainingUnplannedVUs == 0 {
+			if !shownWarning {
+				bp.logger.Warningf("Insufficient VUs, reached %d active VUs and cannot initialize more", maxVUs)
+				shownWarning = true
+			}
+			return
+		}
+
+		select {
+		case makeUnplannedVUCh <- struct{}{}: // great!
+			remainingUnplannedVUs--
+		default: // we're already allocating a new VU
+		}
+	}
+
+	timer := time.NewTimer(time.Hour * 24)
+	defer timer.Stop()
+	waitUntil := func(t time.Time) bool {
+		timer.Reset(time.Until(t))
+		select {
+		case <-timer.C:
+			return true
+		case <-regDurationCtx.Done():
+			return false
+		}
+	}
+
+	// settle waits for the iterations that are still running at the end of a
+	// step and then for their samples, or until the settle time is over.
+	settle := func(stepEnd time.Time) bool {
+		settleEnd := stepEnd.Add(settleTime)
+		for vusPool.Running() > 0 && time.Until(settleEnd) > metricsFlushDelay {
+			if !waitUntil(time.Now().Add(10 * time.Millisecond)) {
+				return false
+			}
+		}
+		flushEnd := time.Now().Add(metricsFlushDelay)
+		if flushEnd.After(settleEnd) {
+			flushEnd = settleEnd
+		}
+		return waitUntil(flushEnd)
+	}
+
+	start, offsets, _ := bp.et.GetStripedOffsets()
+	stepStart := startTime
+	for {
+		rate := search.rate
+		bp.currentRate.Store(rate)
+		stopObservation, err := bp.observer.ObserveMetrics(metricNames)
+		if err != nil {
+			return err
+		}
+
+		// Every instance starts the iterations of its segment in each step, the
+		// same way the constant-arrival-rate executor does for its whole duration.
+		stepEnd := stepStart.Add(stepDuration)
+		notScaledTickerPeriod := getTickerPeriod(big.NewRat(rate, int64(timeUnit))).TimeDuration()
+		for li, gi := 0, start; ; li, gi = li+1, gi+offsets[li%len(offsets)] {
+			t := stepStart.Add(notScaledTickerPeriod * time.Duration(gi))
+			if !t.Before(stepEnd) {
+				break
+			}
+			if !waitUntil(t) {
+				stopObservation()
+				return nil
+			}
+			startIteration()
+		}
+		if !waitUntil(stepEnd) || !settle(stepEnd) {
+			stopObservation()
+			return nil
+		}
+
+		breached, err := bp.evaluateConditions(stopObservation(), stepDuration)
+		if err != nil {
+			return err
+		}
+		logger := bp.logger.WithField("rate", rate)
+		if len(breached) > 0 {
+			logger.WithField("conditions", breached).Debug("The conditions were breached")
+		} else {
+			logger.Debug("The conditions were met")
+			bp.sustainableRate.Store(rate)
+			ratePerSec, _ := bp.GetSustainableRate()
+			metrics.PushIfNotDone(parentCtx, out, metrics.Sample{
+				TimeSeries: metrics.TimeSeries{
+					Metric: sustainableRateMetric,
+					Tags:   metricTags,
+				},
+				Time:  time.Now(),
+				Value: ratePerSec,
+			})
+		}
+
+		if !search.next(len(breached) > 0) {
+			break
+		}
+		// The next step starts right after the settling, but never later than
+		// planned, so all of the steps fit in the max duration.
+		stepStart = time.Now()
+		if settleEnd := stepEnd.Add(settleTime); stepStart.After(settleEnd) {
+			stepStart = settleEnd
+		}
+	}
+
+	finished.Store(true)
+	if ratePerSec, ok := bp.GetSustainableRate(); ok {
+		bp.logger.Infof("The highest sustainable rate of scenario %s is %.2f iterations/s", bp.config.Name, ratePerSec)
+	} else {
+		bp.logger.Warnf("Scenario %s didn't meet its conditions even at its startRate", bp.config.Name)
+	}
+
+	// The regular duration is over earlier, so the iterations that are
+	// still running only have the gracefulStop to finish.
+	time.AfterFunc(gracefulStop, cancel)
+	return nil
+}
+
+// evaluateConditions returns the conditions that the metric samples of a step
+// have breached. The metrics without any samples in the step are ignored.
+func (bp *BreakingPoint) evaluateConditions(
+	sinks map[string]metrics.Sink, stepDuration time.Duration,
+) ([]string, error) {
+	var breached []string
+	for name, sink := range sinks {
+		if sink.IsEmpty() {
+			continue
+		}
+		ts := bp.conditions[name]
+		passed, err := ts.Run(sink, stepDuration)
+		if err != nil {
+			return nil, fmt.Errorf("evaluating the conditions on metric %s: %w", name, err)
+		}
+		if passed {
+			continue
+		}
+		for _, threshold := range ts.Thresholds {
+			if threshold.LastFailed {
+				breached = append(breached, name+": "+threshold.Source)
+			}
+		}
+	}
+	sort.Strings(breached)
+	return breached, nil
+}
+
+// rateSearch decides the arrival rate of each step of the breaking-point executor.
+type rateSearch struct {
+	config BreakingPointConfig
+
+	// rate is the one of the current step, the other two are 0 until a step
+	// meets the conditions or breaches them, respectively
+	rate, sustainable, breaching int64
+}
+
+// next moves to the rate of the next step, depending on whether the current
+// one breached the conditions. It returns false when the search is over.
+func (rs *rateSearch) next(breached bool) bool {
+	if breached {
+		if rs.config.OnBreach.String != onBreachBackoff {
+			return false
+		}
+		rs.breaching = rs.rate
+	} else {
+		rs.sustainable = rs.rate
+		if rs.breaching == 0 {
+			if rs.rate >= rs.config.MaxRate.Int64 {
+				return false
+			}
+			rs.rate = min(rs.rate+rs.config.StepRate.Int64, rs.config.MaxRate.Int64)
+			return true
+		}
+	}
+
+	if rs.breaching-rs.sustainable <= 1 {
+		return false
+	}
+	rs.rate = (rs.sustainable + rs.breaching) / 2
+	return true
+}
@@ -0,0 +1,252 @@
+package executor
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/types"
+	"go.k6.io/k6/metrics"
+)
+
+func getTestBreakingPointConfig(startRate, stepRate, maxRate int64, onBreach string) *BreakingPointConfig {
+	return &BreakingPointConfig{
+		BaseConfig:      BaseConfig{Name: "bpoint", Type: breakingPointType, GracefulStop: types.NullDurationFrom(0)},
+		StartRate:       null.IntFrom(startRate),
+		StepRate:        null.IntFrom(stepRate),
+		MaxRate:         null.IntFrom(maxRate),
+		TimeUnit:        types.NullDurationFrom(time.Second),
+		StepDuration:    types.NullDurationFrom(100 * time.Millisecond),
+		Conditions:      map[string][]string{"vus": {"value<25"}},
+		OnBreach:        null.StringFrom(onBreach),
+		PreAllocatedVUs: null.IntFrom(5),
+		MaxVUs:          null.IntFrom(5),
+	}
+}
+
+// rateObserver reports the rate of each step of the executor as the value
+// of all the observed metrics.
+type rateObserver struct {
+	bp    *BreakingPoint
+	mx    sync.Mutex
+	rates []int64
+}
+
+func (ro *rateObserver) ObserveMetrics(names []string) (func() map[string]metrics.Sink, error) {
+	rate := ro.bp.currentRate.Load()
+	ro.mx.Lock()
+	ro.rates = append(ro.rates, rate)
+	ro.mx.Unlock()
+
+	return func() map[string]metrics.Sink {
+		sinks := make(map[string]metrics.Sink, len(names))
+		for _, name := range names {
+			sink := metrics.NewSink(metrics.Gauge)
+			sink.Add(metrics.Sample{Time: time.Now(), Value: float64(rate)})
+			sinks[name] = sink
+		}
+		return sinks
+	}, nil
+}
+
+func TestBreakingPointRateSearch(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		config      *BreakingPointConfig
+		breakingAt  int64
+		rates       []int64
+		sustainable int64
+	}{
+		"stop": {
+			config:      getTestBreakingPointConfig(10, 10, 100, onBreachStop),
+			breakingAt:  35,
+			rates:       []int64{10, 20, 30, 40},
+			sustainable: 30,
+		},
+		"max rate": {
+			config:      getTestBreakingPointConfig(10, 20, 45, onBreachStop),
+			breakingAt:  100,
+			rates:       []int64{10, 30, 45},
+			sustainable: 45,
+		},
+		"backoff": {
+			config:      getTestBreakingPointConfig(10, 20, 100, onBreachBackoff),
+			breakingAt:  25,
+			rates:       []int64{10, 30, 20, 25, 22, 23, 24},
+			sustainable: 24,
+		},
+		"backoff from the start rate": {
+			config:      getTestBreakingPointConfig(20, 10, 100, onBreachBackoff),
+			breakingAt:  3,
+			rates:       []int64{20, 10, 5, 2, 3},
+			sustainable: 2,
+		},
+		"unsustainable": {
+			config:      getTestBreakingPointConfig(20, 10, 100, onBreachBackoff),
+			breakingAt:  1,
+			rates:       []int64{20, 10, 5, 2, 1},
+			sustainable: 0,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			rs := &rateSearch{config: *tc.config, rate: tc.config.StartRate.Int64}
+			rates := []int64{rs.rate}
+			for rs.next(rs.rate >= tc.breakingAt) {
+				rates = append(rates, rs.rate)
+			}
+			assert.Equal(t, tc.rates, rates)
+			assert.Equal(t, tc.sustainable, rs.sustainable)
+			assert.LessOrEqual(t, int64(len(rates)), tc.config.getMaxSteps())
+		})
+	}
+}
+
+func TestBreakingPointRun(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		config      *BreakingPointConfig
+		rates       []int64
+		sustainable []float64
+		// the highest sustainable rate that the iterations have seen, before the last step ended
+		seenByIterations float64
+	}{
+		"stop": {
+			config:           getTestBreakingPointConfig(10, 10, 100, onBreachStop),
+			rates:            []int64{10, 20, 30},
+			sustainable:      []float64{10, 20},
+			seenByIterations: 20,
+		},
+		"backoff": {
+			config:           getTestBreakingPointConfig(10, 20, 100, onBreachBackoff),
+			rates:            []int64{10, 30, 20, 25, 22, 23, 24},
+			sustainable:      []float64{10, 20, 22, 23, 24},
+			seenByIterations: 23,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var mx sync.Mutex
+			var seenRate float64
+			runner := simpleRunner(func(ctx context.Context, _ *lib.State) error {
+				if rate, ok := lib.GetScenarioState(ctx).SustainableRateFn(); ok {
+					mx.Lock()
+					seenRate = max(seenRate, rate)
+					mx.Unlock()
+				}
+				return nil
+			})
+
+			test := setupExecutorTest(t, "", "", lib.Options{}, runner, tc.config)
+			defer test.cancel()
+
+			bp := test.executor.(*BreakingPoint) //nolint:forcetypeassert
+			observer := &rateObserver{bp: bp}
+			bp.SetMetricsObserver(observer)
+
+			engineOut := make(chan metrics.SampleContainer, 1000)
+			require.NoError(t, test.executor.Run(test.ctx, engineOut))
+			close(engineOut)
+
+			assert.Equal(t, tc.rates, observer.rates)
+			rate, ok := bp.GetSustainableRate()
+			require.True(t, ok)
+			assert.Equal(t, tc.sustainable[len(tc.sustainable)-1], rate)
+
+			var sustainable []float64
+			for sc := range engineOut {
+				for _, s := range sc.GetSamples() {
+					if s.Metric.Name == metrics.SustainableRateName {
+						sustainable = append(sustainable, s.Value)
+					}
+				}
+			}
+			assert.Equal(t, tc.sustainable, sustainable)
+
+			mx.Lock()
+			defer mx.Unlock()
+			assert.Equal(t, tc.seenByIterations, seenRate)
+		})
+	}
+}
+
+// unfinishedObserver records how many of the started iterations weren't
+// finished yet at the end of each observation.
+type unfinishedObserver struct {
+	started, finished *atomic.Int64
+	mx                sync.Mutex
+	unfinished        []int64
+}
+
+func (uo *unfinishedObserver) ObserveMetrics(names []string) (func() map[string]metrics.Sink, error) {
+	return func() map[string]metrics.Sink {
+		uo.mx.Lock()
+		uo.unfinished = append(uo.unfinished, uo.started.Load()-uo.finished.Load())
+		uo.mx.Unlock()
+
+		sinks := make(map[string]metrics.Sink, len(names))
+		for _, name := range names {
+			sink := metrics.NewSink(metrics.Gauge)
+			sink.Add(metrics.Sample{Time: time.Now(), Value: 1})
+			sinks[name] = sink
+		}
+		return sinks
+	}, nil
+}
+
+func TestBreakingPointRunSettles(t *testing.T) {
+	t.Parallel()
+
+	var started, finished atomic.Int64
+	runner := simpleRunner(func(ctx context.Context, _ *lib.State) error {
+		started.Add(1)
+		defer finished.Add(1)
+		// longer than the step, like the slow tail at the breaking point
+		select {
+		case <-time.After(150 * time.Millisecond):
+		case <-ctx.Done():
+		}
+		return nil
+	})
+
+	config := getTestBreakingPointConfig(20, 10, 40, onBreachStop)
+	config.SettleTime = types.NullDurationFrom(time.Second)
+	test := setupExecutorTest(t, "", "", lib.Options{}, runner, config)
+	defer test.cancel()
+
+	bp := test.executor.(*BreakingPoint) //nolint:forcetypeassert
+	observer := &unfinishedObserver{started: &started, finished: &finished}
+	bp.SetMetricsObserver(observer)
+
+	require.NoError(t, test.executor.Run(test.ctx, make(chan metrics.SampleContainer, 1000)))
+
+	observer.mx.Lock()
+	defer observer.mx.Unlock()
+	assert.Equal(t, []int64{0, 0, 0}, observer.unfinished, "the steps were evaluated with running iterations")
+}
+
+func TestBreakingPointRunWithoutObserver(t *testing.T) {
+	t.Parallel()
+
+	runner := simpleRunner(func(_ context.Context, _ *lib.State) error { return nil })
+	test := setupExecutorTest(t, "", "", lib.Options{}, runner, getTestBreakingPointConfig(10, 10, 100, onBreachStop))
+	defer test.cancel()
+
+	err := test.executor.Run(test.ctx, make(chan metrics.SampleContainer, 100))
+	require.ErrorContains(t, err, "the breaking-point executor of scenario bpoint needs the metrics of the test run")
+}
@@ -2,6 +2,7 @@ package executor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"math/big"
@@ -43,6 +44,10 @@ type ConstantArrivalRateConfig struct {
 	// absolutely hard limit on the number of VUs the executor will use
 	PreAllocatedVUs null.Int `json:"preAllocatedVUs"`
 	MaxVUs          null.Int `json:"maxVUs"`
+
+	// The distribution of the times between the starts of the iterations,
+	// they are all the same by default
+	Distribution *ArrivalDistribution `json:"distribution,omitempty"`
 }
 
 // NewConstantArrivalRateConfig returns a ConstantArrivalRateConfig with default values
@@ -83,8 +88,13 @@ func (carc ConstantArrivalRateConfig) GetDescription(et *lib.ExecutionTuple) str
 		arrRatePerSec, _ = getArrivalRatePerSec(arrRate).Float64()
 	}
 
+	facts := []string{maxVUsRange}
+	if distribution := carc.Distribution.getDescription(); distribution != "" {
+		facts = append(facts, distribution)
+	}
+
 	return fmt.Sprintf("%.2f iterations/s for %s%s", arrRatePerSec, carc.Duration.Duration,
-		carc.getBaseInfo(maxVUsRange))
+		carc.getBaseInfo(facts...))
 }
 
 // Validate makes sure all options are configured and valid
@@ -121,6 +131,8 @@ func (carc *ConstantArrivalRateConfig) Validate() []error {
 		errors = append(errors, fmt.Errorf("maxVUs can't be less than preAllocatedVUs"))
 	}
 
+	errors = append(errors, carc.Distribution.Validate()...)
+
 	return errors
 }
 
@@ -150,6 +162,7 @@ func (carc ConstantArrivalRateConfig) NewExecutor(
 	return &ConstantArrivalRate{
 		BaseExecutor: NewBaseExecutor(&carc, es, logger),
 		config:       carc,
+		updates:      newScenarioUpdates(),
 	}, nil
 }
 
@@ -162,12 +175,18 @@ func (carc ConstantArrivalRateConfig) HasWork(et *lib.ExecutionTuple) bool {
 // specific period.
 type ConstantArrivalRate struct {
 	*BaseExecutor
-	config ConstantArrivalRateConfig
-	et     *lib.ExecutionTuple
+	config  ConstantArrivalRateConfig
+	et      *lib.ExecutionTuple
+	updates *scenarioUpdates
 }
 
-// Make sure we implement the lib.Executor interface.
-var _ lib.Executor = &ConstantArrivalRate{}
+// Make sure we implement the lib.Executor, lib.LiveUpdatableExecutor and
+// lib.StoppableExecutor interfaces.
+var (
+	_ lib.Executor              = &ConstantArrivalRate{}
+	_ lib.LiveUpdatableExecutor = &ConstantArrivalRate{}
+	_ lib.StoppableExecutor     = &ConstantArrivalRate{}
+)
 
 // Init values needed for the execution
 func (car *ConstantArrivalRate) Init(_ context.Context) error {
@@ -180,6 +199,16 @@ func (car *ConstantArrivalRate) Init(_ context.Context) error {
 	return err
 }
 
+// UpdateConfig changes the rate of the running scenario with a ScenarioUpdate.
+// The iterations after the change start at the new rate.
+func (car *ConstantArrivalRate) UpdateConfig(ctx context.Context, newConf interface{}) error {
+	update, ok := newConf.(ScenarioUpdate)
+	if !ok {
+		return errors.New("invalid config type")
+	}
+	return car.updates.send(ctx, car.config.Name, update)
+}
+
 // Run executes a constant number of iterations per second.
 //
 // TODO: Split this up and make an independent component that can be reused
@@ -212,7 +241,7 @@ func (car ConstantArrivalRate) Run(parentCtx context.Context, out chan<- metrics
 
 	returnedVUs := make(chan struct{})
 	waitOnProgressChannel := make(chan struct{})
-	startTime, maxDurationCtx, regDurationCtx, cancel := getDurationContexts(parentCtx, duration, gracefulStop)
+	startTime, maxDurationCtx, regDurationCtx, cancel := car.getDurationContexts(parentCtx, duration, gracefulStop)
 	defer func() {
 		cancel()
 		<-waitOnProgressChannel
@@ -231,15 +260,16 @@ func (car ConstantArrivalRate) Run(parentCtx context.Context, out chan<- metrics
 	activeVUsCount := uint64(0)
 
 	vusFmt := pb.GetFixedLengthIntFormat(maxVUs)
-	progIters := fmt.Sprintf(
-		pb.GetFixedLengthFloatFormat(arrivalRatePerSec, 2)+" iters/s", arrivalRatePerSec)
+	itersFmt := pb.GetFixedLengthFloatFormat(arrivalRatePerSec, 2) + " iters/s"
+	var progIters atomic.Value // the rate can be changed while the scenario runs
+	progIters.Store(fmt.Sprintf(itersFmt, arrivalRatePerSec))
 	progressFn := func() (float64, []string) {
 		spent := time.Since(startTime)
 		currActiveVUs := atomic.LoadUint64(&activeVUsCount)
 		progVUs := fmt.Sprintf(vusFmt+"/"+vusFmt+" VUs",
 			vusPool.Running(), currActiveVUs)
 
-		right := []string{progVUs, duration.String(), progIters}
+		right := []string{progVUs, duration.String(), progIters.Load().(string)} //nolint:forcetypeassert
 
 		if spent > duration {
 			return 1, right
@@ -315,54 +345,96 @@ func (car ConstantArrivalRate) Run(parentCtx context.Context, out chan<- metrics
 	start, offsets, _ := car.et.GetStripedOffsets()
 	timer := time.NewTimer(time.Hour * 24)
 	// here the we need the not scaled one
-	notScaledTickerPeriod := getTickerPeriod(
-		big.NewRat(
-			car.config.Rate.Int64,
-			int64(car.config.TimeUnit.TimeDuration()),
-		)).TimeDuration()
+	timeUnit := car.config.TimeUnit.TimeDuration()
+	notScaledTickerPeriod := getTickerPeriod(big.NewRat(car.config.Rate.Int64, int64(timeUnit))).TimeDuration()
+
+	// When the rate is changed, the iterations after that are scheduled from
+	// the time of the change, with the new period.
+	var (
+		rateStart time.Duration // the time since the start at which the rate was last changed
+		rateArea  float64       // the area of the arrivals at that time
+	)
+	changeRate := func(update ScenarioUpdate) error {
+		if update.hasStageChanges() {
+			return fmt.Errorf("%s scenarios don't have stages, only their rate can be changed", constantArrivalRateType)
+		}
+		if update.Rate.Int64 <= 0 {
+			return errors.New("the rate must be more than 0")
+		}
+		elapsed := time.Since(startTime)
+		rateArea += float64(elapsed-rateStart) / float64(notScaledTickerPeriod)
+		rateStart = elapsed
+		notScaledTickerPeriod = getTickerPeriod(big.NewRat(update.Rate.Int64, int64(timeUnit))).TimeDuration()
+
+		car.config.Rate = update.Rate
+		newConfig := car.config
+		car.setConfig(&newConfig)
+		ratePerSec, _ := getArrivalRatePerSec(
+			getScaledArrivalRate(car.et.Segment, update.Rate.Int64, timeUnit)).Float64()
+		progIters.Store(fmt.Sprintf(itersFmt, ratePerSec))
+		car.logger.WithField("rate", update.Rate.Int64).Debug("Changed the rate of the scenario")
+		return nil
+	}
+
+	arrivals := newArrivals(car.config.Distribution, car.config.Name)
+	_, stopUpdates := car.updates.start()
+	defer stopUpdates()
+	// waitFor waits until the iteration with the given global index should
+	// start, while applying the rate changes. It returns false if the regular
+	// duration is over before that.
+	waitFor := func(gi int64) bool {
+		for {
+			t := rateStart + arrivals.timeOf(gi, notScaledTickerPeriod) -
+				time.Duration(rateArea*float64(notScaledTickerPeriod))
+			timer.Reset(t - time.Since(startTime))
+			select {
+			case <-timer.C:
+				return true
+			case req := <-car.updates.requests:
+				req.result <- changeRate(req.update)
+			case <-regDurationCtx.Done():
+				return false
+			}
+		}
+	}
 
 	droppedIterationMetric := car.executionState.Test.BuiltinMetrics.DroppedIterations
 	shownWarning := false
 	metricTags := car.getMetricTags(nil)
 	for li, gi := 0, start; ; li, gi = li+1, gi+offsets[li%len(offsets)] {
-		t := notScaledTickerPeriod*time.Duration(gi) - time.Since(startTime)
-		timer.Reset(t)
-		select {
-		case <-timer.C:
-			if vusPool.TryRunIteration() {
-				continue
-			}
-
-			// Since there aren't any free VUs available, consider this iteration
-			// dropped - we aren't going to try to recover it, but
-
-			metrics.PushIfNotDone(parentCtx, out, metrics.Sample{
-				TimeSeries: metrics.TimeSeries{
-					Metric: droppedIterationMetric,
-					Tags:   metricTags,
-				},
-				Time:  time.Now(),
-				Value: 1,
-			})
-
-			// We'll try to start allocating another VU in the background,
-			// non-blockingly, if we have remainingUnplannedVUs...
-			if remainingUnplannedVUs == 0 {
-				if !shownWarning {
-					car.logger.Warningf("Insufficient VUs, reached %d active VUs and cannot initialize more", maxVUs)
-					shownWarning = true
-				}
-				continue
-			}
+		if !waitFor(gi) {
+			return nil
+		}
+		if vusPool.TryRunIteration() {
+			continue
+		}
 
-			select {
-			case makeUnplannedVUCh <- struct{}{}: // great!
-				remainingUnplannedVUs--
-			default: // we're already allocating a new VU
+		// Since there aren't any free VUs available, consider this iteration
+		// dropped - we aren't going to try to recover it, but
+
+		metrics.PushIfNotDone(parentCtx, out, metrics.Sample{
+			TimeSeries: metrics.TimeSeries{
+				Metric: droppedIterationMetric,
+				Tags:   metricTags,
+			},
+			Time:  time.Now(),
+			Value: 1,
+		})
+
+		// We'll try to start allocating another VU in the background,
+		// non-blockingly, if we have remainingUnplannedVUs...
+		if remainingUnplannedVUs == 0 {
+			if !shownWarning {
+				car.logger.Warningf("Insufficient VUs, reached %d active VUs and cannot initialize more", maxVUs)
+				shownWarning = true
 			}
+			continue
+		}
 
-		case <-regDurationCtx.Done():
-			return nil
+		select {
+		case makeUnplannedVUCh <- struct{}{}: // great!
+			remainingUnplannedVUs--
+		default: // we're already allocating a new VU
 		}
 	}
 }
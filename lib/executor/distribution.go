@@ -0,0 +1,211 @@
+package executor
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"sort"
+	"time"
+
+	"gopkg.in/guregu/null.v3"
+)
+
+// The supported types of arrival distributions
+const (
+	distributionUniform     = "uniform"
+	distributionPoisson     = "poisson"
+	distributionExponential = "exponential" // the same as poisson, for the times between the arrivals
+	distributionEmpirical   = "empirical"
+)
+
+// ArrivalDistribution configures how the times between the starts of the
+// iterations of the arrival-rate executors are distributed. Regardless of it,
+// the iterations start at the configured rate on average.
+//
+// With the uniform distribution, which is the default, the iterations start
+// at perfectly even intervals. With the poisson one, the intervals are
+// exponentially distributed, as they are for independent arrivals, like the
+// requests of many users. With the empirical one, they are distributed like
+// the values of the histogram, e.g. the intervals seen in production logs.
+type ArrivalDistribution struct {
+	Type string `json:"type"`
+	// The random intervals are the same in every test run with the same seed,
+	// by default it's derived from the scenario name. Every instance of a
+	// distributed test has to use the same one, so their iterations follow
+	// the same schedule.
+	Seed      null.Int          `json:"seed"`
+	Histogram []HistogramBucket `json:"histogram,omitempty"`
+}
+
+// HistogramBucket is a bucket of an empirical arrival distribution, with the
+// intervals from the UpTo value of the previous bucket, or 0 for the first
+// one, up to its own UpTo value. Only the relative sizes of the intervals
+// matter, as they are scaled to the rate of the executor.
+type HistogramBucket struct {
+	UpTo   float64 `json:"upTo"`
+	Weight float64 `json:"weight"`
+}
+
+// UnmarshalJSON accepts either a distribution object or just the name of its
+// type, e.g. "poisson".
+func (ad *ArrivalDistribution) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		return json.Unmarshal(data, &ad.Type)
+	}
+
+	type arrivalDistribution ArrivalDistribution // avoid the recursion
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode((*arrivalDistribution)(ad))
+}
+
+// getType returns the type of the distribution, which is uniform by default.
+func (ad *ArrivalDistribution) getType() string {
+	if ad == nil || ad.Type == "" {
+		return distributionUniform
+	}
+	return ad.Type
+}
+
+// Validate makes sure that the distribution is valid.
+func (ad *ArrivalDistribution) Validate() []error {
+	var errs []error
+	switch ad.getType() {
+	case distributionUniform, distributionPoisson, distributionExponential:
+		if ad != nil && len(ad.Histogram) > 0 {
+			errs = append(errs, fmt.Errorf("the histogram can only be used with the %s distribution", distributionEmpirical))
+		}
+	case distributionEmpirical:
+		errs = append(errs, validateHistogram(ad.Histogram)...)
+	default:
+		errs = append(errs, fmt.Errorf("unknown distribution %q, the supported ones are %s, %s and %s",
+			ad.Type, distributionUniform, distributionPoisson, distributionEmpirical))
+	}
+	return errs
+}
+
+func validateHistogram(histogram []HistogramBucket) []error {
+	if len(histogram) == 0 {
+		return []error{fmt.Errorf("the %s distribution needs a histogram", distributionEmpirical)}
+	}
+	var errs []error
+	var totalWeight float64
+	for i, bucket := range histogram {
+		if bucket.UpTo <= 0 || (i > 0 && bucket.UpTo <= histogram[i-1].UpTo) {
+			errs = append(errs, fmt.Errorf("the upTo value of histogram bucket %d must be more than 0 "+
+				"and more than the one of the previous bucket", i))
+		}
+		if bucket.Weight < 0 {
+			errs = append(errs, fmt.Errorf("the weight of histogram bucket %d can't be negative", i))
+		}
+		totalWeight += bucket.Weight
+	}
+	if totalWeight <= 0 {
+		errs = append(errs, errors.New("the weights of the histogram buckets must sum to more than 0"))
+	}
+	return errs
+}
+
+// getDescription returns a short description of the distribution, or an
+// empty string for the default uniform one.
+func (ad *ArrivalDistribution) getDescription() string {
+	t := ad.getType()
+	if t == distributionUniform {
+		return ""
+	}
+	if ad.Seed.Valid {
+		return fmt.Sprintf("distribution: %s, seed: %d", t, ad.Seed.Int64)
+	}
+	return "distribution: " + t
+}
+
+// newIntervalGenerator returns a function that generates the random
+// intervals between the arrivals, with a mean of 1. It returns nil for the
+// uniform distribution, since all of its intervals are exactly 1.
+func (ad *ArrivalDistribution) newIntervalGenerator(scenario string) func() float64 {
+	t := ad.getType()
+	if t == distributionUniform {
+		return nil
+	}
+
+	seed := ad.Seed.Int64
+	if !ad.Seed.Valid {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(scenario))
+		seed = int64(h.Sum64()) //nolint:gosec
+	}
+	// the generator of math/rand is stable for a given seed, so it's used
+	// instead of the one of math/rand/v2, even though it's not secure
+	r := rand.New(rand.NewSource(seed)) //nolint:gosec
+
+	if t != distributionEmpirical {
+		return r.ExpFloat64
+	}
+
+	// The intervals are uniformly distributed in the chosen bucket, so its
+	// mean is the middle of the bucket, and they're scaled to an overall mean of 1.
+	cumulative := make([]float64, len(ad.Histogram))
+	var total, mean, lower float64
+	for i, bucket := range ad.Histogram {
+		total += bucket.Weight
+		cumulative[i] = total
+		mean += bucket.Weight * (lower + bucket.UpTo) / 2
+		lower = bucket.UpTo
+	}
+	mean /= total
+
+	return func() float64 {
+		i := sort.SearchFloat64s(cumulative, r.Float64()*total)
+		for ad.Histogram[i].Weight == 0 { // the search returns the first bucket with the same cumulative weight
+			i++
+		}
+		var lower float64
+		if i > 0 {
+			lower = ad.Histogram[i-1].UpTo
+		}
+		return (lower + r.Float64()*(ad.Histogram[i].UpTo-lower)) / mean
+	}
+}
+
+// arrivals tracks the "areas" of the iterations, i.e. the number of iterations
+// that should have been started on average, at the time an iteration starts.
+//
+// With the uniform distribution, the area of the iteration with the global
+// index i is exactly i; with the others, it's the sum of the random intervals
+// of all the iterations before it. Every instance generates the intervals of
+// all iterations, not only of the ones in its execution segment, so the
+// iterations of all instances follow the same schedule.
+type arrivals struct {
+	interval func() float64
+	index    int64
+	area     float64
+}
+
+func newArrivals(distribution *ArrivalDistribution, scenario string) *arrivals {
+	return &arrivals{interval: distribution.newIntervalGenerator(scenario)}
+}
+
+// areaOf returns the area of the iteration with the given global index. It
+// has to be called with increasing indexes.
+func (a *arrivals) areaOf(index int64) float64 {
+	if a.interval == nil {
+		return float64(index)
+	}
+	for ; a.index < index; a.index++ {
+		a.area += a.interval()
+	}
+	return a.area
+}
+
+// timeOf returns the time at which the iteration with the given global index
+// should start, when the iterations start every period on average. It has to
+// be called with increasing indexes.
+func (a *arrivals) timeOf(index int64, period time.Duration) time.Duration {
+	if a.interval == nil {
+		return period * time.Duration(index)
+	}
+	return time.Duration(float64(period) * a.areaOf(index))
+}
@@ -0,0 +1,129 @@
+package executor
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/types"
+)
+
+func TestArrivalDistributionIntervals(t *testing.T) {
+	t.Parallel()
+
+	histogram := []HistogramBucket{{UpTo: 10, Weight: 1}, {UpTo: 20, Weight: 0}, {UpTo: 100, Weight: 3}}
+	testCases := map[string]struct {
+		distribution *ArrivalDistribution
+		min, max     float64
+	}{
+		"poisson":     {distribution: &ArrivalDistribution{Type: distributionPoisson}, min: 0, max: 100},
+		"exponential": {distribution: &ArrivalDistribution{Type: distributionExponential}, min: 0, max: 100},
+		"empirical": {
+			distribution: &ArrivalDistribution{Type: distributionEmpirical, Histogram: histogram},
+			// the mean of the histogram is (5*1 + 60*3) / 4 = 46.25
+			min: 0, max: 100 / 46.25,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			const count = 100000
+			interval := tc.distribution.newIntervalGenerator("scenario")
+			sameScenario := tc.distribution.newIntervalGenerator("scenario")
+			otherScenario := tc.distribution.newIntervalGenerator("other")
+			seeded := &ArrivalDistribution{Type: tc.distribution.Type, Seed: null.IntFrom(42), Histogram: histogram}
+
+			var sum float64
+			var differs bool
+			seededInterval, sameSeed := seeded.newIntervalGenerator("scenario"), seeded.newIntervalGenerator("other")
+			for range count {
+				v := interval()
+				require.Equal(t, v, sameScenario())
+				require.Equal(t, seededInterval(), sameSeed())
+				differs = differs || v != otherScenario()
+				require.GreaterOrEqual(t, v, tc.min)
+				require.LessOrEqual(t, v, tc.max)
+				sum += v
+			}
+			assert.True(t, differs)
+			assert.InDelta(t, 1, sum/count, 0.01)
+		})
+	}
+}
+
+func TestArrivalsAcrossSegments(t *testing.T) {
+	t.Parallel()
+
+	distribution := &ArrivalDistribution{Type: distributionPoisson}
+	full := newArrivals(distribution, "scenario")
+	expected := make([]time.Duration, 100)
+	for i := range expected {
+		expected[i] = full.timeOf(int64(i), time.Second)
+	}
+	assert.Equal(t, time.Duration(0), expected[0])
+	assert.True(t, sort.SliceIsSorted(expected, func(i, j int) bool { return expected[i] < expected[j] }))
+
+	seq := newExecutionSegmentSequenceFromString("0,1/4,1/2,1")
+	var got []time.Duration
+	for _, segment := range *seq {
+		et := mustNewExecutionTuple(segment, seq)
+		start, offsets, _ := et.GetStripedOffsets()
+		arrivals := newArrivals(distribution, "scenario")
+		for li, gi := 0, start; gi < int64(len(expected)); li, gi = li+1, gi+offsets[li%len(offsets)] {
+			got = append(got, arrivals.timeOf(gi, time.Second))
+		}
+	}
+	sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+	assert.Equal(t, expected, got)
+}
+
+func TestRampingArrivalRateCalDistribution(t *testing.T) {
+	t.Parallel()
+
+	config := RampingArrivalRateConfig{
+		BaseConfig: BaseConfig{Name: "varrival"},
+		TimeUnit:   types.NullDurationFrom(time.Second),
+		StartRate:  null.IntFrom(0),
+		Stages: []Stage{
+			{Duration: types.NullDurationFrom(10 * time.Second), Target: null.IntFrom(100)},
+			{Duration: types.NullDurationFrom(10 * time.Second), Target: null.IntFrom(100)},
+		},
+		Distribution: &ArrivalDistribution{Type: distributionPoisson},
+	}
+	getTimes := func(config RampingArrivalRateConfig, et *lib.ExecutionTuple) []time.Duration {
+		ch := make(chan time.Duration)
+		go config.cal(et, ch)
+		var times []time.Duration
+		for d := range ch {
+			times = append(times, d)
+		}
+		return times
+	}
+
+	expected := getTimes(config, mustNewExecutionTuple(nil, nil))
+	// 500 iterations during the ramp-up and 1000 more during the constant stage, on average
+	assert.InDelta(t, 1500, len(expected), 150)
+	assert.Less(t, expected[len(expected)-1], 20*time.Second)
+	assert.Equal(t, expected, getTimes(config, mustNewExecutionTuple(nil, nil)))
+
+	uniform := config
+	uniform.Distribution = nil
+	uniformTimes := getTimes(uniform, mustNewExecutionTuple(nil, nil))
+	assert.Len(t, uniformTimes, 1500)
+	assert.NotEqual(t, uniformTimes, expected)
+
+	seq := newExecutionSegmentSequenceFromString("0,1/3,2/3,1")
+	var got []time.Duration
+	for _, segment := range *seq {
+		got = append(got, getTimes(config, mustNewExecutionTuple(segment, seq))...)
+	}
+	sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+	assert.Equal(t, expected, got)
+}
@@ -358,6 +358,42 @@ var configMapTestCases = []configMapTestCase{
 	{`{"carrival": {"executor": "constant-arrival-rate", "rate": 10, "duration": "10m", "preAllocatedVUs": 20, "maxVUs": 15}}`, exp{validationError: true}},
 	{`{"carrival": {"executor": "constant-arrival-rate", "rate": 10, "duration": "0s", "preAllocatedVUs": 20, "maxVUs": 25}}`, exp{validationError: true}},
 	{`{"carrival": {"executor": "constant-arrival-rate", "rate": 10, "duration": "10m", "preAllocatedVUs": -2, "maxVUs": 25}}`, exp{validationError: true}},
+	{
+		`{"carrival": {"executor": "constant-arrival-rate", "rate": 10, "duration": "10m", "preAllocatedVUs": 20, "distribution": "poisson"}}`,
+		exp{custom: func(t *testing.T, cm lib.ScenarioConfigs) {
+			assert.Empty(t, cm["carrival"].Validate())
+			assert.Equal(t, &ArrivalDistribution{Type: "poisson"}, cm["carrival"].(*ConstantArrivalRateConfig).Distribution)
+
+			et, err := lib.NewExecutionTuple(nil, nil)
+			require.NoError(t, err)
+			assert.Equal(t, "10.00 iterations/s for 10m0s (maxVUs: 20, distribution: poisson, gracefulStop: 30s)", cm["carrival"].GetDescription(et))
+		}},
+	},
+	{
+		`{"carrival": {"executor": "constant-arrival-rate", "rate": 10, "duration": "10m", "preAllocatedVUs": 20,
+		"distribution": {"type": "empirical", "seed": 42, "histogram": [{"upTo": 0.1, "weight": 3}, {"upTo": 1, "weight": 1}]}}}`,
+		exp{custom: func(t *testing.T, cm lib.ScenarioConfigs) {
+			assert.Empty(t, cm["carrival"].Validate())
+			assert.Equal(t, &ArrivalDistribution{
+				Type:      "empirical",
+				Seed:      null.IntFrom(42),
+				Histogram: []HistogramBucket{{UpTo: 0.1, Weight: 3}, {UpTo: 1, Weight: 1}},
+			}, cm["carrival"].(*ConstantArrivalRateConfig).Distribution)
+
+			et, err := lib.NewExecutionTuple(nil, nil)
+			require.NoError(t, err)
+			assert.Equal(t, "10.00 iterations/s for 10m0s (maxVUs: 20, distribution: empirical, seed: 42, gracefulStop: 30s)", cm["carrival"].GetDescription(et))
+		}},
+	},
+	{`{"carrival": {"executor": "constant-arrival-rate", "rate": 10, "duration": "10m", "preAllocatedVUs": 20, "distribution": "uniform"}}`, exp{}},
+	{`{"carrival": {"executor": "constant-arrival-rate", "rate": 10, "duration": "10m", "preAllocatedVUs": 20, "distribution": {"type": "exponential", "seed": 1}}}`, exp{}},
+	{`{"carrival": {"executor": "constant-arrival-rate", "rate": 10, "duration": "10m", "preAllocatedVUs": 20, "distribution": "normal"}}`, exp{validationError: true}},
+	{`{"carrival": {"executor": "constant-arrival-rate", "rate": 10, "duration": "10m", "preAllocatedVUs": 20, "distribution": {"type": "poisson", "mean": 1}}}`, exp{parseError: true}},
+	{`{"carrival": {"executor": "constant-arrival-rate", "rate": 10, "duration": "10m", "preAllocatedVUs": 20, "distribution": {"type": "poisson", "histogram": [{"upTo": 1, "weight": 1}]}}}`, exp{validationError: true}},
+	{`{"carrival": {"executor": "constant-arrival-rate", "rate": 10, "duration": "10m", "preAllocatedVUs": 20, "distribution": "empirical"}}`, exp{validationError: true}},
+	{`{"carrival": {"executor": "constant-arrival-rate", "rate": 10, "duration": "10m", "preAllocatedVUs": 20, "distribution": {"type": "empirical", "histogram": [{"upTo": 2, "weight": 1}, {"upTo": 1, "weight": 1}]}}}`, exp{validationError: true}},
+	{`{"carrival": {"executor": "constant-arrival-rate", "rate": 10, "duration": "10m", "preAllocatedVUs": 20, "distribution": {"type": "empirical", "histogram": [{"upTo": 1, "weight": -1}, {"upTo": 2, "weight": 2}]}}}`, exp{validationError: true}},
+	{`{"carrival": {"executor": "constant-arrival-rate", "rate": 10, "duration": "10m", "preAllocatedVUs": 20, "distribution": {"type": "empirical", "histogram": [{"upTo": 1, "weight": 0}]}}}`, exp{validationError: true}},
 	// ramping-arrival-rate
 	{
 		`{"varrival": {"executor": "ramping-arrival-rate", "startRate": 10, "timeUnit": "30s", "preAllocatedVUs": 20,
@@ -408,6 +444,110 @@ var configMapTestCases = []configMapTestCase{
 	{`{"varrival": {"executor": "ramping-arrival-rate", "preAllocatedVUs": 20, "maxVUs": 50, "stages": [{"duration": "5m", "target": 10}], "timeUnit": "-1s"}}`, exp{validationError: true}},
 	{`{"varrival": {"executor": "ramping-arrival-rate", "preAllocatedVUs": 20, "maxVUs": 50, "stages": [{"duration": "5m", "target": 10}], "timeUnit": "0s"}}`, exp{validationError: true}},
 	{`{"varrival": {"executor": "ramping-arrival-rate", "preAllocatedVUs": 30, "maxVUs": 20, "stages": [{"duration": "5m", "target": 10}]}}`, exp{validationError: true}},
+	{
+		`{"varrival": {"executor": "ramping-arrival-rate", "preAllocatedVUs": 20, "stages": [{"duration": "5m", "target": 10}], "distribution": {"type": "poisson", "seed": 7}}}`,
+		exp{custom: func(t *testing.T, cm lib.ScenarioConfigs) {
+			assert.Empty(t, cm["varrival"].Validate())
+			assert.Equal(t, &ArrivalDistribution{Type: "poisson", Seed: null.IntFrom(7)}, cm["varrival"].(*RampingArrivalRateConfig).Distribution)
+
+			et, err := lib.NewExecutionTuple(nil, nil)
+			require.NoError(t, err)
+			assert.Equal(t, "Up to 10.00 iterations/s for 5m0s over 1 stages (maxVUs: 20, distribution: poisson, seed: 7, gracefulStop: 30s)", cm["varrival"].GetDescription(et))
+		}},
+	},
+	{`{"varrival": {"executor": "ramping-arrival-rate", "preAllocatedVUs": 20, "stages": [{"duration": "5m", "target": 10}], "distribution": "exponential"}}`, exp{}},
+	{`{"varrival": {"executor": "ramping-arrival-rate", "preAllocatedVUs": 20, "stages": [{"duration": "5m", "target": 10}], "distribution": "pareto"}}`, exp{validationError: true}},
+	{`{"varrival": {"executor": "ramping-arrival-rate", "preAllocatedVUs": 20, "stages": [{"duration": "5m", "target": 10}], "distribution": {"type": "empirical"}}}`, exp{validationError: true}},
+
+	// breaking-point
+	{
+		`{"bpoint": {"executor": "breaking-point", "startRate": 10, "stepRate": 20, "maxRate": 100, "stepDuration": "1m",
+		"preAllocatedVUs": 20, "maxVUs": 50, "conditions": {"http_req_duration{status:200}": ["p(95)<500"]}, "onBreach": "backoff"}}`,
+		exp{custom: func(t *testing.T, cm lib.ScenarioConfigs) {
+			et, err := lib.NewExecutionTuple(nil, nil)
+			require.NoError(t, err)
+			assert.Empty(t, cm["bpoint"].Validate())
+			assert.Equal(t, "10.00 to 100.00 iterations/s in 1m0s steps, while the conditions are met "+
+				"(maxVUs: 20-50, gracefulStop: 30s)", cm["bpoint"].GetDescription(et))
+
+			// 6 steps up to the maxRate and 5 more to halve the range of 20 iterations/s,
+			// which can each take the default settleTime of 5s more
+			schedReqs := cm["bpoint"].GetExecutionRequirements(et)
+			endOffset, isFinal := lib.GetEndOffset(schedReqs)
+			assert.Equal(t, 12*time.Minute+25*time.Second, endOffset)
+			assert.Equal(t, true, isFinal)
+			assert.Equal(t, uint64(20), lib.GetMaxPlannedVUs(schedReqs))
+			assert.Equal(t, uint64(50), lib.GetMaxPossibleVUs(schedReqs))
+		}},
+	},
+	{
+		`{"bpoint": {"executor": "breaking-point", "startRate": 10, "stepRate": 10, "maxRate": 100, "stepDuration": "1m",
+		"settleTime": "0s", "preAllocatedVUs": 20, "conditions": {"http_req_failed": ["rate<0.01"]}}}`,
+		exp{custom: func(t *testing.T, cm lib.ScenarioConfigs) {
+			et, err := lib.NewExecutionTuple(nil, nil)
+			require.NoError(t, err)
+			assert.Empty(t, cm["bpoint"].Validate())
+			config := cm["bpoint"].(*BreakingPointConfig) //nolint:forcetypeassert
+			require.EqualValues(t, 20, config.MaxVUs.Int64)
+			assert.Equal(t, "stop", config.OnBreach.String)
+			endOffset, _ := lib.GetEndOffset(config.GetExecutionRequirements(et))
+			assert.Equal(t, 10*time.Minute+30*time.Second, endOffset)
+		}},
+	},
+	{`{"bpoint": {"executor": "breaking-point", "startRate": 10, "stepRate": 10, "maxRate": 100, "stepDuration": "1m", "preAllocatedVUs": 20}}`, exp{validationError: true}},
+	{`{"bpoint": {"executor": "breaking-point", "startRate": 10, "stepRate": 10, "maxRate": 100, "stepDuration": "1m", "preAllocatedVUs": 20, "conditions": {"checks": []}}}`, exp{validationError: true}},
+	{`{"bpoint": {"executor": "breaking-point", "startRate": 10, "stepRate": 10, "maxRate": 100, "stepDuration": "1m", "preAllocatedVUs": 20, "conditions": {"checks": ["rate>"]}}}`, exp{validationError: true}},
+	{`{"bpoint": {"executor": "breaking-point", "startRate": 10, "stepRate": 10, "maxRate": 100, "stepDuration": "1m", "preAllocatedVUs": 20, "conditions": {"checks": ["rate over 1m>0.9"]}}}`, exp{validationError: true}},
+	{`{"bpoint": {"executor": "breaking-point", "startRate": 10, "stepRate": 10, "maxRate": 100, "stepDuration": "1m", "preAllocatedVUs": 20, "conditions": {"checks": ["rate>0.9"]}, "onBreach": "retry"}}`, exp{validationError: true}},
+	{`{"bpoint": {"executor": "breaking-point", "startRate": 10, "stepRate": 10, "maxRate": 100, "stepDuration": "1m", "settleTime": "-1s", "preAllocatedVUs": 20, "conditions": {"checks": ["rate>0.9"]}}}`, exp{validationError: true}},
+	{`{"bpoint": {"executor": "breaking-point", "startRate": 10, "stepRate": 0, "maxRate": 100, "stepDuration": "1m", "preAllocatedVUs": 20, "conditions": {"checks": ["rate>0.9"]}}}`, exp{validationError: true}},
+	{`{"bpoint": {"executor": "breaking-point", "startRate": 10, "stepRate": 10, "maxRate": 5, "stepDuration": "1m", "preAllocatedVUs": 20, "conditions": {"checks": ["rate>0.9"]}}}`, exp{validationError: true}},
+	{`{"bpoint": {"executor": "breaking-point", "startRate": 10, "stepRate": 10, "maxRate": 100, "preAllocatedVUs": 20, "conditions": {"checks": ["rate>0.9"]}}}`, exp{validationError: true}},
+	{`{"bpoint": {"executor": "breaking-point", "startRate": 10, "stepRate": 10, "maxRate": 100, "stepDuration": "1m", "conditions": {"checks": ["rate>0.9"]}}}`, exp{validationError: true}},
+	// replay
+	{
+		`{"replay": {"executor": "replay", "file": "./logs/access.csv", "speed": 2, "maxDuration": "30m", "preAllocatedVUs": 20, "maxVUs": 50}}`,
+		exp{custom: func(t *testing.T, cm lib.ScenarioConfigs) {
+			et, err := lib.NewExecutionTuple(nil, nil)
+			require.NoError(t, err)
+			assert.Empty(t, cm["replay"].Validate())
+			config := cm["replay"].(*ReplayConfig) //nolint:forcetypeassert
+			assert.Equal(t, "csv", config.getFormat())
+			assert.Equal(t, "timestamp", config.TimestampField.String)
+			assert.Equal(t, "Replay of the records in ./logs/access.csv at 2x speed for up to 30m0s "+
+				"(maxVUs: 20-50, gracefulStop: 30s)", config.GetDescription(et))
+
+			schedReqs := config.GetExecutionRequirements(et)
+			endOffset, isFinal := lib.GetEndOffset(schedReqs)
+			assert.Equal(t, 30*time.Minute+30*time.Second, endOffset)
+			assert.Equal(t, true, isFinal)
+			assert.Equal(t, uint64(20), lib.GetMaxPlannedVUs(schedReqs))
+			assert.Equal(t, uint64(50), lib.GetMaxPossibleVUs(schedReqs))
+		}},
+	},
+	{
+		`{"replay": {"executor": "replay", "file": "access.log", "format": "jsonl", "timestampField": "time", "preAllocatedVUs": 20}}`,
+		exp{custom: func(t *testing.T, cm lib.ScenarioConfigs) {
+			et, err := lib.NewExecutionTuple(nil, nil)
+			require.NoError(t, err)
+			assert.Empty(t, cm["replay"].Validate())
+			config := cm["replay"].(*ReplayConfig) //nolint:forcetypeassert
+			require.EqualValues(t, 20, config.MaxVUs.Int64)
+			assert.Equal(t, "jsonl", config.getFormat())
+			assert.Equal(t, "Replay of the records in access.log for up to 10m0s (maxVUs: 20, gracefulStop: 30s)",
+				config.GetDescription(et))
+		}},
+	},
+	{`{"replay": {"executor": "replay", "file": "access.ndjson", "preAllocatedVUs": 20}}`, exp{}},
+	{`{"replay": {"executor": "replay", "preAllocatedVUs": 20}}`, exp{validationError: true}},
+	{`{"replay": {"executor": "replay", "file": "access.log", "preAllocatedVUs": 20}}`, exp{validationError: true}},
+	{`{"replay": {"executor": "replay", "file": "access.csv", "format": "xml", "preAllocatedVUs": 20}}`, exp{validationError: true}},
+	{`{"replay": {"executor": "replay", "file": "access.csv", "timestampField": "", "preAllocatedVUs": 20}}`, exp{validationError: true}},
+	{`{"replay": {"executor": "replay", "file": "access.csv", "speed": 0, "preAllocatedVUs": 20}}`, exp{validationError: true}},
+	{`{"replay": {"executor": "replay", "file": "access.csv", "maxDuration": "0s", "preAllocatedVUs": 20}}`, exp{validationError: true}},
+	{`{"replay": {"executor": "replay", "file": "access.csv"}}`, exp{validationError: true}},
+	{`{"replay": {"executor": "replay", "file": "access.csv", "preAllocatedVUs": 20, "maxVUs": 10}}`, exp{validationError: true}},
+	{`{"replay": {"executor": "replay", "file": "access.csv", "preAllocatedVUs": 20, "rate": 10}}`, exp{parseError: true}},
 	// TODO: more tests of mixed executors and execution plans
 
 	// scenario options
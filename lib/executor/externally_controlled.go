@@ -241,6 +241,12 @@ func (mex *ExternallyControlled) SetPaused(paused bool) error {
 	}
 }
 
+// Stop isn't supported by the externally controlled executor, its VUs can be
+// scaled down to 0 or the whole test can be stopped instead.
+func (mex *ExternallyControlled) Stop() error {
+	return fmt.Errorf("the %s scenario %s can't be stopped on its own", externallyControlledType, mex.config.Name)
+}
+
 // UpdateConfig validates the supplied config and updates it in real time. It is
 // possible to update the configuration even when k6 is paused, either in the
 // beginning (i.e. when running k6 with --paused) or in the middle of the script
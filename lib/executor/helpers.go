@@ -165,18 +165,20 @@ func getIterationRunner(
 //   - If the whole test is aborted, the parent context will be cancelled, so
 //     that will also cancel these contexts, thus the "general abort" case is
 //     handled transparently.
-func getDurationContexts(parentCtx context.Context, regularDuration, gracefulStop time.Duration) (
+//
+// The regular duration can be changed while the executor runs, e.g. when its
+// scenario is stopped early with Stop(). If that already happened before the
+// executor started, the regular duration is 0.
+func (bs *BaseExecutor) getDurationContexts(parentCtx context.Context, regularDuration, gracefulStop time.Duration) (
 	startTime time.Time, maxDurationCtx, regDurationCtx context.Context, maxDurationCancel func(),
 ) {
-	startTime = time.Now()
-	maxEndTime := startTime.Add(regularDuration + gracefulStop)
-
-	maxDurationCtx, maxDurationCancel = context.WithDeadline(parentCtx, maxEndTime)
-	if gracefulStop == 0 {
-		return startTime, maxDurationCtx, maxDurationCtx, maxDurationCancel
+	bs.durationsMx.Lock()
+	defer bs.durationsMx.Unlock()
+	if bs.stopped {
+		regularDuration = 0
 	}
-	regDurationCtx, _ = context.WithDeadline(maxDurationCtx, startTime.Add(regularDuration)) //nolint:govet
-	return startTime, maxDurationCtx, regDurationCtx, maxDurationCancel
+	bs.durations = newDurationContexts(parentCtx, regularDuration, gracefulStop)
+	return bs.durations.startTime, bs.durations.maxCtx, bs.durations.regCtx, bs.durations.cancel
 }
 
 // trackProgress is a helper function that monitors certain end-events in an
@@ -139,7 +139,7 @@ func (pvi PerVUIterations) Run(parentCtx context.Context, out chan<- metrics.Sam
 	gracefulStop := pvi.config.GetGracefulStop()
 
 	waitOnProgressChannel := make(chan struct{})
-	startTime, maxDurationCtx, regDurationCtx, cancel := getDurationContexts(parentCtx, duration, gracefulStop)
+	startTime, maxDurationCtx, regDurationCtx, cancel := pvi.getDurationContexts(parentCtx, duration, gracefulStop)
 	defer func() {
 		cancel()
 		<-waitOnProgressChannel
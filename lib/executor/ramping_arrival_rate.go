@@ -2,6 +2,7 @@ package executor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"sync"
@@ -43,6 +44,10 @@ type RampingArrivalRateConfig struct {
 	// absolutely hard limit on the number of VUs the executor will use
 	PreAllocatedVUs null.Int `json:"preAllocatedVUs"`
 	MaxVUs          null.Int `json:"maxVUs"`
+
+	// The distribution of the times between the starts of the iterations,
+	// they are all the same for the same rate by default
+	Distribution *ArrivalDistribution `json:"distribution,omitempty"`
 }
 
 // NewRampingArrivalRateConfig returns a RampingArrivalRateConfig with default values
@@ -78,9 +83,14 @@ func (varc RampingArrivalRateConfig) GetDescription(et *lib.ExecutionTuple) stri
 		getScaledArrivalRate(et.Segment, maxUnscaledRate, varc.TimeUnit.TimeDuration()),
 	).Float64()
 
+	facts := []string{maxVUsRange}
+	if distribution := varc.Distribution.getDescription(); distribution != "" {
+		facts = append(facts, distribution)
+	}
+
 	return fmt.Sprintf("Up to %.2f iterations/s for %s over %d stages%s",
 		maxArrRatePerSec, sumStagesDuration(varc.Stages),
-		len(varc.Stages), varc.getBaseInfo(maxVUsRange))
+		len(varc.Stages), varc.getBaseInfo(facts...))
 }
 
 // Validate makes sure all options are configured and valid
@@ -110,6 +120,8 @@ func (varc *RampingArrivalRateConfig) Validate() []error {
 		errors = append(errors, fmt.Errorf("maxVUs can't be less than preAllocatedVUs"))
 	}
 
+	errors = append(errors, varc.Distribution.Validate()...)
+
 	return errors
 }
 
@@ -140,6 +152,7 @@ func (varc RampingArrivalRateConfig) NewExecutor(
 	return &RampingArrivalRate{
 		BaseExecutor: NewBaseExecutor(&varc, es, logger),
 		config:       varc,
+		updates:      newScenarioUpdates(),
 	}, nil
 }
 
@@ -153,12 +166,18 @@ func (varc RampingArrivalRateConfig) HasWork(et *lib.ExecutionTuple) bool {
 // TODO: combine with the ConstantArrivalRate?
 type RampingArrivalRate struct {
 	*BaseExecutor
-	config RampingArrivalRateConfig
-	et     *lib.ExecutionTuple
+	config  RampingArrivalRateConfig
+	et      *lib.ExecutionTuple
+	updates *scenarioUpdates
 }
 
-// Make sure we implement the lib.Executor interface.
-var _ lib.Executor = &RampingArrivalRate{}
+// Make sure we implement the lib.Executor, lib.LiveUpdatableExecutor and
+// lib.StoppableExecutor interfaces.
+var (
+	_ lib.Executor              = &RampingArrivalRate{}
+	_ lib.LiveUpdatableExecutor = &RampingArrivalRate{}
+	_ lib.StoppableExecutor     = &RampingArrivalRate{}
+)
 
 // Init values needed for the execution
 func (varr *RampingArrivalRate) Init(_ context.Context) error {
@@ -171,6 +190,16 @@ func (varr *RampingArrivalRate) Init(_ context.Context) error {
 	return err //nolint:wrapcheck
 }
 
+// UpdateConfig skips or adds stages of the running scenario with a
+// ScenarioUpdate. The iterations that were already started stay the same.
+func (varr *RampingArrivalRate) UpdateConfig(ctx context.Context, newConf interface{}) error {
+	update, ok := newConf.(ScenarioUpdate)
+	if !ok {
+		return errors.New("invalid config type")
+	}
+	return varr.updates.send(ctx, varr.config.Name, update)
+}
+
 // cal calculates the  transtitions between stages and gives the next full value produced by the
 // stages. In this explanation we are talking about events and in practice those events are starting
 // of an iteration, but could really be anything that needs to occur at a constant or linear rate.
@@ -232,6 +261,11 @@ func (varr *RampingArrivalRate) Init(_ context.Context) error {
 // the striping algorithm from the lib.ExecutionTuple for additional speed up but this could
 // possibly be refactored if need for this arises.
 func (varc RampingArrivalRateConfig) cal(et *lib.ExecutionTuple, ch chan<- time.Duration) {
+	varc.calUntil(nil, et, ch)
+}
+
+// calUntil is like cal, but it stops early when the done channel is closed.
+func (varc RampingArrivalRateConfig) calUntil(done <-chan struct{}, et *lib.ExecutionTuple, ch chan<- time.Duration) {
 	start, offsets, _ := et.GetStripedOffsets()
 	li := -1
 	// TODO: move this to a utility function, or directly what GetStripedOffsets uses once we see everywhere we will use it
@@ -246,25 +280,39 @@ func (varc RampingArrivalRateConfig) cal(et *lib.ExecutionTuple, ch chan<- time.
 		doneSoFar, endCount, to, dur float64
 		from                         = float64(varc.StartRate.ValueOrZero()) / timeUnit
 		// start .. starts at 0 but the algorithm works with area so we need to start from 1 not 0
-		i = float64(start + 1)
+		n        = start + 1
+		arrivals = newArrivals(varc.Distribution, varc.Name)
+		i        = arrivals.areaOf(n)
 	)
+	advance := func() {
+		n += next()
+		i = arrivals.areaOf(n)
+	}
 
 	for _, stage := range varc.Stages {
 		to = float64(stage.Target.ValueOrZero()) / timeUnit
 		dur = float64(stage.Duration.Duration)
 		if from != to { // ramp up/down
 			endCount += dur * ((to-from)/2 + from)
-			for ; i <= endCount; i += float64(next()) {
+			for ; i <= endCount; advance() {
 				// TODO: try to twist this in a way to be able to get i (the only changing part)
 				// somewhere where it is less in the middle of the equation
 				x := (from*dur - noNegativeSqrt(dur*(from*from*dur+2*(i-doneSoFar)*(to-from)))) / (from - to)
 
-				ch <- time.Duration(x) + stageStart
+				select {
+				case ch <- time.Duration(x) + stageStart:
+				case <-done:
+					return
+				}
 			}
 		} else {
 			endCount += dur * to
-			for ; i <= endCount; i += float64(next()) {
-				ch <- time.Duration((i-doneSoFar)/to) + stageStart
+			for ; i <= endCount; advance() {
+				select {
+				case ch <- time.Duration((i-doneSoFar)/to) + stageStart:
+				case <-done:
+					return
+				}
 			}
 		}
 		doneSoFar = endCount
@@ -326,7 +374,7 @@ func (varr RampingArrivalRate) Run(parentCtx context.Context, out chan<- metrics
 
 	returnedVUs := make(chan struct{})
 	waitOnProgressChannel := make(chan struct{})
-	startTime, maxDurationCtx, regDurationCtx, cancel := getDurationContexts(parentCtx, duration, gracefulStop)
+	startTime, maxDurationCtx, regDurationCtx, cancel := varr.getDurationContexts(parentCtx, duration, gracefulStop)
 
 	vusPool := newActiveVUPool(varr.executionState)
 
@@ -342,6 +390,7 @@ func (varr RampingArrivalRate) Run(parentCtx context.Context, out chan<- metrics
 	}()
 
 	activeVUsCount := uint64(0)
+	regularDuration := int64(duration) // the stages can be changed while the scenario runs
 	tickerPeriod := int64(startTickerPeriod.Duration)
 	vusFmt := pb.GetFixedLengthIntFormat(maxVUs)
 	itersFmt := pb.GetFixedLengthFloatFormat(maxArrivalRatePerSec, 2) + " iters/s"
@@ -358,6 +407,7 @@ func (varr RampingArrivalRate) Run(parentCtx context.Context, out chan<- metrics
 		}
 		progIters := fmt.Sprintf(itersFmt, itersPerSec)
 
+		duration := time.Duration(atomic.LoadInt64(&regularDuration))
 		right := []string{progVUs, duration.String(), progIters}
 
 		spent := time.Since(startTime)
@@ -439,16 +489,67 @@ func (varr RampingArrivalRate) Run(parentCtx context.Context, out chan<- metrics
 	timer := time.NewTimer(time.Hour)
 	start := time.Now()
 	ch := make(chan time.Duration, 10) // buffer 10 iteration times ahead
+	calDone := make(chan struct{})
+	defer func() { close(calDone) }()
 	var prevTime time.Duration
 	shownWarning := false
 	metricTags := varr.getMetricTags(nil)
-	go varr.config.cal(varr.et, ch)
-	for nextTime := range ch {
+	go varr.config.calUntil(calDone, varr.et, ch)
+
+	// When the stages are changed, the schedule is calculated again for the
+	// new ones. It's the same up to now, so the iterations that were already
+	// started or dropped are skipped.
+	iterations := 0
+	changeStages := func(update ScenarioUpdate) error {
+		if err := update.validateStageChanges(rampingArrivalRateType); err != nil {
+			return err
+		}
+		stages, err := rewriteStages(varr.config.StartRate.Int64, varr.config.Stages, time.Since(start), update)
+		if err != nil {
+			return err
+		}
+		newDuration := sumStagesDuration(stages)
+		if err = varr.setDurations(newDuration, gracefulStop); err != nil {
+			return err
+		}
+		varr.config.Stages = stages
+		newConfig := varr.config
+		varr.setConfig(&newConfig)
+		atomic.StoreInt64(&regularDuration, int64(newDuration))
+
+		close(calDone)
+		calDone, ch = make(chan struct{}), make(chan time.Duration, 10)
+		go varr.config.calUntil(calDone, varr.et, ch)
+		for range iterations {
+			if _, ok := <-ch; !ok {
+				break
+			}
+		}
+		varr.logger.WithField("stages", len(stages)).Debug("Changed the stages of the scenario")
+		return nil
+	}
+
+	_, stopUpdates := varr.updates.start()
+	defer stopUpdates()
+	for {
 		select {
 		case <-regDurationDone:
 			return nil
 		default:
 		}
+		var nextTime time.Duration
+		select {
+		case t, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			nextTime = t
+		case req := <-varr.updates.requests:
+			req.result <- changeStages(req.update)
+			continue
+		case <-regDurationDone:
+			return nil
+		}
 		atomic.StoreInt64(&tickerPeriod, int64(nextTime-prevTime))
 		prevTime = nextTime
 		b := time.Until(start.Add(nextTime))
@@ -456,11 +557,15 @@ func (varr RampingArrivalRate) Run(parentCtx context.Context, out chan<- metrics
 			timer.Reset(b)
 			select {
 			case <-timer.C:
+			case req := <-varr.updates.requests:
+				req.result <- changeStages(req.update)
+				continue
 			case <-regDurationDone:
 				return nil
 			}
 		}
 
+		iterations++
 		if vusPool.TryRunIteration() {
 			continue
 		}
@@ -492,7 +597,6 @@ func (varr RampingArrivalRate) Run(parentCtx context.Context, out chan<- metrics
 		default: // we're already allocating a new VU
 		}
 	}
-	return nil
 }
 
 // activeVUPool controls the activeVUs
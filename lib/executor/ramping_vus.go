@@ -2,6 +2,7 @@ package executor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"sync/atomic"
@@ -453,6 +454,7 @@ func (vlvc RampingVUsConfig) NewExecutor(es *lib.ExecutionState, logger *logrus.
 	return &RampingVUs{
 		BaseExecutor: NewBaseExecutor(vlvc, es, logger),
 		config:       vlvc,
+		updates:      newScenarioUpdates(),
 	}, nil
 }
 
@@ -466,13 +468,22 @@ func (vlvc RampingVUsConfig) HasWork(et *lib.ExecutionTuple) bool {
 // stages' duration.
 type RampingVUs struct {
 	*BaseExecutor
-	config RampingVUsConfig
+	config  RampingVUsConfig
+	updates *scenarioUpdates
 
 	rawSteps, gracefulSteps []lib.ExecutionStep
+	// the most VUs that the stages can have after live updates, since
+	// only as many VUs as the initial stages need are planned
+	maxTarget int64
 }
 
-// Make sure we implement the lib.Executor interface.
-var _ lib.Executor = &RampingVUs{}
+// Make sure we implement the lib.Executor, lib.LiveUpdatableExecutor and
+// lib.StoppableExecutor interfaces.
+var (
+	_ lib.Executor              = &RampingVUs{}
+	_ lib.LiveUpdatableExecutor = &RampingVUs{}
+	_ lib.StoppableExecutor     = &RampingVUs{}
+)
 
 // Init initializes the rampingVUs executor by precalculating the raw
 // and graceful steps.
@@ -483,12 +494,45 @@ func (vlv *RampingVUs) Init(_ context.Context) error {
 	vlv.gracefulSteps = vlv.config.GetExecutionRequirements(
 		vlv.executionState.ExecutionTuple,
 	)
+	vlv.maxTarget = getStagesUnscaledMaxTarget(vlv.config.StartVUs.Int64, vlv.config.Stages)
 	return nil
 }
 
+// UpdateConfig skips or adds stages of the running scenario with a
+// ScenarioUpdate. The targets of the new stages can't be more than the most
+// VUs of the initial stages.
+func (vlv *RampingVUs) UpdateConfig(ctx context.Context, newConf interface{}) error {
+	update, ok := newConf.(ScenarioUpdate)
+	if !ok {
+		return errors.New("invalid config type")
+	}
+	return vlv.updates.send(ctx, vlv.GetConfig().GetName(), update)
+}
+
+// Stop stops the scenario before its regular end, by ramping down to 0 VUs
+// now. The VUs get the graceful ramp-down period to finish their iterations.
+func (vlv *RampingVUs) Stop() error {
+	if vlv.updates.stopBeforeStart() {
+		return vlv.BaseExecutor.Stop()
+	}
+	err := vlv.updates.send(context.Background(), vlv.GetConfig().GetName(), ScenarioUpdate{stop: true})
+	if errors.Is(err, errScenarioFinished) {
+		return fmt.Errorf("scenario %s %w", vlv.GetConfig().GetName(), errScenarioFinished)
+	}
+	return err
+}
+
 // Run constantly loops through as many iterations as possible on a variable
 // number of VUs for the specified stages.
 func (vlv *RampingVUs) Run(ctx context.Context, _ chan<- metrics.SampleContainer) error {
+	stoppedEarlier, stopUpdates := vlv.updates.start()
+	defer stopUpdates()
+	if stoppedEarlier {
+		vlv.logger.Debug("The scenario was stopped before it started")
+		vlv.progress.Modify(pb.WithStatus(pb.Interrupted))
+		return nil
+	}
+
 	regularDuration, isFinal := lib.GetEndOffset(vlv.rawSteps)
 	if !isFinal {
 		return fmt.Errorf("%s expected raw end offset at %s to be final", vlv.config.GetName(), regularDuration)
@@ -498,7 +542,7 @@ func (vlv *RampingVUs) Run(ctx context.Context, _ chan<- metrics.SampleContainer
 		return fmt.Errorf("%s expected graceful end offset at %s to be final", vlv.config.GetName(), maxDuration)
 	}
 	waitOnProgressChannel := make(chan struct{})
-	startTime, maxDurationCtx, regularDurationCtx, cancel := getDurationContexts(
+	startTime, maxDurationCtx, regularDurationCtx, cancel := vlv.getDurationContexts(
 		ctx, regularDuration, maxDuration-regularDuration,
 	)
 	defer func() {
@@ -522,10 +566,11 @@ func (vlv *RampingVUs) Run(ctx context.Context, _ chan<- metrics.SampleContainer
 		maxVUs:         maxVUs,
 		activeVUsCount: new(int64),
 		started:        startTime,
+		duration:       int64(regularDuration),
 		runIteration:   getIterationRunner(vlv.executionState, vlv.logger),
 	}
 
-	progressFn := runState.makeProgressFn(regularDuration)
+	progressFn := runState.makeProgressFn()
 	maxDurationCtx = lib.WithScenarioState(maxDurationCtx, &lib.ScenarioState{
 		Name:       vlv.config.Name,
 		Executor:   vlv.config.Type,
@@ -568,16 +613,18 @@ type rampingVUsRunState struct {
 	maxVUs         uint64      // the scaled number of initially configured MaxVUs
 	activeVUsCount *int64      // the current number of active VUs, used only for the progress display
 	started        time.Time
+	duration       int64 // the regular duration, which is changed by live updates of the stages
 	wg             sync.WaitGroup
 
 	runIteration func(context.Context, lib.ActiveVU) bool // a helper closure function that runs a single iteration
 }
 
-func (rs *rampingVUsRunState) makeProgressFn(regular time.Duration) (progressFn func() (float64, []string)) {
+func (rs *rampingVUsRunState) makeProgressFn() (progressFn func() (float64, []string)) {
 	vusFmt := pb.GetFixedLengthIntFormat(int64(rs.maxVUs)) //nolint:gosec
-	regularDuration := pb.GetFixedLengthDuration(regular, regular)
 
 	return func() (float64, []string) {
+		regular := time.Duration(atomic.LoadInt64(&rs.duration))
+		regularDuration := pb.GetFixedLengthDuration(regular, regular)
 		spent := time.Since(rs.started)
 		cur := atomic.LoadInt64(rs.activeVUsCount)
 		progVUs := fmt.Sprintf(vusFmt+"/"+vusFmt+" VUs", cur, rs.maxVUs)
@@ -619,24 +666,41 @@ func (rs *rampingVUsRunState) runLoopsIfPossible(ctx context.Context, cancel fun
 // iterateSteps iterates over rawSteps and gracefulSteps in order according to
 // their TimeOffsets, prioritizing rawSteps. It stops iterating once rawSteps
 // are over. And it returns the number of handled gracefulSteps.
+//
+// While it waits for the next step, it applies the live updates of the
+// stages, after which it continues with the steps of the new stages.
 func (rs *rampingVUsRunState) iterateSteps(
 	ctx context.Context,
 	handleNewMaxAllowedVUs, handleNewScheduledVUs func(lib.ExecutionStep),
 ) (handledGracefulSteps int) {
-	wait := waiter(ctx, rs.started)
+	timer := time.NewTimer(time.Hour * 24)
+	defer timer.Stop()
 	i, j := 0, 0
 	for i != len(rs.executor.rawSteps) {
 		r, g := rs.executor.rawSteps[i], rs.executor.gracefulSteps[j]
+		offset := r.TimeOffset
 		if g.TimeOffset < r.TimeOffset {
-			if wait(g.TimeOffset) {
-				break
+			offset = g.TimeOffset
+		}
+		if diff := offset - time.Since(rs.started); diff > 0 {
+			timer.Reset(diff)
+			select {
+			case <-ctx.Done():
+				return j
+			case req := <-rs.executor.updates.requests:
+				nextRaw, nextGraceful, err := rs.changeStages(req.update, handleNewMaxAllowedVUs, handleNewScheduledVUs)
+				if err == nil {
+					i, j = nextRaw, nextGraceful
+				}
+				req.result <- err
+				continue
+			case <-timer.C:
 			}
+		}
+		if g.TimeOffset < r.TimeOffset {
 			handleNewMaxAllowedVUs(g)
 			j++
 		} else {
-			if wait(r.TimeOffset) {
-				break
-			}
 			handleNewScheduledVUs(r)
 			i++
 		}
@@ -644,6 +708,69 @@ func (rs *rampingVUsRunState) iterateSteps(
 	return j
 }
 
+// changeStages applies the update of the stages now. The raw and graceful
+// steps are calculated again for the new stages, with the same ones up to
+// now, and the current ones are handled. It returns the indexes of the next
+// raw and graceful steps.
+func (rs *rampingVUsRunState) changeStages(
+	update ScenarioUpdate, handleNewMaxAllowedVUs, handleNewScheduledVUs func(lib.ExecutionStep),
+) (nextRaw, nextGraceful int, err error) {
+	vlv := rs.executor
+	if !update.stop {
+		if err = update.validateStageChanges(rampingVUsType); err != nil {
+			return 0, 0, err
+		}
+		for i, stage := range update.AddStages {
+			if stage.Target.Int64 > vlv.maxTarget {
+				return 0, 0, fmt.Errorf("the target of the added stage %d can't be more than %d VUs, "+
+					"the most VUs of the initial stages", i+1, vlv.maxTarget)
+			}
+		}
+	}
+
+	elapsed := time.Since(rs.started)
+	stages, err := rewriteStages(vlv.config.StartVUs.Int64, vlv.config.Stages, elapsed, update)
+	if err != nil {
+		return 0, 0, err
+	}
+	newConfig := vlv.config
+	newConfig.Stages = stages
+	et := vlv.executionState.ExecutionTuple
+	rawSteps := newConfig.getRawExecutionSteps(et, true)
+	gracefulSteps := newConfig.GetExecutionRequirements(et)
+	regularDuration, _ := lib.GetEndOffset(rawSteps)
+	maxDuration, _ := lib.GetEndOffset(gracefulSteps)
+	if err = vlv.setDurations(regularDuration, maxDuration-regularDuration); err != nil {
+		return 0, 0, err
+	}
+	if update.stop {
+		vlv.durationsMx.Lock()
+		vlv.markStopped()
+		vlv.durationsMx.Unlock()
+	}
+
+	// only the stages are changed, since the VU handles use the base config
+	vlv.config.Stages = stages
+	vlv.setConfig(newConfig)
+	vlv.rawSteps, vlv.gracefulSteps = rawSteps, gracefulSteps
+	atomic.StoreInt64(&rs.duration, int64(regularDuration))
+	vlv.logger.WithField("stages", len(stages)).Debug("Changed the stages of the scenario")
+
+	for nextRaw < len(rawSteps) && rawSteps[nextRaw].TimeOffset <= elapsed {
+		nextRaw++
+	}
+	for nextGraceful < len(gracefulSteps) && gracefulSteps[nextGraceful].TimeOffset <= elapsed {
+		nextGraceful++
+	}
+	if nextRaw > 0 {
+		handleNewScheduledVUs(rawSteps[nextRaw-1])
+	}
+	if nextGraceful > 0 {
+		handleNewMaxAllowedVUs(gracefulSteps[nextGraceful-1])
+	}
+	return nextRaw, nextGraceful, nil
+}
+
 // runRemainingGracefulSteps runs the remaining gracefulSteps concurrently
 // before the gracefulStop timeout period stops VUs.
 //
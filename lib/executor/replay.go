@@ -0,0 +1,477 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/internal/ui/pb"
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/fsext"
+	"go.k6.io/k6/lib/types"
+	"go.k6.io/k6/metrics"
+)
+
+const replayType = "replay"
+
+func init() {
+	lib.RegisterExecutorConfigType(
+		replayType,
+		func(name string, rawJSON []byte) (lib.ExecutorConfig, error) {
+			config := NewReplayConfig(name)
+			err := lib.StrictJSONUnmarshal(rawJSON, &config)
+			return config, err
+		},
+	)
+}
+
+// ReplayConfig stores the config for the replay executor, which starts an
+// iteration for every record of a time-stamped log, e.g. an access log, at
+// the same offset from the start of the test as the one of the record from
+// the earliest record of the log.
+type ReplayConfig struct {
+	BaseConfig
+	// The path of the log, relative to the working directory, and its format,
+	// csv or jsonl, which is detected from the extension of the file by default.
+	// The log is loaded with the test, so it's included in its archives.
+	File   null.String `json:"file"`
+	Format null.String `json:"format"`
+	// The CSV column or JSON field with the timestamps of the records, which
+	// are either in RFC3339 format, in the common log format, or numbers of
+	// seconds since the Unix epoch
+	TimestampField null.String `json:"timestampField"`
+	// Speed is the factor by which the records are replayed faster than they
+	// were recorded, e.g. 2 replays an hour of traffic in 30 minutes
+	Speed       null.Float         `json:"speed"`
+	MaxDuration types.NullDuration `json:"maxDuration"`
+
+	// Initialize `PreAllocatedVUs` number of VUs, and if more than that are needed,
+	// they will be dynamically allocated, until `MaxVUs` is reached, which is an
+	// absolutely hard limit on the number of VUs the executor will use
+	PreAllocatedVUs null.Int `json:"preAllocatedVUs"`
+	MaxVUs          null.Int `json:"maxVUs"`
+}
+
+// NewReplayConfig returns a ReplayConfig with default values
+func NewReplayConfig(name string) *ReplayConfig {
+	return &ReplayConfig{
+		BaseConfig:     NewBaseConfig(name, replayType),
+		TimestampField: null.NewString("timestamp", false),
+		Speed:          null.NewFloat(1, false),
+		MaxDuration:    types.NewNullDuration(10*time.Minute, false),
+	}
+}
+
+// Make sure we implement the lib.ExecutorConfig and lib.FileReadingExecutorConfig interfaces
+var (
+	_ lib.ExecutorConfig            = &ReplayConfig{}
+	_ lib.FileReadingExecutorConfig = &ReplayConfig{}
+)
+
+// GetFilePaths returns the path of the log.
+func (rc ReplayConfig) GetFilePaths() []string {
+	return []string{rc.File.String}
+}
+
+// WithFilePaths returns a copy of the config with the given path of the log.
+func (rc ReplayConfig) WithFilePaths(paths []string) lib.ExecutorConfig {
+	rc.File = null.NewString(paths[0], rc.File.Valid)
+	return &rc
+}
+
+// GetPreAllocatedVUs is just a helper method that returns the scaled pre-allocated VUs.
+func (rc ReplayConfig) GetPreAllocatedVUs(et *lib.ExecutionTuple) int64 {
+	return et.ScaleInt64(rc.PreAllocatedVUs.Int64)
+}
+
+// GetMaxVUs is just a helper method that returns the scaled max VUs.
+func (rc ReplayConfig) GetMaxVUs(et *lib.ExecutionTuple) int64 {
+	return et.ScaleInt64(rc.MaxVUs.Int64)
+}
+
+// getFormat returns the format of the log, which is detected from the
+// extension of the file, if it isn't specified.
+func (rc ReplayConfig) getFormat() string {
+	if rc.Format.Valid {
+		return rc.Format.String
+	}
+	switch strings.ToLower(filepath.Ext(rc.File.String)) {
+	case ".csv":
+		return replayFormatCSV
+	case ".jsonl", ".ndjson", ".json":
+		return replayFormatJSONL
+	default:
+		return ""
+	}
+}
+
+// GetDescription returns a human-readable description of the executor options
+func (rc ReplayConfig) GetDescription(et *lib.ExecutionTuple) string {
+	maxVUsRange := fmt.Sprintf("maxVUs: %d", et.ScaleInt64(rc.PreAllocatedVUs.Int64))
+	if rc.MaxVUs.Int64 > rc.PreAllocatedVUs.Int64 {
+		maxVUsRange += fmt.Sprintf("-%d", et.ScaleInt64(rc.MaxVUs.Int64))
+	}
+	speed := ""
+	if rc.Speed.Float64 != 1 {
+		speed = fmt.Sprintf(" at %gx speed", rc.Speed.Float64)
+	}
+
+	return fmt.Sprintf("Replay of the records in %s%s for up to %s%s",
+		rc.File.String, speed, rc.MaxDuration.Duration, rc.getBaseInfo(maxVUsRange))
+}
+
+// Validate makes sure all options are configured and valid
+func (rc *ReplayConfig) Validate() []error {
+	errs := rc.BaseConfig.Validate()
+
+	if rc.File.String == "" {
+		errs = append(errs, errors.New("the log file isn't specified"))
+	} else if format := rc.getFormat(); format != replayFormatCSV && format != replayFormatJSONL {
+		if rc.Format.Valid {
+			errs = append(errs, fmt.Errorf("the format must be either %q or %q", replayFormatCSV, replayFormatJSONL))
+		} else {
+			errs = append(errs, fmt.Errorf("the format of the log file %s can't be detected from its extension, "+
+				"it has to be specified as either %q or %q", rc.File.String, replayFormatCSV, replayFormatJSONL))
+		}
+	}
+
+	if rc.TimestampField.String == "" {
+		errs = append(errs, errors.New("the timestampField can't be empty"))
+	}
+
+	if rc.Speed.Float64 <= 0 {
+		errs = append(errs, errors.New("the speed must be more than 0"))
+	}
+
+	if rc.MaxDuration.TimeDuration() < minDuration {
+		errs = append(errs, fmt.Errorf(
+			"the maxDuration must be at least %s, but is %s", minDuration, rc.MaxDuration,
+		))
+	}
+
+	if !rc.PreAllocatedVUs.Valid {
+		errs = append(errs, errors.New("the number of preAllocatedVUs isn't specified"))
+	} else if rc.PreAllocatedVUs.Int64 < 0 {
+		errs = append(errs, errors.New("the number of preAllocatedVUs can't be negative"))
+	}
+
+	if !rc.MaxVUs.Valid {
+		// TODO: don't change the config while validating
+		rc.MaxVUs.Int64 = rc.PreAllocatedVUs.Int64
+	} else if rc.MaxVUs.Int64 < rc.PreAllocatedVUs.Int64 {
+		errs = append(errs, errors.New("maxVUs can't be less than preAllocatedVUs"))
+	}
+
+	return errs
+}
+
+// GetExecutionRequirements returns the number of required VUs to run the
+// executor for its whole duration (disregarding any startTime), including the
+// maximum waiting time for any iterations to gracefully stop. This is used by
+// the execution scheduler in its VU reservation calculations, so it knows how
+// many VUs to pre-initialize.
+func (rc ReplayConfig) GetExecutionRequirements(et *lib.ExecutionTuple) []lib.ExecutionStep {
+	return []lib.ExecutionStep{
+		{
+			TimeOffset:      0,
+			PlannedVUs:      uint64(et.ScaleInt64(rc.PreAllocatedVUs.Int64)),                                  //nolint:gosec
+			MaxUnplannedVUs: uint64(et.ScaleInt64(rc.MaxVUs.Int64) - et.ScaleInt64(rc.PreAllocatedVUs.Int64)), //nolint:gosec
+		}, {
+			TimeOffset:      rc.MaxDuration.TimeDuration() + rc.GracefulStop.TimeDuration(),
+			PlannedVUs:      0,
+			MaxUnplannedVUs: 0,
+		},
+	}
+}
+
+// NewExecutor creates a new Replay executor
+func (rc ReplayConfig) NewExecutor(es *lib.ExecutionState, logger *logrus.Entry) (lib.Executor, error) {
+	return &Replay{
+		BaseExecutor: NewBaseExecutor(&rc, es, logger),
+		config:       rc,
+	}, nil
+}
+
+// HasWork reports whether there is any work to be done for the given execution segment.
+func (rc ReplayConfig) HasWork(et *lib.ExecutionTuple) bool {
+	return rc.GetMaxVUs(et) > 0
+}
+
+// Replay starts an iteration for every record of a log, at the recorded
+// offsets, and passes the records to the iterations.
+//
+// The records are striped across the execution segments the same way as the
+// iterations of the arrival-rate executors, so every instance of a distributed
+// test replays only its own part of the log. Each iteration gets the record
+// with the same global index as its iterationInTest, which is reserved for it
+// when its record is dispatched.
+type Replay struct {
+	*BaseExecutor
+	config ReplayConfig
+	et     *lib.ExecutionTuple
+	fs     fsext.Fs
+	pwd    string
+
+	// The records of the execution segment, sorted by their global indexes,
+	// with their offsets adjusted to the speed
+	records []replayRecord
+}
+
+// Make sure we implement the lib.Executor and lib.FileReadingExecutor interfaces.
+var (
+	_ lib.Executor            = &Replay{}
+	_ lib.FileReadingExecutor = &Replay{}
+)
+
+// SetFileSystem sets the file system from which the log is read.
+func (rp *Replay) SetFileSystem(fs fsext.Fs, pwd string) {
+	rp.fs = fs
+	rp.pwd = pwd
+}
+
+// Init reads the records of the execution segment from the log.
+func (rp *Replay) Init(_ context.Context) error {
+	// err should always be nil, because Init() won't be called for executors
+	// with no work, as determined by their config's HasWork() method.
+	et, err := rp.executionState.ExecutionTuple.GetNewExecutionTupleFromValue(rp.config.MaxVUs.Int64)
+	if err != nil {
+		return err
+	}
+	rp.et = et
+	rp.iterSegIndex = lib.NewSegmentedIndex(et)
+
+	if rp.fs == nil {
+		return fmt.Errorf("the %s executor of scenario %s needs to read the log file, "+
+			"but it doesn't have access to the file system", replayType, rp.config.Name)
+	}
+	records, err := rp.readLog()
+	if err != nil {
+		return fmt.Errorf("couldn't read the log file of scenario %s: %w", rp.config.Name, err)
+	}
+
+	speed := rp.config.Speed.Float64
+	start, offsets, _ := et.GetStripedOffsets()
+	for li, gi := 0, start; gi < int64(len(records)); li, gi = li+1, gi+offsets[li%len(offsets)] {
+		record := records[gi]
+		record.index = gi
+		record.offset = time.Duration(float64(record.offset) / speed)
+		rp.records = append(rp.records, record)
+	}
+
+	logDuration := time.Duration(float64(records[len(records)-1].offset) / speed)
+	if maxDuration := rp.config.MaxDuration.TimeDuration(); logDuration > maxDuration {
+		rp.logger.Warnf("Replaying the log of scenario %s takes %s, but its maxDuration is %s, "+
+			"so only the records in the first %s will be replayed", rp.config.Name, logDuration, maxDuration, maxDuration)
+	}
+	return nil
+}
+
+func (rp *Replay) readLog() ([]replayRecord, error) {
+	path := rp.config.File.String
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(rp.pwd, path)
+	}
+	f, err := rp.fs.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	return readReplayLog(f, rp.config.getFormat(), rp.config.TimestampField.String)
+}
+
+// getRecord returns the record with the given global index, if it's in the
+// execution segment.
+func (rp *Replay) getRecord(iterationInTest uint64) (any, bool) {
+	index := int64(iterationInTest) //nolint:gosec
+	i := sort.Search(len(rp.records), func(i int) bool { return rp.records[i].index >= index })
+	if i == len(rp.records) || rp.records[i].index != index {
+		return nil, false
+	}
+	return rp.records[i].data, true
+}
+
+// Run starts the iterations at the offsets of the records of the execution
+// segment, until all of them are started or the maxDuration is reached.
+//
+//nolint:funlen
+func (rp *Replay) Run(parentCtx context.Context, out chan<- metrics.SampleContainer) (err error) {
+	gracefulStop := rp.config.GetGracefulStop()
+	duration := rp.config.MaxDuration.TimeDuration()
+	preAllocatedVUs := rp.config.GetPreAllocatedVUs(rp.executionState.ExecutionTuple)
+	maxVUs := rp.config.GetMaxVUs(rp.executionState.ExecutionTuple)
+
+	// Make sure the log and the progress bar have accurate information
+	rp.logger.WithFields(logrus.Fields{
+		"maxVUs": maxVUs, "preAllocatedVUs": preAllocatedVUs, "maxDuration": duration,
+		"records": len(rp.records), "type": rp.config.GetType(),
+	}).Debug("Starting executor run...")
+
+	activeVUsWg := &sync.WaitGroup{}
+
+	returnedVUs := make(chan struct{})
+	waitOnProgressChannel := make(chan struct{})
+	startTime, maxDurationCtx, regDurationCtx, cancel := rp.getDurationContexts(parentCtx, duration, gracefulStop)
+	defer func() {
+		cancel()
+		<-waitOnProgressChannel
+	}()
+
+	vusPool := newActiveVUPool(rp.executionState)
+	defer func() {
+		// Make sure all VUs aren't executing iterations anymore, for the cancel()
+		// below to deactivate them.
+		<-returnedVUs
+		// first close the vusPool so we wait for the gracefulShutdown
+		vusPool.Close()
+		cancel()
+		activeVUsWg.Wait()
+	}()
+	activeVUsCount := uint64(0)
+	startedRecords := uint64(0)
+	totalRecords := uint64(len(rp.records))
+
+	vusFmt := pb.GetFixedLengthIntFormat(maxVUs)
+	recordsFmt := pb.GetFixedLengthIntFormat(int64(totalRecords)) //nolint:gosec
+	progressFn := func() (float64, []string) {
+		spent := time.Since(startTime)
+		currActiveVUs := atomic.LoadUint64(&activeVUsCount)
+		started := atomic.LoadUint64(&startedRecords)
+		right := []string{
+			fmt.Sprintf(vusFmt+"/"+vusFmt+" VUs", vusPool.Running(), currActiveVUs),
+			fmt.Sprintf(recordsFmt+"/"+recordsFmt+" records", started, totalRecords),
+			pb.GetFixedLengthDuration(spent, duration) + "/" + duration.String(),
+		}
+		if totalRecords == 0 {
+			return 1, right
+		}
+		return float64(started) / float64(totalRecords), right
+	}
+	rp.progress.Modify(pb.WithProgress(progressFn))
+	maxDurationCtx = lib.WithScenarioState(maxDurationCtx, &lib.ScenarioState{
+		Name:       rp.config.Name,
+		Executor:   rp.config.Type,
+		StartTime:  startTime,
+		ProgressFn: progressFn,
+		RecordFn:   rp.getRecord,
+	})
+
+	go func() {
+		trackProgress(parentCtx, maxDurationCtx, regDurationCtx, rp, progressFn)
+		close(waitOnProgressChannel)
+	}()
+
+	returnVU := func(u lib.InitializedVU) {
+		// Return the VU without decreasing the global active VU counter, which
+		// is done in the goroutine started by activeVUPool.AddVU, whenever the
+		// VU finishes running an iteration. This results in a more accurate
+		// report of VUs that are _actually_ active.
+		rp.executionState.ReturnVU(u, false)
+		activeVUsWg.Done()
+	}
+
+	// The iteration counters are reserved when the records are dispatched, so
+	// the ones of the dropped records are skipped in order. They are passed to
+	// the VU that accepted the iteration, which is the only one waiting for
+	// them, since the channel is unbuffered.
+	dispatchedCounters := make(chan [2]uint64)
+	runIterationBasic := getIterationRunner(rp.executionState, rp.logger)
+	activateVU := func(initVU lib.InitializedVU) lib.ActiveVU {
+		activeVUsWg.Add(1)
+		var counters [2]uint64 // only used by the goroutine of the VU in the pool
+		activeVU := initVU.Activate(getVUActivationParams(
+			maxDurationCtx, rp.config.BaseConfig, returnVU,
+			func() (uint64, uint64) { return counters[0], counters[1] },
+		))
+		atomic.AddUint64(&activeVUsCount, 1)
+		vusPool.AddVU(maxDurationCtx, activeVU, func(ctx context.Context, avu lib.ActiveVU) bool {
+			counters = <-dispatchedCounters
+			return runIterationBasic(ctx, avu)
+		})
+		return activeVU
+	}
+
+	remainingUnplannedVUs := maxVUs - preAllocatedVUs
+	makeUnplannedVUCh := make(chan struct{})
+	defer close(makeUnplannedVUCh)
+	go func() {
+		defer close(returnedVUs)
+		for range makeUnplannedVUCh {
+			rp.logger.Debug("Starting initialization of an unplanned VU...")
+			initVU, err := rp.executionState.GetUnplannedVU(maxDurationCtx, rp.logger)
+			if err != nil {
+				// TODO figure out how to return it to the Run goroutine
+				rp.logger.WithError(err).Error("Error while allocating unplanned VU")
+			} else {
+				rp.logger.Debug("The unplanned VU finished initializing successfully!")
+				activateVU(initVU)
+			}
+		}
+	}()
+
+	// Get the pre-allocated VUs in the local buffer
+	for i := int64(0); i < preAllocatedVUs; i++ {
+		initVU, err := rp.executionState.GetPlannedVU(rp.logger, false)
+		if err != nil {
+			return err
+		}
+		activateVU(initVU)
+	}
+
+	timer := time.NewTimer(time.Hour * 24)
+	droppedIterationMetric := rp.executionState.Test.BuiltinMetrics.DroppedIterations
+	shownWarning := false
+	metricTags := rp.getMetricTags(nil)
+	for _, record := range rp.records {
+		timer.Reset(record.offset - time.Since(startTime))
+		select {
+		case <-timer.C:
+			atomic.AddUint64(&startedRecords, 1)
+			local, global := rp.nextIterationCounters()
+			if vusPool.TryRunIteration() {
+				dispatchedCounters <- [2]uint64{local, global}
+				continue
+			}
+
+			// Since there aren't any free VUs available, consider this iteration
+			// dropped, its record is skipped with the counters reserved for it
+			metrics.PushIfNotDone(parentCtx, out, metrics.Sample{
+				TimeSeries: metrics.TimeSeries{
+					Metric: droppedIterationMetric,
+					Tags:   metricTags,
+				},
+				Time:  time.Now(),
+				Value: 1,
+			})
+
+			// We'll try to start allocating another VU in the background,
+			// non-blockingly, if we have remainingUnplannedVUs...
+			if remainingUnplannedVUs == 0 {
+				if !shownWarning {
+					rp.logger.Warningf("Insufficient VUs, reached %d active VUs and cannot initialize more", maxVUs)
+					shownWarning = true
+				}
+				continue
+			}
+
+			select {
+			case makeUnplannedVUCh <- struct{}{}: // great!
+				remainingUnplannedVUs--
+			default: // we're already allocating a new VU
+			}
+
+		case <-regDurationCtx.Done():
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package executor
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// The supported formats of the logs of the replay executor
+const (
+	replayFormatCSV   = "csv"
+	replayFormatJSONL = "jsonl"
+)
+
+// replayTimeLayouts are the layouts of the timestamps that are parsed, besides
+// the numeric ones, which are seconds since the Unix epoch.
+//
+//nolint:gochecknoglobals
+var replayTimeLayouts = []string{
+	time.RFC3339Nano,
+	"02/Jan/2006:15:04:05 -0700", // the common log format of nginx and Apache
+	"2006-01-02 15:04:05.999999999",
+}
+
+// replayRecord is a record of the log, with the offset of its timestamp from
+// the earliest one and its global index in the log, sorted by the timestamps.
+type replayRecord struct {
+	index  int64
+	offset time.Duration
+	data   any
+}
+
+// readReplayLog reads all the records of a log with the given format and
+// returns them sorted by their timestamps.
+func readReplayLog(r io.Reader, format, timestampField string) ([]replayRecord, error) {
+	var (
+		records    []replayRecord
+		timestamps []time.Time
+		err        error
+	)
+	switch format {
+	case replayFormatCSV:
+		records, timestamps, err = readReplayCSV(r, timestampField)
+	case replayFormatJSONL:
+		records, timestamps, err = readReplayJSONL(r, timestampField)
+	default:
+		err = fmt.Errorf("unsupported log format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("the log doesn't have any records")
+	}
+
+	earliest := timestamps[0]
+	for _, ts := range timestamps[1:] {
+		if ts.Before(earliest) {
+			earliest = ts
+		}
+	}
+	for i, ts := range timestamps {
+		records[i].offset = ts.Sub(earliest)
+	}
+	// access logs are usually written when the requests end, so they can be
+	// a little out of order
+	sort.SliceStable(records, func(i, j int) bool { return records[i].offset < records[j].offset })
+
+	return records, nil
+}
+
+func readReplayCSV(r io.Reader, timestampField string) ([]replayRecord, []time.Time, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	timestampColumn := -1
+	for i, name := range header {
+		if name == timestampField {
+			timestampColumn = i
+		}
+	}
+	if timestampColumn < 0 {
+		return nil, nil, fmt.Errorf("the header of the log doesn't have the %q column", timestampField)
+	}
+
+	var (
+		records    []replayRecord
+		timestamps []time.Time
+	)
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		ts, err := parseReplayTimestamp(row[timestampColumn])
+		if err != nil {
+			line, _ := reader.FieldPos(timestampColumn)
+			return nil, nil, fmt.Errorf("invalid timestamp on line %d: %w", line, err)
+		}
+		data := make(map[string]string, len(header))
+		for i, name := range header {
+			data[name] = row[i]
+		}
+		records = append(records, replayRecord{data: data})
+		timestamps = append(timestamps, ts)
+	}
+	return records, timestamps, nil
+}
+
+func readReplayJSONL(r io.Reader, timestampField string) ([]replayRecord, []time.Time, error) {
+	var (
+		records    []replayRecord
+		timestamps []time.Time
+		reader     = bufio.NewReader(r)
+	)
+	for line := 1; ; line++ {
+		raw, err := reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, nil, err
+		}
+		if raw = bytes.TrimSpace(raw); len(raw) > 0 {
+			var data map[string]any
+			if jsonErr := json.Unmarshal(raw, &data); jsonErr != nil {
+				return nil, nil, fmt.Errorf("invalid record on line %d: %w", line, jsonErr)
+			}
+			value, ok := data[timestampField]
+			if !ok {
+				return nil, nil, fmt.Errorf("the record on line %d doesn't have the %q field", line, timestampField)
+			}
+			ts, tsErr := parseReplayTimestamp(value)
+			if tsErr != nil {
+				return nil, nil, fmt.Errorf("invalid timestamp on line %d: %w", line, tsErr)
+			}
+			records = append(records, replayRecord{data: data})
+			timestamps = append(timestamps, ts)
+		}
+		if errors.Is(err, io.EOF) {
+			return records, timestamps, nil
+		}
+	}
+}
+
+// parseReplayTimestamp parses a timestamp in one of the supported layouts,
+// or a number of seconds since the Unix epoch, which can be fractional.
+func parseReplayTimestamp(value any) (time.Time, error) {
+	switch v := value.(type) {
+	case float64:
+		return unixSecondsToTime(v), nil
+	case string:
+		if seconds, err := strconv.ParseFloat(v, 64); err == nil {
+			return unixSecondsToTime(seconds), nil
+		}
+		for _, layout := range replayTimeLayouts {
+			if ts, err := time.Parse(layout, v); err == nil {
+				return ts, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("%q isn't a supported timestamp", v)
+	default:
+		return time.Time{}, fmt.Errorf("%v isn't a supported timestamp", v)
+	}
+}
+
+func unixSecondsToTime(seconds float64) time.Time {
+	whole, fraction := math.Modf(seconds)
+	return time.Unix(int64(whole), int64(fraction*float64(time.Second)))
+}
@@ -0,0 +1,327 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/internal/lib/testutils/minirunner"
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/fsext"
+	"go.k6.io/k6/lib/types"
+	"go.k6.io/k6/metrics"
+)
+
+func TestReadReplayLog(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		format, log string
+		offsets     []time.Duration
+		data        []any
+		err         string
+	}{
+		"csv": {
+			format: replayFormatCSV,
+			log: "timestamp,method,url\n" +
+				"2024-03-01T10:00:01.5Z,GET,/b\n" +
+				"2024-03-01T10:00:00Z,POST,/a\n" +
+				"2024-03-01T10:00:03Z,GET,\"/c?x=1,2\"\n",
+			offsets: []time.Duration{0, 1500 * time.Millisecond, 3 * time.Second},
+			data: []any{
+				map[string]string{"timestamp": "2024-03-01T10:00:00Z", "method": "POST", "url": "/a"},
+				map[string]string{"timestamp": "2024-03-01T10:00:01.5Z", "method": "GET", "url": "/b"},
+				map[string]string{"timestamp": "2024-03-01T10:00:03Z", "method": "GET", "url": "/c?x=1,2"},
+			},
+		},
+		"csv with the common log format": {
+			format:  replayFormatCSV,
+			log:     "time,path\n01/Mar/2024:10:00:00 +0000,/a\n01/Mar/2024:11:00:00 +0100,/b\n01/Mar/2024:10:00:02 +0000,/c\n",
+			offsets: []time.Duration{0, 0, 2 * time.Second},
+			data: []any{
+				map[string]string{"time": "01/Mar/2024:10:00:00 +0000", "path": "/a"},
+				map[string]string{"time": "01/Mar/2024:11:00:00 +0100", "path": "/b"},
+				map[string]string{"time": "01/Mar/2024:10:00:02 +0000", "path": "/c"},
+			},
+		},
+		"jsonl": {
+			format: replayFormatJSONL,
+			log: `{"timestamp": 1709287200.25, "url": "/a"}` + "\n\n" +
+				`{"timestamp": "1709287200", "url": "/b", "body": {"id": 1}}` + "\n" +
+				`{"timestamp": "2024-03-01 10:00:01.5", "url": "/c"}`,
+			offsets: []time.Duration{0, 250 * time.Millisecond, 1500 * time.Millisecond},
+			data: []any{
+				map[string]any{"timestamp": "1709287200", "url": "/b", "body": map[string]any{"id": 1.0}},
+				map[string]any{"timestamp": 1709287200.25, "url": "/a"},
+				map[string]any{"timestamp": "2024-03-01 10:00:01.5", "url": "/c"},
+			},
+		},
+		"csv without the timestamp column": {
+			format: replayFormatCSV,
+			log:    "ts,url\n1,/a\n",
+			err:    `the header of the log doesn't have the "timestamp" column`,
+		},
+		"csv with an invalid timestamp": {
+			format: replayFormatCSV,
+			log:    "timestamp,url\n1,/a\nyesterday,/b\n",
+			err:    `invalid timestamp on line 3: "yesterday" isn't a supported timestamp`,
+		},
+		"csv with a missing column": {
+			format: replayFormatCSV,
+			log:    "timestamp,url\n1,/a\n2\n",
+			err:    "wrong number of fields",
+		},
+		"jsonl without the timestamp field": {
+			format: replayFormatJSONL,
+			log:    `{"timestamp": 1}` + "\n" + `{"url": "/b"}`,
+			err:    `the record on line 2 doesn't have the "timestamp" field`,
+		},
+		"jsonl with an invalid record": {
+			format: replayFormatJSONL,
+			log:    `{"timestamp": 1}` + "\n" + `{"timestamp": 2`,
+			err:    "invalid record on line 2",
+		},
+		"jsonl with an invalid timestamp": {
+			format: replayFormatJSONL,
+			log:    `{"timestamp": true}`,
+			err:    "invalid timestamp on line 1: true isn't a supported timestamp",
+		},
+		"empty": {
+			format: replayFormatJSONL,
+			log:    "\n",
+			err:    "the log doesn't have any records",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			timestampField := "timestamp"
+			if strings.HasPrefix(tc.log, "time,") {
+				timestampField = "time"
+			}
+			records, err := readReplayLog(strings.NewReader(tc.log), tc.format, timestampField)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+
+			offsets := make([]time.Duration, len(records))
+			data := make([]any, len(records))
+			for i, record := range records {
+				offsets[i] = record.offset
+				data[i] = record.data
+			}
+			assert.Equal(t, tc.offsets, offsets)
+			assert.Equal(t, tc.data, data)
+		})
+	}
+}
+
+// replayTestConfig gives the replay executor access to the test file system.
+type replayTestConfig struct {
+	*ReplayConfig
+	fs fsext.Fs
+}
+
+func (rtc replayTestConfig) NewExecutor(es *lib.ExecutionState, logger *logrus.Entry) (lib.Executor, error) {
+	executor, err := rtc.ReplayConfig.NewExecutor(es, logger)
+	if err != nil {
+		return nil, err
+	}
+	executor.(*Replay).SetFileSystem(rtc.fs, "/") //nolint:forcetypeassert
+	return executor, nil
+}
+
+func getTestReplayConfig(t *testing.T, records int, interval time.Duration) replayTestConfig {
+	fs := fsext.NewMemMapFs()
+	var log strings.Builder
+	start := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	for i := range records {
+		_, _ = fmt.Fprintf(&log, `{"timestamp": %q, "id": %d}`+"\n",
+			start.Add(time.Duration(i)*interval).Format(time.RFC3339Nano), i)
+	}
+	require.NoError(t, fsext.WriteFile(fs, "/logs/traffic.jsonl", []byte(log.String()), 0o644))
+
+	config := NewReplayConfig("replay")
+	config.File = null.StringFrom("logs/traffic.jsonl")
+	config.GracefulStop = types.NullDurationFrom(time.Second)
+	config.PreAllocatedVUs = null.IntFrom(5)
+	config.MaxVUs = null.IntFrom(5)
+	return replayTestConfig{ReplayConfig: config, fs: fs}
+}
+
+// replayedRecord is a record that an iteration got, with its start time.
+type replayedRecord struct {
+	id      float64
+	startAt time.Duration
+}
+
+// delayedCountersRunner returns VUs that wait for the given delay before they
+// get the counters of each iteration, like a busy VU could.
+type delayedCountersRunner struct {
+	*minirunner.MiniRunner
+	delay time.Duration
+}
+
+func (r delayedCountersRunner) NewVU(
+	ctx context.Context, idLocal, idGlobal uint64, out chan<- metrics.SampleContainer,
+) (lib.InitializedVU, error) {
+	vu, err := r.MiniRunner.NewVU(ctx, idLocal, idGlobal, out)
+	return delayedCountersVU{InitializedVU: vu, delay: r.delay}, err
+}
+
+type delayedCountersVU struct {
+	lib.InitializedVU
+	delay time.Duration
+}
+
+func (vu delayedCountersVU) Activate(params *lib.VUActivationParams) lib.ActiveVU {
+	getNextIterationCounters := params.GetNextIterationCounters
+	params.GetNextIterationCounters = func() (uint64, uint64) {
+		time.Sleep(vu.delay)
+		return getNextIterationCounters()
+	}
+	return vu.InitializedVU.Activate(params)
+}
+
+func runReplayTest(
+	t *testing.T, segment, sequence string, config replayTestConfig, countersDelay time.Duration,
+) []replayedRecord {
+	var (
+		mx       sync.Mutex
+		replayed []replayedRecord
+	)
+	var runner lib.Runner = simpleRunner(func(ctx context.Context, state *lib.State) error {
+		ss := lib.GetScenarioState(ctx)
+		record, ok := ss.RecordFn(state.GetScenarioGlobalVUIter())
+		require.True(t, ok)
+		mx.Lock()
+		defer mx.Unlock()
+		replayed = append(replayed, replayedRecord{
+			id:      record.(map[string]any)["id"].(float64), //nolint:forcetypeassert
+			startAt: time.Since(ss.StartTime),
+		})
+		return nil
+	})
+	if countersDelay > 0 {
+		runner = delayedCountersRunner{MiniRunner: runner.(*minirunner.MiniRunner), delay: countersDelay} //nolint:forcetypeassert
+	}
+
+	test := setupExecutorTest(t, segment, sequence, lib.Options{}, runner, config)
+	defer test.cancel()
+
+	engineOut := make(chan metrics.SampleContainer, 1000)
+	require.NoError(t, test.executor.Run(test.ctx, engineOut))
+
+	mx.Lock()
+	defer mx.Unlock()
+	sort.Slice(replayed, func(i, j int) bool { return replayed[i].id < replayed[j].id })
+	return replayed
+}
+
+func TestReplayRun(t *testing.T) {
+	t.Parallel()
+
+	config := getTestReplayConfig(t, 10, 100*time.Millisecond)
+	config.Speed = null.FloatFrom(2)
+
+	start := time.Now()
+	replayed := runReplayTest(t, "", "", config, 0)
+	assert.Less(t, time.Since(start), time.Second)
+
+	require.Len(t, replayed, 10)
+	for i, record := range replayed {
+		assert.Equal(t, float64(i), record.id)
+		expected := time.Duration(i) * 50 * time.Millisecond
+		assert.InDelta(t, expected, record.startAt, float64(25*time.Millisecond))
+	}
+}
+
+func TestReplayRunSegments(t *testing.T) {
+	t.Parallel()
+
+	config := getTestReplayConfig(t, 20, 10*time.Millisecond)
+	const sequence = "0,1/4,1/2,1"
+
+	var ids []float64
+	for _, segment := range []string{"0:1/4", "1/4:1/2", "1/2:1"} {
+		replayed := runReplayTest(t, segment, sequence, config, 0)
+		assert.NotEmpty(t, replayed)
+		for _, record := range replayed {
+			ids = append(ids, record.id)
+		}
+	}
+	sort.Float64s(ids)
+
+	expected := make([]float64, 20)
+	for i := range expected {
+		expected[i] = float64(i)
+	}
+	assert.Equal(t, expected, ids)
+}
+
+func TestReplayRunDroppedIterations(t *testing.T) {
+	t.Parallel()
+
+	config := getTestReplayConfig(t, 10, 100*time.Millisecond)
+	config.PreAllocatedVUs = null.IntFrom(1)
+	config.MaxVUs = null.IntFrom(1)
+
+	// The only VU is still busy when every other record is dispatched, so
+	// those records are dropped, and every iteration still gets the record
+	// that was dispatched to it, even if it gets its counters after that.
+	const countersDelay = 150 * time.Millisecond
+	replayed := runReplayTest(t, "", "", config, countersDelay)
+	require.NotEmpty(t, replayed)
+	assert.Less(t, len(replayed), 10)
+	for _, record := range replayed {
+		expected := time.Duration(record.id)*100*time.Millisecond + countersDelay
+		assert.InDelta(t, expected, record.startAt, float64(50*time.Millisecond), "record %v", record.id)
+	}
+}
+
+func TestReplayMaxDuration(t *testing.T) {
+	t.Parallel()
+
+	config := getTestReplayConfig(t, 10, 300*time.Millisecond)
+	config.MaxDuration = types.NullDurationFrom(time.Second)
+
+	runner := simpleRunner(func(_ context.Context, _ *lib.State) error { return nil })
+	test := setupExecutorTest(t, "", "", lib.Options{}, runner, config)
+	defer test.cancel()
+
+	entries := test.logHook.Drain()
+	require.Len(t, entries, 1)
+	assert.Equal(t, logrus.WarnLevel, entries[0].Level)
+	assert.Equal(t, "Replaying the log of scenario replay takes 2.7s, but its maxDuration is 1s, "+
+		"so only the records in the first 1s will be replayed", entries[0].Message)
+
+	require.NoError(t, test.executor.Run(test.ctx, make(chan metrics.SampleContainer, 100)))
+	assert.Equal(t, uint64(4), test.state.GetFullIterationCount())
+}
+
+func TestReplayInitWithoutFileSystem(t *testing.T) {
+	t.Parallel()
+
+	config := getTestReplayConfig(t, 10, time.Second)
+	runner := simpleRunner(func(_ context.Context, _ *lib.State) error { return nil })
+	es := lib.NewExecutionState(getTestRunState(t, lib.Options{}, runner), mustNewExecutionTuple(nil, nil), 5, 5)
+	executor, err := config.ReplayConfig.NewExecutor(es, logrus.NewEntry(logrus.New()))
+	require.NoError(t, err)
+
+	err = executor.Init(context.Background())
+	require.ErrorContains(t, err, "the replay executor of scenario replay needs to read the log file, "+
+		"but it doesn't have access to the file system")
+}
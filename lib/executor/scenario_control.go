@@ -0,0 +1,248 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/lib/types"
+)
+
+var (
+	errScenarioNotStarted = errors.New("hasn't started yet")
+	errScenarioFinished   = errors.New("has already finished")
+)
+
+// ScenarioUpdate is a change of a running scenario, e.g. through the REST API.
+// Which of its fields can be used depends on the executor of the scenario.
+type ScenarioUpdate struct {
+	// Rate is the new rate of a constant-arrival-rate scenario, in iterations
+	// per its timeUnit.
+	Rate null.Int `json:"rate"`
+	// SkipStages is the number of stages of a ramping scenario that are
+	// skipped, starting with the current one.
+	SkipStages null.Int `json:"skipStages"`
+	// AddStages are added after the remaining stages of a ramping scenario.
+	AddStages []Stage `json:"addStages"`
+
+	// stop is used by the ramping VUs executor to stop its scenario, by
+	// skipping all of the remaining stages and ramping down to 0 VUs.
+	stop bool
+}
+
+// hasStageChanges returns whether the update changes the stages of a ramping
+// scenario.
+func (su ScenarioUpdate) hasStageChanges() bool {
+	return su.SkipStages.Valid || len(su.AddStages) > 0 || su.stop
+}
+
+// validateStageChanges makes sure that the update only changes the stages of
+// a ramping scenario, and that the changes are valid.
+func (su ScenarioUpdate) validateStageChanges(executorType string) error {
+	if su.Rate.Valid {
+		return fmt.Errorf("the rate of %s scenarios can't be changed, only their stages", executorType)
+	}
+	if !su.hasStageChanges() {
+		return errors.New("the update doesn't change anything")
+	}
+	if su.SkipStages.Int64 < 0 {
+		return errors.New("the number of stages to skip can't be negative")
+	}
+	if len(su.AddStages) > 0 {
+		return errors.Join(validateStages(su.AddStages)...)
+	}
+	return nil
+}
+
+// rewriteStages returns the stages of a ramping scenario after the update is
+// applied at the given time since the start of the scenario. If stages are
+// skipped, the current one is cut at that time, with the current value as
+// its target, so the stages up to now stay the same. It returns an error if
+// all of the stages are already over.
+func rewriteStages(
+	startValue int64, stages []Stage, elapsed time.Duration, update ScenarioUpdate,
+) ([]Stage, error) {
+	current, from := -1, startValue
+	var stageStart time.Duration
+	for i, stage := range stages {
+		stageEnd := stageStart + stage.Duration.TimeDuration()
+		if elapsed < stageEnd {
+			current = i
+			break
+		}
+		stageStart, from = stageEnd, stage.Target.ValueOrZero()
+	}
+	if current < 0 {
+		return nil, errors.New("all of its stages are already over")
+	}
+
+	skip := update.SkipStages.Int64
+	if update.stop {
+		skip = int64(len(stages))
+	}
+	newStages := make([]Stage, 0, len(stages)+len(update.AddStages)+1)
+	if skip == 0 {
+		newStages = append(newStages, stages...)
+	} else {
+		// the current value is rounded, since the targets are integers
+		stage := stages[current]
+		spent := elapsed - stageStart
+		to := stage.Target.ValueOrZero()
+		progress := float64(spent) / float64(stage.Duration.TimeDuration())
+		newStages = append(newStages, stages[:current]...)
+		newStages = append(newStages, Stage{
+			Duration: types.NullDurationFrom(spent),
+			Target:   null.IntFrom(from + int64(math.Round(float64(to-from)*progress))),
+		})
+		if next := int64(current) + skip; next < int64(len(stages)) {
+			newStages = append(newStages, stages[next:]...)
+		}
+	}
+	if update.stop {
+		newStages = append(newStages, Stage{Duration: types.NullDurationFrom(0), Target: null.IntFrom(0)})
+	}
+	return append(newStages, update.AddStages...), nil
+}
+
+// scenarioUpdateRequest is an update that is sent to the Run method of an
+// executor, which applies it and sends back the result.
+type scenarioUpdateRequest struct {
+	update ScenarioUpdate
+	result chan error
+}
+
+// scenarioUpdates passes the live updates of a scenario to the Run method of
+// its executor, which applies them in its main loop, so they don't race with
+// the scheduling of the iterations.
+type scenarioUpdates struct {
+	requests chan scenarioUpdateRequest
+	done     chan struct{}
+
+	mx             sync.Mutex
+	started        bool
+	stoppedEarlier bool
+}
+
+func newScenarioUpdates() *scenarioUpdates {
+	return &scenarioUpdates{
+		requests: make(chan scenarioUpdateRequest),
+		done:     make(chan struct{}),
+	}
+}
+
+// start is called by Run before it starts to receive the updates. It returns
+// whether the scenario was stopped before that, and a function that has to be
+// called when Run stops receiving the updates.
+func (su *scenarioUpdates) start() (stoppedEarlier bool, stop func()) {
+	su.mx.Lock()
+	defer su.mx.Unlock()
+	su.started = true
+	return su.stoppedEarlier, func() { close(su.done) }
+}
+
+// stopBeforeStart marks the scenario as stopped if Run hasn't started to
+// receive the updates yet, and returns whether it did that.
+func (su *scenarioUpdates) stopBeforeStart() bool {
+	su.mx.Lock()
+	defer su.mx.Unlock()
+	if su.started {
+		return false
+	}
+	su.stoppedEarlier = true
+	return true
+}
+
+// send sends the update to Run and waits for the result of applying it.
+func (su *scenarioUpdates) send(ctx context.Context, scenario string, update ScenarioUpdate) error {
+	su.mx.Lock()
+	started := su.started
+	su.mx.Unlock()
+	if !started {
+		return fmt.Errorf("scenario %s %w", scenario, errScenarioNotStarted)
+	}
+
+	req := scenarioUpdateRequest{update: update, result: make(chan error, 1)}
+	select {
+	case su.requests <- req:
+	case <-su.done:
+		return fmt.Errorf("scenario %s %w", scenario, errScenarioFinished)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case err := <-req.result:
+		if err != nil {
+			return fmt.Errorf("couldn't update scenario %s: %w", scenario, err)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// durationContexts are the contexts returned by getDurationContexts. Unlike
+// contexts with deadlines, the end of their regular duration, and with it the
+// end of the graceful stop period, can be changed while the executor runs.
+type durationContexts struct {
+	startTime    time.Time
+	gracefulStop time.Duration
+
+	maxCtx, regCtx       context.Context //nolint:containedctx
+	maxCancel, regCancel func()
+
+	mx                 sync.Mutex
+	maxTimer, regTimer *time.Timer
+}
+
+func newDurationContexts(parentCtx context.Context, regularDuration, gracefulStop time.Duration) *durationContexts {
+	dc := &durationContexts{startTime: time.Now(), gracefulStop: gracefulStop}
+	dc.maxCtx, dc.maxCancel = context.WithCancel(parentCtx)
+	dc.regCtx, dc.regCancel = context.WithCancel(dc.maxCtx)
+	dc.maxTimer = time.AfterFunc(regularDuration+gracefulStop, dc.maxCancel)
+	dc.regTimer = time.AfterFunc(regularDuration, dc.regCancel)
+	return dc
+}
+
+// setRegularDuration changes the regular duration, counted from the start
+// time, and moves the end of the graceful stop period after it. It returns
+// false if the regular duration was already over.
+func (dc *durationContexts) setRegularDuration(regularDuration time.Duration) bool {
+	dc.mx.Lock()
+	defer dc.mx.Unlock()
+	return dc.reset(regularDuration, dc.gracefulStop)
+}
+
+// setDurations is like setRegularDuration, but it also changes the graceful
+// stop period.
+func (dc *durationContexts) setDurations(regularDuration, gracefulStop time.Duration) bool {
+	dc.mx.Lock()
+	defer dc.mx.Unlock()
+	return dc.reset(regularDuration, gracefulStop)
+}
+
+func (dc *durationContexts) reset(regularDuration, gracefulStop time.Duration) bool {
+	if dc.regCtx.Err() != nil {
+		return false
+	}
+	regularEnd := dc.startTime.Add(regularDuration)
+	if !dc.regTimer.Reset(time.Until(regularEnd)) {
+		return false // the timer already fired
+	}
+	dc.gracefulStop = gracefulStop
+	dc.maxTimer.Reset(time.Until(regularEnd.Add(gracefulStop)))
+	return true
+}
+
+// cancel cancels both contexts and stops their timers.
+func (dc *durationContexts) cancel() {
+	dc.mx.Lock()
+	defer dc.mx.Unlock()
+	dc.maxTimer.Stop()
+	dc.regTimer.Stop()
+	dc.maxCancel()
+}
@@ -0,0 +1,284 @@
+package executor
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/types"
+	"go.k6.io/k6/metrics"
+)
+
+func TestRewriteStages(t *testing.T) {
+	t.Parallel()
+
+	stage := func(d time.Duration, target int64) Stage {
+		return Stage{Duration: types.NullDurationFrom(d), Target: null.IntFrom(target)}
+	}
+	stages := []Stage{stage(2*time.Second, 10), stage(2*time.Second, 10), stage(2*time.Second, 0)}
+
+	testCases := map[string]struct {
+		elapsed  time.Duration
+		update   ScenarioUpdate
+		expected []Stage
+		err      string
+	}{
+		"add stages": {
+			elapsed:  3 * time.Second,
+			update:   ScenarioUpdate{AddStages: []Stage{stage(time.Second, 5)}},
+			expected: append(append([]Stage{}, stages...), stage(time.Second, 5)),
+		},
+		"skip the current stage": {
+			elapsed:  time.Second,
+			update:   ScenarioUpdate{SkipStages: null.IntFrom(1)},
+			expected: []Stage{stage(time.Second, 6), stages[1], stages[2]},
+		},
+		"skip the rest and add stages": {
+			elapsed:  3 * time.Second,
+			update:   ScenarioUpdate{SkipStages: null.IntFrom(5), AddStages: []Stage{stage(time.Second, 20)}},
+			expected: []Stage{stages[0], stage(time.Second, 10), stage(time.Second, 20)},
+		},
+		"stop": {
+			elapsed:  4500 * time.Millisecond,
+			update:   ScenarioUpdate{stop: true},
+			expected: []Stage{stages[0], stages[1], stage(500*time.Millisecond, 7), stage(0, 0)},
+		},
+		"all stages are over": {
+			elapsed: 6 * time.Second,
+			update:  ScenarioUpdate{AddStages: []Stage{stage(time.Second, 5)}},
+			err:     "all of its stages are already over",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			newStages, err := rewriteStages(2, stages, tc.elapsed, tc.update)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, newStages)
+		})
+	}
+}
+
+func TestScenarioUpdateBeforeStart(t *testing.T) {
+	t.Parallel()
+
+	runner := simpleRunner(func(_ context.Context, _ *lib.State) error { return nil })
+	config := getTestConstantArrivalRateConfig()
+	config.Name = "test"
+	test := setupExecutorTest(t, "", "", lib.Options{}, runner, config)
+	defer test.cancel()
+
+	updatable, ok := test.executor.(lib.LiveUpdatableExecutor)
+	require.True(t, ok)
+	err := updatable.UpdateConfig(context.Background(), ScenarioUpdate{Rate: null.IntFrom(10)})
+	require.EqualError(t, err, "scenario test hasn't started yet")
+}
+
+func TestConstantArrivalRateUpdateRate(t *testing.T) {
+	t.Parallel()
+
+	var count int64
+	runner := simpleRunner(func(_ context.Context, _ *lib.State) error {
+		atomic.AddInt64(&count, 1)
+		return nil
+	})
+	config := getTestConstantArrivalRateConfig()
+	config.Rate = null.IntFrom(10)
+	config.Duration = types.NullDurationFrom(2 * time.Second)
+	test := setupExecutorTest(t, "", "", lib.Options{}, runner, config)
+	defer test.cancel()
+
+	updatable, ok := test.executor.(lib.LiveUpdatableExecutor)
+	require.True(t, ok)
+	go func() {
+		time.Sleep(time.Second)
+		assert.InDelta(t, 10, atomic.LoadInt64(&count), 1)
+		assert.NoError(t, updatable.UpdateConfig(test.ctx, ScenarioUpdate{Rate: null.IntFrom(40)}))
+		assert.ErrorContains(t, updatable.UpdateConfig(test.ctx, ScenarioUpdate{Rate: null.IntFrom(0)}),
+			"the rate must be more than 0")
+		assert.ErrorContains(t, updatable.UpdateConfig(test.ctx, ScenarioUpdate{SkipStages: null.IntFrom(1)}),
+			"constant-arrival-rate scenarios don't have stages")
+	}()
+
+	require.NoError(t, test.executor.Run(test.ctx, make(chan metrics.SampleContainer, 1000)))
+	assert.InDelta(t, 50, atomic.LoadInt64(&count), 2)
+	newConfig, ok := test.executor.GetConfig().(*ConstantArrivalRateConfig)
+	require.True(t, ok)
+	assert.Equal(t, null.IntFrom(40), newConfig.Rate)
+	assert.ErrorContains(t, updatable.UpdateConfig(test.ctx, ScenarioUpdate{Rate: null.IntFrom(10)}),
+		"has already finished")
+}
+
+func TestRampingArrivalRateChangeStages(t *testing.T) {
+	t.Parallel()
+
+	var count int64
+	runner := simpleRunner(func(_ context.Context, _ *lib.State) error {
+		atomic.AddInt64(&count, 1)
+		return nil
+	})
+	config := &RampingArrivalRateConfig{
+		BaseConfig:      BaseConfig{GracefulStop: types.NullDurationFrom(time.Second)},
+		TimeUnit:        types.NullDurationFrom(time.Second),
+		StartRate:       null.IntFrom(10),
+		Stages:          []Stage{{Duration: types.NullDurationFrom(5 * time.Second), Target: null.IntFrom(10)}},
+		PreAllocatedVUs: null.IntFrom(10),
+		MaxVUs:          null.IntFrom(20),
+	}
+	test := setupExecutorTest(t, "", "", lib.Options{}, runner, config)
+	defer test.cancel()
+
+	updatable, ok := test.executor.(lib.LiveUpdatableExecutor)
+	require.True(t, ok)
+	go func() {
+		time.Sleep(time.Second)
+		assert.NoError(t, updatable.UpdateConfig(test.ctx, ScenarioUpdate{
+			SkipStages: null.IntFrom(1),
+			AddStages:  []Stage{{Duration: types.NullDurationFrom(time.Second), Target: null.IntFrom(30)}},
+		}))
+		assert.ErrorContains(t, updatable.UpdateConfig(test.ctx, ScenarioUpdate{Rate: null.IntFrom(10)}),
+			"the rate of ramping-arrival-rate scenarios can't be changed")
+	}()
+
+	start := time.Now()
+	require.NoError(t, test.executor.Run(test.ctx, make(chan metrics.SampleContainer, 1000)))
+	assert.InDelta(t, 2*time.Second, time.Since(start), float64(200*time.Millisecond))
+	// 10 iterations in the first second and 20 while ramping up to 30 iterations/s
+	assert.InDelta(t, 30, atomic.LoadInt64(&count), 2)
+
+	newConfig, ok := test.executor.GetConfig().(*RampingArrivalRateConfig)
+	require.True(t, ok)
+	require.Len(t, newConfig.Stages, 2)
+	assert.Equal(t, null.IntFrom(30), newConfig.Stages[1].Target)
+}
+
+func TestRampingVUsChangeStages(t *testing.T) {
+	t.Parallel()
+
+	runner := simpleRunner(func(_ context.Context, _ *lib.State) error {
+		time.Sleep(50 * time.Millisecond)
+		return nil
+	})
+	config := RampingVUsConfig{
+		BaseConfig:       BaseConfig{GracefulStop: types.NullDurationFrom(0)},
+		GracefulRampDown: types.NullDurationFrom(0),
+		StartVUs:         null.IntFrom(4),
+		Stages:           []Stage{{Duration: types.NullDurationFrom(5 * time.Second), Target: null.IntFrom(4)}},
+	}
+	test := setupExecutorTest(t, "", "", lib.Options{}, runner, config)
+	defer test.cancel()
+
+	updatable, ok := test.executor.(lib.LiveUpdatableExecutor)
+	require.True(t, ok)
+	go func() {
+		time.Sleep(500 * time.Millisecond)
+		assert.Equal(t, int64(4), test.state.GetCurrentlyActiveVUsCount())
+		assert.ErrorContains(t, updatable.UpdateConfig(test.ctx, ScenarioUpdate{
+			AddStages: []Stage{{Duration: types.NullDurationFrom(time.Second), Target: null.IntFrom(5)}},
+		}), "the target of the added stage 1 can't be more than 4 VUs")
+		assert.NoError(t, updatable.UpdateConfig(test.ctx, ScenarioUpdate{
+			SkipStages: null.IntFrom(1),
+			AddStages:  []Stage{{Duration: types.NullDurationFrom(time.Second), Target: null.IntFrom(2)}},
+		}))
+		time.Sleep(200 * time.Millisecond)
+		// the VUs are ramped down, but they can't go below 2 by the end of the test
+		assert.LessOrEqual(t, test.state.GetCurrentlyActiveVUsCount(), int64(4))
+		assert.GreaterOrEqual(t, test.state.GetCurrentlyActiveVUsCount(), int64(2))
+	}()
+
+	start := time.Now()
+	require.NoError(t, test.executor.Run(test.ctx, nil))
+	assert.InDelta(t, 1500*time.Millisecond, time.Since(start), float64(200*time.Millisecond))
+
+	newConfig, ok := test.executor.GetConfig().(RampingVUsConfig)
+	require.True(t, ok)
+	require.Len(t, newConfig.Stages, 2)
+	assert.Equal(t, null.IntFrom(2), newConfig.Stages[1].Target)
+}
+
+func TestStopScenario(t *testing.T) {
+	t.Parallel()
+
+	runner := simpleRunner(func(_ context.Context, _ *lib.State) error {
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	})
+	configs := map[string]lib.ExecutorConfig{
+		"constant-vus": ConstantVUsConfig{
+			BaseConfig: BaseConfig{Name: "test", GracefulStop: types.NullDurationFrom(time.Second)},
+			VUs:        null.IntFrom(2),
+			Duration:   types.NullDurationFrom(5 * time.Second),
+		},
+		"constant-arrival-rate": func() lib.ExecutorConfig {
+			config := getTestConstantArrivalRateConfig()
+			config.Name = "test"
+			return config
+		}(),
+		"ramping-vus": RampingVUsConfig{
+			BaseConfig: BaseConfig{Name: "test", GracefulStop: types.NullDurationFrom(time.Second)},
+			StartVUs:   null.IntFrom(2),
+			Stages:     []Stage{{Duration: types.NullDurationFrom(5 * time.Second), Target: null.IntFrom(2)}},
+		},
+	}
+
+	for name, config := range configs {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			test := setupExecutorTest(t, "", "", lib.Options{}, runner, config)
+			defer test.cancel()
+
+			stoppable, ok := test.executor.(lib.StoppableExecutor)
+			require.True(t, ok)
+			go func() {
+				time.Sleep(500 * time.Millisecond)
+				assert.NoError(t, stoppable.Stop())
+			}()
+
+			start := time.Now()
+			require.NoError(t, test.executor.Run(test.ctx, make(chan metrics.SampleContainer, 1000)))
+			assert.Less(t, time.Since(start), 2*time.Second)
+
+			stoppedAt, stopped := test.state.GetScenarioStopTime("test")
+			assert.True(t, stopped)
+			assert.Zero(t, stoppedAt) // the execution state wasn't started
+		})
+	}
+}
+
+func TestStopScenarioBeforeStart(t *testing.T) {
+	t.Parallel()
+
+	var count int64
+	runner := simpleRunner(func(_ context.Context, _ *lib.State) error {
+		atomic.AddInt64(&count, 1)
+		return nil
+	})
+	config := RampingVUsConfig{
+		BaseConfig: BaseConfig{Name: "test", GracefulStop: types.NullDurationFrom(time.Second)},
+		StartVUs:   null.IntFrom(2),
+		Stages:     []Stage{{Duration: types.NullDurationFrom(5 * time.Second), Target: null.IntFrom(2)}},
+	}
+	test := setupExecutorTest(t, "", "", lib.Options{}, runner, config)
+	defer test.cancel()
+
+	stoppable, ok := test.executor.(lib.StoppableExecutor)
+	require.True(t, ok)
+	require.NoError(t, stoppable.Stop())
+	require.NoError(t, test.executor.Run(test.ctx, nil))
+	assert.Zero(t, atomic.LoadInt64(&count))
+	_, stopped := test.state.GetScenarioStopTime("test")
+	assert.True(t, stopped)
+}
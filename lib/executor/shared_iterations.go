@@ -171,7 +171,7 @@ func (si SharedIterations) Run(parentCtx context.Context, out chan<- metrics.Sam
 	gracefulStop := si.config.GetGracefulStop()
 
 	waitOnProgressChannel := make(chan struct{})
-	startTime, maxDurationCtx, regDurationCtx, cancel := getDurationContexts(parentCtx, duration, gracefulStop)
+	startTime, maxDurationCtx, regDurationCtx, cancel := si.getDurationContexts(parentCtx, duration, gracefulStop)
 	defer func() {
 		cancel()
 		<-waitOnProgressChannel
@@ -12,6 +12,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"go.k6.io/k6/internal/ui/pb"
+	"go.k6.io/k6/lib/fsext"
 	"go.k6.io/k6/metrics"
 )
 
@@ -105,6 +106,16 @@ type ScenarioState struct {
 	Name, Executor string
 	StartTime      time.Time
 	ProgressFn     func() (float64, []string)
+
+	// SustainableRateFn returns the highest arrival rate, in iterations per
+	// second, that the executor has found to be sustainable so far. It's nil
+	// for the executors that don't search for it.
+	SustainableRateFn func() (float64, bool)
+
+	// RecordFn returns the record of the log that the given iteration of the
+	// scenario, counted across all instances, replays. It's nil for the
+	// executors that don't replay a log.
+	RecordFn func(iterationInTest uint64) (any, bool)
 }
 
 // InitVUFunc is just a shorthand so we don't have to type the function
@@ -129,12 +140,56 @@ type PausableExecutor interface {
 }
 
 // LiveUpdatableExecutor should be implemented for the executors whose
-// configuration can be modified in the middle of the test execution. Currently,
-// only the manual execution executor implements it.
+// configuration can be modified in the middle of the test execution. Besides
+// the externally controlled executor, the arrival-rate and ramping VUs
+// executors implement it.
 type LiveUpdatableExecutor interface {
 	UpdateConfig(ctx context.Context, newConfig interface{}) error
 }
 
+// StoppableExecutor should be implemented by the executors whose scenarios can
+// be stopped before their regular end, without stopping the whole test. The
+// running iterations are still given the graceful stop period to finish.
+type StoppableExecutor interface {
+	Stop() error
+}
+
+// MetricsObserver gives access to the values of the metrics while the test is
+// running, so the executors can adapt the load to the system under test.
+type MetricsObserver interface {
+	// ObserveMetrics starts aggregating the samples of the given metrics or
+	// sub-metrics, e.g. `http_req_duration{status:200}`, that are emitted from
+	// now on, in new sinks. The returned function stops the observation and
+	// returns the sinks by the metric names.
+	ObserveMetrics(names []string) (stop func() map[string]metrics.Sink, err error)
+}
+
+// MetricsObservingExecutor should be implemented by the executors that need to
+// observe the metric values during the test execution. Currently, only the
+// breaking-point executor implements it.
+type MetricsObservingExecutor interface {
+	SetMetricsObserver(MetricsObserver)
+}
+
+// FileReadingExecutor should be implemented by the executors that read local
+// files, relative to the given working directory, when they are initialized.
+// Currently, only the replay executor implements it.
+type FileReadingExecutor interface {
+	SetFileSystem(fs fsext.Fs, pwd string)
+}
+
+// FileReadingExecutorConfig should be implemented by the configs of the executors
+// that read local files, so that the files can be loaded together with the test,
+// and be included in its archives, like the ones opened by the script.
+type FileReadingExecutorConfig interface {
+	ExecutorConfig
+	// GetFilePaths returns the paths of the files, as they are configured.
+	GetFilePaths() []string
+	// WithFilePaths returns a copy of the config with the paths of the files
+	// replaced with the given ones, which are in the order of GetFilePaths().
+	WithFilePaths(paths []string) ExecutorConfig
+}
+
 // ExecutorConfigConstructor is a simple function that returns a concrete
 // Config instance with the specified name and all default values correctly
 // initialized
@@ -160,6 +215,34 @@ func RegisterExecutorConfigType(configType string, constructor ExecutorConfigCon
 // ScenarioConfigs can contain mixed executor config types
 type ScenarioConfigs map[string]ExecutorConfig
 
+// WithFilePaths returns a copy of the scenario configs, with the paths of the files
+// read by the executors of the FileReadingExecutorConfig ones replaced with the
+// results of the given function.
+func (scs ScenarioConfigs) WithFilePaths(replace func(path string) (string, error)) (ScenarioConfigs, error) {
+	if scs == nil {
+		return nil, nil
+	}
+	result := make(ScenarioConfigs, len(scs))
+	for name, config := range scs {
+		frc, ok := config.(FileReadingExecutorConfig)
+		if !ok {
+			result[name] = config
+			continue
+		}
+		paths := frc.GetFilePaths()
+		replaced := make([]string, 0, len(paths))
+		for _, path := range paths {
+			newPath, err := replace(path)
+			if err != nil {
+				return nil, fmt.Errorf("scenario %s: %w", name, err)
+			}
+			replaced = append(replaced, newPath)
+		}
+		result[name] = frc.WithFilePaths(replaced)
+	}
+	return result, nil
+}
+
 // UnmarshalJSON implements the json.Unmarshaler interface in a two-step manner,
 // creating the correct type of configs based on the `type` property.
 func (scs *ScenarioConfigs) UnmarshalJSON(data []byte) error {
@@ -29,6 +29,12 @@ type OnlyCachedEnabler interface {
 	AllowOnlyCached()
 }
 
+// FileCacher caches files, so that they can be opened
+// even in the mode that allows only the already opened files
+type FileCacher interface {
+	CacheFile(path string) error
+}
+
 // CacheLayerGetter provide a direct access to a cache layer
 type CacheLayerGetter interface {
 	GetCachingFs() afero.Fs
@@ -58,6 +64,24 @@ func (c *CacheOnReadFs) AllowOnlyCached() {
 	c.lock.Unlock()
 }
 
+// CacheFile reads the file into the cache, and allows it to be opened in the
+// cached only mode too, e.g. for the files that are needed by the test, but
+// that aren't opened by the script.
+func (c *CacheOnReadFs) CacheFile(path string) error {
+	f, err := c.Fs.Open(path)
+	if err != nil {
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	c.cached[path] = true
+	c.lock.Unlock()
+	return nil
+}
+
 // Open opens file and track the history of opened files
 // if CacheOnReadFs is in the opened only mode it should return
 // an error if file wasn't open before
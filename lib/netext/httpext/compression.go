@@ -50,21 +50,12 @@ func compressBody(algos []CompressionType, body io.ReadCloser) (*bytes.Buffer, s
 			contentEncoding += ", "
 		}
 		contentEncoding += compressionType.String()
-		var w io.WriteCloser
-		switch compressionType {
-		case CompressionTypeGzip:
-			w = gzip.NewWriter(buf)
-		case CompressionTypeDeflate:
-			w = zlib.NewWriter(buf)
-		case CompressionTypeZstd:
-			w, _ = zstd.NewWriter(buf)
-		case CompressionTypeBr:
-			w = brotli.NewWriter(buf)
-		default:
-			return nil, "", fmt.Errorf("unknown compressionType %s", compressionType)
+		w, err := NewCompressionWriter(compressionType, buf)
+		if err != nil {
+			return nil, "", err
 		}
 		// we don't close in defer because zlib will write it's checksum again if it closes twice :(
-		_, err := io.Copy(w, prevBuf)
+		_, err = io.Copy(w, prevBuf)
 		if err != nil {
 			_ = w.Close()
 			return nil, "", err
@@ -78,6 +69,24 @@ func compressBody(algos []CompressionType, body io.ReadCloser) (*bytes.Buffer, s
 	return buf, contentEncoding, body.Close()
 }
 
+// NewCompressionWriter returns a writer compressing the data written to it with the given
+// compression type, and writing the compressed data to w. The writer must be closed to
+// flush the end of the compressed data.
+func NewCompressionWriter(compression CompressionType, w io.Writer) (io.WriteCloser, error) {
+	switch compression {
+	case CompressionTypeGzip:
+		return gzip.NewWriter(w), nil
+	case CompressionTypeDeflate:
+		return zlib.NewWriter(w), nil
+	case CompressionTypeZstd:
+		return zstd.NewWriter(w)
+	case CompressionTypeBr:
+		return brotli.NewWriter(w), nil
+	default:
+		return nil, fmt.Errorf("unknown compressionType %s", compression)
+	}
+}
+
 //nolint:gochecknoglobals
 var decompressionErrors = [...]error{
 	zlib.ErrChecksum, zlib.ErrDictionary, zlib.ErrHeader,
@@ -115,6 +124,25 @@ func wrapDecompressionError(err error) error {
 	return err
 }
 
+// decodeResponseBody returns the body of the response, transparently decompressed
+// if it has a content-encoding we support. If not, it's returned as it is.
+func decodeResponseBody(resp *http.Response) (*readCloser, error) {
+	rc := &readCloser{resp.Body}
+	contentEncodings := strings.Split(resp.Header.Get("Content-Encoding"), ",")
+	for i := len(contentEncodings) - 1; i >= 0; i-- {
+		contentEncoding := strings.TrimSpace(contentEncodings[i])
+		if compression, err := CompressionTypeString(contentEncoding); err == nil {
+			decoder, err := pickDecoder(compression, rc)
+			if err != nil {
+				return nil, newDecompressionError(err)
+			}
+
+			rc = &readCloser{decoder}
+		}
+	}
+	return rc, nil
+}
+
 func readResponseBody(
 	state *lib.State,
 	respType ResponseType,
@@ -132,7 +160,6 @@ func readResponseBody(
 		return nil, err
 	}
 
-	rc := &readCloser{resp.Body}
 	// Ensure that the entire response body is read and closed, e.g. in case of decoding errors
 	defer func(respBody io.ReadCloser) {
 		_, _ = io.Copy(io.Discard, respBody)
@@ -146,24 +173,14 @@ func readResponseBody(
 		// this also prevents trying to read
 		return nil, nil //nolint:nilnil
 	}
-	contentEncodings := strings.Split(resp.Header.Get("Content-Encoding"), ",")
-	// Transparently decompress the body if it's has a content-encoding we
-	// support. If not, simply return it as it is.
-	for i := len(contentEncodings) - 1; i >= 0; i-- {
-		contentEncoding := strings.TrimSpace(contentEncodings[i])
-		if compression, err := CompressionTypeString(contentEncoding); err == nil {
-			decoder, err := pickDecoder(compression, rc)
-			if err != nil {
-				return nil, newDecompressionError(err)
-			}
-
-			rc = &readCloser{decoder}
-		}
+	rc, err := decodeResponseBody(resp)
+	if err != nil {
+		return nil, err
 	}
 
 	buf := state.BufferPool.Get()
 	defer state.BufferPool.Put(buf)
-	_, err := io.Copy(buf, rc.Reader)
+	_, err = io.Copy(buf, rc.Reader)
 	if err != nil {
 		respErr = wrapDecompressionError(err)
 	}
@@ -194,17 +211,23 @@ func readResponseBody(
 }
 
 func pickDecoder(compression CompressionType, rc *readCloser) (io.Reader, error) {
+	return NewDecompressionReader(compression, rc)
+}
+
+// NewDecompressionReader returns a reader decompressing the data read from r, which is
+// compressed with the given compression type.
+func NewDecompressionReader(compression CompressionType, r io.Reader) (io.Reader, error) {
 	var decoder io.Reader
 	var err error
 	switch compression {
 	case CompressionTypeDeflate:
-		decoder, err = zlib.NewReader(rc)
+		decoder, err = zlib.NewReader(r)
 	case CompressionTypeGzip:
-		decoder, err = gzip.NewReader(rc)
+		decoder, err = gzip.NewReader(r)
 	case CompressionTypeZstd:
-		decoder, err = zstd.NewReader(rc)
+		decoder, err = zstd.NewReader(r)
 	case CompressionTypeBr:
-		decoder = brotli.NewReader(rc)
+		decoder = brotli.NewReader(r)
 	default:
 		// We have not implemented a compression ... :(
 		err = fmt.Errorf(
@@ -1,6 +1,7 @@
 package httpext
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
@@ -28,6 +29,7 @@ const (
 	defaultNetNonTCPErrorCode errCode = 1010
 	invalidURLErrorCode       errCode = 1020
 	requestTimeoutErrorCode   errCode = 1050
+	requestAbortedErrorCode   errCode = 1051
 	// DNS errors
 	defaultDNSErrorCode      errCode = 1100
 	dnsNoSuchHostErrorCode   errCode = 1101
@@ -82,6 +84,7 @@ const (
 	x509HostnameErrorCodeMsg    = "x509: certificate doesn't match hostname"
 	x509UnknownAuthority        = "x509: unknown authority"
 	requestTimeoutErrorCodeMsg  = "request timeout"
+	requestAbortedErrorCodeMsg  = "request aborted: %s"
 	invalidURLErrorCodeMsg      = "invalid URL"
 )
 
@@ -201,6 +204,24 @@ func errorCodeForError(err error) (errCode, string) {
 	}
 }
 
+// wrapAbortedError returns the error of a request whose context was canceled with a
+// specific cause, e.g. by the AbortSignal of a script, as one with its own error code
+// and the cause as its message.
+func wrapAbortedError(ctx context.Context, err error) error {
+	if err == nil || ctx.Err() == nil {
+		return err
+	}
+	cause := context.Cause(ctx)
+	if errors.Is(cause, context.Canceled) || errors.Is(cause, context.DeadlineExceeded) {
+		return err
+	}
+	var k6Err K6Error
+	if errors.As(err, &k6Err) && k6Err.Code == requestAbortedErrorCode {
+		return err
+	}
+	return NewK6Error(requestAbortedErrorCode, fmt.Sprintf(requestAbortedErrorCodeMsg, cause), cause)
+}
+
 // K6Error is a helper struct that enhances Go errors with custom k6-specific
 // error-codes and more user-readable error messages.
 type K6Error struct {
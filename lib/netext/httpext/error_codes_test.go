@@ -31,6 +31,25 @@ func TestDefaultError(t *testing.T) {
 	testErrorCode(t, defaultErrorCode, fmt.Errorf("random error"))
 }
 
+func TestAbortedError(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(errors.New("AbortError: signal is aborted without reason"))
+	err := wrapAbortedError(ctx, fmt.Errorf("request failed: %w", context.Canceled))
+	code, msg := errorCodeForError(err)
+	assert.Equal(t, requestAbortedErrorCode, code)
+	assert.Equal(t, "request aborted: AbortError: signal is aborted without reason", msg)
+	assert.Equal(t, err, wrapAbortedError(ctx, err))
+
+	// A context canceled without a specific cause, e.g. at the end of an iteration, isn't an abort
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	cancelCtx()
+	err = wrapAbortedError(ctx, context.Canceled)
+	code, _ = errorCodeForError(err)
+	assert.Equal(t, defaultErrorCode, code)
+}
+
 func TestDNSErrors(t *testing.T) {
 	t.Parallel()
 	var (
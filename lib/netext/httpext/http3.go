@@ -0,0 +1,63 @@
+package httpext
+
+import (
+	"context"
+	"crypto/tls"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+
+	"go.k6.io/k6/lib/netext"
+)
+
+type tracerKey struct{}
+
+func withTracer(ctx context.Context, tracer *Tracer) context.Context {
+	return context.WithValue(ctx, tracerKey{}, tracer)
+}
+
+func tracerFromContext(ctx context.Context) *Tracer {
+	tracer, _ := ctx.Value(tracerKey{}).(*Tracer)
+	return tracer
+}
+
+// NewHTTP3Transport returns a new HTTP/3 transport that makes its QUIC
+// connections with the provided dialer and TLS config. The TLS config is
+// cloned and gets a session cache, so connections to a host that was
+// already connected to can be resumed with 0-RTT, if the server allows it.
+func NewHTTP3Transport(dialer *netext.Dialer, tlsConfig *tls.Config) *http3.Transport {
+	tlsConfig = tlsConfig.Clone()
+	if tlsConfig.ClientSessionCache == nil {
+		tlsConfig.ClientSessionCache = tls.NewLRUClientSessionCache(0)
+	}
+
+	return &http3.Transport{
+		TLSClientConfig: tlsConfig,
+		// The responses are decompressed by MakeRequest, like with the TCP transport.
+		DisableCompression: true,
+		Dial: func(ctx context.Context, addr string, tlsConf *tls.Config, conf *quic.Config) (*quic.Conn, error) {
+			conn, err := dialer.DialQUIC(ctx, addr, tlsConf, conf)
+			if err != nil {
+				return nil, err
+			}
+			tracer := tracerFromContext(ctx)
+			if tracer == nil {
+				return conn, nil
+			}
+			select {
+			case <-conn.HandshakeComplete():
+				tracer.QUICHandshakeDone(conn.ConnectionState().Used0RTT)
+			default:
+				// With 0-RTT, the connection is returned before the handshake is done.
+				go func() {
+					select {
+					case <-conn.HandshakeComplete():
+						tracer.QUICHandshakeDone(conn.ConnectionState().Used0RTT)
+					case <-conn.Context().Done():
+					}
+				}()
+			}
+			return conn, nil
+		},
+	}
+}
@@ -0,0 +1,178 @@
+package httpext
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/netext"
+	"go.k6.io/k6/metrics"
+)
+
+func newHTTP3TestServer(t *testing.T, handler http.Handler) string {
+	t.Helper()
+
+	// only used for its certificate
+	tlsSrv := httptest.NewTLSServer(handler)
+	t.Cleanup(tlsSrv.Close)
+
+	udpConn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	srv := &http3.Server{
+		Handler:    handler,
+		TLSConfig:  http3.ConfigureTLSConfig(&tls.Config{Certificates: tlsSrv.TLS.Certificates}), //nolint:gosec
+		QUICConfig: &quic.Config{Allow0RTT: true},
+	}
+	go func() { _ = srv.Serve(udpConn) }()
+	t.Cleanup(func() {
+		_ = srv.Close()
+		_ = udpConn.Close()
+	})
+
+	return "https://" + udpConn.LocalAddr().String()
+}
+
+func TestMakeRequestHTTP3(t *testing.T) {
+	t.Parallel()
+
+	url := newHTTP3TestServer(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("hello over quic"))
+	}))
+
+	dialer := netext.NewDialer(net.Dialer{}, nil)
+	h3Transport := NewHTTP3Transport(dialer, &tls.Config{InsecureSkipVerify: true}) //nolint:gosec
+	t.Cleanup(func() { _ = h3Transport.Close() })
+
+	samples := make(chan metrics.SampleContainer, 10)
+	registry := metrics.NewRegistry()
+	state := &lib.State{
+		Options: lib.Options{
+			SystemTags: &metrics.DefaultSystemTagSet,
+		},
+		Transport:      http.DefaultTransport,
+		HTTP3Transport: h3Transport,
+		Dialer:         dialer,
+		Samples:        samples,
+		Logger:         logrus.New(),
+		BufferPool:     lib.NewBufferPool(),
+		BuiltinMetrics: metrics.RegisterBuiltinMetrics(registry),
+		Tags:           lib.NewVUStateTags(registry.RootTagSet()),
+	}
+
+	makeRequest := func() *Trail {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		require.NoError(t, err)
+		preq := &ParsedHTTPRequest{
+			Req:          req,
+			URL:          &URL{u: req.URL, URL: url},
+			Body:         new(bytes.Buffer),
+			Timeout:      10 * time.Second,
+			HTTP3:        true,
+			ResponseType: ResponseTypeText,
+			TagsAndMeta:  state.Tags.GetCurrentValues(),
+		}
+		res, err := MakeRequest(context.Background(), state, preq)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.Status)
+		assert.Equal(t, "HTTP/3.0", res.Proto)
+		assert.Equal(t, "hello over quic", res.Body)
+
+		require.Len(t, samples, 1)
+		trail, ok := (<-samples).(*Trail)
+		require.True(t, ok)
+		proto, _ := trail.Tags.Get(metrics.TagProto.String())
+		assert.Equal(t, "HTTP/3.0", proto)
+		assert.True(t, trail.HTTP3)
+		return trail
+	}
+	sampleValues := func(trail *Trail) map[string]float64 {
+		values := make(map[string]float64)
+		for _, s := range trail.GetSamples() {
+			values[s.Metric.Name] = s.Value
+		}
+		return values
+	}
+
+	t.Run("NewConnection", func(t *testing.T) {
+		trail := makeRequest()
+		assert.False(t, trail.ConnReused)
+		assert.Greater(t, trail.TLSHandshaking, time.Duration(0))
+		assert.Greater(t, trail.QUICHandshaking, time.Duration(0))
+
+		values := sampleValues(trail)
+		assert.Contains(t, values, metrics.HTTPReqQUICHandshakingName)
+		assert.Contains(t, values, metrics.HTTPReqQUIC0RTTName)
+		assert.Greater(t, dialer.BytesWritten, int64(0))
+		assert.Greater(t, dialer.BytesRead, int64(0))
+	})
+
+	t.Run("ReusedConnection", func(t *testing.T) {
+		trail := makeRequest()
+		assert.True(t, trail.ConnReused)
+		assert.Zero(t, trail.QUICHandshaking)
+
+		values := sampleValues(trail)
+		assert.Equal(t, 0.0, values[metrics.HTTPReqQUICHandshakingName])
+		assert.NotContains(t, values, metrics.HTTPReqQUIC0RTTName)
+	})
+
+	t.Run("ResumedConnection", func(t *testing.T) {
+		h3Transport.CloseIdleConnections()
+		trail := makeRequest()
+		assert.False(t, trail.ConnReused)
+		assert.Equal(t, 1.0, sampleValues(trail)[metrics.HTTPReqQUIC0RTTName])
+	})
+}
+
+func TestMakeRequestHTTP3PlainHTTP(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {}))
+	t.Cleanup(srv.Close)
+
+	samples := make(chan metrics.SampleContainer, 10)
+	registry := metrics.NewRegistry()
+	state := &lib.State{
+		Options: lib.Options{
+			SystemTags: &metrics.DefaultSystemTagSet,
+		},
+		Transport:      srv.Client().Transport,
+		HTTP3Transport: &http3.Transport{},
+		Samples:        samples,
+		Logger:         logrus.New(),
+		BufferPool:     lib.NewBufferPool(),
+		BuiltinMetrics: metrics.RegisterBuiltinMetrics(registry),
+		Tags:           lib.NewVUStateTags(registry.RootTagSet()),
+	}
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	preq := &ParsedHTTPRequest{
+		Req:         req,
+		URL:         &URL{u: req.URL, URL: srv.URL},
+		Body:        new(bytes.Buffer),
+		Timeout:     10 * time.Second,
+		HTTP3:       true,
+		TagsAndMeta: state.Tags.GetCurrentValues(),
+	}
+	res, err := MakeRequest(context.Background(), state, preq)
+	require.NoError(t, err)
+	assert.Equal(t, "HTTP/1.1", res.Proto)
+
+	require.Len(t, samples, 1)
+	trail, ok := (<-samples).(*Trail)
+	require.True(t, ok)
+	assert.False(t, trail.HTTP3)
+	assert.Len(t, trail.GetSamples(), 8)
+}
@@ -44,6 +44,7 @@ type ParsedHTTPRequest struct {
 	Timeout          time.Duration
 	Auth             string
 	Throw            bool
+	HTTP3            bool
 	ResponseType     ResponseType
 	ResponseCallback func(int) bool
 	Compressions     []CompressionType
@@ -51,6 +52,14 @@ type ParsedHTTPRequest struct {
 	ActiveJar        *cookiejar.Jar
 	Cookies          map[string]*HTTPRequestCookie
 	TagsAndMeta      metrics.TagsAndMeta
+
+	// BodyStream is the body of the request, instead of Body, when it's streamed.
+	// It's sent with the chunked transfer encoding, unless the Content-Length
+	// header is set, and it can't be compressed or sent again after a redirect.
+	BodyStream io.ReadCloser
+	// StreamResponseBody makes the body of the response available through
+	// Response.BodyStream, instead of it being read by MakeRequest.
+	StreamResponseBody bool
 }
 
 // Matches non-compliant io.Closer implementations (e.g. zstd.Decoder)
@@ -151,6 +160,15 @@ func MakeRequest(ctx context.Context, state *lib.State, preq *ParsedHTTPRequest)
 		}
 		// as per the documentation using GetBody still requires setting the Body.
 		preq.Req.Body, _ = preq.Req.GetBody()
+	} else if preq.BodyStream != nil {
+		if len(preq.Compressions) > 0 {
+			return nil, errors.New("the compression of streamed request bodies isn't supported")
+		}
+		preq.Req.Body = preq.BodyStream
+		preq.Req.ContentLength = -1 // unknown, unless the Content-Length header is set
+		if length, err := strconv.ParseInt(preq.Req.Header.Get("Content-Length"), 10, 64); err == nil && length >= 0 {
+			preq.Req.ContentLength = length
+		}
 	}
 
 	if contentLengthHeader := preq.Req.Header.Get("Content-Length"); contentLengthHeader != "" {
@@ -181,7 +199,7 @@ func MakeRequest(ctx context.Context, state *lib.State, preq *ParsedHTTPRequest)
 		}
 	}
 
-	tracerTransport := newTransport(ctx, state, &preq.TagsAndMeta, preq.ResponseCallback)
+	tracerTransport := newTransport(ctx, state, &preq.TagsAndMeta, preq.ResponseCallback, preq.HTTP3)
 	var transport http.RoundTripper = tracerTransport
 
 	if state.Options.HTTPDebug.String != "" {
@@ -261,7 +279,13 @@ func MakeRequest(ctx context.Context, state *lib.State, preq *ParsedHTTPRequest)
 	}
 
 	reqCtx, cancelFunc := context.WithTimeout(ctx, preq.Timeout)
-	defer cancelFunc()
+	var streamed bool
+	defer func() {
+		// the request of a streamed body is finished by it
+		if !streamed {
+			cancelFunc()
+		}
+	}()
 	mreq := preq.Req.WithContext(reqCtx)
 	res, resErr := client.Do(mreq)
 
@@ -274,16 +298,23 @@ func MakeRequest(ctx context.Context, state *lib.State, preq *ParsedHTTPRequest)
 		return nil, fmt.Errorf("unsupported response status: %s", res.Status)
 	}
 
-	if resErr == nil {
+	switch {
+	case resErr == nil && preq.StreamResponseBody:
+		resp.BodyStream, resErr = newStreamedBody(reqCtx, cancelFunc, res, tracerTransport)
+		streamed = resErr == nil
+	case resErr == nil:
 		resp.Body, resErr = readResponseBody(state, preq.ResponseType, res, resErr)
 		if resErr != nil && errors.Is(resErr, context.DeadlineExceeded) {
 			// TODO This can be more specific that the timeout happened in the middle of the reading of the body
 			resErr = NewK6Error(requestTimeoutErrorCode, requestTimeoutErrorCodeMsg, resErr)
 		}
 	}
-	finishedReq := tracerTransport.processLastSavedRequest(wrapDecompressionError(resErr))
-	if finishedReq != nil {
-		updateK6Response(resp, finishedReq)
+	resErr = wrapAbortedError(reqCtx, resErr)
+	if !streamed {
+		finishedReq := tracerTransport.processLastSavedRequest(wrapDecompressionError(resErr))
+		if finishedReq != nil {
+			updateK6Response(resp, finishedReq)
+		}
 	}
 
 	if resErr == nil {
@@ -10,6 +10,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"runtime"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -288,6 +289,132 @@ func TestMakeRequestTimeoutInTheMiddle(t *testing.T) {
 	}
 }
 
+func TestMakeRequestStreamedBodies(t *testing.T) {
+	t.Parallel()
+
+	newState := func(srv *httptest.Server) (*lib.State, chan metrics.SampleContainer) {
+		registry := metrics.NewRegistry()
+		samples := make(chan metrics.SampleContainer, 10)
+		return &lib.State{
+			Options:        lib.Options{SystemTags: &metrics.DefaultSystemTagSet},
+			Transport:      srv.Client().Transport,
+			Samples:        samples,
+			Logger:         logrus.New(),
+			BufferPool:     lib.NewBufferPool(),
+			BuiltinMetrics: metrics.RegisterBuiltinMetrics(registry),
+			Tags:           lib.NewVUStateTags(registry.RootTagSet()),
+		}, samples
+	}
+	newRequest := func(state *lib.State, method, url string, timeout time.Duration) *ParsedHTTPRequest {
+		req, _ := http.NewRequest(method, url, nil)
+		return &ParsedHTTPRequest{
+			Req:                req,
+			URL:                &URL{u: req.URL, URL: url},
+			Timeout:            timeout,
+			Throw:              true,
+			StreamResponseBody: true,
+			TagsAndMeta:        state.Tags.GetCurrentValues(),
+		}
+	}
+	errorTag := func(t *testing.T, samples chan metrics.SampleContainer) string {
+		t.Helper()
+		require.Len(t, samples, 1)
+		return (<-samples).GetSamples()[0].Tags.Map()["error"]
+	}
+
+	t.Run("Response", func(t *testing.T) {
+		t.Parallel()
+		proceed := make(chan struct{})
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte("first"))
+			w.(http.Flusher).Flush() //nolint:forcetypeassert
+			<-proceed
+			_, _ = w.Write([]byte(" second"))
+		}))
+		defer srv.Close()
+		state, samples := newState(srv)
+
+		res, err := MakeRequest(context.Background(), state, newRequest(state, http.MethodGet, srv.URL, time.Minute))
+		require.NoError(t, err)
+		require.NotNil(t, res.BodyStream)
+		assert.Equal(t, http.StatusOK, res.Status)
+		assert.Nil(t, res.Body)
+
+		buf := make([]byte, len("first"))
+		_, err = io.ReadFull(res.BodyStream, buf)
+		require.NoError(t, err)
+		assert.Equal(t, "first", string(buf))
+		// the request is finished only with the body
+		assert.Empty(t, samples)
+
+		close(proceed)
+		rest, err := io.ReadAll(res.BodyStream)
+		require.NoError(t, err)
+		assert.Equal(t, " second", string(rest))
+		assert.Empty(t, errorTag(t, samples))
+		require.NoError(t, res.BodyStream.Close())
+	})
+
+	t.Run("Request", func(t *testing.T) {
+		t.Parallel()
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Transfer-Encoding-Received", strings.Join(r.TransferEncoding, ","))
+			_, _ = io.Copy(w, r.Body)
+		}))
+		defer srv.Close()
+		state, _ := newState(srv)
+
+		pr, pw := io.Pipe()
+		go func() {
+			_, _ = pw.Write([]byte("hello "))
+			_, _ = pw.Write([]byte("world"))
+			_ = pw.Close()
+		}()
+		preq := newRequest(state, http.MethodPost, srv.URL, time.Minute)
+		preq.BodyStream = pr
+		res, err := MakeRequest(context.Background(), state, preq)
+		require.NoError(t, err)
+		assert.Equal(t, "chunked", res.Headers["Transfer-Encoding-Received"])
+		body, err := io.ReadAll(res.BodyStream)
+		require.NoError(t, err)
+		assert.Equal(t, "hello world", string(body))
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		t.Parallel()
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush() //nolint:forcetypeassert
+			<-r.Context().Done()
+		}))
+		defer srv.Close()
+		state, samples := newState(srv)
+
+		res, err := MakeRequest(context.Background(), state, newRequest(state, http.MethodGet, srv.URL, time.Minute))
+		require.NoError(t, err)
+		require.NoError(t, res.BodyStream.Close())
+		assert.Empty(t, errorTag(t, samples))
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		t.Parallel()
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush() //nolint:forcetypeassert
+			<-r.Context().Done()
+		}))
+		defer srv.Close()
+		state, samples := newState(srv)
+
+		res, err := MakeRequest(context.Background(), state,
+			newRequest(state, http.MethodGet, srv.URL, 50*time.Millisecond))
+		require.NoError(t, err)
+		_, err = io.ReadAll(res.BodyStream)
+		require.Error(t, err)
+		assert.Equal(t, "request timeout", errorTag(t, samples))
+	})
+}
+
 func BenchmarkWrapDecompressionError(b *testing.B) {
 	err := errors.New("error")
 	b.ResetTimer()
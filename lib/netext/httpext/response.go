@@ -2,6 +2,7 @@ package httpext
 
 import (
 	"crypto/tls"
+	"io"
 
 	"go.k6.io/k6/lib/netext"
 )
@@ -69,6 +70,12 @@ type Response struct {
 	Error          string                   `json:"error"`
 	ErrorCode      int                      `json:"error_code"`
 	Request        *Request                 `json:"request"`
+
+	// BodyStream is the body of the response when the request was made with
+	// ParsedHTTPRequest.StreamResponseBody. The metrics of the request are
+	// emitted once it's read completely, it fails or it's closed, so the timings,
+	// the remote address and the error of the response aren't set.
+	BodyStream io.ReadCloser `json:"-"`
 }
 
 // NewResponse returns an empty Response instance.
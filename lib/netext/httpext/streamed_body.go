@@ -0,0 +1,77 @@
+package httpext
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// streamedBody is the body of a response that is read by the caller of MakeRequest,
+// see ParsedHTTPRequest.StreamResponseBody. The request is finished, and its metrics
+// are emitted, once the body is read completely, reading it fails or it's closed.
+type streamedBody struct {
+	ctx       context.Context //nolint:containedctx
+	cancel    context.CancelFunc
+	transport *transport
+	response  *http.Response
+
+	mu      sync.Mutex // the body can be closed while it's read
+	decoded *readCloser
+	once    sync.Once
+}
+
+var _ io.ReadCloser = &streamedBody{}
+
+func newStreamedBody(
+	ctx context.Context, cancel context.CancelFunc, res *http.Response, t *transport,
+) (*streamedBody, error) {
+	decoded, err := decodeResponseBody(res)
+	if err != nil {
+		_ = res.Body.Close()
+		return nil, err
+	}
+	return &streamedBody{ctx: ctx, cancel: cancel, transport: t, response: res, decoded: decoded}, nil
+}
+
+// Read implements io.Reader.
+func (b *streamedBody) Read(p []byte) (int, error) {
+	b.mu.Lock()
+	n, err := b.decoded.Read(p)
+	b.mu.Unlock()
+	if err != nil {
+		b.finish(err)
+	}
+	return n, err
+}
+
+// Close implements io.Closer. The rest of the body is discarded.
+func (b *streamedBody) Close() error {
+	b.finish(nil)
+	return nil
+}
+
+// finish closes the body and emits the metrics of the request, with the error that
+// the reading of the body failed with, if there was one.
+func (b *streamedBody) finish(err error) {
+	b.once.Do(func() {
+		if errors.Is(err, io.EOF) {
+			err = nil
+		}
+		if err != nil && errors.Is(b.ctx.Err(), context.DeadlineExceeded) {
+			// TODO This can be more specific that the timeout happened in the middle of the reading of the body
+			err = NewK6Error(requestTimeoutErrorCode, requestTimeoutErrorCodeMsg, err)
+		}
+		err = wrapAbortedError(b.ctx, err)
+
+		// The raw body is closed first, so that a Read blocked on it returns.
+		_ = b.response.Body.Close()
+		b.mu.Lock()
+		_ = b.decoded.Close()
+		b.mu.Unlock()
+		b.cancel()
+
+		b.transport.processLastSavedRequest(wrapDecompressionError(err))
+	})
+}
@@ -33,6 +33,11 @@ type Trail struct {
 	ConnReused     bool
 	ConnRemoteAddr net.Addr
 
+	// HTTP/3 specific information, only set for requests made over QUIC.
+	HTTP3           bool
+	QUICHandshaking time.Duration // Executing the QUIC handshake, until it's confirmed.
+	QUICUsed0RTT    null.Bool     // Whether 0-RTT was used, only valid for new connections.
+
 	Failed null.Bool
 	// Populated by SaveSamples()
 	Tags     *metrics.TagSet
@@ -44,7 +49,7 @@ type Trail struct {
 func (tr *Trail) SaveSamples(builtinMetrics *metrics.BuiltinMetrics, ctm *metrics.TagsAndMeta) {
 	tr.Tags = ctm.Tags
 	tr.Metadata = ctm.Metadata
-	tr.Samples = make([]metrics.Sample, 0, 9) // this is with 1 more for a possible HTTPReqFailed
+	tr.Samples = make([]metrics.Sample, 0, 11) // this is with 3 more for a possible HTTPReqFailed and HTTP/3
 	tr.Samples = append(tr.Samples, []metrics.Sample{
 		{
 			TimeSeries: metrics.TimeSeries{
@@ -119,6 +124,30 @@ func (tr *Trail) SaveSamples(builtinMetrics *metrics.BuiltinMetrics, ctm *metric
 			Value:    metrics.D(tr.Receiving),
 		},
 	}...)
+
+	if !tr.HTTP3 {
+		return
+	}
+	tr.Samples = append(tr.Samples, metrics.Sample{
+		TimeSeries: metrics.TimeSeries{
+			Metric: builtinMetrics.HTTPReqQUICHandshaking,
+			Tags:   ctm.Tags,
+		},
+		Time:     tr.EndTime,
+		Metadata: ctm.Metadata,
+		Value:    metrics.D(tr.QUICHandshaking),
+	})
+	if tr.QUICUsed0RTT.Valid {
+		tr.Samples = append(tr.Samples, metrics.Sample{
+			TimeSeries: metrics.TimeSeries{
+				Metric: builtinMetrics.HTTPReqQUIC0RTT,
+				Tags:   ctm.Tags,
+			},
+			Time:     tr.EndTime,
+			Metadata: ctm.Metadata,
+			Value:    metrics.B(tr.QUICUsed0RTT.Bool),
+		})
+	}
 }
 
 // GetSamples implements the metrics.SampleContainer interface.
@@ -136,6 +165,22 @@ func (tr *Trail) GetTime() time.Time {
 	return tr.EndTime
 }
 
+// WithRedactedSamples returns a copy of the trail with the given samples, and
+// its tags and metadata replaced by the given functions. It implements the
+// output.RedactableSampleContainer interface, so the outputs still receive the
+// trails after the secrets in them are redacted.
+func (tr *Trail) WithRedactedSamples(
+	samples []metrics.Sample,
+	redactTags func(*metrics.TagSet) *metrics.TagSet,
+	redactMetadata func(map[string]string) map[string]string,
+) metrics.SampleContainer {
+	trail := *tr
+	trail.Samples = samples
+	trail.Tags = redactTags(tr.Tags)
+	trail.Metadata = redactMetadata(tr.Metadata)
+	return &trail
+}
+
 // Ensure that interfaces are implemented correctly
 var _ metrics.ConnectedSampleContainer = &Trail{}
 
@@ -156,6 +201,11 @@ type Tracer struct {
 
 	connReused     bool
 	connRemoteAddr net.Addr
+
+	// Set by the HTTP/3 transport, which has no httptrace hooks for them.
+	http3             bool
+	quicHandshakeDone int64
+	quicHandshake0RTT int64
 }
 
 // Trace returns a premade ClientTrace that calls all of the Tracer's hooks.
@@ -311,6 +361,16 @@ func (t *Tracer) GotFirstResponseByte() {
 	atomic.CompareAndSwapInt64(&t.gotFirstResponseByte, 0, now())
 }
 
+// QUICHandshakeDone is called when the handshake of a new QUIC connection
+// is confirmed, with whether 0-RTT was used for it. With 0-RTT, this can
+// happen after the request has already been sent.
+func (t *Tracer) QUICHandshakeDone(used0RTT bool) {
+	if used0RTT {
+		atomic.StoreInt64(&t.quicHandshake0RTT, 1)
+	}
+	atomic.CompareAndSwapInt64(&t.quicHandshakeDone, 0, now())
+}
+
 // Done calculates all metrics and should be called when the request is finished.
 func (t *Tracer) Done() *Trail {
 	done := time.Now()
@@ -380,5 +440,15 @@ func (t *Tracer) Done() *Trail {
 	trail.ConnDuration = trail.Connecting + trail.TLSHandshaking
 	trail.Duration = trail.Sending + trail.Waiting + trail.Receiving
 
+	if t.http3 {
+		trail.HTTP3 = true
+		// The handshake of a reused connection was measured by an earlier request.
+		quicHandshakeDone := atomic.LoadInt64(&t.quicHandshakeDone)
+		if !trail.ConnReused && quicHandshakeDone != 0 && tlsHandshakeStart != 0 {
+			trail.QUICHandshaking = time.Duration(quicHandshakeDone - tlsHandshakeStart)
+			trail.QUICUsed0RTT = null.BoolFrom(atomic.LoadInt64(&t.quicHandshake0RTT) == 1)
+		}
+	}
+
 	return &trail
 }
@@ -23,6 +23,7 @@ import (
 	"go.k6.io/k6/lib/netext"
 	"go.k6.io/k6/lib/types"
 	"go.k6.io/k6/metrics"
+	"go.k6.io/k6/output"
 )
 
 const traceDelay = 100 * time.Millisecond
@@ -290,3 +291,39 @@ func TestCancelledRequest(t *testing.T) {
 		}
 	})
 }
+
+var _ output.RedactableSampleContainer = &Trail{}
+
+func TestTrailWithRedactedSamples(t *testing.T) {
+	t.Parallel()
+
+	registry := metrics.NewRegistry()
+	metric := registry.MustNewMetric("http_reqs", metrics.Counter)
+	tags := registry.RootTagSet().With("url", "https://test.k6.io/?token=s3cr3t")
+	redactedTags := registry.RootTagSet().With("url", "https://test.k6.io/?token=***")
+	trail := &Trail{
+		EndTime:  time.Now(),
+		Duration: time.Second,
+		Tags:     tags,
+		Metadata: map[string]string{"trace_id": "s3cr3t"},
+		Samples:  []metrics.Sample{{TimeSeries: metrics.TimeSeries{Metric: metric, Tags: tags}, Value: 1}},
+	}
+	samples := []metrics.Sample{{TimeSeries: metrics.TimeSeries{Metric: metric, Tags: redactedTags}, Value: 1}}
+
+	redacted := trail.WithRedactedSamples(
+		samples,
+		func(*metrics.TagSet) *metrics.TagSet { return redactedTags },
+		func(map[string]string) map[string]string { return map[string]string{"trace_id": "***"} },
+	)
+
+	// some outputs, e.g. the cloud insights, need the trails
+	redactedTrail, ok := redacted.(*Trail)
+	require.True(t, ok)
+	assert.NotSame(t, trail, redactedTrail)
+	assert.Equal(t, trail.Duration, redactedTrail.Duration)
+	assert.Equal(t, samples, redactedTrail.Samples)
+	assert.Same(t, redactedTags, redactedTrail.Tags)
+	assert.Equal(t, map[string]string{"trace_id": "***"}, redactedTrail.Metadata)
+	assert.Same(t, tags, trail.Tags, "the original isn't changed")
+	assert.Equal(t, map[string]string{"trace_id": "s3cr3t"}, trail.Metadata)
+}
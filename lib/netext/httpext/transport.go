@@ -22,6 +22,7 @@ type transport struct {
 	state            *lib.State
 	tagsAndMeta      *metrics.TagsAndMeta
 	responseCallback func(int) bool
+	http3            bool
 
 	lastRequest     *unfinishedRequest
 	lastRequestLock *sync.Mutex
@@ -54,18 +55,21 @@ var _ http.RoundTripper = &transport{}
 // newTransport returns a new http.RoundTripper implementation that wraps around
 // the provided state's Transport. It uses a httpext.Tracer to measure all HTTP
 // requests made through it and annotates and emits the recorded metric samples
-// through the state.Samples channel.
+// through the state.Samples channel. If http3 is true, the HTTPS requests are
+// made through the state's HTTP3Transport instead.
 func newTransport(
 	ctx context.Context,
 	state *lib.State,
 	tagsAndMeta *metrics.TagsAndMeta,
 	responseCallback func(int) bool,
+	http3 bool,
 ) *transport {
 	return &transport{
 		ctx:              ctx,
 		state:            state,
 		tagsAndMeta:      tagsAndMeta,
 		responseCallback: responseCallback,
+		http3:            http3,
 		lastRequestLock:  new(sync.Mutex),
 	}
 }
@@ -201,9 +205,19 @@ func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
 
 	ctx := req.Context()
 	tracer := &Tracer{}
+	roundTripper := t.state.Transport
+	traceCtx := ctx
+	// HTTP/3 can only be used for HTTPS, so redirects to plain HTTP URLs are
+	// still followed, over TCP.
+	if t.http3 && req.URL.Scheme == "https" && t.state.HTTP3Transport != nil {
+		roundTripper = t.state.HTTP3Transport
+		tracer.http3 = true
+		traceCtx = withTracer(traceCtx, tracer)
+	}
 	// nosemgrep: dynamic-httptrace-clienttrace // this is a false possitive
-	reqWithTracer := req.WithContext(httptrace.WithClientTrace(ctx, tracer.Trace()))
-	resp, err := t.state.Transport.RoundTrip(reqWithTracer)
+	reqWithTracer := req.WithContext(httptrace.WithClientTrace(traceCtx, tracer.Trace()))
+	resp, err := roundTripper.RoundTrip(reqWithTracer)
+	err = wrapAbortedError(ctx, err)
 
 	var netError net.Error
 	if errors.As(err, &netError) && netError.Timeout() {
@@ -0,0 +1,123 @@
+package netext
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http/httptrace"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/quic-go/quic-go"
+)
+
+// DialQUIC establishes a new QUIC connection to the given address, with the
+// same k6 specifics as DialContext - blocked hostnames, hosts, DNS resolving
+// and blacklists. Every connection gets its own UDP socket, which is closed
+// together with the connection, and the sent and received data is added to
+// the same counters as the one of the TCP connections.
+//
+// If the context has a httptrace.ClientTrace, its connect hooks are called
+// around the creation of the UDP socket and its TLS hooks are called around
+// the QUIC handshake, which returns early if 0-RTT can be used.
+func (d *Dialer) DialQUIC(
+	ctx context.Context, addr string, tlsConf *tls.Config, conf *quic.Config,
+) (*quic.Conn, error) {
+	dialAddr, err := d.getDialAddr(addr)
+	if err != nil {
+		return nil, err
+	}
+	udpAddr, err := net.ResolveUDPAddr("udp", dialAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	var localAddr *net.UDPAddr
+	if tcpAddr, ok := d.LocalAddr.(*net.TCPAddr); ok && tcpAddr != nil {
+		localAddr = &net.UDPAddr{IP: tcpAddr.IP}
+	}
+	trace := httptrace.ContextClientTrace(ctx)
+	if trace != nil && trace.ConnectStart != nil {
+		trace.ConnectStart("udp", dialAddr)
+	}
+	udpConn, err := net.ListenUDP("udp", localAddr)
+	if trace != nil && trace.ConnectDone != nil {
+		trace.ConnectDone("udp", dialAddr, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if trace != nil && trace.TLSHandshakeStart != nil {
+		trace.TLSHandshakeStart()
+	}
+	pconn := &PacketConn{
+		PacketConn:   udpConn,
+		udpConn:      udpConn,
+		BytesRead:    &d.BytesRead,
+		BytesWritten: &d.BytesWritten,
+	}
+	conn, err := quic.DialEarly(ctx, pconn, udpAddr, tlsConf, conf)
+	if trace != nil && trace.TLSHandshakeDone != nil {
+		var state tls.ConnectionState
+		if conn != nil {
+			state = conn.ConnectionState().TLS
+		}
+		trace.TLSHandshakeDone(state, err)
+	}
+	if err != nil {
+		_ = udpConn.Close()
+		return nil, err
+	}
+	go func() {
+		<-conn.Context().Done()
+		_ = udpConn.Close()
+	}()
+	return conn, nil
+}
+
+// PacketConn wraps a UDP socket and keeps track of sent and received data
+// size. It intentionally doesn't expose the batched ReadMsgUDP/WriteMsgUDP
+// methods, so that quic-go uses ReadFrom and WriteTo for all of the packets.
+type PacketConn struct {
+	net.PacketConn
+
+	udpConn                 *net.UDPConn
+	BytesRead, BytesWritten *int64
+}
+
+var _ net.PacketConn = &PacketConn{}
+
+// ReadFrom reads a packet from the connection and counts its size.
+func (c *PacketConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	n, addr, err := c.PacketConn.ReadFrom(b)
+	if n > 0 {
+		atomic.AddInt64(c.BytesRead, int64(n))
+	}
+	return n, addr, err
+}
+
+// WriteTo writes a packet to the connection and counts its size.
+func (c *PacketConn) WriteTo(b []byte, addr net.Addr) (int, error) {
+	n, err := c.PacketConn.WriteTo(b, addr)
+	if n > 0 {
+		atomic.AddInt64(c.BytesWritten, int64(n))
+	}
+	return n, err
+}
+
+// SyscallConn returns the raw connection of the UDP socket, which quic-go
+// uses to tune the socket buffers and the DF bit.
+func (c *PacketConn) SyscallConn() (syscall.RawConn, error) {
+	return c.udpConn.SyscallConn()
+}
+
+// SetReadBuffer sets the size of the receive buffer of the UDP socket.
+func (c *PacketConn) SetReadBuffer(bytes int) error {
+	return c.udpConn.SetReadBuffer(bytes)
+}
+
+// SetWriteBuffer sets the size of the send buffer of the UDP socket.
+func (c *PacketConn) SetWriteBuffer(bytes int) error {
+	return c.udpConn.SetWriteBuffer(bytes)
+}
@@ -304,6 +304,10 @@ type Options struct {
 	// errors about running out of file handles or sockets, or being unable to bind addresses.
 	NoVUConnectionReuse null.Bool `json:"noVUConnectionReuse" envconfig:"K6_NO_VU_CONNECTION_REUSE"`
 
+	// Make HTTPS requests over HTTP/3 (QUIC) instead of HTTP/1.1 or HTTP/2.
+	// It can also be enabled or disabled for a single request with its params.
+	HTTP3 null.Bool `json:"http3" envconfig:"K6_HTTP3"`
+
 	// MinIterationDuration can be used to force VUs to pause between iterations if a specific
 	// iteration is shorter than the specified value.
 	MinIterationDuration types.NullDuration `json:"minIterationDuration" envconfig:"K6_MIN_ITERATION_DURATION"`
@@ -342,6 +346,10 @@ type Options struct {
 
 	// Specify client IP ranges and/or CIDR from which VUs will make requests
 	LocalIPs types.NullIPPool `json:"-" envconfig:"K6_LOCAL_IPS"`
+
+	// Directories the k6/experimental/fs module is allowed to write files in; writes are
+	// disabled when it's empty. It isn't part of the script options, so archives can't enable them.
+	FSAllowWrite []string `json:"-" envconfig:"K6_FS_ALLOW_WRITE"`
 }
 
 // Apply returns the result of overwriting any fields with any that are set on the argument.
@@ -466,6 +474,9 @@ func (o Options) Apply(opts Options) Options {
 	if opts.NoVUConnectionReuse.Valid {
 		o.NoVUConnectionReuse = opts.NoVUConnectionReuse
 	}
+	if opts.HTTP3.Valid {
+		o.HTTP3 = opts.HTTP3
+	}
 	if opts.MinIterationDuration.Valid {
 		o.MinIterationDuration = opts.MinIterationDuration
 	}
@@ -502,6 +513,9 @@ func (o Options) Apply(opts Options) Options {
 	if opts.LocalIPs.Valid {
 		o.LocalIPs = opts.LocalIPs
 	}
+	if opts.FSAllowWrite != nil {
+		o.FSAllowWrite = opts.FSAllowWrite
+	}
 	if opts.DNS.TTL.Valid {
 		o.DNS.TTL = opts.DNS.TTL
 	}
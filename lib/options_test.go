@@ -412,6 +412,12 @@ func TestOptions(t *testing.T) {
 		assert.True(t, opts.NoVUConnectionReuse.Valid)
 		assert.True(t, opts.NoVUConnectionReuse.Bool)
 	})
+	t.Run("HTTP3", func(t *testing.T) {
+		t.Parallel()
+		opts := Options{}.Apply(Options{HTTP3: null.BoolFrom(true)})
+		assert.True(t, opts.HTTP3.Valid)
+		assert.True(t, opts.HTTP3.Bool)
+	})
 	t.Run("NoCookiesReset", func(t *testing.T) {
 		t.Parallel()
 		opts := Options{}.Apply(Options{NoCookiesReset: null.BoolFrom(true)})
@@ -564,6 +570,13 @@ func TestOptions(t *testing.T) {
 		opts := Options{}.Apply(Options{LocalIPs: clientIPRanges})
 		assert.NotNil(t, opts.LocalIPs)
 	})
+	t.Run("FSAllowWrite", func(t *testing.T) {
+		t.Parallel()
+		opts := Options{FSAllowWrite: []string{"/tmp"}}.Apply(Options{})
+		assert.Equal(t, []string{"/tmp"}, opts.FSAllowWrite)
+		opts = opts.Apply(Options{FSAllowWrite: []string{"results", "fixtures"}})
+		assert.Equal(t, []string{"results", "fixtures"}, opts.FSAllowWrite)
+	})
 }
 
 func TestOptionsEnv(t *testing.T) {
@@ -640,6 +653,11 @@ func TestOptionsEnv(t *testing.T) {
 			"true":  null.BoolFrom(true),
 			"false": null.BoolFrom(false),
 		},
+		{"HTTP3", "K6_HTTP3"}: {
+			"":      null.Bool{},
+			"true":  null.BoolFrom(true),
+			"false": null.BoolFrom(false),
+		},
 		{"UserAgent", "K6_USER_AGENT"}: {
 			"":    null.String{},
 			"Hi!": null.StringFrom("Hi!"),
@@ -649,6 +667,11 @@ func TestOptionsEnv(t *testing.T) {
 			"192.168.220.2":    mustNullIPPool("192.168.220.2"),
 			"192.168.220.0/24": mustNullIPPool("192.168.220.0/24"),
 		},
+		{"FSAllowWrite", "K6_FS_ALLOW_WRITE"}: {
+			"":                []string{},
+			"results":         []string{"results"},
+			"results,scratch": []string{"results", "scratch"},
+		},
 		{"Throw", "K6_THROW"}: {
 			"":      null.Bool{},
 			"true":  null.BoolFrom(true),
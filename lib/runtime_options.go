@@ -5,6 +5,8 @@ import (
 	"strings"
 
 	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/lib/types"
 )
 
 // CompatibilityMode specifies the JS compatibility mode
@@ -43,8 +45,19 @@ type RuntimeOptions struct {
 	NoSummary     null.Bool   `json:"noSummary"`
 	SummaryMode   null.String `json:"summaryMode"`
 	SummaryExport null.String `json:"summaryExport"`
+	SummaryJSON   null.String `json:"summaryJSON"`
+	SummaryHTML   null.String `json:"summaryHTML"`
+	SummaryJUnit  null.String `json:"summaryJUnit"`
 	KeyWriter     null.String `json:"-"`
 	TracesOutput  null.String `json:"tracesOutput"`
+
+	// The size of the time buckets for which the summary also keeps the
+	// aggregated values of the main metrics, if set
+	SummaryTimeline types.NullDuration `json:"summaryTimeline"`
+
+	// How the Trend metric sinks store the observed values: "exact" (all
+	// values) or "hdr" (bounded-memory histograms with estimated percentiles)
+	TrendSinkMode null.String `json:"trendSinkMode"`
 }
 
 // ValidateCompatibilityMode checks if the provided val is a valid compatibility mode
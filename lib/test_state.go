@@ -10,6 +10,7 @@ import (
 	"go.k6.io/k6/internal/event"
 	"go.k6.io/k6/internal/lib/trace"
 	"go.k6.io/k6/internal/usage"
+	"go.k6.io/k6/lib/fsext"
 	"go.k6.io/k6/metrics"
 	"go.k6.io/k6/secretsource"
 )
@@ -28,6 +29,10 @@ type TestPreInitState struct {
 	Usage          *usage.Usage
 	SecretsManager *secretsource.Manager
 
+	// WritableFS is the file system the scripts can write files to, in the
+	// directories allowed by the FSAllowWrite option.
+	WritableFS fsext.Fs
+
 	// FIXME (@oleiade): is this the way?
 	TestStatus *TestStatus
 }
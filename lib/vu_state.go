@@ -52,6 +52,10 @@ type State struct {
 	CookieJar *cookiejar.Jar
 	TLSConfig *tls.Config
 
+	// HTTP3Transport is used instead of Transport for the HTTPS requests
+	// that should be made over HTTP/3. It can be nil, e.g. in tests.
+	HTTP3Transport http.RoundTripper
+
 	// Rate limits.
 	RPSLimit *rate.Limiter
 
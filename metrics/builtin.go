@@ -7,6 +7,7 @@ const (
 	IterationsName        = "iterations"
 	IterationDurationName = "iteration_duration"
 	DroppedIterationsName = "dropped_iterations"
+	SustainableRateName   = "sustainable_rate"
 
 	ChecksName        = "checks"
 	GroupDurationName = "group_duration"
@@ -21,6 +22,9 @@ const (
 	HTTPReqWaitingName        = "http_req_waiting"
 	HTTPReqReceivingName      = "http_req_receiving"
 
+	HTTPReqQUICHandshakingName = "http_req_quic_handshaking"
+	HTTPReqQUIC0RTTName        = "http_req_quic_0rtt"
+
 	WSSessionsName         = "ws_sessions"
 	WSMessagesSentName     = "ws_msgs_sent"
 	WSMessagesReceivedName = "ws_msgs_received"
@@ -41,6 +45,7 @@ type BuiltinMetrics struct {
 	Iterations        *Metric
 	IterationDuration *Metric
 	DroppedIterations *Metric
+	SustainableRate   *Metric
 
 	// Runner-emitted.
 	Checks        *Metric
@@ -57,6 +62,10 @@ type BuiltinMetrics struct {
 	HTTPReqWaiting        *Metric
 	HTTPReqReceiving      *Metric
 
+	// HTTP/3-related
+	HTTPReqQUICHandshaking *Metric
+	HTTPReqQUIC0RTT        *Metric
+
 	// Websocket-related
 	WSSessions         *Metric
 	WSMessagesSent     *Metric
@@ -81,6 +90,7 @@ func RegisterBuiltinMetrics(registry *Registry) *BuiltinMetrics {
 		Iterations:        registry.MustNewMetric(IterationsName, Counter),
 		IterationDuration: registry.MustNewMetric(IterationDurationName, Trend, Time),
 		DroppedIterations: registry.MustNewMetric(DroppedIterationsName, Counter),
+		SustainableRate:   registry.MustNewMetric(SustainableRateName, Gauge),
 
 		Checks:        registry.MustNewMetric(ChecksName, Rate),
 		GroupDuration: registry.MustNewMetric(GroupDurationName, Trend, Time),
@@ -95,6 +105,9 @@ func RegisterBuiltinMetrics(registry *Registry) *BuiltinMetrics {
 		HTTPReqWaiting:        registry.MustNewMetric(HTTPReqWaitingName, Trend, Time),
 		HTTPReqReceiving:      registry.MustNewMetric(HTTPReqReceivingName, Trend, Time),
 
+		HTTPReqQUICHandshaking: registry.MustNewMetric(HTTPReqQUICHandshakingName, Trend, Time),
+		HTTPReqQUIC0RTT:        registry.MustNewMetric(HTTPReqQUIC0RTTName, Rate),
+
 		WSSessions:         registry.MustNewMetric(WSSessionsName, Counter),
 		WSMessagesSent:     registry.MustNewMetric(WSMessagesSentName, Counter),
 		WSMessagesReceived: registry.MustNewMetric(WSMessagesReceivedName, Counter),
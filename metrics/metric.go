@@ -25,6 +25,15 @@ type Metric struct {
 	Observed   bool         `json:"-"`
 }
 
+// NewSink returns a new empty Sink for the values of the metric, of the same
+// kind as the sinks that the registry of the metric creates.
+func (m *Metric) NewSink() Sink {
+	if m.registry == nil {
+		return NewSink(m.Type)
+	}
+	return newSink(m.Type, m.registry.TrendSinkMode())
+}
+
 // A Submetric represents a filtered dataset based on a parent metric.
 type Submetric struct {
 	Name   string  `json:"name"`
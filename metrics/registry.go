@@ -13,6 +13,8 @@ type Registry struct {
 	metrics map[string]*Metric
 	l       sync.RWMutex
 
+	trendSinkMode TrendSinkMode
+
 	rootTagSet *atlas.Node
 }
 
@@ -26,6 +28,23 @@ func NewRegistry() *Registry {
 	}
 }
 
+// SetTrendSinkMode sets how the sinks of the Trend metrics created by the
+// registry, and by all of their sub-metrics, store the observed values. It
+// should be called before any metrics are created, since it doesn't affect the
+// already existing ones.
+func (r *Registry) SetTrendSinkMode(mode TrendSinkMode) {
+	r.l.Lock()
+	defer r.l.Unlock()
+	r.trendSinkMode = mode
+}
+
+// TrendSinkMode returns the mode of the Trend sinks created by the registry.
+func (r *Registry) TrendSinkMode() TrendSinkMode {
+	r.l.RLock()
+	defer r.l.RUnlock()
+	return r.trendSinkMode
+}
+
 const (
 	nameRegexString = "^[a-zA-Z_][a-zA-Z0-9_]{1,128}$"
 	badNameWarning  = "Metric names must only include up to 128 ASCII letters, numbers, or underscores " +
@@ -96,7 +115,7 @@ func (r *Registry) newMetric(name string, mt MetricType, vt ...ValueType) *Metri
 		valueType = vt[0]
 	}
 
-	sink := NewSink(mt)
+	sink := newSink(mt, r.trendSinkMode)
 	return &Metric{
 		registry: r,
 		Name:     name,
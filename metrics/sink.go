@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"strings"
 	"time"
+
+	"go.k6.io/k6/internal/ds/histogram"
 )
 
 var (
@@ -24,6 +27,10 @@ type Sink interface {
 // NewSink creates the related Sink for
 // the provided MetricType.
 func NewSink(mt MetricType) Sink {
+	return newSink(mt, TrendSinkModeExact)
+}
+
+func newSink(mt MetricType, trendSinkMode TrendSinkMode) Sink {
 	var sink Sink
 	switch mt {
 	case Counter:
@@ -31,7 +38,11 @@ func NewSink(mt MetricType) Sink {
 	case Gauge:
 		sink = &GaugeSink{}
 	case Trend:
-		sink = NewTrendSink()
+		if trendSinkMode == TrendSinkModeHDR {
+			sink = NewHDRTrendSink()
+		} else {
+			sink = NewTrendSink()
+		}
 	case Rate:
 		sink = &RateSink{}
 	default:
@@ -104,16 +115,74 @@ func (g *GaugeSink) Format(_ time.Duration) map[string]float64 {
 	return map[string]float64{"value": g.Value}
 }
 
-// NewTrendSink makes a Trend sink with the OpenHistogram circllhist histogram.
+// TrendSinkMode specifies how a TrendSink stores the observed values.
+type TrendSinkMode uint8
+
+// Possible values for TrendSinkMode.
+const (
+	// TrendSinkModeExact keeps every observed value, so the percentiles are
+	// exact, but the memory usage grows with the number of observed values.
+	TrendSinkModeExact TrendSinkMode = iota
+
+	// TrendSinkModeHDR keeps the observed values in HDR histograms, so the
+	// memory usage is bounded, but the percentiles are estimations. The error
+	// of each estimated value is at most the bigger of 1/256 (~0.4%) of the
+	// exact value or 0.0005, while min, max, avg, count and sum stay exact.
+	TrendSinkModeHDR
+)
+
+const (
+	trendSinkModeExactString = "exact"
+	trendSinkModeHDRString   = "hdr"
+)
+
+// ParseTrendSinkMode returns the TrendSinkMode with the given name. An empty
+// string is considered to be the default TrendSinkModeExact.
+func ParseTrendSinkMode(s string) (TrendSinkMode, error) {
+	switch strings.TrimSpace(s) {
+	case "", trendSinkModeExactString:
+		return TrendSinkModeExact, nil
+	case trendSinkModeHDRString:
+		return TrendSinkModeHDR, nil
+	default:
+		return TrendSinkModeExact, fmt.Errorf(
+			"invalid trend sink mode %q, use %q or %q", s, trendSinkModeExactString, trendSinkModeHDRString,
+		)
+	}
+}
+
+// String returns the name of the TrendSinkMode.
+func (m TrendSinkMode) String() string {
+	if m == TrendSinkModeHDR {
+		return trendSinkModeHDRString
+	}
+	return trendSinkModeExactString
+}
+
+// NewTrendSink makes a Trend sink that keeps all of the observed values.
 func NewTrendSink() *TrendSink {
 	return &TrendSink{}
 }
 
+// NewHDRTrendSink makes a Trend sink that keeps the observed values in
+// bounded-memory HDR histograms. See TrendSinkModeHDR for its accuracy.
+func NewHDRTrendSink() *TrendSink {
+	return &TrendSink{
+		hdr:         histogram.NewHdr(),
+		negativeHdr: histogram.NewHdr(),
+	}
+}
+
 // TrendSink is a sink for a Trend
 type TrendSink struct {
 	values []float64
 	sorted bool
 
+	// These are used instead of the values slice by the HDR trend sinks. Since
+	// the histograms only track non-negative values, the negative ones are
+	// negated and tracked separately.
+	hdr, negativeHdr *histogram.Hdr
+
 	count    uint64
 	min, max float64
 	sum      float64
@@ -135,18 +204,27 @@ func (t *TrendSink) Add(s Sample) {
 		}
 	}
 
-	t.values = append(t.values, s.Value)
-	t.sorted = false
+	switch {
+	case t.hdr == nil:
+		t.values = append(t.values, s.Value)
+		t.sorted = false
+	case s.Value < 0:
+		t.negativeHdr.Add(-s.Value)
+	default:
+		t.hdr.Add(s.Value)
+	}
 	t.count++
 	t.sum += s.Value
 }
 
 // P calculates the given percentile from sink values.
 func (t *TrendSink) P(pct float64) float64 {
-	switch t.count {
-	case 0:
+	switch {
+	case t.count == 0:
 		return 0
-	case 1:
+	case t.hdr != nil:
+		return t.estimatedP(pct)
+	case t.count == 1:
 		return t.values[0]
 	default:
 		if !t.sorted {
@@ -165,6 +243,26 @@ func (t *TrendSink) P(pct float64) float64 {
 	}
 }
 
+// estimatedP calculates the given percentile from the HDR histograms, in the
+// same way P() does it for the exact values.
+func (t *TrendSink) estimatedP(pct float64) float64 {
+	i := pct * (float64(t.count) - 1.0)
+	j := t.estimatedValueAt(uint64(math.Floor(i)))
+	k := t.estimatedValueAt(uint64(math.Ceil(i)))
+	f := i - math.Floor(i)
+	return j + (k-j)*f
+}
+
+// estimatedValueAt returns the estimated value at the given rank.
+func (t *TrendSink) estimatedValueAt(rank uint64) float64 {
+	negativeCount := t.negativeHdr.Count
+	if rank < negativeCount {
+		// the biggest absolute values are the smallest negative ones
+		return -t.negativeHdr.ValueAt(negativeCount - 1 - rank)
+	}
+	return t.hdr.ValueAt(rank - negativeCount)
+}
+
 // Min returns the minimum value.
 func (t *TrendSink) Min() float64 {
 	return t.min
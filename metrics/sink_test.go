@@ -236,6 +236,80 @@ func TestTrendSink(t *testing.T) {
 	})
 }
 
+func TestHDRTrendSink(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no values", func(t *testing.T) {
+		t.Parallel()
+
+		sink := NewHDRTrendSink()
+		assert.True(t, sink.IsEmpty())
+		assert.Equal(t, 0.0, sink.P(0.95))
+	})
+	t.Run("one value", func(t *testing.T) {
+		t.Parallel()
+
+		sink := NewHDRTrendSink()
+		sink.Add(Sample{TimeSeries: TimeSeries{Metric: &Metric{}}, Value: 10.0})
+		assert.Equal(t, 10.0, sink.P(0.0))
+		assert.Equal(t, 10.0, sink.P(0.5))
+		assert.Equal(t, 10.0, sink.P(1.0))
+		assert.Empty(t, sink.values)
+	})
+	t.Run("accuracy", func(t *testing.T) {
+		t.Parallel()
+
+		exact, estimated := NewTrendSink(), NewHDRTrendSink()
+		for i := 0; i < 100000; i++ {
+			// a long-tailed distribution of values, including some negative ones
+			v := math.Pow(float64(i%1000)/10, 3)/100 - 5
+			sample := Sample{TimeSeries: TimeSeries{Metric: &Metric{}}, Value: v}
+			exact.Add(sample)
+			estimated.Add(sample)
+		}
+
+		assert.Equal(t, exact.Count(), estimated.Count())
+		assert.Equal(t, exact.Min(), estimated.Min())
+		assert.Equal(t, exact.Max(), estimated.Max())
+		assert.InDelta(t, exact.Avg(), estimated.Avg(), 0.000001)
+		for _, pct := range []float64{0, 0.01, 0.1, 0.25, 0.5, 0.9, 0.95, 0.99, 0.999, 1} {
+			exp := exact.P(pct)
+			assert.InDelta(t, exp, estimated.P(pct), math.Max(math.Abs(exp)/256, 0.0005), pct)
+		}
+		assert.Empty(t, estimated.values)
+	})
+}
+
+func TestRegistryTrendSinkMode(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	r.SetTrendSinkMode(TrendSinkModeHDR)
+	m, err := r.NewMetric("my_trend", Trend)
+	require.NoError(t, err)
+	require.NotNil(t, m.Sink.(*TrendSink).hdr) //nolint:forcetypeassert
+
+	sm, err := m.AddSubmetric("a:1")
+	require.NoError(t, err)
+	require.NotNil(t, sm.Metric.Sink.(*TrendSink).hdr) //nolint:forcetypeassert
+	require.NotNil(t, m.NewSink().(*TrendSink).hdr)    //nolint:forcetypeassert
+}
+
+func TestParseTrendSinkMode(t *testing.T) {
+	t.Parallel()
+
+	for input, exp := range map[string]TrendSinkMode{
+		"": TrendSinkModeExact, "exact": TrendSinkModeExact, "hdr": TrendSinkModeHDR,
+	} {
+		mode, err := ParseTrendSinkMode(input)
+		require.NoError(t, err)
+		assert.Equal(t, exp, mode)
+	}
+
+	_, err := ParseTrendSinkMode("tdigest")
+	require.ErrorContains(t, err, `invalid trend sink mode "tdigest"`)
+}
+
 func TestRateSink(t *testing.T) {
 	t.Parallel()
 	samples6 := []float64{1.0, 0.0, 1.0, 0.0, 0.0, 1.0}
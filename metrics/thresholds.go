@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -26,6 +27,9 @@ type Threshold struct {
 	AbortGracePeriod types.NullDuration
 	// parsed is the threshold expression parsed from the Source
 	parsed *thresholdExpression
+	// windowFailed is a marker if a time-windowed threshold failed in any of
+	// the windows it was evaluated for, which fails it for the rest of the test
+	windowFailed bool
 }
 
 func newThreshold(src string, abortOnFail bool, gracePeriod types.NullDuration) *Threshold {
@@ -79,9 +83,15 @@ func (t *Threshold) runNoTaint(sinks map[string]float64) (bool, error) {
 	return passes, nil
 }
 
+// run evaluates the threshold and returns if it passes with the current values.
+// Once a time-windowed threshold fails, it's marked as failed even if the
+// next windows pass, but only the current windows are considered for aborting.
 func (t *Threshold) run(sinks map[string]float64) (bool, error) {
 	passes, err := t.runNoTaint(sinks)
-	t.LastFailed = !passes
+	if !passes && t.parsed != nil && t.parsed.Window > 0 {
+		t.windowFailed = true
+	}
+	t.LastFailed = !passes || t.windowFailed
 	return passes, err
 }
 
@@ -150,16 +160,16 @@ func (ts *Thresholds) runAll(timeSpentInTest time.Duration) (bool, error) {
 			return false, fmt.Errorf("threshold %d run error: %w", i, err)
 		}
 
-		if !b {
+		if threshold.LastFailed {
 			succeeded = false
+		}
 
-			if ts.Abort || !threshold.AbortOnFail {
-				continue
-			}
-
-			ts.Abort = !threshold.AbortGracePeriod.Valid ||
-				threshold.AbortGracePeriod.Duration < types.Duration(timeSpentInTest)
+		if b || ts.Abort || !threshold.AbortOnFail {
+			continue
 		}
+
+		ts.Abort = !threshold.AbortGracePeriod.Valid ||
+			threshold.AbortGracePeriod.Duration < types.Duration(timeSpentInTest)
 	}
 
 	return succeeded, nil
@@ -168,9 +178,60 @@ func (ts *Thresholds) runAll(timeSpentInTest time.Duration) (bool, error) {
 // Run processes all the thresholds with the provided Sink at the provided time and returns if any
 // of them fails
 func (ts *Thresholds) Run(sink Sink, duration time.Duration) (bool, error) {
+	return ts.RunWithWindows(sink, nil, duration)
+}
+
+// RunWithWindows processes all the thresholds like Run does, but it also evaluates
+// the time-windowed thresholds against the provided windowSinks, which should only
+// contain the samples of the last window of the respective duration. Windowed
+// thresholds without a matching sink, or with an empty non-counter one, are
+// considered as having no samples yet and they aren't evaluated.
+//
+// A time-windowed threshold that fails for any of the evaluated windows keeps
+// failing for the rest of the test, so the end-of-test result covers all of
+// them and not just the last one.
+func (ts *Thresholds) RunWithWindows(
+	sink Sink, windowSinks map[time.Duration]Sink, duration time.Duration,
+) (bool, error) {
 	// Initialize the sinks store
 	ts.sinked = make(map[string]float64)
 
+	if err := ts.sinkValues(sink, 0, duration); err != nil {
+		return false, err
+	}
+
+	for window, windowSink := range windowSinks {
+		// Counters are still meaningful without any samples in the window,
+		// everything else would just report some zero values.
+		if _, isCounter := windowSink.(*CounterSink); windowSink.IsEmpty() && !isCounter {
+			continue
+		}
+
+		// At the start of the test, a window can't cover more than the time
+		// that has elapsed, so we use it to calculate the windowed rates.
+		if err := ts.sinkValues(windowSink, window, min(window, duration)); err != nil {
+			return false, err
+		}
+	}
+
+	return ts.runAll(duration)
+}
+
+// sinkValues fills the sinks store with the values of the aggregation methods
+// used by the thresholds with the provided window, for the provided sink.
+func (ts *Thresholds) sinkValues(sink Sink, window time.Duration, duration time.Duration) error {
+	// store sets the value for all the thresholds with the given window and
+	// aggregation method, using their keys, which also encode the window.
+	store := func(method string, value func() float64) {
+		for _, threshold := range ts.Thresholds {
+			if threshold.parsed == nil || threshold.parsed.Window != window ||
+				threshold.parsed.AggregationMethod != method {
+				continue
+			}
+			ts.sinked[threshold.parsed.SinkKey()] = value()
+		}
+	}
+
 	// FIXME: Remove this comment as soon as the metrics.Sink does not expose Format anymore.
 	//
 	// As of December 2021, this block reproduces the behavior of the
@@ -180,37 +241,95 @@ func (ts *Thresholds) Run(sink Sink, duration time.Duration) (bool, error) {
 	// For more details, see https://github.com/grafana/k6/issues/2320
 	switch sinkImpl := sink.(type) {
 	case *CounterSink:
-		ts.sinked["count"] = sinkImpl.Value
-		ts.sinked["rate"] = sinkImpl.Value / (float64(duration) / float64(time.Second))
+		if window == 0 {
+			ts.sinked["count"] = sinkImpl.Value
+			ts.sinked["rate"] = sinkImpl.Value / (float64(duration) / float64(time.Second))
+			break
+		}
+		store(tokenCount, func() float64 { return sinkImpl.Value })
+		store(tokenRate, func() float64 { return sinkImpl.Value / (float64(duration) / float64(time.Second)) })
 	case *GaugeSink:
-		ts.sinked["value"] = sinkImpl.Value
+		if window == 0 {
+			ts.sinked["value"] = sinkImpl.Value
+			break
+		}
+		store(tokenValue, func() float64 { return sinkImpl.Value })
 	case *TrendSink:
-		ts.sinked["min"] = sinkImpl.Min()
-		ts.sinked["max"] = sinkImpl.Max()
-		ts.sinked["avg"] = sinkImpl.Avg()
-		ts.sinked["med"] = sinkImpl.P(0.5)
+		if window == 0 {
+			ts.sinked["min"] = sinkImpl.Min()
+			ts.sinked["max"] = sinkImpl.Max()
+			ts.sinked["avg"] = sinkImpl.Avg()
+			ts.sinked["med"] = sinkImpl.P(0.5)
+		} else {
+			store(tokenMin, sinkImpl.Min)
+			store(tokenMax, sinkImpl.Max)
+			store(tokenAvg, sinkImpl.Avg)
+			store(tokenMed, func() float64 { return sinkImpl.P(0.5) })
+		}
 
 		// Parse the percentile thresholds and insert them in
 		// the sinks mapping.
 		for _, threshold := range ts.Thresholds {
-			if threshold.parsed.AggregationMethod != tokenPercentile {
+			if threshold.parsed.AggregationMethod != tokenPercentile || threshold.parsed.Window != window {
 				continue
 			}
 
-			key := fmt.Sprintf("p(%g)", threshold.parsed.AggregationValue.Float64)
+			key := threshold.parsed.SinkKey()
 			ts.sinked[key] = sinkImpl.P(threshold.parsed.AggregationValue.Float64 / 100)
 		}
 	case *RateSink:
 		// We want to avoid division by zero, which
 		// would lead to [#2520](https://github.com/grafana/k6/issues/2520)
-		if sinkImpl.Total > 0 {
+		if sinkImpl.Total == 0 {
+			break
+		}
+		if window == 0 {
 			ts.sinked["rate"] = float64(sinkImpl.Trues) / float64(sinkImpl.Total)
+			break
 		}
+		store(tokenRate, func() float64 { return float64(sinkImpl.Trues) / float64(sinkImpl.Total) })
 	default:
-		return false, fmt.Errorf("unable to run Thresholds; reason: unknown sink type")
+		return fmt.Errorf("unable to run Thresholds; reason: unknown sink type")
 	}
 
-	return ts.runAll(duration)
+	return nil
+}
+
+// Windows returns the distinct, sorted durations of the sliding windows
+// used by the time-windowed thresholds, if there are any.
+func (ts *Thresholds) Windows() []time.Duration {
+	var windows []time.Duration
+	for _, threshold := range ts.Thresholds {
+		if threshold.parsed == nil || threshold.parsed.Window == 0 ||
+			slices.Contains(windows, threshold.parsed.Window) {
+			continue
+		}
+		windows = append(windows, threshold.parsed.Window)
+	}
+	slices.Sort(windows)
+	return windows
+}
+
+// WindowValues returns the values of the time-windowed thresholds' aggregations,
+// as they were computed during the last run, indexed by keys of the form
+// "p(95) over 1m". Windows without samples are not included.
+func (ts *Thresholds) WindowValues() map[string]float64 {
+	var values map[string]float64
+	for _, threshold := range ts.Thresholds {
+		if threshold.parsed == nil || threshold.parsed.Window == 0 {
+			continue
+		}
+		key := threshold.parsed.SinkKey()
+		value, ok := ts.sinked[key]
+		if !ok {
+			continue
+		}
+		if values == nil {
+			values = make(map[string]float64)
+		}
+		values[key] = value
+	}
+	return values
 }
 
 // Parse parses the Thresholds and fills each Threshold.parsed field with the result.
@@ -4,8 +4,11 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/lib/types"
 )
 
 // thresholdExpression holds the parsed result of a threshold expression,
@@ -21,6 +24,15 @@ type thresholdExpression struct {
 	// would result in AggregationValue to be set to 99.9.
 	AggregationValue null.Float
 
+	// Window holds the length of the sliding time window the aggregation
+	// method should be applied to, for expressions of the form p(95) over 1m < 200.
+	// It is zero when the aggregation applies to the whole test run.
+	Window time.Duration
+
+	// WindowSource holds the window duration verbatim, as it was written in
+	// the threshold expression, so that it can be used for display purposes.
+	WindowSource string
+
 	// Operator holds the operator parsed from the threshold expression.
 	// Possible values are described by `operatorTokens`.
 	Operator string
@@ -41,22 +53,32 @@ type thresholdExpression struct {
 // case specifically. If we encounter the percentile aggregation method token,
 // we recompute the whole "p(value)" expression in order to look for it in the
 // sinks.
+//
+// Time-windowed expressions get their own key, of the form "p(95) over 1m", so that
+// their values don't collide with the ones computed over the whole test run.
 func (te *thresholdExpression) SinkKey() string {
+	key := te.AggregationMethod
 	if te.AggregationMethod == tokenPercentile {
-		return fmt.Sprintf("%s(%g)", tokenPercentile, te.AggregationValue.Float64)
+		key = fmt.Sprintf("%s(%g)", tokenPercentile, te.AggregationValue.Float64)
+	}
+
+	if te.Window > 0 {
+		key += " " + tokenOver + " " + te.WindowSource
 	}
 
-	return te.AggregationMethod
+	return key
 }
 
 // parseThresholdExpression parses a threshold condition expression,
 // as defined in a JS script (for instance p(95)<1000), into a thresholdExpression
 // instance.
 //
-// It is expected to be of the form: `aggregation_method operator value`.
+// It is expected to be of the form: `aggregation_method [over duration] operator value`.
 // As defined by the following BNF:
 // ```
-// assertion           -> aggregation_method whitespace* operator whitespace* float
+// assertion           -> aggregation_method window? whitespace* operator whitespace* float
+// window              -> whitespace+ "over" whitespace+ duration
+// duration            -> a k6 duration, for instance "30s" or "1m30s"
 // aggregation_method  -> trend | rate | gauge | counter
 // counter             -> "count" | "rate"
 // gauge               -> "value"
@@ -76,6 +98,14 @@ func parseThresholdExpression(input string) (*thresholdExpression, error) {
 		return nil, fmt.Errorf("failed parsing threshold expression %q; reason: %w", input, err)
 	}
 
+	method, window, windowSource, err := parseThresholdWindow(method)
+	if err != nil {
+		err = fmt.Errorf("failed parsing threshold expression's %q window; "+
+			"reason: %w", input, err,
+		)
+		return nil, err
+	}
+
 	parsedMethod, parsedMethodValue, err := parseThresholdAggregationMethod(method)
 	if err != nil {
 		err = fmt.Errorf("failed parsing threshold expression's %q left hand side; "+
@@ -95,6 +125,8 @@ func parseThresholdExpression(input string) (*thresholdExpression, error) {
 	condition := &thresholdExpression{
 		AggregationMethod: parsedMethod,
 		AggregationValue:  parsedMethodValue,
+		Window:            window,
+		WindowSource:      windowSource,
 		Operator:          operator,
 		Value:             parsedValue,
 	}
@@ -151,6 +183,34 @@ func scanThresholdExpression(input string) (string, string, string, error) {
 	return "", "", "", fmt.Errorf("malformed threshold expression")
 }
 
+// tokenOver separates a threshold expression's aggregation method
+// from the duration of the sliding window it applies to.
+const tokenOver = "over"
+
+// parseThresholdWindow splits the left hand side of a threshold expression
+// of the form `aggregation_method over duration` in its aggregation method
+// and window parts. If the input has no window, it is returned as is, with
+// a zero window duration.
+func parseThresholdWindow(input string) (string, time.Duration, string, error) {
+	fields := strings.Fields(input)
+	if len(fields) < 2 || fields[1] != tokenOver {
+		return input, 0, "", nil
+	}
+	if len(fields) != 3 {
+		return "", 0, "", fmt.Errorf("expected a single duration after %q", tokenOver)
+	}
+
+	window, err := types.ParseExtendedDuration(fields[2])
+	if err != nil {
+		return "", 0, "", fmt.Errorf("malformed window duration; reason: %w", err)
+	}
+	if window <= 0 {
+		return "", 0, "", fmt.Errorf("the window duration should be positive, but was %s", fields[2])
+	}
+
+	return fields[0], window, fields[2], nil
+}
+
 // Define accepted threshold expression aggregation tokens
 // Percentile token `p(..)` is accepted too but handled separately.
 const (
@@ -2,6 +2,7 @@ package metrics
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/guregu/null.v3"
@@ -40,6 +41,43 @@ func TestParseThresholdExpression(t *testing.T) {
 			wantExpression: &thresholdExpression{AggregationMethod: "count", Operator: ">", Value: 20},
 			wantErr:        false,
 		},
+		{
+			name:  "valid time-windowed threshold expression syntax",
+			input: "p(95) over 1m < 300",
+			wantExpression: &thresholdExpression{
+				AggregationMethod: "p",
+				AggregationValue:  null.FloatFrom(95),
+				Window:            time.Minute,
+				WindowSource:      "1m",
+				Operator:          "<",
+				Value:             300,
+			},
+			wantErr: false,
+		},
+		{
+			name:           "time-windowed expression without a duration fails",
+			input:          "rate over<0.01",
+			wantExpression: nil,
+			wantErr:        true,
+		},
+		{
+			name:           "time-windowed expression with a malformed duration fails",
+			input:          "rate over 1x < 0.01",
+			wantExpression: nil,
+			wantErr:        true,
+		},
+		{
+			name:           "time-windowed expression with a zero duration fails",
+			input:          "rate over 0s < 0.01",
+			wantExpression: nil,
+			wantErr:        true,
+		},
+		{
+			name:           "time-windowed expression with trailing tokens fails",
+			input:          "rate over 30s 1m < 0.01",
+			wantExpression: nil,
+			wantErr:        true,
+		},
 	}
 	for _, testCase := range tests {
 		t.Run(testCase.name, func(t *testing.T) {
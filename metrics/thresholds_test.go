@@ -42,7 +42,7 @@ func TestThreshold_runNoTaint(t *testing.T) {
 	}{
 		{
 			name:             "valid expression using the > operator over passing threshold",
-			parsed:           &thresholdExpression{tokenRate, null.Float{}, tokenGreater, 0.01},
+			parsed:           &thresholdExpression{tokenRate, null.Float{}, 0, "", tokenGreater, 0.01},
 			abortGracePeriod: types.NullDurationFrom(0 * time.Second),
 			sinks:            map[string]float64{"rate": 1},
 			wantOk:           true,
@@ -50,7 +50,7 @@ func TestThreshold_runNoTaint(t *testing.T) {
 		},
 		{
 			name:             "valid expression using the > operator over passing threshold and defined abort grace period",
-			parsed:           &thresholdExpression{tokenRate, null.Float{}, tokenGreater, 0.01},
+			parsed:           &thresholdExpression{tokenRate, null.Float{}, 0, "", tokenGreater, 0.01},
 			abortGracePeriod: types.NullDurationFrom(2 * time.Second),
 			sinks:            map[string]float64{"rate": 1},
 			wantOk:           true,
@@ -58,7 +58,7 @@ func TestThreshold_runNoTaint(t *testing.T) {
 		},
 		{
 			name:             "valid expression using the >= operator over passing threshold",
-			parsed:           &thresholdExpression{tokenRate, null.Float{}, tokenGreaterEqual, 0.01},
+			parsed:           &thresholdExpression{tokenRate, null.Float{}, 0, "", tokenGreaterEqual, 0.01},
 			abortGracePeriod: types.NullDurationFrom(0 * time.Second),
 			sinks:            map[string]float64{"rate": 0.01},
 			wantOk:           true,
@@ -66,7 +66,7 @@ func TestThreshold_runNoTaint(t *testing.T) {
 		},
 		{
 			name:             "valid expression using the <= operator over passing threshold",
-			parsed:           &thresholdExpression{tokenRate, null.Float{}, tokenLessEqual, 0.01},
+			parsed:           &thresholdExpression{tokenRate, null.Float{}, 0, "", tokenLessEqual, 0.01},
 			abortGracePeriod: types.NullDurationFrom(0 * time.Second),
 			sinks:            map[string]float64{"rate": 0.01},
 			wantOk:           true,
@@ -74,7 +74,7 @@ func TestThreshold_runNoTaint(t *testing.T) {
 		},
 		{
 			name:             "valid expression using the < operator over passing threshold",
-			parsed:           &thresholdExpression{tokenRate, null.Float{}, tokenLess, 0.01},
+			parsed:           &thresholdExpression{tokenRate, null.Float{}, 0, "", tokenLess, 0.01},
 			abortGracePeriod: types.NullDurationFrom(0 * time.Second),
 			sinks:            map[string]float64{"rate": 0.00001},
 			wantOk:           true,
@@ -82,7 +82,7 @@ func TestThreshold_runNoTaint(t *testing.T) {
 		},
 		{
 			name:             "valid expression using the == operator over passing threshold",
-			parsed:           &thresholdExpression{tokenRate, null.Float{}, tokenLooselyEqual, 0.01},
+			parsed:           &thresholdExpression{tokenRate, null.Float{}, 0, "", tokenLooselyEqual, 0.01},
 			abortGracePeriod: types.NullDurationFrom(0 * time.Second),
 			sinks:            map[string]float64{"rate": 0.01},
 			wantOk:           true,
@@ -90,7 +90,7 @@ func TestThreshold_runNoTaint(t *testing.T) {
 		},
 		{
 			name:             "valid expression using the === operator over passing threshold",
-			parsed:           &thresholdExpression{tokenRate, null.Float{}, tokenStrictlyEqual, 0.01},
+			parsed:           &thresholdExpression{tokenRate, null.Float{}, 0, "", tokenStrictlyEqual, 0.01},
 			abortGracePeriod: types.NullDurationFrom(0 * time.Second),
 			sinks:            map[string]float64{"rate": 0.01},
 			wantOk:           true,
@@ -98,7 +98,7 @@ func TestThreshold_runNoTaint(t *testing.T) {
 		},
 		{
 			name:             "valid expression using != operator over passing threshold",
-			parsed:           &thresholdExpression{tokenRate, null.Float{}, tokenBangEqual, 0.01},
+			parsed:           &thresholdExpression{tokenRate, null.Float{}, 0, "", tokenBangEqual, 0.01},
 			abortGracePeriod: types.NullDurationFrom(0 * time.Second),
 			sinks:            map[string]float64{"rate": 0.02},
 			wantOk:           true,
@@ -106,7 +106,7 @@ func TestThreshold_runNoTaint(t *testing.T) {
 		},
 		{
 			name:             "valid expression over failing threshold",
-			parsed:           &thresholdExpression{tokenRate, null.Float{}, tokenGreater, 0.01},
+			parsed:           &thresholdExpression{tokenRate, null.Float{}, 0, "", tokenGreater, 0.01},
 			abortGracePeriod: types.NullDurationFrom(0 * time.Second),
 			sinks:            map[string]float64{"rate": 0.00001},
 			wantOk:           false,
@@ -114,7 +114,7 @@ func TestThreshold_runNoTaint(t *testing.T) {
 		},
 		{
 			name:             "valid expression over non-existing sink",
-			parsed:           &thresholdExpression{tokenRate, null.Float{}, tokenGreater, 0.01},
+			parsed:           &thresholdExpression{tokenRate, null.Float{}, 0, "", tokenGreater, 0.01},
 			abortGracePeriod: types.NullDurationFrom(0 * time.Second),
 			sinks:            map[string]float64{"med": 27.2},
 			wantOk:           true,
@@ -124,7 +124,7 @@ func TestThreshold_runNoTaint(t *testing.T) {
 			// The ParseThresholdCondition constructor should ensure that no invalid
 			// operator gets through, but let's protect our future selves anyhow.
 			name:             "invalid expression operator",
-			parsed:           &thresholdExpression{tokenRate, null.Float{}, "&", 0.01},
+			parsed:           &thresholdExpression{tokenRate, null.Float{}, 0, "", "&", 0.01},
 			abortGracePeriod: types.NullDurationFrom(0 * time.Second),
 			sinks:            map[string]float64{"rate": 0.00001},
 			wantOk:           false,
@@ -166,7 +166,7 @@ func BenchmarkRunNoTaint(b *testing.B) {
 		LastFailed:       false,
 		AbortOnFail:      false,
 		AbortGracePeriod: types.NullDurationFrom(2 * time.Second),
-		parsed:           &thresholdExpression{tokenRate, null.Float{}, tokenGreater, 0.01},
+		parsed:           &thresholdExpression{tokenRate, null.Float{}, 0, "", tokenGreater, 0.01},
 	}
 
 	sinks := map[string]float64{"rate": 1}
@@ -742,6 +742,97 @@ func TestThresholdsRun(t *testing.T) {
 	}
 }
 
+func TestThresholdsRunWithWindows(t *testing.T) {
+	t.Parallel()
+
+	thresholds := NewThresholds([]string{
+		"p(95)<100", "p(95) over 1m<100", "avg over 30s<100", "max over 30s>0",
+	})
+	require.NoError(t, thresholds.Parse())
+	assert.Equal(t, []time.Duration{30 * time.Second, time.Minute}, thresholds.Windows())
+
+	// The slow samples are only part of the whole test run sink, so only
+	// the threshold evaluated over the whole test run should fail.
+	ok, err := thresholds.RunWithWindows(getTrendSink(10, 20, 500, 900), map[time.Duration]Sink{
+		30 * time.Second: getTrendSink(10, 20),
+		time.Minute:      getTrendSink(10, 20),
+	}, 5*time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.True(t, thresholds.Thresholds[0].LastFailed)
+	assert.False(t, thresholds.Thresholds[1].LastFailed)
+	assert.False(t, thresholds.Thresholds[2].LastFailed)
+	assert.Equal(t, map[string]float64{
+		"p(95) over 1m": 19.5,
+		"avg over 30s":  15,
+		"max over 30s":  20,
+	}, thresholds.WindowValues())
+
+	// Windows without samples don't fail the thresholds.
+	ok, err = thresholds.RunWithWindows(getTrendSink(10, 20), map[time.Duration]Sink{
+		30 * time.Second: getTrendSink(),
+		time.Minute:      getTrendSink(),
+	}, 5*time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Empty(t, thresholds.WindowValues())
+}
+
+func TestThresholdsRunWithWindowsCounterRate(t *testing.T) {
+	t.Parallel()
+
+	thresholds := NewThresholds([]string{"rate over 30s<2", "count over 30s>0"})
+	require.NoError(t, thresholds.Parse())
+
+	// Only 10 seconds have elapsed, so the rate is calculated for them, instead of the whole window.
+	ok, err := thresholds.RunWithWindows(&CounterSink{Value: 30}, map[time.Duration]Sink{
+		30 * time.Second: &CounterSink{Value: 30},
+	}, 10*time.Second)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, map[string]float64{"rate over 30s": 3, "count over 30s": 30}, thresholds.WindowValues())
+
+	// An empty counter window still has a count.
+	ok, err = thresholds.RunWithWindows(&CounterSink{Value: 30}, map[time.Duration]Sink{
+		30 * time.Second: NewSink(Counter),
+	}, time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, map[string]float64{"rate over 30s": 0, "count over 30s": 0}, thresholds.WindowValues())
+	assert.True(t, thresholds.Thresholds[1].LastFailed)
+}
+
+func TestThresholdsRunWithWindowsStickyFailure(t *testing.T) {
+	t.Parallel()
+
+	thresholds := NewThresholds([]string{"avg over 30s<100", "avg<1000"})
+	require.NoError(t, thresholds.Parse())
+	thresholds.Thresholds[0].AbortOnFail = true
+	thresholds.Thresholds[0].AbortGracePeriod = types.NullDurationFrom(time.Minute)
+
+	// The window fails during the abort grace period, so the test isn't aborted.
+	ok, err := thresholds.RunWithWindows(getTrendSink(500), map[time.Duration]Sink{
+		30 * time.Second: getTrendSink(500),
+	}, 30*time.Second)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.False(t, thresholds.Abort)
+	assert.True(t, thresholds.Thresholds[0].LastFailed)
+
+	// The next windows pass, but the threshold stays failed for the end-of-test result,
+	// and neither the past failure nor an empty window can abort the test.
+	for _, windowSink := range []Sink{getTrendSink(10), getTrendSink()} {
+		ok, err = thresholds.RunWithWindows(getTrendSink(500, 10), map[time.Duration]Sink{
+			30 * time.Second: windowSink,
+		}, 2*time.Minute)
+		require.NoError(t, err)
+		assert.False(t, ok)
+		assert.False(t, thresholds.Abort)
+		assert.True(t, thresholds.Thresholds[0].LastFailed)
+		assert.False(t, thresholds.Thresholds[1].LastFailed)
+	}
+}
+
 func TestThresholdsJSON(t *testing.T) {
 	t.Parallel()
 
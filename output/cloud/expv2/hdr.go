@@ -8,6 +8,9 @@ import (
 )
 
 // histogramAsProto converts the histogram into the equivalent Protobuf version.
+//
+// The counters of the protocol are 32-bit, but the histograms only have the
+// values of a time series in an aggregation period, so they can't overflow them.
 func histogramAsProto(h *histogram.Hdr, time int64) *pbcloud.TrendHdrValue {
 	var (
 		indexes  []uint32
@@ -28,13 +31,13 @@ func histogramAsProto(h *histogram.Hdr, time int64) *pbcloud.TrendHdrValue {
 
 		// init the counters
 		counters = make([]uint32, 1, len(h.Buckets))
-		counters[0] = h.Buckets[indexes[0]]
+		counters[0] = uint32(h.Buckets[indexes[0]]) //nolint:gosec
 		// open the first span
 		spans = append(spans, &pbcloud.BucketSpan{Offset: indexes[0], Length: 1})
 	}
 
 	for i := 1; i < len(indexes); i++ {
-		counters = append(counters, h.Buckets[indexes[i]])
+		counters = append(counters, uint32(h.Buckets[indexes[i]])) //nolint:gosec
 
 		// if the current and the previous indexes are not consecutive
 		// consider as closed the current on-going span and start a new one.
@@ -51,16 +54,18 @@ func histogramAsProto(h *histogram.Hdr, time int64) *pbcloud.TrendHdrValue {
 		MinValue:      h.Min,
 		MaxValue:      h.Max,
 		Sum:           h.Sum,
-		Count:         h.Count,
+		Count:         uint32(h.Count), //nolint:gosec
 		Counters:      counters,
 		Spans:         spans,
 		MinResolution: h.MinimumResolution,
 	}
 	if h.ExtraLowBucket > 0 {
-		hval.ExtraLowValuesCounter = &h.ExtraLowBucket
+		extraLow := uint32(h.ExtraLowBucket) //nolint:gosec
+		hval.ExtraLowValuesCounter = &extraLow
 	}
 	if h.ExtraHighBucket > 0 {
-		hval.ExtraHighValuesCounter = &h.ExtraHighBucket
+		extraHigh := uint32(h.ExtraHighBucket) //nolint:gosec
+		hval.ExtraHighValuesCounter = &extraHigh
 	}
 	return hval
 }
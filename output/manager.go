@@ -1,6 +1,8 @@
 package output
 
 import (
+	"maps"
+	"slices"
 	"sync"
 	"time"
 
@@ -11,10 +13,32 @@ import (
 // TODO: completely get rid of this, see https://github.com/grafana/k6/issues/2430
 const sendBatchToOutputsRate = 50 * time.Millisecond
 
+// Redactor replaces the secrets in texts, e.g. the secretsource.Manager.
+type Redactor interface {
+	Redact(text string) string
+}
+
+// RedactableSampleContainer is a sample container that some outputs need to
+// receive with its own type, e.g. the HTTP trails, so that it's kept when the
+// secrets in its samples are redacted.
+type RedactableSampleContainer interface {
+	metrics.SampleContainer
+
+	// WithRedactedSamples returns a copy of the container with the given
+	// samples, and its own tags and metadata replaced by the given functions.
+	WithRedactedSamples(
+		samples []metrics.Sample,
+		redactTags func(*metrics.TagSet) *metrics.TagSet,
+		redactMetadata func(map[string]string) map[string]string,
+	) metrics.SampleContainer
+}
+
 // Manager can be used to manage multiple outputs at the same time.
 type Manager struct {
-	outputs []Output
-	logger  logrus.FieldLogger
+	outputs    []Output
+	logger     logrus.FieldLogger
+	redactor   Redactor
+	unredacted []Output
 
 	testStopCallback func(error)
 }
@@ -28,6 +52,16 @@ func NewManager(outputs []Output, logger logrus.FieldLogger, testStopCallback fu
 	}
 }
 
+// SetRedactor sets the redactor of the secrets in the tags and the metadata of
+// the metric samples, e.g. the URLs of the requests, before they are sent to
+// the outputs. The unredacted outputs receive the original samples, e.g. the
+// metrics engine ingester, whose thresholds and submetrics have to match the
+// real tag values. It has to be called before Start().
+func (om *Manager) SetRedactor(redactor Redactor, unredacted ...Output) {
+	om.redactor = redactor
+	om.unredacted = unredacted
+}
+
 // Start spins up all configured outputs and then starts a new goroutine that
 // pipes metrics from the given samples channel to them.
 //
@@ -48,8 +82,16 @@ func (om *Manager) Start(samplesChan chan metrics.SampleContainer) (wait func(),
 	wg.Add(1)
 
 	sendToOutputs := func(sampleContainers []metrics.SampleContainer) {
+		redacted := sampleContainers
+		if om.redactor != nil {
+			redacted = redactSamples(slices.Clone(sampleContainers), om.redactor)
+		}
 		for _, out := range om.outputs {
-			out.AddMetricSamples(sampleContainers)
+			if slices.Contains(om.unredacted, out) {
+				out.AddMetricSamples(sampleContainers)
+			} else {
+				out.AddMetricSamples(redacted)
+			}
 		}
 	}
 
@@ -117,3 +159,79 @@ func (om *Manager) stopOutputs(testErr error, upToID int) {
 		}
 	}
 }
+
+// redactSamples replaces the secrets in the tags and the metadata of the samples.
+// The containers with secrets are replaced with redacted copies of the same type
+// for the [RedactableSampleContainer] ones, which some outputs need, and with
+// plain [metrics.ConnectedSamples] or [metrics.Samples] for the others.
+// The containers without secrets are kept as they are.
+func redactSamples(containers []metrics.SampleContainer, redactor Redactor) []metrics.SampleContainer {
+	// the tag sets are shared by many samples, so only redact each once
+	redactedTags := make(map[*metrics.TagSet]*metrics.TagSet)
+	redactTags := func(tags *metrics.TagSet) *metrics.TagSet {
+		if tags == nil {
+			return nil
+		}
+		if redacted, ok := redactedTags[tags]; ok {
+			return redacted
+		}
+		redacted := tags
+		for k, v := range tags.Map() {
+			if r := redactor.Redact(v); r != v {
+				redacted = redacted.With(k, r)
+			}
+		}
+		redactedTags[tags] = redacted
+		return redacted
+	}
+	// the metadata is only copied when it has secrets, the original isn't changed
+	redactMetadata := func(metadata map[string]string) (map[string]string, bool) {
+		redacted, isRedacted := metadata, false
+		for k, v := range metadata {
+			if r := redactor.Redact(v); r != v {
+				if !isRedacted {
+					redacted, isRedacted = maps.Clone(metadata), true
+				}
+				redacted[k] = r
+			}
+		}
+		return redacted, isRedacted
+	}
+
+	for i, container := range containers {
+		samples := container.GetSamples()
+		var redacted []metrics.Sample
+		for j, sample := range samples {
+			tags := redactTags(sample.Tags)
+			metadata, metadataRedacted := redactMetadata(sample.Metadata)
+			if tags == sample.Tags && !metadataRedacted {
+				continue
+			}
+			if redacted == nil {
+				redacted = slices.Clone(samples)
+			}
+			redacted[j].TimeSeries = metrics.TimeSeries{Metric: sample.Metric, Tags: tags}
+			redacted[j].Metadata = metadata
+		}
+		if redacted == nil {
+			continue
+		}
+
+		switch c := container.(type) {
+		case RedactableSampleContainer:
+			containers[i] = c.WithRedactedSamples(redacted, redactTags, func(metadata map[string]string) map[string]string {
+				metadata, _ = redactMetadata(metadata)
+				return metadata
+			})
+		case metrics.ConnectedSampleContainer:
+			containers[i] = metrics.ConnectedSamples{
+				Samples: redacted,
+				Tags:    redactTags(c.GetTags()),
+				Time:    c.GetTime(),
+			}
+		default:
+			containers[i] = metrics.Samples(redacted)
+		}
+	}
+	return containers
+}
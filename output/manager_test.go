@@ -0,0 +1,190 @@
+package output
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/internal/lib/testutils"
+	"go.k6.io/k6/metrics"
+)
+
+// bufferOutput is an output which keeps the received samples.
+type bufferOutput struct {
+	*SampleBuffer
+}
+
+func (bufferOutput) Description() string { return "buffer" }
+func (bufferOutput) Start() error        { return nil }
+func (bufferOutput) Stop() error         { return nil }
+
+type replacerRedactor struct{ *strings.Replacer }
+
+func (r replacerRedactor) Redact(text string) string { return r.Replace(text) }
+
+func TestManagerRedactsSamples(t *testing.T) {
+	t.Parallel()
+
+	registry := metrics.NewRegistry()
+	metric := registry.MustNewMetric("my_metric", metrics.Counter)
+	now := time.Now()
+	sample := func(url, traceID string) metrics.Sample {
+		s := metrics.Sample{
+			TimeSeries: metrics.TimeSeries{
+				Metric: metric,
+				Tags:   registry.RootTagSet().WithTagsFromMap(map[string]string{"url": url, "method": "GET"}),
+			},
+			Time:  now,
+			Value: 1,
+		}
+		if traceID != "" {
+			s.Metadata = map[string]string{"trace_id": traceID}
+		}
+		return s
+	}
+
+	clean := sample("https://test.k6.io/", "")
+	secretURL := sample("https://test.k6.io/?token=s3cr3t", "")
+	secretMetadata := sample("https://test.k6.io/", "s3cr3t")
+	connected := metrics.ConnectedSamples{
+		Samples: []metrics.Sample{clean, secretURL},
+		Tags:    secretURL.Tags,
+		Time:    now,
+	}
+
+	out := &SampleBuffer{}
+	manager := NewManager([]Output{bufferOutput{out}}, testutils.NewLogger(t), nil)
+	manager.SetRedactor(replacerRedactor{strings.NewReplacer("s3cr3t", "***")})
+
+	samples := make(chan metrics.SampleContainer, 10)
+	wait, finish, err := manager.Start(samples)
+	require.NoError(t, err)
+	samples <- clean
+	samples <- secretURL
+	samples <- secretMetadata
+	samples <- connected
+	close(samples)
+	wait()
+	finish(nil)
+
+	redacted := out.GetBufferedSamples()
+	require.Len(t, redacted, 4)
+
+	assert.Equal(t, clean, redacted[0], "the samples without secrets aren't changed")
+
+	for _, container := range redacted[1:] {
+		for _, s := range container.GetSamples() {
+			assert.NotContains(t, s.Tags.Map()["url"], "s3cr3t")
+			assert.NotContains(t, s.Metadata["trace_id"], "s3cr3t")
+			assert.Equal(t, "GET", s.Tags.Map()["method"])
+		}
+	}
+	assert.Equal(t, "https://test.k6.io/?token=***", redacted[1].GetSamples()[0].Tags.Map()["url"])
+	assert.Equal(t, map[string]string{"trace_id": "***"}, redacted[2].GetSamples()[0].Metadata)
+	assert.Equal(t, map[string]string{"trace_id": "s3cr3t"}, secretMetadata.Metadata, "the original isn't changed")
+
+	redactedConnected, ok := redacted[3].(metrics.ConnectedSamples)
+	require.True(t, ok)
+	assert.Equal(t, now, redactedConnected.Time)
+	assert.Equal(t, "https://test.k6.io/?token=***", redactedConnected.Tags.Map()["url"])
+	assert.Equal(t, clean, redactedConnected.Samples[0])
+	assert.Same(t, redactedConnected.Tags, redactedConnected.Samples[1].Tags, "the tag sets are redacted once")
+}
+
+// trailContainer is a sample container that keeps its type when redacted.
+type trailContainer struct {
+	metrics.ConnectedSamples
+	duration time.Duration
+}
+
+func (c *trailContainer) WithRedactedSamples(
+	samples []metrics.Sample,
+	redactTags func(*metrics.TagSet) *metrics.TagSet,
+	_ func(map[string]string) map[string]string,
+) metrics.SampleContainer {
+	redacted := *c
+	redacted.Samples = samples
+	redacted.Tags = redactTags(c.Tags)
+	return &redacted
+}
+
+var _ RedactableSampleContainer = &trailContainer{}
+
+func TestManagerRedactsKeepingTheContainerType(t *testing.T) {
+	t.Parallel()
+
+	registry := metrics.NewRegistry()
+	metric := registry.MustNewMetric("http_reqs", metrics.Counter)
+	tags := registry.RootTagSet().WithTagsFromMap(map[string]string{"url": "https://test.k6.io/?token=s3cr3t"})
+	trail := &trailContainer{
+		ConnectedSamples: metrics.ConnectedSamples{
+			Samples: []metrics.Sample{{TimeSeries: metrics.TimeSeries{Metric: metric, Tags: tags}, Value: 1}},
+			Tags:    tags,
+			Time:    time.Now(),
+		},
+		duration: time.Second,
+	}
+
+	out := &SampleBuffer{}
+	manager := NewManager([]Output{bufferOutput{out}}, testutils.NewLogger(t), nil)
+	manager.SetRedactor(replacerRedactor{strings.NewReplacer("s3cr3t", "***")})
+
+	samples := make(chan metrics.SampleContainer, 1)
+	wait, finish, err := manager.Start(samples)
+	require.NoError(t, err)
+	samples <- trail
+	close(samples)
+	wait()
+	finish(nil)
+
+	redacted := out.GetBufferedSamples()
+	require.Len(t, redacted, 1)
+
+	redactedTrail, ok := redacted[0].(*trailContainer)
+	require.True(t, ok, "the container type is kept")
+	assert.NotSame(t, trail, redactedTrail)
+	assert.Equal(t, trail.duration, redactedTrail.duration)
+	assert.Equal(t, "https://test.k6.io/?token=***", redactedTrail.Tags.Map()["url"])
+	assert.Same(t, redactedTrail.Tags, redactedTrail.Samples[0].Tags)
+	assert.Equal(t, "https://test.k6.io/?token=s3cr3t", trail.Tags.Map()["url"], "the original isn't changed")
+}
+
+func TestManagerUnredactedOutputs(t *testing.T) {
+	t.Parallel()
+
+	registry := metrics.NewRegistry()
+	metric := registry.MustNewMetric("http_reqs", metrics.Counter)
+	secret := metrics.Sample{
+		TimeSeries: metrics.TimeSeries{
+			Metric: metric,
+			Tags:   registry.RootTagSet().With("url", "https://test.k6.io/?token=s3cr3t"),
+		},
+		Time:  time.Now(),
+		Value: 1,
+	}
+
+	redactedOut, unredactedOut := &SampleBuffer{}, &SampleBuffer{}
+	unredacted := bufferOutput{unredactedOut}
+	manager := NewManager([]Output{bufferOutput{redactedOut}, unredacted}, testutils.NewLogger(t), nil)
+	manager.SetRedactor(replacerRedactor{strings.NewReplacer("s3cr3t", "***")}, unredacted)
+
+	samples := make(chan metrics.SampleContainer, 1)
+	wait, finish, err := manager.Start(samples)
+	require.NoError(t, err)
+	samples <- secret
+	close(samples)
+	wait()
+	finish(nil)
+
+	redacted := redactedOut.GetBufferedSamples()
+	require.Len(t, redacted, 1)
+	assert.Equal(t, "https://test.k6.io/?token=***", redacted[0].GetSamples()[0].Tags.Map()["url"])
+
+	// e.g. the thresholds of the metrics engine have to match the real values
+	original := unredactedOut.GetBufferedSamples()
+	require.Len(t, original, 1)
+	assert.Equal(t, secret, original[0])
+}
@@ -1,4 +1,5 @@
 // Package secretsource is a package to provide secret source interface and common functionality
 // This functionality is to be used to provide k6 with a way to get secrets and help it handle them correctly.
-// Predominantly by redacting them from logs.
+// Predominantly by redacting them from logs, and from the other outputs of the test run like
+// the metric samples, the console output file and the end-of-test summary.
 package secretsource
@@ -24,6 +24,14 @@ type Source interface {
 	Get(key string) (value string, err error)
 }
 
+// UncachedSource can be implemented by the sources which cache the secrets on their
+// own, e.g. to read them again after some time. If Uncached returns true, the [Manager]
+// doesn't cache the secrets of the source, and calls its Get method every time.
+type UncachedSource interface {
+	Source
+	Uncached() bool
+}
+
 // Params contains all possible constructor parameters an output may need.
 type Params struct {
 	ConfigArgument string // the string on the cli
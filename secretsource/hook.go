@@ -1,7 +1,9 @@
 package secretsource
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/url"
 	"strings"
 	"sync"
 	"time"
@@ -9,6 +11,8 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+const redactedSecret = "***SECRET_REDACTED***"
+
 // secretsHook is a Logrus hook for hiding secrets from entries before they get logged
 type secretsHook struct {
 	secrets  []string
@@ -23,12 +27,50 @@ func (s *secretsHook) Levels() []logrus.Level { return logrus.AllLevels }
 // Adding the same secret multiple times will not error, but is not recommended.
 // It is users job to not keep adding the same secret over time but only once.
 func (s *secretsHook) add(secret string) {
+	if secret == "" { // an empty secret would be "found" between every character
+		return
+	}
 	s.mx.Lock()
 	defer s.mx.Unlock()
-	s.secrets = append(s.secrets, secret, "***SECRET_REDACTED***")
+	for _, variant := range secretVariants(secret) {
+		s.secrets = append(s.secrets, variant, redactedSecret)
+	}
 	s.replacer = strings.NewReplacer(s.secrets...)
 }
 
+// secretVariants returns the secret together with its encoded forms, which end up in
+// the URLs, e.g. of the --http-debug dumps and the url tags, and in the JSON strings.
+func secretVariants(secret string) []string {
+	variants := []string{secret}
+	jsonEncoded, _ := json.Marshal(secret)
+	for _, variant := range []string{
+		url.QueryEscape(secret),
+		url.PathEscape(secret),
+		strings.ReplaceAll(url.QueryEscape(secret), "+", "%20"), // JS encodeURIComponent
+		string(jsonEncoded[1 : len(jsonEncoded)-1]), // without the quotes
+	} {
+		duplicate := false
+		for _, v := range variants {
+			duplicate = duplicate || v == variant
+		}
+		if !duplicate {
+			variants = append(variants, variant)
+		}
+	}
+	return variants
+}
+
+// redact replaces all the secrets added so far in the text.
+func (s *secretsHook) redact(text string) string {
+	s.mx.RLock()
+	replacer := s.replacer
+	s.mx.RUnlock()
+	if replacer == nil {
+		return text
+	}
+	return replacer.Replace(text)
+}
+
 // Fire is part of the [logrus.Hook]
 func (s *secretsHook) Fire(entry *logrus.Entry) error {
 	s.mx.Lock()
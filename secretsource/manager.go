@@ -61,20 +61,41 @@ func (sm *Manager) Get(sourceName, key string) (string, error) {
 	if !ok {
 		return "", UnknownSourceError(sourceName)
 	}
-	v, ok := sourceCache.Load(key)
-	if ok {
+	source := sm.sources[sourceName]
+	uncached, isUncachedSource := source.(UncachedSource)
+	cached := !isUncachedSource || !uncached.Uncached()
+	if v, ok := sourceCache.Load(key); ok && cached {
 		return v.(string), nil //nolint:forcetypeassert
 	}
-	source := sm.sources[sourceName]
 	value, err := source.Get(key)
 	if err != nil {
 		return "", err
 	}
-	sourceCache.Store(key, value)
-	sm.hook.add(value)
+	// The values of the uncached sources are still kept, so that they are added
+	// to the redacted ones only once, unless they change, e.g. when rotated.
+	if previous, loaded := sourceCache.Swap(key, value); !loaded || previous != value {
+		sm.hook.add(value)
+	}
 	return value, err
 }
 
+// Redact replaces all the secrets retrieved so far, and their URL and JSON encoded
+// forms, in the text. It's the same replacement which is applied to the log entries,
+// for the other outputs of the test run which can contain secrets, like the metric
+// samples and the end-of-test summary.
+func (sm *Manager) Redact(text string) string {
+	if sm == nil {
+		return text
+	}
+	return sm.hook.redact(text)
+}
+
+// Hook returns the logrus hook which redacts the secrets from the log entries, for
+// the loggers other than the main one, e.g. the one of the --console-output file.
+func (sm *Manager) Hook() logrus.Hook {
+	return sm.hook
+}
+
 // UnknownSourceError is returned when a unknown source is requested
 type UnknownSourceError string
 
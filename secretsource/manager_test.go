@@ -0,0 +1,89 @@
+package secretsource
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mapSource map[string]string
+
+func (mapSource) Description() string { return "map" }
+
+func (m mapSource) Get(key string) (string, error) {
+	v, ok := m[key]
+	if !ok {
+		return "", errors.New("no value")
+	}
+	return v, nil
+}
+
+// uncachedSource is a mapSource which counts its gets, and opts out of the caching of the Manager.
+type uncachedSource struct {
+	mapSource
+	gets int
+}
+
+func (u *uncachedSource) Get(key string) (string, error) {
+	u.gets++
+	return u.mapSource.Get(key)
+}
+
+func (u *uncachedSource) Uncached() bool { return true }
+
+func TestManagerUncachedSource(t *testing.T) {
+	t.Parallel()
+
+	source := &uncachedSource{mapSource: mapSource{"token": "first"}}
+	manager, _, err := NewManager(map[string]Source{"default": source})
+	require.NoError(t, err)
+
+	for range 2 {
+		v, err := manager.Get(DefaultSourceName, "token")
+		require.NoError(t, err)
+		assert.Equal(t, "first", v)
+	}
+	assert.Equal(t, 2, source.gets)
+	assert.Len(t, manager.hook.secrets, 2*len(secretVariants("first")), "the same value is added once")
+
+	source.mapSource["token"] = "second"
+	v, err := manager.Get(DefaultSourceName, "token")
+	require.NoError(t, err)
+	assert.Equal(t, "second", v)
+	assert.Equal(t, "***SECRET_REDACTED*** ***SECRET_REDACTED***", manager.Redact("first second"))
+}
+
+func TestManagerRedact(t *testing.T) {
+	t.Parallel()
+
+	manager, hook, err := NewManager(map[string]Source{"default": mapSource{
+		"token": `a b+c/"d"`,
+		"empty": "",
+	}})
+	require.NoError(t, err)
+
+	text := `raw a b+c/"d", query a+b%2Bc%2F%22d%22, path a%20b+c%2F%22d%22, ` +
+		`uri a%20b%2Bc%2F%22d%22, json "a b+c/\"d\""`
+	assert.Equal(t, text, manager.Redact(text), "no secrets were retrieved yet")
+
+	_, err = manager.Get(DefaultSourceName, "token")
+	require.NoError(t, err)
+	_, err = manager.Get(DefaultSourceName, "empty")
+	require.NoError(t, err)
+
+	redacted := `raw ***SECRET_REDACTED***, query ***SECRET_REDACTED***, path ***SECRET_REDACTED***, ` +
+		`uri ***SECRET_REDACTED***, json "***SECRET_REDACTED***"`
+	assert.Equal(t, redacted, manager.Redact(text))
+
+	entry := &logrus.Entry{Message: text, Data: logrus.Fields{"url": "/?t=a+b%2Bc%2F%22d%22"}}
+	require.NoError(t, hook.Fire(entry))
+	assert.Equal(t, redacted, entry.Message)
+	assert.Equal(t, "/?t=***SECRET_REDACTED***", entry.Data["url"])
+	assert.Same(t, hook, manager.Hook())
+
+	var nilManager *Manager
+	assert.Equal(t, text, nilManager.Redact(text))
+}